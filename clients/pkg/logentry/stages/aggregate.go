@@ -0,0 +1,292 @@
+package stages
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/go-logfmt/logfmt"
+	"github.com/mitchellh/mapstructure"
+	"github.com/pkg/errors"
+
+	"github.com/grafana/loki/clients/pkg/promtail/api"
+
+	"github.com/grafana/loki/pkg/logproto"
+)
+
+const (
+	ErrAggregateStageInvalidWindow    = "aggregate stage `window` parse error: %v"
+	ErrAggregateStageInvalidMaxGroups = "aggregate stage `max_groups` must be greater than 0"
+	ErrAggregateStageInvalidTemplate  = "aggregate stage template parse error: %v"
+)
+
+const (
+	aggregateWindowDefault    = time.Minute
+	aggregateMaxGroupsDefault = 1000
+)
+
+// AggregateConfig contains the configuration for an aggregateStage
+type AggregateConfig struct {
+	Keys      []string `mapstructure:"keys"`
+	Window    *string  `mapstructure:"window"`
+	window    time.Duration
+	MaxGroups *int    `mapstructure:"max_groups"`
+	Template  *string `mapstructure:"template"`
+	template  *template.Template
+}
+
+func validateAggregateConfig(cfg *AggregateConfig) error {
+	cfg.window = aggregateWindowDefault
+	if cfg.Window != nil {
+		window, err := time.ParseDuration(*cfg.Window)
+		if err != nil {
+			return errors.Errorf(ErrAggregateStageInvalidWindow, err)
+		}
+		if window <= 0 {
+			return errors.Errorf(ErrAggregateStageInvalidWindow, "window must be greater than 0")
+		}
+		cfg.window = window
+	}
+
+	if cfg.MaxGroups == nil {
+		cfg.MaxGroups = new(int)
+		*cfg.MaxGroups = aggregateMaxGroupsDefault
+	} else if *cfg.MaxGroups <= 0 {
+		return errors.New(ErrAggregateStageInvalidMaxGroups)
+	}
+
+	if cfg.Template != nil {
+		t, err := template.New("aggregate_template").Funcs(functionMap).Parse(*cfg.Template)
+		if err != nil {
+			return errors.Errorf(ErrAggregateStageInvalidTemplate, err)
+		}
+		cfg.template = t
+	}
+	return nil
+}
+
+// aggregateStage groups the entries of a stream by the values of keys over a window, and replaces each group with a
+// summary entry.
+type aggregateStage struct {
+	logger log.Logger
+	cfg    *AggregateConfig
+}
+
+// aggregateGroup is a group of entries summarized into a single entry.
+type aggregateGroup struct {
+	sample    Entry    // The first entry of the group.
+	keys      []string // The values of the keys of the group.
+	count     int
+	firstSeen time.Time
+	lastSeen  time.Time
+}
+
+// aggregateState captures the internal state of a running aggregate stage.
+type aggregateState struct {
+	groups map[string]*aggregateGroup
+	order  []*aggregateGroup // The groups in the order they were created.
+}
+
+// AggregateSummary is the data available to the template of the summary entries.
+type AggregateSummary struct {
+	Count     int
+	FirstSeen time.Time
+	LastSeen  time.Time
+	Keys      map[string]string
+	Sample    string
+}
+
+// newAggregateStage creates an aggregateStage from config
+func newAggregateStage(logger log.Logger, config interface{}) (Stage, error) {
+	cfg := &AggregateConfig{}
+	err := mapstructure.WeakDecode(config, cfg)
+	if err != nil {
+		return nil, err
+	}
+	err = validateAggregateConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &aggregateStage{
+		logger: log.With(logger, "component", "stage", "type", "aggregate"),
+		cfg:    cfg,
+	}, nil
+}
+
+// Run implements Stage. The groups are flushed at the end of each window, when the maximum number of groups is
+// reached, and when the inbound channel is closed.
+func (a *aggregateStage) Run(in chan Entry) chan Entry {
+	out := make(chan Entry)
+	go func() {
+		defer close(out)
+
+		state := &aggregateState{groups: map[string]*aggregateGroup{}}
+		ticker := time.NewTicker(a.cfg.window)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if Debug {
+					level.Debug(a.logger).Log("msg", "flush groups at the end of the window", "groups", len(state.order))
+				}
+				a.flush(out, state)
+			case e, ok := <-in:
+				if !ok {
+					if Debug {
+						level.Debug(a.logger).Log("msg", "flush groups because inbound closed", "groups", len(state.order))
+					}
+					a.flush(out, state)
+					return
+				}
+				a.add(out, state, e)
+			}
+		}
+	}()
+	return out
+}
+
+func (a *aggregateStage) add(out chan Entry, s *aggregateState, e Entry) {
+	keys := a.keyValues(e)
+	id := groupID(e, keys)
+
+	g, ok := s.groups[id]
+	if !ok {
+		if len(s.order) >= *a.cfg.MaxGroups {
+			if Debug {
+				level.Debug(a.logger).Log("msg", "flush groups because the maximum number of groups is reached", "groups", len(s.order))
+			}
+			a.flush(out, s)
+		}
+		g = &aggregateGroup{
+			sample:    e,
+			keys:      keys,
+			firstSeen: e.Timestamp,
+			lastSeen:  e.Timestamp,
+		}
+		s.groups[id] = g
+		s.order = append(s.order, g)
+	}
+
+	g.count++
+	if e.Timestamp.Before(g.firstSeen) {
+		g.firstSeen = e.Timestamp
+	}
+	if e.Timestamp.After(g.lastSeen) {
+		g.lastSeen = e.Timestamp
+	}
+}
+
+// keyValues returns the values of the keys of the entry, missing values being empty.
+func (a *aggregateStage) keyValues(e Entry) []string {
+	if len(a.cfg.Keys) == 0 {
+		return nil
+	}
+	values := make([]string, len(a.cfg.Keys))
+	for i, k := range a.cfg.Keys {
+		v, ok := e.Extracted[k]
+		if !ok {
+			continue
+		}
+		s, err := getString(v)
+		if err != nil {
+			if Debug {
+				level.Debug(a.logger).Log("msg", "failed to convert key value to string", "key", k, "err", err, "type", reflect.TypeOf(v))
+			}
+			continue
+		}
+		values[i] = s
+	}
+	return values
+}
+
+// groupID identifies the group of an entry by its stream and its key values, or its line if no keys are configured.
+func groupID(e Entry, keys []string) string {
+	var sb strings.Builder
+	sb.WriteString(e.Labels.String())
+	sb.WriteByte(0xff)
+	if keys == nil {
+		sb.WriteString(e.Line)
+		return sb.String()
+	}
+	for _, k := range keys {
+		sb.WriteString(k)
+		sb.WriteByte(0xff)
+	}
+	return sb.String()
+}
+
+func (a *aggregateStage) flush(out chan Entry, s *aggregateState) {
+	for _, g := range s.order {
+		line, err := a.summary(g)
+		if err != nil {
+			if Debug {
+				level.Debug(a.logger).Log("msg", "failed to format summary, sending the sample entry", "err", err)
+			}
+			line = g.sample.Line
+		}
+
+		// copy extracted data.
+		extracted := make(map[string]interface{}, len(g.sample.Extracted))
+		for k, v := range g.sample.Extracted {
+			extracted[k] = v
+		}
+		out <- Entry{
+			Extracted: extracted,
+			Entry: api.Entry{
+				Labels: g.sample.Labels.Clone(),
+				Entry: logproto.Entry{
+					Timestamp: g.firstSeen,
+					Line:      line,
+				},
+			},
+		}
+	}
+	s.groups = map[string]*aggregateGroup{}
+	s.order = nil
+}
+
+// summary formats the summary line of a group, with the template if configured or as logfmt otherwise.
+func (a *aggregateStage) summary(g *aggregateGroup) (string, error) {
+	if a.cfg.template != nil {
+		keys := make(map[string]string, len(a.cfg.Keys))
+		for i, k := range a.cfg.Keys {
+			keys[k] = g.keys[i]
+		}
+		var buf bytes.Buffer
+		err := a.cfg.template.Execute(&buf, AggregateSummary{
+			Count:     g.count,
+			FirstSeen: g.firstSeen,
+			LastSeen:  g.lastSeen,
+			Keys:      keys,
+			Sample:    g.sample.Line,
+		})
+		return buf.String(), err
+	}
+
+	var buf bytes.Buffer
+	enc := logfmt.NewEncoder(&buf)
+	keyvals := []interface{}{
+		"count", g.count,
+		"first_seen", g.firstSeen.UTC().Format(time.RFC3339Nano),
+		"last_seen", g.lastSeen.UTC().Format(time.RFC3339Nano),
+	}
+	for i, k := range a.cfg.Keys {
+		keyvals = append(keyvals, k, g.keys[i])
+	}
+	keyvals = append(keyvals, "sample", g.sample.Line)
+	if err := enc.EncodeKeyvals(keyvals...); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// Name implements Stage
+func (a *aggregateStage) Name() string {
+	return StageTypeAggregate
+}
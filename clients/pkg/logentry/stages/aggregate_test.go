@@ -0,0 +1,134 @@
+package stages
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	util_log "github.com/grafana/loki/pkg/util/log"
+)
+
+var testAggregateYaml = `
+pipeline_stages:
+- logfmt:
+    mapping:
+      msg:
+- aggregate:
+    keys: [msg]
+`
+
+func Test_AggregatePipeline(t *testing.T) {
+	pl, err := NewPipeline(util_log.Logger, loadConfig(testAggregateYaml), nil, prometheus.DefaultRegisterer)
+	require.NoError(t, err)
+
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	out := processEntries(pl,
+		newEntry(nil, model.LabelSet{"app": "api"}, `level=debug msg="cache miss" key=a`, ts),
+		newEntry(nil, model.LabelSet{"app": "api"}, `level=debug msg="cache hit" key=b`, ts.Add(time.Second)),
+		newEntry(nil, model.LabelSet{"app": "api"}, `level=debug msg="cache miss" key=c`, ts.Add(2*time.Second)),
+		newEntry(nil, model.LabelSet{"app": "db"}, `level=debug msg="cache miss" key=d`, ts.Add(3*time.Second)),
+	)
+
+	require.Len(t, out, 3)
+	require.Equal(t, `count=2 first_seen=2024-01-02T03:04:05Z last_seen=2024-01-02T03:04:07Z msg="cache miss" sample="level=debug msg=\"cache miss\" key=a"`, out[0].Line)
+	require.Equal(t, model.LabelSet{"app": "api"}, out[0].Labels)
+	require.Equal(t, ts, out[0].Timestamp)
+	require.Equal(t, `count=1 first_seen=2024-01-02T03:04:06Z last_seen=2024-01-02T03:04:06Z msg="cache hit" sample="level=debug msg=\"cache hit\" key=b"`, out[1].Line)
+	require.Equal(t, model.LabelSet{"app": "db"}, out[2].Labels)
+	require.Contains(t, out[2].Line, "count=1 ")
+}
+
+func Test_aggregateStage_Template(t *testing.T) {
+	cfg := &AggregateConfig{Keys: []string{"msg"}, Template: ptrFromString(`{{ .Count }} occurrences of {{ .Keys.msg }}, last seen {{ .LastSeen.Unix }}: {{ .Sample }}`)}
+	require.NoError(t, validateAggregateConfig(cfg))
+	stage := &aggregateStage{cfg: cfg, logger: util_log.Logger}
+
+	ts := time.Unix(1700000000, 0)
+	out := processEntries(stage,
+		newEntry(map[string]interface{}{"msg": "timeout"}, nil, "request 1 timeout", ts),
+		newEntry(map[string]interface{}{"msg": "timeout"}, nil, "request 2 timeout", ts.Add(time.Minute)),
+	)
+	require.Len(t, out, 1)
+	require.Equal(t, "2 occurrences of timeout, last seen 1700000060: request 1 timeout", out[0].Line)
+}
+
+func Test_aggregateStage_NoKeys(t *testing.T) {
+	cfg := &AggregateConfig{}
+	require.NoError(t, validateAggregateConfig(cfg))
+	stage := &aggregateStage{cfg: cfg, logger: util_log.Logger}
+
+	out := processEntries(stage,
+		simpleEntry("connection reset", "a"),
+		simpleEntry("connection refused", "a"),
+		simpleEntry("connection reset", "a"),
+	)
+	require.Len(t, out, 2)
+	require.Contains(t, out[0].Line, `count=2 `)
+	require.Contains(t, out[0].Line, `sample="connection reset"`)
+	require.Contains(t, out[1].Line, `count=1 `)
+}
+
+func Test_aggregateStage_Flush(t *testing.T) {
+	cfg := &AggregateConfig{Window: ptrFromString("50ms"), MaxGroups: new(int)}
+	*cfg.MaxGroups = 2
+	require.NoError(t, validateAggregateConfig(cfg))
+	stage := &aggregateStage{cfg: cfg, logger: util_log.Logger}
+
+	in := make(chan Entry)
+	out := stage.Run(in)
+
+	// Reaching the maximum number of groups flushes them.
+	go func() {
+		in <- simpleEntry("line 1", "a")
+		in <- simpleEntry("line 2", "a")
+		in <- simpleEntry("line 3", "a")
+	}()
+	first, second := <-out, <-out
+	require.Contains(t, first.Line, `sample="line 1"`)
+	require.Contains(t, second.Line, `sample="line 2"`)
+
+	// The end of the window flushes the groups.
+	select {
+	case e := <-out:
+		require.Contains(t, e.Line, `sample="line 3"`)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the window to be flushed")
+	}
+
+	// Closing the inbound channel flushes the groups.
+	go func() {
+		in <- simpleEntry("line 4", "a")
+		close(in)
+	}()
+	e := <-out
+	require.Contains(t, e.Line, `sample="line 4"`)
+	_, ok := <-out
+	require.False(t, ok)
+}
+
+func Test_validateAggregateConfig(t *testing.T) {
+	for name, tc := range map[string]struct {
+		config *AggregateConfig
+		err    string
+	}{
+		"defaults":       {&AggregateConfig{}, ""},
+		"invalid window": {&AggregateConfig{Window: ptrFromString("1 minute")}, "aggregate stage `window` parse error"},
+		"zero window":    {&AggregateConfig{Window: ptrFromString("0s")}, "aggregate stage `window` parse error"},
+		"zero groups":    {&AggregateConfig{MaxGroups: new(int)}, ErrAggregateStageInvalidMaxGroups},
+		"bad template":   {&AggregateConfig{Template: ptrFromString("{{ .Count")}, "aggregate stage template parse error"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := validateAggregateConfig(tc.config)
+			if tc.err != "" {
+				require.ErrorContains(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, aggregateWindowDefault, tc.config.window)
+			require.Equal(t, aggregateMaxGroupsDefault, *tc.config.MaxGroups)
+		})
+	}
+}
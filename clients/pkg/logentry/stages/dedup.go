@@ -0,0 +1,235 @@
+package stages
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cespare/xxhash/v2"
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/hashicorp/golang-lru/simplelru"
+	"github.com/mitchellh/mapstructure"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/loki/pkg/util"
+)
+
+const (
+	ErrDedupStageInvalidWindow     = "dedup stage `window` parse error: %v"
+	ErrDedupStageInvalidTolerance  = "dedup stage `timestamp_tolerance` parse error: %v"
+	ErrDedupStageInvalidMaxEntries = "dedup stage `max_entries` must be greater than 0"
+)
+
+const (
+	dedupWindowDefault     = time.Minute
+	dedupMaxEntriesDefault = 10000
+)
+
+var (
+	defaultDedupReason = "dedup_stage"
+	dedupSeparator     = []byte{0xff}
+)
+
+// DedupConfig contains the configuration for a dedupStage
+type DedupConfig struct {
+	Labels             *[]string `mapstructure:"labels"`
+	TimestampTolerance *string   `mapstructure:"timestamp_tolerance"`
+	tolerance          time.Duration
+	Window             *string `mapstructure:"window"`
+	window             time.Duration
+	MaxEntries         *int    `mapstructure:"max_entries"`
+	DropReason         *string `mapstructure:"drop_counter_reason"`
+}
+
+func validateDedupConfig(cfg *DedupConfig) error {
+	cfg.window = dedupWindowDefault
+	if cfg.Window != nil {
+		window, err := time.ParseDuration(*cfg.Window)
+		if err != nil {
+			return errors.Errorf(ErrDedupStageInvalidWindow, err)
+		}
+		if window <= 0 {
+			return errors.Errorf(ErrDedupStageInvalidWindow, "window must be greater than 0")
+		}
+		cfg.window = window
+	}
+
+	if cfg.TimestampTolerance != nil {
+		tolerance, err := time.ParseDuration(*cfg.TimestampTolerance)
+		if err != nil {
+			return errors.Errorf(ErrDedupStageInvalidTolerance, err)
+		}
+		if tolerance < 0 {
+			return errors.Errorf(ErrDedupStageInvalidTolerance, "timestamp_tolerance must not be negative")
+		}
+		cfg.tolerance = tolerance
+	}
+
+	if cfg.MaxEntries == nil {
+		cfg.MaxEntries = new(int)
+		*cfg.MaxEntries = dedupMaxEntriesDefault
+	} else if *cfg.MaxEntries <= 0 {
+		return errors.New(ErrDedupStageInvalidMaxEntries)
+	}
+
+	if cfg.DropReason == nil || *cfg.DropReason == "" {
+		cfg.DropReason = &defaultDedupReason
+	}
+	return nil
+}
+
+// dedupStage drops the entries already seen within a window: entries with the same line and labels, whose
+// timestamps are within the timestamp tolerance of each other.
+//
+// The entries seen are shared by all the runs of the stage, so that duplicates are dropped across the targets of
+// a scrape config. They are tracked in a LRU cache to bound the memory used.
+type dedupStage struct {
+	logger    log.Logger
+	cfg       *DedupConfig
+	dropCount *prometheus.CounterVec
+	evicted   *prometheus.CounterVec
+	now       func() time.Time
+
+	mtx  sync.Mutex
+	seen *simplelru.LRU
+}
+
+// dedupKey identifies an entry by the hash of its labels and line, and by its timestamp bucket.
+type dedupKey struct {
+	hash   uint64
+	bucket int64
+}
+
+// dedupRecord is an entry seen by the stage.
+type dedupRecord struct {
+	timestamp time.Time // The timestamp of the entry.
+	seenAt    time.Time // The time the entry was seen by the stage.
+}
+
+// newDedupStage creates a dedupStage from config
+func newDedupStage(logger log.Logger, config interface{}, registerer prometheus.Registerer) (Stage, error) {
+	cfg := &DedupConfig{}
+	err := mapstructure.WeakDecode(config, cfg)
+	if err != nil {
+		return nil, err
+	}
+	err = validateDedupConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &dedupStage{
+		logger:    log.With(logger, "component", "stage", "type", "dedup"),
+		cfg:       cfg,
+		dropCount: getDropCountMetric(registerer),
+		evicted: util.RegisterCounterVec(registerer, "logentry", "dedup_evicted_entries_total",
+			"A count of the entries evicted by the dedup stage before the end of their window, because max_entries was reached", nil),
+		now: time.Now,
+	}
+	d.seen, err = simplelru.NewLRU(*cfg.MaxEntries, d.onEvict)
+	if err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// Run implements Stage
+func (d *dedupStage) Run(in chan Entry) chan Entry {
+	out := make(chan Entry)
+	go func() {
+		defer close(out)
+		for e := range in {
+			if d.isDuplicate(e) {
+				if Debug {
+					level.Debug(d.logger).Log("msg", "dropping duplicate entry", "labels", e.Labels, "timestamp", e.Timestamp)
+				}
+				d.dropCount.WithLabelValues(*d.cfg.DropReason).Inc()
+				continue
+			}
+			out <- e
+		}
+	}()
+	return out
+}
+
+// isDuplicate returns whether the entry was already seen, recording it otherwise.
+func (d *dedupStage) isDuplicate(e Entry) bool {
+	hash := d.hash(e)
+	bucket := e.Timestamp.UnixNano()
+	if d.cfg.tolerance > 0 {
+		bucket /= int64(d.cfg.tolerance)
+	}
+
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	now := d.now()
+
+	// The entries within the tolerance are either in the same bucket or in the adjacent ones.
+	candidates := []int64{bucket}
+	if d.cfg.tolerance > 0 {
+		candidates = append(candidates, bucket-1, bucket+1)
+	}
+	for _, b := range candidates {
+		v, ok := d.seen.Peek(dedupKey{hash: hash, bucket: b})
+		if !ok {
+			continue
+		}
+		r := v.(dedupRecord)
+		if now.Sub(r.seenAt) > d.cfg.window {
+			continue
+		}
+		diff := e.Timestamp.Sub(r.timestamp)
+		if diff < 0 {
+			diff = -diff
+		}
+		if diff <= d.cfg.tolerance {
+			return true
+		}
+	}
+
+	d.seen.Add(dedupKey{hash: hash, bucket: bucket}, dedupRecord{timestamp: e.Timestamp, seenAt: now})
+	return false
+}
+
+// hash hashes the line and the configured labels of the entry, or all of its labels if none are configured.
+func (d *dedupStage) hash(e Entry) uint64 {
+	var names []string
+	if d.cfg.Labels != nil {
+		names = *d.cfg.Labels
+	} else {
+		names = make([]string, 0, len(e.Labels))
+		for name := range e.Labels {
+			names = append(names, string(name))
+		}
+		sort.Strings(names)
+	}
+
+	h := xxhash.New()
+	for _, name := range names {
+		value, ok := e.Labels[model.LabelName(name)]
+		if !ok {
+			continue
+		}
+		_, _ = h.WriteString(name)
+		_, _ = h.Write(dedupSeparator)
+		_, _ = h.WriteString(string(value))
+		_, _ = h.Write(dedupSeparator)
+	}
+	_, _ = h.WriteString(e.Line)
+	return h.Sum64()
+}
+
+// onEvict counts the entries evicted from the cache while they were still in their window.
+func (d *dedupStage) onEvict(_ interface{}, value interface{}) {
+	if d.now().Sub(value.(dedupRecord).seenAt) <= d.cfg.window {
+		d.evicted.WithLabelValues().Inc()
+	}
+}
+
+// Name implements Stage
+func (d *dedupStage) Name() string {
+	return StageTypeDedup
+}
@@ -0,0 +1,135 @@
+package stages
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	util_log "github.com/grafana/loki/pkg/util/log"
+)
+
+var testDedupYaml = `
+pipeline_stages:
+- dedup:
+    labels: [app]
+    timestamp_tolerance: 1s
+`
+
+func Test_DedupPipeline(t *testing.T) {
+	pl, err := NewPipeline(util_log.Logger, loadConfig(testDedupYaml), nil, prometheus.DefaultRegisterer)
+	require.NoError(t, err)
+
+	ts := time.Now()
+	out := processEntries(pl,
+		newEntry(nil, model.LabelSet{"app": "api", "relay": "a"}, "connection reset", ts),
+		newEntry(nil, model.LabelSet{"app": "api", "relay": "b"}, "connection reset", ts.Add(300*time.Millisecond)),
+		newEntry(nil, model.LabelSet{"app": "db", "relay": "b"}, "connection reset", ts.Add(300*time.Millisecond)),
+		newEntry(nil, model.LabelSet{"app": "api", "relay": "a"}, "connection reset", ts.Add(5*time.Second)),
+		newEntry(nil, model.LabelSet{"app": "api", "relay": "b"}, "connection reset", ts.Add(4500*time.Millisecond)),
+	)
+	require.Len(t, out, 3)
+	require.Equal(t, model.LabelSet{"app": "api", "relay": "a"}, out[0].Labels)
+	require.Equal(t, model.LabelSet{"app": "db", "relay": "b"}, out[1].Labels)
+	require.Equal(t, ts.Add(5*time.Second), out[2].Timestamp)
+}
+
+func Test_dedupStage_Labels(t *testing.T) {
+	ts := time.Now()
+	entries := func() []Entry {
+		return []Entry{
+			newEntry(nil, model.LabelSet{"app": "api", "host": "a"}, "line", ts),
+			newEntry(nil, model.LabelSet{"app": "api", "host": "b"}, "line", ts),
+			newEntry(nil, model.LabelSet{"app": "api", "host": "a"}, "line", ts),
+			newEntry(nil, model.LabelSet{"app": "api", "host": "a"}, "line", ts.Add(time.Nanosecond)),
+		}
+	}
+
+	// All the labels are hashed by default.
+	st, err := newDedupStage(util_log.Logger, map[string]interface{}{}, prometheus.NewRegistry())
+	require.NoError(t, err)
+	require.Len(t, processEntries(st, entries()...), 3)
+
+	// Only the line is hashed without labels.
+	st, err = newDedupStage(util_log.Logger, map[string]interface{}{"labels": []interface{}{}}, prometheus.NewRegistry())
+	require.NoError(t, err)
+	require.Len(t, processEntries(st, entries()...), 2)
+}
+
+func Test_dedupStage_Window(t *testing.T) {
+	st, err := newDedupStage(util_log.Logger, map[string]interface{}{"window": "10s"}, prometheus.NewRegistry())
+	require.NoError(t, err)
+	d := st.(*dedupStage)
+	now := time.Now()
+	d.now = func() time.Time { return now }
+
+	ts := time.Unix(1700000000, 0)
+	e := simpleEntry("line", "a")
+	e.Timestamp = ts
+	require.False(t, d.isDuplicate(e))
+	now = now.Add(5 * time.Second)
+	require.True(t, d.isDuplicate(e))
+	// The window starts when the entry is first seen.
+	now = now.Add(6 * time.Second)
+	require.False(t, d.isDuplicate(e))
+	require.True(t, d.isDuplicate(e))
+}
+
+func Test_dedupStage_MaxEntries(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	st, err := newDedupStage(util_log.Logger, map[string]interface{}{"max_entries": 2, "drop_counter_reason": "duplicate"}, reg)
+	require.NoError(t, err)
+	d := st.(*dedupStage)
+
+	ts := time.Now()
+	out := processEntries(st,
+		newEntry(nil, nil, "line 1", ts),
+		newEntry(nil, nil, "line 2", ts),
+		newEntry(nil, nil, "line 2", ts),
+		newEntry(nil, nil, "line 3", ts),
+		// line 1 was evicted to track line 3.
+		newEntry(nil, nil, "line 1", ts),
+	)
+	require.Len(t, out, 4)
+	require.Equal(t, 2.0, testutil.ToFloat64(d.evicted))
+	require.Equal(t, 1.0, testutil.ToFloat64(d.dropCount.WithLabelValues("duplicate")))
+}
+
+func Test_dedupStage_SharedAcrossRuns(t *testing.T) {
+	st, err := newDedupStage(util_log.Logger, map[string]interface{}{}, prometheus.NewRegistry())
+	require.NoError(t, err)
+
+	ts := time.Now()
+	require.Len(t, processEntries(st, newEntry(nil, model.LabelSet{"app": "api"}, "line", ts)), 1)
+	require.Len(t, processEntries(st, newEntry(nil, model.LabelSet{"app": "api"}, "line", ts)), 0)
+}
+
+func Test_validateDedupConfig(t *testing.T) {
+	for name, tc := range map[string]struct {
+		config *DedupConfig
+		err    string
+	}{
+		"defaults":           {&DedupConfig{}, ""},
+		"invalid window":     {&DedupConfig{Window: ptrFromString("1 minute")}, "dedup stage `window` parse error"},
+		"zero window":        {&DedupConfig{Window: ptrFromString("0s")}, "dedup stage `window` parse error"},
+		"invalid tolerance":  {&DedupConfig{TimestampTolerance: ptrFromString("1")}, "dedup stage `timestamp_tolerance` parse error"},
+		"negative tolerance": {&DedupConfig{TimestampTolerance: ptrFromString("-1s")}, "dedup stage `timestamp_tolerance` parse error"},
+		"zero entries":       {&DedupConfig{MaxEntries: new(int)}, ErrDedupStageInvalidMaxEntries},
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := validateDedupConfig(tc.config)
+			if tc.err != "" {
+				require.ErrorContains(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, dedupWindowDefault, tc.config.window)
+			require.Equal(t, time.Duration(0), tc.config.tolerance)
+			require.Equal(t, dedupMaxEntriesDefault, *tc.config.MaxEntries)
+			require.Equal(t, defaultDedupReason, *tc.config.DropReason)
+		})
+	}
+}
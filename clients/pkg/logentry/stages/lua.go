@@ -0,0 +1,427 @@
+package stages
+
+import (
+	"context"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/mitchellh/mapstructure"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+	lua "github.com/yuin/gopher-lua"
+	"github.com/yuin/gopher-lua/parse"
+
+	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/push"
+)
+
+// Config Errors
+const (
+	ErrEmptyLuaStageConfig = "empty lua stage configuration"
+	ErrLuaScriptRequired   = "lua stage requires exactly one of `script` or `script_file`"
+	ErrLuaCouldNotReadFile = "lua stage could not read script file"
+	ErrLuaCouldNotCompile  = "lua stage could not compile script"
+	ErrLuaInvalidTimeout   = "lua stage `timeout` parse error: %v"
+)
+
+// Global variables holding the entry in the scripts
+const (
+	luaGlobalLine           = "line"
+	luaGlobalTimestamp      = "timestamp"
+	luaGlobalLabels         = "labels"
+	luaGlobalExtracted      = "extracted"
+	luaGlobalStructMetadata = "structured_metadata"
+)
+
+var defaultLuaDropReason = "lua_stage"
+
+const defaultLuaTimeout = 100 * time.Millisecond
+
+// Reasons of the errors counted by the lua stage errors metric
+const (
+	luaErrorReasonTimeout = "timeout"
+	luaErrorReasonScript  = "script"
+)
+
+// luaRemovedGlobals are the functions of the base library removed from the scripts environment, as they give access
+// to the file system or to other scripts.
+var luaRemovedGlobals = []string{"dofile", "loadfile", "load", "loadstring", "require", "module", "getfenv", "setfenv", "collectgarbage", "_printregs", "newproxy"}
+
+// LuaConfig contains the configuration for a luaStage
+type LuaConfig struct {
+	Script     string  `mapstructure:"script"`
+	ScriptFile string  `mapstructure:"script_file"`
+	Timeout    *string `mapstructure:"timeout"`
+	timeout    time.Duration
+	DropReason *string `mapstructure:"drop_counter_reason"`
+}
+
+// validateLuaConfig validates the config and returns the compiled script
+func validateLuaConfig(c *LuaConfig) (*lua.FunctionProto, error) {
+	if c == nil {
+		return nil, errors.New(ErrEmptyLuaStageConfig)
+	}
+	if (c.Script == "") == (c.ScriptFile == "") {
+		return nil, errors.New(ErrLuaScriptRequired)
+	}
+	if c.DropReason == nil || *c.DropReason == "" {
+		c.DropReason = &defaultLuaDropReason
+	}
+	c.timeout = defaultLuaTimeout
+	if c.Timeout != nil {
+		timeout, err := time.ParseDuration(*c.Timeout)
+		if err != nil {
+			return nil, errors.Errorf(ErrLuaInvalidTimeout, err)
+		}
+		if timeout <= 0 {
+			return nil, errors.Errorf(ErrLuaInvalidTimeout, "timeout must be greater than 0")
+		}
+		c.timeout = timeout
+	}
+
+	script, name := c.Script, "script"
+	if c.ScriptFile != "" {
+		b, err := os.ReadFile(c.ScriptFile)
+		if err != nil {
+			return nil, errors.Wrap(err, ErrLuaCouldNotReadFile)
+		}
+		script, name = string(b), c.ScriptFile
+	}
+	chunk, err := parse.Parse(strings.NewReader(script), name)
+	if err != nil {
+		return nil, errors.Wrap(err, ErrLuaCouldNotCompile)
+	}
+	proto, err := lua.Compile(chunk, name)
+	if err != nil {
+		return nil, errors.Wrap(err, ErrLuaCouldNotCompile)
+	}
+	return proto, nil
+}
+
+// luaStage runs a Lua script on each entry, which can modify the line, timestamp, labels, extracted values and
+// structured metadata of the entry, or drop it by returning false.
+//
+// Lua is used rather than an expression language such as CEL or expr: conditions which set several labels, remove
+// extracted values and drop entries are statements, which expression languages without side effects can't express,
+// and gopher-lua is a pure Go implementation which is already a dependency.
+type luaStage struct {
+	cfg         *LuaConfig
+	proto       *lua.FunctionProto
+	logger      log.Logger
+	dropCount   *prometheus.CounterVec
+	errorsCount *prometheus.CounterVec
+}
+
+// newLuaStage creates a new luaStage
+func newLuaStage(logger log.Logger, config interface{}, registerer prometheus.Registerer) (Stage, error) {
+	cfg := &LuaConfig{}
+	if err := mapstructure.Decode(config, cfg); err != nil {
+		return nil, err
+	}
+	proto, err := validateLuaConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &luaStage{
+		cfg:         cfg,
+		proto:       proto,
+		logger:      log.With(logger, "component", "stage", "type", "lua"),
+		dropCount:   getDropCountMetric(registerer),
+		errorsCount: getLuaErrorsMetric(registerer),
+	}, nil
+}
+
+func getLuaErrorsMetric(registerer prometheus.Registerer) *prometheus.CounterVec {
+	errorsCount := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "logentry",
+		Name:      "lua_stage_errors_total",
+		Help:      "A count of all the entries left unchanged by a lua stage because its script failed or timed out",
+	}, []string{"reason"})
+	err := registerer.Register(errorsCount)
+	if err != nil {
+		if existing, ok := err.(prometheus.AlreadyRegisteredError); ok {
+			errorsCount = existing.ExistingCollector.(*prometheus.CounterVec)
+		} else {
+			// Same behavior as MustRegister if the error is not for AlreadyRegistered
+			panic(err)
+		}
+	}
+	return errorsCount
+}
+
+// Run implements Stage. A Lua state can't be used concurrently, so each call gets its own.
+func (l *luaStage) Run(in chan Entry) chan Entry {
+	out := make(chan Entry)
+	go func() {
+		defer close(out)
+		L := newLuaState()
+		defer L.Close()
+		fn := L.NewFunctionFromProto(l.proto)
+		// the globals of the state are only read through the environment of each entry.
+		envMeta := L.CreateTable(0, 1)
+		envMeta.RawSetString("__index", L.Get(lua.GlobalsIndex))
+		for e := range in {
+			if !l.process(L, fn, envMeta, &e) {
+				l.dropCount.WithLabelValues(*l.cfg.DropReason).Inc()
+				continue
+			}
+			out <- e
+		}
+	}()
+	return out
+}
+
+// newLuaState creates a Lua state with the base, table, string and math libraries only.
+func newLuaState() *lua.LState {
+	L := lua.NewState(lua.Options{SkipOpenLibs: true})
+	for _, lib := range []struct {
+		name string
+		fn   lua.LGFunction
+	}{
+		{lua.BaseLibName, lua.OpenBase},
+		{lua.TabLibName, lua.OpenTable},
+		{lua.StringLibName, lua.OpenString},
+		{lua.MathLibName, lua.OpenMath},
+	} {
+		L.Push(L.NewFunction(lib.fn))
+		L.Push(lua.LString(lib.name))
+		L.Call(1, 0)
+	}
+	for _, name := range luaRemovedGlobals {
+		L.SetGlobal(name, lua.LNil)
+	}
+	return L
+}
+
+// process runs the script on the entry and returns whether the entry is kept. The entry is left unchanged if the
+// script fails or doesn't complete within the timeout.
+// The script runs in a new environment, inheriting the globals of the state through envMeta, so that the globals it
+// sets don't leak to the next entries.
+func (l *luaStage) process(L *lua.LState, fn *lua.LFunction, envMeta *lua.LTable, e *Entry) bool {
+	ts := lua.LNumber(float64(e.Timestamp.UnixNano()) / float64(time.Second))
+	labels := L.CreateTable(0, len(e.Labels))
+	for k, v := range e.Labels {
+		labels.RawSetString(string(k), lua.LString(v))
+	}
+	extracted := L.CreateTable(0, len(e.Extracted))
+	for k, v := range e.Extracted {
+		extracted.RawSetString(k, toLuaValue(L, v))
+	}
+	metadata := L.CreateTable(0, len(e.StructuredMetadata))
+	for _, m := range e.StructuredMetadata {
+		metadata.RawSetString(m.Name, lua.LString(m.Value))
+	}
+	env := L.CreateTable(0, 5)
+	L.SetMetatable(env, envMeta)
+	env.RawSetString(luaGlobalLine, lua.LString(e.Line))
+	env.RawSetString(luaGlobalTimestamp, ts)
+	env.RawSetString(luaGlobalLabels, labels)
+	env.RawSetString(luaGlobalExtracted, extracted)
+	env.RawSetString(luaGlobalStructMetadata, metadata)
+	fn.Env = env
+
+	ctx, cancel := context.WithTimeout(context.Background(), l.cfg.timeout)
+	defer cancel()
+	L.SetContext(ctx)
+	defer L.RemoveContext()
+
+	L.Push(fn)
+	if err := L.PCall(0, 1, nil); err != nil {
+		reason := luaErrorReasonScript
+		if ctx.Err() != nil {
+			reason = luaErrorReasonTimeout
+		}
+		l.errorsCount.WithLabelValues(reason).Inc()
+		if Debug {
+			level.Debug(l.logger).Log("msg", "failed to run lua script", "reason", reason, "err", err)
+		}
+		return true
+	}
+	ret := L.Get(-1)
+	L.Pop(1)
+	if ret == lua.LFalse {
+		return false
+	}
+
+	e.Line = lua.LVAsString(env.RawGetString(luaGlobalLine))
+	if v, ok := env.RawGetString(luaGlobalTimestamp).(lua.LNumber); ok && v != ts {
+		sec, frac := math.Modf(float64(v))
+		e.Timestamp = time.Unix(int64(sec), int64(frac*float64(time.Second)))
+	}
+	if t, ok := env.RawGetString(luaGlobalLabels).(*lua.LTable); ok {
+		e.Labels = l.labelsFromTable(t)
+	}
+	if t, ok := env.RawGetString(luaGlobalExtracted).(*lua.LTable); ok {
+		e.Extracted = extractedFromTable(t, e.Extracted)
+	}
+	if t, ok := env.RawGetString(luaGlobalStructMetadata).(*lua.LTable); ok {
+		e.StructuredMetadata = l.structuredMetadataFromTable(t, e.StructuredMetadata)
+	}
+	return true
+}
+
+// labelsFromTable returns the labels set in t, skipping the invalid ones.
+func (l *luaStage) labelsFromTable(t *lua.LTable) model.LabelSet {
+	labels := make(model.LabelSet, t.Len())
+	t.ForEach(func(k, v lua.LValue) {
+		name, value := model.LabelName(lua.LVAsString(k)), model.LabelValue(lua.LVAsString(v))
+		if !name.IsValid() || !value.IsValid() {
+			if Debug {
+				level.Debug(l.logger).Log("msg", "invalid label set by lua script", "label", name, "value", value)
+			}
+			return
+		}
+		labels[name] = value
+	})
+	return labels
+}
+
+// structuredMetadataFromTable returns the structured metadata set in t, the existing names keeping their order and
+// the new ones being sorted.
+func (l *luaStage) structuredMetadataFromTable(t *lua.LTable, previous push.LabelsAdapter) push.LabelsAdapter {
+	values := map[string]string{}
+	t.ForEach(func(k, v lua.LValue) {
+		name := lua.LVAsString(k)
+		if !model.LabelName(name).IsValid() {
+			if Debug {
+				level.Debug(l.logger).Log("msg", "invalid structured metadata name set by lua script", "name", name)
+			}
+			return
+		}
+		values[name] = lua.LVAsString(v)
+	})
+	if len(values) == 0 {
+		return nil
+	}
+
+	result := make(push.LabelsAdapter, 0, len(values))
+	for _, m := range previous {
+		if v, ok := values[m.Name]; ok {
+			result = append(result, logproto.LabelAdapter{Name: m.Name, Value: v})
+			delete(values, m.Name)
+		}
+	}
+	added := make([]string, 0, len(values))
+	for name := range values {
+		added = append(added, name)
+	}
+	sort.Strings(added)
+	for _, name := range added {
+		result = append(result, logproto.LabelAdapter{Name: name, Value: values[name]})
+	}
+	return result
+}
+
+// extractedFromTable returns the extracted values set in t. The previous values left unchanged by the script are kept
+// as is, to not change their type.
+func extractedFromTable(t *lua.LTable, previous map[string]interface{}) map[string]interface{} {
+	extracted := make(map[string]interface{}, len(previous))
+	t.ForEach(func(k, v lua.LValue) {
+		key := lua.LVAsString(k)
+		if p, ok := previous[key]; ok {
+			if _, isTable := v.(*lua.LTable); !isTable && toLuaValue(nil, p) == v {
+				extracted[key] = p
+				return
+			}
+		}
+		if value := fromLuaValue(v); value != nil {
+			extracted[key] = value
+		}
+	})
+	return extracted
+}
+
+// toLuaValue converts an extracted value to a Lua value. L can be nil for values which are not maps or slices.
+func toLuaValue(L *lua.LState, v interface{}) lua.LValue {
+	switch v := v.(type) {
+	case nil:
+		return lua.LNil
+	case string:
+		return lua.LString(v)
+	case bool:
+		return lua.LBool(v)
+	case float64:
+		return lua.LNumber(v)
+	case float32:
+		return lua.LNumber(v)
+	case int:
+		return lua.LNumber(v)
+	case int32:
+		return lua.LNumber(v)
+	case int64:
+		return lua.LNumber(v)
+	case uint:
+		return lua.LNumber(v)
+	case uint32:
+		return lua.LNumber(v)
+	case uint64:
+		return lua.LNumber(v)
+	case map[string]interface{}:
+		if L == nil {
+			return lua.LNil
+		}
+		t := L.CreateTable(0, len(v))
+		for k, vv := range v {
+			t.RawSetString(k, toLuaValue(L, vv))
+		}
+		return t
+	case []interface{}:
+		if L == nil {
+			return lua.LNil
+		}
+		t := L.CreateTable(len(v), 0)
+		for _, vv := range v {
+			t.Append(toLuaValue(L, vv))
+		}
+		return t
+	default:
+		s, err := getString(v)
+		if err != nil {
+			return lua.LNil
+		}
+		return lua.LString(s)
+	}
+}
+
+// fromLuaValue converts a Lua value to an extracted value. Tables with consecutive integer keys only are converted
+// to slices, the other ones to maps.
+func fromLuaValue(v lua.LValue) interface{} {
+	switch v := v.(type) {
+	case lua.LString:
+		return string(v)
+	case lua.LNumber:
+		return float64(v)
+	case lua.LBool:
+		return bool(v)
+	case *lua.LTable:
+		var size int
+		v.ForEach(func(lua.LValue, lua.LValue) { size++ })
+		if n := v.MaxN(); n > 0 && n == size {
+			s := make([]interface{}, 0, n)
+			for i := 1; i <= n; i++ {
+				s = append(s, fromLuaValue(v.RawGetInt(i)))
+			}
+			return s
+		}
+		m := make(map[string]interface{}, size)
+		v.ForEach(func(k, vv lua.LValue) {
+			if value := fromLuaValue(vv); value != nil {
+				m[lua.LVAsString(k)] = value
+			}
+		})
+		return m
+	default:
+		return nil
+	}
+}
+
+// Name implements Stage
+func (l *luaStage) Name() string {
+	return StageTypeLua
+}
@@ -0,0 +1,181 @@
+package stages
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/pkg/push"
+	util_log "github.com/grafana/loki/pkg/util/log"
+)
+
+var testLuaYaml = `
+pipeline_stages:
+- json:
+    expressions:
+      status:
+      path:
+      user:
+- lua:
+    script: |
+      if extracted.status >= 500 and string.sub(extracted.path, 1, 4) == "/api" then
+        labels.level = "error"
+        extracted.user = nil
+      end
+      if extracted.path == "/healthz" then
+        return false
+      end
+- labels:
+    user:
+`
+
+func TestLuaPipeline(t *testing.T) {
+	pl, err := NewPipeline(util_log.Logger, loadConfig(testLuaYaml), nil, prometheus.DefaultRegisterer)
+	require.NoError(t, err)
+
+	out := processEntries(pl,
+		newEntry(nil, model.LabelSet{"job": "api"}, `{"status": 503, "path": "/api/v1/push", "user": "bob"}`, time.Now()),
+		newEntry(nil, model.LabelSet{"job": "api"}, `{"status": 200, "path": "/api/v1/push", "user": "alice"}`, time.Now()),
+		newEntry(nil, model.LabelSet{"job": "api"}, `{"status": 200, "path": "/healthz", "user": "alice"}`, time.Now()),
+	)
+	require.Len(t, out, 2)
+	require.Equal(t, model.LabelSet{"job": "api", "level": "error"}, out[0].Labels)
+	require.Equal(t, model.LabelSet{"job": "api", "user": "alice"}, out[1].Labels)
+	// Values left unchanged keep their type.
+	require.Equal(t, float64(200), out[1].Extracted["status"])
+}
+
+func TestLuaStage_Entry(t *testing.T) {
+	st, err := newLuaStage(util_log.Logger, map[string]interface{}{
+		"script": `
+line = string.upper(line) .. " " .. labels.app .. " " .. extracted.nested.a .. " " .. #extracted.list
+timestamp = timestamp + 1.5
+labels.app = nil
+labels.env = "prod"
+labels["invalid-name"] = "x"
+extracted.count = extracted.count + 1
+extracted.list[3] = "c"
+extracted.nested.b = true
+structured_metadata.trace_id = nil
+structured_metadata.user = "bob"
+structured_metadata.pod = "loki-0"
+`,
+	}, prometheus.DefaultRegisterer)
+	require.NoError(t, err)
+
+	ts := time.Unix(1700000000, 0)
+	e := newEntry(map[string]interface{}{
+		"count":  1,
+		"list":   []interface{}{"a", "b"},
+		"nested": map[string]interface{}{"a": "x"},
+		"keep":   int64(7),
+	}, model.LabelSet{"app": "loki"}, "hello", ts)
+	e.StructuredMetadata = push.LabelsAdapter{{Name: "user", Value: "alice"}, {Name: "trace_id", Value: "123"}}
+
+	out := processEntries(st, e)[0]
+	require.Equal(t, "HELLO loki x 2", out.Line)
+	require.Equal(t, ts.Add(1500*time.Millisecond), out.Timestamp)
+	require.Equal(t, model.LabelSet{"env": "prod"}, out.Labels)
+	require.Equal(t, map[string]interface{}{
+		"count":  float64(2),
+		"list":   []interface{}{"a", "b", "c"},
+		"nested": map[string]interface{}{"a": "x", "b": true},
+		"keep":   int64(7),
+	}, out.Extracted)
+	require.Equal(t, push.LabelsAdapter{{Name: "user", Value: "bob"}, {Name: "pod", Value: "loki-0"}}, out.StructuredMetadata)
+}
+
+func TestLuaStage_RuntimeError(t *testing.T) {
+	st, err := newLuaStage(util_log.Logger, map[string]interface{}{
+		"script": `line = "changed"; labels.app = "other"; error("boom")`,
+	}, prometheus.DefaultRegisterer)
+	require.NoError(t, err)
+
+	ts := time.Now()
+	out := processEntries(st, newEntry(nil, model.LabelSet{"app": "loki"}, "hello", ts))
+	require.Len(t, out, 1)
+	require.Equal(t, "hello", out[0].Line)
+	require.Equal(t, model.LabelSet{"app": "loki"}, out[0].Labels)
+	require.Equal(t, ts, out[0].Timestamp)
+}
+
+func TestLuaStage_Timeout(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	st, err := newLuaStage(util_log.Logger, map[string]interface{}{
+		"script":  `if line == "loop" then while true do end end; line = line .. "!"; if line == "fail!" then error("boom") end`,
+		"timeout": "50ms",
+	}, registry)
+	require.NoError(t, err)
+
+	out := processEntries(st,
+		newEntry(nil, nil, "loop", time.Now()),
+		newEntry(nil, nil, "fail", time.Now()),
+		newEntry(nil, nil, "hello", time.Now()),
+	)
+	require.Len(t, out, 3)
+	require.Equal(t, "loop", out[0].Line)
+	require.Equal(t, "fail", out[1].Line)
+	// the state is still usable once a script timed out.
+	require.Equal(t, "hello!", out[2].Line)
+
+	errorsCount := st.(*luaStage).errorsCount
+	require.Equal(t, 1.0, testutil.ToFloat64(errorsCount.WithLabelValues(luaErrorReasonTimeout)))
+	require.Equal(t, 1.0, testutil.ToFloat64(errorsCount.WithLabelValues(luaErrorReasonScript)))
+}
+
+func TestLuaStage_GlobalsAreReset(t *testing.T) {
+	st, err := newLuaStage(util_log.Logger, map[string]interface{}{
+		"script": `count = (count or 0) + 1; line = line .. " " .. count`,
+	}, prometheus.DefaultRegisterer)
+	require.NoError(t, err)
+
+	out := processEntries(st, newEntry(nil, nil, "a", time.Now()), newEntry(nil, nil, "b", time.Now()))
+	require.Equal(t, "a 1", out[0].Line)
+	require.Equal(t, "b 1", out[1].Line)
+}
+
+func TestLuaStage_Sandbox(t *testing.T) {
+	st, err := newLuaStage(util_log.Logger, map[string]interface{}{
+		"script": `line = tostring(os) .. " " .. tostring(io) .. " " .. tostring(dofile) .. " " .. tostring(require)`,
+	}, prometheus.DefaultRegisterer)
+	require.NoError(t, err)
+
+	out := processEntries(st, newEntry(nil, nil, "hello", time.Now()))
+	require.Equal(t, "nil nil nil nil", out[0].Line)
+}
+
+func TestValidateLuaConfig(t *testing.T) {
+	scriptFile := filepath.Join(t.TempDir(), "script.lua")
+	require.NoError(t, os.WriteFile(scriptFile, []byte(`return extracted.level ~= "debug"`), 0o644))
+
+	for name, tc := range map[string]struct {
+		config *LuaConfig
+		err    string
+	}{
+		"empty":             {nil, ErrEmptyLuaStageConfig},
+		"no script":         {&LuaConfig{}, ErrLuaScriptRequired},
+		"script and file":   {&LuaConfig{Script: "return true", ScriptFile: scriptFile}, ErrLuaScriptRequired},
+		"syntax error":      {&LuaConfig{Script: "if line then"}, ErrLuaCouldNotCompile},
+		"missing file":      {&LuaConfig{ScriptFile: filepath.Join(t.TempDir(), "missing.lua")}, ErrLuaCouldNotReadFile},
+		"invalid timeout":   {&LuaConfig{Script: "return true", Timeout: ptrFromString("soon")}, "lua stage `timeout` parse error"},
+		"negative timeout":  {&LuaConfig{Script: "return true", Timeout: ptrFromString("-1s")}, "lua stage `timeout` parse error"},
+		"valid script":      {&LuaConfig{Script: "return true"}, ""},
+		"valid script file": {&LuaConfig{ScriptFile: scriptFile}, ""},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := validateLuaConfig(tc.config)
+			if tc.err != "" {
+				require.ErrorContains(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, defaultLuaDropReason, *tc.config.DropReason)
+		})
+	}
+}
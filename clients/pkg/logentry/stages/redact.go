@@ -0,0 +1,371 @@
+package stages
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"net/netip"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/mitchellh/mapstructure"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/loki/pkg/util"
+)
+
+// Config Errors
+const (
+	ErrEmptyRedactStageConfig  = "empty redact stage configuration"
+	ErrRedactUnknownDetector   = "unknown redact stage detector: %s"
+	ErrRedactInvalidMode       = "redact stage mode must be one of token, mask or hash"
+	ErrRedactInvalidSource     = "redact stage source should be a string or a list of strings"
+	ErrRedactNegativeKeep      = "redact stage keep must be positive"
+	ErrRedactInvalidMaskLength = "redact stage mask_char must be a single character"
+	ErrRedactHashKeyRequired   = "redact stage hash_key is required in the hash mode"
+)
+
+// Redaction modes
+const (
+	RedactModeToken = "token"
+	RedactModeMask  = "mask"
+	RedactModeHash  = "hash"
+)
+
+// Built-in detectors
+const (
+	RedactDetectorEmail      = "email"
+	RedactDetectorCreditCard = "credit_card"
+	RedactDetectorIBAN       = "iban"
+	RedactDetectorIPv4       = "ipv4"
+	RedactDetectorIPv6       = "ipv6"
+	RedactDetectorJWT        = "jwt"
+	RedactDetectorAPIKey     = "api_key"
+)
+
+const (
+	defaultRedactKeep     = 4
+	defaultRedactMaskChar = "*"
+	redactHashLength      = 32
+)
+
+// detector finds sensitive values in a string with a regular expression. If the expression has a group named value,
+// only this group is redacted. Matches are redacted only if valid is nil or returns true.
+type detector struct {
+	name  string
+	expr  *regexp.Regexp
+	valid func(string) bool
+}
+
+// detectors lists the built-in detectors, in the order they are applied. JWTs and API keys are detected first as
+// they might contain values matched by other detectors.
+var detectors = []*detector{
+	{
+		name: RedactDetectorJWT,
+		expr: regexp.MustCompile(`\beyJ[A-Za-z0-9_-]+\.eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`),
+	},
+	{
+		name: RedactDetectorAPIKey,
+		expr: regexp.MustCompile(strings.Join([]string{
+			`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`,            // AWS access key IDs
+			`\bgh[pousr]_[A-Za-z0-9]{36,}\b`,           // GitHub tokens
+			`\bgithub_pat_[A-Za-z0-9_]{22,}\b`,         // GitHub fine-grained tokens
+			`\bglc_[A-Za-z0-9+/=_-]{32,}`,              // Grafana Cloud tokens
+			`\bglsa_[A-Za-z0-9_]{32,}\b`,               // Grafana service account tokens
+			`\bxox[abposr]-[A-Za-z0-9-]{10,}`,          // Slack tokens
+			`\b[rs]k_(?:live|test)_[A-Za-z0-9]{16,}\b`, // Stripe keys
+			`\bAIza[0-9A-Za-z_-]{35}\b`,                // Google API keys
+			// Values assigned to API key fields, e.g. api_key=value or "apiKey": "value"
+			`(?i:\b(?:api[_-]?key|access[_-]?key|secret[_-]?key|client[_-]?secret)["']?\s*[:=]\s*["']?(?P<value>[^\s"'&,;]{8,}))`,
+		}, "|")),
+	},
+	{
+		name: RedactDetectorEmail,
+		expr: regexp.MustCompile(`\b[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}\b`),
+	},
+	{
+		name:  RedactDetectorCreditCard,
+		expr:  regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`),
+		valid: validCreditCard,
+	},
+	{
+		name:  RedactDetectorIBAN,
+		expr:  regexp.MustCompile(`\b[A-Z]{2}\d{2}(?: ?[A-Z0-9]{4}){2,7}(?: ?[A-Z0-9]{1,4})?\b`),
+		valid: validIBAN,
+	},
+	{
+		name:  RedactDetectorIPv6,
+		expr:  regexp.MustCompile(`(?i)(?:[0-9a-f]{1,4}:|:)(?:[0-9a-f]{0,4}:){1,6}(?:\d{1,3}(?:\.\d{1,3}){3}|[0-9a-f]{1,4}|:)`),
+		valid: validIPv6,
+	},
+	{
+		name: RedactDetectorIPv4,
+		expr: regexp.MustCompile(`\b(?:(?:25[0-5]|2[0-4]\d|1\d\d|[1-9]?\d)\.){3}(?:25[0-5]|2[0-4]\d|1\d\d|[1-9]?\d)\b`),
+	},
+}
+
+// RedactConfig contains the configuration for a redactStage
+type RedactConfig struct {
+	Detectors []string    `mapstructure:"detectors"`
+	Source    interface{} `mapstructure:"source"`
+	Mode      string      `mapstructure:"mode"`
+	Token     string      `mapstructure:"token"`
+	Keep      *int        `mapstructure:"keep"`
+	MaskChar  string      `mapstructure:"mask_char"`
+	HashKey   string      `mapstructure:"hash_key"`
+}
+
+// validateRedactConfig validates the config and returns the enabled detectors and the sources
+func validateRedactConfig(c *RedactConfig) ([]*detector, []string, error) {
+	if c == nil {
+		return nil, nil, errors.New(ErrEmptyRedactStageConfig)
+	}
+
+	enabled := detectors
+	if len(c.Detectors) > 0 {
+		names := make(map[string]struct{}, len(c.Detectors))
+		for _, name := range c.Detectors {
+			if !isDetector(name) {
+				return nil, nil, fmt.Errorf(ErrRedactUnknownDetector, name)
+			}
+			names[name] = struct{}{}
+		}
+		enabled = nil
+		for _, d := range detectors {
+			if _, ok := names[d.name]; ok {
+				enabled = append(enabled, d)
+			}
+		}
+	}
+
+	switch c.Mode {
+	case "":
+		c.Mode = RedactModeToken
+	case RedactModeToken, RedactModeMask, RedactModeHash:
+	default:
+		return nil, nil, errors.New(ErrRedactInvalidMode)
+	}
+	// Without a secret key, the hashes of low entropy values like emails or IPs could be reversed by brute force.
+	if c.Mode == RedactModeHash && c.HashKey == "" {
+		return nil, nil, errors.New(ErrRedactHashKeyRequired)
+	}
+	if c.Keep == nil {
+		keep := defaultRedactKeep
+		c.Keep = &keep
+	} else if *c.Keep < 0 {
+		return nil, nil, errors.New(ErrRedactNegativeKeep)
+	}
+	if c.MaskChar == "" {
+		c.MaskChar = defaultRedactMaskChar
+	} else if len([]rune(c.MaskChar)) != 1 {
+		return nil, nil, errors.New(ErrRedactInvalidMaskLength)
+	}
+
+	var sources []string
+	if c.Source != nil {
+		var err error
+		sources, err = unifySourceField(c.Source)
+		if err != nil {
+			return nil, nil, errors.New(ErrRedactInvalidSource)
+		}
+	}
+	return enabled, sources, nil
+}
+
+func isDetector(name string) bool {
+	for _, d := range detectors {
+		if d.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// redactStage replaces sensitive values found by detectors in the log line or extracted values
+type redactStage struct {
+	cfg       *RedactConfig
+	detectors []*detector
+	sources   []string
+	logger    log.Logger
+	redacted  *prometheus.CounterVec
+}
+
+// newRedactStage creates a new redactStage
+func newRedactStage(logger log.Logger, config interface{}, registerer prometheus.Registerer) (Stage, error) {
+	cfg := &RedactConfig{}
+	if err := mapstructure.WeakDecode(config, cfg); err != nil {
+		return nil, err
+	}
+	enabled, sources, err := validateRedactConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return toStage(&redactStage{
+		cfg:       cfg,
+		detectors: enabled,
+		sources:   sources,
+		logger:    log.With(logger, "component", "stage", "type", "redact"),
+		redacted: util.RegisterCounterVec(registerer, "logentry", "redacted_values_total",
+			"A count of all the values redacted by the redact stage, by detector.", []string{"detector"}),
+	}), nil
+}
+
+// Process implements Stage
+func (r *redactStage) Process(_ model.LabelSet, extracted map[string]interface{}, _ *time.Time, entry *string) {
+	if r.sources == nil {
+		if entry != nil {
+			*entry = r.redact(*entry)
+		}
+		return
+	}
+
+	for _, source := range r.sources {
+		v, ok := extracted[source]
+		if !ok {
+			if Debug {
+				level.Debug(r.logger).Log("msg", "source does not exist in the set of extracted values", "source", source)
+			}
+			continue
+		}
+		value, err := getString(v)
+		if err != nil {
+			if Debug {
+				level.Debug(r.logger).Log("msg", "failed to convert source value to string", "source", source, "err", err, "type", reflect.TypeOf(v))
+			}
+			continue
+		}
+		extracted[source] = r.redact(value)
+	}
+}
+
+// redact returns s with the values found by the detectors replaced.
+func (r *redactStage) redact(s string) string {
+	for _, d := range r.detectors {
+		matches := d.expr.FindAllStringSubmatchIndex(s, -1)
+		if matches == nil {
+			continue
+		}
+		group := d.expr.SubexpIndex("value")
+
+		var (
+			sb    strings.Builder
+			last  int
+			count int
+		)
+		for _, m := range matches {
+			start, end := m[0], m[1]
+			if group > 0 && m[2*group] >= 0 {
+				start, end = m[2*group], m[2*group+1]
+			}
+			value := s[start:end]
+			if d.valid != nil && !d.valid(value) {
+				continue
+			}
+			sb.WriteString(s[last:start])
+			sb.WriteString(r.replacement(d.name, value))
+			last = end
+			count++
+		}
+		if count == 0 {
+			continue
+		}
+		sb.WriteString(s[last:])
+		s = sb.String()
+		r.redacted.WithLabelValues(d.name).Add(float64(count))
+	}
+	return s
+}
+
+// replacement returns the value replacing a value found by a detector.
+func (r *redactStage) replacement(name, value string) string {
+	switch r.cfg.Mode {
+	case RedactModeMask:
+		runes := []rune(value)
+		masked := len(runes) - *r.cfg.Keep
+		if masked < 0 {
+			masked = 0
+		}
+		return strings.Repeat(r.cfg.MaskChar, masked) + string(runes[masked:])
+	case RedactModeHash:
+		mac := hmac.New(sha256.New, []byte(r.cfg.HashKey))
+		_, _ = mac.Write([]byte(value))
+		return hex.EncodeToString(mac.Sum(nil))[:redactHashLength]
+	default:
+		if r.cfg.Token != "" {
+			return r.cfg.Token
+		}
+		return "<redacted:" + name + ">"
+	}
+}
+
+// Name implements Stage
+func (r *redactStage) Name() string {
+	return StageTypeRedact
+}
+
+// validCreditCard reports whether the digits of s start with the prefix of a card network and pass the Luhn
+// checksum. Checking the prefix avoids redacting numbers like Unix timestamps.
+func validCreditCard(s string) bool {
+	digits := strings.NewReplacer(" ", "", "-", "").Replace(s)
+	if len(digits) < 13 || len(digits) > 19 {
+		return false
+	}
+	switch d0, d1 := digits[0], digits[1]; {
+	case d0 == '4': // Visa
+	case d0 == '5' && d1 >= '1' && d1 <= '5': // Mastercard
+	case d0 == '2' && d1 >= '2' && d1 <= '7': // Mastercard
+	case d0 == '3' && strings.IndexByte("047689", d1) >= 0: // American Express, Diners Club, JCB
+	case d0 == '6': // Discover, UnionPay, Maestro
+	default:
+		return false
+	}
+
+	var sum int
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if (len(digits)-1-i)%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return sum%10 == 0
+}
+
+// validIBAN reports whether s is an IBAN with a valid mod-97 checksum.
+func validIBAN(s string) bool {
+	s = strings.ReplaceAll(s, " ", "")
+	if len(s) < 15 || len(s) > 34 {
+		return false
+	}
+	var digits strings.Builder
+	for _, c := range s[4:] + s[:4] {
+		switch {
+		case c >= '0' && c <= '9':
+			digits.WriteRune(c)
+		case c >= 'A' && c <= 'Z':
+			fmt.Fprintf(&digits, "%d", c-'A'+10)
+		default:
+			return false
+		}
+	}
+	n, ok := new(big.Int).SetString(digits.String(), 10)
+	return ok && new(big.Int).Mod(n, big.NewInt(97)).Int64() == 1
+}
+
+// validIPv6 reports whether s is an IPv6 address.
+func validIPv6(s string) bool {
+	addr, err := netip.ParseAddr(s)
+	return err == nil && addr.Is6()
+}
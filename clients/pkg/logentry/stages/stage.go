@@ -41,6 +41,10 @@ const (
 	StageTypeDecolorize      = "decolorize"
 	StageTypeEventLogMessage = "eventlogmessage"
 	StageTypeGeoIP           = "geoip"
+	StageTypeRedact          = "redact"
+	StageTypeLua             = "lua"
+	StageTypeAggregate       = "aggregate"
+	StageTypeDedup           = "dedup"
 	// Deprecated. Renamed to `structured_metadata`. Will be removed after the migration.
 	StageTypeNonIndexedLabels   = "non_indexed_labels"
 	StageTypeStructuredMetadata = "structured_metadata"
@@ -207,6 +211,18 @@ func initCreators() {
 		StageTypeGeoIP: func(params StageCreationParams) (Stage, error) {
 			return newGeoIPStage(params.logger, params.config)
 		},
+		StageTypeRedact: func(params StageCreationParams) (Stage, error) {
+			return newRedactStage(params.logger, params.config, params.registerer)
+		},
+		StageTypeLua: func(params StageCreationParams) (Stage, error) {
+			return newLuaStage(params.logger, params.config, params.registerer)
+		},
+		StageTypeAggregate: func(params StageCreationParams) (Stage, error) {
+			return newAggregateStage(params.logger, params.config)
+		},
+		StageTypeDedup: func(params StageCreationParams) (Stage, error) {
+			return newDedupStage(params.logger, params.config, params.registerer)
+		},
 		StageTypeNonIndexedLabels:   newStructuredMetadataStage,
 		StageTypeStructuredMetadata: newStructuredMetadataStage,
 	}
@@ -60,6 +60,8 @@ type Metrics struct {
 	mutatedBytes                 *prometheus.CounterVec
 	requestDuration              *prometheus.HistogramVec
 	batchRetries                 *prometheus.CounterVec
+	routedEntries                *prometheus.CounterVec
+	unroutedEntries              prometheus.Counter
 	countersWithHost             []*prometheus.CounterVec
 	countersWithHostTenant       []*prometheus.CounterVec
 	countersWithHostTenantReason []*prometheus.CounterVec
@@ -113,6 +115,16 @@ func NewMetrics(reg prometheus.Registerer) *Metrics {
 		Name:      "batch_retries_total",
 		Help:      "Number of times batches has had to be retried.",
 	}, []string{HostLabel, TenantLabel})
+	m.routedEntries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "promtail",
+		Name:      "routed_entries_total",
+		Help:      "Number of log entries routed to each client.",
+	}, []string{ClientLabel})
+	m.unroutedEntries = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "promtail",
+		Name:      "unrouted_entries_total",
+		Help:      "Number of log entries dropped because they were routed to no client.",
+	})
 
 	m.countersWithHost = []*prometheus.CounterVec{
 		m.encodedBytes, m.sentBytes, m.sentEntries,
@@ -136,6 +148,8 @@ func NewMetrics(reg prometheus.Registerer) *Metrics {
 		m.mutatedBytes = mustRegisterOrGet(reg, m.mutatedBytes).(*prometheus.CounterVec)
 		m.requestDuration = mustRegisterOrGet(reg, m.requestDuration).(*prometheus.HistogramVec)
 		m.batchRetries = mustRegisterOrGet(reg, m.batchRetries).(*prometheus.CounterVec)
+		m.routedEntries = mustRegisterOrGet(reg, m.routedEntries).(*prometheus.CounterVec)
+		m.unroutedEntries = mustRegisterOrGet(reg, m.unroutedEntries).(prometheus.Counter)
 	}
 
 	return &m
@@ -32,14 +32,17 @@ type clientWriteTo struct {
 
 	logger   log.Logger
 	toClient chan<- api.Entry
+	// route selects the entries sent to the client, all entries are sent if nil.
+	route routeFunc
 }
 
 // newClientWriteTo creates a new clientWriteTo
-func newClientWriteTo(toClient chan<- api.Entry, logger log.Logger) *clientWriteTo {
+func newClientWriteTo(toClient chan<- api.Entry, route routeFunc, logger log.Logger) *clientWriteTo {
 	return &clientWriteTo{
 		series:        make(map[chunks.HeadSeriesRef]model.LabelSet),
 		seriesSegment: make(map[chunks.HeadSeriesRef]int),
 		toClient:      toClient,
+		route:         route,
 		logger:        logger,
 	}
 }
@@ -77,6 +80,9 @@ func (c *clientWriteTo) AppendEntries(entries wal.RefEntries) error {
 	l, ok := c.series[entries.Ref]
 	c.seriesLock.RUnlock()
 	if ok {
+		if c.route != nil && !c.route(l) {
+			return nil
+		}
 		entry.Labels = l
 		for _, e := range entries.Entries {
 			entry.Entry = e
@@ -45,7 +45,7 @@ func TestClientWriter_LogEntriesAreReconstructedAndForwardedCorrectly(t *testing
 		"I'm in a starbucks",
 	}
 
-	writeTo := newClientWriteTo(ch, logger)
+	writeTo := newClientWriteTo(ch, nil, logger)
 	testAppLabelsRef := chunks.HeadSeriesRef(1)
 	writeTo.StoreSeries([]record.RefSeries{
 		{
@@ -101,7 +101,7 @@ func TestClientWriter_LogEntriesWithoutMatchingSeriesAreIgnored(t *testing.T) {
 		"I'm in a starbucks",
 	}
 
-	writeTo := newClientWriteTo(ch, logger)
+	writeTo := newClientWriteTo(ch, nil, logger)
 	testAppLabelsRef := chunks.HeadSeriesRef(1)
 	writeTo.StoreSeries([]record.RefSeries{
 		{
@@ -174,7 +174,7 @@ func bench(numWriters, totalLines int, b *testing.B) {
 		}
 	}()
 
-	writeTo := newClientWriteTo(ch, logger)
+	writeTo := newClientWriteTo(ch, nil, logger)
 
 	// spin up the numWriters routines
 	writersWG := sync.WaitGroup{}
@@ -48,6 +48,21 @@ type Config struct {
 	// 429 'Too Many Requests' response from the distributor. Helps
 	// prevent HOL blocking in multitenant deployments.
 	DropRateLimitedBatches bool `yaml:"drop_rate_limited_batches"`
+
+	// Routing selects the entries sent to this client, all entries are sent
+	// when it is empty.
+	Routing RoutingConfig `yaml:"routing,omitempty"`
+}
+
+// RoutingConfig describes which entries are sent to a client.
+type RoutingConfig struct {
+	// Selector is a stream selector, e.g. {job="security"}, matched against
+	// the labels of the entries before the external labels are added.
+	Selector string `yaml:"selector,omitempty"`
+
+	// Fallback sends to the client the entries matched by the selector of
+	// no other client.
+	Fallback bool `yaml:"fallback,omitempty"`
 }
 
 // RegisterFlags with prefix registers flags where every name is prefixed by
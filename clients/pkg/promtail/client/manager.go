@@ -47,6 +47,9 @@ type Manager struct {
 	name        string
 	clients     []Client
 	walWatchers []Stoppable
+	// routes holds the route of each client, it is nil when no client has routing configured.
+	routes  []routeFunc
+	metrics *Metrics
 
 	entries chan api.Entry
 	once    sync.Once
@@ -66,7 +69,7 @@ func NewManager(metrics *Metrics, logger log.Logger, limits limit.Config, reg pr
 
 	clientsCheck := make(map[string]struct{})
 	clients := make([]Client, 0, len(clientCfgs))
-	watchers := make([]Stoppable, 0, len(clientCfgs))
+	names := make([]string, 0, len(clientCfgs))
 	for _, cfg := range clientCfgs {
 		client, err := New(metrics, cfg, limits.MaxStreams, limits.MaxLineSize.Val(), limits.MaxLineSizeTruncate, logger)
 		if err != nil {
@@ -80,14 +83,27 @@ func NewManager(metrics *Metrics, logger log.Logger, limits limit.Config, reg pr
 
 		clientsCheck[client.Name()] = fake
 		clients = append(clients, client)
+		names = append(names, client.Name())
+	}
 
+	routes, err := newRoutes(names, clientCfgs)
+	if err != nil {
+		return nil, err
+	}
+
+	watchers := make([]Stoppable, 0, len(clientCfgs))
+	for i, client := range clients {
 		if walCfg.Enabled {
 			// Create and launch wal watcher for this client
 
 			// add some context information for the logger the watcher uses
 			wlog := log.With(logger, "client", client.Name())
 
-			writeTo := newClientWriteTo(client.Chan(), wlog)
+			var route routeFunc
+			if routes != nil {
+				route = routes[i]
+			}
+			writeTo := newClientWriteTo(client.Chan(), route, wlog)
 			// subscribe watcher's wal.WriteTo to writer events. This will make the writer trigger the cleanup of the wal.WriteTo
 			// series cache whenever a segment is deleted.
 			notifier.SubscribeCleanup(writeTo)
@@ -105,6 +121,8 @@ func NewManager(metrics *Metrics, logger log.Logger, limits limit.Config, reg pr
 	manager := &Manager{
 		clients:     clients,
 		walWatchers: watchers,
+		routes:      routes,
+		metrics:     metrics,
 		entries:     make(chan api.Entry),
 	}
 	if walCfg.Enabled {
@@ -121,32 +139,61 @@ func NewManager(metrics *Metrics, logger log.Logger, limits limit.Config, reg pr
 // This is necessary since to treat the WAL-enabled manager the same way as the WAL-disabled one, the processing pipeline
 // send entries both to the WAL writer, and the channel exposed by the manager. In the case the WAL is enabled, these entries
 // are not used since they are read from the WAL, so we need a routine to just read the entries received through the channel
-// and discarding them, to not block the sending side.
+// and discarding them, to not block the sending side. The routing metrics are still updated from them, the WAL watchers
+// routing the same entries.
 func (m *Manager) startWithConsume() {
 	m.wg.Add(1)
 	go func() {
 		defer m.wg.Done()
-		// discard read entries
-		//nolint:revive
-		for range m.entries {
+		if m.routes == nil {
+			// discard read entries
+			//nolint:revive
+			for range m.entries {
+			}
+			return
+		}
+		for e := range m.entries {
+			m.route(e, func(Client) {})
 		}
 	}()
 }
 
 // startWithForward starts the main manager routine, which reads entries from the exposed channel, and forwards them
-// doing a fan-out across all inner clients.
+// doing a fan-out across all inner clients, or across the clients the entries are routed to.
 func (m *Manager) startWithForward() {
 	m.wg.Add(1)
 	go func() {
 		defer m.wg.Done()
 		for e := range m.entries {
-			for _, c := range m.clients {
-				c.Chan() <- e
+			if m.routes == nil {
+				for _, c := range m.clients {
+					c.Chan() <- e
+				}
+				continue
 			}
+			m.route(e, func(c Client) {
+				c.Chan() <- e
+			})
 		}
 	}()
 }
 
+// route calls send with each client the entry is routed to, and updates the routing metrics.
+func (m *Manager) route(e api.Entry, send func(Client)) {
+	var routed bool
+	for i, c := range m.clients {
+		if route := m.routes[i]; route != nil && !route(e.Labels) {
+			continue
+		}
+		routed = true
+		m.metrics.routedEntries.WithLabelValues(c.Name()).Inc()
+		send(c)
+	}
+	if !routed {
+		m.metrics.unroutedEntries.Inc()
+	}
+}
+
 func (m *Manager) StopNow() {
 	for _, c := range m.clients {
 		c.StopNow()
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"sync"
 	"testing"
 	"time"
 
@@ -13,6 +14,7 @@ import (
 	"github.com/grafana/dskit/backoff"
 	"github.com/grafana/dskit/flagext"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/require"
 
@@ -302,6 +304,102 @@ func TestManager_WALDisabled_MultipleConfigs(t *testing.T) {
 	require.Len(t, seenEntries, expectedTotalLines)
 }
 
+func TestManager_Routing(t *testing.T) {
+	for _, walEnabled := range []bool{true, false} {
+		t.Run(fmt.Sprintf("wal-enabled = %t", walEnabled), func(t *testing.T) {
+			walConfig := wal.Config{
+				Dir:           t.TempDir(),
+				Enabled:       walEnabled,
+				MaxSegmentAge: time.Second * 10,
+				WatchConfig:   wal.DefaultWatchConfig,
+			}
+			reg := prometheus.NewRegistry()
+			logger := log.NewLogfmtLogger(os.Stdout)
+			clientMetrics := NewMetrics(reg)
+
+			securityConfig, securityReqs, closeSecurity := newServerAndClientConfig(t)
+			securityConfig.Name = "security"
+			securityConfig.Routing = RoutingConfig{Selector: `{category="security"}`}
+			defaultConfig, defaultReqs, closeDefault := newServerAndClientConfig(t)
+			defaultConfig.Name = "default"
+			defaultConfig.Routing = RoutingConfig{Fallback: true}
+			allConfig, allReqs, closeAll := newServerAndClientConfig(t)
+			allConfig.Name = "all"
+
+			var (
+				writer   *wal.Writer
+				notifier WriterEventsNotifier = NilNotifier
+				err      error
+			)
+			if walEnabled {
+				writer, err = wal.NewWriter(walConfig, logger, reg)
+				require.NoError(t, err)
+				notifier = writer
+			}
+			manager, err := NewManager(clientMetrics, logger, testLimitsConfig, prometheus.NewRegistry(), walConfig, notifier, securityConfig, defaultConfig, allConfig)
+			require.NoError(t, err)
+
+			var (
+				mtx      sync.Mutex
+				received = map[string][]string{}
+			)
+			collect := func(name string, reqs chan utils.RemoteWriteRequest) {
+				for req := range reqs {
+					mtx.Lock()
+					for _, s := range req.Request.Streams {
+						for _, e := range s.Entries {
+							received[name] = append(received[name], e.Line)
+						}
+					}
+					mtx.Unlock()
+				}
+			}
+			go collect("security", securityReqs)
+			go collect("default", defaultReqs)
+			go collect("all", allReqs)
+
+			defer func() {
+				if writer != nil {
+					writer.Stop()
+				}
+				manager.Stop()
+				closeSecurity.Close()
+				closeDefault.Close()
+				closeAll.Close()
+			}()
+
+			for i, category := range []string{"security", "app", "security", "app", "app"} {
+				e := api.Entry{
+					Labels: model.LabelSet{"category": model.LabelValue(category)},
+					Entry: logproto.Entry{
+						Timestamp: time.Now(),
+						Line:      fmt.Sprintf("%s%d", category, i),
+					},
+				}
+				if writer != nil {
+					writer.Chan() <- e
+				}
+				manager.Chan() <- e
+			}
+
+			require.Eventually(t, func() bool {
+				mtx.Lock()
+				defer mtx.Unlock()
+				return len(received["security"]) == 2 && len(received["default"]) == 3 && len(received["all"]) == 5
+			}, 5*time.Second, 10*time.Millisecond, "timed out waiting for requests to be received")
+			mtx.Lock()
+			require.ElementsMatch(t, []string{"security0", "security2"}, received["security"])
+			require.ElementsMatch(t, []string{"app1", "app3", "app4"}, received["default"])
+			mtx.Unlock()
+
+			require.Equal(t, 2.0, testutil.ToFloat64(clientMetrics.routedEntries.WithLabelValues("security")))
+			require.Equal(t, 3.0, testutil.ToFloat64(clientMetrics.routedEntries.WithLabelValues("default")))
+			require.Equal(t, 5.0, testutil.ToFloat64(clientMetrics.routedEntries.WithLabelValues("all")))
+			require.Equal(t, 0.0, testutil.ToFloat64(clientMetrics.unroutedEntries))
+		})
+	}
+}
+
 func TestManager_StopClients(t *testing.T) {
 	var stopped int
 
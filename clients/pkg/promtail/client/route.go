@@ -0,0 +1,70 @@
+package client
+
+import (
+	"fmt"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+
+	"github.com/grafana/loki/clients/pkg/logentry/logql"
+)
+
+// routeFunc reports whether an entry with the given labels is sent to a client.
+type routeFunc func(model.LabelSet) bool
+
+// newRoutes builds the route of each client from its routing config. A nil route sends all entries to its client, and
+// nil is returned when no client has a routing config.
+func newRoutes(names []string, cfgs []Config) ([]routeFunc, error) {
+	var routed bool
+	selectors := make([][]*labels.Matcher, len(cfgs))
+	for i, cfg := range cfgs {
+		if cfg.Routing.Fallback {
+			routed = true
+		}
+		if cfg.Routing.Selector == "" {
+			continue
+		}
+		if cfg.Routing.Fallback {
+			return nil, fmt.Errorf("routing selector and fallback are mutually exclusive, found both for client: %s", names[i])
+		}
+		matchers, err := logql.ParseMatchers(cfg.Routing.Selector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid routing selector for client %s: %w", names[i], err)
+		}
+		selectors[i] = matchers
+		routed = true
+	}
+	if !routed {
+		return nil, nil
+	}
+
+	routes := make([]routeFunc, len(cfgs))
+	for i, cfg := range cfgs {
+		switch {
+		case selectors[i] != nil:
+			matchers := selectors[i]
+			routes[i] = func(ls model.LabelSet) bool {
+				return matches(matchers, ls)
+			}
+		case cfg.Routing.Fallback:
+			routes[i] = func(ls model.LabelSet) bool {
+				for _, matchers := range selectors {
+					if matchers != nil && matches(matchers, ls) {
+						return false
+					}
+				}
+				return true
+			}
+		}
+	}
+	return routes, nil
+}
+
+func matches(matchers []*labels.Matcher, ls model.LabelSet) bool {
+	for _, m := range matchers {
+		if !m.Matches(string(ls[model.LabelName(m.Name)])) {
+			return false
+		}
+	}
+	return true
+}
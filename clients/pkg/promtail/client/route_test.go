@@ -0,0 +1,85 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRoutes(t *testing.T) {
+	var (
+		security = model.LabelSet{"job": "auth", "category": "security"}
+		app      = model.LabelSet{"job": "app"}
+	)
+	for _, tc := range []struct {
+		name     string
+		routing  []RoutingConfig
+		expected [][]bool // whether security and app entries are sent to each client
+		err      string
+	}{
+		{
+			name:    "no routing",
+			routing: []RoutingConfig{{}, {}},
+		},
+		{
+			name:     "selector and fallback",
+			routing:  []RoutingConfig{{Selector: `{category="security"}`}, {Fallback: true}},
+			expected: [][]bool{{true, false}, {false, true}},
+		},
+		{
+			name:     "selector and all entries",
+			routing:  []RoutingConfig{{Selector: `{category="security"}`}, {}},
+			expected: [][]bool{{true, false}, {true, true}},
+		},
+		{
+			name:     "overlapping selectors",
+			routing:  []RoutingConfig{{Selector: `{category="security"}`}, {Selector: `{job=~"auth|app"}`}, {Fallback: true}},
+			expected: [][]bool{{true, false}, {true, true}, {false, false}},
+		},
+		{
+			name:     "negative matcher",
+			routing:  []RoutingConfig{{Selector: `{category!="security"}`}},
+			expected: [][]bool{{false, true}},
+		},
+		{
+			name:    "invalid selector",
+			routing: []RoutingConfig{{Selector: `{category="security"`}},
+			err:     "invalid routing selector for client client-0",
+		},
+		{
+			name:    "selector and fallback on the same client",
+			routing: []RoutingConfig{{Selector: `{category="security"}`, Fallback: true}},
+			err:     "mutually exclusive",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				names = make([]string, len(tc.routing))
+				cfgs  = make([]Config, len(tc.routing))
+			)
+			for i, r := range tc.routing {
+				names[i] = "client-" + string(rune('0'+i))
+				cfgs[i] = Config{Routing: r}
+			}
+			routes, err := newRoutes(names, cfgs)
+			if tc.err != "" {
+				require.ErrorContains(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			if tc.expected == nil {
+				require.Nil(t, routes)
+				return
+			}
+			require.Len(t, routes, len(tc.expected))
+			for i, expected := range tc.expected {
+				route := routes[i]
+				if route == nil {
+					route = func(model.LabelSet) bool { return true }
+				}
+				require.Equal(t, expected, []bool{route(security), route(app)}, "client %d", i)
+			}
+		})
+	}
+}
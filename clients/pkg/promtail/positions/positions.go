@@ -48,6 +48,7 @@ type positions struct {
 	cfg       Config
 	mtx       sync.Mutex
 	positions map[string]string
+	files     map[string]FileIdentity
 	quit      chan struct{}
 	done      chan struct{}
 }
@@ -55,6 +56,19 @@ type positions struct {
 // File format for the positions data.
 type File struct {
 	Positions map[string]string `yaml:"positions"`
+	// Files holds the identity of the files whose position is recorded. Positions
+	// files written by older versions only have path-keyed positions.
+	Files map[string]FileIdentity `yaml:"files,omitempty"`
+}
+
+// FileIdentity identifies a file independently of its path, so that it can be
+// followed across renames and its truncation detected.
+type FileIdentity struct {
+	Device uint64 `yaml:"device,omitempty"`
+	Inode  uint64 `yaml:"inode,omitempty"`
+	// Fingerprint is the hash of the first FingerprintSize bytes of the file.
+	Fingerprint     string `yaml:"fingerprint"`
+	FingerprintSize int64  `yaml:"fingerprint_size"`
 }
 
 type Positions interface {
@@ -72,6 +86,15 @@ type Positions interface {
 	PutString(path string, pos string)
 	// Put records (asynchronously) how far we've read through a file.
 	Put(path string, pos int64)
+	// PutFile records (asynchronously) how far we've read through a file
+	// along with the identity of the file.
+	PutFile(path string, pos int64, id FileIdentity)
+	// GetFileIdentity returns the identity recorded for a file, if any.
+	GetFileIdentity(path string) (FileIdentity, bool)
+	// FileIdentities returns the identities of all the files recorded, by path.
+	FileIdentities() map[string]FileIdentity
+	// Keys returns the keys of the positions recorded which start with prefix.
+	Keys(prefix string) []string
 	// Remove removes the position tracking for a filepath
 	Remove(path string)
 	// SyncPeriod returns how often the positions file gets resynced
@@ -90,7 +113,8 @@ func New(logger log.Logger, cfg Config) (Positions, error) {
 	p := &positions{
 		logger:    logger,
 		cfg:       cfg,
-		positions: positionData,
+		positions: positionData.Positions,
+		files:     positionData.Files,
 		quit:      make(chan struct{}),
 		done:      make(chan struct{}),
 	}
@@ -130,6 +154,42 @@ func (p *positions) Get(path string) (int64, error) {
 	return strconv.ParseInt(pos, 10, 64)
 }
 
+func (p *positions) PutFile(path string, pos int64, id FileIdentity) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	p.positions[path] = strconv.FormatInt(pos, 10)
+	p.files[path] = id
+}
+
+func (p *positions) GetFileIdentity(path string) (FileIdentity, bool) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	id, ok := p.files[path]
+	return id, ok
+}
+
+func (p *positions) FileIdentities() map[string]FileIdentity {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	files := make(map[string]FileIdentity, len(p.files))
+	for k, v := range p.files {
+		files[k] = v
+	}
+	return files
+}
+
+func (p *positions) Keys(prefix string) []string {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	var keys []string
+	for k := range p.positions {
+		if strings.HasPrefix(k, prefix) {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
 func (p *positions) Remove(path string) {
 	p.mtx.Lock()
 	defer p.mtx.Unlock()
@@ -138,6 +198,7 @@ func (p *positions) Remove(path string) {
 
 func (p *positions) remove(path string) {
 	delete(p.positions, path)
+	delete(p.files, path)
 }
 
 func (p *positions) SyncPeriod() time.Duration {
@@ -173,9 +234,13 @@ func (p *positions) save() {
 	for k, v := range p.positions {
 		positions[k] = v
 	}
+	files := make(map[string]FileIdentity, len(p.files))
+	for k, v := range p.files {
+		files[k] = v
+	}
 	p.mtx.Unlock()
 
-	if err := writePositionFile(p.cfg.PositionsFile, positions); err != nil {
+	if err := writePositionFile(p.cfg.PositionsFile, File{Positions: positions, Files: files}); err != nil {
 		level.Error(p.logger).Log("msg", "error writing positions file", "error", err)
 	}
 }
@@ -213,14 +278,16 @@ func (p *positions) cleanup() {
 	}
 }
 
-func readPositionsFile(cfg Config, logger log.Logger) (map[string]string, error) {
+func readPositionsFile(cfg Config, logger log.Logger) (File, error) {
+	empty := File{Positions: map[string]string{}, Files: map[string]FileIdentity{}}
+
 	cleanfn := filepath.Clean(cfg.PositionsFile)
 	buf, err := os.ReadFile(cleanfn)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return map[string]string{}, nil
+			return empty, nil
 		}
-		return nil, err
+		return File{}, err
 	}
 
 	var p File
@@ -229,16 +296,20 @@ func readPositionsFile(cfg Config, logger log.Logger) (map[string]string, error)
 		// return empty if cfg option enabled
 		if cfg.IgnoreInvalidYaml {
 			level.Debug(logger).Log("msg", "ignoring invalid positions file", "file", cleanfn, "error", err)
-			return map[string]string{}, nil
+			return empty, nil
 		}
 
-		return nil, fmt.Errorf("invalid yaml positions file [%s]: %v", cleanfn, err)
+		return File{}, fmt.Errorf("invalid yaml positions file [%s]: %v", cleanfn, err)
 	}
 
 	// p.Positions will be nil if the file exists but is empty
 	if p.Positions == nil {
 		p.Positions = map[string]string{}
 	}
+	// p.Files will be nil if the file was written by a version without file identities.
+	if p.Files == nil {
+		p.Files = map[string]FileIdentity{}
+	}
 
-	return p.Positions, nil
+	return p, nil
 }
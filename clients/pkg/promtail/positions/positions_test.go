@@ -52,7 +52,7 @@ func TestReadPositionsOK(t *testing.T) {
 	}, log.NewNopLogger())
 
 	require.NoError(t, err)
-	require.Equal(t, "17623", pos["/log/path/random.log"])
+	require.Equal(t, "17623", pos.Positions["/log/path/random.log"])
 }
 
 func TestReadPositionsEmptyFile(t *testing.T) {
@@ -72,7 +72,7 @@ func TestReadPositionsEmptyFile(t *testing.T) {
 	}, log.NewNopLogger())
 
 	require.NoError(t, err)
-	require.NotNil(t, pos)
+	require.NotNil(t, pos.Positions)
 }
 
 func TestReadPositionsFromDir(t *testing.T) {
@@ -136,7 +136,7 @@ func TestReadPositionsFromBadYamlIgnoreCorruption(t *testing.T) {
 	}, log.NewNopLogger())
 
 	require.NoError(t, err)
-	require.Equal(t, map[string]string{}, out)
+	require.Equal(t, map[string]string{}, out.Positions)
 }
 
 func Test_ReadOnly(t *testing.T) {
@@ -177,6 +177,72 @@ func Test_ReadOnly(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, map[string]string{
 		"/log/path/random.log": "17623",
+	}, out.Positions)
+
+}
+
+func TestFileIdentities(t *testing.T) {
+	temp := tempFilename(t)
+	defer func() {
+		_ = os.Remove(temp)
+	}()
+	// Positions files written by older versions only have path-keyed positions.
+	yaml := []byte(`positions:
+  /log/path/random.log: "17623"
+`)
+	err := os.WriteFile(temp, yaml, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := New(util_log.Logger, Config{
+		SyncPeriod:    10 * time.Second,
+		PositionsFile: temp,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, ok := p.GetFileIdentity("/log/path/random.log")
+	require.False(t, ok)
+
+	id := FileIdentity{Device: 1, Inode: 2, Fingerprint: "8ba6b1f2a09ad36c", FingerprintSize: 1024}
+	p.PutFile("/log/path/random.log", 17700, id)
+	p.PutFile("/log/path/other.log", 10, FileIdentity{Device: 1, Inode: 3})
+	p.Remove("/log/path/other.log")
+	p.Stop()
+
+	out, err := readPositionsFile(Config{PositionsFile: temp}, log.NewNopLogger())
+	require.NoError(t, err)
+	require.Equal(t, File{
+		Positions: map[string]string{"/log/path/random.log": "17700"},
+		Files:     map[string]FileIdentity{"/log/path/random.log": id},
 	}, out)
 
+	p, err = New(util_log.Logger, Config{
+		SyncPeriod:    10 * time.Second,
+		PositionsFile: temp,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Stop()
+	got, ok := p.GetFileIdentity("/log/path/random.log")
+	require.True(t, ok)
+	require.Equal(t, id, got)
+	require.Equal(t, map[string]FileIdentity{"/log/path/random.log": id}, p.FileIdentities())
+}
+
+func TestKeys(t *testing.T) {
+	p, err := New(util_log.Logger, Config{
+		SyncPeriod:    10 * time.Second,
+		PositionsFile: tempFilename(t),
+	})
+	require.NoError(t, err)
+	defer p.Stop()
+
+	p.Put("/log/path/random.log", 10)
+	p.PutString(CursorKey("objectstore-job/a.log"), "5:done")
+	p.PutString(CursorKey("objectstore-job/b.log"), "7")
+	require.ElementsMatch(t, []string{CursorKey("objectstore-job/a.log"), CursorKey("objectstore-job/b.log")}, p.Keys(CursorKey("objectstore-job/")))
+	require.Len(t, p.Keys(""), 3)
+	require.Empty(t, p.Keys("journal-"))
 }
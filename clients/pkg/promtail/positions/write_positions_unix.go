@@ -11,10 +11,8 @@ import (
 	yaml "gopkg.in/yaml.v2"
 )
 
-func writePositionFile(filename string, positions map[string]string) error {
-	buf, err := yaml.Marshal(File{
-		Positions: positions,
-	})
+func writePositionFile(filename string, positions File) error {
+	buf, err := yaml.Marshal(positions)
 	if err != nil {
 		return err
 	}
@@ -12,10 +12,8 @@ import (
 
 // writePositionFile is a fall back for Windows because renameio does not support Windows.
 // See https://github.com/google/renameio#windows-support
-func writePositionFile(filename string, positions map[string]string) error {
-	buf, err := yaml.Marshal(File{
-		Positions: positions,
-	})
+func writePositionFile(filename string, positions File) error {
+	buf, err := yaml.Marshal(positions)
 	if err != nil {
 		return err
 	}
@@ -29,23 +29,33 @@ import (
 
 	"github.com/grafana/loki/clients/pkg/logentry/stages"
 	"github.com/grafana/loki/clients/pkg/promtail/discovery/consulagent"
+
+	"github.com/grafana/loki/pkg/loghttp/push"
+	chunk_aws "github.com/grafana/loki/pkg/storage/chunk/client/aws"
+	chunk_azure "github.com/grafana/loki/pkg/storage/chunk/client/azure"
+	chunk_gcp "github.com/grafana/loki/pkg/storage/chunk/client/gcp"
+	chunk_local "github.com/grafana/loki/pkg/storage/chunk/client/local"
 )
 
 // Config describes a job to scrape.
 type Config struct {
-	JobName              string                      `mapstructure:"job_name,omitempty" yaml:"job_name,omitempty"`
-	PipelineStages       stages.PipelineStages       `mapstructure:"pipeline_stages,omitempty" yaml:"pipeline_stages,omitempty"`
-	JournalConfig        *JournalTargetConfig        `mapstructure:"journal,omitempty" yaml:"journal,omitempty"`
-	SyslogConfig         *SyslogTargetConfig         `mapstructure:"syslog,omitempty" yaml:"syslog,omitempty"`
-	GcplogConfig         *GcplogTargetConfig         `mapstructure:"gcplog,omitempty" yaml:"gcplog,omitempty"`
-	PushConfig           *PushTargetConfig           `mapstructure:"loki_push_api,omitempty" yaml:"loki_push_api,omitempty"`
-	WindowsConfig        *WindowsEventsTargetConfig  `mapstructure:"windows_events,omitempty" yaml:"windows_events,omitempty"`
-	KafkaConfig          *KafkaTargetConfig          `mapstructure:"kafka,omitempty" yaml:"kafka,omitempty"`
-	AzureEventHubsConfig *AzureEventHubsTargetConfig `mapstructure:"azure_event_hubs,omitempty" yaml:"azure_event_hubs,omitempty"`
-	GelfConfig           *GelfTargetConfig           `mapstructure:"gelf,omitempty" yaml:"gelf,omitempty"`
-	CloudflareConfig     *CloudflareConfig           `mapstructure:"cloudflare,omitempty" yaml:"cloudflare,omitempty"`
-	HerokuDrainConfig    *HerokuDrainTargetConfig    `mapstructure:"heroku_drain,omitempty" yaml:"heroku_drain,omitempty"`
-	RelabelConfigs       []*relabel.Config           `mapstructure:"relabel_configs,omitempty" yaml:"relabel_configs,omitempty"`
+	JobName                string                        `mapstructure:"job_name,omitempty" yaml:"job_name,omitempty"`
+	PipelineStages         stages.PipelineStages         `mapstructure:"pipeline_stages,omitempty" yaml:"pipeline_stages,omitempty"`
+	JournalConfig          *JournalTargetConfig          `mapstructure:"journal,omitempty" yaml:"journal,omitempty"`
+	SyslogConfig           *SyslogTargetConfig           `mapstructure:"syslog,omitempty" yaml:"syslog,omitempty"`
+	GcplogConfig           *GcplogTargetConfig           `mapstructure:"gcplog,omitempty" yaml:"gcplog,omitempty"`
+	PushConfig             *PushTargetConfig             `mapstructure:"loki_push_api,omitempty" yaml:"loki_push_api,omitempty"`
+	WindowsConfig          *WindowsEventsTargetConfig    `mapstructure:"windows_events,omitempty" yaml:"windows_events,omitempty"`
+	KafkaConfig            *KafkaTargetConfig            `mapstructure:"kafka,omitempty" yaml:"kafka,omitempty"`
+	AzureEventHubsConfig   *AzureEventHubsTargetConfig   `mapstructure:"azure_event_hubs,omitempty" yaml:"azure_event_hubs,omitempty"`
+	GelfConfig             *GelfTargetConfig             `mapstructure:"gelf,omitempty" yaml:"gelf,omitempty"`
+	CloudflareConfig       *CloudflareConfig             `mapstructure:"cloudflare,omitempty" yaml:"cloudflare,omitempty"`
+	HerokuDrainConfig      *HerokuDrainTargetConfig      `mapstructure:"heroku_drain,omitempty" yaml:"heroku_drain,omitempty"`
+	OTLPConfig             *OTLPTargetConfig             `mapstructure:"otlp,omitempty" yaml:"otlp,omitempty"`
+	ObjectStoreConfig      *ObjectStoreTargetConfig      `mapstructure:"object_store,omitempty" yaml:"object_store,omitempty"`
+	HTTPPollConfig         *HTTPPollTargetConfig         `mapstructure:"http_poll,omitempty" yaml:"http_poll,omitempty"`
+	KubernetesEventsConfig *KubernetesEventsTargetConfig `mapstructure:"kubernetes_events,omitempty" yaml:"kubernetes_events,omitempty"`
+	RelabelConfigs         []*relabel.Config             `mapstructure:"relabel_configs,omitempty" yaml:"relabel_configs,omitempty"`
 	// List of Docker service discovery configurations.
 	DockerSDConfigs        []*moby.DockerSDConfig `mapstructure:"docker_sd_configs,omitempty" yaml:"docker_sd_configs,omitempty"`
 	ServiceDiscoveryConfig ServiceDiscoveryConfig `mapstructure:",squash" yaml:",inline"`
@@ -441,6 +451,157 @@ type PushTargetConfig struct {
 	KeepTimestamp bool `yaml:"use_incoming_timestamp"`
 }
 
+// OTLPTargetConfig describes a scrape config that listens for OpenTelemetry logs sent over OTLP/HTTP or OTLP/gRPC.
+type OTLPTargetConfig struct {
+	// Server is the weaveworks server config for listening connections. OTLP/HTTP is served on the
+	// /v1/logs path of the HTTP listener and OTLP/gRPC on the gRPC listener.
+	Server server.Config `yaml:"server"`
+
+	// Labels optionally holds labels to associate with each record received on the OTLP receiver.
+	Labels model.LabelSet `yaml:"labels"`
+
+	// If promtail should maintain the incoming log timestamp or replace it with the current time.
+	KeepTimestamp bool `yaml:"use_incoming_timestamp"`
+
+	// OTLP configures which resource, scope and log attributes become labels or structured metadata,
+	// using the same rules as the Loki distributor OTLP endpoint.
+	OTLP push.OTLPConfig `yaml:"otlp_config"`
+}
+
+// ObjectStoreTargetConfig describes a scrape config that reads log files stored in an object store bucket.
+type ObjectStoreTargetConfig struct {
+	// Storage is the type of object store to read from: s3, gcs, azure or filesystem.
+	Storage string `yaml:"storage"`
+
+	// The client configurations of each object store type, same as the Loki storage_config.
+	S3         chunk_aws.S3Config            `yaml:"s3"`
+	GCS        chunk_gcp.GCSConfig           `yaml:"gcs"`
+	Azure      chunk_azure.BlobStorageConfig `yaml:"azure"`
+	Filesystem chunk_local.FSConfig          `yaml:"filesystem"`
+
+	// Prefix restricts the objects read to the ones whose key starts with it.
+	Prefix string `yaml:"prefix"`
+
+	// PollInterval is how often the bucket prefix is listed for new objects. Default to 1m.
+	PollInterval time.Duration `yaml:"poll_interval"`
+
+	// Compression is the compression format of the objects: gz, z, bz2, zst or none.
+	// When empty the format is guessed from the extension of each object key.
+	Compression string `yaml:"compression"`
+
+	// Preset parses well known log files to extract their timestamp and labels from their key.
+	// Available presets: elb, cloudfront and vpc_flow_logs.
+	Preset string `yaml:"preset"`
+
+	// Labels optionally holds labels to associate with each log line read from the bucket.
+	Labels model.LabelSet `yaml:"labels"`
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface.
+func (c *ObjectStoreTargetConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	// Use the default values of the Loki storage flags for the object store clients.
+	flagext.DefaultValues(&c.S3, &c.GCS, &c.Azure, &c.Filesystem)
+
+	type plain ObjectStoreTargetConfig
+	return unmarshal((*plain)(c))
+}
+
+// HTTPPollTargetConfig describes a scrape config that polls log entries from a paginated HTTP JSON API.
+type HTTPPollTargetConfig struct {
+	// URL is the address of the first page of entries.
+	URL string `yaml:"url"`
+
+	// Client holds the HTTP client options, such as authentication and TLS.
+	Client promconfig.HTTPClientConfig `yaml:",inline"`
+
+	// Headers are added to every request, for instance to authenticate with an API token.
+	Headers map[string]string `yaml:"headers"`
+
+	// PollInterval is how often the API is polled once all pages have been read. Default to 1m.
+	PollInterval time.Duration `yaml:"poll_interval"`
+
+	// EntriesPath is the JSONPath of the array of entries in the response body.
+	// When empty the response body itself must be the array.
+	EntriesPath string `yaml:"entries_path"`
+
+	// CursorPath is the JSONPath of the cursor of the next page in the response body,
+	// sent in the CursorParam query parameter of the next request.
+	CursorPath  string `yaml:"cursor_path"`
+	CursorParam string `yaml:"cursor_param"`
+
+	// NextLinkPath is the JSONPath of the URL of the next page in the response body.
+	NextLinkPath string `yaml:"next_link_path"`
+
+	// FollowLinkHeader reads the URL of the next page from the rel="next" Link response header.
+	FollowLinkHeader bool `yaml:"follow_link_header"`
+
+	// TimestampPath is the JSONPath of the timestamp in each entry. When empty, or when the
+	// timestamp can't be parsed, the current time is used.
+	TimestampPath string `yaml:"timestamp_path"`
+
+	// TimestampFormat is the Go layout of the timestamp, or one of Unix, UnixMs, UnixUs and UnixNs.
+	// Default to RFC3339.
+	TimestampFormat string `yaml:"timestamp_format"`
+
+	// Labels optionally holds labels to associate with each entry polled from the API.
+	Labels model.LabelSet `yaml:"labels"`
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface.
+func (c *HTTPPollTargetConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	c.Client = promconfig.DefaultHTTPClientConfig
+
+	type plain HTTPPollTargetConfig
+	if err := unmarshal((*plain)(c)); err != nil {
+		return err
+	}
+	// explicitly call Validate on HTTPClientConfig as it's UnmarshalYAML
+	// method doesn't get invoked given that it's not a pointer.
+	return c.Client.Validate()
+}
+
+// KubernetesEventsTargetConfig describes a scrape config that watches the Kubernetes Events API.
+type KubernetesEventsTargetConfig struct {
+	// APIServer is the address of the Kubernetes API server. When empty, and no kubeconfig file is set,
+	// Promtail is assumed to run inside the cluster and uses its pod service account.
+	APIServer promconfig.URL `yaml:"api_server,omitempty"`
+
+	// KubeConfig is the path of a kubeconfig file to connect to the API server.
+	KubeConfig string `yaml:"kubeconfig_file"`
+
+	// Client holds the HTTP client options used with the api_server, such as authentication and TLS.
+	Client promconfig.HTTPClientConfig `yaml:",inline"`
+
+	// Namespaces restricts the events watched to the ones of these namespaces. When empty, the events
+	// of all namespaces are watched.
+	Namespaces []string `yaml:"namespaces"`
+
+	// FieldSelector restricts the events watched, for instance to "type=Warning".
+	FieldSelector string `yaml:"field_selector"`
+
+	// Labels optionally holds labels to associate with each event.
+	Labels model.LabelSet `yaml:"labels"`
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface.
+func (c *KubernetesEventsTargetConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	c.Client = promconfig.DefaultHTTPClientConfig
+
+	type plain KubernetesEventsTargetConfig
+	if err := unmarshal((*plain)(c)); err != nil {
+		return err
+	}
+	if c.APIServer.URL != nil && c.KubeConfig != "" {
+		return fmt.Errorf("cannot use 'kubeconfig_file' and 'api_server' simultaneously")
+	}
+	if c.APIServer.URL == nil && !reflect.DeepEqual(c.Client, promconfig.DefaultHTTPClientConfig) {
+		return fmt.Errorf("to use custom HTTP client configuration please provide the 'api_server' URL explicitly")
+	}
+	// explicitly call Validate on HTTPClientConfig as it's UnmarshalYAML
+	// method doesn't get invoked given that it's not a pointer.
+	return c.Client.Validate()
+}
+
 // DefaultScrapeConfig is the default Config.
 var DefaultScrapeConfig = Config{
 	PipelineStages: stages.PipelineStages{},
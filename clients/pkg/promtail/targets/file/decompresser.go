@@ -16,6 +16,7 @@ import (
 
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
+	"github.com/klauspost/compress/zstd"
 	"github.com/pkg/errors"
 	"github.com/prometheus/common/model"
 	"go.uber.org/atomic"
@@ -36,6 +37,7 @@ func supportedCompressedFormats() map[string]struct{} {
 		".tar.gz": {},
 		".z":      {},
 		".bz2":    {},
+		".zst":    {},
 		// TODO: add support for .zip extension.
 	}
 }
@@ -107,35 +109,76 @@ func newDecompressor(metrics *Metrics, logger log.Logger, handler api.EntryHandl
 //
 // The selected reader implementation is based on the extension of the given file name.
 // It'll error if the extension isn't supported.
-func mountReader(f *os.File, logger log.Logger, format string) (reader io.Reader, err error) {
-	var decompressLib string
+func mountReader(f *os.File, logger log.Logger, format string) (reader io.ReadCloser, err error) {
+	reader, decompressLib, err := decompressionReader(f, format)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
 
+	if reader == nil {
+		return nil, fmt.Errorf("file %q has unsupported format, it has to be one of %q", f.Name(), supportedFormatsList())
+	}
+
+	level.Debug(logger).Log("msg", fmt.Sprintf("using %q to decompress file %q", decompressLib, f.Name()))
+	return reader, nil
+}
+
+// NewDecompressionReader returns a reader decompressing r with the given compression format.
+// It'll error if the format isn't supported. The reader has to be closed to release the
+// resources of the decompressor, closing it doesn't close r.
+func NewDecompressionReader(r io.Reader, format string) (io.ReadCloser, error) {
+	reader, _, err := decompressionReader(r, format)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	if reader == nil {
+		return nil, fmt.Errorf("unsupported compression format %q, it has to be one of %q", format, supportedFormatsList())
+	}
+	return reader, nil
+}
+
+// decompressionReader returns a reader decompressing r together with the name of the library used, or a nil reader
+// if the format isn't supported.
+func decompressionReader(r io.Reader, format string) (reader io.ReadCloser, decompressLib string, err error) {
 	switch format {
 	case "gz":
 		decompressLib = "compress/gzip"
-		reader, err = gzip.NewReader(f)
+		reader, err = gzip.NewReader(r)
 	case "z":
 		decompressLib = "compress/zlib"
-		reader, err = zlib.NewReader(f)
+		reader, err = zlib.NewReader(r)
 	case "bz2":
 		decompressLib = "bzip2"
-		reader = bzip2.NewReader(f)
+		reader = io.NopCloser(bzip2.NewReader(r))
+	case "zst":
+		decompressLib = "zstd"
+		var decoder *zstd.Decoder
+		// A single goroutine is enough to decompress a stream that is read sequentially.
+		decoder, err = zstd.NewReader(r, zstd.WithDecoderConcurrency(1))
+		if err == nil {
+			reader = zstdReader{decoder}
+		}
 	}
+	return reader, decompressLib, err
+}
 
-	if err != nil && err != io.EOF {
-		return nil, err
-	}
+// zstdReader closes the zstd decoder, releasing its goroutines and buffers, when the reader is closed.
+type zstdReader struct {
+	*zstd.Decoder
+}
 
-	if reader == nil {
-		supportedFormatsList := strings.Builder{}
-		for format := range supportedCompressedFormats() {
-			supportedFormatsList.WriteString(format)
-		}
-		return nil, fmt.Errorf("file %q has unsupported format, it has to be one of %q", f.Name(), supportedFormatsList.String())
-	}
+func (r zstdReader) Close() error {
+	r.Decoder.Close()
+	return nil
+}
 
-	level.Debug(logger).Log("msg", fmt.Sprintf("using %q to decompress file %q", decompressLib, f.Name()))
-	return reader, nil
+func supportedFormatsList() string {
+	supportedFormatsList := strings.Builder{}
+	for format := range supportedCompressedFormats() {
+		supportedFormatsList.WriteString(format)
+	}
+	return supportedFormatsList.String()
 }
 
 func (t *decompressor) updatePosition() {
@@ -193,6 +236,7 @@ func (t *decompressor) readLines() {
 		level.Error(t.logger).Log("msg", "error mounting new reader", "err", err)
 		return
 	}
+	defer r.Close()
 
 	level.Info(t.logger).Log("msg", "successfully mounted reader", "path", t.path, "ext", filepath.Ext(t.path))
 
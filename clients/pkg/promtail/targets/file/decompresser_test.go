@@ -1,12 +1,15 @@
 package file
 
 import (
+	"bytes"
+	"io"
 	"os"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/go-kit/log"
+	"github.com/klauspost/compress/zstd"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/atomic"
@@ -183,3 +186,25 @@ func TestOnelineFiles(t *testing.T) {
 		require.Contains(t, firstEntry.Line, `5.202.214.160 - - [26/Jan/2019:19:45:25 +0330] "GET / HTTP/1.1" 200 30975 "https://www.zanbil.ir/" "Mozilla/5.0 (Windows NT 6.2; WOW64; rv:21.0) Gecko/20100101 Firefox/21.0" "-"`)
 	})
 }
+
+func TestNewDecompressionReader(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := zstd.NewWriter(&buf)
+	require.NoError(t, err)
+	_, err = w.Write([]byte("line1\nline2\n"))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	r, err := NewDecompressionReader(&buf, "zst")
+	require.NoError(t, err)
+	b, err := io.ReadAll(r)
+	require.NoError(t, err)
+	require.Equal(t, "line1\nline2\n", string(b))
+	require.NoError(t, r.Close())
+	// The decoder is released once closed.
+	_, err = r.Read(make([]byte, 1))
+	require.ErrorIs(t, err, zstd.ErrDecoderClosed)
+
+	_, err = NewDecompressionReader(&buf, "zip")
+	require.Error(t, err)
+}
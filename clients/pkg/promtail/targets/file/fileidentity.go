@@ -0,0 +1,73 @@
+package file
+
+import (
+	"errors"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/cespare/xxhash/v2"
+
+	"github.com/grafana/loki/clients/pkg/promtail/positions"
+)
+
+// fingerprintSize is the number of bytes at the beginning of a file hashed to fingerprint it.
+const fingerprintSize = 1024
+
+// fileHead holds the device and inode of a file along with its first bytes, which together
+// identify the file independently of its path.
+type fileHead struct {
+	device uint64
+	inode  uint64
+	head   []byte
+}
+
+// readFileHead reads the device, inode and first fingerprintSize bytes of the file at path.
+func readFileHead(path string) (fileHead, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return fileHead{}, err
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return fileHead{}, err
+	}
+	device, inode := fileID(fi)
+
+	buf := make([]byte, fingerprintSize)
+	n, err := io.ReadFull(f, buf)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return fileHead{}, err
+	}
+	return fileHead{device: device, inode: inode, head: buf[:n]}, nil
+}
+
+// identity returns the identity of the file, fingerprinted with all the bytes read.
+func (h fileHead) identity() positions.FileIdentity {
+	return positions.FileIdentity{
+		Device:          h.device,
+		Inode:           h.inode,
+		Fingerprint:     fingerprint(h.head),
+		FingerprintSize: int64(len(h.head)),
+	}
+}
+
+// sameInode returns true if the identity has the same device and inode as the file.
+func (h fileHead) sameInode(id positions.FileIdentity) bool {
+	return h.device == id.Device && h.inode == id.Inode
+}
+
+// matches returns true if the file is the one with the given identity: it has the same
+// device and inode, and still starts with the bytes it had when the identity was recorded.
+func (h fileHead) matches(id positions.FileIdentity) bool {
+	if !h.sameInode(id) || int64(len(h.head)) < id.FingerprintSize {
+		return false
+	}
+	return fingerprint(h.head[:id.FingerprintSize]) == id.Fingerprint
+}
+
+func fingerprint(b []byte) string {
+	return strconv.FormatUint(xxhash.Sum64(b), 16)
+}
@@ -0,0 +1,19 @@
+//go:build !windows
+// +build !windows
+
+package file
+
+import (
+	"os"
+	"syscall"
+)
+
+// fileID returns the device and inode of a file.
+func fileID(fi os.FileInfo) (uint64, uint64) {
+	st, ok := fi.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, 0
+	}
+	// The type of the device differs between platforms.
+	return uint64(st.Dev), st.Ino //nolint:unconvert
+}
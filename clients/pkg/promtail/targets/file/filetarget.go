@@ -404,11 +404,14 @@ func (t *FileTarget) stopTailingAndRemovePosition(ps []string) {
 }
 
 // pruneStoppedTailers removes any tailers which have stopped running from
-// the list of active tailers. This allows them to be restarted if there were errors.
+// the list of active tailers. This allows them to be restarted if there were errors,
+// or if the file they were reading was renamed.
 func (t *FileTarget) pruneStoppedTailers() {
 	toRemove := make([]string, 0, len(t.readers))
-	for k, t := range t.readers {
-		if !t.IsRunning() {
+	for k, r := range t.readers {
+		if !r.IsRunning() {
+			// Release the handler of the stopped reader.
+			r.Stop()
 			toRemove = append(toRemove, k)
 		}
 	}
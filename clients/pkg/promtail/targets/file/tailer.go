@@ -36,6 +36,10 @@ type tailer struct {
 	posAndSizeMtx sync.Mutex
 	stopOnce      sync.Once
 
+	// identity of the file being read and last position recorded for it, guarded by posAndSizeMtx.
+	identity positions.FileIdentity
+	lastPos  int64
+
 	running *atomic.Bool
 	posquit chan struct{}
 	posdone chan struct{}
@@ -45,25 +49,25 @@ type tailer struct {
 }
 
 func newTailer(metrics *Metrics, logger log.Logger, handler api.EntryHandler, positions positions.Positions, pollOptions watch.PollingFileWatcherOptions, path string, encoding string) (*tailer, error) {
-	// Simple check to make sure the file we are tailing doesn't
-	// have a position already saved which is past the end of the file.
 	fi, err := os.Stat(path)
 	if err != nil {
 		return nil, err
 	}
-	pos, err := positions.Get(path)
+	head, err := readFileHead(path)
 	if err != nil {
 		return nil, err
 	}
-
-	if fi.Size() < pos {
-		positions.Remove(path)
+	pos, err := startPosition(logger, positions, path, head, fi.Size())
+	if err != nil {
+		return nil, err
 	}
 
+	// The tail stops once a renamed or deleted file has been fully read, instead of reopening
+	// the path: the new file is tailed by a new tailer, with its own identity.
 	tail, err := tail.TailFile(path, tail.Config{
 		Follow:    true,
 		Poll:      true,
-		ReOpen:    true,
+		ReOpen:    false,
 		MustExist: true,
 		Location: &tail.SeekInfo{
 			Offset: pos,
@@ -84,6 +88,8 @@ func newTailer(metrics *Metrics, logger log.Logger, handler api.EntryHandler, po
 		positions: positions,
 		path:      path,
 		tail:      tail,
+		identity:  head.identity(),
+		lastPos:   pos,
 		running:   atomic.NewBool(false),
 		posquit:   make(chan struct{}),
 		posdone:   make(chan struct{}),
@@ -106,6 +112,61 @@ func newTailer(metrics *Metrics, logger log.Logger, handler api.EntryHandler, po
 	return tailer, nil
 }
 
+// startPosition returns the offset to start reading the file at path from.
+//
+// The position recorded for the path is only used if it was recorded for the same file, positions recorded
+// without a file identity by older versions are trusted as before. A file without position which was renamed
+// from another path resumes from the position of that path. Truncated files are read from the start.
+func startPosition(logger log.Logger, ps positions.Positions, path string, head fileHead, size int64) (int64, error) {
+	pos, err := ps.Get(path)
+	if err != nil {
+		return 0, err
+	}
+
+	id, ok := ps.GetFileIdentity(path)
+	switch {
+	case ok && head.matches(id):
+		// Same file as the one the position was recorded for.
+	case ok && head.sameInode(id):
+		level.Info(logger).Log("msg", "file content changed since its position was recorded, reading it from the start", "path", path)
+		pos = 0
+	case ok:
+		level.Info(logger).Log("msg", "file was replaced since its position was recorded", "path", path)
+		if pos, err = renamedPosition(logger, ps, path, head); err != nil {
+			return 0, err
+		}
+	case pos == 0:
+		if pos, err = renamedPosition(logger, ps, path, head); err != nil {
+			return 0, err
+		}
+	}
+
+	// Make sure the position isn't past the end of the file.
+	if size < pos {
+		level.Info(logger).Log("msg", "file was truncated since its position was recorded, reading it from the start", "path", path)
+		pos = 0
+	}
+	return pos, nil
+}
+
+// renamedPosition returns the position recorded for the file at path under the path it was renamed from, or 0.
+func renamedPosition(logger log.Logger, ps positions.Positions, path string, head fileHead) (int64, error) {
+	for oldPath, id := range ps.FileIdentities() {
+		// Shorter fingerprints can't tell apart a renamed file from a new file with the same content
+		// reusing the inode of a deleted file.
+		if oldPath == path || id.FingerprintSize < fingerprintSize || !head.matches(id) {
+			continue
+		}
+		pos, err := ps.Get(oldPath)
+		if err != nil {
+			return 0, err
+		}
+		level.Info(logger).Log("msg", "file was renamed, resuming from the position of its previous path", "path", path, "previous_path", oldPath, "position", pos)
+		return pos, nil
+	}
+	return 0, nil
+}
+
 // updatePosition is run in a goroutine and checks the current size of the file and saves it to the positions file
 // at a regular interval. If there is ever an error it stops the tailer and exits, the tailer will be re-opened
 // by the filetarget sync method if it still exists and will start reading from the last successful entry in the
@@ -215,10 +276,30 @@ func (t *tailer) MarkPositionAndSize() error {
 		return err
 	}
 
+	head, err := readFileHead(t.path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	switch {
+	case err != nil || !head.sameInode(t.identity):
+		// The file was renamed or deleted, and is being read until its end. Its position is still
+		// recorded with its identity, so that it can be resumed under its new name.
+	case size < pos:
+		// The file was truncated, the tail reopens it and reads it again from the start.
+		return nil
+	case !head.matches(t.identity) && pos >= t.lastPos:
+		// The content of the file was replaced without the tail noticing it was truncated.
+		return fmt.Errorf("content of file %s changed while reading it", t.path)
+	default:
+		// The fingerprint covers more bytes as the file grows, and changes after the file is truncated.
+		t.identity = head.identity()
+	}
+	t.lastPos = pos
+
 	// Update metrics and positions file all together to avoid race conditions when `t.tail` is stopped.
 	t.metrics.totalBytes.WithLabelValues(t.path).Set(float64(size))
 	t.metrics.readBytes.WithLabelValues(t.path).Set(float64(pos))
-	t.positions.Put(t.path, pos)
+	t.positions.PutFile(t.path, pos, t.identity)
 
 	return nil
 }
@@ -0,0 +1,182 @@
+package file
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/tail/watch"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/clients/pkg/promtail/client/fake"
+	"github.com/grafana/loki/clients/pkg/promtail/positions"
+)
+
+// logLines returns n log lines of 16 bytes.
+func logLines(prefix string, n int) string {
+	var sb strings.Builder
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&sb, "%-10s %04d\n", prefix, i)
+	}
+	return sb.String()
+}
+
+func TestStartPosition(t *testing.T) {
+	dir := t.TempDir()
+	logFile := filepath.Join(dir, "test.log")
+	content := logLines("line", 100)
+	require.NoError(t, os.WriteFile(logFile, []byte(content), 0o644))
+	head, err := readFileHead(logFile)
+	require.NoError(t, err)
+	require.Equal(t, int64(fingerprintSize), head.identity().FingerprintSize)
+	other := positions.FileIdentity{Device: head.device, Inode: head.inode + 1, Fingerprint: fingerprint([]byte(content[:16])), FingerprintSize: 16}
+	small := positions.FileIdentity{Device: head.device, Inode: head.inode, Fingerprint: fingerprint([]byte(content[:16])), FingerprintSize: 16}
+
+	for _, tc := range []struct {
+		name     string
+		setup    func(ps positions.Positions)
+		expected int64
+	}{
+		{
+			name:     "no position",
+			setup:    func(ps positions.Positions) {},
+			expected: 0,
+		},
+		{
+			name: "position without identity",
+			setup: func(ps positions.Positions) {
+				ps.Put(logFile, 6)
+			},
+			expected: 6,
+		},
+		{
+			name: "same file",
+			setup: func(ps positions.Positions) {
+				ps.PutFile(logFile, 16, small)
+			},
+			expected: 16,
+		},
+		{
+			name: "file replaced",
+			setup: func(ps positions.Positions) {
+				ps.PutFile(logFile, 6, other)
+			},
+			expected: 0,
+		},
+		{
+			name: "file content changed",
+			setup: func(ps positions.Positions) {
+				ps.PutFile(logFile, 16, positions.FileIdentity{Device: head.device, Inode: head.inode, Fingerprint: fingerprint([]byte(logLines("other", 1))), FingerprintSize: 16})
+			},
+			expected: 0,
+		},
+		{
+			name: "file truncated",
+			setup: func(ps positions.Positions) {
+				ps.PutFile(logFile, 2000, head.identity())
+			},
+			expected: 0,
+		},
+		{
+			name: "file renamed",
+			setup: func(ps positions.Positions) {
+				ps.PutFile(filepath.Join(dir, "other.log"), 2, other)
+				ps.PutFile(filepath.Join(dir, "test.log.1"), 1200, head.identity())
+			},
+			expected: 1200,
+		},
+		{
+			name: "file renamed over another file",
+			setup: func(ps positions.Positions) {
+				ps.PutFile(logFile, 2, other)
+				ps.PutFile(filepath.Join(dir, "test.log.1"), 1200, head.identity())
+			},
+			expected: 1200,
+		},
+		{
+			name: "file renamed with a short fingerprint",
+			setup: func(ps positions.Positions) {
+				// It can't be told apart from a new file reusing the inode of a deleted file.
+				ps.PutFile(filepath.Join(dir, "test.log.1"), 16, small)
+			},
+			expected: 0,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ps, err := positions.New(log.NewNopLogger(), positions.Config{
+				SyncPeriod:    10 * time.Second,
+				PositionsFile: filepath.Join(t.TempDir(), "positions.yml"),
+			})
+			require.NoError(t, err)
+			defer ps.Stop()
+
+			tc.setup(ps)
+			pos, err := startPosition(log.NewNopLogger(), ps, logFile, head, int64(len(content)))
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, pos)
+		})
+	}
+}
+
+func TestTailerFollowsRenamedFile(t *testing.T) {
+	var (
+		logger   = log.NewNopLogger()
+		dir      = t.TempDir()
+		logFile  = filepath.Join(dir, "test.log")
+		rotated  = filepath.Join(dir, "test.log.1")
+		metrics  = NewMetrics(nil)
+		pollOpts = watch.PollingFileWatcherOptions{MinPollFrequency: 10 * time.Millisecond, MaxPollFrequency: 10 * time.Millisecond}
+	)
+	ps, err := positions.New(logger, positions.Config{
+		SyncPeriod:    10 * time.Second,
+		PositionsFile: filepath.Join(dir, "positions.yml"),
+	})
+	require.NoError(t, err)
+	defer ps.Stop()
+
+	require.NoError(t, os.WriteFile(logFile, []byte(logLines("line", 100)), 0o644))
+	client := fake.New(func() {})
+	defer client.Stop()
+	tailer, err := newTailer(metrics, logger, client, ps, pollOpts, logFile, "")
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		return len(client.Received()) == 100
+	}, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, tailer.MarkPositionAndSize())
+
+	// Rotate the file: the tailer stops once the renamed file is read.
+	require.NoError(t, os.Rename(logFile, rotated))
+	require.NoError(t, os.WriteFile(logFile, []byte("new line\n"), 0o644))
+	require.Eventually(t, func() bool {
+		return !tailer.IsRunning()
+	}, 5*time.Second, 10*time.Millisecond)
+	tailer.Stop()
+
+	// The renamed file is resumed from its position, the new file is read from the start.
+	rotatedTailer, err := newTailer(metrics, logger, client, ps, pollOpts, rotated, "")
+	require.NoError(t, err)
+	defer rotatedTailer.Stop()
+	currentTailer, err := newTailer(metrics, logger, client, ps, pollOpts, logFile, "")
+	require.NoError(t, err)
+	defer currentTailer.Stop()
+
+	f, err := os.OpenFile(rotated, os.O_APPEND|os.O_WRONLY, 0o644)
+	require.NoError(t, err)
+	_, err = f.WriteString("last line\n")
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	require.Eventually(t, func() bool {
+		return len(client.Received()) == 102
+	}, 5*time.Second, 10*time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+	var lines []string
+	for _, e := range client.Received()[100:] {
+		lines = append(lines, e.Line)
+	}
+	require.ElementsMatch(t, []string{"last line", "new line"}, lines)
+}
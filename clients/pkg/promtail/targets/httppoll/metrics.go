@@ -0,0 +1,45 @@
+package httppoll
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// Metrics holds a set of HTTP poll target metrics.
+type Metrics struct {
+	reg prometheus.Registerer
+
+	Entries  *prometheus.CounterVec
+	Requests *prometheus.CounterVec
+	Errors   *prometheus.CounterVec
+}
+
+// NewMetrics creates a new set of HTTP poll target metrics. If reg is non-nil, the
+// metrics will be registered.
+func NewMetrics(reg prometheus.Registerer) *Metrics {
+	var m Metrics
+	m.reg = reg
+
+	m.Entries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "promtail",
+		Name:      "http_poll_target_entries_total",
+		Help:      "Total number of entries read from API responses by the HTTP poll target.",
+	}, []string{"job"})
+	m.Requests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "promtail",
+		Name:      "http_poll_target_requests_total",
+		Help:      "Total number of pages requested by the HTTP poll target.",
+	}, []string{"job"})
+	m.Errors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "promtail",
+		Name:      "http_poll_target_errors_total",
+		Help:      "Total number of errors requesting or parsing pages in the HTTP poll target.",
+	}, []string{"job"})
+
+	if reg != nil {
+		reg.MustRegister(
+			m.Entries,
+			m.Requests,
+			m.Errors,
+		)
+	}
+
+	return &m
+}
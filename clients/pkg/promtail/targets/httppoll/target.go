@@ -0,0 +1,529 @@
+package httppoll
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/grafana/jsonparser"
+	promconfig "github.com/prometheus/common/config"
+	"github.com/prometheus/common/model"
+	"go.uber.org/atomic"
+
+	"github.com/grafana/loki/clients/pkg/promtail/api"
+	"github.com/grafana/loki/clients/pkg/promtail/positions"
+	"github.com/grafana/loki/clients/pkg/promtail/scrapeconfig"
+	"github.com/grafana/loki/clients/pkg/promtail/targets/target"
+
+	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/logql/log/jsonexpr"
+)
+
+const (
+	defaultPollInterval    = time.Minute
+	defaultTimestampFormat = time.RFC3339
+
+	positionKeyPrefix = "http_poll-"
+	// maxErrorBodySize is the size of the response body logged when a request fails.
+	maxErrorBodySize = 1024
+)
+
+// Target polls a paginated HTTP JSON API, sending each entry of the pages read as a log line.
+//
+// The position of the target is the cursor of the page being read and the number of its entries
+// already sent. Once the last page is reached, it is polled again every poll interval for new entries.
+type Target struct {
+	metrics   *Metrics
+	logger    log.Logger
+	handler   api.EntryHandler
+	positions positions.Positions
+	config    *scrapeconfig.HTTPPollTargetConfig
+	jobName   string
+
+	entriesPath   []string
+	cursorPath    []string
+	nextLinkPath  []string
+	timestampPath []string
+
+	client  *http.Client
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+	running *atomic.Bool
+
+	mtx sync.Mutex
+	err error
+}
+
+// NewTarget creates a new HTTP poll target, resuming from the cursor recorded in the positions file.
+func NewTarget(
+	metrics *Metrics,
+	logger log.Logger,
+	handler api.EntryHandler,
+	position positions.Positions,
+	jobName string,
+	config *scrapeconfig.HTTPPollTargetConfig,
+) (*Target, error) {
+	if err := validateConfig(config); err != nil {
+		return nil, err
+	}
+	client, err := promconfig.NewClientFromConfig(config.Client, "promtail")
+	if err != nil {
+		return nil, err
+	}
+
+	t := &Target{
+		metrics:   metrics,
+		logger:    logger,
+		handler:   handler,
+		positions: position,
+		config:    config,
+		jobName:   jobName,
+
+		client:  client,
+		running: atomic.NewBool(false),
+	}
+	for _, p := range []struct {
+		expr string
+		path *[]string
+	}{
+		{config.EntriesPath, &t.entriesPath},
+		{config.CursorPath, &t.cursorPath},
+		{config.NextLinkPath, &t.nextLinkPath},
+		{config.TimestampPath, &t.timestampPath},
+	} {
+		if *p.path, err = parsePath(p.expr); err != nil {
+			return nil, err
+		}
+	}
+
+	t.ctx, t.cancel = context.WithCancel(context.Background())
+	t.start()
+	return t, nil
+}
+
+func validateConfig(cfg *scrapeconfig.HTTPPollTargetConfig) error {
+	if cfg.URL == "" {
+		return errors.New("http_poll url is required")
+	}
+	if _, err := url.Parse(cfg.URL); err != nil {
+		return fmt.Errorf("invalid http_poll url: %w", err)
+	}
+	pagination := 0
+	for _, set := range []bool{cfg.CursorPath != "", cfg.NextLinkPath != "", cfg.FollowLinkHeader} {
+		if set {
+			pagination++
+		}
+	}
+	if pagination > 1 {
+		return errors.New("only one of cursor_path, next_link_path and follow_link_header can be set")
+	}
+	if cfg.CursorPath != "" && cfg.CursorParam == "" {
+		return errors.New("cursor_param is required when cursor_path is set")
+	}
+	if cfg.PollInterval == 0 {
+		cfg.PollInterval = defaultPollInterval
+	}
+	if cfg.TimestampFormat == "" {
+		cfg.TimestampFormat = defaultTimestampFormat
+	}
+	return nil
+}
+
+// parsePath parses a JSONPath into the keys used by jsonparser. An empty path, or "$", is the root of the document.
+func parsePath(expr string) ([]string, error) {
+	expr = strings.TrimPrefix(strings.TrimPrefix(expr, "$"), ".")
+	if expr == "" {
+		return nil, nil
+	}
+	path, err := jsonexpr.Parse(expr, false)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse JSONPath [%s]: %w", expr, err)
+	}
+	keys := make([]string, 0, len(path))
+	for _, p := range path {
+		switch v := p.(type) {
+		case int:
+			keys = append(keys, fmt.Sprintf("[%d]", v))
+		case string:
+			keys = append(keys, v)
+		}
+	}
+	return keys, nil
+}
+
+func (t *Target) start() {
+	t.wg.Add(1)
+	t.running.Store(true)
+	go func() {
+		defer func() {
+			t.wg.Done()
+			t.running.Store(false)
+		}()
+
+		for t.ctx.Err() == nil {
+			more, err := t.poll()
+			if t.ctx.Err() != nil {
+				return
+			}
+			t.setErr(err)
+			if err != nil {
+				level.Error(t.logger).Log("msg", "failed to poll entries", "url", t.config.URL, "err", err)
+			}
+			// Read the next page right away, wait for new entries otherwise.
+			if more && err == nil {
+				continue
+			}
+			select {
+			case <-time.After(t.config.PollInterval):
+			case <-t.ctx.Done():
+			}
+		}
+	}()
+}
+
+// poll reads the page at the current position, returning true if there is a next page to read.
+func (t *Target) poll() (bool, error) {
+	cursor, offset, err := t.position()
+	if err != nil {
+		level.Warn(t.logger).Log("msg", "invalid position, polling from the first page", "err", err)
+		cursor, offset = "", 0
+	}
+	reqURL, err := t.pageURL(cursor)
+	if err != nil {
+		return false, err
+	}
+
+	body, header, err := t.get(reqURL)
+	if err != nil {
+		return false, err
+	}
+	entries, err := t.entries(body)
+	if err != nil {
+		return false, err
+	}
+	next, err := t.next(body, header, reqURL)
+	if err != nil {
+		return false, err
+	}
+
+	for i := offset; i < len(entries); i++ {
+		if !t.send(entries[i]) {
+			return false, nil
+		}
+		t.setPosition(cursor, i+1)
+	}
+	if t.ctx.Err() != nil {
+		return false, nil
+	}
+	if next != "" && next != cursor {
+		t.setPosition(next, 0)
+		return true, nil
+	}
+	// The last page is read again at the next poll, skipping the entries already sent.
+	if offset < len(entries) {
+		t.setPosition(cursor, len(entries))
+	}
+	return false, nil
+}
+
+// pageURL returns the URL of the page for the given cursor, the configured URL being the first page.
+func (t *Target) pageURL(cursor string) (*url.URL, error) {
+	if cursor != "" && t.config.CursorPath == "" {
+		// Next links are recorded as the cursor.
+		return url.Parse(cursor)
+	}
+	u, err := url.Parse(t.config.URL)
+	if err != nil {
+		return nil, err
+	}
+	if cursor != "" {
+		q := u.Query()
+		q.Set(t.config.CursorParam, cursor)
+		u.RawQuery = q.Encode()
+	}
+	return u, nil
+}
+
+func (t *Target) get(u *url.URL) ([]byte, http.Header, error) {
+	req, err := http.NewRequestWithContext(t.ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	for k, v := range t.config.Headers {
+		req.Header.Set(k, v)
+	}
+
+	t.metrics.Requests.WithLabelValues(t.jobName).Inc()
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+		return nil, nil, fmt.Errorf("server returned HTTP status %s: %s", resp.Status, body)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+	return body, resp.Header, nil
+}
+
+// entries returns the entries of the array found at the entries path of the body. Object entries
+// are returned as is, string entries are unquoted. A missing array has no entries.
+func (t *Target) entries(body []byte) ([][]byte, error) {
+	var (
+		entries [][]byte
+		errs    []error
+	)
+	_, err := jsonparser.ArrayEach(body, func(value []byte, typ jsonparser.ValueType, _ int, err error) {
+		if err == nil && typ == jsonparser.String {
+			var s string
+			s, err = jsonparser.ParseString(value)
+			value = []byte(s)
+		}
+		if err != nil {
+			errs = append(errs, err)
+			return
+		}
+		entries = append(entries, value)
+	}, t.entriesPath...)
+	if errors.Is(err, jsonparser.KeyPathNotFoundError) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read entries at %q: %w", t.config.EntriesPath, err)
+	}
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("failed to read entries at %q: %w", t.config.EntriesPath, errors.Join(errs...))
+	}
+	return entries, nil
+}
+
+// next returns the cursor of the next page, or an empty string if there is none.
+func (t *Target) next(body []byte, header http.Header, reqURL *url.URL) (string, error) {
+	var link string
+	switch {
+	case t.config.CursorPath != "":
+		return stringValue(body, t.cursorPath)
+	case t.config.NextLinkPath != "":
+		var err error
+		if link, err = stringValue(body, t.nextLinkPath); err != nil {
+			return "", err
+		}
+	case t.config.FollowLinkHeader:
+		link = nextLink(header.Values("Link"))
+	}
+	if link == "" {
+		return "", nil
+	}
+	// Links can be relative to the page requested.
+	u, err := reqURL.Parse(link)
+	if err != nil {
+		return "", fmt.Errorf("invalid next link %q: %w", link, err)
+	}
+	return u.String(), nil
+}
+
+// stringValue returns the string or number at the path of a JSON document, or an empty string if there is none.
+func stringValue(data []byte, path []string) (string, error) {
+	value, typ, _, err := jsonparser.Get(data, path...)
+	if err != nil {
+		if errors.Is(err, jsonparser.KeyPathNotFoundError) {
+			return "", nil
+		}
+		return "", err
+	}
+	switch typ {
+	case jsonparser.String:
+		return jsonparser.ParseString(value)
+	case jsonparser.Number:
+		return string(value), nil
+	case jsonparser.Null:
+		return "", nil
+	default:
+		return "", fmt.Errorf("unexpected %s value %s", typ, value)
+	}
+}
+
+// nextLink returns the URL of the rel="next" link of Link headers, as described by RFC 8288.
+func nextLink(headers []string) string {
+	for _, header := range headers {
+		for _, link := range strings.Split(header, ",") {
+			parts := strings.Split(link, ";")
+			target := strings.TrimSpace(parts[0])
+			if !strings.HasPrefix(target, "<") || !strings.HasSuffix(target, ">") {
+				continue
+			}
+			for _, param := range parts[1:] {
+				name, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+				if !strings.EqualFold(name, "rel") {
+					continue
+				}
+				for _, rel := range strings.Fields(strings.Trim(value, `"`)) {
+					if strings.EqualFold(rel, "next") {
+						return strings.TrimSuffix(strings.TrimPrefix(target, "<"), ">")
+					}
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// send sends an entry to the pipeline, with the timestamp found at the timestamp path if any.
+// It returns false if the target was stopped before the entry could be sent.
+func (t *Target) send(entry []byte) bool {
+	ts := time.Now()
+	if t.config.TimestampPath != "" {
+		parsed, err := t.timestamp(entry)
+		if err != nil {
+			level.Debug(t.logger).Log("msg", "failed to parse timestamp, using current time", "err", err)
+		} else {
+			ts = parsed
+		}
+	}
+	e := api.Entry{
+		Labels: t.config.Labels.Clone(),
+		Entry: logproto.Entry{
+			Timestamp: ts,
+			Line:      string(entry),
+		},
+	}
+	select {
+	case t.handler.Chan() <- e:
+	case <-t.ctx.Done():
+		return false
+	}
+	t.metrics.Entries.WithLabelValues(t.jobName).Inc()
+	return true
+}
+
+func (t *Target) timestamp(entry []byte) (time.Time, error) {
+	value, err := stringValue(entry, t.timestampPath)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if value == "" {
+		return time.Time{}, fmt.Errorf("no timestamp at %q", t.config.TimestampPath)
+	}
+	return parseTimestamp(t.config.TimestampFormat, value)
+}
+
+// parseTimestamp parses a timestamp with a Go layout, or as a Unix epoch in the unit of the format.
+func parseTimestamp(format, value string) (time.Time, error) {
+	switch format {
+	case "Unix":
+		seconds, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return time.Unix(0, int64(seconds*float64(time.Second))), nil
+	case "UnixMs", "UnixUs", "UnixNs":
+		epoch, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return time.Time{}, err
+		}
+		switch format {
+		case "UnixMs":
+			return time.UnixMilli(epoch), nil
+		case "UnixUs":
+			return time.UnixMicro(epoch), nil
+		default:
+			return time.Unix(0, epoch), nil
+		}
+	default:
+		return time.Parse(format, value)
+	}
+}
+
+func (t *Target) positionKey() string {
+	return positions.CursorKey(positionKeyPrefix + t.jobName)
+}
+
+// position returns the cursor of the page being read and the number of its entries already sent.
+func (t *Target) position() (string, int, error) {
+	pos := t.positions.GetString(t.positionKey())
+	if pos == "" {
+		return "", 0, nil
+	}
+	offset, cursor, ok := strings.Cut(pos, ":")
+	if !ok {
+		return "", 0, fmt.Errorf("invalid position %q", pos)
+	}
+	n, err := strconv.Atoi(offset)
+	if err != nil {
+		return "", 0, err
+	}
+	return cursor, n, nil
+}
+
+func (t *Target) setPosition(cursor string, offset int) {
+	t.positions.PutString(t.positionKey(), fmt.Sprintf("%d:%s", offset, cursor))
+}
+
+func (t *Target) setErr(err error) {
+	if err != nil {
+		t.metrics.Errors.WithLabelValues(t.jobName).Inc()
+	}
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	t.err = err
+}
+
+// Stop stops the target, interrupting the request in flight.
+func (t *Target) Stop() {
+	t.cancel()
+	t.wg.Wait()
+	t.handler.Stop()
+}
+
+// Type returns HTTPPollTargetType.
+func (t *Target) Type() target.TargetType {
+	return target.HTTPPollTargetType
+}
+
+// DiscoveredLabels returns the set of labels discovered by the HTTP poll target, which
+// is always nil. Implements Target.
+func (t *Target) DiscoveredLabels() model.LabelSet {
+	return nil
+}
+
+// Labels returns the set of labels that statically apply to all log entries
+// produced by the HTTP poll target.
+func (t *Target) Labels() model.LabelSet {
+	return t.config.Labels
+}
+
+// Ready indicates whether or not the HTTP poll target is polling its API.
+func (t *Target) Ready() bool {
+	return t.running.Load()
+}
+
+// Details returns target-specific details.
+func (t *Target) Details() interface{} {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	var errMsg string
+	if t.err != nil {
+		errMsg = t.err.Error()
+	}
+	return map[string]string{
+		"url":      t.config.URL,
+		"position": t.positions.GetString(t.positionKey()),
+		"error":    errMsg,
+	}
+}
@@ -0,0 +1,263 @@
+package httppoll
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/clients/pkg/promtail/api"
+	"github.com/grafana/loki/clients/pkg/promtail/client/fake"
+	"github.com/grafana/loki/clients/pkg/promtail/scrapeconfig"
+	"github.com/grafana/loki/clients/pkg/promtail/targets/testutils"
+)
+
+// cursorAPI serves pages of events paginated with a cursor query parameter,
+// the last page growing as events are appended.
+type cursorAPI struct {
+	mtx    sync.Mutex
+	pages  [][]string
+	header http.Header
+}
+
+func (a *cursorAPI) append(events ...string) {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+	a.pages[len(a.pages)-1] = append(a.pages[len(a.pages)-1], events...)
+}
+
+func (a *cursorAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+	a.header = r.Header.Clone()
+
+	page := 0
+	if c := r.URL.Query().Get("after"); c != "" {
+		_, _ = fmt.Sscanf(c, "page-%d", &page)
+	}
+	var next string
+	if page+1 < len(a.pages) {
+		next = fmt.Sprintf(`"page-%d"`, page+1)
+	} else {
+		next = "null"
+	}
+	events := ""
+	for i, e := range a.pages[page] {
+		if i > 0 {
+			events += ","
+		}
+		events += fmt.Sprintf(`{"published":%q,"message":%q}`, "2023-01-0"+e[len(e)-1:]+"T00:00:00Z", e)
+	}
+	fmt.Fprintf(w, `{"data":{"events":[%s]},"meta":{"next":%s}}`, events, next)
+}
+
+func Test_HTTPPollTargetCursor(t *testing.T) {
+	var (
+		w             = log.NewSyncWriter(os.Stderr)
+		logger        = log.NewLogfmtLogger(w)
+		positionsFile = filepath.Join(t.TempDir(), "positions.yml")
+		metrics       = NewMetrics(prometheus.NewRegistry())
+		api           = &cursorAPI{pages: [][]string{{"e1", "e2"}, {"e3"}}}
+	)
+	srv := httptest.NewServer(api)
+	defer srv.Close()
+
+	cfg := &scrapeconfig.HTTPPollTargetConfig{
+		URL:           srv.URL + "/events?limit=2",
+		Headers:       map[string]string{"Authorization": "SSWS token"},
+		PollInterval:  10 * time.Millisecond,
+		EntriesPath:   "data.events",
+		CursorPath:    "$.meta.next",
+		CursorParam:   "after",
+		TimestampPath: "published",
+		Labels:        model.LabelSet{"job": "audit"},
+	}
+
+	ps := testutils.NewPositions(t, logger, positionsFile)
+	client := fake.New(func() {})
+	ta, err := NewTarget(metrics, logger, client, ps, "job1", cfg)
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		return len(client.Received()) == 3
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Equal(t, []string{
+		`{"published":"2023-01-01T00:00:00Z","message":"e1"}`,
+		`{"published":"2023-01-02T00:00:00Z","message":"e2"}`,
+		`{"published":"2023-01-03T00:00:00Z","message":"e3"}`,
+	}, testutils.ReceivedLines(client))
+	for i, e := range client.Received() {
+		require.Equal(t, model.LabelSet{"job": "audit"}, e.Labels)
+		require.Equal(t, time.Date(2023, 1, i+1, 0, 0, 0, 0, time.UTC), e.Timestamp.UTC())
+	}
+	api.mtx.Lock()
+	require.Equal(t, "SSWS token", api.header.Get("Authorization"))
+	api.mtx.Unlock()
+
+	// New events of the last page are read at the next poll.
+	api.append("e4")
+	require.Eventually(t, func() bool {
+		return len(client.Received()) == 4
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Equal(t, "2:page-1", ps.GetString(ta.positionKey()))
+	ta.Stop()
+	ps.Stop()
+
+	// Restarting resumes after the last entry sent.
+	api.append("e5")
+	ps = testutils.NewPositions(t, logger, positionsFile)
+	defer ps.Stop()
+	client = fake.New(func() {})
+	ta, err = NewTarget(metrics, logger, client, ps, "job1", cfg)
+	require.NoError(t, err)
+	defer ta.Stop()
+
+	require.Eventually(t, func() bool {
+		return len(client.Received()) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+	require.Equal(t, []string{`{"published":"2023-01-05T00:00:00Z","message":"e5"}`}, testutils.ReceivedLines(client))
+}
+
+func Test_HTTPPollTargetLinkHeader(t *testing.T) {
+	var (
+		logger  = log.NewNopLogger()
+		metrics = NewMetrics(nil)
+	)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/audit-log", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") == "2" {
+			w.Header().Set("Link", `</audit-log?page=1>; rel="prev"`)
+			fmt.Fprint(w, `["line 3"]`)
+			return
+		}
+		w.Header().Add("Link", `</audit-log?page=2>; rel="next", </audit-log?page=2>; rel="last"`)
+		fmt.Fprint(w, `["line 1", "line \"2\""]`)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	cfg := &scrapeconfig.HTTPPollTargetConfig{
+		URL:              srv.URL + "/audit-log",
+		PollInterval:     10 * time.Millisecond,
+		FollowLinkHeader: true,
+	}
+	ps := testutils.NewPositions(t, logger, filepath.Join(t.TempDir(), "positions.yml"))
+	defer ps.Stop()
+	client := fake.New(func() {})
+	ta, err := NewTarget(metrics, logger, client, ps, "job1", cfg)
+	require.NoError(t, err)
+	defer ta.Stop()
+
+	require.Eventually(t, func() bool {
+		return len(client.Received()) == 3
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Equal(t, []string{"line 1", `line "2"`, "line 3"}, testutils.ReceivedLines(client))
+	require.Equal(t, "1:"+srv.URL+"/audit-log?page=2", ps.GetString(ta.positionKey()))
+}
+
+func Test_HTTPPollTargetError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "invalid token", http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	ps := testutils.NewPositions(t, log.NewNopLogger(), filepath.Join(t.TempDir(), "positions.yml"))
+	defer ps.Stop()
+	ta, err := NewTarget(NewMetrics(nil), log.NewNopLogger(), fake.New(func() {}), ps, "job1", &scrapeconfig.HTTPPollTargetConfig{
+		URL:          srv.URL,
+		PollInterval: time.Hour,
+	})
+	require.NoError(t, err)
+	defer ta.Stop()
+
+	require.Eventually(t, func() bool {
+		return ta.Details().(map[string]string)["error"] != ""
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Contains(t, ta.Details().(map[string]string)["error"], "401 Unauthorized: invalid token")
+}
+
+func Test_HTTPPollTargetStopWhileSending(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `["line 1", "line 2"]`)
+	}))
+	defer srv.Close()
+
+	ps := testutils.NewPositions(t, log.NewNopLogger(), filepath.Join(t.TempDir(), "positions.yml"))
+	defer ps.Stop()
+	// Nothing reads the entries sent, so the target is blocked sending the first line.
+	handler := api.NewEntryHandler(make(chan api.Entry), func() {})
+	ta, err := NewTarget(NewMetrics(nil), log.NewNopLogger(), handler, ps, "job1", &scrapeconfig.HTTPPollTargetConfig{
+		URL:          srv.URL,
+		PollInterval: 10 * time.Millisecond,
+	})
+	require.NoError(t, err)
+
+	stopped := make(chan struct{})
+	go func() {
+		ta.Stop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("target did not stop while blocked sending")
+	}
+	// The entry which was not sent is not recorded as read.
+	require.Equal(t, "", ps.GetString(ta.positionKey()))
+}
+
+func Test_ValidateConfig(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		config scrapeconfig.HTTPPollTargetConfig
+		err    string
+	}{
+		{"missing url", scrapeconfig.HTTPPollTargetConfig{}, "url is required"},
+		{"missing cursor param", scrapeconfig.HTTPPollTargetConfig{URL: "http://localhost", CursorPath: "next"}, "cursor_param is required"},
+		{"multiple paginations", scrapeconfig.HTTPPollTargetConfig{URL: "http://localhost", NextLinkPath: "next", FollowLinkHeader: true}, "only one of"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			require.ErrorContains(t, validateConfig(&tc.config), tc.err)
+		})
+	}
+}
+
+func Test_ParseTimestamp(t *testing.T) {
+	for _, tc := range []struct {
+		format, value string
+		expected      time.Time
+	}{
+		{time.RFC3339, "2023-01-02T03:04:05.123Z", time.Date(2023, 1, 2, 3, 4, 5, 123000000, time.UTC)},
+		{"Unix", "1672628645.5", time.Date(2023, 1, 2, 3, 4, 5, 500000000, time.UTC)},
+		{"UnixMs", "1672628645123", time.Date(2023, 1, 2, 3, 4, 5, 123000000, time.UTC)},
+		{"UnixUs", "1672628645123456", time.Date(2023, 1, 2, 3, 4, 5, 123456000, time.UTC)},
+		{"UnixNs", "1672628645123456789", time.Date(2023, 1, 2, 3, 4, 5, 123456789, time.UTC)},
+	} {
+		t.Run(tc.format, func(t *testing.T) {
+			ts, err := parseTimestamp(tc.format, tc.value)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, ts.UTC())
+		})
+	}
+}
+
+func Test_NextLink(t *testing.T) {
+	require.Equal(t, "https://api.github.com/orgs/grafana/audit-log?after=abc", nextLink([]string{
+		`<https://api.github.com/orgs/grafana/audit-log?after=abc>; rel="next", <https://api.github.com/orgs/grafana/audit-log?before=def>; rel="prev"`,
+	}))
+	require.Equal(t, "https://example.okta.com/api/v1/logs?after=1", nextLink([]string{
+		`<https://example.okta.com/api/v1/logs?since=0>; rel="self"`,
+		`<https://example.okta.com/api/v1/logs?after=1>; rel="next"`,
+	}))
+	require.Equal(t, "", nextLink([]string{`<https://example.com/?page=1>; rel="prev"`}))
+}
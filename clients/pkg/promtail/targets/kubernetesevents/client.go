@@ -0,0 +1,79 @@
+package kubernetesevents
+
+import (
+	"context"
+	"fmt"
+
+	promconfig "github.com/prometheus/common/config"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/grafana/loki/clients/pkg/promtail/scrapeconfig"
+
+	"github.com/grafana/loki/pkg/util/build"
+)
+
+var userAgent = fmt.Sprintf("promtail/%s", build.Version)
+
+// EventsClient lists and watches the events of a namespace, metav1.NamespaceAll
+// being all namespaces.
+type EventsClient interface {
+	List(ctx context.Context, namespace string, opts metav1.ListOptions) (*corev1.EventList, error)
+	Watch(ctx context.Context, namespace string, opts metav1.ListOptions) (watch.Interface, error)
+}
+
+// NewEventsClient returns an EventsClient reading the core/v1 Events API of a clientset.
+func NewEventsClient(client kubernetes.Interface) EventsClient {
+	return clientset{client}
+}
+
+type clientset struct {
+	client kubernetes.Interface
+}
+
+func (c clientset) List(ctx context.Context, namespace string, opts metav1.ListOptions) (*corev1.EventList, error) {
+	return c.client.CoreV1().Events(namespace).List(ctx, opts)
+}
+
+func (c clientset) Watch(ctx context.Context, namespace string, opts metav1.ListOptions) (watch.Interface, error) {
+	return c.client.CoreV1().Events(namespace).Watch(ctx, opts)
+}
+
+// newClientset creates a clientset connecting to the API server the same way the
+// Kubernetes service discovery does: with the kubeconfig file if set, with the pod
+// service account if no API server is set, and with the HTTP client options otherwise.
+func newClientset(cfg *scrapeconfig.KubernetesEventsTargetConfig) (kubernetes.Interface, error) {
+	var (
+		kcfg *rest.Config
+		err  error
+	)
+	switch {
+	case cfg.KubeConfig != "":
+		kcfg, err = clientcmd.BuildConfigFromFlags("", cfg.KubeConfig)
+		if err != nil {
+			return nil, err
+		}
+	case cfg.APIServer.URL == nil:
+		kcfg, err = rest.InClusterConfig()
+		if err != nil {
+			return nil, err
+		}
+	default:
+		rt, err := promconfig.NewRoundTripperFromConfig(cfg.Client, "kubernetes_events")
+		if err != nil {
+			return nil, err
+		}
+		kcfg = &rest.Config{
+			Host:      cfg.APIServer.String(),
+			Transport: rt,
+		}
+	}
+	kcfg.UserAgent = userAgent
+	kcfg.ContentType = "application/vnd.kubernetes.protobuf"
+
+	return kubernetes.NewForConfig(kcfg)
+}
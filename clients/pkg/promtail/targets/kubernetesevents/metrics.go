@@ -0,0 +1,45 @@
+package kubernetesevents
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// Metrics holds a set of Kubernetes events target metrics.
+type Metrics struct {
+	reg prometheus.Registerer
+
+	Entries *prometheus.CounterVec
+	Watches *prometheus.CounterVec
+	Errors  *prometheus.CounterVec
+}
+
+// NewMetrics creates a new set of Kubernetes events target metrics. If reg is non-nil, the
+// metrics will be registered.
+func NewMetrics(reg prometheus.Registerer) *Metrics {
+	var m Metrics
+	m.reg = reg
+
+	m.Entries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "promtail",
+		Name:      "kubernetes_events_target_entries_total",
+		Help:      "Total number of Kubernetes events sent by the Kubernetes events target.",
+	}, []string{"job"})
+	m.Watches = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "promtail",
+		Name:      "kubernetes_events_target_watches_total",
+		Help:      "Total number of watches of the Events API started by the Kubernetes events target.",
+	}, []string{"job"})
+	m.Errors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "promtail",
+		Name:      "kubernetes_events_target_errors_total",
+		Help:      "Total number of errors listing or watching the Events API in the Kubernetes events target.",
+	}, []string{"job"})
+
+	if reg != nil {
+		reg.MustRegister(
+			m.Entries,
+			m.Watches,
+			m.Errors,
+		)
+	}
+
+	return &m
+}
@@ -0,0 +1,492 @@
+package kubernetesevents
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/go-logfmt/logfmt"
+	"github.com/grafana/dskit/backoff"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/relabel"
+	"go.uber.org/atomic"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+
+	"github.com/grafana/loki/clients/pkg/promtail/api"
+	"github.com/grafana/loki/clients/pkg/promtail/positions"
+	"github.com/grafana/loki/clients/pkg/promtail/scrapeconfig"
+	"github.com/grafana/loki/clients/pkg/promtail/targets/target"
+
+	"github.com/grafana/loki/pkg/logproto"
+)
+
+const (
+	positionKeyPrefix = "kubernetes_events-"
+	// listPageSize is the number of events requested per page when listing events.
+	listPageSize = 500
+
+	metaLabelPrefix = model.MetaLabelPrefix + "kubernetes_event_"
+)
+
+var defaultBackoff = backoff.Config{
+	MinBackoff: 1 * time.Second,
+	MaxBackoff: 1 * time.Minute,
+	MaxRetries: 0, // Retry forever.
+}
+
+// Target watches the Kubernetes Events API, sending each event added or updated as a log line.
+//
+// The position of the target is the resource version of the last event received in each watched
+// namespace, the watch resuming from it after a restart, along with the time of the last event sent.
+// When there is no resource version, or when it has expired, the events are listed first and the ones
+// more recent than the last event sent are sent before watching again.
+type Target struct {
+	metrics       *Metrics
+	logger        log.Logger
+	handler       api.EntryHandler
+	positions     positions.Positions
+	config        *scrapeconfig.KubernetesEventsTargetConfig
+	relabelConfig []*relabel.Config
+	jobName       string
+
+	client  EventsClient
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+	running *atomic.Bool
+
+	mtx sync.Mutex
+	err error
+}
+
+// NewTarget creates a new Kubernetes events target, connecting to the API server configured.
+func NewTarget(
+	metrics *Metrics,
+	logger log.Logger,
+	handler api.EntryHandler,
+	position positions.Positions,
+	jobName string,
+	config *scrapeconfig.KubernetesEventsTargetConfig,
+	relabelConfig []*relabel.Config,
+) (*Target, error) {
+	client, err := newClientset(config)
+	if err != nil {
+		return nil, err
+	}
+	return NewTargetWithClient(metrics, logger, handler, position, jobName, config, relabelConfig, NewEventsClient(client)), nil
+}
+
+// NewTargetWithClient creates a new Kubernetes events target reading events with the given client.
+func NewTargetWithClient(
+	metrics *Metrics,
+	logger log.Logger,
+	handler api.EntryHandler,
+	position positions.Positions,
+	jobName string,
+	config *scrapeconfig.KubernetesEventsTargetConfig,
+	relabelConfig []*relabel.Config,
+	client EventsClient,
+) *Target {
+	t := &Target{
+		metrics:       metrics,
+		logger:        logger,
+		handler:       handler,
+		positions:     position,
+		config:        config,
+		relabelConfig: relabelConfig,
+		jobName:       jobName,
+
+		client:  client,
+		running: atomic.NewBool(false),
+	}
+	t.ctx, t.cancel = context.WithCancel(context.Background())
+	t.start()
+	return t
+}
+
+// namespaces returns the namespaces watched, metav1.NamespaceAll being all namespaces.
+func (t *Target) namespaces() []string {
+	if len(t.config.Namespaces) == 0 {
+		return []string{metav1.NamespaceAll}
+	}
+	return t.config.Namespaces
+}
+
+func (t *Target) start() {
+	t.running.Store(true)
+	for _, ns := range t.namespaces() {
+		t.wg.Add(1)
+		go t.run(ns)
+	}
+	go func() {
+		t.wg.Wait()
+		t.running.Store(false)
+	}()
+}
+
+// run watches the events of a namespace until the target is stopped.
+func (t *Target) run(namespace string) {
+	defer t.wg.Done()
+
+	backoff := backoff.New(t.ctx, defaultBackoff)
+	// lastSent is the time of the last event sent, events listed after the
+	// resource version expired being sent only if they are more recent.
+	_, lastSent := t.position(namespace)
+	for backoff.Ongoing() {
+		err := t.watch(namespace, &lastSent)
+		if t.ctx.Err() != nil {
+			return
+		}
+		t.setErr(err)
+		if err != nil {
+			level.Error(t.logger).Log("msg", "failed to watch events", "namespace", namespace, "err", err)
+			backoff.Wait()
+			continue
+		}
+		backoff.Reset()
+	}
+}
+
+// watch watches the events of a namespace from its position, until the watch is closed or fails.
+func (t *Target) watch(namespace string, lastSent *time.Time) error {
+	resourceVersion, _ := t.position(namespace)
+	if resourceVersion == "" {
+		var err error
+		if resourceVersion, err = t.list(namespace, lastSent); err != nil {
+			return err
+		}
+	}
+
+	t.metrics.Watches.WithLabelValues(t.jobName).Inc()
+	w, err := t.client.Watch(t.ctx, namespace, metav1.ListOptions{
+		FieldSelector:       t.config.FieldSelector,
+		ResourceVersion:     resourceVersion,
+		AllowWatchBookmarks: true,
+	})
+	if err != nil {
+		return t.expired(namespace, *lastSent, err)
+	}
+	defer w.Stop()
+
+	for {
+		select {
+		case <-t.ctx.Done():
+			return nil
+		case ev, ok := <-w.ResultChan():
+			if !ok {
+				return nil
+			}
+			switch ev.Type {
+			case watch.Added, watch.Modified, watch.Deleted, watch.Bookmark:
+				event, ok := ev.Object.(*corev1.Event)
+				if !ok {
+					level.Warn(t.logger).Log("msg", "unexpected object type in watch", "type", ev.Type, "object", ev.Object)
+					continue
+				}
+				// Deleted events have expired, there is nothing new to send.
+				if (ev.Type == watch.Added || ev.Type == watch.Modified) && !t.send(event, lastSent) {
+					return nil
+				}
+				t.setPosition(namespace, event.ResourceVersion, *lastSent)
+			case watch.Error:
+				return t.expired(namespace, *lastSent, apierrors.FromObject(ev.Object))
+			}
+		}
+	}
+}
+
+// expired resets the resource version of the namespace if the error is caused by an expired resource
+// version, so that events are listed again. Other errors are returned as is.
+func (t *Target) expired(namespace string, lastSent time.Time, err error) error {
+	if !apierrors.IsResourceExpired(err) && !apierrors.IsGone(err) {
+		return err
+	}
+	level.Warn(t.logger).Log("msg", "resource version expired, listing events again", "namespace", namespace, "err", err)
+	t.setPosition(namespace, "", lastSent)
+	return nil
+}
+
+// list sends the events of a namespace more recent than lastSent, in chronological order,
+// and returns the resource version to watch from.
+func (t *Target) list(namespace string, lastSent *time.Time) (string, error) {
+	var (
+		events          []corev1.Event
+		resourceVersion string
+		opts            = metav1.ListOptions{
+			FieldSelector: t.config.FieldSelector,
+			Limit:         listPageSize,
+		}
+	)
+	for {
+		list, err := t.client.List(t.ctx, namespace, opts)
+		if err != nil {
+			return "", err
+		}
+		// Next pages are read from the snapshot of the first one.
+		if resourceVersion == "" {
+			resourceVersion = list.ResourceVersion
+		}
+		events = append(events, list.Items...)
+		if list.Continue == "" {
+			break
+		}
+		opts.Continue = list.Continue
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return eventTime(&events[i]).Before(eventTime(&events[j]))
+	})
+	for i := range events {
+		if t.ctx.Err() != nil {
+			return "", t.ctx.Err()
+		}
+		if !eventTime(&events[i]).After(*lastSent) {
+			continue
+		}
+		if !t.send(&events[i], lastSent) {
+			return "", t.ctx.Err()
+		}
+	}
+	t.setPosition(namespace, resourceVersion, *lastSent)
+	return resourceVersion, nil
+}
+
+// send sends an event to the pipeline, unless it is dropped by the relabeling rules, and advances
+// lastSent to its time. It returns false if the target was stopped before the event could be sent.
+func (t *Target) send(event *corev1.Event, lastSent *time.Time) bool {
+	ts := eventTime(event)
+	if entry, ok := t.entry(event, ts); ok {
+		select {
+		case t.handler.Chan() <- entry:
+		case <-t.ctx.Done():
+			return false
+		}
+		t.metrics.Entries.WithLabelValues(t.jobName).Inc()
+	}
+	if ts.After(*lastSent) {
+		*lastSent = ts
+	}
+	return true
+}
+
+// entry returns the entry of an event, or false if the event is dropped by the relabeling rules.
+func (t *Target) entry(event *corev1.Event, ts time.Time) (api.Entry, bool) {
+	lbls, keep := relabel.Process(labels.FromMap(t.eventLabels(event)), t.relabelConfig...)
+	if !keep {
+		return api.Entry{}, false
+	}
+	entryLabels := make(model.LabelSet, len(lbls))
+	for _, l := range lbls {
+		if strings.HasPrefix(l.Name, model.ReservedLabelPrefix) || l.Value == "" {
+			continue
+		}
+		entryLabels[model.LabelName(l.Name)] = model.LabelValue(l.Value)
+	}
+
+	line, err := eventLine(event)
+	if err != nil {
+		level.Warn(t.logger).Log("msg", "failed to format event", "event", event.Name, "err", err)
+		return api.Entry{}, false
+	}
+	return api.Entry{
+		Labels: entryLabels,
+		Entry: logproto.Entry{
+			Timestamp: ts,
+			Line:      line,
+		},
+	}, true
+}
+
+// eventLabels returns the labels of an event before relabeling: the static labels, the namespace
+// and kind of the involved object, and the meta labels describing the event.
+func (t *Target) eventLabels(event *corev1.Event) map[string]string {
+	obj := event.InvolvedObject
+	lbls := map[string]string{
+		"namespace": obj.Namespace,
+		"kind":      obj.Kind,
+
+		metaLabelPrefix + "namespace":                   event.Namespace,
+		metaLabelPrefix + "name":                        event.Name,
+		metaLabelPrefix + "type":                        event.Type,
+		metaLabelPrefix + "reason":                      event.Reason,
+		metaLabelPrefix + "source_component":            eventSource(event),
+		metaLabelPrefix + "source_host":                 event.Source.Host,
+		metaLabelPrefix + "involved_object_kind":        obj.Kind,
+		metaLabelPrefix + "involved_object_namespace":   obj.Namespace,
+		metaLabelPrefix + "involved_object_name":        obj.Name,
+		metaLabelPrefix + "involved_object_uid":         string(obj.UID),
+		metaLabelPrefix + "involved_object_api_version": obj.APIVersion,
+		metaLabelPrefix + "involved_object_field_path":  obj.FieldPath,
+	}
+	for k, v := range t.config.Labels {
+		lbls[string(k)] = string(v)
+	}
+	return lbls
+}
+
+// eventLine formats an event as a logfmt line.
+func eventLine(event *corev1.Event) (string, error) {
+	obj := event.InvolvedObject
+	keyvals := []interface{}{
+		"type", event.Type,
+		"reason", event.Reason,
+		"kind", obj.Kind,
+	}
+	if obj.Namespace != "" {
+		keyvals = append(keyvals, "namespace", obj.Namespace)
+	}
+	keyvals = append(keyvals, "name", obj.Name)
+	if obj.FieldPath != "" {
+		keyvals = append(keyvals, "field_path", obj.FieldPath)
+	}
+	if count := eventCount(event); count > 1 {
+		keyvals = append(keyvals, "count", count)
+	}
+	if source := eventSource(event); source != "" {
+		keyvals = append(keyvals, "source", source)
+	}
+	keyvals = append(keyvals, "msg", event.Message)
+
+	var buf bytes.Buffer
+	if err := logfmt.NewEncoder(&buf).EncodeKeyvals(keyvals...); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// eventTime returns the last time an event occurred, which depends on the API used to create it.
+func eventTime(event *corev1.Event) time.Time {
+	switch {
+	case event.Series != nil && !event.Series.LastObservedTime.IsZero():
+		return event.Series.LastObservedTime.Time
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	case !event.FirstTimestamp.IsZero():
+		return event.FirstTimestamp.Time
+	default:
+		return event.CreationTimestamp.Time
+	}
+}
+
+func eventCount(event *corev1.Event) int32 {
+	if event.Series != nil {
+		return event.Series.Count
+	}
+	return event.Count
+}
+
+func eventSource(event *corev1.Event) string {
+	if event.ReportingController != "" {
+		return event.ReportingController
+	}
+	return event.Source.Component
+}
+
+func (t *Target) positionKey(namespace string) string {
+	if namespace == metav1.NamespaceAll {
+		return positions.CursorKey(positionKeyPrefix + t.jobName)
+	}
+	return positions.CursorKey(positionKeyPrefix + t.jobName + "-" + namespace)
+}
+
+// position returns the resource version to watch the events of a namespace from, and the time of the
+// last event sent. Positions written by older versions only have the resource version.
+func (t *Target) position(namespace string) (string, time.Time) {
+	pos := t.positions.GetString(t.positionKey(namespace))
+	i := strings.LastIndexByte(pos, ':')
+	if i < 0 {
+		return pos, time.Time{}
+	}
+	nanos, err := strconv.ParseInt(pos[i+1:], 10, 64)
+	if err != nil {
+		level.Warn(t.logger).Log("msg", "invalid time of the last event sent in position", "namespace", namespace, "position", pos, "err", err)
+		return pos[:i], time.Time{}
+	}
+	return pos[:i], time.Unix(0, nanos).UTC()
+}
+
+// setPosition records the resource version to watch the events of a namespace from, and the time of
+// the last event sent, as "<resource version>:<unix time in nanoseconds>".
+func (t *Target) setPosition(namespace, resourceVersion string, lastSent time.Time) {
+	if resourceVersion == "" && lastSent.IsZero() {
+		t.positions.Remove(t.positionKey(namespace))
+		return
+	}
+	pos := resourceVersion
+	if !lastSent.IsZero() {
+		pos += ":" + strconv.FormatInt(lastSent.UnixNano(), 10)
+	}
+	t.positions.PutString(t.positionKey(namespace), pos)
+}
+
+func (t *Target) setErr(err error) {
+	if err != nil {
+		t.metrics.Errors.WithLabelValues(t.jobName).Inc()
+	}
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	t.err = err
+}
+
+// Stop stops the target, closing its watches.
+func (t *Target) Stop() {
+	t.cancel()
+	t.wg.Wait()
+	t.handler.Stop()
+}
+
+// Type returns KubernetesEventsTargetType.
+func (t *Target) Type() target.TargetType {
+	return target.KubernetesEventsTargetType
+}
+
+// DiscoveredLabels returns the set of labels discovered by the Kubernetes events target, which
+// is always nil. Implements Target.
+func (t *Target) DiscoveredLabels() model.LabelSet {
+	return nil
+}
+
+// Labels returns the set of labels that statically apply to all log entries
+// produced by the Kubernetes events target.
+func (t *Target) Labels() model.LabelSet {
+	return t.config.Labels
+}
+
+// Ready indicates whether or not the Kubernetes events target is watching events.
+func (t *Target) Ready() bool {
+	return t.running.Load()
+}
+
+// Details returns target-specific details.
+func (t *Target) Details() interface{} {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	var errMsg string
+	if t.err != nil {
+		errMsg = t.err.Error()
+	}
+	details := map[string]string{
+		"field_selector": t.config.FieldSelector,
+		"error":          errMsg,
+	}
+	for _, ns := range t.namespaces() {
+		key := "position"
+		if ns != metav1.NamespaceAll {
+			key += "_" + ns
+		}
+		details[key] = t.positions.GetString(t.positionKey(ns))
+	}
+	return details
+}
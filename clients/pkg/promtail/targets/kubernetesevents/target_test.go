@@ -0,0 +1,439 @@
+package kubernetesevents
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/relabel"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/grafana/loki/clients/pkg/promtail/api"
+	"github.com/grafana/loki/clients/pkg/promtail/client/fake"
+	"github.com/grafana/loki/clients/pkg/promtail/positions"
+	"github.com/grafana/loki/clients/pkg/promtail/scrapeconfig"
+	"github.com/grafana/loki/clients/pkg/promtail/targets/testutils"
+)
+
+var eventsTime = time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+func newEvent(name, resourceVersion, reason, message string, minute int) corev1.Event {
+	return corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       "default",
+			ResourceVersion: resourceVersion,
+		},
+		InvolvedObject: corev1.ObjectReference{
+			Kind:      "Pod",
+			Namespace: "default",
+			Name:      "web-0",
+			UID:       "1234",
+		},
+		Reason:        reason,
+		Message:       message,
+		Type:          corev1.EventTypeNormal,
+		Source:        corev1.EventSource{Component: "kubelet", Host: "node-1"},
+		Count:         1,
+		LastTimestamp: metav1.NewTime(eventsTime.Add(time.Duration(minute) * time.Minute)),
+	}
+}
+
+// fakeEventsClient lists a fixed set of events and returns fake watchers the tests send events to.
+type fakeEventsClient struct {
+	mtx             sync.Mutex
+	events          []corev1.Event
+	resourceVersion string
+	pageSize        int
+	lists           int
+	watchOpts       []metav1.ListOptions
+	watchErr        error
+
+	watchers chan *watch.FakeWatcher
+}
+
+func newFakeEventsClient(resourceVersion string, events ...corev1.Event) *fakeEventsClient {
+	return &fakeEventsClient{
+		events:          events,
+		resourceVersion: resourceVersion,
+		pageSize:        2,
+		watchers:        make(chan *watch.FakeWatcher, 10),
+	}
+}
+
+func (c *fakeEventsClient) List(_ context.Context, _ string, opts metav1.ListOptions) (*corev1.EventList, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.lists++
+
+	start, _ := strconv.Atoi(opts.Continue)
+	end := start + c.pageSize
+	list := &corev1.EventList{ListMeta: metav1.ListMeta{ResourceVersion: c.resourceVersion}}
+	if end < len(c.events) {
+		list.Continue = strconv.Itoa(end)
+	} else {
+		end = len(c.events)
+	}
+	list.Items = c.events[start:end]
+	return list, nil
+}
+
+func (c *fakeEventsClient) Watch(_ context.Context, _ string, opts metav1.ListOptions) (watch.Interface, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.watchOpts = append(c.watchOpts, opts)
+	if err := c.watchErr; err != nil {
+		c.watchErr = nil
+		return nil, err
+	}
+	w := watch.NewFake()
+	c.watchers <- w
+	return w, nil
+}
+
+func (c *fakeEventsClient) watchedVersions() []string {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	var versions []string
+	for _, opts := range c.watchOpts {
+		versions = append(versions, opts.ResourceVersion)
+	}
+	return versions
+}
+
+func (c *fakeEventsClient) nextWatcher(t *testing.T) *watch.FakeWatcher {
+	select {
+	case w := <-c.watchers:
+		return w
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for a watch")
+		return nil
+	}
+}
+
+func Test_KubernetesEventsTarget(t *testing.T) {
+	var (
+		w             = log.NewSyncWriter(os.Stderr)
+		logger        = log.NewLogfmtLogger(w)
+		positionsFile = filepath.Join(t.TempDir(), "positions.yml")
+		metrics       = NewMetrics(prometheus.NewRegistry())
+		cfg           = &scrapeconfig.KubernetesEventsTargetConfig{
+			FieldSelector: "type=Normal",
+			Labels:        model.LabelSet{"job": "kubernetes-events"},
+		}
+	)
+
+	// The events listed are sent in chronological order.
+	kube := newFakeEventsClient("10",
+		newEvent("web-0.2", "8", "Pulled", "Container image already present on machine", 2),
+		newEvent("web-0.1", "7", "Scheduled", "Successfully assigned default/web-0 to node-1", 1),
+		newEvent("web-0.3", "9", "Started", "Started container web", 3),
+	)
+	ps := testutils.NewPositions(t, logger, positionsFile)
+	client := fake.New(func() {})
+	ta := NewTargetWithClient(metrics, logger, client, ps, "job1", cfg, nil, kube)
+
+	watcher := kube.nextWatcher(t)
+	require.Equal(t, []string{"10"}, kube.watchedVersions())
+	require.Equal(t, "type=Normal", kube.watchOpts[0].FieldSelector)
+	require.True(t, kube.watchOpts[0].AllowWatchBookmarks)
+	resourceVersion, lastSent := ta.position(metav1.NamespaceAll)
+	require.Equal(t, "10", resourceVersion)
+	require.Equal(t, eventsTime.Add(3*time.Minute), lastSent)
+
+	restarted := newEvent("web-0.4", "11", "BackOff", "Back-off restarting failed container", 4)
+	restarted.Type = corev1.EventTypeWarning
+	watcher.Add(&restarted)
+	updated := restarted.DeepCopy()
+	updated.ResourceVersion, updated.Count = "12", 2
+	updated.LastTimestamp = metav1.NewTime(eventsTime.Add(5 * time.Minute))
+	watcher.Modify(updated)
+	watcher.Action(watch.Bookmark, &corev1.Event{ObjectMeta: metav1.ObjectMeta{ResourceVersion: "13"}})
+
+	require.Eventually(t, func() bool {
+		resourceVersion, _ := ta.position(metav1.NamespaceAll)
+		return len(client.Received()) == 5 && resourceVersion == "13"
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Equal(t, fmt.Sprintf("13:%d", eventsTime.Add(5*time.Minute).UnixNano()), ps.GetString(positions.CursorKey("kubernetes_events-job1")))
+	require.Equal(t, []string{
+		`type=Normal reason=Scheduled kind=Pod namespace=default name=web-0 source=kubelet msg="Successfully assigned default/web-0 to node-1"`,
+		`type=Normal reason=Pulled kind=Pod namespace=default name=web-0 source=kubelet msg="Container image already present on machine"`,
+		`type=Normal reason=Started kind=Pod namespace=default name=web-0 source=kubelet msg="Started container web"`,
+		`type=Warning reason=BackOff kind=Pod namespace=default name=web-0 source=kubelet msg="Back-off restarting failed container"`,
+		`type=Warning reason=BackOff kind=Pod namespace=default name=web-0 count=2 source=kubelet msg="Back-off restarting failed container"`,
+	}, testutils.ReceivedLines(client))
+	for i, e := range client.Received() {
+		require.Equal(t, model.LabelSet{"job": "kubernetes-events", "namespace": "default", "kind": "Pod"}, e.Labels)
+		require.Equal(t, eventsTime.Add(time.Duration(i+1)*time.Minute), e.Timestamp.UTC())
+	}
+	require.True(t, ta.Ready())
+	ta.Stop()
+	require.Eventually(t, func() bool { return !ta.Ready() }, 5*time.Second, 10*time.Millisecond)
+	ps.Stop()
+
+	// Restarting resumes the watch from the last resource version received, without listing events.
+	ps = testutils.NewPositions(t, logger, positionsFile)
+	defer ps.Stop()
+	kube = newFakeEventsClient("20")
+	client = fake.New(func() {})
+	ta = NewTargetWithClient(metrics, logger, client, ps, "job1", cfg, nil, kube)
+	defer ta.Stop()
+
+	kube.nextWatcher(t)
+	require.Equal(t, []string{"13"}, kube.watchedVersions())
+	require.Equal(t, 0, kube.lists)
+}
+
+func Test_KubernetesEventsTargetExpired(t *testing.T) {
+	var (
+		logger  = log.NewNopLogger()
+		metrics = NewMetrics(nil)
+		cfg     = &scrapeconfig.KubernetesEventsTargetConfig{Namespaces: []string{"default"}}
+	)
+	ps := testutils.NewPositions(t, logger, filepath.Join(t.TempDir(), "positions.yml"))
+	defer ps.Stop()
+	key := positions.CursorKey("kubernetes_events-job1-default")
+	ps.PutString(key, "5")
+
+	// The position expired while Promtail was stopped: all the events are listed again.
+	kube := newFakeEventsClient("10",
+		newEvent("web-0.1", "7", "Scheduled", "scheduled", 1),
+		newEvent("web-0.2", "8", "Pulled", "pulled", 2),
+	)
+	kube.watchErr = apierrors.NewResourceExpired("too old resource version: 5 (7)")
+	client := fake.New(func() {})
+	ta := NewTargetWithClient(metrics, logger, client, ps, "job1", cfg, nil, kube)
+	defer ta.Stop()
+
+	watcher := kube.nextWatcher(t)
+	require.Equal(t, []string{"5", "10"}, kube.watchedVersions())
+	require.Equal(t, 1, kube.lists)
+	resourceVersion, _ := ta.position("default")
+	require.Equal(t, "10", resourceVersion)
+
+	// The watch expires while running: only the events more recent than the last one sent are listed.
+	kube.mtx.Lock()
+	kube.resourceVersion = "30"
+	kube.events = append(kube.events, newEvent("web-0.3", "25", "Started", "started", 3))
+	kube.mtx.Unlock()
+	watcher.Error(&apierrors.NewGone("watch expired").ErrStatus)
+
+	kube.nextWatcher(t)
+	require.Equal(t, []string{"5", "10", "30"}, kube.watchedVersions())
+	require.Eventually(t, func() bool {
+		return len(client.Received()) == 3
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Equal(t, []string{
+		`type=Normal reason=Scheduled kind=Pod namespace=default name=web-0 source=kubelet msg=scheduled`,
+		`type=Normal reason=Pulled kind=Pod namespace=default name=web-0 source=kubelet msg=pulled`,
+		`type=Normal reason=Started kind=Pod namespace=default name=web-0 source=kubelet msg=started`,
+	}, testutils.ReceivedLines(client))
+	resourceVersion, _ = ta.position("default")
+	require.Equal(t, "30", resourceVersion)
+}
+
+func Test_KubernetesEventsTargetExpiredAfterRestart(t *testing.T) {
+	var (
+		logger  = log.NewNopLogger()
+		metrics = NewMetrics(nil)
+		cfg     = &scrapeconfig.KubernetesEventsTargetConfig{Namespaces: []string{"default"}}
+	)
+	ps := testutils.NewPositions(t, logger, filepath.Join(t.TempDir(), "positions.yml"))
+	defer ps.Stop()
+	// The position recorded by the previous run expired while Promtail was stopped: only the
+	// events more recent than the last one sent by the previous run are sent.
+	ps.PutString(positions.CursorKey("kubernetes_events-job1-default"), fmt.Sprintf("5:%d", eventsTime.Add(time.Minute).UnixNano()))
+
+	kube := newFakeEventsClient("10",
+		newEvent("web-0.1", "7", "Scheduled", "scheduled", 1),
+		newEvent("web-0.2", "8", "Pulled", "pulled", 2),
+	)
+	kube.watchErr = apierrors.NewResourceExpired("too old resource version: 5 (7)")
+	client := fake.New(func() {})
+	ta := NewTargetWithClient(metrics, logger, client, ps, "job1", cfg, nil, kube)
+	defer ta.Stop()
+
+	kube.nextWatcher(t)
+	require.Equal(t, []string{"5", "10"}, kube.watchedVersions())
+	require.Eventually(t, func() bool {
+		return len(client.Received()) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Equal(t, []string{`type=Normal reason=Pulled kind=Pod namespace=default name=web-0 source=kubelet msg=pulled`}, testutils.ReceivedLines(client))
+	resourceVersion, lastSent := ta.position("default")
+	require.Equal(t, "10", resourceVersion)
+	require.Equal(t, eventsTime.Add(2*time.Minute), lastSent)
+}
+
+func Test_KubernetesEventsTargetStopWhileSending(t *testing.T) {
+	var (
+		logger = log.NewNopLogger()
+		cfg    = &scrapeconfig.KubernetesEventsTargetConfig{Namespaces: []string{"default"}}
+	)
+	ps := testutils.NewPositions(t, logger, filepath.Join(t.TempDir(), "positions.yml"))
+	defer ps.Stop()
+	kube := newFakeEventsClient("10",
+		newEvent("web-0.1", "7", "Scheduled", "scheduled", 1),
+		newEvent("web-0.2", "8", "Pulled", "pulled", 2),
+	)
+	// Nothing reads the entries sent, so the target is blocked sending the first event.
+	handler := api.NewEntryHandler(make(chan api.Entry), func() {})
+	ta := NewTargetWithClient(NewMetrics(nil), logger, handler, ps, "job1", cfg, nil, kube)
+
+	stopped := make(chan struct{})
+	go func() {
+		ta.Stop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("target did not stop while blocked sending")
+	}
+	// The event which was not sent is not recorded as read.
+	require.Empty(t, ps.GetString(positions.CursorKey("kubernetes_events-job1-default")))
+}
+
+func Test_KubernetesEventsTargetClientset(t *testing.T) {
+	var (
+		logger  = log.NewNopLogger()
+		metrics = NewMetrics(nil)
+		cfg     = &scrapeconfig.KubernetesEventsTargetConfig{
+			Namespaces:    []string{"default"},
+			FieldSelector: "type=Normal",
+		}
+	)
+	ps := testutils.NewPositions(t, logger, filepath.Join(t.TempDir(), "positions.yml"))
+	defer ps.Stop()
+
+	scheduled := newEvent("web-0.1", "7", "Scheduled", "scheduled", 1)
+	other := newEvent("other.1", "8", "Scheduled", "other namespace", 2)
+	other.Namespace = "kube-system"
+	kube := kubefake.NewSimpleClientset(&scheduled, &other)
+	client := fake.New(func() {})
+	ta := NewTargetWithClient(metrics, logger, client, ps, "job1", cfg, nil, NewEventsClient(kube))
+	defer ta.Stop()
+
+	// Events created once the namespace is watched are sent as they are received.
+	require.Eventually(t, func() bool {
+		for _, action := range kube.Actions() {
+			if action.GetVerb() == "watch" {
+				return true
+			}
+		}
+		return false
+	}, 5*time.Second, 10*time.Millisecond)
+	pulled := newEvent("web-0.2", "9", "Pulled", "pulled", 3)
+	_, err := kube.CoreV1().Events("default").Create(context.Background(), &pulled, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		return len(client.Received()) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Equal(t, []string{
+		`type=Normal reason=Scheduled kind=Pod namespace=default name=web-0 source=kubelet msg=scheduled`,
+		`type=Normal reason=Pulled kind=Pod namespace=default name=web-0 source=kubelet msg=pulled`,
+	}, testutils.ReceivedLines(client))
+
+	for _, action := range kube.Actions() {
+		require.Equal(t, "default", action.GetNamespace())
+		require.Equal(t, "events", action.GetResource().Resource)
+		switch a := action.(type) {
+		case k8stesting.ListAction:
+			require.Equal(t, "type=Normal", a.GetListRestrictions().Fields.String())
+		case k8stesting.WatchAction:
+			require.Equal(t, "type=Normal", a.GetWatchRestrictions().Fields.String())
+		}
+	}
+	require.Eventually(t, func() bool {
+		resourceVersion, _ := ta.position("default")
+		return resourceVersion == "9"
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func Test_KubernetesEventsTargetRelabeling(t *testing.T) {
+	var (
+		logger  = log.NewNopLogger()
+		metrics = NewMetrics(nil)
+		cfg     = &scrapeconfig.KubernetesEventsTargetConfig{}
+	)
+	relabelConfig := []*relabel.Config{
+		{
+			SourceLabels: model.LabelNames{"__meta_kubernetes_event_type"},
+			Regex:        relabel.MustNewRegexp("Warning"),
+			Action:       relabel.Keep,
+		},
+		{
+			SourceLabels: model.LabelNames{"__meta_kubernetes_event_reason"},
+			Regex:        relabel.MustNewRegexp("(.*)"),
+			Replacement:  "$1",
+			TargetLabel:  "reason",
+			Action:       relabel.Replace,
+		},
+		{
+			Regex:  relabel.MustNewRegexp("kind"),
+			Action: relabel.LabelDrop,
+		},
+	}
+	ps := testutils.NewPositions(t, logger, filepath.Join(t.TempDir(), "positions.yml"))
+	defer ps.Stop()
+
+	warning := newEvent("web-0.2", "8", "BackOff", "Back-off restarting failed container", 2)
+	warning.Type = corev1.EventTypeWarning
+	kube := newFakeEventsClient("10", newEvent("web-0.1", "7", "Started", "started", 1), warning)
+	client := fake.New(func() {})
+	ta := NewTargetWithClient(metrics, logger, client, ps, "job1", cfg, relabelConfig, kube)
+	defer ta.Stop()
+
+	kube.nextWatcher(t)
+	require.Eventually(t, func() bool {
+		return len(client.Received()) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Equal(t, model.LabelSet{"namespace": "default", "reason": "BackOff"}, client.Received()[0].Labels)
+}
+
+func Test_eventTime(t *testing.T) {
+	event := corev1.Event{ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(eventsTime)}}
+	require.Equal(t, eventsTime, eventTime(&event))
+
+	event.EventTime = metav1.NewMicroTime(eventsTime.Add(time.Second))
+	require.Equal(t, eventsTime.Add(time.Second), eventTime(&event))
+
+	event.Series = &corev1.EventSeries{Count: 3, LastObservedTime: metav1.NewMicroTime(eventsTime.Add(time.Minute))}
+	require.Equal(t, eventsTime.Add(time.Minute), eventTime(&event))
+	require.Equal(t, int32(3), eventCount(&event))
+}
+
+func Test_expired(t *testing.T) {
+	ps := testutils.NewPositions(t, log.NewNopLogger(), filepath.Join(t.TempDir(), "positions.yml"))
+	defer ps.Stop()
+	ps.PutString(positions.CursorKey("kubernetes_events-job1-default"), "5")
+
+	ta := &Target{positions: ps, jobName: "job1", logger: log.NewNopLogger()}
+	require.NoError(t, ta.expired("default", time.Time{}, apierrors.NewGone("gone")))
+	require.Empty(t, ps.GetString(positions.CursorKey("kubernetes_events-job1-default")))
+
+	// The time of the last event sent is kept.
+	ps.PutString(positions.CursorKey("kubernetes_events-job1-default"), "5")
+	require.NoError(t, ta.expired("default", eventsTime, apierrors.NewGone("gone")))
+	resourceVersion, lastSent := ta.position("default")
+	require.Empty(t, resourceVersion)
+	require.Equal(t, eventsTime, lastSent)
+
+	err := apierrors.NewForbidden(schema.GroupResource{Resource: "events"}, "", nil)
+	require.Equal(t, err, ta.expired("default", time.Time{}, err))
+}
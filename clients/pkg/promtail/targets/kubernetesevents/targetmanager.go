@@ -0,0 +1,91 @@
+package kubernetesevents
+
+import (
+	"fmt"
+
+	"github.com/go-kit/log"
+
+	"github.com/grafana/loki/clients/pkg/logentry/stages"
+	"github.com/grafana/loki/clients/pkg/promtail/api"
+	"github.com/grafana/loki/clients/pkg/promtail/positions"
+	"github.com/grafana/loki/clients/pkg/promtail/scrapeconfig"
+	"github.com/grafana/loki/clients/pkg/promtail/targets/target"
+)
+
+// TargetManager manages a series of Kubernetes events targets.
+type TargetManager struct {
+	logger  log.Logger
+	targets map[string]*Target
+}
+
+// NewTargetManager creates a new Kubernetes events target manager.
+func NewTargetManager(
+	metrics *Metrics,
+	logger log.Logger,
+	positions positions.Positions,
+	pushClient api.EntryHandler,
+	scrapeConfigs []scrapeconfig.Config,
+) (*TargetManager, error) {
+	tm := &TargetManager{
+		logger:  logger,
+		targets: make(map[string]*Target),
+	}
+	for _, cfg := range scrapeConfigs {
+		if cfg.KubernetesEventsConfig == nil {
+			continue
+		}
+		// The job name is part of the positions key of the target.
+		if _, ok := tm.targets[cfg.JobName]; ok {
+			return nil, fmt.Errorf("`job_name` must be unique for each `kubernetes_events` scrape_config, "+
+				"a duplicate `job_name` of %s was found", cfg.JobName)
+		}
+		pipeline, err := stages.NewPipeline(log.With(logger, "component", "kubernetes_events_pipeline_"+cfg.JobName), cfg.PipelineStages, &cfg.JobName, metrics.reg)
+		if err != nil {
+			return nil, err
+		}
+		t, err := NewTarget(metrics, log.With(logger, "target", "kubernetes_events", "job", cfg.JobName), pipeline.Wrap(pushClient), positions, cfg.JobName, cfg.KubernetesEventsConfig, cfg.RelabelConfigs)
+		if err != nil {
+			return nil, err
+		}
+		tm.targets[cfg.JobName] = t
+	}
+
+	return tm, nil
+}
+
+// Ready returns true if at least one Kubernetes events target is active.
+func (tm *TargetManager) Ready() bool {
+	for _, t := range tm.targets {
+		if t.Ready() {
+			return true
+		}
+	}
+	return false
+}
+
+// Stop stops the TargetManager and all of its Kubernetes events targets.
+func (tm *TargetManager) Stop() {
+	for _, t := range tm.targets {
+		t.Stop()
+	}
+}
+
+// ActiveTargets returns the list of Kubernetes events targets watching events.
+func (tm *TargetManager) ActiveTargets() map[string][]target.Target {
+	result := make(map[string][]target.Target, len(tm.targets))
+	for k, v := range tm.targets {
+		if v.Ready() {
+			result[k] = []target.Target{v}
+		}
+	}
+	return result
+}
+
+// AllTargets returns the list of all Kubernetes events targets.
+func (tm *TargetManager) AllTargets() map[string][]target.Target {
+	result := make(map[string][]target.Target, len(tm.targets))
+	for k, v := range tm.targets {
+		result[k] = []target.Target{v}
+	}
+	return result
+}
@@ -18,9 +18,13 @@ import (
 	"github.com/grafana/loki/clients/pkg/promtail/targets/gcplog"
 	"github.com/grafana/loki/clients/pkg/promtail/targets/gelf"
 	"github.com/grafana/loki/clients/pkg/promtail/targets/heroku"
+	"github.com/grafana/loki/clients/pkg/promtail/targets/httppoll"
 	"github.com/grafana/loki/clients/pkg/promtail/targets/journal"
 	"github.com/grafana/loki/clients/pkg/promtail/targets/kafka"
+	"github.com/grafana/loki/clients/pkg/promtail/targets/kubernetesevents"
 	"github.com/grafana/loki/clients/pkg/promtail/targets/lokipush"
+	"github.com/grafana/loki/clients/pkg/promtail/targets/objectstore"
+	"github.com/grafana/loki/clients/pkg/promtail/targets/otlp"
 	"github.com/grafana/loki/clients/pkg/promtail/targets/stdin"
 	"github.com/grafana/loki/clients/pkg/promtail/targets/syslog"
 	"github.com/grafana/loki/clients/pkg/promtail/targets/target"
@@ -39,6 +43,10 @@ const (
 	CloudflareConfigs           = "cloudflareConfigs"
 	DockerSDConfigs             = "dockerSDConfigs"
 	HerokuDrainConfigs          = "herokuDrainConfigs"
+	OTLPConfigs                 = "otlpConfigs"
+	ObjectStoreConfigs          = "objectStoreConfigs"
+	HTTPPollConfigs             = "httpPollConfigs"
+	KubernetesEventsConfigs     = "kubernetesEventsConfigs"
 	AzureEventHubsScrapeConfigs = "azureeventhubsScrapeConfigs"
 )
 
@@ -51,6 +59,9 @@ var (
 	dockerMetrics      *docker.Metrics
 	journalMetrics     *journal.Metrics
 	herokuDrainMetrics *heroku.Metrics
+	objectStoreMetrics *objectstore.Metrics
+	httpPollMetrics    *httppoll.Metrics
+	kubeEventsMetrics  *kubernetesevents.Metrics
 )
 
 type targetManager interface {
@@ -115,6 +126,14 @@ func NewTargetManagers(
 			targetScrapeConfigs[DockerSDConfigs] = append(targetScrapeConfigs[DockerSDConfigs], cfg)
 		case cfg.HerokuDrainConfig != nil:
 			targetScrapeConfigs[HerokuDrainConfigs] = append(targetScrapeConfigs[HerokuDrainConfigs], cfg)
+		case cfg.OTLPConfig != nil:
+			targetScrapeConfigs[OTLPConfigs] = append(targetScrapeConfigs[OTLPConfigs], cfg)
+		case cfg.ObjectStoreConfig != nil:
+			targetScrapeConfigs[ObjectStoreConfigs] = append(targetScrapeConfigs[ObjectStoreConfigs], cfg)
+		case cfg.HTTPPollConfig != nil:
+			targetScrapeConfigs[HTTPPollConfigs] = append(targetScrapeConfigs[HTTPPollConfigs], cfg)
+		case cfg.KubernetesEventsConfig != nil:
+			targetScrapeConfigs[KubernetesEventsConfigs] = append(targetScrapeConfigs[KubernetesEventsConfigs], cfg)
 		default:
 			return nil, fmt.Errorf("no valid target scrape config defined for %q", cfg.JobName)
 		}
@@ -158,6 +177,15 @@ func NewTargetManagers(
 	if len(targetScrapeConfigs[HerokuDrainConfigs]) > 0 && herokuDrainMetrics == nil {
 		herokuDrainMetrics = heroku.NewMetrics(reg)
 	}
+	if len(targetScrapeConfigs[ObjectStoreConfigs]) > 0 && objectStoreMetrics == nil {
+		objectStoreMetrics = objectstore.NewMetrics(reg)
+	}
+	if len(targetScrapeConfigs[HTTPPollConfigs]) > 0 && httpPollMetrics == nil {
+		httpPollMetrics = httppoll.NewMetrics(reg)
+	}
+	if len(targetScrapeConfigs[KubernetesEventsConfigs]) > 0 && kubeEventsMetrics == nil {
+		kubeEventsMetrics = kubernetesevents.NewMetrics(reg)
+	}
 
 	for target, scrapeConfigs := range targetScrapeConfigs {
 		switch target {
@@ -234,6 +262,42 @@ func NewTargetManagers(
 				return nil, errors.Wrap(err, "failed to make Heroku drain target manager")
 			}
 			targetManagers = append(targetManagers, herokuDrainTargetManager)
+		case OTLPConfigs:
+			otlpTargetManager, err := otlp.NewTargetManager(reg, logger, client, scrapeConfigs)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to make OTLP target manager")
+			}
+			targetManagers = append(targetManagers, otlpTargetManager)
+		case ObjectStoreConfigs:
+			pos, err := getPositionFile()
+			if err != nil {
+				return nil, err
+			}
+			objectStoreTargetManager, err := objectstore.NewTargetManager(objectStoreMetrics, logger, pos, client, scrapeConfigs)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to make object store target manager")
+			}
+			targetManagers = append(targetManagers, objectStoreTargetManager)
+		case HTTPPollConfigs:
+			pos, err := getPositionFile()
+			if err != nil {
+				return nil, err
+			}
+			httpPollTargetManager, err := httppoll.NewTargetManager(httpPollMetrics, logger, pos, client, scrapeConfigs)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to make HTTP poll target manager")
+			}
+			targetManagers = append(targetManagers, httpPollTargetManager)
+		case KubernetesEventsConfigs:
+			pos, err := getPositionFile()
+			if err != nil {
+				return nil, err
+			}
+			kubeEventsTargetManager, err := kubernetesevents.NewTargetManager(kubeEventsMetrics, logger, pos, client, scrapeConfigs)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to make Kubernetes events target manager")
+			}
+			targetManagers = append(targetManagers, kubeEventsTargetManager)
 		case WindowsEventsConfigs:
 			windowsTargetManager, err := windows.NewTargetManager(reg, logger, client, scrapeConfigs)
 			if err != nil {
@@ -0,0 +1,45 @@
+package objectstore
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// Metrics holds a set of object store target metrics.
+type Metrics struct {
+	reg prometheus.Registerer
+
+	Entries *prometheus.CounterVec
+	Objects *prometheus.CounterVec
+	Errors  *prometheus.CounterVec
+}
+
+// NewMetrics creates a new set of object store target metrics. If reg is non-nil, the
+// metrics will be registered.
+func NewMetrics(reg prometheus.Registerer) *Metrics {
+	var m Metrics
+	m.reg = reg
+
+	m.Entries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "promtail",
+		Name:      "objectstore_target_entries_total",
+		Help:      "Total number of log lines read from objects by the object store target.",
+	}, []string{"job"})
+	m.Objects = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "promtail",
+		Name:      "objectstore_target_objects_total",
+		Help:      "Total number of objects fully read by the object store target.",
+	}, []string{"job"})
+	m.Errors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "promtail",
+		Name:      "objectstore_target_errors_total",
+		Help:      "Total number of errors listing or reading objects in the object store target.",
+	}, []string{"job"})
+
+	if reg != nil {
+		reg.MustRegister(
+			m.Entries,
+			m.Objects,
+			m.Errors,
+		)
+	}
+
+	return &m
+}
@@ -0,0 +1,119 @@
+package objectstore
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/prometheus/common/model"
+)
+
+// Presets available to parse well known AWS log files, matching the S3 parsers of lambda-promtail.
+const (
+	PresetELB         = "elb"
+	PresetCloudFront  = "cloudfront"
+	PresetVPCFlowLogs = "vpc_flow_logs"
+)
+
+const presetLabelsPrefix = "__aws_"
+
+type preset struct {
+	// value to use for the __aws_log_type label
+	logTypeLabel string
+	// regex matching the object key and exporting labels from it
+	keyRegex *regexp.Regexp
+	// regex that extracts the timestamp from the log line
+	timestampRegex *regexp.Regexp
+	// time format to use to convert the timestamp to time.Time
+	timestampFormat string
+	// how many lines to skip at the beginning of the object
+	skipHeaderCount int
+	// name of the key regex group to use as a value for the __aws_<logType>_owner label
+	ownerLabelKey string
+}
+
+var (
+	// AWS Elastic Load Balancers and VPC Flow Logs
+	// source: https://docs.aws.amazon.com/elasticloadbalancing/latest/application/load-balancer-access-logs.html#access-log-file-format
+	// source: https://docs.aws.amazon.com/vpc/latest/userguide/flow-logs-s3.html#flow-logs-s3-path
+	// example: my-bucket/AWSLogs/123456789012/elasticloadbalancing/us-east-1/2022/01/24/123456789012_elasticloadbalancing_us-east-1_app.my-loadbalancer.b13ea9d19f16d015_20220124T0000Z_0.0.0.0_2et2e1mx.log.gz
+	defaultKeyRegex       = regexp.MustCompile(`AWSLogs\/(?P<account_id>\d+)\/(?P<type>[a-zA-Z0-9_\-]+)\/(?P<region>[\w-]+)\/(?P<year>\d+)\/(?P<month>\d+)\/(?P<day>\d+)\/\d+\_(?:elasticloadbalancing|vpcflowlogs)\_\w+-\w+-\d_(?:(?P<lb_type>app|net)\.*?)?(?P<src>[a-zA-Z0-9\-]+)`)
+	defaultTimestampRegex = regexp.MustCompile(`(?P<timestamp>\d+-\d+-\d+T\d+:\d+:\d+(?:\.\d+Z)?)`)
+	// CloudFront
+	// source: https://docs.aws.amazon.com/AmazonCloudFront/latest/DeveloperGuide/AccessLogs.html#AccessLogsFileNaming
+	// example: example-prefix/EMLARXS9EXAMPLE.2019-11-14-20.RT4KCN4SGK9.gz
+	cloudfrontKeyRegex       = regexp.MustCompile(`(?P<prefix>.*)\/(?P<src>[A-Z0-9]+)\.(?P<year>\d+)-(?P<month>\d+)-(?P<day>\d+)-(.+)`)
+	cloudfrontTimestampRegex = regexp.MustCompile(`(?P<timestamp>\d+-\d+-\d+\s\d+:\d+:\d+)`)
+
+	presets = map[string]preset{
+		PresetELB: {
+			logTypeLabel:    "s3_lb",
+			keyRegex:        defaultKeyRegex,
+			ownerLabelKey:   "account_id",
+			timestampRegex:  defaultTimestampRegex,
+			timestampFormat: time.RFC3339,
+		},
+		PresetCloudFront: {
+			logTypeLabel:    "s3_cloudfront",
+			keyRegex:        cloudfrontKeyRegex,
+			ownerLabelKey:   "prefix",
+			timestampRegex:  cloudfrontTimestampRegex,
+			timestampFormat: "2006-01-02\x0915:04:05",
+			skipHeaderCount: 2,
+		},
+		PresetVPCFlowLogs: {
+			logTypeLabel:    "s3_vpc_flow",
+			keyRegex:        defaultKeyRegex,
+			ownerLabelKey:   "account_id",
+			timestampRegex:  defaultTimestampRegex,
+			timestampFormat: time.RFC3339,
+			skipHeaderCount: 1,
+		},
+	}
+)
+
+func getPreset(name string) (*preset, error) {
+	if name == "" {
+		return nil, nil
+	}
+	p, ok := presets[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown preset %q, it has to be one of %q, %q or %q", name, PresetELB, PresetCloudFront, PresetVPCFlowLogs)
+	}
+	return &p, nil
+}
+
+// labels returns the labels extracted from the object key, in the same way as lambda-promtail does.
+func (p *preset) labels(key string) model.LabelSet {
+	groups := map[string]string{}
+	match := p.keyRegex.FindStringSubmatch(key)
+	for i, name := range p.keyRegex.SubexpNames() {
+		if i != 0 && name != "" && i < len(match) && match[i] != "" {
+			groups[name] = match[i]
+		}
+	}
+	return model.LabelSet{
+		model.LabelName(presetLabelsPrefix + "log_type"):                               model.LabelValue(p.logTypeLabel),
+		model.LabelName(presetLabelsPrefix + p.logTypeLabel):                           model.LabelValue(groups["src"]),
+		model.LabelName(fmt.Sprintf("%s%s_owner", presetLabelsPrefix, p.logTypeLabel)): model.LabelValue(groups[p.ownerLabelKey]),
+	}
+}
+
+// timestamp extracts the timestamp of a log line, returning false if none is found.
+func (p *preset) timestamp(line string) (time.Time, bool, error) {
+	match := p.timestampRegex.FindStringSubmatch(line)
+	if len(match) == 0 {
+		return time.Time{}, false, nil
+	}
+	ts := match[1]
+	if p.timestampFormat == time.RFC3339 && !strings.HasSuffix(ts, "Z") {
+		// NLB logs and timestamps without fractional seconds don't have the Z suffix. RFC3339 requires a TZ specifier, use UTC
+		ts += "Z"
+	}
+	t, err := time.Parse(p.timestampFormat, ts)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	return t, true, nil
+}
@@ -0,0 +1,68 @@
+package objectstore
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Presets(t *testing.T) {
+	for _, tc := range []struct {
+		name           string
+		preset         string
+		key            string
+		line           string
+		expectedLabels model.LabelSet
+		expectedTime   time.Time
+	}{
+		{
+			name:   "application load balancer",
+			preset: PresetELB,
+			key:    "my-bucket/AWSLogs/123456789012/elasticloadbalancing/us-east-1/2022/01/24/123456789012_elasticloadbalancing_us-east-1_app.my-loadbalancer.b13ea9d19f16d015_20220124T0000Z_0.0.0.0_2et2e1mx.log.gz",
+			line:   `http 2018-07-02T22:23:00.186641Z app/my-loadbalancer/50dc6c495c0c9188 192.168.131.39:2817 10.0.0.1:80 0.000 0.001 0.000 200 200 34 366`,
+			expectedLabels: model.LabelSet{
+				"__aws_log_type":    "s3_lb",
+				"__aws_s3_lb":       "my-loadbalancer",
+				"__aws_s3_lb_owner": "123456789012",
+			},
+			expectedTime: time.Date(2018, 7, 2, 22, 23, 0, 186641000, time.UTC),
+		},
+		{
+			name:   "network load balancer",
+			preset: PresetELB,
+			key:    "my-bucket/prefix/AWSLogs/123456789012/elasticloadbalancing/us-east-2/2016/05/01/123456789012_elasticloadbalancing_us-east-2_net.my-loadbalancer.1234567890abcdef_201605010000Z_2soosksi.log.gz",
+			line:   `tls 2.0 2018-12-20T02:59:40 net/my-network-loadbalancer/c6e77e28c25b2234 g3d4b5e8bb8464cd 72.21.218.154:51341`,
+			expectedLabels: model.LabelSet{
+				"__aws_log_type":    "s3_lb",
+				"__aws_s3_lb":       "my-loadbalancer",
+				"__aws_s3_lb_owner": "123456789012",
+			},
+			expectedTime: time.Date(2018, 12, 20, 2, 59, 40, 0, time.UTC),
+		},
+		{
+			name:   "cloudfront",
+			preset: PresetCloudFront,
+			key:    "example-prefix/EMLARXS9EXAMPLE.2019-11-14-20.RT4KCN4SGK9.gz",
+			line:   "2019-12-04\t21:02:31\tLAX1\t392\t192.0.2.100\tGET\td111111abcdef8.cloudfront.net\t/index.html\t200",
+			expectedLabels: model.LabelSet{
+				"__aws_log_type":            "s3_cloudfront",
+				"__aws_s3_cloudfront":       "EMLARXS9EXAMPLE",
+				"__aws_s3_cloudfront_owner": "example-prefix",
+			},
+			expectedTime: time.Date(2019, 12, 4, 21, 2, 31, 0, time.UTC),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p, err := getPreset(tc.preset)
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedLabels, p.labels(tc.key))
+
+			ts, ok, err := p.timestamp(tc.line)
+			require.NoError(t, err)
+			require.True(t, ok)
+			require.Equal(t, tc.expectedTime, ts.UTC())
+		})
+	}
+}
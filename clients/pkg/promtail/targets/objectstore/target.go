@@ -0,0 +1,412 @@
+package objectstore
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/relabel"
+	"go.uber.org/atomic"
+
+	"github.com/grafana/loki/clients/pkg/promtail/api"
+	"github.com/grafana/loki/clients/pkg/promtail/positions"
+	"github.com/grafana/loki/clients/pkg/promtail/scrapeconfig"
+	"github.com/grafana/loki/clients/pkg/promtail/targets/file"
+	"github.com/grafana/loki/clients/pkg/promtail/targets/target"
+
+	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/storage/chunk/client"
+	"github.com/grafana/loki/pkg/storage/chunk/client/aws"
+	"github.com/grafana/loki/pkg/storage/chunk/client/azure"
+	"github.com/grafana/loki/pkg/storage/chunk/client/gcp"
+	"github.com/grafana/loki/pkg/storage/chunk/client/hedging"
+	"github.com/grafana/loki/pkg/storage/chunk/client/local"
+	"github.com/grafana/loki/pkg/storage/config"
+)
+
+const (
+	defaultPollInterval = time.Minute
+
+	// ObjectKeyLabel is the label holding the key of the object a log line was read from.
+	ObjectKeyLabel = "__objectstore_key"
+
+	positionKeyPrefix = "objectstore-"
+	positionDone      = "done"
+	noCompression     = "none"
+)
+
+var (
+	blobStorageMetricsOnce sync.Once
+	blobStorageMetrics     azure.BlobStorageMetrics
+)
+
+// Target reads log files stored in an object store bucket, polling its prefix for new objects.
+type Target struct {
+	metrics        *Metrics
+	logger         log.Logger
+	handler        api.EntryHandler
+	positions      positions.Positions
+	config         *scrapeconfig.ObjectStoreTargetConfig
+	relabelConfigs []*relabel.Config
+	jobName        string
+	preset         *preset
+
+	client  client.ObjectClient
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+	running *atomic.Bool
+
+	mtx  sync.Mutex
+	err  error
+	keys map[string]struct{} // keys of the objects tracked in the positions file
+}
+
+// NewTarget creates a new object store target, reading the objects of the configured bucket prefix.
+func NewTarget(
+	metrics *Metrics,
+	logger log.Logger,
+	handler api.EntryHandler,
+	position positions.Positions,
+	relabel []*relabel.Config,
+	jobName string,
+	config *scrapeconfig.ObjectStoreTargetConfig,
+) (*Target, error) {
+	if config.PollInterval == 0 {
+		config.PollInterval = defaultPollInterval
+	}
+	p, err := getPreset(config.Preset)
+	if err != nil {
+		return nil, err
+	}
+	objectClient, err := newObjectClient(config)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t := &Target{
+		metrics:        metrics,
+		logger:         logger,
+		handler:        handler,
+		positions:      position,
+		config:         config,
+		relabelConfigs: relabel,
+		jobName:        jobName,
+		preset:         p,
+
+		client:  objectClient,
+		ctx:     ctx,
+		cancel:  cancel,
+		running: atomic.NewBool(false),
+		keys:    map[string]struct{}{},
+	}
+	// Track the objects recorded by a previous run, so that their positions are removed too once they are gone.
+	prefix := t.positionKey("")
+	for _, k := range position.Keys(prefix) {
+		t.keys[strings.TrimPrefix(k, prefix)] = struct{}{}
+	}
+	t.start()
+	return t, nil
+}
+
+func newObjectClient(cfg *scrapeconfig.ObjectStoreTargetConfig) (client.ObjectClient, error) {
+	switch cfg.Storage {
+	case config.StorageTypeS3, config.StorageTypeAWS:
+		return aws.NewS3ObjectClient(cfg.S3, hedging.Config{})
+	case config.StorageTypeGCS:
+		return gcp.NewGCSObjectClient(context.Background(), cfg.GCS, hedging.Config{})
+	case config.StorageTypeAzure:
+		// The blob storage metrics are registered globally, so they can only be created once.
+		blobStorageMetricsOnce.Do(func() {
+			blobStorageMetrics = azure.NewBlobStorageMetrics()
+		})
+		return azure.NewBlobStorage(&cfg.Azure, blobStorageMetrics, hedging.Config{})
+	case config.StorageTypeFileSystem:
+		return local.NewFSObjectClient(cfg.Filesystem)
+	default:
+		return nil, fmt.Errorf("unsupported object store %q, it has to be one of %q, %q, %q or %q", cfg.Storage,
+			config.StorageTypeS3, config.StorageTypeGCS, config.StorageTypeAzure, config.StorageTypeFileSystem)
+	}
+}
+
+func (t *Target) start() {
+	t.wg.Add(1)
+	t.running.Store(true)
+	go func() {
+		defer func() {
+			t.wg.Done()
+			t.running.Store(false)
+		}()
+
+		ticker := time.NewTicker(t.config.PollInterval)
+		defer ticker.Stop()
+		for {
+			t.poll()
+			select {
+			case <-ticker.C:
+			case <-t.ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// poll lists the bucket prefix and reads the objects which have not been fully read yet.
+func (t *Target) poll() {
+	objects, _, err := t.client.List(t.ctx, t.config.Prefix, "")
+	if err != nil {
+		t.setErr(err)
+		level.Error(t.logger).Log("msg", "failed to list objects", "prefix", t.config.Prefix, "err", err)
+		return
+	}
+	// Read objects in lexical order, which is the order of most log files keys.
+	sort.Slice(objects, func(i, j int) bool { return objects[i].Key < objects[j].Key })
+
+	failed := false
+	listed := make(map[string]struct{}, len(objects))
+	for _, obj := range objects {
+		if t.ctx.Err() != nil {
+			return
+		}
+		listed[obj.Key] = struct{}{}
+
+		offset, done, err := t.position(obj.Key)
+		if err != nil {
+			level.Warn(t.logger).Log("msg", "invalid position, reading object from the start", "key", obj.Key, "err", err)
+		}
+		if done {
+			continue
+		}
+		if err := t.read(obj.Key, offset); err != nil {
+			t.setErr(err)
+			failed = true
+			level.Error(t.logger).Log("msg", "failed to read object", "key", obj.Key, "err", err)
+		}
+	}
+
+	// Forget the positions of the objects which are gone from the bucket.
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	for key := range t.keys {
+		if _, ok := listed[key]; !ok {
+			t.positions.Remove(t.positionKey(key))
+			delete(t.keys, key)
+		}
+	}
+	for key := range listed {
+		t.keys[key] = struct{}{}
+	}
+	if !failed {
+		t.err = nil
+	}
+}
+
+// read sends the lines of an object starting at the given byte offset of its (decompressed) content,
+// recording the offset of the lines read in the positions file.
+func (t *Target) read(key string, offset int64) error {
+	rc, _, err := t.client.GetObject(t.ctx, key)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	var r io.Reader = rc
+	if format := t.compression(key); format != "" {
+		dr, err := file.NewDecompressionReader(rc, format)
+		if err != nil {
+			return err
+		}
+		defer dr.Close()
+		r = dr
+	}
+
+	lbs, keep := t.labels(key)
+	skipHeaderCount := 0
+	if t.preset != nil {
+		skipHeaderCount = t.preset.skipHeaderCount
+	}
+
+	var read int64
+	br := bufio.NewReader(r)
+	for line := 1; t.ctx.Err() == nil; line++ {
+		b, err := br.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return err
+		}
+		if len(b) > 0 {
+			read += int64(len(b))
+			// Lines up to the offset have already been sent, they are only read to count them.
+			if read > offset {
+				if keep && line > skipHeaderCount && !t.send(lbs, strings.TrimRight(string(b), "\r\n")) {
+					return nil
+				}
+				t.positions.PutString(t.positionKey(key), strconv.FormatInt(read, 10))
+			}
+		}
+		if err == io.EOF {
+			t.positions.PutString(t.positionKey(key), fmt.Sprintf("%d:%s", read, positionDone))
+			t.metrics.Objects.WithLabelValues(t.jobName).Inc()
+			return nil
+		}
+	}
+	return nil
+}
+
+// send sends a log line to the pipeline, with the timestamp parsed by the preset if any.
+// It returns false if the target was stopped before the line could be sent.
+func (t *Target) send(lbs model.LabelSet, line string) bool {
+	ts := time.Now()
+	if t.preset != nil {
+		parsed, ok, err := t.preset.timestamp(line)
+		if err != nil {
+			level.Debug(t.logger).Log("msg", "failed to parse timestamp, using current time", "err", err)
+		} else if ok {
+			ts = parsed
+		}
+	}
+	entry := api.Entry{
+		Labels: lbs.Clone(),
+		Entry: logproto.Entry{
+			Timestamp: ts,
+			Line:      line,
+		},
+	}
+	select {
+	case t.handler.Chan() <- entry:
+	case <-t.ctx.Done():
+		return false
+	}
+	t.metrics.Entries.WithLabelValues(t.jobName).Inc()
+	return true
+}
+
+// labels returns the labels of the lines of an object after relabeling, or false if the object is dropped.
+func (t *Target) labels(key string) (model.LabelSet, bool) {
+	lb := labels.NewBuilder(nil)
+	lb.Set(ObjectKeyLabel, key)
+	if t.preset != nil {
+		for k, v := range t.preset.labels(key) {
+			lb.Set(string(k), string(v))
+		}
+	}
+	for k, v := range t.config.Labels {
+		lb.Set(string(k), string(v))
+	}
+
+	processed, keep := relabel.Process(lb.Labels(), t.relabelConfigs...)
+	if !keep {
+		return nil, false
+	}
+
+	filtered := model.LabelSet{}
+	for _, lbl := range processed {
+		if strings.HasPrefix(lbl.Name, "__") {
+			continue
+		}
+		filtered[model.LabelName(lbl.Name)] = model.LabelValue(lbl.Value)
+	}
+	return filtered, true
+}
+
+// compression returns the compression format of an object, guessing it from its key extension if not configured.
+func (t *Target) compression(key string) string {
+	switch t.config.Compression {
+	case noCompression:
+		return ""
+	case "":
+		switch ext := path.Ext(key); ext {
+		case ".gz", ".z", ".bz2", ".zst":
+			return strings.TrimPrefix(ext, ".")
+		}
+		return ""
+	default:
+		return t.config.Compression
+	}
+}
+
+// positionKey returns the key of the position of an object. The job name is escaped so that
+// the keys of a job are never a prefix of the keys of another one.
+func (t *Target) positionKey(key string) string {
+	return positions.CursorKey(positionKeyPrefix + url.PathEscape(t.jobName) + "/" + key)
+}
+
+// position returns the byte offset read so far in an object and whether it has been fully read.
+func (t *Target) position(key string) (int64, bool, error) {
+	pos := t.positions.GetString(t.positionKey(key))
+	if pos == "" {
+		return 0, false, nil
+	}
+	pos, done := strings.CutSuffix(pos, ":"+positionDone)
+	offset, err := strconv.ParseInt(pos, 10, 64)
+	if err != nil {
+		return 0, false, err
+	}
+	return offset, done, nil
+}
+
+func (t *Target) setErr(err error) {
+	t.metrics.Errors.WithLabelValues(t.jobName).Inc()
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	t.err = err
+}
+
+// Stop stops the target, waiting for the object being read to be interrupted.
+func (t *Target) Stop() {
+	t.cancel()
+	t.wg.Wait()
+	t.client.Stop()
+	t.handler.Stop()
+}
+
+// Type returns ObjectStoreTargetType.
+func (t *Target) Type() target.TargetType {
+	return target.ObjectStoreTargetType
+}
+
+// DiscoveredLabels returns the set of labels discovered by the object store target, which
+// is always nil. Implements Target.
+func (t *Target) DiscoveredLabels() model.LabelSet {
+	return nil
+}
+
+// Labels returns the set of labels that statically apply to all log entries
+// produced by the object store target.
+func (t *Target) Labels() model.LabelSet {
+	return t.config.Labels
+}
+
+// Ready indicates whether or not the object store target is polling its bucket prefix.
+func (t *Target) Ready() bool {
+	return t.running.Load()
+}
+
+// Details returns target-specific details.
+func (t *Target) Details() interface{} {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	var errMsg string
+	if t.err != nil {
+		errMsg = t.err.Error()
+	}
+	return map[string]string{
+		"storage": t.config.Storage,
+		"prefix":  t.config.Prefix,
+		"preset":  t.config.Preset,
+		"objects": strconv.Itoa(len(t.keys)),
+		"error":   errMsg,
+	}
+}
@@ -0,0 +1,236 @@
+package objectstore
+
+import (
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/relabel"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/clients/pkg/promtail/api"
+	"github.com/grafana/loki/clients/pkg/promtail/client/fake"
+	"github.com/grafana/loki/clients/pkg/promtail/positions"
+	"github.com/grafana/loki/clients/pkg/promtail/scrapeconfig"
+	"github.com/grafana/loki/clients/pkg/promtail/targets/testutils"
+
+	"github.com/grafana/loki/pkg/storage/chunk/client/local"
+)
+
+func writeObject(t *testing.T, dir, key, content string, compress bool) {
+	p := filepath.Join(dir, filepath.FromSlash(key))
+	require.NoError(t, os.MkdirAll(filepath.Dir(p), 0o755))
+	data := []byte(content)
+	if compress {
+		var buf bytes.Buffer
+		w := gzip.NewWriter(&buf)
+		_, err := w.Write(data)
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+		data = buf.Bytes()
+	}
+	require.NoError(t, os.WriteFile(p, data, 0o644))
+}
+
+func Test_ObjectStoreTarget(t *testing.T) {
+	var (
+		w             = log.NewSyncWriter(os.Stderr)
+		logger        = log.NewLogfmtLogger(w)
+		bucket        = t.TempDir()
+		positionsFile = filepath.Join(t.TempDir(), "positions.yml")
+		metrics       = NewMetrics(prometheus.NewRegistry())
+	)
+	writeObject(t, bucket, "logs/a.log", "a1\na2\n", false)
+	writeObject(t, bucket, "logs/b.log.gz", "b1\r\nb2", true)
+	writeObject(t, bucket, "other/c.log", "c1\n", false)
+
+	cfg := &scrapeconfig.ObjectStoreTargetConfig{
+		Storage:      "filesystem",
+		Filesystem:   local.FSConfig{Directory: bucket},
+		Prefix:       "logs/",
+		PollInterval: 10 * time.Millisecond,
+		Labels:       model.LabelSet{"job": "objectstore"},
+	}
+	rlbl := []*relabel.Config{
+		{
+			SourceLabels: model.LabelNames{ObjectKeyLabel},
+			Regex:        relabel.MustNewRegexp("(.*)"),
+			TargetLabel:  "key",
+			Replacement:  "$1",
+			Action:       relabel.Replace,
+		},
+	}
+
+	ps := testutils.NewPositions(t, logger, positionsFile)
+	client := fake.New(func() {})
+	ta, err := NewTarget(metrics, logger, client, ps, rlbl, "job1", cfg)
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		return len(client.Received()) == 4
+	}, 5*time.Second, 10*time.Millisecond)
+	require.ElementsMatch(t, []string{"a1", "a2", "b1", "b2"}, testutils.ReceivedLines(client))
+	for _, e := range client.Received() {
+		require.Equal(t, model.LabelValue("objectstore"), e.Labels["job"])
+		require.Contains(t, []model.LabelValue{"logs/a.log", "logs/b.log.gz"}, e.Labels["key"])
+		require.NotContains(t, e.Labels, model.LabelName(ObjectKeyLabel))
+	}
+
+	offset, done, err := ta.position("logs/a.log")
+	require.NoError(t, err)
+	require.True(t, done)
+	require.Equal(t, int64(6), offset)
+
+	// Objects fully read are not read again.
+	time.Sleep(50 * time.Millisecond)
+	require.Len(t, client.Received(), 4)
+	ta.Stop()
+	ps.Stop()
+
+	// Restart from a partially read object: only the lines after the recorded offset are sent.
+	writeObject(t, bucket, "logs/d.log", "d1\nd2\nd3\n", false)
+	ps = testutils.NewPositions(t, logger, positionsFile)
+	defer ps.Stop()
+	ps.PutString(ta.positionKey("logs/d.log"), "3")
+
+	client = fake.New(func() {})
+	ta, err = NewTarget(metrics, logger, client, ps, rlbl, "job1", cfg)
+	require.NoError(t, err)
+	defer ta.Stop()
+
+	require.Eventually(t, func() bool {
+		return len(client.Received()) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Equal(t, []string{"d2", "d3"}, testutils.ReceivedLines(client))
+
+	// Positions of deleted objects are removed.
+	require.NoError(t, os.Remove(filepath.Join(bucket, "logs", "a.log")))
+	require.Eventually(t, func() bool {
+		return ps.GetString(ta.positionKey("logs/a.log")) == ""
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func Test_ObjectStoreTargetRemovesPositionsAfterRestart(t *testing.T) {
+	var (
+		logger  = log.NewNopLogger()
+		bucket  = t.TempDir()
+		metrics = NewMetrics(nil)
+		cfg     = &scrapeconfig.ObjectStoreTargetConfig{
+			Storage:      "filesystem",
+			Filesystem:   local.FSConfig{Directory: bucket},
+			PollInterval: 10 * time.Millisecond,
+		}
+	)
+	writeObject(t, bucket, "b.log", "b1\n", false)
+
+	ps := testutils.NewPositions(t, logger, filepath.Join(t.TempDir(), "positions.yml"))
+	defer ps.Stop()
+	// Positions recorded by a previous run, for an object deleted since then, and by a job whose
+	// name starts with the name of this one.
+	ps.PutString(positions.CursorKey("objectstore-job1/a.log"), "3:done")
+	ps.PutString(positions.CursorKey("objectstore-job1-other/a.log"), "3:done")
+
+	client := fake.New(func() {})
+	ta, err := NewTarget(metrics, logger, client, ps, nil, "job1", cfg)
+	require.NoError(t, err)
+	defer ta.Stop()
+
+	require.Eventually(t, func() bool {
+		_, done, _ := ta.position("b.log")
+		return done
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Equal(t, "", ps.GetString(ta.positionKey("a.log")))
+	require.Equal(t, "3:done", ps.GetString(positions.CursorKey("objectstore-job1-other/a.log")))
+}
+
+func Test_ObjectStoreTargetStopWhileSending(t *testing.T) {
+	var (
+		logger = log.NewNopLogger()
+		bucket = t.TempDir()
+		cfg    = &scrapeconfig.ObjectStoreTargetConfig{
+			Storage:      "filesystem",
+			Filesystem:   local.FSConfig{Directory: bucket},
+			PollInterval: 10 * time.Millisecond,
+		}
+	)
+	writeObject(t, bucket, "a.log", "a1\na2\n", false)
+
+	ps := testutils.NewPositions(t, logger, filepath.Join(t.TempDir(), "positions.yml"))
+	defer ps.Stop()
+	// Nothing reads the entries sent, so the target is blocked sending the first line.
+	handler := api.NewEntryHandler(make(chan api.Entry), func() {})
+	ta, err := NewTarget(NewMetrics(nil), logger, handler, ps, nil, "job1", cfg)
+	require.NoError(t, err)
+
+	stopped := make(chan struct{})
+	go func() {
+		ta.Stop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("target did not stop while blocked sending")
+	}
+	// The line which was not sent is not recorded as read.
+	require.Equal(t, "", ps.GetString(ta.positionKey("a.log")))
+}
+
+func Test_ObjectStoreTargetPreset(t *testing.T) {
+	var (
+		logger  = log.NewNopLogger()
+		bucket  = t.TempDir()
+		metrics = NewMetrics(nil)
+		key     = "AWSLogs/123456789012/vpcflowlogs/us-east-1/2018/06/20/123456789012_vpcflowlogs_us-east-1_fl-1234abcd_20180620T1620Z_fe123456.log"
+	)
+	writeObject(t, bucket, key, "version account-id interface-id\n2 123456789012 eni-1235b8ca123456789 2018-06-20T16:20:00Z\n", false)
+
+	cfg := &scrapeconfig.ObjectStoreTargetConfig{
+		Storage:      "filesystem",
+		Filesystem:   local.FSConfig{Directory: bucket},
+		PollInterval: 10 * time.Millisecond,
+		Preset:       PresetVPCFlowLogs,
+		Compression:  "none",
+	}
+	rlbl := []*relabel.Config{
+		{
+			Action:      relabel.LabelMap,
+			Regex:       relabel.MustNewRegexp("__aws_(.*)"),
+			Replacement: "$1",
+		},
+	}
+
+	ps := testutils.NewPositions(t, logger, filepath.Join(t.TempDir(), "positions.yml"))
+	defer ps.Stop()
+	client := fake.New(func() {})
+	ta, err := NewTarget(metrics, logger, client, ps, rlbl, "job1", cfg)
+	require.NoError(t, err)
+	defer ta.Stop()
+
+	require.Eventually(t, func() bool {
+		return len(client.Received()) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	e := client.Received()[0]
+	require.Equal(t, "2 123456789012 eni-1235b8ca123456789 2018-06-20T16:20:00Z", e.Line)
+	require.Equal(t, time.Date(2018, 6, 20, 16, 20, 0, 0, time.UTC), e.Timestamp.UTC())
+	require.Equal(t, model.LabelSet{
+		"log_type":          "s3_vpc_flow",
+		"s3_vpc_flow":       "fl-1234abcd",
+		"s3_vpc_flow_owner": "123456789012",
+	}, e.Labels)
+}
+
+func Test_NewTargetErrors(t *testing.T) {
+	metrics := NewMetrics(nil)
+	_, err := NewTarget(metrics, log.NewNopLogger(), fake.New(func() {}), nil, nil, "job1", &scrapeconfig.ObjectStoreTargetConfig{Storage: "tape"})
+	require.ErrorContains(t, err, "unsupported object store")
+
+	_, err = NewTarget(metrics, log.NewNopLogger(), fake.New(func() {}), nil, nil, "job1", &scrapeconfig.ObjectStoreTargetConfig{Storage: "filesystem", Preset: "foo"})
+	require.ErrorContains(t, err, "unknown preset")
+}
@@ -0,0 +1,236 @@
+package otlp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/flagext"
+	"github.com/grafana/dskit/server"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/relabel"
+	promql_parser "github.com/prometheus/prometheus/promql/parser"
+	"go.opentelemetry.io/collector/pdata/plog/plogotlp"
+
+	"github.com/grafana/loki/clients/pkg/promtail/api"
+	"github.com/grafana/loki/clients/pkg/promtail/scrapeconfig"
+	"github.com/grafana/loki/clients/pkg/promtail/targets/serverutils"
+	"github.com/grafana/loki/clients/pkg/promtail/targets/target"
+
+	"github.com/grafana/loki/pkg/loghttp/push"
+	"github.com/grafana/loki/pkg/logproto"
+	util_log "github.com/grafana/loki/pkg/util/log"
+)
+
+// Target receives OpenTelemetry logs over OTLP/HTTP and OTLP/gRPC.
+type Target struct {
+	plogotlp.UnimplementedGRPCServer
+
+	logger         log.Logger
+	handler        api.EntryHandler
+	config         *scrapeconfig.OTLPTargetConfig
+	otlpConfig     push.OTLPConfig
+	relabelConfigs []*relabel.Config
+	jobName        string
+	server         *server.Server
+}
+
+// NewTarget creates a new OTLP target, listening for logs on the configured server.
+func NewTarget(logger log.Logger, handler api.EntryHandler, relabel []*relabel.Config, jobName string, config *scrapeconfig.OTLPTargetConfig) (*Target, error) {
+	if err := config.OTLP.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid otlp_config for OTLP target: %w", err)
+	}
+
+	// Apply the default resource attributes stored as index labels, like the distributor does.
+	var globalOTLPConfig push.GlobalOTLPConfig
+	flagext.DefaultValues(&globalOTLPConfig)
+	otlpConfig := config.OTLP
+	otlpConfig.ApplyGlobalOTLPConfig(globalOTLPConfig)
+
+	t := &Target{
+		logger:         logger,
+		handler:        handler,
+		config:         config,
+		otlpConfig:     otlpConfig,
+		relabelConfigs: relabel,
+		jobName:        jobName,
+	}
+
+	mergedServerConfigs, err := serverutils.MergeWithDefaults(config.Server)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse configs and override defaults when configuring OTLP target: %w", err)
+	}
+	// Set the config to the new combined config.
+	config.Server = mergedServerConfigs
+
+	if err := t.run(); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+func (t *Target) run() error {
+	level.Info(t.logger).Log("msg", "starting OTLP server", "job", t.jobName)
+	// To prevent metric collisions because all metrics are going to be registered in the global Prometheus registry.
+	t.config.Server.MetricsNamespace = "promtail_" + t.jobName
+
+	// We don't want the /debug and /metrics endpoints running
+	t.config.Server.RegisterInstrumentation = false
+
+	// The logger registers a metric which will cause a duplicate registry panic unless we provide an empty registry
+	// The metric created is for counting log lines and isn't likely to be missed.
+	serverCfg := &t.config.Server
+	serverCfg.Log = util_log.InitLogger(serverCfg, prometheus.NewRegistry(), false)
+
+	// Set new registry for upcoming metric server
+	// If not, it'll likely panic when the tool gets reloaded.
+	if t.config.Server.Registerer == nil {
+		t.config.Server.Registerer = prometheus.NewRegistry()
+	}
+
+	srv, err := server.New(t.config.Server)
+	if err != nil {
+		return err
+	}
+
+	t.server = srv
+	t.server.HTTP.Path("/v1/logs").Methods("POST").Handler(http.HandlerFunc(t.handleHTTP))
+	plogotlp.RegisterGRPCServer(t.server.GRPC, t)
+
+	go func() {
+		err := srv.Run()
+		if err != nil {
+			level.Error(t.logger).Log("msg", "OTLP server shutdown with error", "err", err)
+		}
+	}()
+
+	return nil
+}
+
+// handleHTTP handles OTLP/HTTP export requests, encoded either as protobuf or JSON.
+func (t *Target) handleHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := util_log.WithContext(r.Context(), util_log.Logger)
+	req, err := push.ParseRequest(logger, "", r, push.EmptyTenantsRetention{}, t, push.ParseOTLPRequest, nil)
+	if err != nil {
+		level.Warn(t.logger).Log("msg", "failed to parse incoming OTLP request", "err", err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := t.handle(req); err != nil {
+		level.Warn(t.logger).Log("msg", "at least one entry in the OTLP request failed to process", "err", err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Export handles OTLP/gRPC export requests. Implements plogotlp.GRPCServer.
+func (t *Target) Export(ctx context.Context, req plogotlp.ExportRequest) (plogotlp.ExportResponse, error) {
+	if err := t.handle(push.OTLPToLokiPushRequest(ctx, req.Logs(), t.otlpConfig)); err != nil {
+		level.Warn(t.logger).Log("msg", "at least one entry in the OTLP request failed to process", "err", err.Error())
+		return plogotlp.NewExportResponse(), err
+	}
+	return plogotlp.NewExportResponse(), nil
+}
+
+// OTLPConfig returns the OTLP config of the target. Implements push.Limits.
+func (t *Target) OTLPConfig(string) push.OTLPConfig {
+	return t.otlpConfig
+}
+
+// handle relabels the streams of the push request and sends their entries to the pipeline.
+func (t *Target) handle(req *logproto.PushRequest) error {
+	var lastErr error
+	for _, stream := range req.Streams {
+		ls, err := promql_parser.ParseMetric(stream.Labels)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		sort.Sort(ls)
+
+		lb := labels.NewBuilder(ls)
+
+		// Add configured labels
+		for k, v := range t.config.Labels {
+			lb.Set(string(k), string(v))
+		}
+
+		// Apply relabeling
+		processed, keep := relabel.Process(lb.Labels(), t.relabelConfigs...)
+		if !keep || len(processed) == 0 {
+			continue
+		}
+
+		// Convert to model.LabelSet
+		filtered := model.LabelSet{}
+		for i := range processed {
+			if strings.HasPrefix(processed[i].Name, "__") {
+				continue
+			}
+			filtered[model.LabelName(processed[i].Name)] = model.LabelValue(processed[i].Value)
+		}
+
+		for _, entry := range stream.Entries {
+			e := api.Entry{
+				Labels: filtered.Clone(),
+				Entry: logproto.Entry{
+					Line:               entry.Line,
+					StructuredMetadata: entry.StructuredMetadata,
+				},
+			}
+			if t.config.KeepTimestamp {
+				e.Timestamp = entry.Timestamp
+			} else {
+				e.Timestamp = time.Now()
+			}
+			t.handler.Chan() <- e
+		}
+	}
+	return lastErr
+}
+
+// Type returns OTLPTargetType.
+func (t *Target) Type() target.TargetType {
+	return target.OTLPTargetType
+}
+
+// Ready indicates whether or not the OTLP target is ready to be read from.
+func (t *Target) Ready() bool {
+	return true
+}
+
+// DiscoveredLabels returns the set of labels discovered by the OTLP target, which
+// is always nil. Implements Target.
+func (t *Target) DiscoveredLabels() model.LabelSet {
+	return nil
+}
+
+// Labels returns the set of labels that statically apply to all log entries
+// produced by the OTLP target.
+func (t *Target) Labels() model.LabelSet {
+	return t.config.Labels
+}
+
+// Details returns target-specific details.
+func (t *Target) Details() interface{} {
+	return map[string]string{}
+}
+
+// Stop shuts down the OTLP target.
+func (t *Target) Stop() error {
+	level.Info(t.logger).Log("msg", "stopping OTLP server", "job", t.jobName)
+	t.server.Shutdown()
+	t.handler.Stop()
+	return nil
+}
@@ -0,0 +1,151 @@
+package otlp
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/server"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/relabel"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/plog/plogotlp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/grafana/loki/clients/pkg/promtail/client/fake"
+	"github.com/grafana/loki/clients/pkg/promtail/scrapeconfig"
+
+	"github.com/grafana/loki/pkg/loghttp/push"
+	"github.com/grafana/loki/pkg/logproto"
+)
+
+const localhost = "127.0.0.1"
+
+func freePort(t *testing.T) int {
+	addr, err := net.ResolveTCPAddr("tcp", localhost+":0")
+	require.NoError(t, err)
+	l, err := net.ListenTCP("tcp", addr)
+	require.NoError(t, err)
+	port := l.Addr().(*net.TCPAddr).Port
+	require.NoError(t, l.Close())
+	return port
+}
+
+func testLogs(now time.Time) plog.Logs {
+	ld := plog.NewLogs()
+	rl := ld.ResourceLogs().AppendEmpty()
+	rl.Resource().Attributes().PutStr("service.name", "checkout")
+	rl.Resource().Attributes().PutStr("host.name", "node-1")
+	sl := rl.ScopeLogs().AppendEmpty()
+	sl.Scope().SetName("checkout-logger")
+	for i := 0; i < 2; i++ {
+		lr := sl.LogRecords().AppendEmpty()
+		lr.Body().SetStr(fmt.Sprintf("line%d", i))
+		lr.SetTimestamp(pcommon.NewTimestampFromTime(now.Add(time.Duration(i) * time.Second)))
+		lr.SetSeverityText("INFO")
+		lr.Attributes().PutStr("user", "jane")
+	}
+	return ld
+}
+
+func TestOTLPTarget(t *testing.T) {
+	w := log.NewSyncWriter(os.Stderr)
+	logger := log.NewLogfmtLogger(w)
+
+	eh := fake.New(func() {})
+	defer eh.Stop()
+
+	httpPort, grpcPort := freePort(t), freePort(t)
+
+	// Adjust some of the defaults
+	defaults := server.Config{}
+	defaults.RegisterFlags(flag.NewFlagSet("empty", flag.ContinueOnError))
+	defaults.HTTPListenAddress = localhost
+	defaults.HTTPListenPort = httpPort
+	defaults.GRPCListenAddress = localhost
+	defaults.GRPCListenPort = grpcPort
+
+	config := &scrapeconfig.OTLPTargetConfig{
+		Server: defaults,
+		Labels: model.LabelSet{
+			"otlpserver": "otlpserver1",
+			"dropme":     "label",
+		},
+		KeepTimestamp: true,
+		OTLP: push.OTLPConfig{
+			LogAttributes: []push.AttributesConfig{
+				{
+					Action:     push.Drop,
+					Attributes: []string{"user"},
+				},
+			},
+		},
+	}
+
+	rlbl := []*relabel.Config{
+		{
+			Action: relabel.LabelDrop,
+			Regex:  relabel.MustNewRegexp("dropme"),
+		},
+	}
+
+	tgt, err := NewTarget(logger, eh, rlbl, "job1", config)
+	require.NoError(t, err)
+	defer func() {
+		_ = tgt.Stop()
+	}()
+
+	now := time.Unix(1700000000, 0).UTC()
+
+	// Send logs over OTLP/HTTP
+	body, err := plogotlp.NewExportRequestFromLogs(testLogs(now)).MarshalProto()
+	require.NoError(t, err)
+	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("http://%s:%d/v1/logs", localhost, httpPort), bytes.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	res, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	require.NoError(t, res.Body.Close())
+	require.Equal(t, http.StatusNoContent, res.StatusCode)
+
+	// Send logs over OTLP/gRPC
+	conn, err := grpc.Dial(fmt.Sprintf("%s:%d", localhost, grpcPort), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+	_, err = plogotlp.NewGRPCClient(conn).Export(context.Background(), plogotlp.NewExportRequestFromLogs(testLogs(now)))
+	require.NoError(t, err)
+
+	// Wait for them to appear in the test handler
+	countdown := 10000
+	for len(eh.Received()) != 4 && countdown > 0 {
+		time.Sleep(1 * time.Millisecond)
+		countdown--
+	}
+	require.Len(t, eh.Received(), 4)
+
+	expectedLabels := model.LabelSet{
+		"otlpserver":   "otlpserver1",
+		"service_name": "checkout",
+	}
+	expectedStructuredMetadata := []logproto.LabelAdapter{
+		{Name: "severity_text", Value: "INFO"},
+		{Name: "host_name", Value: "node-1"},
+		{Name: "scope_name", Value: "checkout-logger"},
+	}
+	for i, e := range eh.Received() {
+		require.Equal(t, expectedLabels, e.Labels)
+		require.Equal(t, fmt.Sprintf("line%d", i%2), e.Line)
+		require.Equal(t, now.Add(time.Duration(i%2)*time.Second), e.Timestamp.UTC())
+		require.Equal(t, expectedStructuredMetadata, []logproto.LabelAdapter(e.StructuredMetadata))
+	}
+}
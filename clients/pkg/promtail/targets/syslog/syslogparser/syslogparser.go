@@ -2,12 +2,16 @@ package syslogparser
 
 import (
 	"bufio"
+	"bytes"
+	"errors"
 	"fmt"
 	"io"
+	"strconv"
 
 	"github.com/influxdata/go-syslog/v3"
 	"github.com/influxdata/go-syslog/v3/nontransparent"
 	"github.com/influxdata/go-syslog/v3/octetcounting"
+	"github.com/influxdata/go-syslog/v3/rfc3164"
 )
 
 // ParseStream parses a rfc5424 syslog stream from the given Reader, calling
@@ -33,3 +37,101 @@ func ParseStream(r io.Reader, callback func(res *syslog.Result), maxMessageLengt
 
 	return nil
 }
+
+// ParseRFC3164Stream parses a rfc3164 syslog stream from the given Reader,
+// calling the callback function with the parsed messages. The parser
+// automatically detects octet counting. Timestamps without a year are
+// assumed to be in the current year.
+// The function returns on EOF or unrecoverable errors.
+func ParseRFC3164Stream(r io.Reader, callback func(res *syslog.Result), maxMessageLength int) error {
+	buf := bufio.NewReaderSize(r, 1<<10)
+
+	b, err := buf.ReadByte()
+	if err != nil {
+		return err
+	}
+	_ = buf.UnreadByte()
+
+	var next func(*bufio.Reader, int) ([]byte, error)
+	if b == '<' {
+		next = readLine
+	} else if b >= '0' && b <= '9' {
+		next = readOctetCounted
+	} else {
+		return fmt.Errorf("invalid or unsupported framing. first byte: '%s'", string(b))
+	}
+
+	parser := rfc3164.NewParser(rfc3164.WithBestEffort(), rfc3164.WithYear(rfc3164.CurrentYear{}), rfc3164.WithRFC3339())
+	for {
+		frame, err := next(buf, maxMessageLength)
+		if len(frame) > 0 {
+			msg, parseErr := parser.Parse(frame)
+			callback(&syslog.Result{Message: msg, Error: parseErr})
+		}
+		var tooLong *messageTooLongError
+		if errors.As(err, &tooLong) {
+			callback(&syslog.Result{Error: err})
+			continue
+		}
+		if err != nil {
+			if !errors.Is(err, io.EOF) {
+				callback(&syslog.Result{Error: err})
+			}
+			return nil
+		}
+	}
+}
+
+// messageTooLongError is returned for the octet counted messages longer than
+// the max message length, which are skipped.
+type messageTooLongError struct {
+	length, maxMessageLength int
+}
+
+func (e *messageTooLongError) Error() string {
+	return fmt.Sprintf("message too long to parse. was size %d, max length %d", e.length, e.maxMessageLength)
+}
+
+// readLine reads a newline separated message, truncated to the max message length.
+func readLine(r *bufio.Reader, maxMessageLength int) ([]byte, error) {
+	var line []byte
+	for {
+		chunk, err := r.ReadSlice('\n')
+		if len(line) < maxMessageLength {
+			line = append(line, chunk[:min(len(chunk), maxMessageLength-len(line))]...)
+		}
+		if !errors.Is(err, bufio.ErrBufferFull) {
+			return bytes.TrimRight(line, "\r\n"), err
+		}
+	}
+}
+
+// readOctetCounted reads a message prefixed by its length. Messages longer
+// than the max message length are skipped.
+func readOctetCounted(r *bufio.Reader, maxMessageLength int) ([]byte, error) {
+	prefix, err := r.ReadSlice(' ')
+	// Some senders also separate the messages with newlines.
+	prefix = bytes.TrimSpace(prefix)
+	if err != nil {
+		if errors.Is(err, io.EOF) && len(prefix) > 0 {
+			return nil, io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	length, err := strconv.Atoi(string(prefix))
+	if err != nil || length <= 0 {
+		return nil, fmt.Errorf("invalid message length: %q", prefix)
+	}
+	if length > maxMessageLength {
+		if _, err := r.Discard(length); err != nil {
+			return nil, err
+		}
+		return nil, &messageTooLongError{length: length, maxMessageLength: maxMessageLength}
+	}
+
+	msg := make([]byte, length)
+	if _, err := io.ReadFull(r, msg); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
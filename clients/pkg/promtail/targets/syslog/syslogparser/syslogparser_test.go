@@ -4,8 +4,10 @@ import (
 	"io"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/influxdata/go-syslog/v3"
+	"github.com/influxdata/go-syslog/v3/rfc3164"
 	"github.com/influxdata/go-syslog/v3/rfc5424"
 	"github.com/stretchr/testify/require"
 
@@ -97,3 +99,52 @@ func TestParseStream_EmptyStream(t *testing.T) {
 	err := syslogparser.ParseStream(r, func(res *syslog.Result) {}, defaultMaxMessageLength)
 	require.Equal(t, err, io.EOF)
 }
+
+func TestParseRFC3164Stream_NewlineSeparated(t *testing.T) {
+	r := strings.NewReader("<13>Oct 11 22:14:15 host app[1]: First\r\n<13>2023-10-11T22:14:15Z host app: Second\n")
+
+	results := make([]*syslog.Result, 0)
+	cb := func(res *syslog.Result) {
+		results = append(results, res)
+	}
+
+	err := syslogparser.ParseRFC3164Stream(r, cb, defaultMaxMessageLength)
+	require.NoError(t, err)
+
+	require.Equal(t, 2, len(results))
+	require.NoError(t, results[0].Error)
+	first := results[0].Message.(*rfc3164.SyslogMessage)
+	require.Equal(t, "First", *first.Message)
+	require.Equal(t, "host", *first.Hostname)
+	require.Equal(t, "app", *first.Appname)
+	require.Equal(t, "1", *first.ProcID)
+	require.Equal(t, time.Now().Year(), first.Timestamp.Year())
+	require.NoError(t, results[1].Error)
+	require.Equal(t, "Second", *results[1].Message.(*rfc3164.SyslogMessage).Message)
+}
+
+func TestParseRFC3164Stream_OctetCounting(t *testing.T) {
+	r := strings.NewReader("35 <13>Oct 11 22:14:15 host app: First\n10000 " + strings.Repeat("a", 10000) + "36 <13>Oct 11 22:14:15 host app: Second")
+
+	results := make([]*syslog.Result, 0)
+	cb := func(res *syslog.Result) {
+		results = append(results, res)
+	}
+
+	err := syslogparser.ParseRFC3164Stream(r, cb, defaultMaxMessageLength)
+	require.NoError(t, err)
+
+	require.Equal(t, 3, len(results))
+	require.NoError(t, results[0].Error)
+	require.Equal(t, "First", *results[0].Message.(*rfc3164.SyslogMessage).Message)
+	require.EqualError(t, results[1].Error, "message too long to parse. was size 10000, max length 8192")
+	require.NoError(t, results[2].Error)
+	require.Equal(t, "Second", *results[2].Message.(*rfc3164.SyslogMessage).Message)
+}
+
+func TestParseRFC3164Stream_InvalidStream(t *testing.T) {
+	r := strings.NewReader("invalid")
+
+	err := syslogparser.ParseRFC3164Stream(r, func(res *syslog.Result) {}, defaultMaxMessageLength)
+	require.EqualError(t, err, "invalid or unsupported framing. first byte: 'i'")
+}
@@ -44,6 +44,18 @@ const (
 
 	// HerokuDrainTargetType is a Heroku Logs target
 	HerokuDrainTargetType = TargetType("HerokuDrain")
+
+	// OTLPTargetType is an OpenTelemetry logs target
+	OTLPTargetType = TargetType("OTLP")
+
+	// ObjectStoreTargetType is an object store target
+	ObjectStoreTargetType = TargetType("ObjectStore")
+
+	// HTTPPollTargetType is an HTTP polling target
+	HTTPPollTargetType = TargetType("HTTPPoll")
+
+	// KubernetesEventsTargetType is a Kubernetes events target
+	KubernetesEventsTargetType = TargetType("KubernetesEvents")
 )
 
 // Target is a promtail scrape target
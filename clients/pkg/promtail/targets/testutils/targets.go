@@ -0,0 +1,31 @@
+package testutils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/clients/pkg/promtail/client/fake"
+	"github.com/grafana/loki/clients/pkg/promtail/positions"
+)
+
+// NewPositions returns positions stored in the given file, which are synced every 10 seconds.
+func NewPositions(t *testing.T, logger log.Logger, file string) positions.Positions {
+	ps, err := positions.New(logger, positions.Config{
+		SyncPeriod:    10 * time.Second,
+		PositionsFile: file,
+	})
+	require.NoError(t, err)
+	return ps
+}
+
+// ReceivedLines returns the lines of the entries received by the client, in the order received.
+func ReceivedLines(c *fake.Client) []string {
+	var lines []string
+	for _, e := range c.Received() {
+		lines = append(lines, e.Line)
+	}
+	return lines
+}
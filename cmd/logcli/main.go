@@ -145,7 +145,16 @@ printed to the terminal. The lines will be printed as soon as the next part is
 complete, you don't have to wait for all the parts to download before getting
 output. The --merge-parts flag will remove the part files when it is done
 reading each of them. To change this, you can use the --keep-parts flag, and
-the part files will not be removed.`)
+the part files will not be removed.
+
+Streaming:
+
+Instead of querying in batches, the entries of a log query can be streamed by
+the server in a single request with the --stream flag. Entries are printed in
+timestamp order as soon as they are received, which is useful to export large
+amounts of logs without splitting them into part files. Setting --limit to 0
+streams all the entries of the time range, up to the
+max_streamed_entries_per_query limit of the server.`)
 	rangeQuery = newQuery(false, queryCmd)
 	tail       = queryCmd.Flag("tail", "Tail the logs").Short('t').Default("false").Bool()
 	follow     = queryCmd.Flag("follow", "Alias for --tail").Short('f').Default("false").Bool()
@@ -552,6 +561,7 @@ func newQuery(instant bool, cmd *kingpin.CmdClause) *query.Query {
 		cmd.Flag("overwrite-completed-parts", "Overwrites completed part files. This will download the range again, and replace the original completed part file. Default will skip a range if it's part file is already downloaded.").Default("false").BoolVar(&q.OverwriteCompleted)
 		cmd.Flag("merge-parts", "Reads the part files in order and writes the output to stdout. Original part files will be deleted with this option.").Default("false").BoolVar(&q.MergeParts)
 		cmd.Flag("keep-parts", "Overrides the default behaviour of --merge-parts which will delete the part files once all the files have been read. This option will keep the part files.").Default("false").BoolVar(&q.KeepParts)
+		cmd.Flag("stream", "Stream the entries of log queries from the server in a single request instead of querying in batches. The entries are printed as they are received, so common labels are not removed. Requires a Loki version supporting streamed query results.").Default("false").BoolVar(&q.Stream)
 	}
 
 	cmd.Flag("forward", "Scan forwards through logs.").Default("false").BoolVar(&q.Forward)
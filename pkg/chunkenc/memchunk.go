@@ -9,6 +9,7 @@ import (
 	"hash/crc32"
 	"io"
 	"reflect"
+	"sort"
 	"time"
 	"unsafe"
 
@@ -961,6 +962,10 @@ func (c *MemChunk) Iterator(ctx context.Context, mintT, maxtT time.Time, directi
 		stats.AddDecompressedBytes(decompressedSize)
 		stats.AddDecompressedStructuredMetadataBytes(decompressedSize)
 	}
+	// skip the whole chunk when its structured metadata can't match the pipeline.
+	if !c.symbolizer.MayMatch(log.StructuredMetadataMatchers(pipeline)) {
+		return iter.NoopIterator, nil
+	}
 	var headIterator iter.EntryIterator
 
 	var lastMax int64 // placeholder to check order across blocks
@@ -1034,6 +1039,67 @@ func (c *MemChunk) Iterator(ctx context.Context, mintT, maxtT time.Time, directi
 	return iter.NewSortEntryIterator(blockItrs, direction), nil
 }
 
+// StructuredMetadataNames returns the distinct names of the structured metadata of the chunk. They are read from
+// the symbolizer of the chunk when it tracks them, so they may include the names of entries outside of the time
+// range, and from the entries within the time range otherwise.
+func (c *MemChunk) StructuredMetadataNames(ctx context.Context, mintT, maxtT time.Time) ([]string, error) {
+	if names, ok := c.symbolizer.Names(); ok {
+		sort.Strings(names)
+		return names, nil
+	}
+	return c.structuredMetadata(ctx, mintT, maxtT, func(l logproto.LabelAdapter) string { return l.Name })
+}
+
+// StructuredMetadataValues returns the distinct values of the structured metadata with the given name of the entries
+// within the time range. The entries are only read when the symbolizer of the chunk has the name.
+func (c *MemChunk) StructuredMetadataValues(ctx context.Context, mintT, maxtT time.Time, name string) ([]string, error) {
+	if !c.symbolizer.MayMatch([]*labels.Matcher{labels.MustNewMatcher(labels.MatchNotEqual, name, "")}) {
+		return nil, nil
+	}
+	return c.structuredMetadata(ctx, mintT, maxtT, func(l logproto.LabelAdapter) string {
+		if l.Name != name {
+			return ""
+		}
+		return l.Value
+	})
+}
+
+// structuredMetadata returns the distinct non-empty strings selected from the structured metadata of the entries
+// within the time range.
+func (c *MemChunk) structuredMetadata(ctx context.Context, mintT, maxtT time.Time, selectFn func(logproto.LabelAdapter) string) ([]string, error) {
+	// the symbolizer is empty when no entry has structured metadata.
+	if c.symbolizer.UncompressedSize() == 0 {
+		return nil, nil
+	}
+
+	itr, err := c.Iterator(ctx, mintT, maxtT, logproto.FORWARD, log.NewNoopPipeline().ForStream(labels.Labels{}))
+	if err != nil {
+		return nil, err
+	}
+	defer itr.Close()
+
+	seen := map[string]struct{}{}
+	var result []string
+	for itr.Next() {
+		for _, l := range itr.Entry().StructuredMetadata {
+			str := selectFn(l)
+			if str == "" {
+				continue
+			}
+			if _, ok := seen[str]; ok {
+				continue
+			}
+			seen[str] = struct{}{}
+			result = append(result, str)
+		}
+	}
+	if err := itr.Error(); err != nil {
+		return nil, err
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
 // Iterator implements Chunk.
 func (c *MemChunk) SampleIterator(ctx context.Context, from, through time.Time, extractor log.StreamSampleExtractor) iter.SampleIterator {
 	mint, maxt := from.UnixNano(), through.UnixNano()
@@ -1046,6 +1112,10 @@ func (c *MemChunk) SampleIterator(ctx context.Context, from, through time.Time,
 		stats.AddDecompressedBytes(decompressedSize)
 		stats.AddDecompressedStructuredMetadataBytes(decompressedSize)
 	}
+	// skip the whole chunk when its structured metadata can't match the extractor.
+	if !c.symbolizer.MayMatch(log.StructuredMetadataMatchers(extractor)) {
+		return iter.NoopIterator
+	}
 
 	var lastMax int64 // placeholder to check order across blocks
 	ordered := true
@@ -1591,9 +1661,10 @@ func (si *bufferedIterator) close() {
 
 func newEntryIterator(ctx context.Context, pool ReaderPool, b []byte, pipeline log.StreamPipeline, format byte, symbolizer *symbolizer) iter.EntryIterator {
 	return &entryBufferedIterator{
-		bufferedIterator: newBufferedIterator(ctx, pool, b, format, symbolizer),
-		pipeline:         pipeline,
-		stats:            stats.FromContext(ctx),
+		bufferedIterator:           newBufferedIterator(ctx, pool, b, format, symbolizer),
+		pipeline:                   pipeline,
+		stats:                      stats.FromContext(ctx),
+		structuredMetadataMatchers: log.StructuredMetadataMatchers(pipeline),
 	}
 }
 
@@ -1602,6 +1673,9 @@ type entryBufferedIterator struct {
 	pipeline log.StreamPipeline
 	stats    *stats.Context
 
+	// lines not matching the structured metadata matchers are skipped before running the pipeline.
+	structuredMetadataMatchers []*labels.Matcher
+
 	cur        logproto.Entry
 	currLabels log.LabelsResult
 }
@@ -1616,6 +1690,9 @@ func (e *entryBufferedIterator) StreamHash() uint64 { return e.pipeline.BaseLabe
 
 func (e *entryBufferedIterator) Next() bool {
 	for e.bufferedIterator.Next() {
+		if !log.MatchStructuredMetadata(e.structuredMetadataMatchers, e.currStructuredMetadata) {
+			continue
+		}
 		newLine, lbs, matches := e.pipeline.Process(e.currTs, e.currLine, e.currStructuredMetadata...)
 		if !matches {
 			continue
@@ -1643,9 +1720,10 @@ func (e *entryBufferedIterator) Close() error {
 
 func newSampleIterator(ctx context.Context, pool ReaderPool, b []byte, format byte, extractor log.StreamSampleExtractor, symbolizer *symbolizer) iter.SampleIterator {
 	it := &sampleBufferedIterator{
-		bufferedIterator: newBufferedIterator(ctx, pool, b, format, symbolizer),
-		extractor:        extractor,
-		stats:            stats.FromContext(ctx),
+		bufferedIterator:           newBufferedIterator(ctx, pool, b, format, symbolizer),
+		extractor:                  extractor,
+		stats:                      stats.FromContext(ctx),
+		structuredMetadataMatchers: log.StructuredMetadataMatchers(extractor),
 	}
 	return it
 }
@@ -1656,12 +1734,18 @@ type sampleBufferedIterator struct {
 	extractor log.StreamSampleExtractor
 	stats     *stats.Context
 
+	// lines not matching the structured metadata matchers are skipped before running the extractor.
+	structuredMetadataMatchers []*labels.Matcher
+
 	cur        logproto.Sample
 	currLabels log.LabelsResult
 }
 
 func (e *sampleBufferedIterator) Next() bool {
 	for e.bufferedIterator.Next() {
+		if !log.MatchStructuredMetadata(e.structuredMetadataMatchers, e.currStructuredMetadata) {
+			continue
+		}
 		val, labels, ok := e.extractor.Process(e.currTs, e.currLine, e.currStructuredMetadata...)
 		if !ok {
 			continue
@@ -1156,6 +1156,11 @@ func TestCheckpointEncoding(t *testing.T) {
 					c.blocks[i].uncompressedSize = 0
 				}
 			}
+			// the symbolizers decoded from a checkpoint don't track the structured metadata names.
+			if f.chunkFormat >= ChunkFormatV4 {
+				require.Nil(t, cpy.symbolizer.names)
+				cpy.symbolizer.names = c.symbolizer.names
+			}
 
 			require.Equal(t, c, cpy)
 
@@ -1185,6 +1190,11 @@ func TestCheckpointEncoding(t *testing.T) {
 					c.blocks[i].uncompressedSize = 0
 				}
 			}
+			// the symbolizers decoded from a checkpoint don't track the structured metadata names.
+			if f.chunkFormat >= ChunkFormatV4 {
+				require.Nil(t, cpy.symbolizer.names)
+				cpy.symbolizer.names = c.symbolizer.names
+			}
 
 			require.Equal(t, c, cpy)
 		})
@@ -1827,6 +1837,17 @@ func TestMemChunk_IteratorWithStructuredMetadata(t *testing.T) {
 						logproto.FromLabelsToLabelAdapters(labels.FromStrings("traceID", "123", "user", "d")),
 					},
 				},
+				{
+					name:          "structured-metadata-filter",
+					query:         `{job="fake"} | structured_metadata traceID="123", user!="a"`,
+					expectedLines: []string{"lineD"},
+					expectedStreams: []string{
+						labels.FromStrings("job", "fake", "traceID", "123", "user", "d").String(),
+					},
+					expectedStructuredMetadata: [][]logproto.LabelAdapter{
+						logproto.FromLabelsToLabelAdapters(labels.FromStrings("traceID", "123", "user", "d")),
+					},
+				},
 				{
 					name:          "keep",
 					query:         `{job="fake"} | keep job, user`,
@@ -1967,3 +1988,102 @@ func TestMemChunk_IteratorWithStructuredMetadata(t *testing.T) {
 		})
 	}
 }
+
+func TestMemChunk_IteratorSkipsStructuredMetadata(t *testing.T) {
+	streamLabels := labels.FromStrings("job", "fake")
+	newChunk := func() *MemChunk {
+		chk := newMemChunkWithFormat(ChunkFormatV4, EncSnappy, UnorderedWithStructuredMetadataHeadBlockFmt, testBlockSize, testTargetSize)
+		require.NoError(t, chk.Append(logprotoEntryWithStructuredMetadata(1, "lineA", []logproto.LabelAdapter{{Name: "traceID", Value: "123"}})))
+		require.NoError(t, chk.cut())
+		require.NoError(t, chk.Append(logprotoEntryWithStructuredMetadata(2, "lineB", []logproto.LabelAdapter{{Name: "traceID", Value: "456"}})))
+		return chk
+	}
+	chk := newChunk()
+
+	closed := newChunk()
+	require.NoError(t, closed.Close())
+	b, err := closed.Bytes()
+	require.NoError(t, err)
+	decoded, err := NewByteChunk(b, testBlockSize, testTargetSize)
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		query         string
+		expectedLines []string
+		skipped       bool
+	}{
+		{query: `{job="fake"} | structured_metadata traceID="456"`, expectedLines: []string{"lineB"}},
+		{query: `{job="fake"} | structured_metadata traceID=~"4.*"`, expectedLines: []string{"lineB"}},
+		{query: `{job="fake"} | structured_metadata traceID="789"`, skipped: true},
+		{query: `{job="fake"} | structured_metadata user=~".+"`, skipped: true},
+		{query: `{job="fake"} | structured_metadata user=""`, expectedLines: []string{"lineA", "lineB"}},
+		// the matchers are not pushed down after labels are modified.
+		{query: `{job="fake"} | label_format user=job | structured_metadata user="fake"`},
+	} {
+		for name, c := range map[string]*MemChunk{"memory": chk, "decoded": decoded} {
+			t.Run(name+"/"+tc.query, func(t *testing.T) {
+				expr, err := syntax.ParseLogSelector(tc.query, true)
+				require.NoError(t, err)
+				pipeline, err := expr.Pipeline()
+				require.NoError(t, err)
+
+				sts, ctx := stats.NewContext(context.Background())
+				it, err := c.Iterator(ctx, time.Unix(0, 0), time.Unix(0, math.MaxInt64), logproto.FORWARD, pipeline.ForStream(streamLabels))
+				require.NoError(t, err)
+				var lines []string
+				for it.Next() {
+					lines = append(lines, it.Entry().Line)
+				}
+				require.NoError(t, it.Close())
+				require.Equal(t, tc.expectedLines, lines)
+
+				result := sts.Result(0, 0, len(lines))
+				if tc.skipped {
+					require.Equal(t, int64(0), result.Querier.Store.Chunk.DecompressedLines+result.Querier.Store.Chunk.HeadChunkLines)
+				} else {
+					require.NotEqual(t, int64(0), result.Querier.Store.Chunk.DecompressedLines+result.Querier.Store.Chunk.HeadChunkLines)
+				}
+			})
+		}
+	}
+}
+
+func TestMemChunk_StructuredMetadata(t *testing.T) {
+	chk := newMemChunkWithFormat(ChunkFormatV4, EncSnappy, UnorderedWithStructuredMetadataHeadBlockFmt, testBlockSize, testTargetSize)
+	require.NoError(t, chk.Append(logprotoEntryWithStructuredMetadata(1, "lineA", []logproto.LabelAdapter{{Name: "traceID", Value: "123"}, {Name: "user", Value: "a"}})))
+	require.NoError(t, chk.Append(logprotoEntryWithStructuredMetadata(2, "lineB", []logproto.LabelAdapter{{Name: "traceID", Value: "123"}})))
+	require.NoError(t, chk.cut())
+	require.NoError(t, chk.Append(logprotoEntryWithStructuredMetadata(3, "lineC", []logproto.LabelAdapter{{Name: "traceID", Value: "456"}})))
+
+	// the names are read from the symbolizer of the chunk.
+	names, err := chk.StructuredMetadataNames(context.Background(), time.Unix(0, 3), time.Unix(0, 4))
+	require.NoError(t, err)
+	require.Equal(t, []string{"traceID", "user"}, names)
+
+	values, err := chk.StructuredMetadataValues(context.Background(), time.Unix(0, 0), time.Unix(0, math.MaxInt64), "traceID")
+	require.NoError(t, err)
+	require.Equal(t, []string{"123", "456"}, values)
+
+	values, err = chk.StructuredMetadataValues(context.Background(), time.Unix(0, 3), time.Unix(0, 4), "traceID")
+	require.NoError(t, err)
+	require.Equal(t, []string{"456"}, values)
+
+	values, err = chk.StructuredMetadataValues(context.Background(), time.Unix(0, 0), time.Unix(0, math.MaxInt64), "missing")
+	require.NoError(t, err)
+	require.Empty(t, values)
+
+	// the names are read from the entries of the chunks decoded from a checkpoint.
+	var chkBuf, headBuf bytes.Buffer
+	require.NoError(t, chk.SerializeForCheckpointTo(&chkBuf, &headBuf))
+	restored, err := MemchunkFromCheckpoint(chkBuf.Bytes(), headBuf.Bytes(), UnorderedWithStructuredMetadataHeadBlockFmt, testBlockSize, testTargetSize)
+	require.NoError(t, err)
+	names, err = restored.StructuredMetadataNames(context.Background(), time.Unix(0, 3), time.Unix(0, 4))
+	require.NoError(t, err)
+	require.Equal(t, []string{"traceID"}, names)
+
+	empty := newMemChunkWithFormat(ChunkFormatV4, EncSnappy, UnorderedWithStructuredMetadataHeadBlockFmt, testBlockSize, testTargetSize)
+	require.NoError(t, empty.Append(logprotoEntry(1, "lineA")))
+	names, err = empty.StructuredMetadataNames(context.Background(), time.Unix(0, 0), time.Unix(0, math.MaxInt64))
+	require.NoError(t, err)
+	require.Empty(t, names)
+}
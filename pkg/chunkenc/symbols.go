@@ -29,11 +29,16 @@ type symbolizer struct {
 	labels         []string
 	size           int
 	compressedSize int
+
+	// names holds the symbols of the label names. They are only tracked by the symbolizers of the chunks being
+	// written, for the label APIs to read them without decoding the entries.
+	names map[uint32]struct{}
 }
 
 func newSymbolizer() *symbolizer {
 	return &symbolizer{
 		symbolsMap: map[string]uint32{},
+		names:      map[uint32]struct{}{},
 	}
 }
 
@@ -44,6 +49,7 @@ func (s *symbolizer) Reset() {
 
 	s.symbolsMap = map[string]uint32{}
 	s.labels = s.labels[:0]
+	s.names = map[uint32]struct{}{}
 	s.size = 0
 	s.compressedSize = 0
 }
@@ -59,11 +65,43 @@ func (s *symbolizer) Add(lbls labels.Labels) symbols {
 	for i, label := range lbls {
 		syms[i].Name = s.add(label.Name)
 		syms[i].Value = s.add(label.Value)
+		s.addName(syms[i].Name)
 	}
 
 	return syms
 }
 
+func (s *symbolizer) addName(idx uint32) {
+	s.mtx.RLock()
+	_, ok := s.names[idx]
+	tracked := s.names != nil
+	s.mtx.RUnlock()
+
+	if ok || !tracked {
+		return
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.names[idx] = struct{}{}
+}
+
+// Names returns the label names added to the symbolizer, and false if they aren't tracked because the symbolizer
+// was decoded from a chunk or a checkpoint.
+func (s *symbolizer) Names() ([]string, bool) {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	if s.names == nil {
+		return nil, false
+	}
+	names := make([]string, 0, len(s.names))
+	for idx := range s.names {
+		names = append(names, s.labels[idx])
+	}
+	return names, true
+}
+
 func (s *symbolizer) add(lbl string) uint32 {
 	s.mtx.RLock()
 	idx, ok := s.symbolsMap[lbl]
@@ -116,6 +154,50 @@ func (s *symbolizer) lookup(idx uint32) string {
 	return s.labels[idx]
 }
 
+// MayMatch returns false when no structured metadata referenced by the symbolizer can match all the matchers.
+// Only matchers not matching an empty value are checked since lines without the label would match the others,
+// the label name is required for them and the value too for equality matchers.
+func (s *symbolizer) MayMatch(matchers []*labels.Matcher) bool {
+	required := map[string]bool{}
+	for _, m := range matchers {
+		if m.Matches("") {
+			continue
+		}
+		required[m.Name] = false
+		if m.Type == labels.MatchEqual {
+			required[m.Value] = false
+		}
+	}
+	if len(required) == 0 {
+		return true
+	}
+
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	if s.symbolsMap != nil {
+		for str := range required {
+			if _, ok := s.symbolsMap[str]; !ok {
+				return false
+			}
+		}
+		return true
+	}
+
+	// symbolizers decoded from chunks only have the list of labels.
+	missing := len(required)
+	for _, l := range s.labels {
+		if found, ok := required[l]; ok && !found {
+			required[l] = true
+			missing--
+			if missing == 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // UncompressedSize returns the number of bytes taken up by deduped string labels
 func (s *symbolizer) UncompressedSize() int {
 	s.mtx.RLock()
@@ -35,7 +35,9 @@ import (
 	"github.com/grafana/loki/pkg/analytics"
 	"github.com/grafana/loki/pkg/compactor/retention"
 	"github.com/grafana/loki/pkg/distributor/clientpool"
+	"github.com/grafana/loki/pkg/distributor/sampling"
 	"github.com/grafana/loki/pkg/distributor/shardstreams"
+	"github.com/grafana/loki/pkg/distributor/transform"
 	"github.com/grafana/loki/pkg/distributor/writefailures"
 	"github.com/grafana/loki/pkg/ingester"
 	"github.com/grafana/loki/pkg/ingester/client"
@@ -76,6 +78,12 @@ type Config struct {
 	WriteFailuresLogging writefailures.Cfg `yaml:"write_failures_logging" doc:"description=Experimental. Customize the logging of write failures."`
 
 	OTLPConfig push.GlobalOTLPConfig `yaml:"otlp_config"`
+
+	// Tee configures the built-in tees duplicating the pushed streams.
+	Tee TeeConfig `yaml:"tee" doc:"description=Experimental. Configure the built-in tees asynchronously duplicating the pushed streams. They never delay nor fail the pushes: entries are dropped when a tee can't keep up."`
+
+	// Syslog configures the syslog listener pushing the received messages.
+	Syslog SyslogConfig `yaml:"syslog" doc:"description=Experimental. Configure the syslog listener receiving RFC5424 or RFC3164 messages over TCP, TLS or UDP, and pushing them to a tenant with the validation and rate limits of the other pushes. The streams are labeled with the host, app, facility and severity of the messages."`
 }
 
 // RegisterFlags registers distributor-related flags.
@@ -84,6 +92,8 @@ func (cfg *Config) RegisterFlags(fs *flag.FlagSet) {
 	cfg.DistributorRing.RegisterFlags(fs)
 	cfg.RateStore.RegisterFlagsWithPrefix("distributor.rate-store", fs)
 	cfg.WriteFailuresLogging.RegisterFlagsWithPrefix("distributor.write-failures-logging", fs)
+	cfg.Tee.RegisterFlagsWithPrefix("distributor.tee", fs)
+	cfg.Syslog.RegisterFlagsWithPrefix("distributor.syslog", fs)
 }
 
 // RateStore manages the ingestion rate of streams, populated by data fetched from ingesters.
@@ -121,6 +131,7 @@ type Distributor struct {
 	// Per-user rate limiter.
 	ingestionRateLimiter *limiter.RateLimiter
 	labelCache           *lru.Cache
+	transformCache       *lru.Cache
 
 	// Push failures rate limiter.
 	writeFailuresManager *writefailures.Manager
@@ -132,6 +143,7 @@ type Distributor struct {
 	ingesterAppendTimeouts *prometheus.CounterVec
 	replicationFactor      prometheus.Gauge
 	streamShardCount       prometheus.Counter
+	transformedEntries     *prometheus.CounterVec
 
 	usageTracker push.UsageTracker
 }
@@ -174,11 +186,21 @@ func New(
 
 	var servs []services.Service
 
+	builtinTees, teeServs, err := newBuiltinTees(cfg.Tee, registerer, logger)
+	if err != nil {
+		return nil, err
+	}
+	servs = append(servs, teeServs...)
+
 	rateLimitStrat := validation.LocalIngestionRateStrategy
 	labelCache, err := lru.New(maxLabelCacheSize)
 	if err != nil {
 		return nil, err
 	}
+	transformCache, err := lru.New(maxLabelCacheSize)
+	if err != nil {
+		return nil, err
+	}
 
 	d := &Distributor{
 		cfg:                   cfg,
@@ -190,10 +212,11 @@ func New(
 		validator:             validator,
 		pool:                  clientpool.NewPool("ingester", clientCfg.PoolConfig, ingestersRing, factory, logger, metricsNamespace),
 		labelCache:            labelCache,
+		transformCache:        transformCache,
 		shardTracker:          NewShardTracker(),
 		healthyInstancesCount: atomic.NewUint32(0),
 		rateLimitStrat:        rateLimitStrat,
-		tee:                   tee,
+		tee:                   WrapTee(append([]Tee{tee}, builtinTees...)...),
 		usageTracker:          usageTracker,
 		ingesterAppends: promauto.With(registerer).NewCounterVec(prometheus.CounterOpts{
 			Namespace: constants.Loki,
@@ -215,6 +238,11 @@ func New(
 			Name:      "stream_sharding_count",
 			Help:      "Total number of times the distributor has sharded streams",
 		}),
+		transformedEntries: promauto.With(registerer).NewCounterVec(prometheus.CounterOpts{
+			Namespace: constants.Loki,
+			Name:      "distributor_transformed_entries_total",
+			Help:      "The total number of entries whose stream labels were changed by a stream transformation rule.",
+		}, []string{"tenant", "rule", "action"}),
 		writeFailuresManager: writefailures.NewManager(logger, registerer, cfg.WriteFailuresLogging, configs, "distributor"),
 	}
 
@@ -256,6 +284,14 @@ func New(
 	)
 	d.rateStore = rs
 
+	if cfg.Syslog.Enabled() {
+		syslogListener, err := newSyslogListener(cfg.Syslog, d.Push, d.tenantsRetention, usageTracker, registerer, logger)
+		if err != nil {
+			return nil, errors.Wrap(err, "syslog listener")
+		}
+		servs = append(servs, syslogListener)
+	}
+
 	servs = append(servs, d.pool, rs)
 	d.subservices, err = services.NewManager(servs...)
 	if err != nil {
@@ -329,6 +365,8 @@ func (d *Distributor) Push(ctx context.Context, req *logproto.PushRequest) (*log
 
 	var validationErrors util.GroupedErrors
 	validationContext := d.validator.getValidationContextForTime(time.Now(), tenantID)
+	transformations := d.validator.Limits.StreamTransformations(tenantID)
+	samplingPolicies := d.validator.Limits.SamplingPolicies(tenantID)
 
 	func() {
 		sp := opentracing.SpanFromContext(ctx)
@@ -347,8 +385,12 @@ func (d *Distributor) Push(ctx context.Context, req *logproto.PushRequest) (*log
 			// Truncate first so subsequent steps have consistent line lengths
 			d.truncateLines(validationContext, &stream)
 
+			// Transform the labels before validating and hashing them.
 			var lbs labels.Labels
-			lbs, stream.Labels, stream.Hash, err = d.parseStreamLabels(validationContext, stream.Labels, &stream)
+			err = d.transformStream(tenantID, transformations, &stream)
+			if err == nil {
+				lbs, stream.Labels, stream.Hash, err = d.parseStreamLabels(validationContext, stream.Labels, &stream)
+			}
 			if err != nil {
 				d.writeFailuresManager.Log(tenantID, err)
 				validationErrors.Add(err)
@@ -363,8 +405,22 @@ func (d *Distributor) Push(ctx context.Context, req *logproto.PushRequest) (*log
 
 			n := 0
 			pushSize := 0
+			// The entries dropped by each of the sampling policies of the tenant.
+			var dropped []struct{ count, size int }
+			policies := sampling.Matching(samplingPolicies, lbs)
 			prevTs := stream.Entries[0].Timestamp
 			for _, entry := range stream.Entries {
+				if len(policies) > 0 {
+					if i, ok := sampling.Dropped(samplingPolicies, policies, stream.Labels, []byte(entry.Line)); ok {
+						if dropped == nil {
+							dropped = make([]struct{ count, size int }, len(samplingPolicies))
+						}
+						dropped[i].count++
+						dropped[i].size += len(entry.Line)
+						continue
+					}
+				}
+
 				if err := d.validator.ValidateEntry(ctx, validationContext, lbs, entry); err != nil {
 					d.writeFailuresManager.Log(tenantID, err)
 					validationErrors.Add(err)
@@ -394,6 +450,11 @@ func (d *Distributor) Push(ctx context.Context, req *logproto.PushRequest) (*log
 				pushSize += len(entry.Line)
 			}
 			stream.Entries = stream.Entries[:n]
+			for i, e := range dropped {
+				if e.count > 0 {
+					d.discardSampledEntries(ctx, tenantID, lbs, stream.Labels, samplingPolicies[i].DisplayName(i), e.count, e.size)
+				}
+			}
 
 			shardStreamsCfg := d.validator.Limits.ShardStreams(tenantID)
 			if shardStreamsCfg.Enabled {
@@ -738,6 +799,81 @@ func (d *Distributor) parseStreamLabels(vContext validationContext, key string,
 	return ls, ls.String(), lsHash, nil
 }
 
+// transformedStream caches the transformation of the labels of a stream by the rules of a tenant.
+type transformedStream struct {
+	rules              *transform.Rule // The first rule, identifying the rules until they are reloaded.
+	labels             string
+	structuredMetadata []logproto.LabelAdapter
+	applied            []int // The index of the rules changing the labels.
+}
+
+// transformStream applies the stream transformations of the tenant to the labels of the stream. The labels moved
+// to structured metadata are added to each of its entries, unless the entry already has structured metadata with
+// the same name.
+func (d *Distributor) transformStream(tenantID string, rules []transform.Rule, stream *logproto.Stream) error {
+	if len(rules) == 0 {
+		return nil
+	}
+
+	// The rules of a tenant are the same until the runtime config is reloaded, so the transformations are cached by
+	// the labels of the stream and checked against the identity of the rules.
+	key := tenantID + "\xff" + stream.Labels
+	var transformed transformedStream
+	if val, ok := d.transformCache.Get(key); ok && val.(transformedStream).rules == &rules[0] {
+		transformed = val.(transformedStream)
+	} else {
+		ls, err := syntax.ParseLabels(stream.Labels)
+		if err != nil {
+			return fmt.Errorf(validation.InvalidLabelsErrorMsg, stream.Labels, err)
+		}
+		transformed.rules = &rules[0]
+		ls, transformed.structuredMetadata = transform.Apply(rules, ls, func(rule int) {
+			transformed.applied = append(transformed.applied, rule)
+		})
+		transformed.labels = ls.String()
+		d.transformCache.Add(key, transformed)
+	}
+
+	for _, rule := range transformed.applied {
+		d.transformedEntries.WithLabelValues(tenantID, rules[rule].MetricName(rule), string(rules[rule].Action)).Add(float64(len(stream.Entries)))
+	}
+	stream.Labels = transformed.labels
+
+	if len(transformed.structuredMetadata) > 0 {
+		for i := range stream.Entries {
+			entry := &stream.Entries[i]
+			metadata := make([]logproto.LabelAdapter, 0, len(entry.StructuredMetadata)+len(transformed.structuredMetadata))
+			metadata = append(metadata, entry.StructuredMetadata...)
+			for _, l := range transformed.structuredMetadata {
+				if !hasStructuredMetadata(entry.StructuredMetadata, l.Name) {
+					metadata = append(metadata, l)
+				}
+			}
+			entry.StructuredMetadata = metadata
+		}
+	}
+	return nil
+}
+
+func hasStructuredMetadata(metadata []logproto.LabelAdapter, name string) bool {
+	for _, l := range metadata {
+		if l.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// discardSampledEntries reports the entries of a stream dropped by a sampling policy of the tenant.
+func (d *Distributor) discardSampledEntries(ctx context.Context, tenantID string, lbs labels.Labels, stream, policy string, count, size int) {
+	validation.DiscardedSamples.WithLabelValues(validation.SamplingPolicy, tenantID).Add(float64(count))
+	validation.DiscardedBytes.WithLabelValues(validation.SamplingPolicy, tenantID).Add(float64(size))
+	if d.usageTracker != nil {
+		d.usageTracker.DiscardedBytesAdd(ctx, tenantID, validation.SamplingPolicy, lbs, float64(size))
+	}
+	d.writeFailuresManager.Log(tenantID, fmt.Errorf(validation.SamplingPolicyErrorMsg, count, stream, policy, size))
+}
+
 // shardCountFor returns the right number of shards to be used by the given stream.
 //
 // It first checks if the number of shards is present in the shard store. If it isn't it will calculate it
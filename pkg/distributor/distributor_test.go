@@ -22,16 +22,20 @@ import (
 	"github.com/grafana/dskit/services"
 	"github.com/grafana/dskit/user"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/health/grpc_health_v1"
 
+	"github.com/grafana/loki/pkg/distributor/sampling"
+	"github.com/grafana/loki/pkg/distributor/transform"
 	"github.com/grafana/loki/pkg/ingester"
 	"github.com/grafana/loki/pkg/ingester/client"
 	"github.com/grafana/loki/pkg/logproto"
 	"github.com/grafana/loki/pkg/logql/syntax"
+	"github.com/grafana/loki/pkg/push"
 	"github.com/grafana/loki/pkg/runtime"
 	"github.com/grafana/loki/pkg/util/constants"
 	fe "github.com/grafana/loki/pkg/util/flagext"
@@ -529,6 +533,85 @@ func Test_TruncateLogLines(t *testing.T) {
 	})
 }
 
+func Test_TransformStreamsOnPush(t *testing.T) {
+	limits := &validation.Limits{}
+	flagext.DefaultValues(limits)
+	limits.AllowStructuredMetadata = true
+	limits.StreamTransformations = []transform.Rule{
+		{Action: transform.StructuredMetadata, Labels: []string{"trace_id"}},
+		{Name: "dev", Selector: `{env="dev"}`, Action: transform.Drop, Labels: []string{"pod"}},
+		{Action: transform.Add, TargetLabel: "cluster", Value: "eu"},
+	}
+	require.NoError(t, limits.Validate())
+
+	ingester := &mockIngester{}
+	distributors, _ := prepare(t, 1, 5, limits, func(addr string) (ring_client.PoolClient, error) { return ingester, nil })
+
+	request := makeWriteRequestWithLabels(2, 10, []string{`{env="dev", pod="a", trace_id="1"}`})
+	_, err := distributors[0].Push(ctx, request)
+	require.NoError(t, err)
+
+	topVal := ingester.Peek()
+	require.Equal(t, `{cluster="eu", env="dev"}`, topVal.Streams[0].Labels)
+	for _, e := range topVal.Streams[0].Entries {
+		require.Equal(t, push.LabelsAdapter{{Name: "trace_id", Value: "1"}}, e.StructuredMetadata)
+	}
+	require.Equal(t, 2.0, testutil.ToFloat64(distributors[0].transformedEntries.WithLabelValues("test", "0", "structured_metadata")))
+	require.Equal(t, 2.0, testutil.ToFloat64(distributors[0].transformedEntries.WithLabelValues("test", "dev", "drop")))
+	require.Equal(t, 2.0, testutil.ToFloat64(distributors[0].transformedEntries.WithLabelValues("test", "2", "add")))
+
+	// The transformation of the stream is cached, and the structured metadata of the entries is not overwritten.
+	request = makeWriteRequestWithLabels(2, 10, []string{`{env="dev", pod="a", trace_id="1"}`})
+	request.Streams[0].Entries[0].StructuredMetadata = push.LabelsAdapter{{Name: "trace_id", Value: "own"}}
+	_, err = distributors[0].Push(ctx, request)
+	require.NoError(t, err)
+
+	ingester.mu.Lock()
+	topVal = ingester.pushed[len(ingester.pushed)-1]
+	ingester.mu.Unlock()
+	require.Equal(t, `{cluster="eu", env="dev"}`, topVal.Streams[0].Labels)
+	require.Equal(t, push.LabelsAdapter{{Name: "trace_id", Value: "own"}}, topVal.Streams[0].Entries[0].StructuredMetadata)
+	require.Equal(t, push.LabelsAdapter{{Name: "trace_id", Value: "1"}}, topVal.Streams[0].Entries[1].StructuredMetadata)
+	require.Equal(t, 4.0, testutil.ToFloat64(distributors[0].transformedEntries.WithLabelValues("test", "0", "structured_metadata")))
+	require.Equal(t, 4.0, testutil.ToFloat64(distributors[0].transformedEntries.WithLabelValues("test", "dev", "drop")))
+	require.Equal(t, 4.0, testutil.ToFloat64(distributors[0].transformedEntries.WithLabelValues("test", "2", "add")))
+	require.Equal(t, 1, distributors[0].transformCache.Len())
+
+	// Reloaded rules are applied instead of the cached transformation.
+	reloaded := []transform.Rule{{Action: transform.Add, TargetLabel: "cluster", Value: "us"}}
+	require.NoError(t, reloaded[0].Validate())
+	stream := logproto.Stream{Labels: `{env="dev", pod="a", trace_id="1"}`, Entries: []logproto.Entry{{Line: "line"}}}
+	require.NoError(t, distributors[0].transformStream("test", reloaded, &stream))
+	require.Equal(t, `{cluster="us", env="dev", pod="a", trace_id="1"}`, stream.Labels)
+}
+
+func Test_SamplingPoliciesOnPush(t *testing.T) {
+	limits := &validation.Limits{}
+	flagext.DefaultValues(limits)
+	limits.SamplingPolicies = []sampling.Policy{
+		{Selector: `{app="api"} |= "5"`, Action: sampling.Drop},
+		{Selector: `{app="api"}`, Action: sampling.Sample, KeepRatio: 1},
+		{Selector: `{app="health"}`, Action: sampling.Sample, KeepRatio: 0},
+	}
+	require.NoError(t, limits.Validate())
+
+	ingester := &mockIngester{}
+	distributors, _ := prepare(t, 1, 5, limits, func(addr string) (ring_client.PoolClient, error) { return ingester, nil })
+
+	discardedSamples := testutil.ToFloat64(validation.DiscardedSamples.WithLabelValues(validation.SamplingPolicy, "test"))
+	discardedBytes := testutil.ToFloat64(validation.DiscardedBytes.WithLabelValues(validation.SamplingPolicy, "test"))
+
+	_, err := distributors[0].Push(ctx, makeWriteRequestWithLabels(10, 10, []string{`{app="api"}`}))
+	require.NoError(t, err)
+	require.Len(t, ingester.Peek().Streams[0].Entries, 9)
+
+	_, err = distributors[0].Push(ctx, makeWriteRequestWithLabels(10, 10, []string{`{app="health"}`}))
+	require.NoError(t, err)
+
+	require.Equal(t, discardedSamples+11, testutil.ToFloat64(validation.DiscardedSamples.WithLabelValues(validation.SamplingPolicy, "test")))
+	require.Equal(t, discardedBytes+110, testutil.ToFloat64(validation.DiscardedBytes.WithLabelValues(validation.SamplingPolicy, "test")))
+}
+
 func TestStreamShard(t *testing.T) {
 	// setup base stream.
 	baseStream := logproto.Stream{}
@@ -19,14 +19,30 @@ import (
 
 // PushHandler reads a snappy-compressed proto from the HTTP body.
 func (d *Distributor) PushHandler(w http.ResponseWriter, r *http.Request) {
-	d.pushHandler(w, r, push.ParseLokiRequest)
+	d.pushHandler(w, r, push.ParseLokiRequest, push.WriteNoContentResponse)
 }
 
 func (d *Distributor) OTLPPushHandler(w http.ResponseWriter, r *http.Request) {
-	d.pushHandler(w, r, push.ParseOTLPRequest)
+	d.pushHandler(w, r, push.ParseOTLPRequest, push.WriteNoContentResponse)
 }
 
-func (d *Distributor) pushHandler(w http.ResponseWriter, r *http.Request, pushRequestParser push.RequestParser) {
+// ElasticsearchBulkHandler reads newline-delimited JSON documents sent to the Elasticsearch bulk API.
+func (d *Distributor) ElasticsearchBulkHandler(w http.ResponseWriter, r *http.Request) {
+	bulk := &push.ElasticsearchBulk{}
+	d.pushHandler(w, r, bulk.ParseRequest, bulk.WriteResponse)
+}
+
+// SplunkHECEventHandler reads JSON events sent to the event endpoint of the Splunk HTTP Event Collector.
+func (d *Distributor) SplunkHECEventHandler(w http.ResponseWriter, r *http.Request) {
+	d.pushHandler(w, r, push.ParseSplunkHECEventRequest, push.WriteSplunkHECResponse)
+}
+
+// SplunkHECRawHandler reads lines sent to the raw endpoint of the Splunk HTTP Event Collector.
+func (d *Distributor) SplunkHECRawHandler(w http.ResponseWriter, r *http.Request) {
+	d.pushHandler(w, r, push.ParseSplunkHECRawRequest, push.WriteSplunkHECResponse)
+}
+
+func (d *Distributor) pushHandler(w http.ResponseWriter, r *http.Request, pushRequestParser push.RequestParser, writeResponse push.ResponseWriter) {
 	logger := util_log.WithContext(r.Context(), util_log.Logger)
 	tenantID, err := tenant.TenantID(r.Context())
 	if err != nil {
@@ -72,7 +88,7 @@ func (d *Distributor) pushHandler(w http.ResponseWriter, r *http.Request, pushRe
 				"msg", "push request successful",
 			)
 		}
-		w.WriteHeader(http.StatusNoContent)
+		writeResponse(w)
 		return
 	}
 
@@ -5,6 +5,7 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/grafana/dskit/user"
@@ -77,7 +78,7 @@ func TestRequestParserWrapping(t *testing.T) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "fake-path", nil)
 	require.NoError(t, err)
 
-	distributors[0].pushHandler(httptest.NewRecorder(), req, stubParser)
+	distributors[0].pushHandler(httptest.NewRecorder(), req, stubParser, push.WriteNoContentResponse)
 
 	require.True(t, called)
 }
@@ -85,3 +86,64 @@ func TestRequestParserWrapping(t *testing.T) {
 func stubParser(_ string, _ *http.Request, _ push.TenantsRetention, _ push.Limits, _ push.UsageTracker) (*logproto.PushRequest, *push.Stats, error) {
 	return &logproto.PushRequest{}, &push.Stats{}, nil
 }
+
+func TestCompatiblePushHandlers(t *testing.T) {
+	limits := &validation.Limits{}
+	flagext.DefaultValues(limits)
+	limits.RejectOldSamples = false
+	distributors, _ := prepare(t, 1, 3, limits, nil)
+
+	for _, tc := range []struct {
+		name    string
+		handler http.HandlerFunc
+		body    string
+		code    int
+		resp    string
+	}{
+		{
+			name:    "elasticsearch bulk",
+			handler: distributors[0].ElasticsearchBulkHandler,
+			body: `{"index":{"_index":"logs"}}
+{"@timestamp":"2024-01-01T00:00:00Z","message":"first"}
+{"create":{"_index":"logs","_id":"2"}}
+{"@timestamp":"2024-01-01T00:00:01Z","message":"second"}
+{"delete":{"_index":"logs","_id":"1"}}
+`,
+			code: http.StatusOK,
+			resp: `{"took":0,"errors":false,"items":[{"index":{"_index":"logs","status":201,"result":"created"}},{"create":{"_index":"logs","_id":"2","status":201,"result":"created"}},{"delete":{"_index":"logs","_id":"1","status":404,"result":"not_found"}}]}`,
+		},
+		{
+			name:    "elasticsearch invalid bulk",
+			handler: distributors[0].ElasticsearchBulkHandler,
+			body:    `{"update":{"_index":"logs"}}`,
+			code:    http.StatusBadRequest,
+		},
+		{
+			name:    "splunk event",
+			handler: distributors[0].SplunkHECEventHandler,
+			body:    `{"time":1704067200,"host":"web-1","event":"first"}{"time":1704067201,"event":{"msg":"second"}}`,
+			code:    http.StatusOK,
+			resp:    `{"text":"Success","code":0}`,
+		},
+		{
+			name:    "splunk raw",
+			handler: distributors[0].SplunkHECRawHandler,
+			body:    "first\nsecond\n",
+			code:    http.StatusOK,
+			resp:    `{"text":"Success","code":0}`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := user.InjectOrgID(context.Background(), "test-user")
+			req, err := http.NewRequestWithContext(ctx, http.MethodPost, "fake-path", strings.NewReader(tc.body))
+			require.NoError(t, err)
+
+			rec := httptest.NewRecorder()
+			tc.handler(rec, req)
+			require.Equal(t, tc.code, rec.Code, rec.Body.String())
+			if tc.resp != "" {
+				require.JSONEq(t, tc.resp, rec.Body.String())
+			}
+		})
+	}
+}
@@ -4,7 +4,9 @@ import (
 	"time"
 
 	"github.com/grafana/loki/pkg/compactor/retention"
+	"github.com/grafana/loki/pkg/distributor/sampling"
 	"github.com/grafana/loki/pkg/distributor/shardstreams"
+	"github.com/grafana/loki/pkg/distributor/transform"
 	"github.com/grafana/loki/pkg/loghttp/push"
 )
 
@@ -31,4 +33,6 @@ type Limits interface {
 	MaxStructuredMetadataSize(userID string) int
 	MaxStructuredMetadataCount(userID string) int
 	OTLPConfig(userID string) push.OTLPConfig
+	StreamTransformations(userID string) []transform.Rule
+	SamplingPolicies(userID string) []sampling.Policy
 }
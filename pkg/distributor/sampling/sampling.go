@@ -0,0 +1,130 @@
+package sampling
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/cespare/xxhash/v2"
+	"github.com/prometheus/prometheus/model/labels"
+
+	"github.com/grafana/loki/pkg/logql/log"
+	"github.com/grafana/loki/pkg/logql/syntax"
+	"github.com/grafana/loki/pkg/util"
+)
+
+// Action is the action of a policy on the entries it matches.
+type Action string
+
+const (
+	// Drop drops the entries.
+	Drop Action = "drop"
+	// Sample keeps a ratio of the entries.
+	Sample Action = "sample"
+)
+
+var separator = []byte{0xff}
+
+// Policy drops or samples the entries pushed by a tenant matching a LogQL selector.
+type Policy struct {
+	Name      string  `yaml:"name,omitempty" json:"name,omitempty" doc:"description=Name of the policy in the logs. Defaults to the index of the policy."`
+	Selector  string  `yaml:"selector" json:"selector" doc:"description=LogQL stream selector, optionally followed by line filters, of the entries the policy applies to."`
+	Action    Action  `yaml:"action" json:"action" doc:"description=Action applied to the entries: drop or sample."`
+	KeepRatio float64 `yaml:"keep_ratio,omitempty" json:"keep_ratio,omitempty" doc:"description=Ratio of the entries kept by the sample action, between 0 and 1."`
+
+	Matchers []*labels.Matcher `yaml:"-" json:"-"` // populated during validation.
+	Filters  []log.Filterer    `yaml:"-" json:"-"` // populated during validation.
+}
+
+// Validate validates the policy, populating its matchers and filters.
+func (p *Policy) Validate() error {
+	switch p.Action {
+	case Drop:
+	case Sample:
+		if p.KeepRatio < 0 || p.KeepRatio > 1 {
+			return fmt.Errorf("invalid keep_ratio %v: must be between 0 and 1", p.KeepRatio)
+		}
+	default:
+		return fmt.Errorf("invalid action %q", p.Action)
+	}
+
+	expr, err := syntax.ParseLogSelector(p.Selector, true)
+	if err != nil {
+		return fmt.Errorf("invalid selector: %w", err)
+	}
+	p.Matchers = expr.Matchers()
+	p.Filters = nil
+	if pipeline, ok := expr.(*syntax.PipelineExpr); ok {
+		for _, stage := range pipeline.MultiStages {
+			filter, ok := stage.(*syntax.LineFilterExpr)
+			if !ok {
+				return fmt.Errorf("invalid selector %q: only line filters are supported", p.Selector)
+			}
+			f, err := filter.Filter()
+			if err != nil {
+				return fmt.Errorf("invalid selector: %w", err)
+			}
+			p.Filters = append(p.Filters, f)
+		}
+	}
+	return nil
+}
+
+// DisplayName returns the name of the policy in the logs.
+func (p *Policy) DisplayName(index int) string {
+	if p.Name != "" {
+		return p.Name
+	}
+	return strconv.Itoa(index)
+}
+
+// Matching returns the indexes of the policies whose stream selector matches the labels of a stream, in order.
+func Matching(policies []Policy, lbs labels.Labels) []int {
+	var matching []int
+	for i := range policies {
+		if util.MatchesLabels(policies[i].Matchers, lbs) {
+			matching = append(matching, i)
+		}
+	}
+	return matching
+}
+
+// Dropped returns whether the entry is dropped, and the index of the policy dropping it. The first of the matching
+// policies whose line filters match the entry decides whether it's dropped. Entries are sampled by the hash of their
+// stream and line, so the same entries are kept when they are pushed again.
+func Dropped(policies []Policy, matching []int, stream string, line []byte) (int, bool) {
+	for _, i := range matching {
+		p := &policies[i]
+		if !p.matchesLine(line) {
+			continue
+		}
+		switch p.Action {
+		case Drop:
+			return i, true
+		case Sample:
+			return i, !keep(stream, line, p.KeepRatio)
+		}
+	}
+	return 0, false
+}
+
+func (p *Policy) matchesLine(line []byte) bool {
+	for _, f := range p.Filters {
+		if !f.Filter(line) {
+			return false
+		}
+	}
+	return true
+}
+
+// keep returns whether the entry is kept, by comparing its hash to the ratio of the hash space.
+func keep(stream string, line []byte, ratio float64) bool {
+	if ratio >= 1 {
+		return true
+	}
+	h := xxhash.New()
+	_, _ = h.WriteString(stream)
+	_, _ = h.Write(separator)
+	_, _ = h.Write(line)
+	return float64(h.Sum64()) < ratio*math.MaxUint64
+}
@@ -0,0 +1,115 @@
+package sampling
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		policy  Policy
+		filters bool
+		err     string
+	}{
+		{
+			name:   "drop",
+			policy: Policy{Selector: `{app="api"}`, Action: Drop},
+		},
+		{
+			name:    "sample with line filters",
+			policy:  Policy{Selector: `{app="api"} |= "debug" != "error"`, Action: Sample, KeepRatio: 0.1},
+			filters: true,
+		},
+		{
+			name:   "invalid action",
+			policy: Policy{Selector: `{app="api"}`, Action: "keep"},
+			err:    `invalid action "keep"`,
+		},
+		{
+			name:   "invalid keep ratio",
+			policy: Policy{Selector: `{app="api"}`, Action: Sample, KeepRatio: 2},
+			err:    "invalid keep_ratio 2: must be between 0 and 1",
+		},
+		{
+			name:   "invalid selector",
+			policy: Policy{Selector: `{app=}`, Action: Drop},
+			err:    "invalid selector",
+		},
+		{
+			name:   "unsupported stage",
+			policy: Policy{Selector: `{app="api"} | json`, Action: Drop},
+			err:    "only line filters are supported",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.policy.Validate()
+			if tc.err != "" {
+				require.ErrorContains(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, tc.policy.Matchers, 1)
+			require.Equal(t, tc.filters, len(tc.policy.Filters) > 0)
+		})
+	}
+}
+
+func TestDropped(t *testing.T) {
+	policies := []Policy{
+		{Selector: `{app="api"} |= "level=error"`, Action: Sample, KeepRatio: 1},
+		{Selector: `{app="api"} |= "level=debug"`, Action: Sample, KeepRatio: 0},
+		{Selector: `{app="health"}`, Action: Drop},
+	}
+	for i := range policies {
+		require.NoError(t, policies[i].Validate())
+	}
+
+	dropped := func(policies []Policy, matching []int, stream, line string) bool {
+		_, dropped := Dropped(policies, matching, stream, []byte(line))
+		return dropped
+	}
+
+	api := Matching(policies, labels.FromStrings("app", "api"))
+	require.Equal(t, []int{0, 1}, api)
+	require.False(t, dropped(policies, api, `{app="api"}`, "level=error level=debug"))
+	require.False(t, dropped(policies, api, `{app="api"}`, "level=info"))
+	policy, ok := Dropped(policies, api, `{app="api"}`, []byte("level=debug"))
+	require.True(t, ok)
+	require.Equal(t, 1, policy)
+
+	chained := []Policy{{Selector: `{app="api"} |= "level=debug" != "keep"`, Action: Drop}}
+	require.NoError(t, chained[0].Validate())
+	require.True(t, dropped(chained, Matching(chained, labels.FromStrings("app", "api")), `{app="api"}`, "level=debug"))
+	require.False(t, dropped(chained, Matching(chained, labels.FromStrings("app", "api")), `{app="api"}`, "level=debug keep"))
+
+	health := Matching(policies, labels.FromStrings("app", "health"))
+	require.Equal(t, []int{2}, health)
+	policy, ok = Dropped(policies, health, `{app="health"}`, []byte("ok"))
+	require.True(t, ok)
+	require.Equal(t, 2, policy)
+
+	require.Empty(t, Matching(policies, labels.FromStrings("app", "web")))
+}
+
+func TestDroppedSampling(t *testing.T) {
+	policies := []Policy{{Selector: `{app="api"}`, Action: Sample, KeepRatio: 0.25}}
+	require.NoError(t, policies[0].Validate())
+	matching := Matching(policies, labels.FromStrings("app", "api"))
+
+	kept := 0
+	for i := 0; i < 10000; i++ {
+		line := []byte("line " + strconv.Itoa(i))
+		_, dropped := Dropped(policies, matching, `{app="api"}`, line)
+		// Entries are sampled deterministically.
+		_, again := Dropped(policies, matching, `{app="api"}`, line)
+		require.Equal(t, dropped, again)
+		if !dropped {
+			kept++
+		}
+	}
+	require.InDelta(t, 2500, kept, 250)
+}
@@ -0,0 +1,501 @@
+package distributor
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/backoff"
+	"github.com/grafana/dskit/services"
+	"github.com/grafana/dskit/user"
+	"github.com/influxdata/go-syslog/v3"
+	"github.com/influxdata/go-syslog/v3/rfc3164"
+	"github.com/influxdata/go-syslog/v3/rfc5424"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/common/model"
+	"golang.org/x/net/netutil"
+
+	"github.com/grafana/loki/clients/pkg/promtail/targets/syslog/syslogparser"
+	"github.com/grafana/loki/pkg/loghttp/push"
+	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/logql/syntax"
+	"github.com/grafana/loki/pkg/util/constants"
+	"github.com/grafana/loki/pkg/util/flagext"
+)
+
+const (
+	syslogFormatRFC5424 = "rfc5424"
+	syslogFormatRFC3164 = "rfc3164"
+
+	syslogProtocolTCP = "tcp"
+	syslogProtocolUDP = "udp"
+
+	syslogHostLabel     = "host"
+	syslogAppLabel      = "app"
+	syslogFacilityLabel = "facility"
+	syslogSeverityLabel = "severity"
+)
+
+// SyslogConfig configures the syslog listener of the distributor.
+type SyslogConfig struct {
+	TCPListenAddress     string            `yaml:"tcp_listen_address"`
+	UDPListenAddress     string            `yaml:"udp_listen_address"`
+	TLSCertFile          string            `yaml:"tls_cert_file"`
+	TLSKeyFile           string            `yaml:"tls_key_file"`
+	TLSClientCAFile      string            `yaml:"tls_client_ca_file"`
+	Format               string            `yaml:"format"`
+	TenantID             string            `yaml:"tenant_id"`
+	Labels               map[string]string `yaml:"labels" doc:"description=Labels added to the streams of the received messages."`
+	UseIncomingTimestamp bool              `yaml:"use_incoming_timestamp"`
+	MaxMessageLength     int               `yaml:"max_message_length"`
+	IdleTimeout          time.Duration     `yaml:"idle_timeout"`
+	MaxConnections       int               `yaml:"max_connections"`
+	BatchSize            flagext.ByteSize  `yaml:"batch_size"`
+	BatchWait            time.Duration     `yaml:"batch_wait"`
+}
+
+// RegisterFlagsWithPrefix registers the flags of the syslog listener.
+func (cfg *SyslogConfig) RegisterFlagsWithPrefix(prefix string, f *flag.FlagSet) {
+	f.StringVar(&cfg.TCPListenAddress, prefix+".tcp-listen-address", "", "Address the syslog listener accepts TCP connections on, for example 0.0.0.0:1514. Disabled if empty.")
+	f.StringVar(&cfg.UDPListenAddress, prefix+".udp-listen-address", "", "Address the syslog listener receives UDP packets on, for example 0.0.0.0:1514. Disabled if empty.")
+	f.StringVar(&cfg.TLSCertFile, prefix+".tls-cert-file", "", "Path to the certificate of the TCP listener. TLS is enabled if set.")
+	f.StringVar(&cfg.TLSKeyFile, prefix+".tls-key-file", "", "Path to the key of the certificate of the TCP listener.")
+	f.StringVar(&cfg.TLSClientCAFile, prefix+".tls-client-ca-file", "", "Path to the CA certificate verifying the certificates of the clients. Client certificates are required if set.")
+	f.StringVar(&cfg.Format, prefix+".format", syslogFormatRFC5424, "Format of the received messages: rfc5424 or rfc3164. The messages are either separated by newlines or prefixed by their length (octet counting).")
+	f.StringVar(&cfg.TenantID, prefix+".tenant-id", "fake", "Tenant the received messages are pushed to.")
+	f.BoolVar(&cfg.UseIncomingTimestamp, prefix+".use-incoming-timestamp", false, "Use the timestamps of the messages instead of the time they are received.")
+	f.IntVar(&cfg.MaxMessageLength, prefix+".max-message-length", 8192, "Maximum length of the messages. Longer messages are truncated or skipped.")
+	f.DurationVar(&cfg.IdleTimeout, prefix+".idle-timeout", 120*time.Second, "Time after which idle TCP connections are closed.")
+	f.IntVar(&cfg.MaxConnections, prefix+".max-connections", 1000, "Maximum number of concurrent TCP connections. New connections wait for others to be closed once it's reached. 0 to disable.")
+	_ = cfg.BatchSize.Set("1MB")
+	f.Var(&cfg.BatchSize, prefix+".batch-size", "Maximum size of the batches of messages pushed.")
+	f.DurationVar(&cfg.BatchWait, prefix+".batch-wait", time.Second, "Maximum time messages are batched before being pushed.")
+}
+
+// Enabled returns whether the syslog listener is enabled.
+func (cfg *SyslogConfig) Enabled() bool {
+	return cfg.TCPListenAddress != "" || cfg.UDPListenAddress != ""
+}
+
+// Validate validates the configuration of the syslog listener.
+func (cfg *SyslogConfig) Validate() error {
+	if !cfg.Enabled() {
+		return nil
+	}
+	if cfg.Format != syslogFormatRFC5424 && cfg.Format != syslogFormatRFC3164 {
+		return fmt.Errorf("invalid format %q: must be %s or %s", cfg.Format, syslogFormatRFC5424, syslogFormatRFC3164)
+	}
+	if cfg.TenantID == "" {
+		return errors.New("tenant_id is required")
+	}
+	if err := mapToLabelSet(cfg.Labels).Validate(); err != nil {
+		return fmt.Errorf("invalid labels: %w", err)
+	}
+	if cfg.MaxMessageLength <= 0 {
+		return fmt.Errorf("invalid max_message_length %d: must be greater than 0", cfg.MaxMessageLength)
+	}
+	if cfg.MaxConnections < 0 {
+		return fmt.Errorf("invalid max_connections %d: must not be negative", cfg.MaxConnections)
+	}
+	if cfg.BatchWait <= 0 {
+		return fmt.Errorf("invalid batch_wait %s: must be greater than 0", cfg.BatchWait)
+	}
+	if (cfg.TLSCertFile == "") != (cfg.TLSKeyFile == "") || (cfg.TLSClientCAFile != "" && cfg.TLSCertFile == "") {
+		return errors.New("tls_cert_file and tls_key_file are required to enable TLS")
+	}
+	return nil
+}
+
+type pushFunc func(context.Context, *logproto.PushRequest) (*logproto.PushResponse, error)
+
+type syslogEntry struct {
+	labels model.LabelSet
+	entry  logproto.Entry
+}
+
+// syslogListener receives syslog messages over TCP, optionally with TLS, and UDP, and pushes them in batches to the
+// tenant of the configuration. The pushes go through the validation and the rate limits of the distributor.
+type syslogListener struct {
+	services.Service
+
+	cfg              SyslogConfig
+	push             pushFunc
+	tenantsRetention push.TenantsRetention
+	usageTracker     push.UsageTracker
+	logger           log.Logger
+	labels           model.LabelSet
+
+	tcpListener net.Listener
+	udpConn     net.PacketConn
+	entries     chan syslogEntry
+
+	// ctx is canceled when the listener stops, to close the connections.
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	// batch is only accessed by the running and stopping goroutines.
+	batch     map[string]*logproto.Stream
+	batchSize int
+
+	receivedEntries *prometheus.CounterVec
+	parsingErrors   *prometheus.CounterVec
+	failedPushes    prometheus.Counter
+}
+
+func newSyslogListener(cfg SyslogConfig, pushFn pushFunc, tenantsRetention push.TenantsRetention, usageTracker push.UsageTracker, registerer prometheus.Registerer, logger log.Logger) (*syslogListener, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	l := &syslogListener{
+		cfg:              cfg,
+		push:             pushFn,
+		tenantsRetention: tenantsRetention,
+		usageTracker:     usageTracker,
+		logger:           log.With(logger, "component", "syslog"),
+		labels:           mapToLabelSet(cfg.Labels),
+		entries:          make(chan syslogEntry, 1024),
+		batch:            map[string]*logproto.Stream{},
+		receivedEntries: promauto.With(registerer).NewCounterVec(prometheus.CounterOpts{
+			Namespace: constants.Loki,
+			Name:      "distributor_syslog_received_entries_total",
+			Help:      "The total number of entries received by the syslog listener.",
+		}, []string{"protocol"}),
+		parsingErrors: promauto.With(registerer).NewCounterVec(prometheus.CounterOpts{
+			Namespace: constants.Loki,
+			Name:      "distributor_syslog_parsing_errors_total",
+			Help:      "The total number of messages the syslog listener failed to parse.",
+		}, []string{"protocol"}),
+		failedPushes: promauto.With(registerer).NewCounter(prometheus.CounterOpts{
+			Namespace: constants.Loki,
+			Name:      "distributor_syslog_failed_pushes_total",
+			Help:      "The total number of batches of syslog entries which failed to be pushed. The entries discarded by the validation or the rate limits are also reported in the discarded samples.",
+		}),
+	}
+	l.Service = services.NewBasicService(l.starting, l.running, l.stopping)
+	return l, nil
+}
+
+func (l *syslogListener) starting(_ context.Context) error {
+	l.ctx, l.cancel = context.WithCancel(context.Background())
+
+	if l.cfg.TCPListenAddress != "" {
+		listener, err := net.Listen(syslogProtocolTCP, l.cfg.TCPListenAddress)
+		if err != nil {
+			return fmt.Errorf("error setting up syslog listener: %w", err)
+		}
+		if l.cfg.MaxConnections > 0 {
+			listener = netutil.LimitListener(listener, l.cfg.MaxConnections)
+		}
+		if l.cfg.TLSCertFile != "" {
+			tlsConfig, err := newSyslogTLSConfig(l.cfg.TLSCertFile, l.cfg.TLSKeyFile, l.cfg.TLSClientCAFile)
+			if err != nil {
+				_ = listener.Close()
+				return fmt.Errorf("error setting up syslog listener: %w", err)
+			}
+			listener = tls.NewListener(listener, tlsConfig)
+		}
+		l.tcpListener = listener
+		level.Info(l.logger).Log("msg", "syslog listening on address", "address", listener.Addr().String(), "protocol", syslogProtocolTCP, "tls", l.cfg.TLSCertFile != "")
+
+		l.wg.Add(1)
+		go l.acceptConnections()
+	}
+
+	if l.cfg.UDPListenAddress != "" {
+		conn, err := net.ListenPacket(syslogProtocolUDP, l.cfg.UDPListenAddress)
+		if err != nil {
+			l.closeListeners()
+			return fmt.Errorf("error setting up syslog listener: %w", err)
+		}
+		l.udpConn = conn
+		level.Info(l.logger).Log("msg", "syslog listening on address", "address", conn.LocalAddr().String(), "protocol", syslogProtocolUDP)
+
+		l.wg.Add(1)
+		go l.acceptPackets()
+	}
+	return nil
+}
+
+func (l *syslogListener) running(ctx context.Context) error {
+	ticker := time.NewTicker(l.cfg.BatchWait)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case e := <-l.entries:
+			l.add(e)
+		case <-ticker.C:
+			l.flush()
+		}
+	}
+}
+
+func (l *syslogListener) stopping(_ error) error {
+	l.closeListeners()
+	l.wg.Wait()
+
+	// Push the entries received before the connections were closed.
+	for {
+		select {
+		case e := <-l.entries:
+			l.add(e)
+		default:
+			l.flush()
+			return nil
+		}
+	}
+}
+
+func (l *syslogListener) closeListeners() {
+	l.cancel()
+	if l.tcpListener != nil {
+		_ = l.tcpListener.Close()
+	}
+	if l.udpConn != nil {
+		_ = l.udpConn.Close()
+	}
+}
+
+// acceptConnections accepts the TCP connections until the listener stops, retrying with backoff when accepting a
+// connection fails, for example when the process runs out of file descriptors.
+func (l *syslogListener) acceptConnections() {
+	defer l.wg.Done()
+
+	retries := backoff.New(l.ctx, backoff.Config{
+		MinBackoff: 5 * time.Millisecond,
+		MaxBackoff: time.Second,
+	})
+	for {
+		conn, err := l.tcpListener.Accept()
+		if err != nil {
+			if l.ctx.Err() != nil {
+				return
+			}
+			level.Warn(l.logger).Log("msg", "failed to accept syslog connection", "err", err, "num_retries", retries.NumRetries())
+			retries.Wait()
+			continue
+		}
+		retries.Reset()
+
+		l.wg.Add(1)
+		go l.handleConnection(conn)
+	}
+}
+
+func (l *syslogListener) handleConnection(conn net.Conn) {
+	defer l.wg.Done()
+
+	ctx, cancel := context.WithCancel(l.ctx)
+	defer cancel()
+	go func() {
+		<-ctx.Done()
+		_ = conn.Close()
+	}()
+
+	err := l.parse(&idleTimeoutConn{Conn: conn, timeout: l.cfg.IdleTimeout}, syslogProtocolTCP)
+	if err != nil && !errors.Is(err, io.EOF) && ctx.Err() == nil {
+		level.Warn(l.logger).Log("msg", "error parsing syslog stream", "remote_addr", conn.RemoteAddr().String(), "err", err)
+	}
+}
+
+func (l *syslogListener) acceptPackets() {
+	defer l.wg.Done()
+
+	buf := make([]byte, l.cfg.MaxMessageLength)
+	for {
+		n, addr, err := l.udpConn.ReadFrom(buf)
+		if err != nil {
+			if l.ctx.Err() != nil {
+				return
+			}
+			level.Warn(l.logger).Log("msg", "failed to read syslog packet", "err", err)
+			continue
+		}
+		// Each packet holds a single message, or several ones separated by newlines or octet counted.
+		if err := l.parse(bytes.NewReader(buf[:n]), syslogProtocolUDP); err != nil && !errors.Is(err, io.EOF) {
+			level.Warn(l.logger).Log("msg", "error parsing syslog packet", "remote_addr", addr.String(), "err", err)
+		}
+	}
+}
+
+func (l *syslogListener) parse(r io.Reader, protocol string) error {
+	parseStream := syslogparser.ParseStream
+	if l.cfg.Format == syslogFormatRFC3164 {
+		parseStream = syslogparser.ParseRFC3164Stream
+	}
+	received := l.receivedEntries.WithLabelValues(protocol)
+	parsingErrors := l.parsingErrors.WithLabelValues(protocol)
+
+	return parseStream(r, func(res *syslog.Result) {
+		if err := res.Error; err != nil {
+			var netErr net.Error
+			if errors.As(err, &netErr) || l.ctx.Err() != nil {
+				// The connection timed out or was closed.
+				return
+			}
+			level.Debug(l.logger).Log("msg", "error parsing syslog message", "err", err)
+			parsingErrors.Inc()
+			return
+		}
+		e, ok := l.toEntry(res.Message)
+		if !ok {
+			return
+		}
+		received.Inc()
+		select {
+		case l.entries <- e:
+		case <-l.ctx.Done():
+		}
+	}, l.cfg.MaxMessageLength)
+}
+
+// toEntry converts a message to an entry, labeled with the host, app, facility and severity of the message. Messages
+// without content are ignored.
+func (l *syslogListener) toEntry(msg syslog.Message) (syslogEntry, bool) {
+	var base *syslog.Base
+	switch m := msg.(type) {
+	case *rfc5424.SyslogMessage:
+		base = &m.Base
+	case *rfc3164.SyslogMessage:
+		base = &m.Base
+	default:
+		return syslogEntry{}, false
+	}
+	if base.Message == nil || *base.Message == "" {
+		return syslogEntry{}, false
+	}
+
+	lbs := l.labels.Clone()
+	setLabel := func(name model.LabelName, value *string) {
+		if value != nil && *value != "" {
+			lbs[name] = model.LabelValue(*value)
+		}
+	}
+	setLabel(syslogHostLabel, base.Hostname)
+	setLabel(syslogAppLabel, base.Appname)
+	setLabel(syslogFacilityLabel, base.FacilityLevel())
+	setLabel(syslogSeverityLabel, base.SeverityLevel())
+
+	ts := time.Now()
+	if l.cfg.UseIncomingTimestamp && base.Timestamp != nil {
+		ts = *base.Timestamp
+	}
+	return syslogEntry{labels: lbs, entry: logproto.Entry{Timestamp: ts, Line: *base.Message}}, true
+}
+
+func (l *syslogListener) add(e syslogEntry) {
+	key := e.labels.String()
+	stream, ok := l.batch[key]
+	if !ok {
+		stream = &logproto.Stream{Labels: key}
+		l.batch[key] = stream
+	}
+	stream.Entries = append(stream.Entries, e.entry)
+	l.batchSize += len(e.entry.Line)
+
+	if l.batchSize >= l.cfg.BatchSize.Val() {
+		l.flush()
+	}
+}
+
+// flush pushes the batch to the tenant. Invalid entries, and entries exceeding the rate limits, are discarded by the
+// push as for any other client.
+func (l *syslogListener) flush() {
+	if len(l.batch) == 0 {
+		return
+	}
+	req := &logproto.PushRequest{Streams: make([]logproto.Stream, 0, len(l.batch))}
+	for _, stream := range l.batch {
+		req.Streams = append(req.Streams, *stream)
+	}
+	l.batch = map[string]*logproto.Stream{}
+	l.batchSize = 0
+
+	ctx := user.InjectOrgID(context.Background(), l.cfg.TenantID)
+	if l.usageTracker != nil {
+		l.trackReceivedBytes(ctx, req.Streams)
+	}
+	if _, err := l.push(ctx, req); err != nil {
+		l.failedPushes.Inc()
+		level.Warn(l.logger).Log("msg", "failed to push syslog entries", "tenant", l.cfg.TenantID, "err", err)
+	}
+}
+
+// trackReceivedBytes records the size of the entries received in the usage tracker, as for the entries pushed over
+// HTTP.
+func (l *syslogListener) trackReceivedBytes(ctx context.Context, streams []logproto.Stream) {
+	for _, s := range streams {
+		lbs, err := syntax.ParseLabels(s.Labels)
+		if err != nil {
+			level.Warn(l.logger).Log("msg", "couldn't parse labels", "labels", s.Labels, "err", err)
+			continue
+		}
+		var retentionPeriod time.Duration
+		if l.tenantsRetention != nil {
+			retentionPeriod = l.tenantsRetention.RetentionPeriodFor(l.cfg.TenantID, lbs)
+		}
+		var size int
+		for _, e := range s.Entries {
+			size += len(e.Line)
+		}
+		l.usageTracker.ReceivedBytesAdd(ctx, l.cfg.TenantID, retentionPeriod, lbs, float64(size))
+	}
+}
+
+// idleTimeoutConn closes the connections without data for the idle timeout.
+type idleTimeoutConn struct {
+	net.Conn
+	timeout time.Duration
+}
+
+func (c *idleTimeoutConn) Read(b []byte) (int, error) {
+	if c.timeout > 0 {
+		_ = c.Conn.SetReadDeadline(time.Now().Add(c.timeout))
+	}
+	return c.Conn.Read(b)
+}
+
+func newSyslogTLSConfig(certFile, keyFile, caFile string) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load server certificate or key: %w", err)
+	}
+	tlsConfig := &tls.Config{Certificates: []tls.Certificate{cert}}
+
+	if caFile != "" {
+		caCert, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load client CA certificate: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caCert) {
+			return nil, errors.New("unable to parse client CA certificate")
+		}
+		tlsConfig.ClientCAs = pool
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return tlsConfig, nil
+}
+
+func mapToLabelSet(m map[string]string) model.LabelSet {
+	lbs := make(model.LabelSet, len(m))
+	for name, value := range m {
+		lbs[model.LabelName(name)] = model.LabelValue(value)
+	}
+	return lbs
+}
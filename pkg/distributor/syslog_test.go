@@ -0,0 +1,252 @@
+package distributor
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/services"
+	"github.com/grafana/dskit/tenant"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/pkg/logproto"
+)
+
+type syslogPushRecorder struct {
+	mtx     sync.Mutex
+	tenants []string
+	lines   map[string][]string // Lines per stream.
+}
+
+func (p *syslogPushRecorder) push(ctx context.Context, req *logproto.PushRequest) (*logproto.PushResponse, error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	p.tenants = append(p.tenants, tenantID)
+	for _, s := range req.Streams {
+		for _, e := range s.Entries {
+			p.lines[s.Labels] = append(p.lines[s.Labels], e.Line)
+		}
+	}
+	return &logproto.PushResponse{}, nil
+}
+
+func (p *syslogPushRecorder) count() int {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	var n int
+	for _, lines := range p.lines {
+		n += len(lines)
+	}
+	return n
+}
+
+type syslogUsageTracker struct {
+	mtx      sync.Mutex
+	received map[string]float64 // Bytes received per tenant and stream.
+}
+
+func (u *syslogUsageTracker) ReceivedBytesAdd(_ context.Context, tenant string, _ time.Duration, lbs labels.Labels, value float64) {
+	u.mtx.Lock()
+	defer u.mtx.Unlock()
+	u.received[tenant+lbs.String()] += value
+}
+
+func (u *syslogUsageTracker) DiscardedBytesAdd(_ context.Context, _, _ string, _ labels.Labels, _ float64) {
+}
+
+func (u *syslogUsageTracker) receivedBytes(key string) float64 {
+	u.mtx.Lock()
+	defer u.mtx.Unlock()
+	return u.received[key]
+}
+
+func newTestSyslogListener(t *testing.T, modify func(*SyslogConfig)) (*syslogListener, *syslogPushRecorder) {
+	cfg := SyslogConfig{
+		TCPListenAddress: "127.0.0.1:0",
+		UDPListenAddress: "127.0.0.1:0",
+		Format:           syslogFormatRFC5424,
+		TenantID:         "syslog",
+		Labels:           map[string]string{"job": "syslog"},
+		MaxMessageLength: 8192,
+		IdleTimeout:      time.Minute,
+		BatchWait:        10 * time.Millisecond,
+	}
+	_ = cfg.BatchSize.Set("1MB")
+	if modify != nil {
+		modify(&cfg)
+	}
+
+	recorder := &syslogPushRecorder{lines: map[string][]string{}}
+	tracker := &syslogUsageTracker{received: map[string]float64{}}
+	l, err := newSyslogListener(cfg, recorder.push, nil, tracker, prometheus.NewRegistry(), log.NewNopLogger())
+	require.NoError(t, err)
+	require.NoError(t, services.StartAndAwaitRunning(context.Background(), l))
+	t.Cleanup(func() {
+		_ = services.StopAndAwaitTerminated(context.Background(), l)
+	})
+	return l, recorder
+}
+
+func TestSyslogListener_RFC5424(t *testing.T) {
+	l, recorder := newTestSyslogListener(t, nil)
+
+	tcp, err := net.Dial("tcp", l.tcpListener.Addr().String())
+	require.NoError(t, err)
+	_, err = fmt.Fprint(tcp, "<165>1 2023-10-11T22:14:15.003Z router1 sshd 12 ID47 - First\n<13>1 - router1 sshd - - - Second\n")
+	require.NoError(t, err)
+	require.NoError(t, tcp.Close())
+
+	udp, err := net.Dial("udp", l.udpConn.LocalAddr().String())
+	require.NoError(t, err)
+	defer udp.Close()
+	msg := "<11>1 - switch2 kernel - - - Third"
+	_, err = fmt.Fprintf(udp, "%d %s", len(msg), msg)
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool { return recorder.count() == 3 }, 5*time.Second, 10*time.Millisecond)
+
+	recorder.mtx.Lock()
+	defer recorder.mtx.Unlock()
+	require.Equal(t, []string{"First"}, recorder.lines[`{app="sshd", facility="local4", host="router1", job="syslog", severity="notice"}`])
+	require.Equal(t, []string{"Second"}, recorder.lines[`{app="sshd", facility="user", host="router1", job="syslog", severity="notice"}`])
+	require.Equal(t, []string{"Third"}, recorder.lines[`{app="kernel", facility="user", host="switch2", job="syslog", severity="error"}`])
+	for _, tenantID := range recorder.tenants {
+		require.Equal(t, "syslog", tenantID)
+	}
+
+	tracker := l.usageTracker.(*syslogUsageTracker)
+	require.Equal(t, float64(len("First")), tracker.receivedBytes(`syslog{app="sshd", facility="local4", host="router1", job="syslog", severity="notice"}`))
+	require.Equal(t, float64(len("Third")), tracker.receivedBytes(`syslog{app="kernel", facility="user", host="switch2", job="syslog", severity="error"}`))
+}
+
+func TestSyslogListener_RFC3164(t *testing.T) {
+	l, recorder := newTestSyslogListener(t, func(cfg *SyslogConfig) {
+		cfg.Format = syslogFormatRFC3164
+		cfg.UseIncomingTimestamp = true
+	})
+
+	tcp, err := net.Dial("tcp", l.tcpListener.Addr().String())
+	require.NoError(t, err)
+	_, err = fmt.Fprint(tcp, "<34>Oct 11 22:14:15 mymachine su: 'su root' failed for lonvick on /dev/pts/8\n")
+	require.NoError(t, err)
+	require.NoError(t, tcp.Close())
+
+	require.Eventually(t, func() bool { return recorder.count() == 1 }, 5*time.Second, 10*time.Millisecond)
+
+	recorder.mtx.Lock()
+	defer recorder.mtx.Unlock()
+	require.Equal(t, []string{"'su root' failed for lonvick on /dev/pts/8"}, recorder.lines[`{app="su", facility="auth", host="mymachine", job="syslog", severity="critical"}`])
+}
+
+func TestSyslogListener_FlushOnStop(t *testing.T) {
+	l, recorder := newTestSyslogListener(t, func(cfg *SyslogConfig) {
+		cfg.BatchWait = time.Hour
+	})
+
+	tcp, err := net.Dial("tcp", l.tcpListener.Addr().String())
+	require.NoError(t, err)
+	_, err = fmt.Fprint(tcp, "<13>1 - host app - - - First\n")
+	require.NoError(t, err)
+	require.NoError(t, tcp.Close())
+
+	// The message is pushed when the listener stops, even though the batch isn't full.
+	require.Eventually(t, func() bool {
+		return testutil.ToFloat64(l.receivedEntries.WithLabelValues(syslogProtocolTCP)) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, services.StopAndAwaitTerminated(context.Background(), l))
+	require.Equal(t, 1, recorder.count())
+}
+
+func TestSyslogListener_MaxConnections(t *testing.T) {
+	l, recorder := newTestSyslogListener(t, func(cfg *SyslogConfig) {
+		cfg.MaxConnections = 1
+	})
+
+	first, err := net.Dial("tcp", l.tcpListener.Addr().String())
+	require.NoError(t, err)
+	_, err = fmt.Fprint(first, "<13>1 - host app - - - First\n")
+	require.NoError(t, err)
+
+	// The second connection is only accepted once the first one is closed.
+	second, err := net.Dial("tcp", l.tcpListener.Addr().String())
+	require.NoError(t, err)
+	_, err = fmt.Fprint(second, "<13>1 - host app - - - Second\n")
+	require.NoError(t, err)
+	require.NoError(t, second.Close())
+	require.Never(t, func() bool {
+		return testutil.ToFloat64(l.receivedEntries.WithLabelValues(syslogProtocolTCP)) > 0
+	}, 200*time.Millisecond, 10*time.Millisecond)
+
+	require.NoError(t, first.Close())
+	require.Eventually(t, func() bool { return recorder.count() == 2 }, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestSyslogConfig_Validate(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		modify func(*SyslogConfig)
+		err    string
+	}{
+		{
+			name:   "disabled",
+			modify: func(cfg *SyslogConfig) { cfg.TCPListenAddress, cfg.Format = "", "" },
+		},
+		{
+			name:   "valid",
+			modify: func(*SyslogConfig) {},
+		},
+		{
+			name:   "invalid format",
+			modify: func(cfg *SyslogConfig) { cfg.Format = "rfc1234" },
+			err:    `invalid format "rfc1234": must be rfc5424 or rfc3164`,
+		},
+		{
+			name:   "missing tenant",
+			modify: func(cfg *SyslogConfig) { cfg.TenantID = "" },
+			err:    "tenant_id is required",
+		},
+		{
+			name:   "invalid labels",
+			modify: func(cfg *SyslogConfig) { cfg.Labels = map[string]string{"0job": "syslog"} },
+			err:    `invalid labels: invalid name "0job"`,
+		},
+		{
+			name:   "negative max connections",
+			modify: func(cfg *SyslogConfig) { cfg.MaxConnections = -1 },
+			err:    "invalid max_connections -1: must not be negative",
+		},
+		{
+			name:   "missing tls key",
+			modify: func(cfg *SyslogConfig) { cfg.TLSCertFile = "cert.pem" },
+			err:    "tls_cert_file and tls_key_file are required to enable TLS",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := SyslogConfig{
+				TCPListenAddress: "127.0.0.1:0",
+				Format:           syslogFormatRFC5424,
+				TenantID:         "syslog",
+				MaxMessageLength: 8192,
+				BatchWait:        time.Second,
+			}
+			tc.modify(&cfg)
+			err := cfg.Validate()
+			if tc.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, tc.err)
+		})
+	}
+}
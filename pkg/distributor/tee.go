@@ -1,6 +1,224 @@
 package distributor
 
+import (
+	"flag"
+	"fmt"
+	"math"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/flagext"
+	"github.com/grafana/dskit/services"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/prometheus/model/labels"
+
+	"github.com/grafana/loki/pkg/logql/syntax"
+	"github.com/grafana/loki/pkg/util/constants"
+)
+
 // Tee implementations can duplicate the log streams to another endpoint.
 type Tee interface {
 	Duplicate(tenant string, streams []KeyedStream)
 }
+
+// TeeConfig configures the built-in tees of the distributor.
+type TeeConfig struct {
+	Forward ForwardTeeConfig `yaml:"forward" doc:"description=Asynchronously forwards the selected streams to another Loki."`
+	Spool   SpoolTeeConfig   `yaml:"spool" doc:"description=Asynchronously writes the selected streams to files in a local directory."`
+}
+
+// RegisterFlagsWithPrefix registers the flags of the built-in tees.
+func (cfg *TeeConfig) RegisterFlagsWithPrefix(prefix string, f *flag.FlagSet) {
+	cfg.Forward.RegisterFlagsWithPrefix(prefix+".forward", f)
+	cfg.Spool.RegisterFlagsWithPrefix(prefix+".spool", f)
+}
+
+// TeeSelectionConfig selects the streams duplicated by a tee.
+type TeeSelectionConfig struct {
+	Tenants     flagext.StringSliceCSV `yaml:"tenants"`
+	Selector    string                 `yaml:"selector"`
+	SampleRatio float64                `yaml:"sample_ratio"`
+}
+
+// RegisterFlagsWithPrefix registers the flags selecting the streams of a tee.
+func (cfg *TeeSelectionConfig) RegisterFlagsWithPrefix(prefix string, f *flag.FlagSet) {
+	f.Var(&cfg.Tenants, prefix+".tenants", "Comma-separated list of the tenants whose streams are duplicated. All tenants if empty.")
+	f.StringVar(&cfg.Selector, prefix+".selector", "", "LogQL stream selector the streams duplicated must match, for example {app=\"nginx\"}. All streams if empty.")
+	f.Float64Var(&cfg.SampleRatio, prefix+".sample-ratio", 1, "Ratio of the streams duplicated, between 0 and 1. The streams are sampled by their labels, so all the entries of a sampled stream are duplicated.")
+}
+
+// teeSelector selects the streams duplicated by a tee.
+type teeSelector struct {
+	tenants   map[string]struct{}
+	matchers  []*labels.Matcher
+	threshold uint64
+}
+
+func newTeeSelector(cfg TeeSelectionConfig) (*teeSelector, error) {
+	if cfg.SampleRatio < 0 || cfg.SampleRatio > 1 {
+		return nil, fmt.Errorf("invalid sample_ratio %v: must be between 0 and 1", cfg.SampleRatio)
+	}
+	s := &teeSelector{
+		threshold: uint64(cfg.SampleRatio * (math.MaxUint32 + 1)),
+	}
+	if len(cfg.Tenants) > 0 {
+		s.tenants = make(map[string]struct{}, len(cfg.Tenants))
+		for _, tenant := range cfg.Tenants {
+			s.tenants[tenant] = struct{}{}
+		}
+	}
+	if cfg.Selector != "" {
+		matchers, err := syntax.ParseMatchers(cfg.Selector, true)
+		if err != nil {
+			return nil, fmt.Errorf("invalid selector %q: %w", cfg.Selector, err)
+		}
+		s.matchers = matchers
+	}
+	return s, nil
+}
+
+// selectStreams returns the streams of the tenant sampled. It is called on the push path, so the streams are only
+// matched against the selector later, with matches.
+func (s *teeSelector) selectStreams(tenant string, streams []KeyedStream) []KeyedStream {
+	if s.tenants != nil {
+		if _, ok := s.tenants[tenant]; !ok {
+			return nil
+		}
+	}
+	if s.threshold > math.MaxUint32 {
+		return streams
+	}
+	var selected []KeyedStream
+	for _, stream := range streams {
+		// The hash key of a stream is the hash of its tenant and labels.
+		if uint64(stream.HashKey) < s.threshold {
+			selected = append(selected, stream)
+		}
+	}
+	return selected
+}
+
+// matches returns whether the labels of a stream match the selector.
+func (s *teeSelector) matches(stream string) (bool, error) {
+	if len(s.matchers) == 0 {
+		return true, nil
+	}
+	lbs, err := syntax.ParseLabels(stream)
+	if err != nil {
+		return false, err
+	}
+	return s.matchLabels(lbs), nil
+}
+
+// matchLabels returns whether the labels match the selector.
+func (s *teeSelector) matchLabels(lbs labels.Labels) bool {
+	for _, m := range s.matchers {
+		if !m.Matches(lbs.Get(m.Name)) {
+			return false
+		}
+	}
+	return true
+}
+
+// teeItem is a set of streams pushed by a tenant, queued to be duplicated.
+type teeItem struct {
+	tenant   string
+	streams  []KeyedStream
+	enqueued int64 // Unix nanoseconds.
+}
+
+// teeMetrics are the metrics of the built-in tees, labeled by tee.
+type teeMetrics struct {
+	sent        *prometheus.CounterVec
+	dropped     *prometheus.CounterVec
+	queueLength *prometheus.GaugeVec
+	lag         *prometheus.HistogramVec
+}
+
+func newTeeMetrics(registerer prometheus.Registerer) *teeMetrics {
+	return &teeMetrics{
+		sent: promauto.With(registerer).NewCounterVec(prometheus.CounterOpts{
+			Namespace: constants.Loki,
+			Name:      "distributor_tee_sent_entries_total",
+			Help:      "The total number of entries duplicated by the tee.",
+		}, []string{"tee"}),
+		dropped: promauto.With(registerer).NewCounterVec(prometheus.CounterOpts{
+			Namespace: constants.Loki,
+			Name:      "distributor_tee_dropped_entries_total",
+			Help:      "The total number of entries the tee failed to duplicate.",
+		}, []string{"tee", "reason"}),
+		queueLength: promauto.With(registerer).NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: constants.Loki,
+			Name:      "distributor_tee_queue_length",
+			Help:      "The number of pushes queued to be duplicated by the tee.",
+		}, []string{"tee"}),
+		lag: promauto.With(registerer).NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: constants.Loki,
+			Name:      "distributor_tee_lag_seconds",
+			Help:      "Time between the push of entries and their duplication by the tee.",
+			Buckets:   prometheus.ExponentialBuckets(0.01, 4, 8),
+		}, []string{"tee"}),
+	}
+}
+
+// countEntries returns the number of entries of the streams.
+func countEntries(streams []KeyedStream) int {
+	var n int
+	for _, s := range streams {
+		n += len(s.Stream.Entries)
+	}
+	return n
+}
+
+// newBuiltinTees creates the enabled built-in tees, returning them along with the services running them.
+func newBuiltinTees(cfg TeeConfig, registerer prometheus.Registerer, logger log.Logger) ([]Tee, []services.Service, error) {
+	if cfg.Forward.URL == "" && cfg.Spool.Directory == "" {
+		return nil, nil, nil
+	}
+	var (
+		metrics = newTeeMetrics(registerer)
+		tees    []Tee
+		servs   []services.Service
+	)
+	if cfg.Forward.URL != "" {
+		t, err := newForwardTee(cfg.Forward, metrics, logger)
+		if err != nil {
+			return nil, nil, fmt.Errorf("forward tee: %w", err)
+		}
+		tees, servs = append(tees, t), append(servs, t)
+	}
+	if cfg.Spool.Directory != "" {
+		t, err := newSpoolTee(cfg.Spool, metrics, logger)
+		if err != nil {
+			return nil, nil, fmt.Errorf("spool tee: %w", err)
+		}
+		tees, servs = append(tees, t), append(servs, t)
+	}
+	return tees, servs, nil
+}
+
+// WrapTee combines tees into a single one, ignoring the nil ones. It returns nil if there is no tee.
+func WrapTee(tees ...Tee) Tee {
+	var nonNil multiTee
+	for _, t := range tees {
+		if t != nil {
+			nonNil = append(nonNil, t)
+		}
+	}
+	switch len(nonNil) {
+	case 0:
+		return nil
+	case 1:
+		return nonNil[0]
+	default:
+		return nonNil
+	}
+}
+
+type multiTee []Tee
+
+func (m multiTee) Duplicate(tenant string, streams []KeyedStream) {
+	for _, t := range m {
+		t.Duplicate(tenant, streams)
+	}
+}
@@ -0,0 +1,267 @@
+package distributor
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/golang/snappy"
+	"github.com/grafana/dskit/backoff"
+	"github.com/grafana/dskit/services"
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/util/build"
+	"github.com/grafana/loki/pkg/util/flagext"
+)
+
+const (
+	forwardTeeName = "forward"
+	// maxErrorBodySize is the size of the response body logged when a push fails.
+	maxErrorBodySize = 1024
+)
+
+var forwardTeeUserAgent = fmt.Sprintf("loki-distributor-tee/%s", build.Version)
+
+// ForwardTeeConfig configures the tee forwarding streams to another Loki.
+type ForwardTeeConfig struct {
+	URL       string             `yaml:"url"`
+	Selection TeeSelectionConfig `yaml:",inline"`
+	BatchSize flagext.ByteSize   `yaml:"batch_size"`
+	BatchWait time.Duration      `yaml:"batch_wait"`
+	QueueSize int                `yaml:"queue_size"`
+	Timeout   time.Duration      `yaml:"timeout"`
+	Backoff   backoff.Config     `yaml:"backoff_config"`
+}
+
+// RegisterFlagsWithPrefix registers the flags of the forward tee.
+func (cfg *ForwardTeeConfig) RegisterFlagsWithPrefix(prefix string, f *flag.FlagSet) {
+	f.StringVar(&cfg.URL, prefix+".url", "", "URL of the push endpoint of the Loki the streams are forwarded to, for example http://loki:3100/loki/api/v1/push. The tee is disabled if empty.")
+	cfg.Selection.RegisterFlagsWithPrefix(prefix, f)
+	_ = cfg.BatchSize.Set("1MB")
+	f.Var(&cfg.BatchSize, prefix+".batch-size", "Maximum size of the batches of entries forwarded per tenant.")
+	f.DurationVar(&cfg.BatchWait, prefix+".batch-wait", time.Second, "Maximum time entries are batched before being forwarded.")
+	f.IntVar(&cfg.QueueSize, prefix+".queue-size", 1000, "Maximum number of pushes queued to be forwarded. Pushes are dropped when the queue is full.")
+	f.DurationVar(&cfg.Timeout, prefix+".timeout", 10*time.Second, "Timeout of the requests to the push endpoint.")
+	f.DurationVar(&cfg.Backoff.MinBackoff, prefix+".min-backoff", 500*time.Millisecond, "Minimum delay before retrying a failed push.")
+	f.DurationVar(&cfg.Backoff.MaxBackoff, prefix+".max-backoff", 5*time.Second, "Maximum delay before retrying a failed push.")
+	f.IntVar(&cfg.Backoff.MaxRetries, prefix+".max-retries", 5, "Maximum number of attempts to push a batch before dropping it.")
+}
+
+// forwardTee asynchronously pushes the selected streams to another Loki. The streams are queued in a bounded queue,
+// batched per tenant, and pushed with retries. When the queue is full, or when a batch can't be pushed, the entries
+// are dropped, so the tee never slows down or fails the pushes to the distributor.
+type forwardTee struct {
+	services.Service
+
+	cfg      ForwardTeeConfig
+	selector *teeSelector
+	client   *http.Client
+	logger   log.Logger
+	queue    chan teeItem
+
+	// batches are only accessed by the running goroutine.
+	batches map[string]*forwardBatch
+
+	metrics     *teeMetrics
+	queueLength prometheus.Gauge
+	queueFull   prometheus.Counter
+}
+
+// forwardBatch is a batch of entries of a tenant.
+type forwardBatch struct {
+	streams  map[string]*logproto.Stream
+	size     int
+	entries  int
+	enqueued int64 // The time the oldest entry was queued, in Unix nanoseconds.
+}
+
+func newForwardTee(cfg ForwardTeeConfig, metrics *teeMetrics, logger log.Logger) (*forwardTee, error) {
+	if _, err := url.Parse(cfg.URL); err != nil {
+		return nil, fmt.Errorf("invalid url: %w", err)
+	}
+	if cfg.QueueSize <= 0 {
+		return nil, fmt.Errorf("invalid queue_size %d: must be greater than 0", cfg.QueueSize)
+	}
+	if cfg.BatchWait <= 0 {
+		return nil, fmt.Errorf("invalid batch_wait %s: must be greater than 0", cfg.BatchWait)
+	}
+	selector, err := newTeeSelector(cfg.Selection)
+	if err != nil {
+		return nil, err
+	}
+	t := &forwardTee{
+		cfg:         cfg,
+		selector:    selector,
+		client:      &http.Client{},
+		logger:      log.With(logger, "tee", forwardTeeName),
+		queue:       make(chan teeItem, cfg.QueueSize),
+		batches:     map[string]*forwardBatch{},
+		metrics:     metrics,
+		queueLength: metrics.queueLength.WithLabelValues(forwardTeeName),
+		queueFull:   metrics.dropped.WithLabelValues(forwardTeeName, "queue_full"),
+	}
+	t.Service = services.NewBasicService(nil, t.running, t.stopping)
+	return t, nil
+}
+
+// Duplicate implements Tee.
+func (t *forwardTee) Duplicate(tenant string, streams []KeyedStream) {
+	streams = t.selector.selectStreams(tenant, streams)
+	if len(streams) == 0 {
+		return
+	}
+	select {
+	case t.queue <- teeItem{tenant: tenant, streams: streams, enqueued: time.Now().UnixNano()}:
+		t.queueLength.Inc()
+	default:
+		t.queueFull.Add(float64(countEntries(streams)))
+	}
+}
+
+func (t *forwardTee) running(ctx context.Context) error {
+	ticker := time.NewTicker(t.cfg.BatchWait)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case item := <-t.queue:
+			t.queueLength.Dec()
+			t.add(ctx, item)
+		case <-ticker.C:
+			t.flush(ctx)
+		}
+	}
+}
+
+// stopping forwards the queued entries, trying once for at most the request timeout.
+func (t *forwardTee) stopping(_ error) error {
+	ctx, cancel := context.WithTimeout(context.Background(), t.cfg.Timeout)
+	defer cancel()
+	for {
+		select {
+		case item := <-t.queue:
+			t.queueLength.Dec()
+			t.add(ctx, item)
+		default:
+			t.flush(ctx)
+			return nil
+		}
+	}
+}
+
+// add adds the streams matching the selector to the batch of the tenant, pushing it once full.
+func (t *forwardTee) add(ctx context.Context, item teeItem) {
+	for _, s := range item.streams {
+		ok, err := t.selector.matches(s.Stream.Labels)
+		if err != nil {
+			level.Warn(t.logger).Log("msg", "failed to parse stream labels", "labels", s.Stream.Labels, "err", err)
+			continue
+		}
+		if !ok {
+			continue
+		}
+
+		b, ok := t.batches[item.tenant]
+		if !ok {
+			b = &forwardBatch{streams: map[string]*logproto.Stream{}, enqueued: item.enqueued}
+			t.batches[item.tenant] = b
+		}
+		stream, ok := b.streams[s.Stream.Labels]
+		if !ok {
+			stream = &logproto.Stream{Labels: s.Stream.Labels}
+			b.streams[s.Stream.Labels] = stream
+		}
+		stream.Entries = append(stream.Entries, s.Stream.Entries...)
+		for _, e := range s.Stream.Entries {
+			b.size += e.Size()
+		}
+		b.entries += len(s.Stream.Entries)
+
+		if b.size >= t.cfg.BatchSize.Val() {
+			delete(t.batches, item.tenant)
+			t.send(ctx, item.tenant, b)
+		}
+	}
+}
+
+// flush pushes the batches of all tenants.
+func (t *forwardTee) flush(ctx context.Context) {
+	for tenant, b := range t.batches {
+		delete(t.batches, tenant)
+		t.send(ctx, tenant, b)
+	}
+}
+
+// send pushes a batch, retrying on network errors, rate limiting and server errors.
+func (t *forwardTee) send(ctx context.Context, tenant string, b *forwardBatch) {
+	req := &logproto.PushRequest{Streams: make([]logproto.Stream, 0, len(b.streams))}
+	for _, s := range b.streams {
+		req.Streams = append(req.Streams, *s)
+	}
+	buf, err := req.Marshal()
+	if err != nil {
+		level.Error(t.logger).Log("msg", "failed to marshal push request", "tenant", tenant, "err", err)
+		t.metrics.dropped.WithLabelValues(forwardTeeName, "send_failed").Add(float64(b.entries))
+		return
+	}
+	body := snappy.Encode(nil, buf)
+
+	var status int
+	retries := backoff.New(ctx, t.cfg.Backoff)
+	for retries.Ongoing() {
+		status, err = t.push(ctx, tenant, body)
+		if err == nil {
+			t.metrics.sent.WithLabelValues(forwardTeeName).Add(float64(b.entries))
+			t.metrics.lag.WithLabelValues(forwardTeeName).Observe(time.Since(time.Unix(0, b.enqueued)).Seconds())
+			return
+		}
+		// Only retry on network errors, rate limiting and server errors.
+		if status > 0 && status != http.StatusTooManyRequests && status/100 != 5 {
+			break
+		}
+		retries.Wait()
+	}
+	if err == nil {
+		err = retries.Err()
+	}
+	level.Warn(t.logger).Log("msg", "failed to forward entries", "tenant", tenant, "entries", b.entries, "retries", retries.NumRetries(), "err", err)
+	t.metrics.dropped.WithLabelValues(forwardTeeName, "send_failed").Add(float64(b.entries))
+}
+
+// push sends a push request, returning the status code of the response if any.
+func (t *forwardTee) push(ctx context.Context, tenant string, body []byte) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, t.cfg.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("User-Agent", forwardTeeUserAgent)
+	req.Header.Set(user.OrgIDHeaderName, tenant)
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+		return resp.StatusCode, fmt.Errorf("server returned HTTP status %s: %s", resp.Status, bytes.TrimSpace(msg))
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,153 @@
+package distributor
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/golang/snappy"
+	"github.com/grafana/dskit/services"
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/pkg/logproto"
+)
+
+type pushRecorder struct {
+	mtx      sync.Mutex
+	requests map[string][]logproto.PushRequest
+	statuses []int // Statuses returned by the first requests.
+}
+
+func (p *pushRecorder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	if len(p.statuses) > 0 {
+		status := p.statuses[0]
+		p.statuses = p.statuses[1:]
+		w.WriteHeader(status)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	buf, err := snappy.Decode(nil, body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	var req logproto.PushRequest
+	if err := req.Unmarshal(buf); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	tenant := r.Header.Get(user.OrgIDHeaderName)
+	p.requests[tenant] = append(p.requests[tenant], req)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (p *pushRecorder) entries(tenant string) int {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	var n int
+	for _, req := range p.requests[tenant] {
+		for _, s := range req.Streams {
+			n += len(s.Entries)
+		}
+	}
+	return n
+}
+
+func newTestForwardTee(t *testing.T, url string, modify func(*ForwardTeeConfig)) (*forwardTee, *teeMetrics) {
+	cfg := ForwardTeeConfig{URL: url, Selection: TeeSelectionConfig{SampleRatio: 1}}
+	_ = cfg.BatchSize.Set("1MB")
+	cfg.BatchWait = 10 * time.Millisecond
+	cfg.QueueSize = 10
+	cfg.Timeout = time.Second
+	cfg.Backoff.MinBackoff = time.Millisecond
+	cfg.Backoff.MaxBackoff = time.Millisecond
+	cfg.Backoff.MaxRetries = 3
+	if modify != nil {
+		modify(&cfg)
+	}
+	metrics := newTeeMetrics(prometheus.NewRegistry())
+	tee, err := newForwardTee(cfg, metrics, log.NewNopLogger())
+	require.NoError(t, err)
+	return tee, metrics
+}
+
+func TestForwardTee(t *testing.T) {
+	recorder := &pushRecorder{requests: map[string][]logproto.PushRequest{}}
+	server := httptest.NewServer(recorder)
+	defer server.Close()
+
+	tee, metrics := newTestForwardTee(t, server.URL, func(cfg *ForwardTeeConfig) {
+		cfg.Selection.Selector = `{app="api"}`
+	})
+	require.NoError(t, services.StartAndAwaitRunning(context.Background(), tee))
+
+	tee.Duplicate("a", []KeyedStream{
+		makeTeeStream(`{app="api"}`, 0, "1", "2"),
+		makeTeeStream(`{app="db"}`, 0, "3"),
+	})
+	tee.Duplicate("a", []KeyedStream{makeTeeStream(`{app="api"}`, 0, "4")})
+	tee.Duplicate("b", []KeyedStream{makeTeeStream(`{app="api"}`, 0, "5")})
+
+	require.Eventually(t, func() bool {
+		return recorder.entries("a") == 3 && recorder.entries("b") == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, services.StopAndAwaitTerminated(context.Background(), tee))
+
+	require.Equal(t, 4.0, testutil.ToFloat64(metrics.sent.WithLabelValues(forwardTeeName)))
+	require.Equal(t, 0.0, testutil.ToFloat64(metrics.queueLength.WithLabelValues(forwardTeeName)))
+}
+
+func TestForwardTee_Retries(t *testing.T) {
+	recorder := &pushRecorder{
+		requests: map[string][]logproto.PushRequest{},
+		statuses: []int{http.StatusTooManyRequests, http.StatusInternalServerError},
+	}
+	server := httptest.NewServer(recorder)
+	defer server.Close()
+
+	tee, metrics := newTestForwardTee(t, server.URL, nil)
+	b := &forwardBatch{streams: map[string]*logproto.Stream{}, entries: 1, enqueued: time.Now().UnixNano()}
+	b.streams[`{app="api"}`] = &logproto.Stream{Labels: `{app="api"}`, Entries: []logproto.Entry{{Timestamp: time.Unix(0, 1), Line: "1"}}}
+	tee.send(context.Background(), "a", b)
+	require.Equal(t, 1, recorder.entries("a"))
+	require.Equal(t, 1.0, testutil.ToFloat64(metrics.sent.WithLabelValues(forwardTeeName)))
+
+	// Client errors are not retried.
+	recorder.statuses = []int{http.StatusBadRequest}
+	tee.send(context.Background(), "a", b)
+	require.Equal(t, 1, recorder.entries("a"))
+	require.Equal(t, 1.0, testutil.ToFloat64(metrics.dropped.WithLabelValues(forwardTeeName, "send_failed")))
+
+	// The batch is dropped once the retries are exhausted.
+	recorder.statuses = []int{http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway}
+	tee.send(context.Background(), "a", b)
+	require.Equal(t, 1, recorder.entries("a"))
+	require.Equal(t, 2.0, testutil.ToFloat64(metrics.dropped.WithLabelValues(forwardTeeName, "send_failed")))
+}
+
+func TestForwardTee_QueueFull(t *testing.T) {
+	// The tee isn't started, so the queue is never consumed.
+	tee, metrics := newTestForwardTee(t, "http://localhost", func(cfg *ForwardTeeConfig) {
+		cfg.QueueSize = 1
+	})
+	tee.Duplicate("a", []KeyedStream{makeTeeStream(`{app="api"}`, 0, "1")})
+	tee.Duplicate("a", []KeyedStream{makeTeeStream(`{app="api"}`, 0, "2", "3")})
+
+	require.Equal(t, 1.0, testutil.ToFloat64(metrics.queueLength.WithLabelValues(forwardTeeName)))
+	require.Equal(t, 2.0, testutil.ToFloat64(metrics.dropped.WithLabelValues(forwardTeeName, "queue_full")))
+}
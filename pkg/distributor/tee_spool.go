@@ -0,0 +1,305 @@
+package distributor
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/services"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/grafana/loki/pkg/logql/syntax"
+	"github.com/grafana/loki/pkg/util/flagext"
+)
+
+const (
+	spoolTeeName       = "spool"
+	spoolFileExtension = ".jsonl"
+)
+
+// SpoolTeeConfig configures the tee writing streams to a local directory.
+type SpoolTeeConfig struct {
+	Directory   string             `yaml:"directory"`
+	Selection   TeeSelectionConfig `yaml:",inline"`
+	QueueSize   int                `yaml:"queue_size"`
+	MaxFileSize flagext.ByteSize   `yaml:"max_file_size"`
+	MaxFiles    int                `yaml:"max_files"`
+}
+
+// RegisterFlagsWithPrefix registers the flags of the spool tee.
+func (cfg *SpoolTeeConfig) RegisterFlagsWithPrefix(prefix string, f *flag.FlagSet) {
+	f.StringVar(&cfg.Directory, prefix+".directory", "", "Directory the streams are written to, in a sub-directory per tenant. The tee is disabled if empty.")
+	cfg.Selection.RegisterFlagsWithPrefix(prefix, f)
+	f.IntVar(&cfg.QueueSize, prefix+".queue-size", 1000, "Maximum number of pushes queued to be written. Pushes are dropped when the queue is full.")
+	_ = cfg.MaxFileSize.Set("100MB")
+	f.Var(&cfg.MaxFileSize, prefix+".max-file-size", "Size after which a new file is started.")
+	f.IntVar(&cfg.MaxFiles, prefix+".max-files", 10, "Maximum number of files kept per tenant. The oldest files are deleted first. No limit if 0.")
+}
+
+// spoolTee asynchronously writes the selected streams to files in a local directory, for example to capture traffic
+// for offline analysis or replay. Each line of a file is a push request in the JSON format of the push API, so the
+// files can be pushed back to Loki as they are.
+//
+// Files are named after the time they were created, in a sub-directory per tenant, and are rotated by size. When the
+// queue is full, or when a file can't be written, the entries are dropped.
+type spoolTee struct {
+	services.Service
+
+	cfg      SpoolTeeConfig
+	selector *teeSelector
+	logger   log.Logger
+	queue    chan teeItem
+
+	// files are only accessed by the running goroutine.
+	files map[string]*spoolFile
+
+	metrics     *teeMetrics
+	queueLength prometheus.Gauge
+	queueFull   prometheus.Counter
+}
+
+// spoolFile is the file currently written for a tenant.
+type spoolFile struct {
+	f    *os.File
+	w    *bufio.Writer
+	size int
+}
+
+// spoolRequest and spoolStream are the JSON push request written for each push.
+type spoolRequest struct {
+	Streams []spoolStream `json:"streams"`
+}
+
+type spoolStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][]interface{}   `json:"values"`
+}
+
+func newSpoolTee(cfg SpoolTeeConfig, metrics *teeMetrics, logger log.Logger) (*spoolTee, error) {
+	if cfg.QueueSize <= 0 {
+		return nil, fmt.Errorf("invalid queue_size %d: must be greater than 0", cfg.QueueSize)
+	}
+	if cfg.MaxFiles < 0 {
+		return nil, fmt.Errorf("invalid max_files %d: must not be negative", cfg.MaxFiles)
+	}
+	selector, err := newTeeSelector(cfg.Selection)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(cfg.Directory, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create directory: %w", err)
+	}
+	t := &spoolTee{
+		cfg:         cfg,
+		selector:    selector,
+		logger:      log.With(logger, "tee", spoolTeeName),
+		queue:       make(chan teeItem, cfg.QueueSize),
+		files:       map[string]*spoolFile{},
+		metrics:     metrics,
+		queueLength: metrics.queueLength.WithLabelValues(spoolTeeName),
+		queueFull:   metrics.dropped.WithLabelValues(spoolTeeName, "queue_full"),
+	}
+	t.Service = services.NewBasicService(nil, t.running, t.stopping)
+	return t, nil
+}
+
+// Duplicate implements Tee.
+func (t *spoolTee) Duplicate(tenant string, streams []KeyedStream) {
+	streams = t.selector.selectStreams(tenant, streams)
+	if len(streams) == 0 {
+		return
+	}
+	select {
+	case t.queue <- teeItem{tenant: tenant, streams: streams, enqueued: time.Now().UnixNano()}:
+		t.queueLength.Inc()
+	default:
+		t.queueFull.Add(float64(countEntries(streams)))
+	}
+}
+
+func (t *spoolTee) running(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case item := <-t.queue:
+			t.queueLength.Dec()
+			t.write(item)
+			// Flush once the queue is drained, so that the files are written in batches.
+			if len(t.queue) == 0 {
+				t.flush()
+			}
+		}
+	}
+}
+
+// stopping writes the queued entries and closes the files.
+func (t *spoolTee) stopping(_ error) error {
+	for {
+		select {
+		case item := <-t.queue:
+			t.queueLength.Dec()
+			t.write(item)
+		default:
+			for tenant, file := range t.files {
+				if err := file.close(); err != nil {
+					level.Warn(t.logger).Log("msg", "failed to close file", "tenant", tenant, "err", err)
+				}
+				delete(t.files, tenant)
+			}
+			return nil
+		}
+	}
+}
+
+// write writes the streams matching the selector to the file of the tenant.
+func (t *spoolTee) write(item teeItem) {
+	req := spoolRequest{Streams: make([]spoolStream, 0, len(item.streams))}
+	var entries int
+	for _, s := range item.streams {
+		lbs, err := syntax.ParseLabels(s.Stream.Labels)
+		if err != nil {
+			level.Warn(t.logger).Log("msg", "failed to parse stream labels", "labels", s.Stream.Labels, "err", err)
+			continue
+		}
+		if !t.selector.matchLabels(lbs) {
+			continue
+		}
+		stream := spoolStream{Stream: lbs.Map(), Values: make([][]interface{}, 0, len(s.Stream.Entries))}
+		for _, e := range s.Stream.Entries {
+			value := []interface{}{strconv.FormatInt(e.Timestamp.UnixNano(), 10), e.Line}
+			if len(e.StructuredMetadata) > 0 {
+				metadata := make(map[string]string, len(e.StructuredMetadata))
+				for _, l := range e.StructuredMetadata {
+					metadata[l.Name] = l.Value
+				}
+				value = append(value, metadata)
+			}
+			stream.Values = append(stream.Values, value)
+		}
+		req.Streams = append(req.Streams, stream)
+		entries += len(s.Stream.Entries)
+	}
+	if entries == 0 {
+		return
+	}
+
+	line, err := json.Marshal(req)
+	if err == nil {
+		err = t.writeLine(item.tenant, append(line, '\n'))
+	}
+	if err != nil {
+		level.Warn(t.logger).Log("msg", "failed to write entries", "tenant", item.tenant, "entries", entries, "err", err)
+		t.metrics.dropped.WithLabelValues(spoolTeeName, "write_failed").Add(float64(entries))
+		return
+	}
+	t.metrics.sent.WithLabelValues(spoolTeeName).Add(float64(entries))
+	t.metrics.lag.WithLabelValues(spoolTeeName).Observe(time.Since(time.Unix(0, item.enqueued)).Seconds())
+}
+
+// writeLine writes a line to the file of the tenant, rotating it once it reaches the maximum size.
+func (t *spoolTee) writeLine(tenant string, line []byte) error {
+	file, ok := t.files[tenant]
+	if !ok {
+		var err error
+		file, err = t.create(tenant)
+		if err != nil {
+			return err
+		}
+		t.files[tenant] = file
+	}
+
+	n, err := file.w.Write(line)
+	file.size += n
+	if err != nil {
+		// Start a new file on the next write.
+		_ = file.close()
+		delete(t.files, tenant)
+		return err
+	}
+
+	if file.size >= t.cfg.MaxFileSize.Val() {
+		delete(t.files, tenant)
+		if err := file.close(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// create creates a new file for the tenant, deleting the oldest files beyond the maximum number of files.
+func (t *spoolTee) create(tenant string) (*spoolFile, error) {
+	dir := filepath.Join(t.cfg.Directory, tenant)
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, err
+	}
+	if t.cfg.MaxFiles > 0 {
+		t.cleanup(dir, t.cfg.MaxFiles-1)
+	}
+
+	name := filepath.Join(dir, strconv.FormatInt(time.Now().UnixNano(), 10)+spoolFileExtension)
+	f, err := os.OpenFile(name, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o640)
+	if err != nil {
+		return nil, err
+	}
+	return &spoolFile{f: f, w: bufio.NewWriter(f)}, nil
+}
+
+// cleanup deletes the oldest files of the directory, keeping at most keep files.
+func (t *spoolTee) cleanup(dir string, keep int) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		level.Warn(t.logger).Log("msg", "failed to list files", "dir", dir, "err", err)
+		return
+	}
+	var files []string
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), spoolFileExtension) {
+			files = append(files, e.Name())
+		}
+	}
+	if len(files) <= keep {
+		return
+	}
+	// Files are named after their creation time, so sorting them by name sorts them from the oldest.
+	sort.Slice(files, func(i, j int) bool {
+		if len(files[i]) != len(files[j]) {
+			return len(files[i]) < len(files[j])
+		}
+		return files[i] < files[j]
+	})
+	for _, name := range files[:len(files)-keep] {
+		if err := os.Remove(filepath.Join(dir, name)); err != nil {
+			level.Warn(t.logger).Log("msg", "failed to delete file", "file", name, "err", err)
+		}
+	}
+}
+
+// flush flushes the files of all tenants.
+func (t *spoolTee) flush() {
+	for tenant, file := range t.files {
+		if err := file.w.Flush(); err != nil {
+			level.Warn(t.logger).Log("msg", "failed to flush file", "tenant", tenant, "err", err)
+			_ = file.close()
+			delete(t.files, tenant)
+		}
+	}
+}
+
+func (f *spoolFile) close() error {
+	err := f.w.Flush()
+	if closeErr := f.f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
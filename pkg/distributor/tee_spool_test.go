@@ -0,0 +1,111 @@
+package distributor
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/services"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/pkg/push"
+)
+
+func newTestSpoolTee(t *testing.T, modify func(*SpoolTeeConfig)) (*spoolTee, *teeMetrics) {
+	cfg := SpoolTeeConfig{Directory: t.TempDir(), Selection: TeeSelectionConfig{SampleRatio: 1}, QueueSize: 10, MaxFiles: 2}
+	_ = cfg.MaxFileSize.Set("1MB")
+	if modify != nil {
+		modify(&cfg)
+	}
+	metrics := newTeeMetrics(prometheus.NewRegistry())
+	tee, err := newSpoolTee(cfg, metrics, log.NewNopLogger())
+	require.NoError(t, err)
+	return tee, metrics
+}
+
+func readSpoolFiles(t *testing.T, dir string) [][]spoolRequest {
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	var files [][]spoolRequest
+	for _, e := range entries {
+		f, err := os.Open(filepath.Join(dir, e.Name()))
+		require.NoError(t, err)
+		var reqs []spoolRequest
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			var req spoolRequest
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &req))
+			reqs = append(reqs, req)
+		}
+		require.NoError(t, scanner.Err())
+		require.NoError(t, f.Close())
+		files = append(files, reqs)
+	}
+	return files
+}
+
+func TestSpoolTee(t *testing.T) {
+	tee, metrics := newTestSpoolTee(t, func(cfg *SpoolTeeConfig) {
+		cfg.Selection.Selector = `{app="api"}`
+	})
+	require.NoError(t, services.StartAndAwaitRunning(context.Background(), tee))
+
+	withMetadata := makeTeeStream(`{app="api"}`, 0, "1")
+	withMetadata.Stream.Entries[0].StructuredMetadata = push.LabelsAdapter{{Name: "trace_id", Value: "abc"}}
+	tee.Duplicate("a", []KeyedStream{withMetadata, makeTeeStream(`{app="db"}`, 0, "2")})
+	tee.Duplicate("a", []KeyedStream{makeTeeStream(`{app="db"}`, 0, "3")})
+	tee.Duplicate("b", []KeyedStream{makeTeeStream(`{app="api"}`, 0, "4", "5")})
+
+	require.Eventually(t, func() bool {
+		return testutil.ToFloat64(metrics.sent.WithLabelValues(spoolTeeName)) == 3
+	}, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, services.StopAndAwaitTerminated(context.Background(), tee))
+
+	files := readSpoolFiles(t, filepath.Join(tee.cfg.Directory, "a"))
+	require.Equal(t, [][]spoolRequest{{{Streams: []spoolStream{{
+		Stream: map[string]string{"app": "api"},
+		Values: [][]interface{}{{"1", "1", map[string]interface{}{"trace_id": "abc"}}},
+	}}}}}, files)
+
+	files = readSpoolFiles(t, filepath.Join(tee.cfg.Directory, "b"))
+	require.Equal(t, [][]spoolRequest{{{Streams: []spoolStream{{
+		Stream: map[string]string{"app": "api"},
+		Values: [][]interface{}{{"1", "4"}, {"2", "5"}},
+	}}}}}, files)
+}
+
+func TestSpoolTee_Rotation(t *testing.T) {
+	tee, metrics := newTestSpoolTee(t, func(cfg *SpoolTeeConfig) {
+		_ = cfg.MaxFileSize.Set("1B")
+	})
+	for i := 0; i < 5; i++ {
+		tee.write(teeItem{tenant: "a", streams: []KeyedStream{makeTeeStream(`{app="api"}`, 0, "line")}, enqueued: time.Now().UnixNano()})
+		// Files are named after their creation time.
+		time.Sleep(time.Millisecond)
+	}
+	tee.flush()
+
+	// Every write starts a new file, and only the last two files are kept.
+	files := readSpoolFiles(t, filepath.Join(tee.cfg.Directory, "a"))
+	require.Len(t, files, 2)
+	for _, reqs := range files {
+		require.Len(t, reqs, 1)
+	}
+	require.Equal(t, 5.0, testutil.ToFloat64(metrics.sent.WithLabelValues(spoolTeeName)))
+}
+
+func TestSpoolTee_WriteFailed(t *testing.T) {
+	tee, metrics := newTestSpoolTee(t, nil)
+	// The tenant directory can't be created over a file.
+	require.NoError(t, os.WriteFile(filepath.Join(tee.cfg.Directory, "a"), nil, 0o600))
+
+	tee.write(teeItem{tenant: "a", streams: []KeyedStream{makeTeeStream(`{app="api"}`, 0, "1", "2")}})
+	require.Equal(t, 2.0, testutil.ToFloat64(metrics.dropped.WithLabelValues(spoolTeeName, "write_failed")))
+}
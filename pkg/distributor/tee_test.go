@@ -0,0 +1,88 @@
+package distributor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/pkg/logproto"
+)
+
+func makeTeeStream(labels string, hashKey uint32, lines ...string) KeyedStream {
+	stream := logproto.Stream{Labels: labels}
+	for i, line := range lines {
+		stream.Entries = append(stream.Entries, logproto.Entry{Timestamp: time.Unix(0, int64(i+1)), Line: line})
+	}
+	return KeyedStream{HashKey: hashKey, Stream: stream}
+}
+
+func TestTeeSelector(t *testing.T) {
+	streams := []KeyedStream{
+		makeTeeStream(`{app="api"}`, 0, "a"),
+		makeTeeStream(`{app="db"}`, 1<<31, "b"),
+		makeTeeStream(`{app="web"}`, 1<<32-1, "c"),
+	}
+
+	s, err := newTeeSelector(TeeSelectionConfig{SampleRatio: 1})
+	require.NoError(t, err)
+	require.Equal(t, streams, s.selectStreams("any", streams))
+
+	s, err = newTeeSelector(TeeSelectionConfig{Tenants: []string{"a", "b"}, SampleRatio: 1})
+	require.NoError(t, err)
+	require.Len(t, s.selectStreams("b", streams), 3)
+	require.Empty(t, s.selectStreams("c", streams))
+
+	s, err = newTeeSelector(TeeSelectionConfig{SampleRatio: 0.5})
+	require.NoError(t, err)
+	require.Equal(t, streams[:1], s.selectStreams("any", streams))
+
+	s, err = newTeeSelector(TeeSelectionConfig{SampleRatio: 0})
+	require.NoError(t, err)
+	require.Empty(t, s.selectStreams("any", streams))
+
+	s, err = newTeeSelector(TeeSelectionConfig{Selector: `{app=~"api|web"}`, SampleRatio: 1})
+	require.NoError(t, err)
+	for _, tc := range []struct {
+		labels  string
+		matches bool
+	}{
+		{`{app="api"}`, true},
+		{`{app="web", env="prod"}`, true},
+		{`{app="db"}`, false},
+		{`{env="prod"}`, false},
+	} {
+		ok, err := s.matches(tc.labels)
+		require.NoError(t, err)
+		require.Equal(t, tc.matches, ok, tc.labels)
+	}
+	_, err = s.matches(`{app=`)
+	require.Error(t, err)
+
+	_, err = newTeeSelector(TeeSelectionConfig{SampleRatio: 1.5})
+	require.Error(t, err)
+	_, err = newTeeSelector(TeeSelectionConfig{Selector: `app="api"`, SampleRatio: 1})
+	require.Error(t, err)
+}
+
+type recordingTee struct {
+	tenants []string
+}
+
+func (r *recordingTee) Duplicate(tenant string, _ []KeyedStream) {
+	r.tenants = append(r.tenants, tenant)
+}
+
+func TestWrapTee(t *testing.T) {
+	require.Nil(t, WrapTee())
+	require.Nil(t, WrapTee(nil, nil))
+
+	a := &recordingTee{}
+	require.Same(t, a, WrapTee(nil, a))
+
+	b := &recordingTee{}
+	tee := WrapTee(a, nil, b)
+	tee.Duplicate("tenant", nil)
+	require.Equal(t, []string{"tenant"}, a.tenants)
+	require.Equal(t, []string{"tenant"}, b.tenants)
+}
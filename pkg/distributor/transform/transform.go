@@ -0,0 +1,210 @@
+package transform
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/cespare/xxhash/v2"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+
+	"github.com/grafana/loki/pkg/logql/syntax"
+	"github.com/grafana/loki/pkg/push"
+	"github.com/grafana/loki/pkg/util"
+)
+
+// Action is the transformation applied by a rule.
+type Action string
+
+const (
+	// Drop drops the labels.
+	Drop Action = "drop"
+	// Rename renames the source label to the target label.
+	Rename Action = "rename"
+	// Hash replaces the values of the labels with their hash, modulo the modulus if set.
+	Hash Action = "hash"
+	// StructuredMetadata moves the labels to the structured metadata of the entries.
+	StructuredMetadata Action = "structured_metadata"
+	// Add sets the target label to the value.
+	Add Action = "add"
+)
+
+// Rule is a transformation of the labels of the streams pushed by a tenant.
+type Rule struct {
+	Name        string   `yaml:"name,omitempty" json:"name,omitempty" doc:"description=Name of the rule in the metrics. Defaults to the index of the rule."`
+	Selector    string   `yaml:"selector,omitempty" json:"selector,omitempty" doc:"description=Stream selector of the streams the rule applies to. All streams if empty."`
+	Action      Action   `yaml:"action" json:"action" doc:"description=Transformation applied: drop, rename, hash, structured_metadata or add."`
+	Labels      []string `yaml:"labels,omitempty" json:"labels,omitempty" doc:"description=Labels transformed by the drop, hash and structured_metadata actions."`
+	SourceLabel string   `yaml:"source_label,omitempty" json:"source_label,omitempty" doc:"description=Label renamed by the rename action."`
+	TargetLabel string   `yaml:"target_label,omitempty" json:"target_label,omitempty" doc:"description=Label set by the rename and add actions."`
+	Value       string   `yaml:"value,omitempty" json:"value,omitempty" doc:"description=Value of the label set by the add action."`
+	Modulus     uint64   `yaml:"modulus,omitempty" json:"modulus,omitempty" doc:"description=If set, the hash action replaces the values with their hash modulo the modulus, to bound the cardinality of the labels."`
+
+	Matchers []*labels.Matcher `yaml:"-" json:"-"` // populated during validation.
+}
+
+// Validate validates the rule, populating its matchers.
+func (r *Rule) Validate() error {
+	switch r.Action {
+	case Drop, Hash, StructuredMetadata:
+		if len(r.Labels) == 0 {
+			return fmt.Errorf("%s action requires labels", r.Action)
+		}
+	case Rename:
+		if r.SourceLabel == "" || r.TargetLabel == "" {
+			return fmt.Errorf("%s action requires source_label and target_label", r.Action)
+		}
+	case Add:
+		if r.TargetLabel == "" || r.Value == "" {
+			return fmt.Errorf("%s action requires target_label and value", r.Action)
+		}
+	default:
+		return fmt.Errorf("invalid action %q", r.Action)
+	}
+
+	if r.TargetLabel != "" && !model.LabelName(r.TargetLabel).IsValid() {
+		return fmt.Errorf("invalid target_label %q", r.TargetLabel)
+	}
+
+	r.Matchers = nil
+	if r.Selector != "" {
+		matchers, err := syntax.ParseMatchers(r.Selector, true)
+		if err != nil {
+			return fmt.Errorf("invalid selector: %w", err)
+		}
+		r.Matchers = matchers
+	}
+	return nil
+}
+
+// Apply applies the rules in order to the labels of a stream. It returns the transformed labels, and the labels
+// moved to the structured metadata of the entries. The selector of a rule is matched against the labels transformed
+// by the previous rules. applied is called with the index of each rule changing the labels.
+func Apply(rules []Rule, lbs labels.Labels, applied func(rule int)) (labels.Labels, push.LabelsAdapter) {
+	var structuredMetadata push.LabelsAdapter
+	for i := range rules {
+		r := &rules[i]
+		if !util.MatchesLabels(r.Matchers, lbs) {
+			continue
+		}
+
+		var changed bool
+		switch r.Action {
+		case Drop:
+			lbs, changed = filter(lbs, r.Labels, nil)
+		case StructuredMetadata:
+			lbs, changed = filter(lbs, r.Labels, func(l labels.Label) {
+				structuredMetadata = setStructuredMetadata(structuredMetadata, l)
+			})
+		case Hash:
+			lbs, changed = r.hash(lbs)
+		case Rename:
+			if value := lbs.Get(r.SourceLabel); value != "" {
+				lbs, _ = filter(lbs, []string{r.SourceLabel}, nil)
+				lbs = set(lbs, r.TargetLabel, value)
+				changed = true
+			}
+		case Add:
+			if lbs.Get(r.TargetLabel) != r.Value {
+				lbs = set(lbs, r.TargetLabel, r.Value)
+				changed = true
+			}
+		}
+
+		if changed && applied != nil {
+			applied(i)
+		}
+	}
+	return lbs, structuredMetadata
+}
+
+// MetricName returns the name of the rule in the metrics.
+func (r *Rule) MetricName(index int) string {
+	if r.Name != "" {
+		return r.Name
+	}
+	return strconv.Itoa(index)
+}
+
+func (r *Rule) hash(lbs labels.Labels) (labels.Labels, bool) {
+	var (
+		hashed  labels.Labels
+		changed bool
+	)
+	for i, l := range lbs {
+		if !contains(r.Labels, l.Name) {
+			continue
+		}
+		if hashed == nil {
+			// The labels may be shared, so they are copied before being modified.
+			hashed = lbs.Copy()
+		}
+		h := xxhash.Sum64String(l.Value)
+		if r.Modulus > 0 {
+			hashed[i].Value = strconv.FormatUint(h%r.Modulus, 10)
+		} else {
+			hashed[i].Value = strconv.FormatUint(h, 16)
+		}
+		changed = true
+	}
+	if !changed {
+		return lbs, false
+	}
+	return hashed, true
+}
+
+// filter removes the labels with the given names, calling removed for each of them.
+func filter(lbs labels.Labels, names []string, removed func(labels.Label)) (labels.Labels, bool) {
+	var (
+		filtered labels.Labels
+		changed  bool
+	)
+	for i, l := range lbs {
+		if !contains(names, l.Name) {
+			if changed {
+				filtered = append(filtered, l)
+			}
+			continue
+		}
+		if !changed {
+			filtered = make(labels.Labels, i, len(lbs)-1)
+			copy(filtered, lbs[:i])
+			changed = true
+		}
+		if removed != nil {
+			removed(l)
+		}
+	}
+	if !changed {
+		return lbs, false
+	}
+	return filtered, true
+}
+
+// set returns a copy of the labels with the label set to the value.
+func set(lbs labels.Labels, name, value string) labels.Labels {
+	b := labels.NewBuilder(lbs)
+	b.Set(name, value)
+	return b.Labels()
+}
+
+// setStructuredMetadata sets the label in the structured metadata, overwriting the value of a label moved by a
+// previous rule with the same name.
+func setStructuredMetadata(metadata push.LabelsAdapter, l labels.Label) push.LabelsAdapter {
+	for i := range metadata {
+		if metadata[i].Name == l.Name {
+			metadata[i].Value = l.Value
+			return metadata
+		}
+	}
+	return append(metadata, push.LabelAdapter{Name: l.Name, Value: l.Value})
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
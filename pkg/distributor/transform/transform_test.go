@@ -0,0 +1,142 @@
+package transform
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/cespare/xxhash/v2"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/pkg/push"
+)
+
+func TestApply(t *testing.T) {
+	for _, tc := range []struct {
+		name               string
+		rules              []Rule
+		labels             labels.Labels
+		expected           labels.Labels
+		structuredMetadata push.LabelsAdapter
+		applied            []int
+	}{
+		{
+			name:     "no rules",
+			labels:   labels.FromStrings("app", "api"),
+			expected: labels.FromStrings("app", "api"),
+		},
+		{
+			name:     "drop",
+			rules:    []Rule{{Action: Drop, Labels: []string{"pod", "missing"}}},
+			labels:   labels.FromStrings("app", "api", "pod", "api-1"),
+			expected: labels.FromStrings("app", "api"),
+			applied:  []int{0},
+		},
+		{
+			name:     "drop missing labels",
+			rules:    []Rule{{Action: Drop, Labels: []string{"missing"}}},
+			labels:   labels.FromStrings("app", "api"),
+			expected: labels.FromStrings("app", "api"),
+		},
+		{
+			name:     "rename",
+			rules:    []Rule{{Action: Rename, SourceLabel: "service", TargetLabel: "app"}},
+			labels:   labels.FromStrings("env", "dev", "service", "api"),
+			expected: labels.FromStrings("app", "api", "env", "dev"),
+			applied:  []int{0},
+		},
+		{
+			name:     "hash",
+			rules:    []Rule{{Action: Hash, Labels: []string{"user"}}},
+			labels:   labels.FromStrings("app", "api", "user", "alice"),
+			expected: labels.FromStrings("app", "api", "user", strconv.FormatUint(xxhash.Sum64String("alice"), 16)),
+			applied:  []int{0},
+		},
+		{
+			name:     "hash with modulus",
+			rules:    []Rule{{Action: Hash, Labels: []string{"user"}, Modulus: 8}},
+			labels:   labels.FromStrings("app", "api", "user", "alice"),
+			expected: labels.FromStrings("app", "api", "user", strconv.FormatUint(xxhash.Sum64String("alice")%8, 10)),
+			applied:  []int{0},
+		},
+		{
+			name:               "structured metadata",
+			rules:              []Rule{{Action: StructuredMetadata, Labels: []string{"trace_id", "span_id"}}},
+			labels:             labels.FromStrings("app", "api", "span_id", "2", "trace_id", "1"),
+			expected:           labels.FromStrings("app", "api"),
+			structuredMetadata: push.LabelsAdapter{{Name: "span_id", Value: "2"}, {Name: "trace_id", Value: "1"}},
+			applied:            []int{0},
+		},
+		{
+			name: "structured metadata moved twice",
+			rules: []Rule{
+				{Action: StructuredMetadata, Labels: []string{"trace_id"}},
+				{Action: Add, TargetLabel: "trace_id", Value: "2"},
+				{Action: StructuredMetadata, Labels: []string{"trace_id"}},
+			},
+			labels:             labels.FromStrings("app", "api", "trace_id", "1"),
+			expected:           labels.FromStrings("app", "api"),
+			structuredMetadata: push.LabelsAdapter{{Name: "trace_id", Value: "2"}},
+			applied:            []int{0, 1, 2},
+		},
+		{
+			name:     "add",
+			rules:    []Rule{{Action: Add, TargetLabel: "cluster", Value: "eu"}, {Action: Add, TargetLabel: "cluster", Value: "eu"}},
+			labels:   labels.FromStrings("app", "api"),
+			expected: labels.FromStrings("app", "api", "cluster", "eu"),
+			applied:  []int{0},
+		},
+		{
+			name: "selectors match the transformed labels",
+			rules: []Rule{
+				{Action: Rename, SourceLabel: "service", TargetLabel: "app"},
+				{Selector: `{app="api"}`, Action: Drop, Labels: []string{"pod"}},
+				{Selector: `{app="db"}`, Action: Add, TargetLabel: "tier", Value: "storage"},
+			},
+			labels:   labels.FromStrings("pod", "api-1", "service", "api"),
+			expected: labels.FromStrings("app", "api"),
+			applied:  []int{0, 1},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			for i := range tc.rules {
+				require.NoError(t, tc.rules[i].Validate())
+			}
+			original := tc.labels.Copy()
+
+			var applied []int
+			lbs, structuredMetadata := Apply(tc.rules, tc.labels, func(rule int) {
+				applied = append(applied, rule)
+			})
+			require.Equal(t, tc.expected, lbs)
+			require.Equal(t, tc.structuredMetadata, structuredMetadata)
+			require.Equal(t, tc.applied, applied)
+			// The labels passed are never modified.
+			require.Equal(t, original, tc.labels)
+		})
+	}
+}
+
+func TestRule_Validate(t *testing.T) {
+	for name, tc := range map[string]struct {
+		rule Rule
+		err  string
+	}{
+		"valid drop":         {Rule{Action: Drop, Labels: []string{"pod"}, Selector: `{app="api"}`}, ""},
+		"invalid action":     {Rule{Action: "keep"}, `invalid action "keep"`},
+		"drop without label": {Rule{Action: Drop}, "drop action requires labels"},
+		"rename without src": {Rule{Action: Rename, TargetLabel: "app"}, "rename action requires source_label and target_label"},
+		"add without value":  {Rule{Action: Add, TargetLabel: "app"}, "add action requires target_label and value"},
+		"invalid target":     {Rule{Action: Add, TargetLabel: "a-b", Value: "c"}, `invalid target_label "a-b"`},
+		"invalid selector":   {Rule{Action: Drop, Labels: []string{"pod"}, Selector: `app="api"`}, "invalid selector"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := tc.rule.Validate()
+			if tc.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tc.err)
+		})
+	}
+}
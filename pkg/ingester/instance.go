@@ -38,6 +38,7 @@ import (
 	"github.com/grafana/loki/pkg/logql/log"
 	"github.com/grafana/loki/pkg/logql/syntax"
 	"github.com/grafana/loki/pkg/logqlmodel/stats"
+	"github.com/grafana/loki/pkg/pattern"
 	"github.com/grafana/loki/pkg/runtime"
 	"github.com/grafana/loki/pkg/storage/chunk"
 	"github.com/grafana/loki/pkg/storage/config"
@@ -537,6 +538,41 @@ func (i *instance) QuerySample(ctx context.Context, req logql.SelectSampleParams
 // If label matchers are given only the matching streams are fetched from the index.
 // The label names or values are then retrieved from those matching streams.
 func (i *instance) Label(ctx context.Context, req *logproto.LabelRequest, matchers ...*labels.Matcher) (*logproto.LabelResponse, error) {
+	resp, err := i.label(ctx, req, matchers...)
+	if err != nil || !req.StructuredMetadata {
+		return resp, err
+	}
+
+	// structured metadata is not indexed, it is read from the chunks of the matching streams.
+	through := time.Now()
+	if req.End != nil {
+		through = *req.End
+	}
+	values := util.NewUniqueStrings(len(resp.Values))
+	values.Add(resp.Values...)
+	// the structured metadata names are returned unless the values of a label are requested.
+	var name string
+	if req.Values {
+		name = req.Name
+	}
+	err = i.forMatchingStreams(ctx, *req.Start, matchers, nil, func(s *stream) error {
+		if !shouldConsiderStream(s, *req.Start, through) {
+			return nil
+		}
+		return s.forStructuredMetadata(ctx, *req.Start, through, name, func(str string) {
+			values.Add(str)
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &logproto.LabelResponse{
+		Values: values.Strings(),
+	}, nil
+}
+
+func (i *instance) label(ctx context.Context, req *logproto.LabelRequest, matchers ...*labels.Matcher) (*logproto.LabelResponse, error) {
 	if len(matchers) == 0 {
 		var labels []string
 		if req.Values {
@@ -779,6 +815,37 @@ func (i *instance) GetVolume(ctx context.Context, req *logproto.VolumeRequest) (
 	return res, nil
 }
 
+// GetPatterns returns the patterns of the matching streams within the
+// requested time range.
+func (i *instance) GetPatterns(ctx context.Context, req *logproto.QueryPatternsRequest) (*logproto.QueryPatternsResponse, error) {
+	matchers, err := syntax.ParseMatchers(req.Query, true)
+	if err != nil {
+		return nil, err
+	}
+
+	acc := pattern.NewAccumulator(req)
+	// the end of the request is inclusive.
+	from, through := req.Start, req.End+1
+	if err = i.forMatchingStreams(ctx, req.Start.Time(), matchers, nil, func(s *stream) error {
+		if s.patterns == nil {
+			return nil
+		}
+		s.chunkMtx.RLock()
+		defer s.chunkMtx.RUnlock()
+		for _, c := range s.patterns.Clusters() {
+			p := c.String()
+			c.Iterate(from, through, func(sample logproto.PatternSample) {
+				acc.Add(p, sample)
+			})
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return acc.Response(1), nil
+}
+
 func (i *instance) numStreams() int {
 	return i.streams.Len()
 }
@@ -391,6 +391,67 @@ func Test_LabelQuery(t *testing.T) {
 	}
 }
 
+func Test_LabelQueryStructuredMetadata(t *testing.T) {
+	instance, currentTime, _ := setupTestStreams(t)
+
+	for _, tc := range []struct {
+		stream string
+		ts     time.Time
+		sm     []logproto.LabelAdapter
+	}{
+		{`{app="test",job="varlogs"}`, currentTime.Add(5 * time.Nanosecond), []logproto.LabelAdapter{{Name: "trace_id", Value: "1"}}},
+		{`{app="test2",job="varlogs"}`, currentTime.Add(11 * time.Nanosecond), []logproto.LabelAdapter{{Name: "trace_id", Value: "2"}, {Name: "user", Value: "a"}}},
+		{`{app="test",job="varlogs2"}`, currentTime.Add(17 * time.Nanosecond), []logproto.LabelAdapter{{Name: "trace_id", Value: "3"}}},
+	} {
+		stream, err := instance.getOrCreateStream(context.Background(), logproto.Stream{Labels: tc.stream}, recordPool.GetRecord())
+		require.NoError(t, err)
+		require.NoError(t, stream.chunks[0].chunk.Append(&logproto.Entry{Timestamp: tc.ts, Line: "with structured metadata", StructuredMetadata: tc.sm}))
+	}
+
+	start := &[]time.Time{currentTime.Add(11 * time.Nanosecond)}[0]
+	end := &[]time.Time{currentTime.Add(20 * time.Nanosecond)}[0]
+	m, err := labels.NewMatcher(labels.MatchEqual, "app", "test")
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		name     string
+		req      *logproto.LabelRequest
+		matchers []*labels.Matcher
+		expected []string
+	}{
+		{
+			"label names",
+			&logproto.LabelRequest{Start: start, End: end, StructuredMetadata: true},
+			nil,
+			[]string{"app", "job", "trace_id", "user"},
+		},
+		{
+			"label names - with matcher",
+			&logproto.LabelRequest{Start: start, End: end, StructuredMetadata: true},
+			[]*labels.Matcher{m},
+			[]string{"app", "job", "trace_id"},
+		},
+		{
+			"label values",
+			&logproto.LabelRequest{Name: "trace_id", Values: true, Start: start, End: end, StructuredMetadata: true},
+			nil,
+			[]string{"2", "3"},
+		},
+		{
+			"label values - without structured metadata",
+			&logproto.LabelRequest{Name: "trace_id", Values: true, Start: start, End: end},
+			nil,
+			[]string{},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, err := instance.Label(context.Background(), tc.req, tc.matchers...)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, resp.Values)
+		})
+	}
+}
+
 func Test_SeriesQuery(t *testing.T) {
 	instance, currentTime, indexShards := setupTestStreams(t)
 
@@ -1429,6 +1490,53 @@ func TestGetStats(t *testing.T) {
 	}, resp)
 }
 
+func TestInstance_GetPatterns(t *testing.T) {
+	ingesterConfig := defaultIngesterTestConfig(t)
+	ingesterConfig.PatternDetection.Enabled = true
+	overrides, err := validation.NewOverrides(defaultLimitsTestConfig(), nil)
+	require.NoError(t, err)
+	instance, err := newInstance(&ingesterConfig, defaultPeriodConfigs, "fake", NewLimiter(overrides, NilMetrics, &ringCountMock{count: 1}, 1), loki_runtime.DefaultTenantConfigs(), noopWAL{}, NilMetrics, nil, nil, nil, nil, NewStreamRateCalculator(), nil)
+	require.NoError(t, err)
+
+	for i := 0; i < 6; i++ {
+		require.NoError(t, instance.Push(context.Background(), &logproto.PushRequest{
+			Streams: []logproto.Stream{
+				{
+					Labels: fmt.Sprintf(`{app="api", pod="api-%d"}`, i%2),
+					Entries: []logproto.Entry{
+						{Timestamp: time.Unix(int64(i*10), 0), Line: fmt.Sprintf("user user-%d logged in", i)},
+						{Timestamp: time.Unix(int64(i*10), 1), Line: fmt.Sprintf("request took %dms", i)},
+					},
+				},
+				{
+					Labels:  `{app="db"}`,
+					Entries: []logproto.Entry{{Timestamp: time.Unix(int64(i*10), 0), Line: "checkpoint done"}},
+				},
+			},
+		}))
+	}
+
+	resp, err := instance.GetPatterns(context.Background(), &logproto.QueryPatternsRequest{
+		Query: `{app="api"}`,
+		Start: model.TimeFromUnix(0),
+		End:   model.TimeFromUnix(40),
+		Step:  (30 * time.Second).Milliseconds(),
+	})
+	require.NoError(t, err)
+	require.Equal(t, &logproto.QueryPatternsResponse{
+		Series: []*logproto.PatternSeries{
+			{
+				Pattern: "request took <_>",
+				Samples: []logproto.PatternSample{{Timestamp: model.TimeFromUnix(0), Value: 3}, {Timestamp: model.TimeFromUnix(30), Value: 2}},
+			},
+			{
+				Pattern: "user <_> logged in",
+				Samples: []logproto.PatternSample{{Timestamp: model.TimeFromUnix(0), Value: 3}, {Timestamp: model.TimeFromUnix(30), Value: 2}},
+			},
+		},
+	}, resp)
+}
+
 func defaultInstance(t *testing.T) *instance {
 	ingesterConfig := defaultIngesterTestConfig(t)
 	defaultLimits := defaultLimitsTestConfig()
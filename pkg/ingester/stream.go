@@ -22,6 +22,7 @@ import (
 	"github.com/grafana/loki/pkg/logproto"
 	"github.com/grafana/loki/pkg/logql/log"
 	"github.com/grafana/loki/pkg/logqlmodel/stats"
+	"github.com/grafana/loki/pkg/pattern/drain"
 	"github.com/grafana/loki/pkg/util/flagext"
 	util_log "github.com/grafana/loki/pkg/util/log"
 	"github.com/grafana/loki/pkg/validation"
@@ -77,6 +78,10 @@ type stream struct {
 
 	chunkFormat          byte
 	chunkHeadBlockFormat chunkenc.HeadBlockFmt
+
+	// patterns of the stored lines, nil when pattern detection is disabled.
+	// Not thread-safe; assume accesses to this are locked by chunkMtx.
+	patterns *drain.Drain
 }
 
 type chunkDesc struct {
@@ -108,6 +113,10 @@ func newStream(
 	writeFailures *writefailures.Manager,
 ) *stream {
 	hashNoShard, _ := labels.HashWithoutLabels(make([]byte, 0, 1024), ShardLbName)
+	var patterns *drain.Drain
+	if cfg.PatternDetection.Enabled {
+		patterns = drain.New(&cfg.PatternDetection)
+	}
 	return &stream{
 		limiter:              NewStreamRateLimiter(limits, tenant, 10*time.Second),
 		cfg:                  cfg,
@@ -125,6 +134,7 @@ func newStream(
 		writeFailures:        writeFailures,
 		chunkFormat:          chunkFormat,
 		chunkHeadBlockFormat: headBlockFmt,
+		patterns:             patterns,
 	}
 }
 
@@ -215,6 +225,7 @@ func (s *stream) Push(
 
 	bytesAdded, storedEntries, entriesWithErr := s.storeEntries(ctx, toStore)
 	s.recordAndSendToTailers(record, storedEntries)
+	s.trainPatterns(storedEntries)
 
 	if len(s.chunks) != prevNumChunks {
 		s.metrics.memoryChunks.Add(float64(len(s.chunks) - prevNumChunks))
@@ -223,6 +234,17 @@ func (s *stream) Push(
 	return bytesAdded, errorForFailedEntries(s, append(invalid, entriesWithErr...), len(entries))
 }
 
+// trainPatterns adds the stored entries to the patterns of the stream.
+// Must hold chunkMtx
+func (s *stream) trainPatterns(entries []logproto.Entry) {
+	if s.patterns == nil {
+		return
+	}
+	for _, e := range entries {
+		s.patterns.Train(e.Line, e.Timestamp)
+	}
+}
+
 func errorForFailedEntries(s *stream, failedEntriesWithError []entryWithError, totalEntries int) error {
 	if len(failedEntriesWithError) == 0 {
 		return nil
@@ -596,6 +618,36 @@ func (s *stream) SampleIterator(ctx context.Context, statsCtx *stats.Context, fr
 	return iter.NewSortSampleIterator(iterators), nil
 }
 
+// forStructuredMetadata calls fn for the distinct structured metadata names of each chunk overlapping the time range,
+// or for the distinct values of the structured metadata with the given name if it isn't empty.
+func (s *stream) forStructuredMetadata(ctx context.Context, from, through time.Time, name string, fn func(string)) error {
+	s.chunkMtx.RLock()
+	defer s.chunkMtx.RUnlock()
+
+	for _, c := range s.chunks {
+		mint, maxt := c.chunk.Bounds()
+		if through.Before(mint) || maxt.Before(from) {
+			continue
+		}
+		var (
+			strs []string
+			err  error
+		)
+		if name == "" {
+			strs, err = c.chunk.StructuredMetadataNames(ctx, from, through)
+		} else {
+			strs, err = c.chunk.StructuredMetadataValues(ctx, from, through, name)
+		}
+		if err != nil {
+			return err
+		}
+		for _, str := range strs {
+			fn(str)
+		}
+	}
+	return nil
+}
+
 func (s *stream) addTailer(t *tailer) {
 	s.tailerMtx.Lock()
 	defer s.tailerMtx.Unlock()
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"math"
 	"net/http"
 	"net/url"
 	"os"
@@ -38,6 +39,8 @@ const (
 	volumePath        = "/loki/api/v1/index/volume"
 	volumeRangePath   = "/loki/api/v1/index/volume_range"
 	defaultAuthHeader = "Authorization"
+
+	ndjsonContentType = "application/x-ndjson"
 )
 
 var userAgent = fmt.Sprintf("loki-logcli/%s", build.Version)
@@ -46,6 +49,7 @@ var userAgent = fmt.Sprintf("loki-logcli/%s", build.Version)
 type Client interface {
 	Query(queryStr string, limit int, time time.Time, direction logproto.Direction, quiet bool) (*loghttp.QueryResponse, error)
 	QueryRange(queryStr string, limit int, start, end time.Time, direction logproto.Direction, step, interval time.Duration, quiet bool) (*loghttp.QueryResponse, error)
+	QueryRangeStream(queryStr string, limit int, start, end time.Time, direction logproto.Direction, quiet bool) (io.ReadCloser, error)
 	ListLabelNames(quiet bool, start, end time.Time) (*loghttp.LabelResponse, error)
 	ListLabelValues(name string, quiet bool, start, end time.Time) (*loghttp.LabelResponse, error)
 	Series(matchers []string, start, end time.Time, quiet bool) (*loghttp.SeriesResponse, error)
@@ -117,6 +121,34 @@ func (c *DefaultClient) QueryRange(queryStr string, limit int, start, end time.T
 	return c.doQuery(queryRangePath, params.Encode(), quiet)
 }
 
+// QueryRangeStream uses the /api/v1/query_range endpoint to execute a range log query whose
+// results are streamed back as newline delimited JSON, one stream with a single entry per line.
+// A query failing once the response has started ends with a line holding only an "error" field,
+// which callers must check for. A limit of 0 streams all the entries of the range.
+// nolint:interfacer
+func (c *DefaultClient) QueryRangeStream(queryStr string, limit int, start, end time.Time, direction logproto.Direction, quiet bool) (io.ReadCloser, error) {
+	params := util.NewQueryStringBuilder()
+	params.SetString("query", queryStr)
+	if limit == 0 {
+		params.SetInt("limit", math.MaxUint32)
+	} else {
+		params.SetInt32("limit", limit)
+	}
+	params.SetInt("start", start.UnixNano())
+	params.SetInt("end", end.UnixNano())
+	params.SetString("direction", direction.String())
+
+	resp, err := c.do(queryRangePath, params.Encode(), quiet, ndjsonContentType)
+	if err != nil {
+		return nil, err
+	}
+	if contentType := resp.Header.Get("Content-Type"); contentType != ndjsonContentType {
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("server does not support streaming query results, got content type %q", contentType)
+	}
+	return resp.Body, nil
+}
+
 // ListLabelNames uses the /api/v1/label endpoint to list label names
 func (c *DefaultClient) ListLabelNames(quiet bool, start, end time.Time) (*loghttp.LabelResponse, error) {
 	var labelResponse loghttp.LabelResponse
@@ -236,22 +268,41 @@ func (c *DefaultClient) doQuery(path string, query string, quiet bool) (*loghttp
 }
 
 func (c *DefaultClient) doRequest(path, query string, quiet bool, out interface{}) error {
-	us, err := buildURL(c.Address, path, query)
+	resp, err := c.do(path, query, quiet, "")
 	if err != nil {
 		return err
 	}
+
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			log.Println("error closing body", err)
+		}
+	}()
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// do sends the request, retrying with backoff, and returns the first successful response.
+// The caller is responsible for closing the response body.
+func (c *DefaultClient) do(path, query string, quiet bool, accept string) (*http.Response, error) {
+	us, err := buildURL(c.Address, path, query)
+	if err != nil {
+		return nil, err
+	}
 	if !quiet {
 		log.Print(us)
 	}
 
 	req, err := http.NewRequest("GET", us, nil)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	h, err := c.getHTTPRequestHeader()
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if accept != "" {
+		h.Set("Accept", accept)
 	}
 	req.Header = h
 
@@ -263,14 +314,14 @@ func (c *DefaultClient) doRequest(path, query string, quiet bool, out interface{
 	if c.ProxyURL != "" {
 		prox, err := url.Parse(c.ProxyURL)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		clientConfig.ProxyURL = config.URL{URL: prox}
 	}
 
 	client, err := config.NewClientFromConfig(clientConfig, "promtail", config.WithHTTP2Disabled())
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if c.Tripperware != nil {
 		client.Transport = c.Tripperware(client.Transport)
@@ -313,15 +364,10 @@ func (c *DefaultClient) doRequest(path, query string, quiet bool, out interface{
 
 	}
 	if !success {
-		return fmt.Errorf("run out of attempts while querying the server")
+		return nil, fmt.Errorf("run out of attempts while querying the server")
 	}
 
-	defer func() {
-		if err := resp.Body.Close(); err != nil {
-			log.Println("error closing body", err)
-		}
-	}()
-	return json.NewDecoder(resp.Body).Decode(out)
+	return resp, nil
 }
 
 // nolint:goconst
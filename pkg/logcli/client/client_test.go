@@ -2,10 +2,16 @@ package client
 
 import (
 	"encoding/base64"
+	"io"
 	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/pkg/logproto"
 )
 
 func Test_buildURL(t *testing.T) {
@@ -78,3 +84,28 @@ func Test_getHTTPRequestHeader(t *testing.T) {
 		})
 	}
 }
+
+func Test_QueryRangeStream(t *testing.T) {
+	body := "{\"stream\":{\"app\":\"foo\"},\"values\":[[\"1\",\"line\"]]}\n"
+	contentType := ndjsonContentType
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, queryRangePath, r.URL.Path)
+		assert.Equal(t, ndjsonContentType, r.Header.Get("Accept"))
+		assert.Equal(t, "4294967295", r.URL.Query().Get("limit"))
+		w.Header().Set("Content-Type", contentType)
+		_, _ = io.WriteString(w, body)
+	}))
+	defer server.Close()
+
+	c := &DefaultClient{Address: server.URL}
+	rc, err := c.QueryRangeStream(`{app="foo"}`, 0, time.Unix(0, 0), time.Unix(10, 0), logproto.FORWARD, true)
+	require.NoError(t, err)
+	got, err := io.ReadAll(rc)
+	require.NoError(t, err)
+	require.NoError(t, rc.Close())
+	require.Equal(t, body, string(got))
+
+	contentType = "application/json"
+	_, err = c.QueryRangeStream(`{app="foo"}`, 0, time.Unix(0, 0), time.Unix(10, 0), logproto.FORWARD, true)
+	require.Error(t, err)
+}
@@ -194,6 +194,10 @@ func (f *FileClient) GetStats(_ string, _, _ time.Time, _ bool) (*logproto.Index
 	return nil, ErrNotSupported
 }
 
+func (f *FileClient) QueryRangeStream(_ string, _ int, _, _ time.Time, _ logproto.Direction, _ bool) (io.ReadCloser, error) {
+	return nil, ErrNotSupported
+}
+
 func (f *FileClient) GetVolume(_ *volume.Query) (*loghttp.QueryResponse, error) {
 	// TODO(trevorwhitney): could we teach logcli to read from an actual index file?
 	return nil, ErrNotSupported
@@ -165,6 +165,24 @@ func (r *QueryResultPrinter) printStream(streams loghttp.Streams, out output.Log
 	return printed, lel
 }
 
+// PrintStreamEntries prints the entries of a stream as they are received from a streamed query.
+// Since the other streams are not known yet, common labels are not removed and the labels
+// are padded to the fixed labels length only.
+func (r *QueryResultPrinter) PrintStreamEntries(s loghttp.Stream, out output.LogOutput) int {
+	ls := s.Labels
+	if len(r.ShowLabelsKey) > 0 {
+		ls = matchLabels(true, ls, r.ShowLabelsKey)
+	}
+	if len(r.IgnoreLabelsKey) > 0 {
+		ls = matchLabels(false, ls, r.IgnoreLabelsKey)
+	}
+
+	for _, e := range s.Entries {
+		out.FormatAndPrintln(e.Timestamp, ls, r.FixedLabelsLen, e.Line)
+	}
+	return len(s.Entries)
+}
+
 func printMatrix(matrix loghttp.Matrix) {
 	// yes we are effectively unmarshalling and then immediately marshalling this object back to json.  we are doing this b/c
 	// it gives us more flexibility with regard to output types in the future.  initially we are supporting just formatted json but eventually
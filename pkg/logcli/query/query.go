@@ -2,6 +2,7 @@ package query
 
 import (
 	"context"
+	"encoding/json"
 	stdErrors "errors"
 	"flag"
 	"fmt"
@@ -22,6 +23,7 @@ import (
 	"github.com/grafana/loki/pkg/loghttp"
 	"github.com/grafana/loki/pkg/logproto"
 	"github.com/grafana/loki/pkg/logql"
+	"github.com/grafana/loki/pkg/logql/syntax"
 	"github.com/grafana/loki/pkg/loki"
 	"github.com/grafana/loki/pkg/storage"
 	chunk "github.com/grafana/loki/pkg/storage/chunk/client"
@@ -80,6 +82,10 @@ type Query struct {
 	// If MergeParts is false, this parameter has no effect, part files will be kept.
 	// Otherwise, if this is true, the part files will not be deleted once they have been merged.
 	KeepParts bool
+
+	// If true, log queries are streamed from the server in a single request instead of being
+	// fetched in batches. Metric queries are not affected.
+	Stream bool
 }
 
 // DoQuery executes the query and prints out the results
@@ -129,6 +135,10 @@ func (q *Query) DoQuery(c client.Client, out output.LogOutput, statistics bool)
 			result.PrintStats(resp.Data.Statistics)
 		}
 		_, _ = result.PrintResult(resp.Data.Result, out, nil)
+	} else if q.Stream && q.isLogQuery() {
+		if err := q.doStreamQuery(c, out, result, d); err != nil {
+			log.Fatalf("Query failed: %+v", err)
+		}
 	} else {
 		unlimited := q.Limit == 0
 
@@ -208,6 +218,59 @@ func (q *Query) DoQuery(c client.Client, out output.LogOutput, statistics bool)
 	}
 }
 
+// doStreamQuery prints the entries of a log query as they are streamed from the server.
+func (q *Query) doStreamQuery(c client.Client, out output.LogOutput, result *print.QueryResultPrinter, d logproto.Direction) error {
+	body, err := c.QueryRangeStream(q.QueryString, q.Limit, q.Start, q.End, d, q.Quiet)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	dec := json.NewDecoder(body)
+	for {
+		var line streamLine
+		if err := dec.Decode(&line); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		if line.Error != "" {
+			return errors.New(line.Error)
+		}
+		result.PrintStreamEntries(line.Stream, out)
+	}
+}
+
+// streamLine is a line of a streamed query response, either a stream with a single entry or an error.
+type streamLine struct {
+	Stream loghttp.Stream
+	Error  string
+}
+
+func (l *streamLine) UnmarshalJSON(data []byte) error {
+	var e struct {
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal(data, &e); err != nil {
+		return err
+	}
+	if e.Error != "" {
+		l.Error = e.Error
+		return nil
+	}
+	return json.Unmarshal(data, &l.Stream)
+}
+
+func (q *Query) isLogQuery() bool {
+	expr, err := syntax.ParseExpr(q.QueryString)
+	if err != nil {
+		return false
+	}
+	_, ok := expr.(syntax.LogSelectorExpr)
+	return ok
+}
+
 func (q *Query) outputFilename() string {
 	return fmt.Sprintf(
 		"%s_%s_%s.part",
@@ -5,7 +5,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 	"testing"
@@ -17,11 +21,15 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/grafana/loki/pkg/iter"
+	logcli "github.com/grafana/loki/pkg/logcli/client"
 	"github.com/grafana/loki/pkg/logcli/output"
+	"github.com/grafana/loki/pkg/logcli/print"
 	"github.com/grafana/loki/pkg/logcli/volume"
 	"github.com/grafana/loki/pkg/loghttp"
 	"github.com/grafana/loki/pkg/logproto"
 	"github.com/grafana/loki/pkg/logql"
+	"github.com/grafana/loki/pkg/logqlmodel"
 	"github.com/grafana/loki/pkg/loki"
 	"github.com/grafana/loki/pkg/storage"
 	"github.com/grafana/loki/pkg/storage/chunk/client"
@@ -405,6 +413,107 @@ func Test_batch(t *testing.T) {
 	}
 }
 
+func Test_stream(t *testing.T) {
+	streams := []logproto.Stream{
+		{
+			Labels: `{test="stream", app="a"}`,
+			Entries: []logproto.Entry{
+				{Timestamp: time.Unix(1, 0), Line: "line1"},
+				{Timestamp: time.Unix(3, 0), Line: "line3"},
+				{Timestamp: time.Unix(5, 0), Line: "line5"},
+			},
+		},
+		{
+			Labels: `{test="stream", app="b"}`,
+			Entries: []logproto.Entry{
+				{Timestamp: time.Unix(2, 0), Line: "line2"},
+				{Timestamp: time.Unix(4, 0), Line: "line4"},
+			},
+		},
+	}
+
+	for _, tt := range []struct {
+		name     string
+		forward  bool
+		limit    int
+		expected []string
+	}{
+		{"forward", true, 10, []string{"line1", "line2", "line3", "line4", "line5"}},
+		{"backward", false, 10, []string{"line5", "line4", "line3", "line2", "line1"}},
+		{"limit", true, 2, []string{"line1", "line2"}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			tc := newTestQueryClient(streams...)
+			writer := &bytes.Buffer{}
+			out := output.NewRaw(writer, nil)
+			q := Query{
+				QueryString: `{test="stream"}`,
+				Start:       time.Unix(0, 0),
+				End:         time.Unix(10, 0),
+				Limit:       tt.limit,
+				BatchSize:   1,
+				Forward:     tt.forward,
+				Stream:      true,
+			}
+			q.DoQuery(tc, out, false)
+
+			split := strings.Split(strings.TrimSuffix(writer.String(), "\n"), "\n")
+			assert.Equal(t, tt.expected, split)
+			assert.Equal(t, 1, tc.queryRangeCalls)
+		})
+	}
+}
+
+func Test_streamError(t *testing.T) {
+	// The server streams two entries, then fails, then writes an entry which must be ignored.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		for _, line := range []string{"line1", "line2"} {
+			require.NoError(t, marshal.WriteStreamLineJSON(logproto.Stream{
+				Labels:  `{app="a"}`,
+				Entries: []logproto.Entry{{Timestamp: time.Unix(1, 0), Line: line}},
+			}, w, nil))
+		}
+		w.(http.Flusher).Flush()
+		require.NoError(t, marshal.WriteStreamErrorJSON(errors.New("query timed out"), w))
+		require.NoError(t, marshal.WriteStreamLineJSON(logproto.Stream{
+			Labels:  `{app="a"}`,
+			Entries: []logproto.Entry{{Timestamp: time.Unix(2, 0), Line: "line3"}},
+		}, w, nil))
+	}))
+	defer server.Close()
+
+	q := Query{
+		QueryString: `{app="a"}`,
+		Start:       time.Unix(0, 0),
+		End:         time.Unix(10, 0),
+		Limit:       10,
+		Forward:     true,
+		Stream:      true,
+		Quiet:       true,
+	}
+	// The entries streamed before the error are printed, the error fails the query.
+	writer := &bytes.Buffer{}
+	err := q.doStreamQuery(&logcli.DefaultClient{Address: server.URL}, output.NewRaw(writer, nil), print.NewQueryResultPrinter(nil, nil, true, 0, true), logproto.FORWARD)
+	require.EqualError(t, err, "query timed out")
+	require.Equal(t, "line1\nline2\n", writer.String())
+
+	// logcli exits with a non-zero status.
+	if os.Getenv("LOGCLI_TEST_STREAM_ERROR") == "1" {
+		q.DoQuery(&logcli.DefaultClient{Address: server.URL}, output.NewRaw(io.Discard, nil), false)
+		return
+	}
+	cmd := exec.Command(os.Args[0], "-test.run=^Test_streamError$")
+	cmd.Env = append(os.Environ(), "LOGCLI_TEST_STREAM_ERROR=1")
+	stderr := &bytes.Buffer{}
+	cmd.Stderr = stderr
+	err = cmd.Run()
+	var exitErr *exec.ExitError
+	require.ErrorAs(t, err, &exitErr)
+	require.NotEqual(t, 0, exitErr.ExitCode())
+	require.Contains(t, stderr.String(), "Query failed: query timed out")
+}
+
 type testQueryClient struct {
 	engine          *logql.Engine
 	queryRangeCalls int
@@ -453,6 +562,32 @@ func (t *testQueryClient) QueryRange(queryStr string, limit int, from, through t
 	return q, nil
 }
 
+func (t *testQueryClient) QueryRangeStream(queryStr string, limit int, from, through time.Time, direction logproto.Direction, _ bool) (io.ReadCloser, error) {
+	ctx := user.InjectOrgID(context.Background(), "fake")
+
+	params, err := logql.NewLiteralParams(queryStr, from, through, 0, 0, direction, uint32(limit), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := t.engine.Query(params).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	it := iter.NewStreamsIterator(v.Data.(logqlmodel.Streams), direction)
+	defer it.Close()
+	for it.Next() {
+		stream := logproto.Stream{Labels: it.Labels(), Entries: []logproto.Entry{it.Entry()}}
+		if err := marshal.WriteStreamLineJSON(stream, &buf, nil); err != nil {
+			return nil, err
+		}
+	}
+	t.queryRangeCalls++
+	return io.NopCloser(&buf), nil
+}
+
 func (t *testQueryClient) ListLabelNames(_ bool, _, _ time.Time) (*loghttp.LabelResponse, error) {
 	panic("implement me")
 }
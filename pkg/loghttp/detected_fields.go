@@ -0,0 +1,104 @@
+package loghttp
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/logql/syntax"
+)
+
+const (
+	defaultDetectedFieldsLineLimit  = 1000
+	defaultDetectedFieldsFieldLimit = 1000
+)
+
+// DetectedFieldsResponse represents the http json response to a detected fields query.
+type DetectedFieldsResponse struct {
+	Fields     []DetectedField `json:"fields"`
+	FieldLimit uint32          `json:"fieldLimit"`
+}
+
+// DetectedField is a field parsed from the sampled log lines.
+type DetectedField struct {
+	Label       string   `json:"label"`
+	Type        string   `json:"type"`
+	Cardinality uint64   `json:"cardinality"`
+	Parsers     []string `json:"parsers"`
+}
+
+// NewDetectedFieldsResponse converts a logproto.DetectedFieldsResponse to its http json representation.
+func NewDetectedFieldsResponse(r *logproto.DetectedFieldsResponse) DetectedFieldsResponse {
+	res := DetectedFieldsResponse{
+		Fields:     make([]DetectedField, 0, len(r.GetFields())),
+		FieldLimit: r.GetFieldLimit(),
+	}
+	for _, f := range r.GetFields() {
+		res.Fields = append(res.Fields, DetectedField{
+			Label:       f.Label,
+			Type:        f.Type,
+			Cardinality: f.Cardinality,
+			Parsers:     f.Parsers,
+		})
+	}
+	return res
+}
+
+// ToProto converts the response to a logproto.DetectedFieldsResponse.
+// The sketches of the cardinalities are not part of the json representation.
+func (r DetectedFieldsResponse) ToProto() *logproto.DetectedFieldsResponse {
+	res := &logproto.DetectedFieldsResponse{
+		Fields:     make([]*logproto.DetectedField, 0, len(r.Fields)),
+		FieldLimit: r.FieldLimit,
+	}
+	for _, f := range r.Fields {
+		res.Fields = append(res.Fields, &logproto.DetectedField{
+			Label:       f.Label,
+			Type:        f.Type,
+			Cardinality: f.Cardinality,
+			Parsers:     f.Parsers,
+		})
+	}
+	return res
+}
+
+// ParseDetectedFieldsQuery parses a detected fields request from an http request.
+func ParseDetectedFieldsQuery(r *http.Request) (*logproto.DetectedFieldsRequest, error) {
+	req := &logproto.DetectedFieldsRequest{
+		Query: query(r),
+	}
+	if _, err := syntax.ParseLogSelector(req.Query, true); err != nil {
+		return nil, err
+	}
+
+	start, end, err := bounds(r)
+	if err != nil {
+		return nil, err
+	}
+	if end.Before(start) {
+		return nil, errEndBeforeStart
+	}
+
+	lineLimit, err := parseInt(r.Form.Get("line_limit"), defaultDetectedFieldsLineLimit)
+	if err != nil {
+		return nil, err
+	}
+	if lineLimit <= 0 {
+		return nil, errors.New("line_limit must be a positive value")
+	}
+	fieldLimit, err := parseInt(r.Form.Get("field_limit"), defaultDetectedFieldsFieldLimit)
+	if err != nil {
+		return nil, err
+	}
+	if fieldLimit <= 0 {
+		return nil, errors.New("field_limit must be a positive value")
+	}
+
+	req.Start = model.TimeFromUnixNano(start.UnixNano())
+	req.End = model.TimeFromUnixNano(end.UnixNano())
+	req.LineLimit = uint32(lineLimit)
+	req.FieldLimit = uint32(fieldLimit)
+	return req, nil
+}
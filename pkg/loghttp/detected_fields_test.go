@@ -0,0 +1,70 @@
+package loghttp
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/pkg/logproto"
+)
+
+func Test_ParseDetectedFieldsQuery(t *testing.T) {
+	req := &http.Request{
+		URL: mustParseURL(`?query={foo="bar"} |= "error"` +
+			`&start=2017-06-10T21:42:24.760738998Z` +
+			`&end=2017-06-10T22:42:24.760738998Z` +
+			`&line_limit=100`,
+		),
+	}
+	require.NoError(t, req.ParseForm())
+
+	actual, err := ParseDetectedFieldsQuery(req)
+	require.NoError(t, err)
+	require.Equal(t, &logproto.DetectedFieldsRequest{
+		Query:      `{foo="bar"} |= "error"`,
+		Start:      model.TimeFromUnixNano(time.Date(2017, 06, 10, 21, 42, 24, 760738998, time.UTC).UnixNano()),
+		End:        model.TimeFromUnixNano(time.Date(2017, 06, 10, 22, 42, 24, 760738998, time.UTC).UnixNano()),
+		LineLimit:  100,
+		FieldLimit: defaultDetectedFieldsFieldLimit,
+	}, actual)
+
+	for _, tc := range []string{
+		`?query=rate({foo="bar"}[1m])`,
+		`?query={foo="bar"}&line_limit=0`,
+		`?query={foo="bar"}&field_limit=-1`,
+	} {
+		req := &http.Request{URL: mustParseURL(tc)}
+		require.NoError(t, req.ParseForm())
+
+		_, err := ParseDetectedFieldsQuery(req)
+		require.Error(t, err, tc)
+	}
+}
+
+func TestDetectedFieldsResponse_JSON(t *testing.T) {
+	resp := &logproto.DetectedFieldsResponse{
+		Fields: []*logproto.DetectedField{
+			{Label: "duration", Type: "duration", Cardinality: 12, Parsers: []string{"logfmt"}},
+			{Label: "level", Type: "string", Cardinality: 3, Parsers: []string{"json", "logfmt"}},
+		},
+		FieldLimit: 100,
+	}
+
+	b, err := json.Marshal(NewDetectedFieldsResponse(resp))
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"fields": [
+			{"label":"duration","type":"duration","cardinality":12,"parsers":["logfmt"]},
+			{"label":"level","type":"string","cardinality":3,"parsers":["json","logfmt"]}
+		],
+		"fieldLimit": 100
+	}`, string(b))
+
+	var decoded DetectedFieldsResponse
+	require.NoError(t, json.Unmarshal(b, &decoded))
+	require.Equal(t, resp, decoded.ToProto())
+}
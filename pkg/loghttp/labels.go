@@ -84,5 +84,10 @@ func ParseLabelQuery(r *http.Request) (*logproto.LabelRequest, error) {
 	req.End = &end
 
 	req.Query = query(r)
+
+	req.StructuredMetadata, err = structuredMetadata(r)
+	if err != nil {
+		return nil, err
+	}
 	return req, nil
 }
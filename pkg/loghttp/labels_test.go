@@ -41,6 +41,15 @@ func TestParseLabelQuery(t *testing.T) {
 				Start:  timePtr(time.Date(2017, 06, 10, 21, 42, 24, 760738998, time.UTC)),
 				End:    timePtr(time.Date(2017, 07, 10, 21, 42, 24, 760738998, time.UTC)),
 			}, false},
+		{"good with structured metadata",
+			&http.Request{
+				URL: mustParseURL(`?start=2017-06-10T21:42:24.760738998Z&end=2017-07-10T21:42:24.760738998Z&structured_metadata=true`),
+			}, &logproto.LabelRequest{
+				Start:              timePtr(time.Date(2017, 06, 10, 21, 42, 24, 760738998, time.UTC)),
+				End:                timePtr(time.Date(2017, 07, 10, 21, 42, 24, 760738998, time.UTC)),
+				StructuredMetadata: true,
+			}, false},
+		{"bad structured metadata", &http.Request{URL: mustParseURL(`?structured_metadata=maybe`)}, nil, true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -38,6 +38,18 @@ func query(r *http.Request) string {
 	return r.Form.Get("query")
 }
 
+func structuredMetadata(r *http.Request) (bool, error) {
+	value := r.Form.Get("structured_metadata")
+	if value == "" {
+		return false, nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, errors.Wrap(err, "could not parse 'structured_metadata' parameter")
+	}
+	return b, nil
+}
+
 func ts(r *http.Request) (time.Time, error) {
 	return parseTimestamp(r.Form.Get("time"), time.Now())
 }
@@ -0,0 +1,144 @@
+package loghttp
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/grafana/jsonparser"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/logql/syntax"
+	"github.com/grafana/loki/pkg/pattern/drain"
+)
+
+// PatternsResponse represents the http json response to a patterns query.
+type PatternsResponse struct {
+	Status string          `json:"status"`
+	Data   []PatternSeries `json:"data"`
+}
+
+// PatternSeries is a pattern and the number of occurrences of its lines over time.
+// Stage is the LogQL stage selecting the lines of the pattern, ready to be pasted into a query.
+type PatternSeries struct {
+	Pattern string          `json:"pattern"`
+	Stage   string          `json:"stage"`
+	Samples []PatternSample `json:"samples"`
+}
+
+// PatternSample is the number of occurrences of a pattern at a timestamp.
+// It is encoded as a [<unix seconds>, <count>] array.
+type PatternSample struct {
+	Timestamp model.Time
+	Value     int64
+}
+
+func (s PatternSample) MarshalJSON() ([]byte, error) {
+	ts, err := s.Timestamp.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	return []byte(fmt.Sprintf("[%s,%d]", ts, s.Value)), nil
+}
+
+func (s *PatternSample) UnmarshalJSON(data []byte) error {
+	var (
+		i   int
+		err error
+	)
+	_, arrErr := jsonparser.ArrayEach(data, func(value []byte, _ jsonparser.ValueType, _ int, _ error) {
+		if err != nil {
+			return
+		}
+		switch i {
+		case 0:
+			err = s.Timestamp.UnmarshalJSON(value)
+		case 1:
+			s.Value, err = jsonparser.ParseInt(value)
+		}
+		i++
+	})
+	if arrErr != nil {
+		return arrErr
+	}
+	if err != nil {
+		return err
+	}
+	if i != 2 {
+		return fmt.Errorf("invalid pattern sample: %s", data)
+	}
+	return nil
+}
+
+// NewPatternsResponse converts a logproto.QueryPatternsResponse to its http json representation.
+func NewPatternsResponse(r *logproto.QueryPatternsResponse) PatternsResponse {
+	res := PatternsResponse{
+		Status: QueryStatusSuccess,
+		Data:   make([]PatternSeries, 0, len(r.GetSeries())),
+	}
+	for _, s := range r.GetSeries() {
+		series := PatternSeries{
+			Pattern: s.Pattern,
+			Stage:   drain.Stage(s.Pattern),
+			Samples: make([]PatternSample, 0, len(s.Samples)),
+		}
+		for _, sample := range s.Samples {
+			series.Samples = append(series.Samples, PatternSample(sample))
+		}
+		res.Data = append(res.Data, series)
+	}
+	return res
+}
+
+// ToProto converts the response to a logproto.QueryPatternsResponse.
+func (r PatternsResponse) ToProto() *logproto.QueryPatternsResponse {
+	res := &logproto.QueryPatternsResponse{
+		Series: make([]*logproto.PatternSeries, 0, len(r.Data)),
+	}
+	for _, s := range r.Data {
+		series := &logproto.PatternSeries{
+			Pattern: s.Pattern,
+			Samples: make([]logproto.PatternSample, 0, len(s.Samples)),
+		}
+		for _, sample := range s.Samples {
+			series.Samples = append(series.Samples, logproto.PatternSample(sample))
+		}
+		res.Series = append(res.Series, series)
+	}
+	return res
+}
+
+// ParsePatternsQuery parses a patterns request from an http request.
+func ParsePatternsQuery(r *http.Request) (*logproto.QueryPatternsRequest, error) {
+	var err error
+	req := &logproto.QueryPatternsRequest{
+		Query: query(r),
+	}
+	if _, err = syntax.ParseMatchers(req.Query, true); err != nil {
+		return nil, err
+	}
+
+	start, end, err := bounds(r)
+	if err != nil {
+		return nil, err
+	}
+	if end.Before(start) {
+		return nil, errEndBeforeStart
+	}
+
+	step, err := step(r, start, end)
+	if err != nil {
+		return nil, err
+	}
+	if step <= 0 {
+		return nil, errZeroOrNegativeStep
+	}
+	if (end.Sub(start) / step) > 11000 {
+		return nil, errStepTooSmall
+	}
+
+	req.Start = model.TimeFromUnixNano(start.UnixNano())
+	req.End = model.TimeFromUnixNano(end.UnixNano())
+	req.Step = step.Milliseconds()
+	return req, nil
+}
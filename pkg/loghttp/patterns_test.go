@@ -0,0 +1,60 @@
+package loghttp
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/pkg/logproto"
+)
+
+func Test_ParsePatternsQuery(t *testing.T) {
+	req := &http.Request{
+		URL: mustParseURL(`?query={foo="bar"}` +
+			`&start=2017-06-10T21:42:24.760738998Z` +
+			`&end=2017-06-10T22:42:24.760738998Z` +
+			`&step=60`,
+		),
+	}
+	require.NoError(t, req.ParseForm())
+
+	actual, err := ParsePatternsQuery(req)
+	require.NoError(t, err)
+	require.Equal(t, &logproto.QueryPatternsRequest{
+		Query: `{foo="bar"}`,
+		Start: model.TimeFromUnixNano(time.Date(2017, 06, 10, 21, 42, 24, 760738998, time.UTC).UnixNano()),
+		End:   model.TimeFromUnixNano(time.Date(2017, 06, 10, 22, 42, 24, 760738998, time.UTC).UnixNano()),
+		Step:  time.Minute.Milliseconds(),
+	}, actual)
+
+	t.Run("invalid selector", func(t *testing.T) {
+		req := &http.Request{URL: mustParseURL(`?query=rate({foo="bar"}[1m])`)}
+		require.NoError(t, req.ParseForm())
+
+		_, err := ParsePatternsQuery(req)
+		require.Error(t, err)
+	})
+}
+
+func TestPatternsResponse_JSON(t *testing.T) {
+	resp := &logproto.QueryPatternsResponse{
+		Series: []*logproto.PatternSeries{
+			{
+				Pattern: `user <_> logged in from "<_>"`,
+				Samples: []logproto.PatternSample{{Timestamp: 1700000000000, Value: 3}, {Timestamp: 1700000010500, Value: 1}},
+			},
+		},
+	}
+
+	b, err := json.Marshal(NewPatternsResponse(resp))
+	require.NoError(t, err)
+	require.JSONEq(t, `{"status":"success","data":[{"pattern":"user <_> logged in from \"<_>\"","stage":"| pattern \"user <v1> logged in from \\\"<v2>\\\"\"","samples":[[1700000000,3],[1700000010.5,1]]}]}`, string(b))
+
+	var decoded PatternsResponse
+	require.NoError(t, json.Unmarshal(b, &decoded))
+	require.Equal(t, resp, decoded.ToProto())
+}
@@ -0,0 +1,85 @@
+package push
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/storage/remote/otlptranslator/prometheus"
+
+	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/push"
+)
+
+// streamsBuilder groups the entries parsed from the requests of other log shippers' APIs into streams.
+type streamsBuilder struct {
+	streams []logproto.Stream
+	indexes map[string]int
+}
+
+func newStreamsBuilder() *streamsBuilder {
+	return &streamsBuilder{indexes: map[string]int{}}
+}
+
+// add adds the entry to the stream with the given labels.
+func (b *streamsBuilder) add(lbs model.LabelSet, entry push.Entry) error {
+	if err := lbs.Validate(); err != nil {
+		return fmt.Errorf("invalid labels: %w", err)
+	}
+	key := lbs.String()
+	i, ok := b.indexes[key]
+	if !ok {
+		i = len(b.streams)
+		b.indexes[key] = i
+		b.streams = append(b.streams, logproto.Stream{Labels: key})
+	}
+	b.streams[i].Entries = append(b.streams[i].Entries, entry)
+	return nil
+}
+
+func (b *streamsBuilder) request() *logproto.PushRequest {
+	return &logproto.PushRequest{Streams: b.streams}
+}
+
+// appendFields appends the JSON fields to the structured metadata, sorted by name. The names of nested fields are
+// joined with underscores, and arrays are kept as JSON. Fields must have been decoded using json.Number.
+func appendFields(structuredMetadata push.LabelsAdapter, prefix string, fields map[string]interface{}) push.LabelsAdapter {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, key := range names {
+		name := key
+		if prefix != "" {
+			name = prefix + "_" + key
+		}
+		name = prometheus.NormalizeLabel(name)
+
+		var value string
+		switch v := fields[key].(type) {
+		case nil:
+			continue
+		case map[string]interface{}:
+			structuredMetadata = appendFields(structuredMetadata, name, v)
+			continue
+		case string:
+			value = v
+		case json.Number:
+			value = v.String()
+		case bool:
+			value = strconv.FormatBool(v)
+		default:
+			b, err := json.Marshal(v)
+			if err != nil {
+				continue
+			}
+			value = string(b)
+		}
+		structuredMetadata = append(structuredMetadata, push.LabelAdapter{Name: name, Value: value})
+	}
+	return structuredMetadata
+}
@@ -0,0 +1,224 @@
+package push
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/push"
+	loki_util "github.com/grafana/loki/pkg/util"
+)
+
+const (
+	// elasticsearchVersion is the Elasticsearch version reported to the clients checking it before sending requests.
+	elasticsearchVersion = "8.13.0"
+
+	esIndexLabel     = "index"
+	esHostLabel      = "host"
+	esTimestampField = "@timestamp"
+)
+
+// esBulkAction is the metadata of an action of a bulk request.
+type esBulkAction struct {
+	Index string `json:"_index"`
+	ID    string `json:"_id,omitempty"`
+}
+
+// esBulkItem is the result of an action of a bulk request.
+type esBulkItem struct {
+	Index  string `json:"_index,omitempty"`
+	ID     string `json:"_id,omitempty"`
+	Status int    `json:"status"`
+	Result string `json:"result"`
+}
+
+// ElasticsearchBulk parses a request to the Elasticsearch bulk API and writes its response, which lists the result
+// of each of the actions of the request. A new one is needed for each request.
+type ElasticsearchBulk struct {
+	items []map[string]esBulkItem
+}
+
+// ParseRequest parses a request to the Elasticsearch bulk API: newline-delimited JSON, where each index or create
+// action is followed by the document to store. Delete actions are skipped, as entries can't be deleted, and other
+// actions are rejected.
+//
+// Each document is converted to an entry of the stream labeled with its index, and its host if any. The line is the
+// message field of the document, or its log field, and its other fields are stored as structured metadata. Documents
+// without such a field are stored as JSON lines.
+func (b *ElasticsearchBulk) ParseRequest(userID string, r *http.Request, tenantsRetention TenantsRetention, _ Limits, tracker UsageTracker) (*logproto.PushRequest, *Stats, error) {
+	stats := newPushStats()
+	// bodySize should always reflect the compressed size of the request body
+	bodySize := loki_util.NewSizeReader(r.Body)
+	stats.ContentType = r.Header.Get(contentType)
+	stats.ContentEncoding = r.Header.Get(contentEnc)
+	body, closeBody, err := decompressBody(bodySize, stats.ContentEncoding)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer closeBody()
+
+	defaultIndex := mux.Vars(r)["index"]
+	builder := newStreamsBuilder()
+	decoder := json.NewDecoder(body)
+	decoder.UseNumber()
+	b.items = b.items[:0]
+	for {
+		var action map[string]esBulkAction
+		if err := decoder.Decode(&action); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, nil, fmt.Errorf("invalid bulk action: %w", err)
+		}
+		if len(action) != 1 {
+			return nil, nil, fmt.Errorf("invalid bulk action: expected a single action, got %d", len(action))
+		}
+
+		for op, meta := range action {
+			index := meta.Index
+			if index == "" {
+				index = defaultIndex
+			}
+
+			switch op {
+			case "index", "create":
+			case "delete":
+				b.items = append(b.items, map[string]esBulkItem{op: {Index: index, ID: meta.ID, Status: http.StatusNotFound, Result: "not_found"}})
+				continue
+			default:
+				return nil, nil, fmt.Errorf("unsupported bulk action %q", op)
+			}
+
+			var doc map[string]interface{}
+			if err := decoder.Decode(&doc); err != nil {
+				return nil, nil, fmt.Errorf("invalid document: %w", err)
+			}
+			if index == "" {
+				return nil, nil, errors.New("invalid document: missing index")
+			}
+
+			lbs, entry, err := esDocumentToEntry(index, doc)
+			if err != nil {
+				return nil, nil, fmt.Errorf("invalid document: %w", err)
+			}
+			if err := builder.add(lbs, entry); err != nil {
+				return nil, nil, err
+			}
+			b.items = append(b.items, map[string]esBulkItem{op: {Index: index, ID: meta.ID, Status: http.StatusCreated, Result: "created"}})
+		}
+	}
+	stats.BodySize = bodySize.Size()
+
+	req := builder.request()
+	if err := addStreamsStats(r.Context(), userID, req.Streams, tenantsRetention, tracker, stats); err != nil {
+		return nil, nil, err
+	}
+	return req, stats, nil
+}
+
+// WriteResponse writes the response to a successful bulk request, with an item for each action of the request.
+func (b *ElasticsearchBulk) WriteResponse(w http.ResponseWriter) {
+	items := b.items
+	if items == nil {
+		items = []map[string]esBulkItem{}
+	}
+
+	w.Header().Set(contentType, applicationJSON)
+	w.Header().Set("X-Elastic-Product", "Elasticsearch")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"took":   0,
+		"errors": false,
+		"items":  items,
+	})
+}
+
+// esDocumentToEntry converts an Elasticsearch document to an entry, returning the labels of its stream.
+func esDocumentToEntry(index string, doc map[string]interface{}) (model.LabelSet, push.Entry, error) {
+	entry := push.Entry{Timestamp: time.Now()}
+	if v, ok := doc[esTimestampField]; ok {
+		ts, err := parseESTimestamp(v)
+		if err != nil {
+			return nil, entry, err
+		}
+		entry.Timestamp = ts
+		delete(doc, esTimestampField)
+	}
+
+	lbs := model.LabelSet{esIndexLabel: model.LabelValue(index)}
+	// The host is either a string, or an object with a name as in the Elastic Common Schema.
+	switch host := doc["host"].(type) {
+	case string:
+		lbs[esHostLabel] = model.LabelValue(host)
+		delete(doc, "host")
+	case map[string]interface{}:
+		if name, ok := host["name"].(string); ok {
+			lbs[esHostLabel] = model.LabelValue(name)
+			delete(host, "name")
+			if len(host) == 0 {
+				delete(doc, "host")
+			}
+		}
+	}
+
+	if line, ok := doc["message"].(string); ok {
+		entry.Line = line
+		delete(doc, "message")
+	} else if line, ok := doc["log"].(string); ok {
+		entry.Line = line
+		delete(doc, "log")
+	} else {
+		line, err := json.Marshal(doc)
+		if err != nil {
+			return nil, entry, err
+		}
+		entry.Line = string(line)
+		return lbs, entry, nil
+	}
+	entry.StructuredMetadata = appendFields(nil, "", doc)
+	return lbs, entry, nil
+}
+
+// parseESTimestamp parses a timestamp in the default formats of Elasticsearch dates: RFC 3339, or milliseconds
+// since the epoch.
+func parseESTimestamp(v interface{}) (time.Time, error) {
+	switch ts := v.(type) {
+	case string:
+		t, err := time.Parse(time.RFC3339Nano, ts)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid %s: %w", esTimestampField, err)
+		}
+		return t, nil
+	case json.Number:
+		ms, err := ts.Int64()
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid %s: %w", esTimestampField, err)
+		}
+		return time.UnixMilli(ms), nil
+	default:
+		return time.Time{}, fmt.Errorf("invalid %s: %v", esTimestampField, v)
+	}
+}
+
+// ElasticsearchInfoHandler responds to the requests for the cluster information, which clients send to check the
+// Elasticsearch version before sending bulk requests.
+func ElasticsearchInfoHandler(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set(contentType, applicationJSON)
+	w.Header().Set("X-Elastic-Product", "Elasticsearch")
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"name":         "loki",
+		"cluster_name": "loki",
+		"version": map[string]interface{}{
+			"number":       elasticsearchVersion,
+			"build_flavor": "default",
+		},
+		"tagline": "You Know, for Search",
+	})
+}
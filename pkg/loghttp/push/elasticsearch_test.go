@@ -0,0 +1,95 @@
+package push
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/push"
+)
+
+func TestParseElasticsearchBulkRequest(t *testing.T) {
+	body := `{"index":{"_index":"filebeat"}}
+{"@timestamp":"2024-01-01T00:00:00.123Z","message":"GET /","host":{"name":"web-1","os":{"family":"debian"}},"http":{"status":200,"ok":true},"tags":["a","b"],"empty":null}
+{"delete":{"_index":"filebeat","_id":"1"}}
+{"create":{}}
+{"@timestamp":1704067201000,"log":"from fluentd","stream":"stdout"}
+{"index":{"_index":"filebeat"}}
+{"@timestamp":"2024-01-01T00:00:02Z","host":"web-1","level":"info"}
+`
+	request := httptest.NewRequest("POST", "/elasticsearch/fluentd/_bulk", strings.NewReader(gzipString(body)))
+	request.Header.Add("Content-Encoding", "gzip")
+	request = mux.SetURLVars(request, map[string]string{"index": "fluentd"})
+
+	tracker := NewMockTracker()
+	bulk := &ElasticsearchBulk{}
+	req, stats, err := bulk.ParseRequest("fake", request, EmptyTenantsRetention{}, EmptyLimits{}, tracker)
+	require.NoError(t, err)
+	require.Equal(t, []logproto.Stream{
+		{
+			Labels: `{host="web-1", index="filebeat"}`,
+			Entries: []push.Entry{
+				{
+					Timestamp: time.Date(2024, 1, 1, 0, 0, 0, 123000000, time.UTC),
+					Line:      "GET /",
+					StructuredMetadata: push.LabelsAdapter{
+						{Name: "host_os_family", Value: "debian"},
+						{Name: "http_ok", Value: "true"},
+						{Name: "http_status", Value: "200"},
+						{Name: "tags", Value: `["a","b"]`},
+					},
+				},
+				{
+					Timestamp: time.Date(2024, 1, 1, 0, 0, 2, 0, time.UTC),
+					Line:      `{"level":"info"}`,
+				},
+			},
+		},
+		{
+			Labels: `{index="fluentd"}`,
+			Entries: []push.Entry{
+				{
+					Timestamp:          time.UnixMilli(1704067201000),
+					Line:               "from fluentd",
+					StructuredMetadata: push.LabelsAdapter{{Name: "stream", Value: "stdout"}},
+				},
+			},
+		},
+	}, req.Streams)
+	require.Equal(t, int64(3), stats.NumLines)
+	require.Equal(t, "gzip", stats.ContentEncoding)
+	require.Equal(t, float64(len("GET /from fluentd{\"level\":\"info\"}")+len("host_os_familydebianhttp_oktruehttp_status200tags[\"a\",\"b\"]streamstdout")), tracker.Total())
+
+	rec := httptest.NewRecorder()
+	bulk.WriteResponse(rec)
+	require.Equal(t, 200, rec.Code)
+	require.Equal(t, "Elasticsearch", rec.Header().Get("X-Elastic-Product"))
+	require.JSONEq(t, `{"took":0,"errors":false,"items":[
+		{"index":{"_index":"filebeat","status":201,"result":"created"}},
+		{"delete":{"_index":"filebeat","_id":"1","status":404,"result":"not_found"}},
+		{"create":{"_index":"fluentd","status":201,"result":"created"}},
+		{"index":{"_index":"filebeat","status":201,"result":"created"}}
+	]}`, rec.Body.String())
+}
+
+func TestParseElasticsearchBulkRequest_Invalid(t *testing.T) {
+	for name, body := range map[string]string{
+		"invalid action":    `{"index":`,
+		"multiple actions":  `{"index":{"_index":"a"},"create":{"_index":"a"}}`,
+		"update action":     `{"update":{"_index":"a","_id":"1"}}` + "\n" + `{"doc":{"message":"a"}}`,
+		"missing document":  `{"index":{"_index":"a"}}`,
+		"missing index":     `{"index":{}}` + "\n" + `{"message":"a"}`,
+		"invalid timestamp": `{"index":{"_index":"a"}}` + "\n" + `{"@timestamp":"yesterday","message":"a"}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			request := httptest.NewRequest("POST", "/elasticsearch/_bulk", strings.NewReader(body))
+			_, _, err := (&ElasticsearchBulk{}).ParseRequest("fake", request, EmptyTenantsRetention{}, EmptyLimits{}, nil)
+			require.Error(t, err)
+		})
+	}
+}
@@ -48,6 +48,13 @@ func ParseOTLPRequest(userID string, r *http.Request, tenantsRetention TenantsRe
 	return req, stats, nil
 }
 
+// OTLPToLokiPushRequest converts OTLP logs to a Loki push request, storing resource, scope and log
+// attributes as labels or structured metadata according to otlpConfig. Agents receiving OTLP logs
+// use it to produce the same streams as the distributor OTLP endpoint.
+func OTLPToLokiPushRequest(ctx context.Context, ld plog.Logs, otlpConfig OTLPConfig) *logproto.PushRequest {
+	return otlpToLokiPushRequest(ctx, ld, "", EmptyTenantsRetention{}, otlpConfig, nil, newPushStats())
+}
+
 func extractLogs(r *http.Request, pushStats *Stats) (plog.Logs, error) {
 	pushStats.ContentEncoding = r.Header.Get(contentEnc)
 	// bodySize should always reflect the compressed size of the request body
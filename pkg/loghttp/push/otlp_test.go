@@ -1,9 +1,12 @@
 package push
 
 import (
+	"bytes"
 	"context"
 	"encoding/base64"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
@@ -12,6 +15,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/plog/plogotlp"
 
 	"github.com/grafana/loki/pkg/logproto"
 	"github.com/grafana/loki/pkg/push"
@@ -706,3 +710,41 @@ type fakeRetention struct{}
 func (f fakeRetention) RetentionPeriodFor(_ string, _ labels.Labels) time.Duration {
 	return time.Hour
 }
+
+func TestOTLPToLokiPushRequest_MatchesParseOTLPRequest(t *testing.T) {
+	otlpConfig := DefaultOTLPConfig(defaultGlobalOTLPConfig)
+
+	generateLogs := func() plog.Logs {
+		ld := plog.NewLogs()
+		rl := ld.ResourceLogs().AppendEmpty()
+		rl.Resource().Attributes().PutStr("service.name", "service-1")
+		rl.Resource().Attributes().PutStr("host.name", "node-1")
+		sl := rl.ScopeLogs().AppendEmpty()
+		sl.Scope().SetName("fizz")
+		lr := sl.LogRecords().AppendEmpty()
+		lr.Body().SetStr("test body")
+		lr.SetTimestamp(pcommon.Timestamp(time.Unix(1, 0).UnixNano()))
+		lr.Attributes().PutStr("user", "jane")
+		return ld
+	}
+
+	body, err := plogotlp.NewExportRequestFromLogs(generateLogs()).MarshalProto()
+	require.NoError(t, err)
+	r := httptest.NewRequest(http.MethodPost, "/otlp/v1/logs", bytes.NewReader(body))
+	r.Header.Set("Content-Type", pbContentType)
+
+	expected, _, err := ParseOTLPRequest("", r, EmptyTenantsRetention{}, fakeOTLPLimits{otlpConfig}, nil)
+	require.NoError(t, err)
+
+	actual := OTLPToLokiPushRequest(context.Background(), generateLogs(), otlpConfig)
+	require.Equal(t, expected, actual)
+	require.Equal(t, `{service_name="service-1"}`, actual.Streams[0].Labels)
+}
+
+type fakeOTLPLimits struct {
+	otlpConfig OTLPConfig
+}
+
+func (l fakeOTLPLimits) OTLPConfig(string) OTLPConfig {
+	return l.otlpConfig
+}
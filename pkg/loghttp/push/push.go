@@ -3,6 +3,7 @@ package push
 import (
 	"compress/flate"
 	"compress/gzip"
+	"context"
 	"fmt"
 	"io"
 	"math"
@@ -62,6 +63,12 @@ type TenantsRetention interface {
 	RetentionPeriodFor(userID string, lbs labels.Labels) time.Duration
 }
 
+type EmptyTenantsRetention struct{}
+
+func (EmptyTenantsRetention) RetentionPeriodFor(string, labels.Labels) time.Duration {
+	return 0
+}
+
 type Limits interface {
 	OTLPConfig(userID string) OTLPConfig
 }
@@ -75,6 +82,14 @@ func (EmptyLimits) OTLPConfig(string) OTLPConfig {
 type RequestParser func(userID string, r *http.Request, tenantsRetention TenantsRetention, limits Limits, tracker UsageTracker) (*logproto.PushRequest, *Stats, error)
 type RequestParserWrapper func(inner RequestParser) RequestParser
 
+// ResponseWriter writes the response to a successful push request.
+type ResponseWriter func(w http.ResponseWriter)
+
+// WriteNoContentResponse writes the response of the native push API to a successful push request.
+func WriteNoContentResponse(w http.ResponseWriter) {
+	w.WriteHeader(http.StatusNoContent)
+}
+
 type Stats struct {
 	Errs                            []error
 	NumLines                        int64
@@ -149,32 +164,14 @@ func ParseRequest(logger log.Logger, userID string, r *http.Request, tenantsRete
 
 func ParseLokiRequest(userID string, r *http.Request, tenantsRetention TenantsRetention, _ Limits, tracker UsageTracker) (*logproto.PushRequest, *Stats, error) {
 	// Body
-	var body io.Reader
 	// bodySize should always reflect the compressed size of the request body
 	bodySize := loki_util.NewSizeReader(r.Body)
 	contentEncoding := r.Header.Get(contentEnc)
-	switch contentEncoding {
-	case "":
-		body = bodySize
-	case "snappy":
-		// Snappy-decoding is done by `util.ParseProtoReader(..., util.RawSnappy)` below.
-		// Pass on body bytes. Note: HTTP clients do not need to set this header,
-		// but they sometimes do. See #3407.
-		body = bodySize
-	case "gzip":
-		gzipReader, err := gzip.NewReader(bodySize)
-		if err != nil {
-			return nil, nil, err
-		}
-		defer gzipReader.Close()
-		body = gzipReader
-	case "deflate":
-		flateReader := flate.NewReader(bodySize)
-		defer flateReader.Close()
-		body = flateReader
-	default:
-		return nil, nil, fmt.Errorf("Content-Encoding %q not supported", contentEncoding)
+	body, closeBody, err := decompressBody(bodySize, contentEncoding)
+	if err != nil {
+		return nil, nil, err
 	}
+	defer closeBody()
 
 	contentType := r.Header.Get(contentType)
 	var (
@@ -182,7 +179,7 @@ func ParseLokiRequest(userID string, r *http.Request, tenantsRetention TenantsRe
 		pushStats = newPushStats()
 	)
 
-	contentType, _ /* params */, err := mime.ParseMediaType(contentType)
+	contentType, _ /* params */, err = mime.ParseMediaType(contentType)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -216,14 +213,51 @@ func ParseLokiRequest(userID string, r *http.Request, tenantsRetention TenantsRe
 	pushStats.ContentType = contentType
 	pushStats.ContentEncoding = contentEncoding
 
-	for _, s := range req.Streams {
+	if err := addStreamsStats(r.Context(), userID, req.Streams, tenantsRetention, tracker, pushStats); err != nil {
+		return nil, nil, err
+	}
+
+	return &req, pushStats, nil
+}
+
+// decompressBody returns a reader decompressing the body according to its content encoding, along with a function
+// releasing the reader.
+func decompressBody(body io.Reader, contentEncoding string) (io.Reader, func(), error) {
+	switch contentEncoding {
+	case "":
+		return body, func() {}, nil
+	case "snappy":
+		// Snappy-decoding is done by `util.ParseProtoReader(..., util.RawSnappy)` in ParseLokiRequest.
+		// Pass on body bytes. Note: HTTP clients do not need to set this header,
+		// but they sometimes do. See #3407.
+		return body, func() {}, nil
+	case "gzip":
+		gzipReader, err := gzip.NewReader(body)
+		if err != nil {
+			return nil, nil, err
+		}
+		return gzipReader, func() { _ = gzipReader.Close() }, nil
+	case "deflate":
+		flateReader := flate.NewReader(body)
+		return flateReader, func() { _ = flateReader.Close() }, nil
+	default:
+		return nil, nil, fmt.Errorf("Content-Encoding %q not supported", contentEncoding)
+	}
+}
+
+// addStreamsStats adds the sizes of the streams to the push stats, and records them in the usage tracker.
+func addStreamsStats(ctx context.Context, userID string, streams []logproto.Stream, tenantsRetention TenantsRetention, tracker UsageTracker, pushStats *Stats) error {
+	for _, s := range streams {
 		pushStats.StreamLabelsSize += int64(len(s.Labels))
 
-		var lbs labels.Labels
+		var (
+			lbs labels.Labels
+			err error
+		)
 		if tenantsRetention != nil || tracker != nil {
 			lbs, err = syntax.ParseLabels(s.Labels)
 			if err != nil {
-				return nil, nil, fmt.Errorf("couldn't parse labels: %w", err)
+				return fmt.Errorf("couldn't parse labels: %w", err)
 			}
 		}
 		var retentionPeriod time.Duration
@@ -240,8 +274,8 @@ func ParseLokiRequest(userID string, r *http.Request, tenantsRetention TenantsRe
 			pushStats.StructuredMetadataBytes[retentionPeriod] += entryLabelsSize
 
 			if tracker != nil {
-				tracker.ReceivedBytesAdd(r.Context(), userID, retentionPeriod, lbs, float64(len(e.Line)))
-				tracker.ReceivedBytesAdd(r.Context(), userID, retentionPeriod, lbs, float64(entryLabelsSize))
+				tracker.ReceivedBytesAdd(ctx, userID, retentionPeriod, lbs, float64(len(e.Line)))
+				tracker.ReceivedBytesAdd(ctx, userID, retentionPeriod, lbs, float64(entryLabelsSize))
 			}
 
 			if e.Timestamp.After(pushStats.MostRecentEntryTimestamp) {
@@ -249,8 +283,7 @@ func ParseLokiRequest(userID string, r *http.Request, tenantsRetention TenantsRe
 			}
 		}
 	}
-
-	return &req, pushStats, nil
+	return nil
 }
 
 func RetentionPeriodToString(retentionPeriod time.Duration) string {
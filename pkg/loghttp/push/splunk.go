@@ -0,0 +1,213 @@
+package push
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/push"
+	loki_util "github.com/grafana/loki/pkg/util"
+)
+
+const (
+	// splunkDefaultIndex is the index of the events sent without one, as the default index of Splunk.
+	splunkDefaultIndex = "main"
+
+	splunkIndexLabel      = "index"
+	splunkSourceTypeLabel = "sourcetype"
+	splunkHostLabel       = "host"
+	splunkSourceMetadata  = "source"
+)
+
+// splunkEvent is an event sent to the Splunk HTTP Event Collector (HEC).
+type splunkEvent struct {
+	Time       json.Number            `json:"time"`
+	Host       string                 `json:"host"`
+	Source     string                 `json:"source"`
+	SourceType string                 `json:"sourcetype"`
+	Index      string                 `json:"index"`
+	Event      json.RawMessage        `json:"event"`
+	Fields     map[string]interface{} `json:"fields"`
+}
+
+// ParseSplunkHECEventRequest parses a request to the event endpoint of the Splunk HTTP Event Collector: a sequence of
+// JSON events.
+//
+// Each event is converted to an entry of the stream labeled with its index, source type and host. The line is the
+// event, as JSON if it isn't a string, and the source and fields of the event are stored as structured metadata.
+func ParseSplunkHECEventRequest(userID string, r *http.Request, tenantsRetention TenantsRetention, _ Limits, tracker UsageTracker) (*logproto.PushRequest, *Stats, error) {
+	return parseSplunkHECRequest(userID, r, tenantsRetention, tracker, func(body io.Reader, builder *streamsBuilder) error {
+		decoder := json.NewDecoder(body)
+		decoder.UseNumber()
+		for {
+			var event splunkEvent
+			if err := decoder.Decode(&event); err != nil {
+				if errors.Is(err, io.EOF) {
+					return nil
+				}
+				return fmt.Errorf("invalid event: %w", err)
+			}
+			lbs, entry, err := splunkEventToEntry(event)
+			if err != nil {
+				return fmt.Errorf("invalid event: %w", err)
+			}
+			if err := builder.add(lbs, entry); err != nil {
+				return err
+			}
+		}
+	})
+}
+
+// ParseSplunkHECRawRequest parses a request to the raw endpoint of the Splunk HTTP Event Collector, where each line
+// of the body is an event. The index, source type, host and source of the events are set by the query parameters.
+func ParseSplunkHECRawRequest(userID string, r *http.Request, tenantsRetention TenantsRetention, _ Limits, tracker UsageTracker) (*logproto.PushRequest, *Stats, error) {
+	query := r.URL.Query()
+	lbs := splunkLabels(query.Get("index"), query.Get("sourcetype"), query.Get("host"))
+	var structuredMetadata push.LabelsAdapter
+	if source := query.Get("source"); source != "" {
+		structuredMetadata = push.LabelsAdapter{{Name: splunkSourceMetadata, Value: source}}
+	}
+
+	return parseSplunkHECRequest(userID, r, tenantsRetention, tracker, func(body io.Reader, builder *streamsBuilder) error {
+		now := time.Now()
+		reader := bufio.NewReader(body)
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil && !errors.Is(err, io.EOF) {
+				return err
+			}
+			if line = strings.TrimRight(line, "\r\n"); line != "" {
+				entry := push.Entry{Timestamp: now, Line: line, StructuredMetadata: structuredMetadata}
+				if err := builder.add(lbs, entry); err != nil {
+					return err
+				}
+			}
+			if err != nil {
+				return nil
+			}
+		}
+	})
+}
+
+// parseSplunkHECRequest decompresses the body of a request and parses it into streams.
+func parseSplunkHECRequest(userID string, r *http.Request, tenantsRetention TenantsRetention, tracker UsageTracker, parse func(io.Reader, *streamsBuilder) error) (*logproto.PushRequest, *Stats, error) {
+	stats := newPushStats()
+	// bodySize should always reflect the compressed size of the request body
+	bodySize := loki_util.NewSizeReader(r.Body)
+	stats.ContentType = r.Header.Get(contentType)
+	stats.ContentEncoding = r.Header.Get(contentEnc)
+	body, closeBody, err := decompressBody(bodySize, stats.ContentEncoding)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer closeBody()
+
+	builder := newStreamsBuilder()
+	if err := parse(body, builder); err != nil {
+		return nil, nil, err
+	}
+	stats.BodySize = bodySize.Size()
+
+	req := builder.request()
+	if err := addStreamsStats(r.Context(), userID, req.Streams, tenantsRetention, tracker, stats); err != nil {
+		return nil, nil, err
+	}
+	return req, stats, nil
+}
+
+// splunkEventToEntry converts a HEC event to an entry, returning the labels of its stream.
+func splunkEventToEntry(event splunkEvent) (model.LabelSet, push.Entry, error) {
+	entry := push.Entry{Timestamp: time.Now()}
+	if event.Time != "" {
+		ts, err := parseEpochSeconds(event.Time.String())
+		if err != nil {
+			return nil, entry, fmt.Errorf("invalid time: %w", err)
+		}
+		entry.Timestamp = ts
+	}
+
+	event.Event = bytes.TrimSpace(event.Event)
+	if len(event.Event) == 0 || bytes.Equal(event.Event, []byte("null")) {
+		return nil, entry, errors.New("event field is required")
+	}
+	if event.Event[0] == '"' {
+		if err := json.Unmarshal(event.Event, &entry.Line); err != nil {
+			return nil, entry, err
+		}
+	} else {
+		var line bytes.Buffer
+		if err := json.Compact(&line, event.Event); err != nil {
+			return nil, entry, err
+		}
+		entry.Line = line.String()
+	}
+	if strings.TrimSpace(entry.Line) == "" {
+		return nil, entry, errors.New("event field cannot be blank")
+	}
+
+	if event.Source != "" {
+		entry.StructuredMetadata = push.LabelsAdapter{{Name: splunkSourceMetadata, Value: event.Source}}
+	}
+	entry.StructuredMetadata = appendFields(entry.StructuredMetadata, "", event.Fields)
+	return splunkLabels(event.Index, event.SourceType, event.Host), entry, nil
+}
+
+// splunkLabels returns the labels of the stream of the events with the given index, source type and host.
+func splunkLabels(index, sourceType, host string) model.LabelSet {
+	if index == "" {
+		index = splunkDefaultIndex
+	}
+	lbs := model.LabelSet{splunkIndexLabel: model.LabelValue(index)}
+	if sourceType != "" {
+		lbs[splunkSourceTypeLabel] = model.LabelValue(sourceType)
+	}
+	if host != "" {
+		lbs[splunkHostLabel] = model.LabelValue(host)
+	}
+	return lbs
+}
+
+// parseEpochSeconds parses a number of seconds since the epoch with an optional decimal part, without the precision
+// loss of floats.
+func parseEpochSeconds(s string) (time.Time, error) {
+	secs, frac, _ := strings.Cut(s, ".")
+	sec, err := strconv.ParseInt(secs, 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	var nsec int64
+	if frac != "" {
+		if len(frac) > 9 {
+			frac = frac[:9]
+		}
+		nsec, err = strconv.ParseInt(frac+strings.Repeat("0", 9-len(frac)), 10, 64)
+		if err != nil {
+			return time.Time{}, err
+		}
+	}
+	return time.Unix(sec, nsec), nil
+}
+
+// WriteSplunkHECResponse writes the response to a successful request to the HTTP Event Collector.
+func WriteSplunkHECResponse(w http.ResponseWriter) {
+	w.Header().Set(contentType, applicationJSON)
+	w.WriteHeader(http.StatusOK)
+	_, _ = io.WriteString(w, `{"text":"Success","code":0}`)
+}
+
+// SplunkHECHealthHandler responds to the health checks of the HTTP Event Collector.
+func SplunkHECHealthHandler(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set(contentType, applicationJSON)
+	w.WriteHeader(http.StatusOK)
+	_, _ = io.WriteString(w, `{"text":"HEC is healthy","code":17}`)
+}
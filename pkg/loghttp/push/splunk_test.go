@@ -0,0 +1,92 @@
+package push
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/push"
+)
+
+func TestParseSplunkHECEventRequest(t *testing.T) {
+	body := `{"time":1704067200.123456,"host":"web-1","source":"/var/log/app.log","sourcetype":"app","index":"apps","event":"first","fields":{"region":"eu","pid":42}}
+{"time":"1704067201","host":"web-1","sourcetype":"app","index":"apps","event":{"msg":"second", "level": "info"}}
+{"event":"third"}`
+	request := httptest.NewRequest("POST", "/services/collector/event", strings.NewReader(body))
+
+	tracker := NewMockTracker()
+	req, stats, err := ParseSplunkHECEventRequest("fake", request, EmptyTenantsRetention{}, EmptyLimits{}, tracker)
+	require.NoError(t, err)
+
+	require.Len(t, req.Streams, 2)
+	require.Equal(t, logproto.Stream{
+		Labels: `{host="web-1", index="apps", sourcetype="app"}`,
+		Entries: []push.Entry{
+			{
+				Timestamp: time.Unix(1704067200, 123456000),
+				Line:      "first",
+				StructuredMetadata: push.LabelsAdapter{
+					{Name: "source", Value: "/var/log/app.log"},
+					{Name: "pid", Value: "42"},
+					{Name: "region", Value: "eu"},
+				},
+			},
+			{
+				Timestamp: time.Unix(1704067201, 0),
+				Line:      `{"msg":"second","level":"info"}`,
+			},
+		},
+	}, req.Streams[0])
+	require.Equal(t, `{index="main"}`, req.Streams[1].Labels)
+	require.Equal(t, "third", req.Streams[1].Entries[0].Line)
+	require.Equal(t, int64(3), stats.NumLines)
+	require.Equal(t, float64(len(`first{"msg":"second","level":"info"}third`)+len("source/var/log/app.logpid42regioneu")), tracker.Total())
+}
+
+func TestParseSplunkHECEventRequest_Invalid(t *testing.T) {
+	for name, body := range map[string]string{
+		"invalid json":  `{"event":`,
+		"missing event": `{"host":"web-1"}`,
+		"blank event":   `{"event":" "}`,
+		"invalid time":  `{"time":"yesterday","event":"a"}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			request := httptest.NewRequest("POST", "/services/collector/event", strings.NewReader(body))
+			_, _, err := ParseSplunkHECEventRequest("fake", request, EmptyTenantsRetention{}, EmptyLimits{}, nil)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestParseSplunkHECRawRequest(t *testing.T) {
+	request := httptest.NewRequest("POST", "/services/collector/raw?sourcetype=syslog&host=web-1&source=udp:514", strings.NewReader("first\r\n\nsecond"))
+
+	req, stats, err := ParseSplunkHECRawRequest("fake", request, EmptyTenantsRetention{}, EmptyLimits{}, nil)
+	require.NoError(t, err)
+	require.Len(t, req.Streams, 1)
+	require.Equal(t, `{host="web-1", index="main", sourcetype="syslog"}`, req.Streams[0].Labels)
+	require.Len(t, req.Streams[0].Entries, 2)
+	require.Equal(t, "first", req.Streams[0].Entries[0].Line)
+	require.Equal(t, "second", req.Streams[0].Entries[1].Line)
+	require.Equal(t, push.LabelsAdapter{{Name: "source", Value: "udp:514"}}, req.Streams[0].Entries[1].StructuredMetadata)
+	require.Equal(t, int64(2), stats.NumLines)
+}
+
+func TestParseEpochSeconds(t *testing.T) {
+	for s, expected := range map[string]time.Time{
+		"1704067200":             time.Unix(1704067200, 0),
+		"1704067200.5":           time.Unix(1704067200, 500000000),
+		"1704067200.123456789":   time.Unix(1704067200, 123456789),
+		"1704067200.12345678999": time.Unix(1704067200, 123456789),
+	} {
+		ts, err := parseEpochSeconds(s)
+		require.NoError(t, err)
+		require.Equal(t, expected, ts, s)
+	}
+	_, err := parseEpochSeconds("1704067200.a")
+	require.Error(t, err)
+}
@@ -445,3 +445,80 @@ func (m *ShardsRequest) LogToSpan(sp opentracing.Span) {
 	}
 	sp.LogFields(fields...)
 }
+
+// Satisfy definitions.Request for QueryPatternsRequest
+func (m *QueryPatternsRequest) GetCachingOptions() (res definitions.CachingOptions) { return }
+
+func (m *QueryPatternsRequest) GetStart() time.Time {
+	return time.Unix(0, m.Start.UnixNano())
+}
+
+func (m *QueryPatternsRequest) GetEnd() time.Time {
+	return time.Unix(0, m.End.UnixNano())
+}
+
+func (m *QueryPatternsRequest) WithStartEnd(start, end time.Time) definitions.Request {
+	clone := *m
+	clone.Start = model.TimeFromUnixNano(start.UnixNano())
+	clone.End = model.TimeFromUnixNano(end.UnixNano())
+	return &clone
+}
+
+func (m *QueryPatternsRequest) WithQuery(query string) definitions.Request {
+	clone := *m
+	clone.Query = query
+	return &clone
+}
+
+func (m *QueryPatternsRequest) LogToSpan(sp opentracing.Span) {
+	fields := []otlog.Field{
+		otlog.String("query", m.GetQuery()),
+		otlog.String("start", m.Start.Time().String()),
+		otlog.String("end", m.End.Time().String()),
+		otlog.String("step", time.Duration(m.Step*int64(time.Millisecond)).String()),
+	}
+	sp.LogFields(fields...)
+}
+
+// Satisfy definitions.Request for DetectedFieldsRequest
+func (m *DetectedFieldsRequest) GetCachingOptions() (res definitions.CachingOptions) { return }
+
+func (m *DetectedFieldsRequest) GetStart() time.Time {
+	return time.Unix(0, m.Start.UnixNano())
+}
+
+func (m *DetectedFieldsRequest) GetEnd() time.Time {
+	return time.Unix(0, m.End.UnixNano())
+}
+
+// GetStep returns 0, detected fields are not computed per step.
+func (m *DetectedFieldsRequest) GetStep() int64 { return 0 }
+
+func (m *DetectedFieldsRequest) WithStartEnd(start, end time.Time) definitions.Request {
+	clone := *m
+	clone.Start = model.TimeFromUnixNano(start.UnixNano())
+	clone.End = model.TimeFromUnixNano(end.UnixNano())
+	return &clone
+}
+
+// WithStartEndForCache implements resultscache.Request.
+func (m *DetectedFieldsRequest) WithStartEndForCache(start, end time.Time) resultscache.Request {
+	return m.WithStartEnd(start, end).(resultscache.Request)
+}
+
+func (m *DetectedFieldsRequest) WithQuery(query string) definitions.Request {
+	clone := *m
+	clone.Query = query
+	return &clone
+}
+
+func (m *DetectedFieldsRequest) LogToSpan(sp opentracing.Span) {
+	fields := []otlog.Field{
+		otlog.String("query", m.GetQuery()),
+		otlog.String("start", m.Start.Time().String()),
+		otlog.String("end", m.End.Time().String()),
+		otlog.Uint32("line_limit", m.LineLimit),
+		otlog.Uint32("field_limit", m.FieldLimit),
+	}
+	sp.LogFields(fields...)
+}
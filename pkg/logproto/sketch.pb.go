@@ -171,6 +171,152 @@ func (m *QuantileSketchSample) GetMetric() []*LabelPair {
 	return nil
 }
 
+type CountDistinctSketchMatrix struct {
+	Values []*CountDistinctSketchVector `protobuf:"bytes,1,rep,name=values,proto3" json:"values,omitempty"`
+}
+
+func (m *CountDistinctSketchMatrix) Reset()      { *m = CountDistinctSketchMatrix{} }
+func (*CountDistinctSketchMatrix) ProtoMessage() {}
+func (*CountDistinctSketchMatrix) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7f9fd40e59b87ff3, []int{3}
+}
+func (m *CountDistinctSketchMatrix) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *CountDistinctSketchMatrix) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_CountDistinctSketchMatrix.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *CountDistinctSketchMatrix) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CountDistinctSketchMatrix.Merge(m, src)
+}
+func (m *CountDistinctSketchMatrix) XXX_Size() int {
+	return m.Size()
+}
+func (m *CountDistinctSketchMatrix) XXX_DiscardUnknown() {
+	xxx_messageInfo_CountDistinctSketchMatrix.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CountDistinctSketchMatrix proto.InternalMessageInfo
+
+func (m *CountDistinctSketchMatrix) GetValues() []*CountDistinctSketchVector {
+	if m != nil {
+		return m.Values
+	}
+	return nil
+}
+
+type CountDistinctSketchVector struct {
+	Samples []*CountDistinctSketchSample `protobuf:"bytes,1,rep,name=samples,proto3" json:"samples,omitempty"`
+}
+
+func (m *CountDistinctSketchVector) Reset()      { *m = CountDistinctSketchVector{} }
+func (*CountDistinctSketchVector) ProtoMessage() {}
+func (*CountDistinctSketchVector) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7f9fd40e59b87ff3, []int{4}
+}
+func (m *CountDistinctSketchVector) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *CountDistinctSketchVector) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_CountDistinctSketchVector.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *CountDistinctSketchVector) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CountDistinctSketchVector.Merge(m, src)
+}
+func (m *CountDistinctSketchVector) XXX_Size() int {
+	return m.Size()
+}
+func (m *CountDistinctSketchVector) XXX_DiscardUnknown() {
+	xxx_messageInfo_CountDistinctSketchVector.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CountDistinctSketchVector proto.InternalMessageInfo
+
+func (m *CountDistinctSketchVector) GetSamples() []*CountDistinctSketchSample {
+	if m != nil {
+		return m.Samples
+	}
+	return nil
+}
+
+type CountDistinctSketchSample struct {
+	// hyperloglog is the binary encoding of a HyperLogLog sketch.
+	Hyperloglog []byte       `protobuf:"bytes,1,opt,name=hyperloglog,proto3" json:"hyperloglog,omitempty"`
+	TimestampMs int64        `protobuf:"varint,2,opt,name=timestamp_ms,json=timestampMs,proto3" json:"timestamp_ms,omitempty"`
+	Metric      []*LabelPair `protobuf:"bytes,3,rep,name=metric,proto3" json:"metric,omitempty"`
+}
+
+func (m *CountDistinctSketchSample) Reset()      { *m = CountDistinctSketchSample{} }
+func (*CountDistinctSketchSample) ProtoMessage() {}
+func (*CountDistinctSketchSample) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7f9fd40e59b87ff3, []int{5}
+}
+func (m *CountDistinctSketchSample) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *CountDistinctSketchSample) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_CountDistinctSketchSample.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *CountDistinctSketchSample) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CountDistinctSketchSample.Merge(m, src)
+}
+func (m *CountDistinctSketchSample) XXX_Size() int {
+	return m.Size()
+}
+func (m *CountDistinctSketchSample) XXX_DiscardUnknown() {
+	xxx_messageInfo_CountDistinctSketchSample.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CountDistinctSketchSample proto.InternalMessageInfo
+
+func (m *CountDistinctSketchSample) GetHyperloglog() []byte {
+	if m != nil {
+		return m.Hyperloglog
+	}
+	return nil
+}
+
+func (m *CountDistinctSketchSample) GetTimestampMs() int64 {
+	if m != nil {
+		return m.TimestampMs
+	}
+	return 0
+}
+
+func (m *CountDistinctSketchSample) GetMetric() []*LabelPair {
+	if m != nil {
+		return m.Metric
+	}
+	return nil
+}
+
 type QuantileSketch struct {
 	// Types that are valid to be assigned to Sketch:
 	//	*QuantileSketch_Tdigest
@@ -181,7 +327,7 @@ type QuantileSketch struct {
 func (m *QuantileSketch) Reset()      { *m = QuantileSketch{} }
 func (*QuantileSketch) ProtoMessage() {}
 func (*QuantileSketch) Descriptor() ([]byte, []int) {
-	return fileDescriptor_7f9fd40e59b87ff3, []int{3}
+	return fileDescriptor_7f9fd40e59b87ff3, []int{6}
 }
 func (m *QuantileSketch) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -267,7 +413,7 @@ type TDigest struct {
 func (m *TDigest) Reset()      { *m = TDigest{} }
 func (*TDigest) ProtoMessage() {}
 func (*TDigest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_7f9fd40e59b87ff3, []int{4}
+	return fileDescriptor_7f9fd40e59b87ff3, []int{7}
 }
 func (m *TDigest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -332,7 +478,7 @@ type TDigest_Centroid struct {
 func (m *TDigest_Centroid) Reset()      { *m = TDigest_Centroid{} }
 func (*TDigest_Centroid) ProtoMessage() {}
 func (*TDigest_Centroid) Descriptor() ([]byte, []int) {
-	return fileDescriptor_7f9fd40e59b87ff3, []int{4, 0}
+	return fileDescriptor_7f9fd40e59b87ff3, []int{7, 0}
 }
 func (m *TDigest_Centroid) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -385,7 +531,7 @@ type CountMinSketch struct {
 func (m *CountMinSketch) Reset()      { *m = CountMinSketch{} }
 func (*CountMinSketch) ProtoMessage() {}
 func (*CountMinSketch) Descriptor() ([]byte, []int) {
-	return fileDescriptor_7f9fd40e59b87ff3, []int{5}
+	return fileDescriptor_7f9fd40e59b87ff3, []int{8}
 }
 func (m *CountMinSketch) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -444,7 +590,7 @@ type TopK struct {
 func (m *TopK) Reset()      { *m = TopK{} }
 func (*TopK) ProtoMessage() {}
 func (*TopK) Descriptor() ([]byte, []int) {
-	return fileDescriptor_7f9fd40e59b87ff3, []int{6}
+	return fileDescriptor_7f9fd40e59b87ff3, []int{9}
 }
 func (m *TopK) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -502,7 +648,7 @@ type TopK_Pair struct {
 func (m *TopK_Pair) Reset()      { *m = TopK_Pair{} }
 func (*TopK_Pair) ProtoMessage() {}
 func (*TopK_Pair) Descriptor() ([]byte, []int) {
-	return fileDescriptor_7f9fd40e59b87ff3, []int{6, 0}
+	return fileDescriptor_7f9fd40e59b87ff3, []int{9, 0}
 }
 func (m *TopK_Pair) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -552,7 +698,7 @@ type TopKMatrix struct {
 func (m *TopKMatrix) Reset()      { *m = TopKMatrix{} }
 func (*TopKMatrix) ProtoMessage() {}
 func (*TopKMatrix) Descriptor() ([]byte, []int) {
-	return fileDescriptor_7f9fd40e59b87ff3, []int{7}
+	return fileDescriptor_7f9fd40e59b87ff3, []int{10}
 }
 func (m *TopKMatrix) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -596,7 +742,7 @@ type TopKMatrix_Vector struct {
 func (m *TopKMatrix_Vector) Reset()      { *m = TopKMatrix_Vector{} }
 func (*TopKMatrix_Vector) ProtoMessage() {}
 func (*TopKMatrix_Vector) Descriptor() ([]byte, []int) {
-	return fileDescriptor_7f9fd40e59b87ff3, []int{7, 0}
+	return fileDescriptor_7f9fd40e59b87ff3, []int{10, 0}
 }
 func (m *TopKMatrix_Vector) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -643,6 +789,9 @@ func init() {
 	proto.RegisterType((*QuantileSketchMatrix)(nil), "logproto.QuantileSketchMatrix")
 	proto.RegisterType((*QuantileSketchVector)(nil), "logproto.QuantileSketchVector")
 	proto.RegisterType((*QuantileSketchSample)(nil), "logproto.QuantileSketchSample")
+	proto.RegisterType((*CountDistinctSketchMatrix)(nil), "logproto.CountDistinctSketchMatrix")
+	proto.RegisterType((*CountDistinctSketchVector)(nil), "logproto.CountDistinctSketchVector")
+	proto.RegisterType((*CountDistinctSketchSample)(nil), "logproto.CountDistinctSketchSample")
 	proto.RegisterType((*QuantileSketch)(nil), "logproto.QuantileSketch")
 	proto.RegisterType((*TDigest)(nil), "logproto.TDigest")
 	proto.RegisterType((*TDigest_Centroid)(nil), "logproto.TDigest.Centroid")
@@ -656,46 +805,50 @@ func init() {
 func init() { proto.RegisterFile("pkg/logproto/sketch.proto", fileDescriptor_7f9fd40e59b87ff3) }
 
 var fileDescriptor_7f9fd40e59b87ff3 = []byte{
-	// 623 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x54, 0x41, 0x4f, 0xd4, 0x4e,
-	0x14, 0xef, 0xfc, 0x77, 0xff, 0xcb, 0xf2, 0x16, 0x88, 0x8e, 0xc4, 0xd4, 0xc5, 0x4c, 0xd6, 0xc6,
-	0x28, 0xd1, 0xb8, 0x9b, 0x40, 0x42, 0x38, 0x83, 0x07, 0x12, 0x45, 0x71, 0x20, 0xc6, 0x70, 0x31,
-	0x43, 0x3b, 0x74, 0x27, 0xdb, 0x76, 0x9a, 0xce, 0x2c, 0xe0, 0xcd, 0x4f, 0x60, 0x8c, 0x9f, 0xc2,
-	0xab, 0x1f, 0xc1, 0x9b, 0x47, 0x8e, 0x1c, 0xa5, 0x5c, 0x3c, 0xf2, 0x11, 0xcc, 0x4c, 0xdb, 0x85,
-	0x2e, 0x31, 0x7a, 0xda, 0x79, 0xbf, 0xf7, 0x7b, 0xbf, 0xf9, 0xcd, 0x7b, 0x7d, 0x0b, 0xf7, 0xd2,
-	0x51, 0x38, 0x88, 0x64, 0x98, 0x66, 0x52, 0xcb, 0x81, 0x1a, 0x71, 0xed, 0x0f, 0xfb, 0x36, 0xc0,
-	0xed, 0x0a, 0xee, 0x2e, 0xd5, 0x48, 0xd5, 0xa1, 0xa0, 0x79, 0xaf, 0x60, 0xf1, 0xcd, 0x98, 0x25,
-	0x5a, 0x44, 0x7c, 0xd7, 0x96, 0x6f, 0x33, 0x9d, 0x89, 0x13, 0xbc, 0x06, 0xad, 0x23, 0x16, 0x8d,
-	0xb9, 0x72, 0x51, 0xaf, 0xb1, 0xdc, 0x59, 0x21, 0xfd, 0x49, 0x61, 0x9d, 0xff, 0x96, 0xfb, 0x5a,
-	0x66, 0xb4, 0x64, 0x7b, 0x3b, 0xd3, 0x7a, 0x45, 0x1e, 0xaf, 0xc3, 0x8c, 0x62, 0x71, 0x1a, 0xfd,
-	0x5d, 0x70, 0xd7, 0xd2, 0x68, 0x45, 0xf7, 0x3e, 0xa1, 0x69, 0xc9, 0x82, 0x81, 0x1f, 0x01, 0x3a,
-	0x74, 0x51, 0x0f, 0x2d, 0x77, 0x56, 0xdc, 0x3f, 0x89, 0x51, 0x74, 0x88, 0x1f, 0xc0, 0x9c, 0x16,
-	0x31, 0x57, 0x9a, 0xc5, 0xe9, 0xfb, 0x58, 0xb9, 0xff, 0xf5, 0xd0, 0x72, 0x83, 0x76, 0x26, 0xd8,
-	0xb6, 0xc2, 0x4f, 0xa1, 0x15, 0x73, 0x9d, 0x09, 0xdf, 0x6d, 0x58, 0x73, 0x77, 0xae, 0xf4, 0x5e,
-	0xb2, 0x03, 0x1e, 0xed, 0x30, 0x91, 0xd1, 0x92, 0xe2, 0x85, 0xb0, 0x50, 0xbf, 0x04, 0x3f, 0x83,
-	0x19, 0x1d, 0x88, 0x90, 0x2b, 0x5d, 0xfa, 0xb9, 0x7d, 0x55, 0xbf, 0xf7, 0xdc, 0x26, 0xb6, 0x1c,
-	0x5a, 0x71, 0xf0, 0x7d, 0x68, 0x07, 0x41, 0x31, 0x2c, 0x6b, 0x66, 0x6e, 0xcb, 0xa1, 0x13, 0x64,
-	0xa3, 0x0d, 0xad, 0xe2, 0xe4, 0x7d, 0x47, 0x30, 0x53, 0x96, 0xe3, 0x5b, 0xd0, 0x88, 0x45, 0x62,
-	0xe5, 0x11, 0x35, 0x47, 0x8b, 0xb0, 0x13, 0x2b, 0x60, 0x10, 0x76, 0x82, 0x7b, 0xd0, 0xf1, 0x65,
-	0x9c, 0x66, 0x5c, 0x29, 0x21, 0x13, 0xb7, 0x61, 0x33, 0xd7, 0x21, 0xbc, 0x0e, 0xb3, 0x69, 0x26,
-	0x7d, 0xae, 0x14, 0x0f, 0xdc, 0xa6, 0x7d, 0x6a, 0xf7, 0x86, 0xd5, 0xfe, 0x26, 0x4f, 0x74, 0x26,
-	0x45, 0x40, 0xaf, 0xc8, 0xdd, 0x35, 0x68, 0x57, 0x30, 0xc6, 0xd0, 0x8c, 0x39, 0xab, 0xcc, 0xd8,
-	0x33, 0xbe, 0x0b, 0xad, 0x63, 0x2e, 0xc2, 0xa1, 0x2e, 0x0d, 0x95, 0x91, 0xf7, 0x0e, 0x16, 0x36,
-	0xe5, 0x38, 0xd1, 0xdb, 0x22, 0x29, 0x9b, 0xb5, 0x08, 0xff, 0x07, 0x3c, 0xd5, 0x43, 0x5b, 0x3e,
-	0x4f, 0x8b, 0xc0, 0xa0, 0xc7, 0x22, 0xd0, 0x45, 0x43, 0xe6, 0x69, 0x11, 0xe0, 0x2e, 0xb4, 0x7d,
-	0x53, 0xcd, 0x33, 0x65, 0x27, 0x33, 0x4f, 0x27, 0xb1, 0xf7, 0x0d, 0x41, 0x73, 0x4f, 0xa6, 0x2f,
-	0xf0, 0x13, 0x68, 0xf8, 0xb1, 0xba, 0xf9, 0x25, 0xd4, 0xef, 0xa5, 0x86, 0x84, 0x1f, 0x43, 0x33,
-	0x12, 0xca, 0x98, 0x9c, 0x1a, 0xb3, 0x51, 0xea, 0xdb, 0x31, 0x5b, 0x82, 0xe9, 0xe5, 0xf0, 0x43,
-	0xca, 0xb3, 0x48, 0x86, 0x91, 0x0c, 0x6d, 0x2f, 0xe7, 0xe8, 0x75, 0xa8, 0xbb, 0x02, 0x4d, 0xc3,
-	0x37, 0xce, 0xf9, 0x11, 0x4f, 0x8a, 0xd1, 0xcf, 0xd2, 0x22, 0x30, 0xa8, 0x75, 0x5a, 0xbd, 0xc7,
-	0x06, 0xde, 0x17, 0x04, 0x60, 0x6e, 0x2a, 0x97, 0x6c, 0x75, 0x6a, 0xc9, 0x96, 0xea, 0x7e, 0x0a,
-	0x56, 0xbf, 0xbe, 0x61, 0xdd, 0xd7, 0xd0, 0x2a, 0x77, 0xca, 0x83, 0xa6, 0x96, 0xe9, 0xa8, 0x7c,
-	0xf9, 0x42, 0xbd, 0x98, 0xda, 0xdc, 0x3f, 0x7c, 0xfc, 0x1b, 0xfb, 0xa7, 0xe7, 0xc4, 0x39, 0x3b,
-	0x27, 0xce, 0xe5, 0x39, 0x41, 0x1f, 0x73, 0x82, 0xbe, 0xe6, 0x04, 0xfd, 0xc8, 0x09, 0x3a, 0xcd,
-	0x09, 0xfa, 0x99, 0x13, 0xf4, 0x2b, 0x27, 0xce, 0x65, 0x4e, 0xd0, 0xe7, 0x0b, 0xe2, 0x9c, 0x5e,
-	0x10, 0xe7, 0xec, 0x82, 0x38, 0xfb, 0x0f, 0x43, 0xa1, 0x87, 0xe3, 0x83, 0xbe, 0x2f, 0xe3, 0x41,
-	0x98, 0xb1, 0x43, 0x96, 0xb0, 0x41, 0x24, 0x47, 0x62, 0x70, 0xfd, 0xdf, 0xe6, 0xa0, 0x65, 0x7f,
-	0x56, 0x7f, 0x07, 0x00, 0x00, 0xff, 0xff, 0x24, 0x9c, 0x74, 0xb7, 0xa9, 0x04, 0x00, 0x00,
+	// 681 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x54, 0x4f, 0x4f, 0xd4, 0x4c,
+	0x1c, 0xee, 0xbc, 0xbb, 0xef, 0xb2, 0xfc, 0x16, 0xc8, 0xfb, 0x8e, 0xc4, 0x94, 0xc5, 0x4c, 0xd6,
+	0x6a, 0x94, 0x68, 0xdc, 0x4d, 0x20, 0x21, 0x24, 0xc6, 0x0b, 0x70, 0x20, 0x51, 0x14, 0x07, 0x62,
+	0x08, 0x17, 0x53, 0xda, 0xa1, 0x3b, 0xd9, 0xb6, 0xd3, 0x74, 0x66, 0x01, 0x6f, 0x7e, 0x01, 0x8d,
+	0xf1, 0x53, 0x78, 0xf5, 0x23, 0x78, 0xf3, 0xc8, 0x91, 0xa3, 0x94, 0x8b, 0x47, 0x3e, 0x82, 0xe9,
+	0xb4, 0xdd, 0xa5, 0x05, 0xff, 0x1c, 0x3c, 0xed, 0xfc, 0x9e, 0x79, 0x7e, 0x4f, 0x9f, 0x79, 0x7e,
+	0x33, 0x0b, 0x73, 0xd1, 0xc0, 0xeb, 0xf9, 0xc2, 0x8b, 0x62, 0xa1, 0x44, 0x4f, 0x0e, 0x98, 0x72,
+	0xfa, 0x5d, 0x5d, 0xe0, 0x66, 0x01, 0xb7, 0xe7, 0x4b, 0xa4, 0x62, 0x91, 0xd1, 0xac, 0xe7, 0x30,
+	0xfb, 0x72, 0x68, 0x87, 0x8a, 0xfb, 0x6c, 0x5b, 0xb7, 0x6f, 0xda, 0x2a, 0xe6, 0xc7, 0x78, 0x19,
+	0x1a, 0x87, 0xb6, 0x3f, 0x64, 0xd2, 0x44, 0x9d, 0xda, 0x42, 0x6b, 0x91, 0x74, 0x47, 0x8d, 0x65,
+	0xfe, 0x2b, 0xe6, 0x28, 0x11, 0xd3, 0x9c, 0x6d, 0x6d, 0x55, 0xf5, 0xb2, 0x7d, 0xbc, 0x02, 0x13,
+	0xd2, 0x0e, 0x22, 0xff, 0xf7, 0x82, 0xdb, 0x9a, 0x46, 0x0b, 0xba, 0xf5, 0x1e, 0x55, 0x25, 0x33,
+	0x06, 0xbe, 0x07, 0xe8, 0xc0, 0x44, 0x1d, 0xb4, 0xd0, 0x5a, 0x34, 0x7f, 0x26, 0x46, 0xd1, 0x01,
+	0xbe, 0x0d, 0x53, 0x8a, 0x07, 0x4c, 0x2a, 0x3b, 0x88, 0x5e, 0x07, 0xd2, 0xfc, 0xa7, 0x83, 0x16,
+	0x6a, 0xb4, 0x35, 0xc2, 0x36, 0x25, 0x7e, 0x08, 0x8d, 0x80, 0xa9, 0x98, 0x3b, 0x66, 0x4d, 0x9b,
+	0xbb, 0x31, 0xd6, 0x7b, 0x66, 0xef, 0x33, 0x7f, 0xcb, 0xe6, 0x31, 0xcd, 0x29, 0xd6, 0x2e, 0xcc,
+	0xad, 0x89, 0x61, 0xa8, 0xd6, 0xb9, 0x54, 0x3c, 0x74, 0x54, 0x29, 0xb7, 0xc7, 0x95, 0xdc, 0xee,
+	0x8c, 0x95, 0xae, 0x69, 0xaa, 0x84, 0xb7, 0x77, 0xad, 0x72, 0x9e, 0xe0, 0x93, 0x6a, 0x82, 0xbf,
+	0x96, 0xae, 0xc6, 0xf8, 0x0e, 0x5d, 0x2b, 0x9e, 0x67, 0xd9, 0x81, 0x56, 0xff, 0x4d, 0xc4, 0x62,
+	0x5f, 0x78, 0xbe, 0xf0, 0x74, 0xaa, 0x53, 0xf4, 0x32, 0xf4, 0xd7, 0x53, 0xf4, 0x60, 0xa6, 0x3c,
+	0x2a, 0xfc, 0x08, 0x26, 0x94, 0xcb, 0x3d, 0x26, 0x55, 0x3e, 0xd5, 0xff, 0xc7, 0xfd, 0x3b, 0xeb,
+	0x7a, 0x63, 0xc3, 0xa0, 0x05, 0x07, 0xdf, 0x82, 0xa6, 0xeb, 0x66, 0x57, 0x5e, 0x9b, 0x99, 0xda,
+	0x30, 0xe8, 0x08, 0x59, 0x6d, 0x42, 0x23, 0x5b, 0x59, 0x5f, 0x10, 0x4c, 0xe4, 0xed, 0xf8, 0x3f,
+	0xa8, 0x05, 0x3c, 0xd4, 0xf2, 0x88, 0xa6, 0x4b, 0x8d, 0xd8, 0xc7, 0x5a, 0x20, 0x45, 0xec, 0xe3,
+	0x34, 0x0a, 0x47, 0x04, 0x51, 0xcc, 0xa4, 0xe4, 0x22, 0x34, 0x6b, 0x7a, 0xe7, 0x32, 0x84, 0x57,
+	0x60, 0x32, 0x8a, 0x85, 0xc3, 0xa4, 0x64, 0xae, 0x59, 0xd7, 0x47, 0x6d, 0x5f, 0xb1, 0xda, 0x5d,
+	0x63, 0xa1, 0x8a, 0x05, 0x77, 0xe9, 0x98, 0xdc, 0x5e, 0x86, 0x66, 0x01, 0x63, 0x0c, 0xf5, 0x80,
+	0xd9, 0x85, 0x19, 0xbd, 0xc6, 0x37, 0xa1, 0x71, 0xc4, 0xb8, 0xd7, 0x57, 0xb9, 0xa1, 0xbc, 0xb2,
+	0x76, 0x61, 0x46, 0xcf, 0x6e, 0x93, 0x87, 0x79, 0x58, 0xb3, 0xf0, 0xaf, 0xcb, 0x22, 0xd5, 0xd7,
+	0xed, 0xd3, 0x34, 0x2b, 0x52, 0xf4, 0x88, 0xbb, 0x2a, 0x0b, 0x64, 0x9a, 0x66, 0x05, 0x6e, 0x43,
+	0xd3, 0x49, 0xbb, 0x59, 0x2c, 0xf5, 0x64, 0xa6, 0xe9, 0xa8, 0xb6, 0x3e, 0x23, 0xa8, 0xef, 0x88,
+	0xe8, 0x29, 0x7e, 0x00, 0x35, 0x27, 0x90, 0x57, 0xdf, 0x53, 0xf9, 0xbb, 0x34, 0x25, 0xe1, 0xfb,
+	0x50, 0xf7, 0xb9, 0x4c, 0x4d, 0x56, 0xc6, 0x9c, 0x2a, 0x75, 0xf5, 0x98, 0x35, 0xa1, 0x7a, 0xad,
+	0x6a, 0x57, 0xae, 0x55, 0x7b, 0x11, 0xea, 0x29, 0x3f, 0x75, 0xce, 0x0e, 0x59, 0x98, 0x8d, 0x7e,
+	0x92, 0x66, 0x45, 0x8a, 0x6a, 0xa7, 0xc5, 0x79, 0x74, 0x61, 0x7d, 0x44, 0x00, 0xe9, 0x97, 0xf2,
+	0x27, 0xb7, 0x54, 0x79, 0x72, 0xf3, 0x65, 0x3f, 0x19, 0xab, 0x5b, 0x7e, 0x6a, 0xed, 0x17, 0xd0,
+	0xc8, 0xdf, 0x95, 0x05, 0x75, 0x25, 0xa2, 0x41, 0x7e, 0xf2, 0x99, 0x72, 0x33, 0xd5, 0x7b, 0x7f,
+	0x70, 0xf9, 0x57, 0xf7, 0x4e, 0xce, 0x88, 0x71, 0x7a, 0x46, 0x8c, 0x8b, 0x33, 0x82, 0xde, 0x26,
+	0x04, 0x7d, 0x4a, 0x08, 0xfa, 0x9a, 0x10, 0x74, 0x92, 0x10, 0xf4, 0x2d, 0x21, 0xe8, 0x7b, 0x42,
+	0x8c, 0x8b, 0x84, 0xa0, 0x0f, 0xe7, 0xc4, 0x38, 0x39, 0x27, 0xc6, 0xe9, 0x39, 0x31, 0xf6, 0xee,
+	0x7a, 0x5c, 0xf5, 0x87, 0xfb, 0x5d, 0x47, 0x04, 0x3d, 0x2f, 0xb6, 0x0f, 0xec, 0xd0, 0xee, 0xf9,
+	0x62, 0xc0, 0x7b, 0x97, 0xff, 0xb3, 0xf7, 0x1b, 0xfa, 0x67, 0xe9, 0x47, 0x00, 0x00, 0x00, 0xff,
+	0xff, 0xcf, 0x0c, 0xb5, 0x2a, 0xef, 0x05, 0x00, 0x00,
 }
 
 func (this *QuantileSketchMatrix) Equal(that interface{}) bool {
@@ -791,6 +944,99 @@ func (this *QuantileSketchSample) Equal(that interface{}) bool {
 	}
 	return true
 }
+func (this *CountDistinctSketchMatrix) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*CountDistinctSketchMatrix)
+	if !ok {
+		that2, ok := that.(CountDistinctSketchMatrix)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if len(this.Values) != len(that1.Values) {
+		return false
+	}
+	for i := range this.Values {
+		if !this.Values[i].Equal(that1.Values[i]) {
+			return false
+		}
+	}
+	return true
+}
+func (this *CountDistinctSketchVector) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*CountDistinctSketchVector)
+	if !ok {
+		that2, ok := that.(CountDistinctSketchVector)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if len(this.Samples) != len(that1.Samples) {
+		return false
+	}
+	for i := range this.Samples {
+		if !this.Samples[i].Equal(that1.Samples[i]) {
+			return false
+		}
+	}
+	return true
+}
+func (this *CountDistinctSketchSample) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*CountDistinctSketchSample)
+	if !ok {
+		that2, ok := that.(CountDistinctSketchSample)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if !bytes.Equal(this.Hyperloglog, that1.Hyperloglog) {
+		return false
+	}
+	if this.TimestampMs != that1.TimestampMs {
+		return false
+	}
+	if len(this.Metric) != len(that1.Metric) {
+		return false
+	}
+	for i := range this.Metric {
+		if !this.Metric[i].Equal(that1.Metric[i]) {
+			return false
+		}
+	}
+	return true
+}
 func (this *QuantileSketch) Equal(that interface{}) bool {
 	if that == nil {
 		return this == nil
@@ -1127,6 +1373,44 @@ func (this *QuantileSketchSample) GoString() string {
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
+func (this *CountDistinctSketchMatrix) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 5)
+	s = append(s, "&logproto.CountDistinctSketchMatrix{")
+	if this.Values != nil {
+		s = append(s, "Values: "+fmt.Sprintf("%#v", this.Values)+",\n")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *CountDistinctSketchVector) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 5)
+	s = append(s, "&logproto.CountDistinctSketchVector{")
+	if this.Samples != nil {
+		s = append(s, "Samples: "+fmt.Sprintf("%#v", this.Samples)+",\n")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *CountDistinctSketchSample) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 7)
+	s = append(s, "&logproto.CountDistinctSketchSample{")
+	s = append(s, "Hyperloglog: "+fmt.Sprintf("%#v", this.Hyperloglog)+",\n")
+	s = append(s, "TimestampMs: "+fmt.Sprintf("%#v", this.TimestampMs)+",\n")
+	if this.Metric != nil {
+		s = append(s, "Metric: "+fmt.Sprintf("%#v", this.Metric)+",\n")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
 func (this *QuantileSketch) GoString() string {
 	if this == nil {
 		return "nil"
@@ -1381,7 +1665,7 @@ func (m *QuantileSketchSample) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
-func (m *QuantileSketch) Marshal() (dAtA []byte, err error) {
+func (m *CountDistinctSketchMatrix) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -1391,35 +1675,158 @@ func (m *QuantileSketch) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *QuantileSketch) MarshalTo(dAtA []byte) (int, error) {
+func (m *CountDistinctSketchMatrix) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *QuantileSketch) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *CountDistinctSketchMatrix) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if m.Sketch != nil {
-		{
-			size := m.Sketch.Size()
-			i -= size
-			if _, err := m.Sketch.MarshalTo(dAtA[i:]); err != nil {
-				return 0, err
+	if len(m.Values) > 0 {
+		for iNdEx := len(m.Values) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Values[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintSketch(dAtA, i, uint64(size))
 			}
+			i--
+			dAtA[i] = 0xa
 		}
 	}
 	return len(dAtA) - i, nil
 }
 
-func (m *QuantileSketch_Tdigest) MarshalTo(dAtA []byte) (int, error) {
-	return m.MarshalToSizedBuffer(dAtA[:m.Size()])
+func (m *CountDistinctSketchVector) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
 }
 
-func (m *QuantileSketch_Tdigest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *CountDistinctSketchVector) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *CountDistinctSketchVector) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
-	if m.Tdigest != nil {
+	_ = i
+	var l int
+	_ = l
+	if len(m.Samples) > 0 {
+		for iNdEx := len(m.Samples) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Samples[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintSketch(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *CountDistinctSketchSample) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *CountDistinctSketchSample) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *CountDistinctSketchSample) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Metric) > 0 {
+		for iNdEx := len(m.Metric) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Metric[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintSketch(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
+	if m.TimestampMs != 0 {
+		i = encodeVarintSketch(dAtA, i, uint64(m.TimestampMs))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.Hyperloglog) > 0 {
+		i -= len(m.Hyperloglog)
+		copy(dAtA[i:], m.Hyperloglog)
+		i = encodeVarintSketch(dAtA, i, uint64(len(m.Hyperloglog)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *QuantileSketch) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *QuantileSketch) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *QuantileSketch) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Sketch != nil {
+		{
+			size := m.Sketch.Size()
+			i -= size
+			if _, err := m.Sketch.MarshalTo(dAtA[i:]); err != nil {
+				return 0, err
+			}
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *QuantileSketch_Tdigest) MarshalTo(dAtA []byte) (int, error) {
+	return m.MarshalToSizedBuffer(dAtA[:m.Size()])
+}
+
+func (m *QuantileSketch_Tdigest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	if m.Tdigest != nil {
 		{
 			size, err := m.Tdigest.MarshalToSizedBuffer(dAtA[:i])
 			if err != nil {
@@ -1820,6 +2227,58 @@ func (m *QuantileSketchSample) Size() (n int) {
 	return n
 }
 
+func (m *CountDistinctSketchMatrix) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Values) > 0 {
+		for _, e := range m.Values {
+			l = e.Size()
+			n += 1 + l + sovSketch(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *CountDistinctSketchVector) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Samples) > 0 {
+		for _, e := range m.Samples {
+			l = e.Size()
+			n += 1 + l + sovSketch(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *CountDistinctSketchSample) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Hyperloglog)
+	if l > 0 {
+		n += 1 + l + sovSketch(uint64(l))
+	}
+	if m.TimestampMs != 0 {
+		n += 1 + sovSketch(uint64(m.TimestampMs))
+	}
+	if len(m.Metric) > 0 {
+		for _, e := range m.Metric {
+			l = e.Size()
+			n += 1 + l + sovSketch(uint64(l))
+		}
+	}
+	return n
+}
+
 func (m *QuantileSketch) Size() (n int) {
 	if m == nil {
 		return 0
@@ -2040,6 +2499,53 @@ func (this *QuantileSketchSample) String() string {
 	}, "")
 	return s
 }
+func (this *CountDistinctSketchMatrix) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForValues := "[]*CountDistinctSketchVector{"
+	for _, f := range this.Values {
+		repeatedStringForValues += strings.Replace(f.String(), "CountDistinctSketchVector", "CountDistinctSketchVector", 1) + ","
+	}
+	repeatedStringForValues += "}"
+	s := strings.Join([]string{`&CountDistinctSketchMatrix{`,
+		`Values:` + repeatedStringForValues + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *CountDistinctSketchVector) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForSamples := "[]*CountDistinctSketchSample{"
+	for _, f := range this.Samples {
+		repeatedStringForSamples += strings.Replace(f.String(), "CountDistinctSketchSample", "CountDistinctSketchSample", 1) + ","
+	}
+	repeatedStringForSamples += "}"
+	s := strings.Join([]string{`&CountDistinctSketchVector{`,
+		`Samples:` + repeatedStringForSamples + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *CountDistinctSketchSample) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForMetric := "[]*LabelPair{"
+	for _, f := range this.Metric {
+		repeatedStringForMetric += strings.Replace(fmt.Sprintf("%v", f), "LabelPair", "LabelPair", 1) + ","
+	}
+	repeatedStringForMetric += "}"
+	s := strings.Join([]string{`&CountDistinctSketchSample{`,
+		`Hyperloglog:` + fmt.Sprintf("%v", this.Hyperloglog) + `,`,
+		`TimestampMs:` + fmt.Sprintf("%v", this.TimestampMs) + `,`,
+		`Metric:` + repeatedStringForMetric + `,`,
+		`}`,
+	}, "")
+	return s
+}
 func (this *QuantileSketch) String() string {
 	if this == nil {
 		return "nil"
@@ -2489,6 +2995,320 @@ func (m *QuantileSketchSample) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *CountDistinctSketchMatrix) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowSketch
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: CountDistinctSketchMatrix: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: CountDistinctSketchMatrix: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Values", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSketch
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSketch
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthSketch
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Values = append(m.Values, &CountDistinctSketchVector{})
+			if err := m.Values[len(m.Values)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipSketch(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthSketch
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthSketch
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *CountDistinctSketchVector) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowSketch
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: CountDistinctSketchVector: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: CountDistinctSketchVector: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Samples", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSketch
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSketch
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthSketch
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Samples = append(m.Samples, &CountDistinctSketchSample{})
+			if err := m.Samples[len(m.Samples)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipSketch(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthSketch
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthSketch
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *CountDistinctSketchSample) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowSketch
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: CountDistinctSketchSample: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: CountDistinctSketchSample: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Hyperloglog", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSketch
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthSketch
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthSketch
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Hyperloglog = append(m.Hyperloglog[:0], dAtA[iNdEx:postIndex]...)
+			if m.Hyperloglog == nil {
+				m.Hyperloglog = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TimestampMs", wireType)
+			}
+			m.TimestampMs = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSketch
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.TimestampMs |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Metric", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSketch
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSketch
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthSketch
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Metric = append(m.Metric, &LabelPair{})
+			if err := m.Metric[len(m.Metric)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipSketch(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthSketch
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthSketch
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func (m *QuantileSketch) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
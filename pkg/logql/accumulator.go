@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/logql/sketch"
 	"github.com/grafana/loki/pkg/logqlmodel"
 	"github.com/grafana/loki/pkg/logqlmodel/metadata"
 	"github.com/grafana/loki/pkg/logqlmodel/stats"
@@ -69,6 +70,79 @@ func (a *QuantileSketchAccumulator) Result() []logqlmodel.Result {
 	return []logqlmodel.Result{{Data: a.matrix}}
 }
 
+type CountDistinctSketchAccumulator struct {
+	matrix ProbabilisticCountDistinctMatrix
+}
+
+// newCountDistinctSketchAccumulator returns an accumulator for sharded
+// approximate distinct count queries that merges results as they come in.
+func newCountDistinctSketchAccumulator() *CountDistinctSketchAccumulator {
+	return &CountDistinctSketchAccumulator{}
+}
+
+func (a *CountDistinctSketchAccumulator) Accumulate(_ context.Context, res logqlmodel.Result, _ int) error {
+	if res.Data.Type() != CountDistinctSketchMatrixType {
+		return fmt.Errorf("unexpected matrix data type: got (%s), want (%s)", res.Data.Type(), CountDistinctSketchMatrixType)
+	}
+	data, ok := res.Data.(ProbabilisticCountDistinctMatrix)
+	if !ok {
+		return fmt.Errorf("unexpected matrix type: got (%T), want (ProbabilisticCountDistinctMatrix)", res.Data)
+	}
+	if a.matrix == nil {
+		a.matrix = data
+		return nil
+	}
+
+	var err error
+	a.matrix, err = a.matrix.Merge(data)
+	return err
+}
+
+func (a *CountDistinctSketchAccumulator) Result() []logqlmodel.Result {
+	return []logqlmodel.Result{{Data: a.matrix}}
+}
+
+type TopKSketchAccumulator struct {
+	k      int
+	matrix sketch.TopKMatrix
+}
+
+// newTopKSketchAccumulator returns an accumulator for sharded approximate
+// topk queries that merges results as they come in.
+func newTopKSketchAccumulator(k int) *TopKSketchAccumulator {
+	return &TopKSketchAccumulator{k: k}
+}
+
+func (a *TopKSketchAccumulator) Accumulate(_ context.Context, res logqlmodel.Result, _ int) error {
+	if res.Data.Type() != sketch.ValueTypeTopKMatrix {
+		return fmt.Errorf("unexpected matrix data type: got (%s), want (%s)", res.Data.Type(), sketch.ValueTypeTopKMatrix)
+	}
+	data, ok := res.Data.(sketch.TopKMatrix)
+	if !ok {
+		return fmt.Errorf("unexpected matrix type: got (%T), want (sketch.TopKMatrix)", res.Data)
+	}
+	if a.matrix == nil {
+		// Sketches decoded from the wire don't know k, so merge them
+		// into empty sketches of the right size instead.
+		a.matrix = make(sketch.TopKMatrix, 0, len(data))
+		for _, v := range data {
+			topk, err := newTopKSketch(a.k)
+			if err != nil {
+				return err
+			}
+			a.matrix = append(a.matrix, sketch.NewTopKVector(topk, v.Timestamp()))
+		}
+	}
+
+	var err error
+	a.matrix, err = a.matrix.Merge(data)
+	return err
+}
+
+func (a *TopKSketchAccumulator) Result() []logqlmodel.Result {
+	return []logqlmodel.Result{{Data: a.matrix}}
+}
+
 // heap impl for keeping only the top n results across m streams
 // importantly, AccumulatedStreams is _bounded_, so it will only
 // store the top `limit` results across all streams.
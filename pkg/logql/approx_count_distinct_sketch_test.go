@@ -0,0 +1,51 @@
+package logql
+
+import (
+	"testing"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProbabilisticCountDistinctMatrixSerialization(t *testing.T) {
+	matrix := ProbabilisticCountDistinctMatrix([]ProbabilisticCountDistinctVector{
+		[]ProbabilisticCountDistinctSample{
+			{T: 42, F: newCountDistinctSketch([]promql.FPoint{{F: 1}, {F: 2}, {F: 2}}), Metric: []labels.Label{{Name: "foo", Value: "bar"}}},
+		},
+	})
+
+	proto, err := matrix.ToProto()
+	require.NoError(t, err)
+
+	actual, err := ProbabilisticCountDistinctMatrixFromProto(proto)
+	require.NoError(t, err)
+	require.Len(t, actual, 1)
+	require.Len(t, actual[0], 1)
+	require.Equal(t, int64(42), actual[0][0].T)
+	require.Equal(t, labels.FromStrings("foo", "bar"), actual[0][0].Metric)
+	require.Equal(t, uint64(2), actual[0][0].F.Estimate())
+}
+
+func TestProbabilisticCountDistinctVectorMerge(t *testing.T) {
+	points := func(from, to int) []promql.FPoint {
+		var res []promql.FPoint
+		for i := from; i < to; i++ {
+			res = append(res, promql.FPoint{F: float64(i)})
+		}
+		return res
+	}
+	left := ProbabilisticCountDistinctVector{
+		{F: newCountDistinctSketch(points(0, 10)), Metric: labels.FromStrings("a", "1")},
+	}
+	right := ProbabilisticCountDistinctVector{
+		{F: newCountDistinctSketch(points(5, 20)), Metric: labels.FromStrings("a", "1")},
+		{F: newCountDistinctSketch(points(0, 3)), Metric: labels.FromStrings("a", "2")},
+	}
+
+	merged, err := left.Merge(right)
+	require.NoError(t, err)
+	require.Len(t, merged, 2)
+	require.Equal(t, uint64(20), merged[0].F.Estimate())
+	require.Equal(t, uint64(3), merged[1].F.Estimate())
+}
@@ -0,0 +1,247 @@
+package logql
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/prometheus/prometheus/promql"
+	promql_parser "github.com/prometheus/prometheus/promql/parser"
+
+	"github.com/grafana/loki/pkg/logql/sketch"
+	"github.com/grafana/loki/pkg/logql/syntax"
+)
+
+// topKSketchCardinality is the cardinality the count-min sketches of
+// approx_topk are sized for. All sketches must have the same dimensions to be
+// merged, so queriers and frontends must agree on it. A larger cardinality is
+// still supported but the error of the estimates grows with it.
+const topKSketchCardinality = 10000
+
+func newTopKSketch(k int) (*sketch.Topk, error) {
+	return sketch.NewCMSTopkForCardinality(nil, k, topKSketchCardinality)
+}
+
+// TopKSketchVector is the count-min sketch of a single step of an
+// approx_topk aggregation.
+type TopKSketchVector sketch.TopKVector
+
+var _ StepResult = TopKSketchVector{}
+
+func (TopKSketchVector) SampleVector() promql.Vector {
+	return promql.Vector{}
+}
+
+func (TopKSketchVector) QuantileSketchVec() ProbabilisticQuantileVector {
+	return ProbabilisticQuantileVector{}
+}
+
+func (TopKSketchVector) CountDistinctSketchVec() ProbabilisticCountDistinctVector {
+	return ProbabilisticCountDistinctVector{}
+}
+
+func (v TopKSketchVector) TopKSketchVec() sketch.TopKVector {
+	return sketch.TopKVector(v)
+}
+
+// TopKSketchStepEvaluator observes the samples of each step of the inner
+// evaluator in a count-min sketch. The labels of a sample are the event and
+// its value, rounded to the nearest integer, the number of occurrences.
+type TopKSketchStepEvaluator struct {
+	inner StepEvaluator
+	k     int
+
+	err error
+}
+
+func newTopKSketchEvaluator(
+	ctx context.Context,
+	evFactory SampleEvaluatorFactory,
+	expr *syntax.VectorAggregationExpr,
+	q Params,
+) (*TopKSketchStepEvaluator, error) {
+	if expr.Params < 1 {
+		return nil, fmt.Errorf("invalid parameter for operation %s: %d", expr.Operation, expr.Params)
+	}
+	inner, err := evFactory.NewStepEvaluator(ctx, evFactory, expr.Left, q)
+	if err != nil {
+		return nil, err
+	}
+	return &TopKSketchStepEvaluator{
+		inner: inner,
+		k:     expr.Params,
+	}, nil
+}
+
+func (e *TopKSketchStepEvaluator) Next() (bool, int64, StepResult) {
+	next, ts, r := e.inner.Next()
+	if !next {
+		return false, 0, TopKSketchVector{}
+	}
+
+	topk, err := newTopKSketch(e.k)
+	if err != nil {
+		e.err = err
+		return false, 0, TopKSketchVector{}
+	}
+	for _, s := range r.SampleVector() {
+		if math.IsNaN(s.F) || s.F < 0 {
+			continue
+		}
+		n := math.Min(math.Round(s.F), math.MaxUint32)
+		if n == 0 {
+			continue
+		}
+		topk.ObserveN(s.Metric.String(), uint32(n))
+	}
+
+	return true, ts, TopKSketchVector(sketch.NewTopKVector(topk, uint64(ts)))
+}
+
+func (e *TopKSketchStepEvaluator) Close() error { return e.inner.Close() }
+
+func (e *TopKSketchStepEvaluator) Error() error {
+	if e.err != nil {
+		return e.err
+	}
+	return e.inner.Error()
+}
+
+func (e *TopKSketchStepEvaluator) Explain(parent Node) {
+	b := parent.Child("TopKSketch")
+	e.inner.Explain(b)
+}
+
+// MergeTopKSketchVector joins the results from stepEvaluator into a sketch.TopKMatrix.
+func MergeTopKSketchVector(next bool, r StepResult, stepEvaluator StepEvaluator, params Params) (promql_parser.Value, error) {
+	vec := r.TopKSketchVec()
+	if stepEvaluator.Error() != nil {
+		return nil, stepEvaluator.Error()
+	}
+
+	if GetRangeType(params) == InstantType {
+		return sketch.TopKMatrix{vec}, nil
+	}
+
+	stepCount := int(math.Ceil(float64(params.End().Sub(params.Start()).Nanoseconds()) / float64(params.Step().Nanoseconds())))
+	if stepCount <= 0 {
+		stepCount = 1
+	}
+
+	result := make(sketch.TopKMatrix, 0, stepCount)
+
+	for next {
+		result = append(result, vec)
+		next, _, r = stepEvaluator.Next()
+		vec = r.TopKSketchVec()
+		if stepEvaluator.Error() != nil {
+			return nil, stepEvaluator.Error()
+		}
+	}
+
+	return result, stepEvaluator.Error()
+}
+
+// TopKSketchMatrixStepEvaluator steps through a matrix of count-min sketches.
+type TopKSketchMatrixStepEvaluator struct {
+	start, end, ts time.Time
+	step           time.Duration
+	m              sketch.TopKMatrix
+}
+
+func NewTopKSketchMatrixStepEvaluator(m sketch.TopKMatrix, params Params) *TopKSketchMatrixStepEvaluator {
+	var (
+		start = params.Start()
+		end   = params.End()
+		step  = params.Step()
+	)
+	return &TopKSketchMatrixStepEvaluator{
+		start: start,
+		end:   end,
+		ts:    start.Add(-step), // will be corrected on first Next() call
+		step:  step,
+		m:     m,
+	}
+}
+
+func (m *TopKSketchMatrixStepEvaluator) Next() (bool, int64, StepResult) {
+	m.ts = m.ts.Add(m.step)
+	if m.ts.After(m.end) {
+		return false, 0, nil
+	}
+
+	ts := m.ts.UnixNano() / int64(time.Millisecond)
+
+	if len(m.m) == 0 {
+		return false, 0, nil
+	}
+
+	vec := m.m[0]
+
+	// Reset for next step
+	m.m = m.m[1:]
+
+	return true, ts, TopKSketchVector(vec)
+}
+
+func (*TopKSketchMatrixStepEvaluator) Close() error { return nil }
+
+func (*TopKSketchMatrixStepEvaluator) Error() error { return nil }
+
+func (*TopKSketchMatrixStepEvaluator) Explain(parent Node) {
+	parent.Child("TopKSketchMatrix")
+}
+
+// TopKSketchVectorStepEvaluator evaluates a count-min sketch into a
+// promql.Vector of the top k series and their estimated values.
+type TopKSketchVectorStepEvaluator struct {
+	inner StepEvaluator
+
+	err error
+}
+
+var _ StepEvaluator = NewTopKSketchVectorStepEvaluator(nil)
+
+func NewTopKSketchVectorStepEvaluator(inner StepEvaluator) *TopKSketchVectorStepEvaluator {
+	return &TopKSketchVectorStepEvaluator{
+		inner: inner,
+	}
+}
+
+func (e *TopKSketchVectorStepEvaluator) Next() (bool, int64, StepResult) {
+	ok, ts, r := e.inner.Next()
+	if !ok {
+		return false, 0, SampleVector{}
+	}
+	topk := r.TopKSketchVec().Topk()
+	if topk == nil {
+		return ok, ts, SampleVector{}
+	}
+
+	result := topk.Topk()
+	vec := make(promql.Vector, 0, len(result))
+	for _, s := range result {
+		metric, err := syntax.ParseLabels(s.Event)
+		if err != nil {
+			e.err = fmt.Errorf("cannot parse labels of topk sketch: %w", err)
+			return false, 0, SampleVector{}
+		}
+		vec = append(vec, promql.Sample{
+			T:      ts,
+			F:      float64(s.Count),
+			Metric: metric,
+		})
+	}
+
+	return ok, ts, SampleVector(vec)
+}
+
+func (*TopKSketchVectorStepEvaluator) Close() error { return nil }
+
+func (e *TopKSketchVectorStepEvaluator) Error() error { return e.err }
+
+func (e *TopKSketchVectorStepEvaluator) Explain(parent Node) {
+	b := parent.Child("TopKSketchVector")
+	e.inner.Explain(b)
+}
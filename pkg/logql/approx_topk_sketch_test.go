@@ -0,0 +1,58 @@
+package logql
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/logql/sketch"
+	"github.com/grafana/loki/pkg/logqlmodel"
+)
+
+func TestTopKSketchAccumulator(t *testing.T) {
+	shard := func(counts map[string]uint32) sketch.TopKMatrix {
+		topk, err := newTopKSketch(2)
+		require.NoError(t, err)
+		for event, n := range counts {
+			topk.ObserveN(event, n)
+		}
+		// go through the wire format as sharded queries do
+		proto, err := sketch.TopKMatrix{sketch.NewTopKVector(topk, 1000)}.ToProto()
+		require.NoError(t, err)
+		m, err := sketch.TopKMatrixFromProto(proto)
+		require.NoError(t, err)
+		return m
+	}
+
+	acc := newTopKSketchAccumulator(2)
+	for _, m := range []sketch.TopKMatrix{
+		shard(map[string]uint32{`{path="/a"}`: 10, `{path="/b"}`: 5}),
+		shard(map[string]uint32{`{path="/b"}`: 7, `{path="/c"}`: 11}),
+		shard(map[string]uint32{`{path="/d"}`: 1}),
+	} {
+		require.NoError(t, acc.Accumulate(context.Background(), logqlmodel.Result{Data: m}, 0))
+	}
+	res := acc.Result()
+	require.Len(t, res, 1)
+
+	params, err := NewLiteralParams(`approx_topk(2, sum by (path) (count_over_time({app="foo"}[1s])))`, time.Unix(1, 0), time.Unix(1, 0), 0, 0, logproto.FORWARD, 0, nil)
+	require.NoError(t, err)
+	ev := NewTopKSketchVectorStepEvaluator(NewTopKSketchMatrixStepEvaluator(res[0].Data.(sketch.TopKMatrix), params))
+
+	ok, ts, r := ev.Next()
+	require.True(t, ok)
+	require.NoError(t, ev.Error())
+	require.Equal(t, int64(1000), ts)
+	require.Equal(t, promql.Vector{
+		{T: 1000, F: 12, Metric: labels.FromStrings("path", "/b")},
+		{T: 1000, F: 11, Metric: labels.FromStrings("path", "/c")},
+	}, r.SampleVector())
+
+	ok, _, _ = ev.Next()
+	require.False(t, ok)
+}
@@ -0,0 +1,196 @@
+// Package detected discovers the fields of log lines by parsing them with the
+// LogQL parsers, and merges the fields detected in several responses of the
+// detected fields API.
+package detected
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/axiomhq/hyperloglog"
+	"github.com/dustin/go-humanize"
+	"github.com/prometheus/prometheus/model/labels"
+
+	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/logql/log"
+	"github.com/grafana/loki/pkg/logqlmodel"
+)
+
+// The types inferred from the values of a field.
+const (
+	TypeString   = "string"
+	TypeInt      = "int"
+	TypeFloat    = "float"
+	TypeDuration = "duration"
+	TypeBytes    = "bytes"
+)
+
+// The names of the parsers used to detect fields, as in LogQL.
+const (
+	ParserLogfmt = "logfmt"
+	ParserJSON   = "json"
+)
+
+// InferType returns the most specific type the value can be converted to.
+func InferType(v string) string {
+	if _, err := strconv.ParseInt(v, 10, 64); err == nil {
+		return TypeInt
+	}
+	if _, err := strconv.ParseFloat(v, 64); err == nil {
+		return TypeFloat
+	}
+	if _, err := time.ParseDuration(v); err == nil {
+		return TypeDuration
+	}
+	if _, err := humanize.ParseBytes(v); err == nil {
+		return TypeBytes
+	}
+	return TypeString
+}
+
+// mergeTypes returns the type of a field whose values have both types.
+func mergeTypes(a, b string) string {
+	switch {
+	case a == b || b == "":
+		return a
+	case a == "":
+		return b
+	case (a == TypeInt && b == TypeFloat) || (a == TypeFloat && b == TypeInt):
+		return TypeFloat
+	default:
+		return TypeString
+	}
+}
+
+type namedParser struct {
+	name     string
+	pipeline log.Pipeline
+}
+
+type field struct {
+	typ     string
+	parsers []string
+	sketch  *hyperloglog.Sketch
+	// cardinality is the largest cardinality of the responses without sketch.
+	cardinality uint64
+}
+
+func (f *field) addParser(name string) {
+	for _, p := range f.parsers {
+		if p == name {
+			return
+		}
+	}
+	f.parsers = append(f.parsers, name)
+}
+
+// Accumulator collects the fields detected in log lines and in responses.
+// It is not safe for concurrent use.
+type Accumulator struct {
+	fields  map[string]*field
+	parsers []namedParser
+	buf     labels.Labels
+}
+
+// NewAccumulator creates an empty Accumulator.
+func NewAccumulator() *Accumulator {
+	return &Accumulator{
+		fields: map[string]*field{},
+		parsers: []namedParser{
+			{name: ParserLogfmt, pipeline: log.NewPipeline([]log.Stage{log.NewLogfmtParser(true, false)})},
+			{name: ParserJSON, pipeline: log.NewPipeline([]log.Stage{log.NewJSONParser()})},
+		},
+	}
+}
+
+// AddLine parses the line of the stream with each parser and records the
+// labels they extract. The parsers failing to parse the line are ignored.
+func (a *Accumulator) AddLine(stream labels.Labels, line []byte) {
+	for _, p := range a.parsers {
+		_, lbs, ok := p.pipeline.ForStream(stream).Process(0, line)
+		if !ok {
+			continue
+		}
+		a.buf = append(a.buf[:0], lbs.Parsed()...)
+		if a.buf.Has(logqlmodel.ErrorLabel) {
+			continue
+		}
+		for _, l := range a.buf {
+			f := a.field(l.Name)
+			f.typ = mergeTypes(f.typ, InferType(l.Value))
+			f.sketch.Insert([]byte(l.Value))
+			f.addParser(p.name)
+		}
+	}
+}
+
+// AddResponse merges the fields of the response.
+func (a *Accumulator) AddResponse(res *logproto.DetectedFieldsResponse) error {
+	if res == nil {
+		return nil
+	}
+	for _, df := range res.Fields {
+		f := a.field(df.Label)
+		f.typ = mergeTypes(f.typ, df.Type)
+		for _, p := range df.Parsers {
+			f.addParser(p)
+		}
+		if len(df.Sketch) == 0 {
+			f.cardinality = max(f.cardinality, df.Cardinality)
+			continue
+		}
+		sketch := hyperloglog.New()
+		if err := sketch.UnmarshalBinary(df.Sketch); err != nil {
+			return err
+		}
+		if err := f.sketch.Merge(sketch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Response returns at most limit of the accumulated fields, sorted by name.
+// A limit of 0 returns all the fields.
+func (a *Accumulator) Response(limit uint32) (*logproto.DetectedFieldsResponse, error) {
+	names := make([]string, 0, len(a.fields))
+	for name := range a.fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	if limit > 0 && len(names) > int(limit) {
+		names = names[:limit]
+	}
+
+	res := &logproto.DetectedFieldsResponse{
+		Fields:     make([]*logproto.DetectedField, 0, len(names)),
+		FieldLimit: limit,
+	}
+	for _, name := range names {
+		f := a.fields[name]
+		sketch, err := f.sketch.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		parsers := append([]string(nil), f.parsers...)
+		sort.Strings(parsers)
+		res.Fields = append(res.Fields, &logproto.DetectedField{
+			Label:       name,
+			Type:        f.typ,
+			Cardinality: max(f.sketch.Estimate(), f.cardinality),
+			Parsers:     parsers,
+			Sketch:      sketch,
+		})
+	}
+	return res, nil
+}
+
+func (a *Accumulator) field(name string) *field {
+	f, ok := a.fields[name]
+	if !ok {
+		f = &field{sketch: hyperloglog.New()}
+		a.fields[name] = f
+	}
+	return f
+}
@@ -0,0 +1,117 @@
+package detected
+
+import (
+	"testing"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/pkg/logproto"
+)
+
+func TestInferType(t *testing.T) {
+	for v, expected := range map[string]string{
+		"42":     TypeInt,
+		"-7":     TypeInt,
+		"0.5":    TypeFloat,
+		"1e3":    TypeFloat,
+		"150ms":  TypeDuration,
+		"1h30m":  TypeDuration,
+		"10KB":   TypeBytes,
+		"1.5 GB": TypeBytes,
+		"GET":    TypeString,
+		"":       TypeString,
+	} {
+		require.Equal(t, expected, InferType(v), v)
+	}
+}
+
+func TestMergeTypes(t *testing.T) {
+	require.Equal(t, TypeInt, mergeTypes("", TypeInt))
+	require.Equal(t, TypeInt, mergeTypes(TypeInt, ""))
+	require.Equal(t, TypeFloat, mergeTypes(TypeInt, TypeFloat))
+	require.Equal(t, TypeFloat, mergeTypes(TypeFloat, TypeInt))
+	require.Equal(t, TypeString, mergeTypes(TypeInt, TypeDuration))
+	require.Equal(t, TypeBytes, mergeTypes(TypeBytes, TypeBytes))
+}
+
+func fieldsByName(res *logproto.DetectedFieldsResponse) map[string]*logproto.DetectedField {
+	m := map[string]*logproto.DetectedField{}
+	for _, f := range res.Fields {
+		m[f.Label] = f
+	}
+	return m
+}
+
+func TestAccumulator_AddLine(t *testing.T) {
+	stream := labels.FromStrings("app", "foo", "status", "stream")
+	acc := NewAccumulator()
+	for _, line := range []string{
+		`level=info status=200 took=12ms`,
+		`level=warn status=500 took=1.5s`,
+		`{"level":"error","status":200,"bytes":"10KB","ratio":1}`,
+		`{"level":"info","ratio":0.5,"nested":{"key":"a"}}`,
+		`just some text`,
+		`{"broken json`,
+	} {
+		acc.AddLine(stream, []byte(line))
+	}
+
+	res, err := acc.Response(0)
+	require.NoError(t, err)
+
+	var names []string
+	for _, f := range res.Fields {
+		names = append(names, f.Label)
+	}
+	// fields clashing with stream labels get the suffix of the parsers.
+	require.Equal(t, []string{"bytes", "level", "nested_key", "ratio", "status_extracted", "took"}, names)
+
+	fields := fieldsByName(res)
+	require.Equal(t, TypeString, fields["level"].Type)
+	require.Equal(t, []string{ParserJSON, ParserLogfmt}, fields["level"].Parsers)
+	require.Equal(t, uint64(3), fields["level"].Cardinality)
+	require.Equal(t, TypeInt, fields["status_extracted"].Type)
+	require.Equal(t, uint64(2), fields["status_extracted"].Cardinality)
+	require.Equal(t, TypeDuration, fields["took"].Type)
+	require.Equal(t, []string{ParserLogfmt}, fields["took"].Parsers)
+	require.Equal(t, TypeBytes, fields["bytes"].Type)
+	require.Equal(t, TypeFloat, fields["ratio"].Type)
+	require.Equal(t, []string{ParserJSON}, fields["nested_key"].Parsers)
+}
+
+func TestAccumulator_AddResponse(t *testing.T) {
+	stream := labels.FromStrings("app", "foo")
+
+	first := NewAccumulator()
+	first.AddLine(stream, []byte(`user=alice latency=10`))
+	first.AddLine(stream, []byte(`user=bob latency=20`))
+	firstRes, err := first.Response(0)
+	require.NoError(t, err)
+
+	second := NewAccumulator()
+	second.AddLine(stream, []byte(`{"user":"bob","latency":0.5}`))
+	second.AddLine(stream, []byte(`{"user":"carol","latency":1}`))
+	secondRes, err := second.Response(0)
+	require.NoError(t, err)
+
+	acc := NewAccumulator()
+	require.NoError(t, acc.AddResponse(firstRes))
+	require.NoError(t, acc.AddResponse(secondRes))
+	// responses without sketch keep their cardinality.
+	require.NoError(t, acc.AddResponse(&logproto.DetectedFieldsResponse{
+		Fields: []*logproto.DetectedField{{Label: "path", Type: TypeString, Cardinality: 7, Parsers: []string{ParserLogfmt}}},
+	}))
+
+	res, err := acc.Response(2)
+	require.NoError(t, err)
+	require.Equal(t, uint32(2), res.FieldLimit)
+	require.Len(t, res.Fields, 2)
+
+	fields := fieldsByName(res)
+	require.Equal(t, TypeFloat, fields["latency"].Type)
+	require.Equal(t, uint64(4), fields["latency"].Cardinality)
+	require.Equal(t, []string{ParserJSON, ParserLogfmt}, fields["latency"].Parsers)
+	require.Equal(t, uint64(7), fields["path"].Cardinality)
+	require.NotContains(t, fields, "user")
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
 	"strings"
 
 	"github.com/go-kit/log"
@@ -12,6 +13,7 @@ import (
 
 	"github.com/grafana/loki/pkg/iter"
 	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/logql/sketch"
 	"github.com/grafana/loki/pkg/logql/syntax"
 	"github.com/grafana/loki/pkg/logqlmodel"
 	"github.com/grafana/loki/pkg/logqlmodel/metadata"
@@ -301,6 +303,94 @@ func (e *QuantileSketchMergeExpr) Walk(f syntax.WalkFn) {
 	}
 }
 
+// CountDistinctSketchEvalExpr evaluates HyperLogLog sketches to the estimated
+// number of distinct values.
+type CountDistinctSketchEvalExpr struct {
+	syntax.SampleExpr
+	countDistinctMergeExpr *CountDistinctSketchMergeExpr
+}
+
+func (e CountDistinctSketchEvalExpr) String() string {
+	return fmt.Sprintf("countDistinctSketchEval<%s>", e.countDistinctMergeExpr.String())
+}
+
+func (e *CountDistinctSketchEvalExpr) Walk(f syntax.WalkFn) {
+	f(e)
+	e.countDistinctMergeExpr.Walk(f)
+}
+
+type CountDistinctSketchMergeExpr struct {
+	syntax.SampleExpr
+	downstreams []DownstreamSampleExpr
+}
+
+func (e CountDistinctSketchMergeExpr) String() string {
+	var sb strings.Builder
+	for i, d := range e.downstreams {
+		if i >= defaultMaxDepth {
+			break
+		}
+
+		if i > 0 {
+			sb.WriteString(" ++ ")
+		}
+
+		sb.WriteString(d.String())
+	}
+	return fmt.Sprintf("countDistinctSketchMerge<%s>", sb.String())
+}
+
+func (e *CountDistinctSketchMergeExpr) Walk(f syntax.WalkFn) {
+	f(e)
+	for _, d := range e.downstreams {
+		d.Walk(f)
+	}
+}
+
+// TopKSketchEvalExpr evaluates merged count-min sketches to the top k series.
+type TopKSketchEvalExpr struct {
+	syntax.SampleExpr
+	topKMergeExpr *TopKSketchMergeExpr
+	k             int
+}
+
+func (e TopKSketchEvalExpr) String() string {
+	return fmt.Sprintf("topkSketchEval<%s>", e.topKMergeExpr.String())
+}
+
+func (e *TopKSketchEvalExpr) Walk(f syntax.WalkFn) {
+	f(e)
+	e.topKMergeExpr.Walk(f)
+}
+
+type TopKSketchMergeExpr struct {
+	syntax.SampleExpr
+	downstreams []DownstreamSampleExpr
+}
+
+func (e TopKSketchMergeExpr) String() string {
+	var sb strings.Builder
+	for i, d := range e.downstreams {
+		if i >= defaultMaxDepth {
+			break
+		}
+
+		if i > 0 {
+			sb.WriteString(" ++ ")
+		}
+
+		sb.WriteString(d.String())
+	}
+	return fmt.Sprintf("topkSketchMerge<%s>", sb.String())
+}
+
+func (e *TopKSketchMergeExpr) Walk(f syntax.WalkFn) {
+	f(e)
+	for _, d := range e.downstreams {
+		d.Walk(f)
+	}
+}
+
 type Downstreamable interface {
 	Downstreamer(context.Context) Downstreamer
 }
@@ -478,6 +568,80 @@ func (ev *DownstreamEvaluator) NewStepEvaluator(
 		inner := NewQuantileSketchMatrixStepEvaluator(matrix, params)
 		return NewQuantileSketchVectorStepEvaluator(inner, *e.quantile), nil
 
+	case *CountDistinctSketchEvalExpr:
+		var queries []DownstreamQuery
+		if e.countDistinctMergeExpr != nil {
+			for _, d := range e.countDistinctMergeExpr.downstreams {
+				qry := DownstreamQuery{
+					Params: ParamsWithExpressionOverride{
+						Params:             params,
+						ExpressionOverride: d.SampleExpr,
+					},
+				}
+				if shard := d.shard; shard != nil {
+					qry.Params = ParamsWithShardsOverride{
+						Params:         qry.Params,
+						ShardsOverride: Shards{*shard}.Encode(),
+					}
+				}
+				queries = append(queries, qry)
+			}
+		}
+
+		acc := newCountDistinctSketchAccumulator()
+		results, err := ev.Downstream(ctx, queries, acc)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(results) != 1 {
+			return nil, fmt.Errorf("unexpected results length for sharded approximate distinct count: got (%d), want (1)", len(results))
+		}
+
+		matrix, ok := results[0].Data.(ProbabilisticCountDistinctMatrix)
+		if !ok {
+			return nil, fmt.Errorf("unexpected matrix type: got (%T), want (ProbabilisticCountDistinctMatrix)", results[0].Data)
+		}
+		inner := NewCountDistinctSketchMatrixStepEvaluator(matrix, params)
+		return NewCountDistinctSketchVectorStepEvaluator(inner), nil
+
+	case *TopKSketchEvalExpr:
+		var queries []DownstreamQuery
+		if e.topKMergeExpr != nil {
+			for _, d := range e.topKMergeExpr.downstreams {
+				qry := DownstreamQuery{
+					Params: ParamsWithExpressionOverride{
+						Params:             params,
+						ExpressionOverride: d.SampleExpr,
+					},
+				}
+				if shard := d.shard; shard != nil {
+					qry.Params = ParamsWithShardsOverride{
+						Params:         qry.Params,
+						ShardsOverride: Shards{*shard}.Encode(),
+					}
+				}
+				queries = append(queries, qry)
+			}
+		}
+
+		acc := newTopKSketchAccumulator(e.k)
+		results, err := ev.Downstream(ctx, queries, acc)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(results) != 1 {
+			return nil, fmt.Errorf("unexpected results length for sharded approximate topk: got (%d), want (1)", len(results))
+		}
+
+		matrix, ok := results[0].Data.(sketch.TopKMatrix)
+		if !ok {
+			return nil, fmt.Errorf("unexpected matrix type: got (%T), want (sketch.TopKMatrix)", results[0].Data)
+		}
+		inner := NewTopKSketchMatrixStepEvaluator(matrix, params)
+		return NewTopKSketchVectorStepEvaluator(inner), nil
+
 	default:
 		return ev.defaultEvaluator.NewStepEvaluator(ctx, nextEvFactory, e, params)
 	}
@@ -523,6 +687,11 @@ func (ev *DownstreamEvaluator) NewIterator(
 		}
 
 		acc := NewStreamAccumulator(params)
+		if len(syntax.LineLimits(e)) > 0 {
+			// limit and dedup stages are applied again once the shards are merged,
+			// the lines of every shard must be kept until then.
+			acc.limit = math.MaxInt
+		}
 		results, err := ev.Downstream(ctx, queries, acc)
 		if err != nil {
 			return nil, err
@@ -42,6 +42,9 @@ func TestMappingEquivalence(t *testing.T) {
 		{`1 + 1`, false},
 		{`{a="1"}`, false},
 		{`{a="1"} |= "number: 10"`, false},
+		{`{a=~".+"} | limit 2`, false},
+		{`{a=~".+"} | dedup`, false},
+		{`{a=~".+"} | dedup by (a) 5s | limit 3`, false},
 		{`rate({a=~".+"}[1s])`, false},
 		{`sum by (a) (rate({a=~".+"}[1s]))`, false},
 		{`sum(rate({a=~".+"}[1s]))`, false},
@@ -142,6 +145,8 @@ func TestMappingEquivalenceSketches(t *testing.T) {
 	}{
 		{`quantile_over_time(0.70, {a=~".+"} | logfmt | unwrap value [1s]) by (a)`, 0.03},
 		{`quantile_over_time(0.99, {a=~".+"} | logfmt | unwrap value [1s]) by (a)`, 0.02},
+		{`approx_count_distinct({a=~".+"} | logfmt | unwrap value [1s]) by (a)`, 0.02},
+		{`approx_topk(2, sum by (a, b) (count_over_time({a=~".+"}[1s])))`, 0.01},
 	} {
 		q := NewMockQuerier(
 			shards,
@@ -168,7 +173,7 @@ func TestMappingEquivalenceSketches(t *testing.T) {
 			ctx := user.InjectOrgID(context.Background(), "fake")
 
 			strategy := NewPowerOfTwoStrategy(ConstantShards(shards))
-			mapper := NewShardMapper(strategy, nilShardMetrics, []string{ShardQuantileOverTime})
+			mapper := NewShardMapper(strategy, nilShardMetrics, []string{ShardQuantileOverTime, ShardApproxCountDistinct, ShardApproxTopK})
 			_, _, mapped, err := mapper.Parse(params.GetExpression())
 			require.NoError(t, err)
 
@@ -203,7 +208,7 @@ func TestMappingEquivalenceSketches(t *testing.T) {
 			ctx := user.InjectOrgID(context.Background(), "fake")
 
 			strategy := NewPowerOfTwoStrategy(ConstantShards(shards))
-			mapper := NewShardMapper(strategy, nilShardMetrics, []string{ShardQuantileOverTime})
+			mapper := NewShardMapper(strategy, nilShardMetrics, []string{ShardQuantileOverTime, ShardApproxCountDistinct, ShardApproxTopK})
 			_, _, mapped, err := mapper.Parse(params.GetExpression())
 			require.NoError(t, err)
 
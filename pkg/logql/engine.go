@@ -28,6 +28,7 @@ import (
 
 	"github.com/grafana/loki/pkg/iter"
 	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/logql/sketch"
 	"github.com/grafana/loki/pkg/logql/syntax"
 	"github.com/grafana/loki/pkg/logqlmodel"
 	"github.com/grafana/loki/pkg/logqlmodel/stats"
@@ -209,6 +210,10 @@ func (q *query) resultLength(res promql_parser.Value) int {
 		return int(r.Lines())
 	case ProbabilisticQuantileMatrix:
 		return len(r)
+	case ProbabilisticCountDistinctMatrix:
+		return len(r)
+	case sketch.TopKMatrix:
+		return len(r)
 	default:
 		// for `scalar` or `string` or any other return type, we just return `0` as result length.
 		return 0
@@ -286,10 +291,19 @@ func (q *query) Eval(ctx context.Context) (promql_parser.Value, error) {
 		return value, err
 
 	case syntax.LogSelectorExpr:
-		itr, err := q.evaluator.NewIterator(ctx, e, q.params)
+		selector, limits := splitLineLimits(e)
+		itr, err := q.evaluator.NewIterator(ctx, selector, q.params)
+		if err != nil {
+			return nil, err
+		}
+
+		// limit and dedup stages need every line in order, they are applied once results are merged.
+		limited, err := NewLineLimitsIterator(itr, limits)
 		if err != nil {
+			util.LogErrorWithContext(ctx, "closing iterator", itr.Close)
 			return nil, err
 		}
+		itr = limited
 
 		encodingFlags := httpreq.ExtractEncodingFlagsFromCtx(ctx)
 		if encodingFlags.Has(httpreq.FlagCategorizeLabels) {
@@ -364,6 +378,10 @@ func (q *query) evalSample(ctx context.Context, expr syntax.SampleExpr) (promql_
 			return q.JoinSampleVector(next, ts, vec, stepEvaluator, maxSeries)
 		case ProbabilisticQuantileVector:
 			return MergeQuantileSketchVector(next, vec, stepEvaluator, q.params)
+		case ProbabilisticCountDistinctVector:
+			return MergeCountDistinctSketchVector(next, vec, stepEvaluator, q.params)
+		case TopKSketchVector:
+			return MergeTopKSketchVector(next, vec, stepEvaluator, q.params)
 		default:
 			return nil, fmt.Errorf("unsupported result type: %T", r)
 		}
@@ -452,6 +470,11 @@ func (q *query) checkIntervalLimit(expr syntax.SampleExpr, limit time.Duration)
 				return
 			}
 			err = fmt.Errorf("%w: [%s] > [%s]", logqlmodel.ErrIntervalLimit, model.Duration(e.Left.Interval), model.Duration(limit))
+		case *syntax.SubqueryExpr:
+			if e.Range <= limit {
+				return
+			}
+			err = fmt.Errorf("%w: [%s] > [%s]", logqlmodel.ErrIntervalLimit, model.Duration(e.Range), model.Duration(limit))
 		}
 	})
 	return err
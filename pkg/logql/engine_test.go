@@ -145,6 +145,51 @@ func TestEngine_LogsRateUnwrap(t *testing.T) {
 			// (61 - 47) / 30 = 0.4666
 			promql.Vector{promql.Sample{T: 60 * 1000, F: 0.46666766666666665, Metric: labels.FromStrings("app", "foo")}},
 		},
+		{
+			`count_distinct_over_time({app="foo"} | unwrap foo [30s])`,
+			time.Unix(60, 0),
+			logproto.FORWARD,
+			10,
+			// create a stream {app="foo"} with 300 samples starting at 46s and ending at 345s with a constant value of 1
+			[][]logproto.Series{
+				{newSeries(testSize, offset(46, constantValue(1)), `{app="foo"}`)},
+			},
+			[]SelectSampleParams{
+				{&logproto.SampleQueryRequest{Start: time.Unix(30, 0), End: time.Unix(60, 0), Selector: `count_distinct_over_time({app="foo"} | unwrap foo[30s])`}},
+			},
+			// there are 15 samples (from 47 to 61) matched from the generated series, all with the same value
+			promql.Vector{promql.Sample{T: 60 * 1000, F: 1, Metric: labels.FromStrings("app", "foo")}},
+		},
+		{
+			`count_distinct_over_time({app="foo"} | unwrap foo [30s])`,
+			time.Unix(60, 0),
+			logproto.FORWARD,
+			10,
+			// create a stream {app="foo"} with 300 samples starting at 46s and ending at 345s with an increasing value by 1
+			[][]logproto.Series{
+				{newSeries(testSize, offset(46, incValue(1)), `{app="foo"}`)},
+			},
+			[]SelectSampleParams{
+				{&logproto.SampleQueryRequest{Start: time.Unix(30, 0), End: time.Unix(60, 0), Selector: `count_distinct_over_time({app="foo"} | unwrap foo[30s])`}},
+			},
+			// there are 15 samples (from 47 to 61) matched from the generated series, all with a different value
+			promql.Vector{promql.Sample{T: 60 * 1000, F: 15, Metric: labels.FromStrings("app", "foo")}},
+		},
+		{
+			`approx_count_distinct({app="foo"} | unwrap foo [30s])`,
+			time.Unix(60, 0),
+			logproto.FORWARD,
+			10,
+			// create a stream {app="foo"} with 300 samples starting at 46s and ending at 345s with an increasing value by 1
+			[][]logproto.Series{
+				{newSeries(testSize, offset(46, incValue(1)), `{app="foo"}`)},
+			},
+			[]SelectSampleParams{
+				{&logproto.SampleQueryRequest{Start: time.Unix(30, 0), End: time.Unix(60, 0), Selector: `approx_count_distinct({app="foo"} | unwrap foo[30s])`}},
+			},
+			// the sketch is exact for a low number of distinct values
+			promql.Vector{promql.Sample{T: 60 * 1000, F: 15, Metric: labels.FromStrings("app", "foo")}},
+		},
 	} {
 		test := test
 		t.Run(fmt.Sprintf("%s %s", test.qs, test.direction), func(t *testing.T) {
@@ -530,6 +575,18 @@ func TestEngine_LogsInstantQuery(t *testing.T) {
 				{T: 60 * 1000, F: 0.25, Metric: labels.FromStrings("app", "bar")},
 			},
 		},
+		{
+			`approx_topk(1, sum by (app) (count_over_time(({app=~"foo|bar"} |~".+bar")[1m])))`, time.Unix(60, 0), logproto.FORWARD, 100,
+			[][]logproto.Series{
+				{newSeries(testSize, factor(10, identity), `{app="foo"}`), newSeries(testSize, offset(46, identity), `{app="bar"}`)},
+			},
+			[]SelectSampleParams{
+				{&logproto.SampleQueryRequest{Start: time.Unix(0, 0), End: time.Unix(60, 0), Selector: `sum by (app)(count_over_time({app=~"foo|bar"} |~".+bar" [1m]))`}},
+			},
+			promql.Vector{
+				{T: 60 * 1000, F: 15, Metric: labels.FromStrings("app", "bar")},
+			},
+		},
 
 		{
 			`topk(1,rate(({app=~"foo|bar"} |~".+bar")[1m])) by (app)`, time.Unix(60, 0), logproto.FORWARD, 100,
@@ -1881,6 +1938,38 @@ func TestEngine_RangeQuery(t *testing.T) {
 				},
 			},
 		},
+		{
+			`sum_over_time(count_over_time({app="foo"}[10s])[30s:10s])`,
+			time.Unix(60, 0), time.Unix(120, 0), 30 * time.Second, 0, logproto.FORWARD, 100,
+			[][]logproto.Series{
+				{newSeries(testSize, identity, `{app="foo"}`)},
+			},
+			[]SelectSampleParams{
+				{&logproto.SampleQueryRequest{Start: time.Unix(20, 0), End: time.Unix(120, 0), Selector: `count_over_time({app="foo"}[10s])`}},
+			},
+			promql.Matrix{
+				promql.Series{
+					Metric: labels.FromStrings("app", "foo"),
+					Floats: []promql.FPoint{{T: 60 * 1000, F: 30}, {T: 90 * 1000, F: 30}, {T: 120 * 1000, F: 30}},
+				},
+			},
+		},
+		{
+			`max_over_time(sum by (app) (count_over_time({app="foo"}[10s]))[30s:] offset 10s)`,
+			time.Unix(60, 0), time.Unix(120, 0), 30 * time.Second, 0, logproto.FORWARD, 100,
+			[][]logproto.Series{
+				{newSeries(testSize, identity, `{app="foo"}`)},
+			},
+			[]SelectSampleParams{
+				{&logproto.SampleQueryRequest{Start: time.Unix(20, 0), End: time.Unix(110, 0), Selector: `sum by (app) (count_over_time({app="foo"}[10s]))`}},
+			},
+			promql.Matrix{
+				promql.Series{
+					Metric: labels.FromStrings("app", "foo"),
+					Floats: []promql.FPoint{{T: 60 * 1000, F: 10}, {T: 90 * 1000, F: 10}, {T: 120 * 1000, F: 10}},
+				},
+			},
+		},
 		{
 			`label_replace(
 				avg by (app) (
@@ -1915,6 +2004,44 @@ func TestEngine_RangeQuery(t *testing.T) {
 				},
 			},
 		},
+		{
+			`label_join(ceil(sum by (app) (rate({app="foo"}[1m]))), "new", "-", "app", "app")`,
+			time.Unix(60, 0), time.Unix(120, 0), 30 * time.Second, 0, logproto.FORWARD, 100,
+			[][]logproto.Series{
+				{
+					newSeries(testSize, factor(10, identity), `{app="foo"}`),
+				},
+			},
+			[]SelectSampleParams{
+				{&logproto.SampleQueryRequest{Start: time.Unix(0, 0), End: time.Unix(120, 0), Selector: `sum by (app) (rate({app="foo"}[1m]))`}},
+			},
+			promql.Matrix{
+				promql.Series{
+					Metric: labels.FromStrings("app", "foo", "new", "foo-foo"),
+					Floats: []promql.FPoint{{T: 60 * 1000, F: 1}, {T: 90 * 1000, F: 1}, {T: 120 * 1000, F: 1}},
+				},
+			},
+		},
+		{
+			`histogram_quantile(0.5, sum by (le) (count_over_time({app="foo"}[1m])))`,
+			time.Unix(60, 0), time.Unix(120, 0), 30 * time.Second, 0, logproto.FORWARD, 100,
+			[][]logproto.Series{
+				{
+					newSeries(testSize, factor(20, identity), `{app="foo", le="1"}`),
+					newSeries(testSize, factor(10, identity), `{app="foo", le="5"}`),
+					newSeries(testSize, factor(5, identity), `{app="foo", le="+Inf"}`),
+				},
+			},
+			[]SelectSampleParams{
+				{&logproto.SampleQueryRequest{Start: time.Unix(0, 0), End: time.Unix(120, 0), Selector: `sum by (le) (count_over_time({app="foo"}[1m]))`}},
+			},
+			promql.Matrix{
+				promql.Series{
+					Metric: labels.EmptyLabels(),
+					Floats: []promql.FPoint{{T: 60 * 1000, F: 5}, {T: 90 * 1000, F: 5}, {T: 120 * 1000, F: 5}},
+				},
+			},
+		},
 		{
 			` sum (
 					sum by (app) (rate({app=~"foo|bar"} |~".+bar" [1m])) +
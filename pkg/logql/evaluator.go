@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"math"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/pkg/errors"
@@ -203,11 +204,16 @@ func NewDefaultEvaluator(querier Querier, maxLookBackPeriod time.Duration) *Defa
 }
 
 func (ev *DefaultEvaluator) NewIterator(ctx context.Context, expr syntax.LogSelectorExpr, q Params) (iter.EntryIterator, error) {
+	limit := q.Limit()
+	if selector, ok := q.GetExpression().(syntax.LogSelectorExpr); ok && len(syntax.LineLimits(selector)) > 0 {
+		// limit and dedup stages drop lines once they are returned, the query limit is applied after them.
+		limit = 0
+	}
 	params := SelectLogParams{
 		QueryRequest: &logproto.QueryRequest{
 			Start:     q.Start(),
 			End:       q.End(),
-			Limit:     q.Limit(),
+			Limit:     limit,
 			Direction: q.Direction(),
 			Selector:  expr.String(),
 			Shards:    q.Shards(),
@@ -253,6 +259,19 @@ func (ev *DefaultEvaluator) NewStepEvaluator(
 				return newRangeAggEvaluator(iter.NewPeekingSampleIterator(it), rangExpr, q, rangExpr.Left.Offset)
 			})
 		}
+		switch e.Operation {
+		case syntax.OpTypeApproxTopK:
+			// approx_topk is only approximated when it's sharded. Unsharded,
+			// all series are at hand and the exact topk is cheaper.
+			return newVectorAggEvaluator(ctx, nextEvFactory, &syntax.VectorAggregationExpr{
+				Left:      e.Left,
+				Grouping:  e.Grouping,
+				Params:    e.Params,
+				Operation: syntax.OpTypeTopK,
+			}, q)
+		case syntax.OpTypeTopKSketch:
+			return newTopKSketchEvaluator(ctx, nextEvFactory, e, q)
+		}
 		return newVectorAggEvaluator(ctx, nextEvFactory, e, q)
 	case *syntax.RangeAggregationExpr:
 		it, err := ev.querier.SelectSamples(ctx, SelectSampleParams{
@@ -274,6 +293,14 @@ func (ev *DefaultEvaluator) NewStepEvaluator(
 		return newBinOpStepEvaluator(ctx, nextEvFactory, e, q)
 	case *syntax.LabelReplaceExpr:
 		return newLabelReplaceEvaluator(ctx, nextEvFactory, e, q)
+	case *syntax.SubqueryExpr:
+		return newSubqueryEvaluator(ctx, nextEvFactory, e, q)
+	case *syntax.FunctionExpr:
+		return newFunctionEvaluator(ctx, nextEvFactory, e, q)
+	case *syntax.LabelJoinExpr:
+		return newLabelJoinEvaluator(ctx, nextEvFactory, e, q)
+	case *syntax.HistogramQuantileExpr:
+		return newHistogramQuantileEvaluator(ctx, nextEvFactory, e, q)
 	case *syntax.VectorExpr:
 		val, err := e.Value()
 		if err != nil {
@@ -556,6 +583,17 @@ func newRangeAggEvaluator(
 		return &QuantileSketchStepEvaluator{
 			iter: iter,
 		}, nil
+	case syntax.OpRangeTypeCountDistinctSketch:
+		iter := newCountDistinctSketchIterator(
+			it,
+			expr.Left.Interval.Nanoseconds(),
+			q.Step().Nanoseconds(),
+			q.Start().UnixNano(), q.End().UnixNano(), o.Nanoseconds(),
+		)
+
+		return &CountDistinctSketchStepEvaluator{
+			iter: iter,
+		}, nil
 	default:
 		iter, err := newRangeVectorIterator(
 			it, expr,
@@ -1175,6 +1213,290 @@ func (e *LabelReplaceEvaluator) Error() error {
 	return e.nextEvaluator.Error()
 }
 
+// newFunctionEvaluator returns an evaluator applying a function, such as abs or
+// round, to the value of each sample of the inner expression.
+func newFunctionEvaluator(
+	ctx context.Context,
+	evFactory SampleEvaluatorFactory,
+	expr *syntax.FunctionExpr,
+	q Params,
+) (*FunctionEvaluator, error) {
+	fn, err := vectorFunction(expr.Operation, expr.Params)
+	if err != nil {
+		return nil, err
+	}
+	nextEvaluator, err := evFactory.NewStepEvaluator(ctx, evFactory, expr.Left, q)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FunctionEvaluator{
+		nextEvaluator: nextEvaluator,
+		expr:          expr,
+		fn:            fn,
+	}, nil
+}
+
+// vectorFunction returns the function applied to the value of each sample
+// for the given operation. The timestamp of the sample is in milliseconds.
+func vectorFunction(op string, param *float64) (func(ts int64, v float64) float64, error) {
+	switch op {
+	case syntax.OpFuncAbs:
+		return func(_ int64, v float64) float64 { return math.Abs(v) }, nil
+	case syntax.OpFuncCeil:
+		return func(_ int64, v float64) float64 { return math.Ceil(v) }, nil
+	case syntax.OpFuncFloor:
+		return func(_ int64, v float64) float64 { return math.Floor(v) }, nil
+	case syntax.OpFuncRound:
+		toNearest := 1.
+		if param != nil {
+			toNearest = *param
+		}
+		// Invert as it seems to cause fewer floating point accuracy issues.
+		toNearestInverse := 1.0 / toNearest
+		return func(_ int64, v float64) float64 {
+			return math.Floor(v*toNearestInverse+0.5) / toNearestInverse
+		}, nil
+	case syntax.OpFuncClampMin:
+		if param == nil {
+			return nil, fmt.Errorf("parameter required for function %s", op)
+		}
+		lower := *param
+		return func(_ int64, v float64) float64 { return math.Max(lower, v) }, nil
+	case syntax.OpFuncClampMax:
+		if param == nil {
+			return nil, fmt.Errorf("parameter required for function %s", op)
+		}
+		upper := *param
+		return func(_ int64, v float64) float64 { return math.Min(upper, v) }, nil
+	case syntax.OpFuncSqrt:
+		return func(_ int64, v float64) float64 { return math.Sqrt(v) }, nil
+	case syntax.OpFuncExp:
+		return func(_ int64, v float64) float64 { return math.Exp(v) }, nil
+	case syntax.OpFuncLn:
+		return func(_ int64, v float64) float64 { return math.Log(v) }, nil
+	case syntax.OpFuncTimestamp:
+		return func(ts int64, _ float64) float64 { return float64(ts) / 1e3 }, nil
+	default:
+		return nil, fmt.Errorf(syntax.UnsupportedErr, op)
+	}
+}
+
+type FunctionEvaluator struct {
+	nextEvaluator StepEvaluator
+	expr          *syntax.FunctionExpr
+	fn            func(ts int64, v float64) float64
+}
+
+func (e *FunctionEvaluator) Next() (bool, int64, StepResult) {
+	next, ts, r := e.nextEvaluator.Next()
+	if !next {
+		return false, 0, SampleVector{}
+	}
+	vec := r.SampleVector()
+	for i := range vec {
+		vec[i].F = e.fn(ts, vec[i].F)
+	}
+	return next, ts, SampleVector(vec)
+}
+
+func (e *FunctionEvaluator) Close() error {
+	return e.nextEvaluator.Close()
+}
+
+func (e *FunctionEvaluator) Error() error {
+	return e.nextEvaluator.Error()
+}
+
+// newLabelJoinEvaluator returns an evaluator setting the destination label of
+// each sample to the values of the source labels joined with the separator.
+func newLabelJoinEvaluator(
+	ctx context.Context,
+	evFactory SampleEvaluatorFactory,
+	expr *syntax.LabelJoinExpr,
+	q Params,
+) (*LabelJoinEvaluator, error) {
+	nextEvaluator, err := evFactory.NewStepEvaluator(ctx, evFactory, expr.Left, q)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LabelJoinEvaluator{
+		nextEvaluator: nextEvaluator,
+		expr:          expr,
+		buf:           make([]byte, 0, 1024),
+	}, nil
+}
+
+type LabelJoinEvaluator struct {
+	nextEvaluator StepEvaluator
+	labelCache    map[uint64]labels.Labels
+	expr          *syntax.LabelJoinExpr
+	buf           []byte
+	values        []string
+}
+
+func (e *LabelJoinEvaluator) Next() (bool, int64, StepResult) {
+	next, ts, r := e.nextEvaluator.Next()
+	if !next {
+		return false, 0, SampleVector{}
+	}
+	vec := r.SampleVector()
+	if e.labelCache == nil {
+		e.labelCache = make(map[uint64]labels.Labels, len(vec))
+	}
+	var hash uint64
+	for i, s := range vec {
+		hash, e.buf = s.Metric.HashWithoutLabels(e.buf)
+		if labels, ok := e.labelCache[hash]; ok {
+			vec[i].Metric = labels
+			continue
+		}
+		e.values = e.values[:0]
+		for _, src := range e.expr.Src {
+			e.values = append(e.values, s.Metric.Get(src))
+		}
+		res := strings.Join(e.values, e.expr.Separator)
+
+		lb := labels.NewBuilder(s.Metric).Del(e.expr.Dst)
+		if len(res) > 0 {
+			lb.Set(e.expr.Dst, res)
+		}
+		outLbs := lb.Labels()
+		e.labelCache[hash] = outLbs
+		vec[i].Metric = outLbs
+	}
+	return next, ts, SampleVector(vec)
+}
+
+func (e *LabelJoinEvaluator) Close() error {
+	return e.nextEvaluator.Close()
+}
+
+func (e *LabelJoinEvaluator) Error() error {
+	return e.nextEvaluator.Error()
+}
+
+// defaultSubqueryStep is the resolution of a subquery without explicit
+// resolution when evaluated as part of an instant query.
+const defaultSubqueryStep = time.Minute
+
+// subqueryParams overrides the time range and step of the parent query
+// for the evaluation of the inner expression of a subquery.
+type subqueryParams struct {
+	Params
+	expr       syntax.SampleExpr
+	start, end time.Time
+	step       time.Duration
+}
+
+func (p subqueryParams) Start() time.Time           { return p.start }
+func (p subqueryParams) End() time.Time             { return p.end }
+func (p subqueryParams) Step() time.Duration        { return p.step }
+func (p subqueryParams) GetExpression() syntax.Expr { return p.expr }
+
+// newSubqueryParams returns the params of the inner expression of a subquery.
+// The start of the inner evaluation is aligned to a multiple of the subquery
+// step so that the results are stable across evaluations.
+func newSubqueryParams(expr *syntax.SubqueryExpr, q Params) subqueryParams {
+	step := expr.Step
+	if step == 0 {
+		step = q.Step()
+	}
+	if step == 0 {
+		step = defaultSubqueryStep
+	}
+
+	start := q.Start().Add(-expr.Offset).Add(-expr.Range).UnixNano()
+	aligned := start - start%step.Nanoseconds()
+	if aligned < start {
+		aligned += step.Nanoseconds()
+	}
+
+	return subqueryParams{
+		Params: q,
+		expr:   expr.Left,
+		start:  time.Unix(0, aligned),
+		end:    q.End().Add(-expr.Offset),
+		step:   step,
+	}
+}
+
+// newSubqueryEvaluator evaluates the inner expression of the subquery over
+// the subquery range and applies the range aggregation on its results.
+func newSubqueryEvaluator(
+	ctx context.Context,
+	evFactory SampleEvaluatorFactory,
+	expr *syntax.SubqueryExpr,
+	q Params,
+) (StepEvaluator, error) {
+	params := newSubqueryParams(expr, q)
+	if params.start.After(params.end) {
+		params.start = params.end
+	}
+
+	nextEvaluator, err := evFactory.NewStepEvaluator(ctx, evFactory, expr.Left, params)
+	if err != nil {
+		return nil, err
+	}
+	series, err := collectSeries(nextEvaluator)
+	if err != nil {
+		return nil, err
+	}
+
+	selector, err := expr.Selector()
+	if err != nil {
+		return nil, err
+	}
+	rangeExpr := &syntax.RangeAggregationExpr{
+		Left: &syntax.LogRange{
+			Left:     selector,
+			Interval: expr.Range,
+			Offset:   expr.Offset,
+		},
+		Operation: expr.Operation,
+		Params:    expr.Params,
+	}
+	it := iter.NewPeekingSampleIterator(iter.NewMultiSeriesIterator(series))
+	return newRangeAggEvaluator(it, rangeExpr, q, expr.Offset)
+}
+
+// collectSeries drains the step evaluator into series of samples.
+// The step evaluator is closed once all steps are read.
+func collectSeries(ev StepEvaluator) (res []logproto.Series, err error) {
+	defer func() {
+		if closeErr := ev.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}()
+
+	series := map[string]*logproto.Series{}
+	for next, ts, r := ev.Next(); next; next, ts, r = ev.Next() {
+		for _, s := range r.SampleVector() {
+			lbs := s.Metric.String()
+			cur, ok := series[lbs]
+			if !ok {
+				cur = &logproto.Series{Labels: lbs, StreamHash: s.Metric.Hash()}
+				series[lbs] = cur
+			}
+			cur.Samples = append(cur.Samples, logproto.Sample{
+				Timestamp: time.UnixMilli(ts).UnixNano(),
+				Value:     s.F,
+			})
+		}
+	}
+	if err := ev.Error(); err != nil {
+		return nil, err
+	}
+
+	res = make([]logproto.Series, 0, len(series))
+	for _, s := range series {
+		res = append(res, *s)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Labels < res[j].Labels })
+	return res, nil
+}
+
 // This is to replace missing timeseries during absent_over_time aggregation.
 func absentLabels(expr syntax.SampleExpr) (labels.Labels, error) {
 	m := labels.Labels{}
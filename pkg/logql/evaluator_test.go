@@ -386,3 +386,34 @@ func newReturnVectorEvaluator(vec []float64) *returnVectorEvaluator {
 		vec: pvec,
 	}
 }
+
+func TestVectorFunction(t *testing.T) {
+	param := func(f float64) *float64 { return &f }
+	for _, tc := range []struct {
+		op       string
+		param    *float64
+		ts       int64
+		v        float64
+		expected float64
+	}{
+		{syntax.OpFuncAbs, nil, 0, -2.5, 2.5},
+		{syntax.OpFuncCeil, nil, 0, 1.2, 2},
+		{syntax.OpFuncFloor, nil, 0, 1.8, 1},
+		{syntax.OpFuncRound, nil, 0, 1.5, 2},
+		{syntax.OpFuncRound, param(0.5), 0, 1.3, 1.5},
+		{syntax.OpFuncRound, param(10), 0, 44, 40},
+		{syntax.OpFuncClampMin, param(0), 0, -3, 0},
+		{syntax.OpFuncClampMax, param(10), 0, 42, 10},
+		{syntax.OpFuncSqrt, nil, 0, 16, 4},
+		{syntax.OpFuncExp, nil, 0, 0, 1},
+		{syntax.OpFuncLn, nil, 0, 1, 0},
+		{syntax.OpFuncTimestamp, nil, 60 * 1000, 42, 60},
+	} {
+		fn, err := vectorFunction(tc.op, tc.param)
+		require.NoError(t, err)
+		require.Equal(t, tc.expected, fn(tc.ts, tc.v), tc.op)
+	}
+
+	_, err := vectorFunction(syntax.OpFuncClampMin, nil)
+	require.Error(t, err)
+}
@@ -14,6 +14,21 @@ func (e *LabelReplaceEvaluator) Explain(parent Node) {
 	e.nextEvaluator.Explain(b)
 }
 
+func (e *FunctionEvaluator) Explain(parent Node) {
+	b := parent.Childf("%s Function", e.expr.Operation)
+	e.nextEvaluator.Explain(b)
+}
+
+func (e *LabelJoinEvaluator) Explain(parent Node) {
+	b := parent.Childf("%s LabelJoin", e.expr.Dst)
+	e.nextEvaluator.Explain(b)
+}
+
+func (e *HistogramQuantileEvaluator) Explain(parent Node) {
+	b := parent.Childf("%f HistogramQuantile", e.quantile)
+	e.nextEvaluator.Explain(b)
+}
+
 func (e *VectorAggEvaluator) Explain(parent Node) {
 	b := parent.Childf("[%s, %s] VectorAgg", e.expr.Operation, e.expr.Grouping)
 	e.nextEvaluator.Explain(b)
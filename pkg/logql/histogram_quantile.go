@@ -0,0 +1,187 @@
+package logql
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql"
+
+	"github.com/grafana/loki/pkg/logql/syntax"
+)
+
+// newHistogramQuantileEvaluator returns a step evaluator for histogram_quantile.
+func newHistogramQuantileEvaluator(
+	ctx context.Context,
+	evFactory SampleEvaluatorFactory,
+	expr *syntax.HistogramQuantileExpr,
+	q Params,
+) (*HistogramQuantileEvaluator, error) {
+	nextEvaluator, err := evFactory.NewStepEvaluator(ctx, evFactory, expr.Left, q)
+	if err != nil {
+		return nil, err
+	}
+
+	return &HistogramQuantileEvaluator{
+		nextEvaluator: nextEvaluator,
+		quantile:      expr.Quantile,
+		buf:           make([]byte, 0, 1024),
+	}, nil
+}
+
+// HistogramQuantileEvaluator calculates the quantile of the histograms of
+// each step. The buckets of a histogram are the samples that share the same
+// labels apart from the `le` label.
+// Samples without a valid `le` label are ignored.
+type HistogramQuantileEvaluator struct {
+	nextEvaluator StepEvaluator
+	quantile      float64
+	buf           []byte
+}
+
+type histogram struct {
+	metric  labels.Labels
+	buckets []bucket
+}
+
+type bucket struct {
+	upperBound float64
+	count      float64
+}
+
+func (e *HistogramQuantileEvaluator) Next() (bool, int64, StepResult) {
+	next, ts, r := e.nextEvaluator.Next()
+	if !next {
+		return false, 0, SampleVector{}
+	}
+	vec := r.SampleVector()
+
+	var (
+		hash       uint64
+		order      []uint64
+		histograms = map[uint64]*histogram{}
+	)
+	for _, s := range vec {
+		upperBound, err := strconv.ParseFloat(s.Metric.Get(labels.BucketLabel), 64)
+		if err != nil {
+			continue
+		}
+		hash, e.buf = s.Metric.HashWithoutLabels(e.buf, labels.BucketLabel)
+		h, ok := histograms[hash]
+		if !ok {
+			h = &histogram{
+				metric: labels.NewBuilder(s.Metric).Del(labels.BucketLabel).Labels(),
+			}
+			histograms[hash] = h
+			order = append(order, hash)
+		}
+		h.buckets = append(h.buckets, bucket{upperBound: upperBound, count: s.F})
+	}
+
+	res := make(SampleVector, 0, len(histograms))
+	for _, hash := range order {
+		h := histograms[hash]
+		res = append(res, promql.Sample{
+			T:      ts,
+			F:      bucketQuantile(e.quantile, h.buckets),
+			Metric: h.metric,
+		})
+	}
+	return next, ts, res
+}
+
+func (e *HistogramQuantileEvaluator) Close() error {
+	return e.nextEvaluator.Close()
+}
+
+func (e *HistogramQuantileEvaluator) Error() error {
+	return e.nextEvaluator.Error()
+}
+
+// bucketQuantile calculates the quantile q from the buckets of a histogram
+// the same way Prometheus does for classic histograms.
+// The quantile is interpolated linearly within the bucket it falls into.
+// If it falls into the highest bucket, the upper bound of the second highest
+// bucket is returned.
+// NaN is returned if there are less than two buckets, no observations or no
+// +Inf bucket.
+func bucketQuantile(q float64, buckets []bucket) float64 {
+	if math.IsNaN(q) {
+		return math.NaN()
+	}
+	if q < 0 {
+		return math.Inf(-1)
+	}
+	if q > 1 {
+		return math.Inf(+1)
+	}
+	sort.Slice(buckets, func(i, j int) bool { return buckets[i].upperBound < buckets[j].upperBound })
+	if !math.IsInf(buckets[len(buckets)-1].upperBound, +1) {
+		return math.NaN()
+	}
+
+	buckets = coalesceBuckets(buckets)
+	ensureMonotonic(buckets)
+
+	if len(buckets) < 2 {
+		return math.NaN()
+	}
+	observations := buckets[len(buckets)-1].count
+	if observations == 0 {
+		return math.NaN()
+	}
+	rank := q * observations
+	b := sort.Search(len(buckets)-1, func(i int) bool { return buckets[i].count >= rank })
+
+	if b == len(buckets)-1 {
+		return buckets[len(buckets)-2].upperBound
+	}
+	if b == 0 && buckets[0].upperBound <= 0 {
+		return buckets[0].upperBound
+	}
+	var (
+		bucketStart float64
+		bucketEnd   = buckets[b].upperBound
+		count       = buckets[b].count
+	)
+	if b > 0 {
+		bucketStart = buckets[b-1].upperBound
+		count -= buckets[b-1].count
+		rank -= buckets[b-1].count
+	}
+	return bucketStart + (bucketEnd-bucketStart)*(rank/count)
+}
+
+// coalesceBuckets merges buckets with the same upper bound, which happens
+// for example when the `le` label values are formatted differently.
+// The buckets must be sorted by upper bound.
+func coalesceBuckets(buckets []bucket) []bucket {
+	last := buckets[0]
+	i := 0
+	for _, b := range buckets[1:] {
+		if b.upperBound == last.upperBound {
+			last.count += b.count
+		} else {
+			buckets[i] = last
+			last = b
+			i++
+		}
+	}
+	buckets[i] = last
+	return buckets[:i+1]
+}
+
+// ensureMonotonic removes decreases of the count between successive buckets,
+// since the binary search in bucketQuantile relies on increasing counts.
+func ensureMonotonic(buckets []bucket) {
+	maxCount := math.Inf(-1)
+	for i := range buckets {
+		if buckets[i].count > maxCount {
+			maxCount = buckets[i].count
+		} else {
+			buckets[i].count = maxCount
+		}
+	}
+}
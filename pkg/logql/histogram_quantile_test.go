@@ -0,0 +1,75 @@
+package logql
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBucketQuantile(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		q        float64
+		buckets  []bucket
+		expected float64
+	}{
+		{
+			name:     "interpolates within bucket",
+			q:        0.5,
+			buckets:  []bucket{{1, 3}, {5, 6}, {math.Inf(+1), 12}},
+			expected: 5,
+		},
+		{
+			name:     "unsorted buckets",
+			q:        0.25,
+			buckets:  []bucket{{math.Inf(+1), 12}, {5, 6}, {1, 3}},
+			expected: 1,
+		},
+		{
+			name:     "highest bucket returns second highest upper bound",
+			q:        0.9,
+			buckets:  []bucket{{1, 3}, {5, 6}, {math.Inf(+1), 12}},
+			expected: 5,
+		},
+		{
+			name:     "non monotonic counts",
+			q:        0.5,
+			buckets:  []bucket{{1, 4}, {2, 2}, {4, 8}, {math.Inf(+1), 8}},
+			expected: 1,
+		},
+		{
+			name:     "no +Inf bucket",
+			q:        0.5,
+			buckets:  []bucket{{1, 3}, {5, 6}},
+			expected: math.NaN(),
+		},
+		{
+			name:     "no observations",
+			q:        0.5,
+			buckets:  []bucket{{1, 0}, {math.Inf(+1), 0}},
+			expected: math.NaN(),
+		},
+		{
+			name:     "quantile below 0",
+			q:        -1,
+			buckets:  []bucket{{1, 3}, {math.Inf(+1), 6}},
+			expected: math.Inf(-1),
+		},
+		{
+			name:     "quantile above 1",
+			q:        2,
+			buckets:  []bucket{{1, 3}, {math.Inf(+1), 6}},
+			expected: math.Inf(+1),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := bucketQuantile(tc.q, tc.buckets)
+			if math.IsNaN(tc.expected) {
+				require.True(t, math.IsNaN(actual))
+				return
+			}
+			require.Equal(t, tc.expected, actual)
+		})
+	}
+}
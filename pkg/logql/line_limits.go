@@ -0,0 +1,172 @@
+package logql
+
+import (
+	"fmt"
+
+	"github.com/cespare/xxhash/v2"
+	"github.com/prometheus/prometheus/model/labels"
+
+	"github.com/grafana/loki/pkg/iter"
+	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/logql/log"
+	"github.com/grafana/loki/pkg/logql/syntax"
+)
+
+// splitLineLimits returns the expression to evaluate for a log query and the limit and dedup stages
+// to apply on its merged results.
+// The stages of downstream expressions are kept since they are also applied by each downstream query.
+func splitLineLimits(expr syntax.LogSelectorExpr) (syntax.LogSelectorExpr, syntax.MultiStageExpr) {
+	if selector, limits := syntax.SplitLineLimits(expr); len(limits) > 0 {
+		return selector, limits
+	}
+	return expr, syntax.LineLimits(expr)
+}
+
+type lineLimitsIterator struct {
+	iter.EntryIterator
+	stages []log.Stage
+
+	// stream labels of the current entry by their string representation.
+	labels    map[string]labels.Labels
+	curLabels labels.Labels
+	curEntry  logproto.Entry
+	getFn     func(name string) (string, bool)
+	err       error
+}
+
+// NewLineLimitsIterator applies the limit and dedup stages of a log query to an iterator.
+// The entries of the iterator must be ordered in the direction of the query.
+func NewLineLimitsIterator(it iter.EntryIterator, limits syntax.MultiStageExpr) (iter.EntryIterator, error) {
+	if len(limits) == 0 {
+		return it, nil
+	}
+	stages := make([]log.Stage, 0, len(limits))
+	for _, l := range limits {
+		s, err := l.Stage()
+		if err != nil {
+			return nil, err
+		}
+		stages = append(stages, s)
+	}
+	i := &lineLimitsIterator{
+		EntryIterator: it,
+		stages:        stages,
+		labels:        map[string]labels.Labels{},
+	}
+	i.getFn = i.get
+	return i, nil
+}
+
+func (i *lineLimitsIterator) Next() bool {
+	for i.EntryIterator.Next() {
+		ok, err := i.allow(i.EntryIterator.Labels(), i.EntryIterator.Entry())
+		if err != nil {
+			i.err = err
+			return false
+		}
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (i *lineLimitsIterator) allow(stream string, entry logproto.Entry) (bool, error) {
+	i.curEntry = entry
+	i.curLabels = nil
+	for _, s := range i.stages {
+		switch s := s.(type) {
+		case *log.LineLimiter:
+			if !s.Allow(xxhash.Sum64String(stream)) {
+				return false, nil
+			}
+		case *log.LineDeduper:
+			lbs, err := i.streamLabels(stream)
+			if err != nil {
+				return false, err
+			}
+			i.curLabels = lbs
+			if !s.Allow(entry.Timestamp.UnixNano(), s.Key([]byte(entry.Line), i.getFn)) {
+				return false, nil
+			}
+		default:
+			return false, fmt.Errorf("unexpected line limit stage %T", s)
+		}
+	}
+	return true, nil
+}
+
+func (i *lineLimitsIterator) streamLabels(stream string) (labels.Labels, error) {
+	if lbs, ok := i.labels[stream]; ok {
+		return lbs, nil
+	}
+	lbs, err := syntax.ParseLabels(stream)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse stream labels: %w", err)
+	}
+	i.labels[stream] = lbs
+	return lbs, nil
+}
+
+// get returns the value of a label of the current entry.
+// When labels are categorized, structured metadata and parsed labels are not part of the stream labels.
+func (i *lineLimitsIterator) get(name string) (string, bool) {
+	if i.curLabels.Has(name) {
+		return i.curLabels.Get(name), true
+	}
+	for _, l := range i.curEntry.StructuredMetadata {
+		if l.Name == name {
+			return l.Value, true
+		}
+	}
+	for _, l := range i.curEntry.Parsed {
+		if l.Name == name {
+			return l.Value, true
+		}
+	}
+	return "", false
+}
+
+func (i *lineLimitsIterator) Error() error {
+	if i.err != nil {
+		return i.err
+	}
+	return i.EntryIterator.Error()
+}
+
+// ApplyLineLimits applies the limit and dedup stages of a log query to streams merged from multiple results,
+// since each result only applied them to its own lines. The order of the streams is preserved.
+func ApplyLineLimits(limits syntax.MultiStageExpr, streams []logproto.Stream, direction logproto.Direction) ([]logproto.Stream, error) {
+	if len(limits) == 0 {
+		return streams, nil
+	}
+	it, err := NewLineLimitsIterator(iter.NewStreamsIterator(streams, direction), limits)
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+
+	index := make(map[string]int, len(streams))
+	result := make([]logproto.Stream, len(streams))
+	for i, s := range streams {
+		if _, ok := index[s.Labels]; !ok {
+			index[s.Labels] = i
+		}
+		result[i] = logproto.Stream{Labels: s.Labels, Hash: s.Hash}
+	}
+	for it.Next() {
+		i := index[it.Labels()]
+		result[i].Entries = append(result[i].Entries, it.Entry())
+	}
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+
+	filtered := result[:0]
+	for _, s := range result {
+		if len(s.Entries) > 0 {
+			filtered = append(filtered, s)
+		}
+	}
+	return filtered, nil
+}
@@ -0,0 +1,182 @@
+package logql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/user"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/pkg/iter"
+	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/logql/syntax"
+	"github.com/grafana/loki/pkg/logqlmodel"
+	"github.com/grafana/loki/pkg/querier/astmapper"
+)
+
+func TestApplyLineLimits(t *testing.T) {
+	entry := func(sec int64, line string, parsed ...logproto.LabelAdapter) logproto.Entry {
+		return logproto.Entry{Timestamp: time.Unix(sec, 0), Line: line, Parsed: parsed}
+	}
+
+	// streams merged from two responses, each one applied the stages to its own lines.
+	streams := []logproto.Stream{
+		{
+			Labels: `{app="foo", pod="a"}`,
+			Entries: []logproto.Entry{
+				entry(1, "a"),
+				entry(2, "b"),
+				entry(61, "a"),
+				entry(62, "c"),
+			},
+		},
+		{
+			Labels: `{app="foo", pod="b"}`,
+			Entries: []logproto.Entry{
+				entry(3, "a", logproto.LabelAdapter{Name: "request_id", Value: "1"}),
+				entry(4, "b", logproto.LabelAdapter{Name: "request_id", Value: "2"}),
+			},
+		},
+	}
+
+	// streams are ordered in the direction of the query.
+	backward := make([]logproto.Stream, len(streams))
+	for i, s := range streams {
+		backward[i] = logproto.Stream{Labels: s.Labels}
+		for j := len(s.Entries) - 1; j >= 0; j-- {
+			backward[i].Entries = append(backward[i].Entries, s.Entries[j])
+		}
+	}
+
+	for _, tc := range []struct {
+		query     string
+		direction logproto.Direction
+		want      []logproto.Stream
+	}{
+		{
+			query:     `{app="foo"}`,
+			direction: logproto.FORWARD,
+			want:      streams,
+		},
+		{
+			query:     `{app="foo"} | limit 1`,
+			direction: logproto.FORWARD,
+			want: []logproto.Stream{
+				{Labels: `{app="foo", pod="a"}`, Entries: []logproto.Entry{entry(1, "a")}},
+				{Labels: `{app="foo", pod="b"}`, Entries: []logproto.Entry{entry(3, "a", logproto.LabelAdapter{Name: "request_id", Value: "1"})}},
+			},
+		},
+		{
+			query:     `{app="foo"} | limit 1`,
+			direction: logproto.BACKWARD,
+			want: []logproto.Stream{
+				{Labels: `{app="foo", pod="a"}`, Entries: []logproto.Entry{entry(62, "c")}},
+				{Labels: `{app="foo", pod="b"}`, Entries: []logproto.Entry{entry(4, "b", logproto.LabelAdapter{Name: "request_id", Value: "2"})}},
+			},
+		},
+		{
+			query:     `{app="foo"} | dedup 1m`,
+			direction: logproto.FORWARD,
+			want: []logproto.Stream{
+				{Labels: `{app="foo", pod="a"}`, Entries: []logproto.Entry{entry(1, "a"), entry(2, "b"), entry(61, "a"), entry(62, "c")}},
+			},
+		},
+		{
+			query:     `{app="foo"} | dedup by (app, request_id) | limit 1`,
+			direction: logproto.FORWARD,
+			want: []logproto.Stream{
+				{Labels: `{app="foo", pod="a"}`, Entries: []logproto.Entry{entry(1, "a")}},
+				{Labels: `{app="foo", pod="b"}`, Entries: []logproto.Entry{entry(3, "a", logproto.LabelAdapter{Name: "request_id", Value: "1"})}},
+			},
+		},
+	} {
+		t.Run(tc.query, func(t *testing.T) {
+			expr, err := syntax.ParseLogSelector(tc.query, true)
+			require.NoError(t, err)
+
+			in := streams
+			if tc.direction == logproto.BACKWARD {
+				in = backward
+			}
+			got, err := ApplyLineLimits(syntax.LineLimits(expr), in, tc.direction)
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestSplitLineLimits_Downstream(t *testing.T) {
+	expr, err := syntax.ParseLogSelector(`{app="foo"} |= "bar" | dedup by (pod)`, true)
+	require.NoError(t, err)
+
+	selector, limits := splitLineLimits(expr)
+	require.Equal(t, `{app="foo"} |= "bar"`, selector.String())
+	require.Equal(t, `| dedup by (pod)`, limits.String())
+
+	// downstream queries apply the stages themselves, they are applied again on the merged results.
+	downstream := &ConcatLogSelectorExpr{
+		DownstreamLogSelectorExpr: DownstreamLogSelectorExpr{
+			shard:           NewPowerOfTwoShard(astmapper.ShardAnnotation{Shard: 0, Of: 2}).Ptr(),
+			LogSelectorExpr: expr,
+		},
+	}
+	selector, limits = splitLineLimits(downstream)
+	require.Equal(t, downstream, selector)
+	require.Equal(t, `| dedup by (pod)`, limits.String())
+}
+
+// limitedQuerier returns at most the limit of the request like ingesters do, a zero limit returning every line.
+type limitedQuerier struct {
+	streams []logproto.Stream
+}
+
+func (q limitedQuerier) SelectLogs(_ context.Context, p SelectLogParams) (iter.EntryIterator, error) {
+	var (
+		result []logproto.Stream
+		count  uint32
+	)
+	for _, s := range q.streams {
+		out := logproto.Stream{Labels: s.Labels}
+		for _, e := range s.Entries {
+			if p.Limit > 0 && count >= p.Limit {
+				break
+			}
+			out.Entries = append(out.Entries, e)
+			count++
+		}
+		result = append(result, out)
+	}
+	return iter.NewStreamsIterator(result, p.Direction), nil
+}
+
+func (limitedQuerier) SelectSamples(_ context.Context, _ SelectSampleParams) (iter.SampleIterator, error) {
+	return nil, errors.New("not implemented")
+}
+
+func TestEngine_LineLimitsDuplicatesFillFirstPage(t *testing.T) {
+	stream := logproto.Stream{Labels: `{app="foo"}`}
+	for i := 0; i < 100; i++ {
+		stream.Entries = append(stream.Entries, logproto.Entry{Timestamp: time.Unix(int64(i), 0), Line: "duplicate"})
+	}
+	for i := 100; i < 200; i++ {
+		stream.Entries = append(stream.Entries, logproto.Entry{Timestamp: time.Unix(int64(i), 0), Line: fmt.Sprintf("line %d", i)})
+	}
+
+	eng := NewEngine(EngineOpts{}, limitedQuerier{streams: []logproto.Stream{stream}}, NoLimits, log.NewNopLogger())
+	params, err := NewLiteralParams(`{app="foo"} | dedup`, time.Unix(0, 0), time.Unix(200, 0), 0, 0, logproto.FORWARD, 100, nil)
+	require.NoError(t, err)
+
+	res, err := eng.Query(params).Exec(user.InjectOrgID(context.Background(), "fake"))
+	require.NoError(t, err)
+
+	streams := res.Data.(logqlmodel.Streams)
+	require.Len(t, streams, 1)
+	require.Len(t, streams[0].Entries, 100)
+	require.Equal(t, "duplicate", streams[0].Entries[0].Line)
+	require.Equal(t, "line 100", streams[0].Entries[1].Line)
+	require.Equal(t, "line 198", streams[0].Entries[99].Line)
+}
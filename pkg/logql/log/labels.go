@@ -324,6 +324,21 @@ func (b *LabelsBuilder) Get(key string) (string, bool) {
 	return v, ok
 }
 
+// GetStructuredMetadata returns the value of a structured metadata label if it exists.
+// The key is the name of the label as ingested, before any suffix is added for conflicting with a stream label.
+func (b *LabelsBuilder) GetStructuredMetadata(key string) (string, bool) {
+	b.referencedStructuredMetadata = true
+	if b.BaseHas(key) {
+		key = key + duplicateSuffix
+	}
+	for _, l := range b.add[StructuredMetadataLabel] {
+		if l.Name == key {
+			return l.Value, true
+		}
+	}
+	return "", false
+}
+
 // Del deletes the label of the given name.
 func (b *LabelsBuilder) Del(ns ...string) *LabelsBuilder {
 	for _, n := range ns {
@@ -9,6 +9,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/prometheus/prometheus/model/labels"
 
+	"github.com/cespare/xxhash/v2"
 	"github.com/dustin/go-humanize"
 )
 
@@ -16,6 +17,7 @@ const (
 	ConvertBytes    = "bytes"
 	ConvertDuration = "duration"
 	ConvertFloat    = "float"
+	ConvertHash     = "hash"
 )
 
 // LineExtractor extracts a float64 from a log line.
@@ -50,8 +52,9 @@ type lineSampleExtractor struct {
 	Stage
 	LineExtractor
 
-	baseBuilder      *BaseLabelsBuilder
-	streamExtractors map[uint64]StreamSampleExtractor
+	baseBuilder                *BaseLabelsBuilder
+	streamExtractors           map[uint64]StreamSampleExtractor
+	structuredMetadataMatchers []*labels.Matcher
 }
 
 // NewLineSampleExtractor creates a SampleExtractor from a LineExtractor.
@@ -60,10 +63,11 @@ func NewLineSampleExtractor(ex LineExtractor, stages []Stage, groups []string, w
 	s := ReduceStages(stages)
 	hints := NewParserHint(s.RequiredLabelNames(), groups, without, noLabels, "", stages)
 	return &lineSampleExtractor{
-		Stage:            s,
-		LineExtractor:    ex,
-		baseBuilder:      NewBaseLabelsBuilderWithGrouping(groups, hints, without, noLabels),
-		streamExtractors: make(map[uint64]StreamSampleExtractor),
+		Stage:                      s,
+		LineExtractor:              ex,
+		baseBuilder:                NewBaseLabelsBuilderWithGrouping(groups, hints, without, noLabels),
+		streamExtractors:           make(map[uint64]StreamSampleExtractor),
+		structuredMetadataMatchers: structuredMetadataMatchers(stages),
 	}, nil
 }
 
@@ -74,9 +78,10 @@ func (l *lineSampleExtractor) ForStream(labels labels.Labels) StreamSampleExtrac
 	}
 
 	res := &streamLineSampleExtractor{
-		Stage:         l.Stage,
-		LineExtractor: l.LineExtractor,
-		builder:       l.baseBuilder.ForLabels(labels, hash),
+		Stage:                      l.Stage,
+		LineExtractor:              l.LineExtractor,
+		builder:                    l.baseBuilder.ForLabels(labels, hash),
+		structuredMetadataMatchers: l.structuredMetadataMatchers,
 	}
 	l.streamExtractors[hash] = res
 	return res
@@ -86,6 +91,8 @@ type streamLineSampleExtractor struct {
 	Stage
 	LineExtractor
 	builder *LabelsBuilder
+
+	structuredMetadataMatchers []*labels.Matcher
 }
 
 func (l *streamLineSampleExtractor) ReferencedStructuredMetadata() bool {
@@ -115,6 +122,10 @@ func (l *streamLineSampleExtractor) ProcessString(ts int64, line string, structu
 
 func (l *streamLineSampleExtractor) BaseLabels() LabelsResult { return l.builder.currentResult }
 
+func (l *streamLineSampleExtractor) StructuredMetadataMatchers() []*labels.Matcher {
+	return l.structuredMetadataMatchers
+}
+
 type convertionFn func(value string) (float64, error)
 
 type labelSampleExtractor struct {
@@ -123,8 +134,9 @@ type labelSampleExtractor struct {
 	labelName    string
 	conversionFn convertionFn
 
-	baseBuilder      *BaseLabelsBuilder
-	streamExtractors map[uint64]StreamSampleExtractor
+	baseBuilder                *BaseLabelsBuilder
+	streamExtractors           map[uint64]StreamSampleExtractor
+	structuredMetadataMatchers []*labels.Matcher
 }
 
 // LabelExtractorWithStages creates a SampleExtractor that will extract metrics from a labels.
@@ -144,6 +156,8 @@ func LabelExtractorWithStages(
 		convFn = convertDuration
 	case ConvertFloat:
 		convFn = convertFloat
+	case ConvertHash:
+		convFn = convertHash
 	default:
 		return nil, errors.Errorf("unsupported conversion operation %s", conversion)
 	}
@@ -155,12 +169,13 @@ func LabelExtractorWithStages(
 	preStage := ReduceStages(preStages)
 	hints := NewParserHint(append(preStage.RequiredLabelNames(), postFilter.RequiredLabelNames()...), groups, without, noLabels, labelName, append(preStages, postFilter))
 	return &labelSampleExtractor{
-		preStage:         preStage,
-		conversionFn:     convFn,
-		labelName:        labelName,
-		postFilter:       postFilter,
-		baseBuilder:      NewBaseLabelsBuilderWithGrouping(groups, hints, without, noLabels),
-		streamExtractors: make(map[uint64]StreamSampleExtractor),
+		preStage:                   preStage,
+		conversionFn:               convFn,
+		labelName:                  labelName,
+		postFilter:                 postFilter,
+		baseBuilder:                NewBaseLabelsBuilderWithGrouping(groups, hints, without, noLabels),
+		streamExtractors:           make(map[uint64]StreamSampleExtractor),
+		structuredMetadataMatchers: structuredMetadataMatchers(preStages),
 	}, nil
 }
 
@@ -225,6 +240,10 @@ func (l *streamLabelSampleExtractor) ProcessString(ts int64, line string, struct
 
 func (l *streamLabelSampleExtractor) BaseLabels() LabelsResult { return l.builder.currentResult }
 
+func (l *streamLabelSampleExtractor) StructuredMetadataMatchers() []*labels.Matcher {
+	return l.structuredMetadataMatchers
+}
+
 // NewFilteringSampleExtractor creates a sample extractor where entries from
 // the underlying log stream are filtered by pipeline filters before being
 // passed to extract samples. Filters are always upstream of the extractor.
@@ -271,6 +290,10 @@ func (sp *filteringStreamExtractor) BaseLabels() LabelsResult {
 	return sp.extractor.BaseLabels()
 }
 
+func (sp *filteringStreamExtractor) StructuredMetadataMatchers() []*labels.Matcher {
+	return StructuredMetadataMatchers(sp.extractor)
+}
+
 func (sp *filteringStreamExtractor) Process(ts int64, line []byte, structuredMetadata ...labels.Label) (float64, LabelsResult, bool) {
 	for _, filter := range sp.filters {
 		if ts < filter.start || ts > filter.end {
@@ -283,7 +306,7 @@ func (sp *filteringStreamExtractor) Process(ts int64, line []byte, structuredMet
 		}
 	}
 
-	return sp.extractor.Process(ts, line)
+	return sp.extractor.Process(ts, line, structuredMetadata...)
 }
 
 func (sp *filteringStreamExtractor) ProcessString(ts int64, line string, structuredMetadata ...labels.Label) (float64, LabelsResult, bool) {
@@ -298,7 +321,7 @@ func (sp *filteringStreamExtractor) ProcessString(ts int64, line string, structu
 		}
 	}
 
-	return sp.extractor.ProcessString(ts, line)
+	return sp.extractor.ProcessString(ts, line, structuredMetadata...)
 }
 
 func convertFloat(v string) (float64, error) {
@@ -320,3 +343,10 @@ func convertBytes(v string) (float64, error) {
 	}
 	return float64(b), nil
 }
+
+// convertHash maps a label value to a number that identifies it, which allows
+// to count distinct values with the sample pipeline.
+// The hash is truncated to 53 bits so it is exactly representable as float64.
+func convertHash(v string) (float64, error) {
+	return float64(xxhash.Sum64String(v) >> 11), nil
+}
@@ -4,6 +4,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/cespare/xxhash/v2"
 	"github.com/prometheus/prometheus/model/labels"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -30,6 +31,16 @@ func Test_labelSampleExtractor_Extract(t *testing.T) {
 			wantLbs: labels.EmptyLabels(),
 			wantOk:  true,
 		},
+		{
+			name: "convert hash",
+			ex: mustSampleExtractor(LabelExtractorWithStages(
+				"foo", ConvertHash, []string{"bar"}, false, false, nil, NoopStage,
+			)),
+			in:      labels.FromStrings("foo", "user-1", "bar", "buzz"),
+			want:    float64(xxhash.Sum64String("user-1") >> 11),
+			wantLbs: labels.FromStrings("bar", "buzz"),
+			wantOk:  true,
+		},
 		{
 			name: "convert float as vector with no grouping",
 			ex: mustSampleExtractor(LabelExtractorWithStages(
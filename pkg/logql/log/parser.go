@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"strconv"
 	"unicode/utf8"
 
 	"github.com/grafana/jsonparser"
@@ -32,6 +33,10 @@ var (
 	_ Stage = &JSONParser{}
 	_ Stage = &RegexpParser{}
 	_ Stage = &LogfmtParser{}
+	_ Stage = &CSVParser{}
+	_ Stage = &KVParser{}
+	_ Stage = &XMLParser{}
+	_ Stage = &XMLExpressionParser{}
 
 	trueBytes = []byte("true")
 
@@ -757,3 +762,220 @@ func (u *UnpackParser) unpack(entry []byte, lbs *LabelsBuilder) ([]byte, error)
 	}
 	return entry, nil
 }
+
+type CSVParser struct {
+	columns   []string
+	separator byte
+	value     []byte // buffer used to unescape quoted fields
+	keys      internedStringSet
+}
+
+// NewCSVParser creates a parser that extracts the columns of a csv log line as labels.
+// Columns are matched by position with the given names, an empty name skips the column.
+// Fields can be quoted with `"` to contain the separator, a quote inside a quoted field is escaped by doubling it.
+func NewCSVParser(columns []string, separator rune) (*CSVParser, error) {
+	if separator == '"' || separator >= utf8.RuneSelf || separator == '\r' || separator == '\n' {
+		return nil, fmt.Errorf("invalid csv separator %q", separator)
+	}
+	uniqueNames := map[string]struct{}{}
+	for _, c := range columns {
+		if c == "" {
+			continue
+		}
+		if !model.LabelName(c).IsValid() {
+			return nil, fmt.Errorf("invalid extracted label name '%s'", c)
+		}
+		if _, ok := uniqueNames[c]; ok {
+			return nil, fmt.Errorf("duplicate extracted label name '%s'", c)
+		}
+		uniqueNames[c] = struct{}{}
+	}
+	if len(uniqueNames) == 0 {
+		return nil, errors.New("at least one column must be named")
+	}
+	return &CSVParser{
+		columns:   columns,
+		separator: byte(separator),
+		keys:      internedStringSet{},
+	}, nil
+}
+
+func (c *CSVParser) Process(_ int64, line []byte, lbs *LabelsBuilder) ([]byte, bool) {
+	parserHints := lbs.ParserLabelHints()
+	if parserHints.NoLabels() {
+		return line, true
+	}
+
+	rest := bytes.TrimRight(line, "\r\n")
+	for i := 0; i < len(c.columns) && rest != nil; i++ {
+		var (
+			value []byte
+			err   error
+		)
+		value, rest, err = c.nextField(rest)
+		if err != nil {
+			addErrLabel(errCSV, err, lbs)
+			return line, parserHints.ShouldContinueParsingLine(logqlmodel.ErrorLabel, lbs)
+		}
+
+		name := c.columns[i]
+		if name == "" {
+			continue
+		}
+		key, ok := c.keys.Get(unsafeGetBytes(name), func() (string, bool) {
+			if lbs.BaseHas(name) {
+				name = name + duplicateSuffix
+			}
+			if !parserHints.ShouldExtract(name) {
+				return "", false
+			}
+			return name, true
+		})
+		if !ok {
+			continue
+		}
+
+		lbs.Set(ParsedLabel, key, string(value))
+		if !parserHints.ShouldContinueParsingLine(key, lbs) {
+			return line, false
+		}
+		if parserHints.AllRequiredExtracted() {
+			break
+		}
+	}
+	return line, true
+}
+
+// nextField returns the value of the first field of line and the remaining fields.
+// The remaining fields are nil when the line has no more fields.
+func (c *CSVParser) nextField(line []byte) ([]byte, []byte, error) {
+	if len(line) == 0 || line[0] != '"' {
+		if i := bytes.IndexByte(line, c.separator); i >= 0 {
+			return line[:i], line[i+1:], nil
+		}
+		return line, nil, nil
+	}
+
+	c.value = c.value[:0]
+	for i := 1; i < len(line); i++ {
+		if line[i] != '"' {
+			c.value = append(c.value, line[i])
+			continue
+		}
+		switch {
+		case i+1 == len(line):
+			return c.value, nil, nil
+		case line[i+1] == '"':
+			c.value = append(c.value, '"')
+			i++
+		case line[i+1] == c.separator:
+			return c.value, line[i+2:], nil
+		default:
+			return nil, nil, fmt.Errorf("extraneous %q in quoted field at position %d", line[i+1], i+1)
+		}
+	}
+	return nil, nil, errors.New("unterminated quoted field")
+}
+
+func (c *CSVParser) RequiredLabelNames() []string { return []string{} }
+
+type KVParser struct {
+	separator   []byte
+	kvSeparator []byte
+	keys        internedStringSet
+}
+
+// NewKVParser creates a parser that extracts the key/value pairs of a log line as labels.
+// Pairs are delimited by the separator and keys are delimited from their values by the kv separator.
+// Values can be quoted with `"` to contain any of the separators.
+func NewKVParser(separator, kvSeparator string) (*KVParser, error) {
+	if separator == "" || kvSeparator == "" {
+		return nil, errors.New("kv separators cannot be empty")
+	}
+	if separator == kvSeparator {
+		return nil, fmt.Errorf("kv separators must be different, both are %q", separator)
+	}
+	return &KVParser{
+		separator:   []byte(separator),
+		kvSeparator: []byte(kvSeparator),
+		keys:        internedStringSet{},
+	}, nil
+}
+
+func (k *KVParser) Process(_ int64, line []byte, lbs *LabelsBuilder) ([]byte, bool) {
+	parserHints := lbs.ParserLabelHints()
+	if parserHints.NoLabels() {
+		return line, true
+	}
+
+	rest := line
+	for len(rest) > 0 {
+		var pair []byte
+		pair, rest = k.nextPair(rest)
+
+		i := bytes.Index(pair, k.kvSeparator)
+		if i < 0 {
+			continue
+		}
+		rawKey := bytes.TrimSpace(pair[:i])
+		key, ok := k.keys.Get(rawKey, func() (string, bool) {
+			sanitized := sanitizeLabelKey(string(rawKey), true)
+			if len(sanitized) == 0 {
+				return "", false
+			}
+			if lbs.BaseHas(sanitized) {
+				sanitized = sanitized + duplicateSuffix
+			}
+			if !parserHints.ShouldExtract(sanitized) {
+				return "", false
+			}
+			return sanitized, true
+		})
+		if !ok {
+			continue
+		}
+
+		val := unquoteKVValue(bytes.TrimSpace(pair[i+len(k.kvSeparator):]))
+		if len(val) == 0 || bytes.ContainsRune(val, utf8.RuneError) {
+			continue
+		}
+
+		lbs.Set(ParsedLabel, key, string(val))
+		if !parserHints.ShouldContinueParsingLine(key, lbs) {
+			return line, false
+		}
+		if parserHints.AllRequiredExtracted() {
+			break
+		}
+	}
+	return line, true
+}
+
+// nextPair returns the first pair of line and the remaining pairs.
+// Separators between double quotes don't delimit pairs.
+func (k *KVParser) nextPair(line []byte) ([]byte, []byte) {
+	var quoted bool
+	for i := 0; i < len(line); i++ {
+		switch {
+		case line[i] == '\\' && quoted:
+			i++
+		case line[i] == '"':
+			quoted = !quoted
+		case !quoted && bytes.HasPrefix(line[i:], k.separator):
+			return line[:i], line[i+len(k.separator):]
+		}
+	}
+	return line, nil
+}
+
+func unquoteKVValue(v []byte) []byte {
+	if len(v) < 2 || v[0] != '"' || v[len(v)-1] != '"' {
+		return v
+	}
+	if s, err := strconv.Unquote(unsafeGetString(v)); err == nil {
+		return []byte(s)
+	}
+	return v[1 : len(v)-1]
+}
+
+func (k *KVParser) RequiredLabelNames() []string { return []string{} }
@@ -180,6 +180,8 @@ func TestLabelShortCircuit(t *testing.T) {
 		{"json", NewJSONParser(), simpleJsn},
 		{"logfmt", NewLogfmtParser(false, false), logFmt},
 		{"logfmt-expression", mustStage(NewLogfmtExpressionParser([]LabelExtractionExpr{NewLabelExtractionExpr("name", "name")}, false)), logFmt},
+		{"kv", mustStage(NewKVParser(";", ":")), []byte(`data:"Click Here";size:36;name:text1;name:duplicate`)},
+		{"xml-expression", mustStage(NewXMLExpressionParser([]LabelExtractionExpr{NewLabelExtractionExpr("name", "text/name")})), []byte(`<text><data>Click Here</data><name>text1</name><name>duplicate</name></text>`)},
 	}
 	for _, tt := range tests {
 		lbs.Reset()
@@ -1371,6 +1373,242 @@ func Test_PatternParser(t *testing.T) {
 	}
 }
 
+func TestNewCSVParser(t *testing.T) {
+	tests := []struct {
+		columns   []string
+		separator rune
+		err       bool
+	}{
+		{[]string{"a", "b"}, ',', false},
+		{[]string{"a", "", "b"}, ';', false},
+		{[]string{""}, ',', true},
+		{[]string{"a", "a"}, ',', true},
+		{[]string{"1a"}, ',', true},
+		{[]string{"a"}, '"', true},
+		{[]string{"a"}, '\n', true},
+		{[]string{"a"}, '€', true},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%v %q", tt.columns, tt.separator), func(t *testing.T) {
+			_, err := NewCSVParser(tt.columns, tt.separator)
+			if tt.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func Test_csvParser_Parse(t *testing.T) {
+	tests := []struct {
+		name      string
+		columns   []string
+		separator rune
+		line      []byte
+		lbs       labels.Labels
+		want      labels.Labels
+	}{
+		{
+			"simple",
+			[]string{"ts", "level", "msg"},
+			',',
+			[]byte("2023-01-01,info,started"),
+			labels.FromStrings("app", "foo"),
+			labels.FromStrings("app", "foo",
+				"ts", "2023-01-01",
+				"level", "info",
+				"msg", "started",
+			),
+		},
+		{
+			"skipped and missing columns",
+			[]string{"", "level", "msg", "extra"},
+			',',
+			[]byte("2023-01-01,info,started\r\n"),
+			labels.FromStrings("app", "foo"),
+			labels.FromStrings("app", "foo",
+				"level", "info",
+				"msg", "started",
+			),
+		},
+		{
+			"extra columns",
+			[]string{"level"},
+			';',
+			[]byte("warn;disk full;sda1"),
+			labels.FromStrings("app", "foo"),
+			labels.FromStrings("app", "foo",
+				"level", "warn",
+			),
+		},
+		{
+			"quoted fields",
+			[]string{"user", "msg", "status"},
+			',',
+			[]byte(`"doe, john","said ""hi""",200`),
+			labels.FromStrings("app", "foo"),
+			labels.FromStrings("app", "foo",
+				"user", "doe, john",
+				"msg", `said "hi"`,
+				"status", "200",
+			),
+		},
+		{
+			"empty fields",
+			[]string{"a", "b", "c"},
+			',',
+			[]byte(`,"",c`),
+			labels.FromStrings("app", "foo"),
+			labels.FromStrings("app", "foo",
+				"a", "",
+				"b", "",
+				"c", "c",
+			),
+		},
+		{
+			"duplicate stream label",
+			[]string{"app"},
+			',',
+			[]byte(`bar`),
+			labels.FromStrings("app", "foo"),
+			labels.FromStrings("app", "foo",
+				"app_extracted", "bar",
+			),
+		},
+		{
+			"unterminated quote",
+			[]string{"a", "b"},
+			',',
+			[]byte(`a,"b`),
+			labels.FromStrings("app", "foo"),
+			labels.FromStrings("app", "foo",
+				"a", "a",
+				"__error__", "CSVParserErr",
+				"__error_details__", "unterminated quoted field",
+			),
+		},
+		{
+			"extraneous quote",
+			[]string{"a", "b"},
+			',',
+			[]byte(`"a"b,c`),
+			labels.FromStrings("app", "foo"),
+			labels.FromStrings("app", "foo",
+				"__error__", "CSVParserErr",
+				"__error_details__", `extraneous 'b' in quoted field at position 3`,
+			),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := NewCSVParser(tt.columns, tt.separator)
+			require.NoError(t, err)
+
+			b := NewBaseLabelsBuilder().ForLabels(tt.lbs, tt.lbs.Hash())
+			b.Reset()
+			_, _ = p.Process(0, tt.line, b)
+			require.Equal(t, tt.want, b.LabelsResult().Labels())
+		})
+	}
+}
+
+func Test_kvParser_Parse(t *testing.T) {
+	tests := []struct {
+		name        string
+		separator   string
+		kvSeparator string
+		line        []byte
+		lbs         labels.Labels
+		want        labels.Labels
+	}{
+		{
+			"defaults",
+			",",
+			"=",
+			[]byte("level=info, status=200 ,msg=ok"),
+			labels.FromStrings("app", "foo"),
+			labels.FromStrings("app", "foo",
+				"level", "info",
+				"status", "200",
+				"msg", "ok",
+			),
+		},
+		{
+			"custom separators",
+			";",
+			":",
+			[]byte("src:10.0.0.1;dst:10.0.0.2;action:deny"),
+			labels.FromStrings("app", "foo"),
+			labels.FromStrings("app", "foo",
+				"src", "10.0.0.1",
+				"dst", "10.0.0.2",
+				"action", "deny",
+			),
+		},
+		{
+			"multi characters separators",
+			" | ",
+			"=>",
+			[]byte("user => bob | ip=>1.2.3.4"),
+			labels.FromStrings("app", "foo"),
+			labels.FromStrings("app", "foo",
+				"user", "bob",
+				"ip", "1.2.3.4",
+			),
+		},
+		{
+			"quoted values",
+			";",
+			":",
+			[]byte(`msg:"a;b:c";quote:"say \"hi\"";unterminated:"x`),
+			labels.FromStrings("app", "foo"),
+			labels.FromStrings("app", "foo",
+				"msg", "a;b:c",
+				"quote", `say "hi"`,
+				"unterminated", `"x`,
+			),
+		},
+		{
+			"invalid pairs",
+			",",
+			"=",
+			[]byte("novalue,=nokey,empty=,,ok=1,first value=2"),
+			labels.FromStrings("app", "foo"),
+			labels.FromStrings("app", "foo",
+				"ok", "1",
+				"first_value", "2",
+			),
+		},
+		{
+			"duplicate stream label",
+			",",
+			"=",
+			[]byte("app=bar"),
+			labels.FromStrings("app", "foo"),
+			labels.FromStrings("app", "foo",
+				"app_extracted", "bar",
+			),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := NewKVParser(tt.separator, tt.kvSeparator)
+			require.NoError(t, err)
+
+			b := NewBaseLabelsBuilder().ForLabels(tt.lbs, tt.lbs.Hash())
+			b.Reset()
+			_, _ = p.Process(0, tt.line, b)
+			require.Equal(t, tt.want, b.LabelsResult().Labels())
+		})
+	}
+
+	_, err := NewKVParser("", "=")
+	require.Error(t, err)
+	_, err = NewKVParser("=", "=")
+	require.Error(t, err)
+}
+
 func BenchmarkJsonExpressionParser(b *testing.B) {
 	simpleJsn := []byte(`{
       "data": "Click Here",
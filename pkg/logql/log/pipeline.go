@@ -4,8 +4,10 @@ import (
 	"context"
 	"reflect"
 	"sync"
+	"time"
 	"unsafe"
 
+	"github.com/cespare/xxhash/v2"
 	"github.com/prometheus/prometheus/model/labels"
 )
 
@@ -143,6 +145,8 @@ type pipeline struct {
 	baseBuilder *BaseLabelsBuilder
 	mu          sync.RWMutex
 
+	structuredMetadataMatchers []*labels.Matcher
+
 	streamPipelines map[uint64]StreamPipeline
 }
 
@@ -169,19 +173,22 @@ func NewPipeline(stages []Stage) Pipeline {
 	hints := NewParserHint(nil, nil, false, false, "", stages)
 	builder := NewBaseLabelsBuilderWithGrouping(nil, hints, false, false)
 	return &pipeline{
-		stages:          stages,
-		baseBuilder:     builder,
-		streamPipelines: make(map[uint64]StreamPipeline),
+		stages:                     stages,
+		baseBuilder:                builder,
+		streamPipelines:            make(map[uint64]StreamPipeline),
+		structuredMetadataMatchers: structuredMetadataMatchers(stages),
 	}
 }
 
 type streamPipeline struct {
 	stages  []Stage
 	builder *LabelsBuilder
+
+	structuredMetadataMatchers []*labels.Matcher
 }
 
 func NewStreamPipeline(stages []Stage, labelsBuilder *LabelsBuilder) StreamPipeline {
-	return &streamPipeline{stages, labelsBuilder}
+	return &streamPipeline{stages, labelsBuilder, structuredMetadataMatchers(stages)}
 }
 
 func (p *pipeline) ForStream(labels labels.Labels) StreamPipeline {
@@ -194,7 +201,7 @@ func (p *pipeline) ForStream(labels labels.Labels) StreamPipeline {
 	}
 	p.mu.RUnlock()
 
-	res := NewStreamPipeline(p.stages, p.baseBuilder.ForLabels(labels, hash))
+	res := &streamPipeline{p.stages, p.baseBuilder.ForLabels(labels, hash), p.structuredMetadataMatchers}
 
 	p.mu.Lock()
 	defer p.mu.Unlock()
@@ -240,6 +247,10 @@ func (p *streamPipeline) ProcessString(ts int64, line string, structuredMetadata
 
 func (p *streamPipeline) BaseLabels() LabelsResult { return p.builder.currentResult }
 
+func (p *streamPipeline) StructuredMetadataMatchers() []*labels.Matcher {
+	return p.structuredMetadataMatchers
+}
+
 // PipelineFilter contains a set of matchers and a pipeline that, when matched,
 // causes an entry from a log stream to be skipped. Matching entries must also
 // fall between 'start' and 'end', inclusive
@@ -316,6 +327,10 @@ func (sp *filteringStreamPipeline) BaseLabels() LabelsResult {
 	return sp.pipeline.BaseLabels()
 }
 
+func (sp *filteringStreamPipeline) StructuredMetadataMatchers() []*labels.Matcher {
+	return StructuredMetadataMatchers(sp.pipeline)
+}
+
 func (sp *filteringStreamPipeline) Process(ts int64, line []byte, structuredMetadata ...labels.Label) ([]byte, LabelsResult, bool) {
 	for _, filter := range sp.filters {
 		if ts < filter.start || ts > filter.end {
@@ -346,6 +361,131 @@ func (sp *filteringStreamPipeline) ProcessString(ts int64, line string, structur
 	return sp.pipeline.ProcessString(ts, line, structuredMetadata...)
 }
 
+// maxLineLimitsKeys bounds the number of streams a LineLimiter and the number of keys a LineDeduper keep track of.
+const maxLineLimitsKeys = 1 << 20
+
+// LineLimiter is a stage that keeps at most limit lines per stream, the stream of a line being
+// identified by its labels when reaching the stage.
+// Once maxLineLimitsKeys streams are tracked, the lines of new streams are dropped.
+// The same LineLimiter is shared by every stream of a pipeline and is safe for concurrent use.
+type LineLimiter struct {
+	limit uint64
+
+	mtx    sync.Mutex
+	counts map[uint64]uint64
+}
+
+// NewLineLimiter creates a stage keeping at most limit lines per stream.
+func NewLineLimiter(limit uint64) *LineLimiter {
+	return &LineLimiter{
+		limit:  limit,
+		counts: map[uint64]uint64{},
+	}
+}
+
+// Allow accounts a line of the stream with the given hash and returns false if the stream already reached the limit.
+func (l *LineLimiter) Allow(stream uint64) bool {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	n, ok := l.counts[stream]
+	if n >= l.limit || (!ok && len(l.counts) >= maxLineLimitsKeys) {
+		return false
+	}
+	l.counts[stream] = n + 1
+	return true
+}
+
+func (l *LineLimiter) Process(_ int64, line []byte, lbs *LabelsBuilder) ([]byte, bool) {
+	return line, l.Allow(lbs.LabelsResult().Hash())
+}
+
+func (l *LineLimiter) RequiredLabelNames() []string { return []string{} }
+
+// LineDeduper is a stage that keeps only the first line of each key within each window, windows being aligned
+// on the epoch. The key of a line is made of the values of the by labels, or its content if no labels are given.
+// Aligned windows make the result independent of how the lines are split in time, which allows to apply it again
+// on results merged from multiple queries.
+// Once maxLineLimitsKeys keys are tracked, the keys of other windows are forgotten, then every key if they all
+// belong to the current window, so a line repeating a forgotten key is kept again.
+// The same LineDeduper is shared by every stream of a pipeline and is safe for concurrent use.
+type LineDeduper struct {
+	by     []string
+	window int64
+
+	mtx  sync.Mutex
+	seen map[dedupKey]struct{}
+}
+
+type dedupKey struct {
+	key    uint64
+	window int64
+}
+
+// NewLineDeduper creates a stage de-duplicating lines by the given labels within window.
+// A zero window de-duplicates lines over the whole query range.
+func NewLineDeduper(by []string, window time.Duration) *LineDeduper {
+	return &LineDeduper{
+		by:     by,
+		window: window.Nanoseconds(),
+		seen:   map[dedupKey]struct{}{},
+	}
+}
+
+// Key returns the key used to compare a line, get returns the value of a label of the line.
+func (d *LineDeduper) Key(line []byte, get func(name string) (string, bool)) uint64 {
+	if len(d.by) == 0 {
+		return xxhash.Sum64(line)
+	}
+	h := xxhash.New()
+	for _, name := range d.by {
+		v, _ := get(name)
+		_, _ = h.WriteString(v)
+		_, _ = h.Write(dedupSeparator)
+	}
+	return h.Sum64()
+}
+
+var dedupSeparator = []byte{0xff}
+
+// Allow accounts a line with the given key and returns false if a line with the same key was already kept in its window.
+func (d *LineDeduper) Allow(ts int64, key uint64) bool {
+	k := dedupKey{key: key}
+	if d.window > 0 {
+		k.window = ts / d.window
+	}
+
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+
+	if _, ok := d.seen[k]; ok {
+		return false
+	}
+	if len(d.seen) >= maxLineLimitsKeys {
+		d.evict(k.window)
+	}
+	d.seen[k] = struct{}{}
+	return true
+}
+
+// evict forgets the keys of every window but the given one, or every key if none belong to another window.
+func (d *LineDeduper) evict(window int64) {
+	for k := range d.seen {
+		if k.window != window {
+			delete(d.seen, k)
+		}
+	}
+	if len(d.seen) >= maxLineLimitsKeys {
+		clear(d.seen)
+	}
+}
+
+func (d *LineDeduper) Process(ts int64, line []byte, lbs *LabelsBuilder) ([]byte, bool) {
+	return line, d.Allow(ts, d.Key(line, lbs.Get))
+}
+
+func (d *LineDeduper) RequiredLabelNames() []string { return d.by }
+
 // ReduceStages reduces multiple stages into one.
 func ReduceStages(stages []Stage) Stage {
 	if len(stages) == 0 {
@@ -523,6 +523,90 @@ func TestKeepLabelsPipeline(t *testing.T) {
 
 }
 
+func TestLineLimiterPipeline(t *testing.T) {
+	p := NewPipeline([]Stage{NewLogfmtParser(false, false), NewDropLabels([]DropLabel{{Name: "ts"}}), NewLineLimiter(2)})
+	foo := p.ForStream(labels.FromStrings("app", "foo"))
+	bar := p.ForStream(labels.FromStrings("app", "bar"))
+
+	for _, tc := range []struct {
+		sp   StreamPipeline
+		line string
+		want bool
+	}{
+		{foo, `level=info ts=1`, true},
+		{foo, `level=info ts=2`, true},
+		{foo, `level=error ts=3`, true}, // different stream once parsed
+		{bar, `level=info ts=4`, true},
+		{foo, `level=info ts=5`, false},
+		{bar, `level=info ts=6`, true},
+		{bar, `level=info ts=7`, false},
+	} {
+		_, _, ok := tc.sp.Process(0, []byte(tc.line))
+		require.Equal(t, tc.want, ok, tc.line)
+	}
+}
+
+func TestLineDeduperPipeline(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		by     []string
+		window time.Duration
+		lines  []string
+		want   []bool
+	}{
+		{
+			name:  "by line",
+			lines: []string{`a`, `b`, `a`, `c`, `b`},
+			want:  []bool{true, true, false, true, false},
+		},
+		{
+			name:  "by labels",
+			by:    []string{"id", "level"},
+			lines: []string{`id=1 level=info msg=a`, `id=1 level=info msg=b`, `id=1 level=error msg=a`, `id=2 level=info msg=a`, `level=info`, `msg=c level=info`},
+			want:  []bool{true, false, true, true, true, false},
+		},
+		{
+			name:   "within window",
+			window: 10 * time.Second,
+			lines:  []string{`a`, `a`, `a`, `a`},
+			want:   []bool{true, false, true, false},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			sp := NewPipeline([]Stage{NewLogfmtParser(false, false), NewLineDeduper(tt.by, tt.window)}).ForStream(labels.FromStrings("app", "foo"))
+			for i, line := range tt.lines {
+				ts := time.Unix(int64(i*6), 0).UnixNano()
+				_, _, ok := sp.Process(ts, []byte(line))
+				require.Equal(t, tt.want[i], ok, line)
+			}
+		})
+	}
+}
+
+func TestLineLimitsBounded(t *testing.T) {
+	l := NewLineLimiter(1)
+	for i := uint64(0); i < maxLineLimitsKeys; i++ {
+		require.True(t, l.Allow(i))
+	}
+	require.False(t, l.Allow(maxLineLimitsKeys), "new streams are dropped once the limit of streams is reached")
+	require.Len(t, l.counts, maxLineLimitsKeys)
+
+	d := NewLineDeduper(nil, time.Second)
+	for i := uint64(0); i < maxLineLimitsKeys; i++ {
+		require.True(t, d.Allow(0, i))
+	}
+	require.False(t, d.Allow(0, 0))
+	// keys of the previous windows are forgotten first.
+	require.True(t, d.Allow(time.Second.Nanoseconds(), 0))
+	require.Len(t, d.seen, 1)
+
+	d = NewLineDeduper(nil, 0)
+	for i := uint64(0); i <= maxLineLimitsKeys; i++ {
+		require.True(t, d.Allow(0, i))
+	}
+	require.Len(t, d.seen, 1)
+}
+
 func Benchmark_Pipeline(b *testing.B) {
 	b.ReportAllocs()
 
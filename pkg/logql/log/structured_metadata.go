@@ -0,0 +1,67 @@
+package log
+
+import (
+	"github.com/prometheus/prometheus/model/labels"
+)
+
+// StructuredMetadataFilter filters log lines using matchers on their structured metadata only,
+// stream and extracted labels with the same name are ignored.
+type StructuredMetadataFilter struct {
+	matchers []*labels.Matcher
+}
+
+func NewStructuredMetadataFilter(matchers []*labels.Matcher) *StructuredMetadataFilter {
+	return &StructuredMetadataFilter{matchers: matchers}
+}
+
+func (f *StructuredMetadataFilter) Process(_ int64, line []byte, lbs *LabelsBuilder) ([]byte, bool) {
+	for _, m := range f.matchers {
+		v, _ := lbs.GetStructuredMetadata(m.Name)
+		if !m.Matches(v) {
+			return nil, false
+		}
+	}
+	return line, true
+}
+
+func (f *StructuredMetadataFilter) RequiredLabelNames() []string { return []string{} }
+
+func (f *StructuredMetadataFilter) Matchers() []*labels.Matcher { return f.matchers }
+
+// structuredMetadataMatchers collects the matchers of the structured metadata filters applied before
+// any stage modifying labels, all the lines kept by the stages must match them.
+func structuredMetadataMatchers(stages []Stage) []*labels.Matcher {
+	var matchers []*labels.Matcher
+	for _, s := range stages {
+		switch s := s.(type) {
+		case *StructuredMetadataFilter:
+			matchers = append(matchers, s.matchers...)
+		case *LabelsFormatter, *DropLabels, *KeepLabels:
+			return matchers
+		}
+	}
+	return matchers
+}
+
+type structuredMetadataMatcher interface {
+	StructuredMetadataMatchers() []*labels.Matcher
+}
+
+// StructuredMetadataMatchers returns the structured metadata matchers every line kept by a stream pipeline or
+// a stream sample extractor matches. Lines and chunks not matching them can be skipped without being processed.
+func StructuredMetadataMatchers(p interface{}) []*labels.Matcher {
+	if m, ok := p.(structuredMetadataMatcher); ok {
+		return m.StructuredMetadataMatchers()
+	}
+	return nil
+}
+
+// MatchStructuredMetadata returns true if the structured metadata of a line matches all the matchers.
+func MatchStructuredMetadata(matchers []*labels.Matcher, structuredMetadata labels.Labels) bool {
+	for _, m := range matchers {
+		if !m.Matches(structuredMetadata.Get(m.Name)) {
+			return false
+		}
+	}
+	return true
+}
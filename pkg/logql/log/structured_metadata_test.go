@@ -0,0 +1,72 @@
+package log
+
+import (
+	"testing"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStructuredMetadataFilter(t *testing.T) {
+	filter := NewStructuredMetadataFilter([]*labels.Matcher{
+		labels.MustNewMatcher(labels.MatchEqual, "trace_id", "abc"),
+		labels.MustNewMatcher(labels.MatchNotRegexp, "env", "dev.*"),
+	})
+	sp := NewPipeline([]Stage{NewLogfmtParser(false, false), filter}).ForStream(labels.FromStrings("app", "foo", "env", "dev"))
+
+	for _, tt := range []struct {
+		name               string
+		line               string
+		structuredMetadata labels.Labels
+		want               bool
+	}{
+		{"match", `msg=a`, labels.FromStrings("trace_id", "abc", "env", "prod"), true},
+		{"missing label", `msg=a`, labels.FromStrings("env", "prod"), false},
+		{"negative matcher", `msg=a`, labels.FromStrings("trace_id", "abc", "env", "dev-1"), false},
+		{"stream label ignored", `msg=a`, labels.FromStrings("trace_id", "abc"), true},
+		{"parsed label ignored", `trace_id=abc`, nil, false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, ok := sp.Process(0, []byte(tt.line), tt.structuredMetadata...)
+			require.Equal(t, tt.want, ok)
+			require.True(t, sp.ReferencedStructuredMetadata())
+		})
+	}
+}
+
+func TestStructuredMetadataMatchers(t *testing.T) {
+	traceID := labels.MustNewMatcher(labels.MatchEqual, "trace_id", "abc")
+	env := labels.MustNewMatcher(labels.MatchEqual, "env", "prod")
+
+	for _, tt := range []struct {
+		name   string
+		stages []Stage
+		want   []*labels.Matcher
+	}{
+		{
+			name:   "no filter",
+			stages: []Stage{NewLogfmtParser(false, false)},
+		},
+		{
+			name:   "filters after parsers",
+			stages: []Stage{NewStructuredMetadataFilter([]*labels.Matcher{traceID}), NewLogfmtParser(false, false), NewStructuredMetadataFilter([]*labels.Matcher{env})},
+			want:   []*labels.Matcher{traceID, env},
+		},
+		{
+			name:   "filters after labels modifications",
+			stages: []Stage{NewStructuredMetadataFilter([]*labels.Matcher{traceID}), NewDropLabels([]DropLabel{{Name: "env"}}), NewStructuredMetadataFilter([]*labels.Matcher{env})},
+			want:   []*labels.Matcher{traceID},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			sp := NewPipeline(tt.stages).ForStream(labels.FromStrings("app", "foo"))
+			require.Equal(t, tt.want, StructuredMetadataMatchers(sp))
+
+			ex, err := NewLineSampleExtractor(CountExtractor, tt.stages, nil, false, false)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, StructuredMetadataMatchers(ex.ForStream(labels.FromStrings("app", "foo"))))
+		})
+	}
+
+	require.Nil(t, StructuredMetadataMatchers(NewNoopPipeline().ForStream(labels.FromStrings("app", "foo"))))
+}
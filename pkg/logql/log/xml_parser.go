@@ -0,0 +1,353 @@
+package log
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/loki/pkg/logql/log/xmlexpr"
+	"github.com/grafana/loki/pkg/logqlmodel"
+)
+
+var errNoXMLElement = errors.New("expecting an xml element")
+
+// xmlFrame is the state of an element being decoded.
+type xmlFrame struct {
+	prefixLen int    // length of the key prefix of the parent element
+	text      []byte // character data of the element
+
+	children []xmlChild // count of the children by name
+	captures []int      // expressions capturing the text of the element
+}
+
+type xmlChild struct {
+	name  string
+	count int
+}
+
+// xmlStack is a reusable stack of frames.
+type xmlStack []xmlFrame
+
+func (s *xmlStack) push(prefixLen int) *xmlFrame {
+	if len(*s) < cap(*s) {
+		*s = (*s)[:len(*s)+1]
+	} else {
+		*s = append(*s, xmlFrame{})
+	}
+	f := &(*s)[len(*s)-1]
+	f.prefixLen = prefixLen
+	f.text = f.text[:0]
+	f.children = f.children[:0]
+	f.captures = f.captures[:0]
+	return f
+}
+
+func (s *xmlStack) pop() {
+	*s = (*s)[:len(*s)-1]
+}
+
+func (s xmlStack) top() *xmlFrame {
+	if len(s) == 0 {
+		return nil
+	}
+	return &s[len(s)-1]
+}
+
+// childIndex increments and returns the 1-based position of the named child among its siblings.
+func (f *xmlFrame) childIndex(name string) int {
+	for i := range f.children {
+		if f.children[i].name == name {
+			f.children[i].count++
+			return f.children[i].count
+		}
+	}
+	f.children = append(f.children, xmlChild{name: name, count: 1})
+	return 1
+}
+
+func isValidXMLStart(line []byte) bool {
+	line = bytes.TrimSpace(line)
+	return len(line) > 0 && line[0] == '<'
+}
+
+type XMLParser struct {
+	prefixBuffer []byte // buffer used to build the keys of the elements
+	stack        xmlStack
+	seen         map[string]struct{}
+
+	keys internedStringSet
+}
+
+// NewXMLParser creates a log stage that can parse an xml log line and add its elements and attributes as labels.
+// Nested elements are flattened by joining their names with `_`, attributes are added as a child of their element.
+// When an element or attribute is repeated, only its first occurrence is extracted.
+func NewXMLParser() *XMLParser {
+	return &XMLParser{
+		prefixBuffer: make([]byte, 0, 1024),
+		seen:         map[string]struct{}{},
+		keys:         internedStringSet{},
+	}
+}
+
+func (x *XMLParser) Process(_ int64, line []byte, lbs *LabelsBuilder) ([]byte, bool) {
+	parserHints := lbs.ParserLabelHints()
+	if parserHints.NoLabels() {
+		return line, true
+	}
+
+	if !isValidXMLStart(line) {
+		addErrLabel(errXML, errNoXMLElement, lbs)
+		return line, parserHints.ShouldContinueParsingLine(logqlmodel.ErrorLabel, lbs)
+	}
+
+	// reset the state.
+	x.prefixBuffer = x.prefixBuffer[:0]
+	x.stack = x.stack[:0]
+	for k := range x.seen {
+		delete(x.seen, k)
+	}
+
+	dec := xml.NewDecoder(bytes.NewReader(line))
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			addErrLabel(errXML, err, lbs)
+			return line, parserHints.ShouldContinueParsingLine(logqlmodel.ErrorLabel, lbs)
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			prefixLen := len(x.prefixBuffer)
+			x.appendPrefix(t.Name.Local)
+			x.stack.push(prefixLen)
+
+			for _, attr := range t.Attr {
+				if attr.Name.Space == "xmlns" || attr.Name.Local == "xmlns" {
+					continue
+				}
+				elementLen := len(x.prefixBuffer)
+				x.appendPrefix(attr.Name.Local)
+				ok := x.set(x.prefixBuffer, []byte(attr.Value), lbs)
+				x.prefixBuffer = x.prefixBuffer[:elementLen]
+				if !ok {
+					return line, false
+				}
+			}
+		case xml.CharData:
+			if f := x.stack.top(); f != nil {
+				f.text = append(f.text, t...)
+			}
+		case xml.EndElement:
+			f := x.stack.top()
+			if !x.set(x.prefixBuffer, bytes.TrimSpace(f.text), lbs) {
+				return line, false
+			}
+			x.prefixBuffer = x.prefixBuffer[:f.prefixLen]
+			x.stack.pop()
+		}
+
+		if parserHints.AllRequiredExtracted() {
+			break
+		}
+	}
+	return line, true
+}
+
+func (x *XMLParser) appendPrefix(name string) {
+	if len(x.prefixBuffer) > 0 {
+		x.prefixBuffer = append(x.prefixBuffer, jsonSpacer)
+	}
+	x.prefixBuffer = appendSanitized(x.prefixBuffer, unsafeGetBytes(name))
+}
+
+// set adds the value as a label if it's the first occurrence of the key.
+// It returns false if the line should not be processed further.
+func (x *XMLParser) set(prefix, value []byte, lbs *LabelsBuilder) bool {
+	if len(value) == 0 {
+		return true
+	}
+	parserHints := lbs.ParserLabelHints()
+	key, ok := x.keys.Get(prefix, func() (string, bool) {
+		field := string(prefix)
+		if lbs.BaseHas(field) {
+			field = field + duplicateSuffix
+		}
+		if !parserHints.ShouldExtract(field) {
+			return "", false
+		}
+		return field, true
+	})
+	if !ok {
+		return true
+	}
+	if _, ok := x.seen[key]; ok {
+		return true
+	}
+	x.seen[key] = struct{}{}
+
+	lbs.Set(ParsedLabel, key, string(value))
+	return parserHints.ShouldContinueParsingLine(key, lbs)
+}
+
+func (x *XMLParser) RequiredLabelNames() []string { return []string{} }
+
+type XMLExpressionParser struct {
+	ids   []string
+	paths []xmlexpr.Path
+
+	path    []xmlexpr.Step // path of the current element
+	stack   xmlStack
+	matched []bool
+	keys    internedStringSet
+}
+
+// NewXMLExpressionParser creates a log stage that extracts the given xml expressions of a log line as labels.
+func NewXMLExpressionParser(expressions []LabelExtractionExpr) (*XMLExpressionParser, error) {
+	if len(expressions) == 0 {
+		return nil, fmt.Errorf("no xml expression provided")
+	}
+	ids := make([]string, 0, len(expressions))
+	paths := make([]xmlexpr.Path, 0, len(expressions))
+	for _, exp := range expressions {
+		path, err := xmlexpr.Parse(exp.Expression)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse expression [%s]: %w", exp.Expression, err)
+		}
+
+		if !model.LabelName(exp.Identifier).IsValid() {
+			return nil, fmt.Errorf("invalid extracted label name '%s'", exp.Identifier)
+		}
+
+		ids = append(ids, exp.Identifier)
+		paths = append(paths, path)
+	}
+
+	return &XMLExpressionParser{
+		ids:     ids,
+		paths:   paths,
+		matched: make([]bool, len(ids)),
+		keys:    internedStringSet{},
+	}, nil
+}
+
+func (x *XMLExpressionParser) Process(_ int64, line []byte, lbs *LabelsBuilder) ([]byte, bool) {
+	parserHints := lbs.ParserLabelHints()
+	if len(line) == 0 || parserHints.NoLabels() {
+		return line, true
+	}
+
+	if !isValidXMLStart(line) {
+		addErrLabel(errXML, errNoXMLElement, lbs)
+		return line, true
+	}
+
+	// reset the state, expressions not required by the query are marked as already matched.
+	x.path = x.path[:0]
+	x.stack = x.stack[:0]
+	remaining := 0
+	for i := range x.ids {
+		x.matched[i] = !parserHints.ShouldExtract(x.key(i, lbs))
+		if !x.matched[i] {
+			remaining++
+		}
+	}
+	if remaining == 0 {
+		return line, true
+	}
+
+	// the root element is a child of a virtual document element.
+	x.stack.push(0)
+
+	dec := xml.NewDecoder(bytes.NewReader(line))
+	for remaining > 0 {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			addErrLabel(errXML, err, lbs)
+			break
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			index := x.stack.top().childIndex(t.Name.Local)
+			x.path = append(x.path, xmlexpr.Step{Name: t.Name.Local, Index: index})
+			f := x.stack.push(0)
+
+			for i, p := range x.paths {
+				if x.matched[i] || !x.matches(p) {
+					continue
+				}
+				if p.Attr == "" {
+					f.captures = append(f.captures, i)
+					continue
+				}
+				for _, attr := range t.Attr {
+					if attr.Name.Local == p.Attr {
+						x.matched[i] = true
+						remaining--
+						lbs.Set(ParsedLabel, x.key(i, lbs), attr.Value)
+						break
+					}
+				}
+			}
+		case xml.CharData:
+			if f := x.stack.top(); len(f.captures) > 0 {
+				f.text = append(f.text, t...)
+			}
+		case xml.EndElement:
+			f := x.stack.top()
+			for _, i := range f.captures {
+				x.matched[i] = true
+				remaining--
+				lbs.Set(ParsedLabel, x.key(i, lbs), string(bytes.TrimSpace(f.text)))
+			}
+			x.stack.pop()
+			x.path = x.path[:len(x.path)-1]
+		}
+	}
+
+	// Ensure there's a label for every value
+	for i, id := range x.ids {
+		if !x.matched[i] {
+			if _, ok := lbs.Get(id); !ok {
+				lbs.Set(ParsedLabel, x.key(i, lbs), "")
+			}
+		}
+	}
+	return line, true
+}
+
+// matches returns true if the path of the current element matches p.
+func (x *XMLExpressionParser) matches(p xmlexpr.Path) bool {
+	if len(p.Steps) != len(x.path) {
+		return false
+	}
+	for i, s := range p.Steps {
+		if s.Name != x.path[i].Name || (s.Index > 0 && s.Index != x.path[i].Index) {
+			return false
+		}
+	}
+	return true
+}
+
+func (x *XMLExpressionParser) key(i int, lbs *LabelsBuilder) string {
+	identifier := x.ids[i]
+	key, _ := x.keys.Get(unsafeGetBytes(identifier), func() (string, bool) {
+		if lbs.BaseHas(identifier) {
+			identifier = identifier + duplicateSuffix
+		}
+		return identifier, true
+	})
+	return key
+}
+
+func (x *XMLExpressionParser) RequiredLabelNames() []string { return []string{} }
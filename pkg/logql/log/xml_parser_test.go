@@ -0,0 +1,209 @@
+package log
+
+import (
+	"testing"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_xmlParser_Parse(t *testing.T) {
+	tests := []struct {
+		name  string
+		line  []byte
+		lbs   labels.Labels
+		want  labels.Labels
+		hints ParserHint
+	}{
+		{
+			"elements and attributes",
+			[]byte(`<event id="42"><user role="admin">bob</user><action>login</action></event>`),
+			labels.FromStrings("app", "foo"),
+			labels.FromStrings("app", "foo",
+				"event_id", "42",
+				"event_user", "bob",
+				"event_user_role", "admin",
+				"event_action", "login",
+			),
+			NoParserHints(),
+		},
+		{
+			"nested and repeated elements",
+			[]byte(`<?xml version="1.0"?>
+<log>
+  <!-- comment -->
+  <items><item>a</item><item>b</item></items>
+  <source.ip>10.0.0.1</source.ip>
+  <empty/>
+</log>`),
+			labels.FromStrings("app", "foo"),
+			labels.FromStrings("app", "foo",
+				"log_items_item", "a",
+				"log_source_ip", "10.0.0.1",
+			),
+			NoParserHints(),
+		},
+		{
+			"namespaces and entities",
+			[]byte(`<e:Event xmlns:e="http://schemas.microsoft.com/win/2004/08/events/event" xmlns="urn:x"><e:Data Name="Target">a &amp; b</e:Data></e:Event>`),
+			labels.FromStrings("app", "foo"),
+			labels.FromStrings("app", "foo",
+				"Event_Data", "a & b",
+				"Event_Data_Name", "Target",
+			),
+			NoParserHints(),
+		},
+		{
+			"duplicate stream label",
+			[]byte(`<app>bar</app>`),
+			labels.FromStrings("app", "foo"),
+			labels.FromStrings("app", "foo",
+				"app_extracted", "bar",
+			),
+			NoParserHints(),
+		},
+		{
+			"not xml",
+			[]byte(`level=info`),
+			labels.FromStrings("app", "foo"),
+			labels.FromStrings("app", "foo",
+				"__error__", "XMLParserErr",
+				"__error_details__", "expecting an xml element",
+			),
+			NoParserHints(),
+		},
+		{
+			"malformed xml",
+			[]byte(`<event><user>bob</event>`),
+			labels.FromStrings("app", "foo"),
+			labels.FromStrings("app", "foo",
+				"__error__", "XMLParserErr",
+				"__error_details__", "XML syntax error on line 1: element <user> closed by </event>",
+			),
+			NoParserHints(),
+		},
+		{
+			"hints",
+			[]byte(`<event id="42"><user role="admin">bob</user><action>login</action></event>`),
+			labels.FromStrings("app", "foo"),
+			labels.FromStrings("app", "foo",
+				"event_user", "bob",
+			),
+			NewParserHint([]string{"event_user"}, []string{"event_user"}, false, false, "", nil),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewBaseLabelsBuilderWithGrouping(nil, tt.hints, false, false).ForLabels(tt.lbs, tt.lbs.Hash())
+			b.Reset()
+			_, _ = NewXMLParser().Process(0, tt.line, b)
+			require.Equal(t, tt.want, b.LabelsResult().Labels())
+		})
+	}
+}
+
+func TestXMLExpressionParser(t *testing.T) {
+	testLine := []byte(`<event id="42">
+  <user role="admin">bob</user>
+  <items><item>a</item><item>b</item><other>x</other><item>c</item></items>
+  <msg>  hello <b>world</b>  </msg>
+</event>`)
+
+	tests := []struct {
+		name        string
+		line        []byte
+		expressions []LabelExtractionExpr
+		lbs         labels.Labels
+		want        labels.Labels
+	}{
+		{
+			"element",
+			testLine,
+			[]LabelExtractionExpr{
+				NewLabelExtractionExpr("user", "event/user"),
+			},
+			labels.EmptyLabels(),
+			labels.FromStrings("user", "bob"),
+		},
+		{
+			"attributes",
+			testLine,
+			[]LabelExtractionExpr{
+				NewLabelExtractionExpr("id", "/event/@id"),
+				NewLabelExtractionExpr("role", "event/user/@role"),
+			},
+			labels.EmptyLabels(),
+			labels.FromStrings("id", "42", "role", "admin"),
+		},
+		{
+			"positions",
+			testLine,
+			[]LabelExtractionExpr{
+				NewLabelExtractionExpr("first", "event/items/item"),
+				NewLabelExtractionExpr("third", "event/items/item[3]"),
+			},
+			labels.EmptyLabels(),
+			labels.FromStrings("first", "a", "third", "c"),
+		},
+		{
+			"text of mixed content",
+			testLine,
+			[]LabelExtractionExpr{
+				NewLabelExtractionExpr("msg", "event/msg"),
+			},
+			labels.EmptyLabels(),
+			labels.FromStrings("msg", "hello"),
+		},
+		{
+			"missing",
+			testLine,
+			[]LabelExtractionExpr{
+				NewLabelExtractionExpr("missing", "event/nope"),
+				NewLabelExtractionExpr("missing_attr", "event/@nope"),
+				NewLabelExtractionExpr("missing_pos", "event/items/item[4]"),
+			},
+			labels.EmptyLabels(),
+			labels.FromStrings("missing", "", "missing_attr", "", "missing_pos", ""),
+		},
+		{
+			"duplicate stream label",
+			testLine,
+			[]LabelExtractionExpr{
+				NewLabelExtractionExpr("user", "event/user"),
+			},
+			labels.FromStrings("user", "alice"),
+			labels.FromStrings("user", "alice", "user_extracted", "bob"),
+		},
+		{
+			"not xml",
+			[]byte(`{"user":"bob"}`),
+			[]LabelExtractionExpr{
+				NewLabelExtractionExpr("user", "event/user"),
+			},
+			labels.EmptyLabels(),
+			labels.FromStrings("__error__", "XMLParserErr", "__error_details__", "expecting an xml element"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := NewXMLExpressionParser(tt.expressions)
+			require.NoError(t, err)
+
+			b := NewBaseLabelsBuilder().ForLabels(tt.lbs, tt.lbs.Hash())
+			b.Reset()
+			_, _ = p.Process(0, tt.line, b)
+			require.Equal(t, tt.want, b.LabelsResult().Labels())
+		})
+	}
+}
+
+func TestXMLExpressionParserFailures(t *testing.T) {
+	for _, expressions := range [][]LabelExtractionExpr{
+		nil,
+		{NewLabelExtractionExpr("user", "event//user")},
+		{NewLabelExtractionExpr("1user", "event/user")},
+	} {
+		_, err := NewXMLExpressionParser(expressions)
+		require.Error(t, err)
+	}
+}
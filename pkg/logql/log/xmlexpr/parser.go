@@ -0,0 +1,130 @@
+// Package xmlexpr parses the XPath-lite expressions used by the xml parser to extract labels.
+//
+// An expression is a path of element names separated by `/`, starting at the root element.
+// Each element can be followed by a 1-based position `[n]` to select the nth sibling with that name,
+// and the last element can be followed by `/@attr` to select one of its attributes:
+//
+//	event/user
+//	/event/items/item[2]
+//	event/user/@id
+package xmlexpr
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var (
+	errEmptyExpression = errors.New("empty expression")
+	errEmptyElement    = errors.New("empty element name")
+)
+
+// Step is a single element of a path.
+type Step struct {
+	Name string
+	// Index is the 1-based position of the element among its siblings with the same name,
+	// 0 matches any position.
+	Index int
+}
+
+// Path is a parsed xml expression.
+type Path struct {
+	Steps []Step
+	// Attr is the attribute to extract from the last element, if empty the text of the element is extracted.
+	Attr string
+}
+
+// Parse parses an xml expression.
+func Parse(expr string) (Path, error) {
+	expr = strings.TrimPrefix(strings.TrimSpace(expr), "/")
+	if expr == "" {
+		return Path{}, errEmptyExpression
+	}
+
+	var path Path
+	parts := strings.Split(expr, "/")
+	for i, part := range parts {
+		if strings.HasPrefix(part, "@") {
+			if i != len(parts)-1 {
+				return Path{}, fmt.Errorf("attribute %q must be the last element of the path", part)
+			}
+			if i == 0 {
+				return Path{}, fmt.Errorf("attribute %q must follow an element", part)
+			}
+			attr := part[1:]
+			if !isValidName(attr) {
+				return Path{}, fmt.Errorf("invalid attribute name %q", attr)
+			}
+			path.Attr = attr
+			break
+		}
+
+		step, err := parseStep(part)
+		if err != nil {
+			return Path{}, err
+		}
+		path.Steps = append(path.Steps, step)
+	}
+	return path, nil
+}
+
+func parseStep(s string) (Step, error) {
+	name, index := s, 0
+	if i := strings.IndexByte(s, '['); i >= 0 {
+		if !strings.HasSuffix(s, "]") {
+			return Step{}, fmt.Errorf("missing closing ']' in %q", s)
+		}
+		n, err := strconv.Atoi(s[i+1 : len(s)-1])
+		if err != nil || n < 1 {
+			return Step{}, fmt.Errorf("invalid position in %q, it must be a positive integer", s)
+		}
+		name, index = s[:i], n
+	}
+	if name == "" {
+		return Step{}, errEmptyElement
+	}
+	if !isValidName(name) {
+		return Step{}, fmt.Errorf("invalid element name %q", name)
+	}
+	return Step{Name: name, Index: index}, nil
+}
+
+// isValidName returns true if s is a valid local xml name.
+// It is a simplified version of the xml specification which doesn't allow namespace prefixes.
+func isValidName(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, r := range s {
+		switch {
+		case r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r > 0x7f:
+		case i > 0 && (r == '-' || r == '.' || r >= '0' && r <= '9'):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// String returns the expression of the path.
+func (p Path) String() string {
+	var sb strings.Builder
+	for i, s := range p.Steps {
+		if i > 0 {
+			sb.WriteByte('/')
+		}
+		sb.WriteString(s.Name)
+		if s.Index > 0 {
+			sb.WriteByte('[')
+			sb.WriteString(strconv.Itoa(s.Index))
+			sb.WriteByte(']')
+		}
+	}
+	if p.Attr != "" {
+		sb.WriteString("/@")
+		sb.WriteString(p.Attr)
+	}
+	return sb.String()
+}
@@ -0,0 +1,72 @@
+package xmlexpr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		expression string
+		want       Path
+	}{
+		{
+			"event",
+			Path{Steps: []Step{{Name: "event"}}},
+		},
+		{
+			"/event/user",
+			Path{Steps: []Step{{Name: "event"}, {Name: "user"}}},
+		},
+		{
+			"event/items/item[2]",
+			Path{Steps: []Step{{Name: "event"}, {Name: "items"}, {Name: "item", Index: 2}}},
+		},
+		{
+			"event/user/@id",
+			Path{Steps: []Step{{Name: "event"}, {Name: "user"}}, Attr: "id"},
+		},
+		{
+			"Event/System/Provider/@Name",
+			Path{Steps: []Step{{Name: "Event"}, {Name: "System"}, {Name: "Provider"}}, Attr: "Name"},
+		},
+		{
+			"log-entry/_source.ip",
+			Path{Steps: []Step{{Name: "log-entry"}, {Name: "_source.ip"}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			got, err := Parse(tt.expression)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+
+			reparsed, err := Parse(got.String())
+			require.NoError(t, err)
+			require.Equal(t, got, reparsed)
+		})
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	for _, expression := range []string{
+		"",
+		"/",
+		"event//user",
+		"@id",
+		"event/@id/user",
+		"event/@",
+		"event/item[0]",
+		"event/item[a]",
+		"event/item[1",
+		"event/[1]",
+		"1event",
+		"ns:event",
+	} {
+		t.Run(expression, func(t *testing.T) {
+			_, err := Parse(expression)
+			require.Error(t, err)
+		})
+	}
+}
@@ -8,7 +8,8 @@ func optimizeSampleExpr(expr syntax.SampleExpr) (syntax.SampleExpr, error) {
 	// we skip sharding AST for now, it's not easy to clone them since they are not part of the language.
 	expr.Walk(func(e syntax.Expr) {
 		switch e.(type) {
-		case *ConcatSampleExpr, DownstreamSampleExpr, *QuantileSketchEvalExpr, *QuantileSketchMergeExpr:
+		case *ConcatSampleExpr, DownstreamSampleExpr, *QuantileSketchEvalExpr, *QuantileSketchMergeExpr,
+			*CountDistinctSketchEvalExpr, *CountDistinctSketchMergeExpr, *TopKSketchEvalExpr, *TopKSketchMergeExpr:
 			skip = true
 			return
 		}
@@ -71,6 +72,18 @@ func removeLineformat(expr syntax.SampleExpr) {
 					found = true
 					break
 				}
+				if _, ok := pipelineExpr.MultiStages[j].(*syntax.CSVParserExpr); ok {
+					found = true
+					break
+				}
+				if _, ok := pipelineExpr.MultiStages[j].(*syntax.KVParserExpr); ok {
+					found = true
+					break
+				}
+				if _, ok := pipelineExpr.MultiStages[j].(*syntax.XMLParserExpr); ok {
+					found = true
+					break
+				}
 			}
 			if found {
 				// we cannot remove safely the linefmtExpr.
@@ -62,6 +62,14 @@ func (q ProbabilisticQuantileVector) QuantileSketchVec() ProbabilisticQuantileVe
 	return q
 }
 
+func (ProbabilisticQuantileVector) CountDistinctSketchVec() ProbabilisticCountDistinctVector {
+	return ProbabilisticCountDistinctVector{}
+}
+
+func (ProbabilisticQuantileVector) TopKSketchVec() sketch.TopKVector {
+	return sketch.TopKVector{}
+}
+
 func (q ProbabilisticQuantileVector) ToProto() *logproto.QuantileSketchVector {
 	samples := make([]*logproto.QuantileSketchSample, len(q))
 	for i, sample := range q {
@@ -7,6 +7,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/axiomhq/hyperloglog"
 	"github.com/prometheus/prometheus/model/labels"
 	"github.com/prometheus/prometheus/promql"
 	promql_parser "github.com/prometheus/prometheus/promql/parser"
@@ -250,6 +251,10 @@ func aggregator(r *syntax.RangeAggregationExpr) (BatchRangeVectorAggregator, err
 		return last, nil
 	case syntax.OpRangeTypeAbsent:
 		return one, nil
+	case syntax.OpRangeTypeCountDistinct:
+		return countDistinctOverTime, nil
+	case syntax.OpRangeTypeApproxCountDistinct:
+		return approxCountDistinctOverTime, nil
 	default:
 		return nil, fmt.Errorf(syntax.UnsupportedErr, r.Operation)
 	}
@@ -504,6 +509,22 @@ func one(_ []promql.FPoint) float64 {
 	return 1.0
 }
 
+// countDistinctOverTime counts the distinct values within the range, which
+// are the hashes of the unwrapped label values.
+func countDistinctOverTime(samples []promql.FPoint) float64 {
+	values := make(map[float64]struct{}, len(samples))
+	for _, v := range samples {
+		values[v.F] = struct{}{}
+	}
+	return float64(len(values))
+}
+
+// approxCountDistinctOverTime estimates the distinct values within the range
+// using a HyperLogLog sketch.
+func approxCountDistinctOverTime(samples []promql.FPoint) float64 {
+	return float64(newCountDistinctSketch(samples).Estimate())
+}
+
 // streaming range agg
 type streamRangeVectorIterator struct {
 	iter                                 iter.PeekingSampleIterator
@@ -627,6 +648,10 @@ func streamingAggregator(r *syntax.RangeAggregationExpr) (RangeStreamingAgg, err
 		return &LastOverTime{}, nil
 	case syntax.OpRangeTypeAbsent:
 		return &OneOverTime{}, nil
+	case syntax.OpRangeTypeCountDistinct:
+		return &CountDistinctOverTime{values: map[float64]struct{}{}}, nil
+	case syntax.OpRangeTypeApproxCountDistinct:
+		return &ApproxCountDistinctOverTime{sketch: hyperloglog.New(), buf: make([]byte, 8)}, nil
 	default:
 		return nil, fmt.Errorf(syntax.UnsupportedErr, r.Operation)
 	}
@@ -851,3 +876,28 @@ func (a *OneOverTime) agg(_ promql.FPoint) {
 func (a *OneOverTime) at() float64 {
 	return 1.0
 }
+
+type CountDistinctOverTime struct {
+	values map[float64]struct{}
+}
+
+func (a *CountDistinctOverTime) agg(sample promql.FPoint) {
+	a.values[sample.F] = struct{}{}
+}
+
+func (a *CountDistinctOverTime) at() float64 {
+	return float64(len(a.values))
+}
+
+type ApproxCountDistinctOverTime struct {
+	sketch *hyperloglog.Sketch
+	buf    []byte
+}
+
+func (a *ApproxCountDistinctOverTime) agg(sample promql.FPoint) {
+	insertCountDistinct(a.sketch, a.buf, sample.F)
+}
+
+func (a *ApproxCountDistinctOverTime) at() float64 {
+	return float64(a.sketch.Estimate())
+}
@@ -175,6 +175,33 @@ func (m RangeMapper) Map(expr syntax.SampleExpr, vectorAggrPushdown *syntax.Vect
 		}
 		e.Left = lhsMapped
 		return e, nil
+	case *syntax.FunctionExpr:
+		// The vector aggregation is not pushed down, since functions do not
+		// commute with aggregations, e.g. sum(ceil(x)) != ceil(sum(x)).
+		lhsMapped, err := m.Map(e.Left, nil, recorder)
+		if err != nil {
+			return nil, err
+		}
+		e.Left = lhsMapped
+		return e, nil
+	case *syntax.LabelJoinExpr:
+		lhsMapped, err := m.Map(e.Left, nil, recorder)
+		if err != nil {
+			return nil, err
+		}
+		e.Left = lhsMapped
+		return e, nil
+	case *syntax.HistogramQuantileExpr:
+		lhsMapped, err := m.Map(e.Left, nil, recorder)
+		if err != nil {
+			return nil, err
+		}
+		e.Left = lhsMapped
+		return e, nil
+	case *syntax.SubqueryExpr:
+		// Subqueries are not split by range since their inner expression
+		// needs to be evaluated over the full range of the subquery.
+		return e, nil
 	case *syntax.LiteralExpr:
 		return e, nil
 	case *syntax.VectorExpr:
@@ -206,8 +233,13 @@ func hasLabelExtractionStage(expr syntax.SampleExpr) bool {
 	found := false
 	expr.Walk(func(e syntax.Expr) {
 		switch concrete := e.(type) {
-		case *syntax.LogfmtParserExpr:
+		case *syntax.LogfmtParserExpr, *syntax.KVParserExpr:
 			found = true
+		case *syntax.XMLParserExpr:
+			// Like `json`, `xml` only extracts an unbounded amount of labels without expressions.
+			if len(concrete.Expressions) == 0 {
+				found = true
+			}
 		case *syntax.LabelParserExpr:
 			// It will **not** return true for `regexp`, `unpack` and `pattern`, since these label extraction
 			// stages can control how many labels, and therefore the resulting amount of series, are extracted.
@@ -570,6 +602,12 @@ func isSplittableByRange(expr syntax.SampleExpr) bool {
 		return isSplittableByRange(e.SampleExpr) || literalLHS && isSplittableByRange(e.RHS) || literalRHS
 	case *syntax.LabelReplaceExpr:
 		return isSplittableByRange(e.Left)
+	case *syntax.FunctionExpr:
+		return isSplittableByRange(e.Left)
+	case *syntax.LabelJoinExpr:
+		return isSplittableByRange(e.Left)
+	case *syntax.HistogramQuantileExpr:
+		return isSplittableByRange(e.Left)
 	case *syntax.VectorExpr:
 		return false
 	default:
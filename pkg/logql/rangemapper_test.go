@@ -1810,6 +1810,35 @@ func Test_SplitRangeVectorMapping(t *testing.T) {
 			)`,
 			3,
 		},
+
+		// functions
+		{
+			`round(sum by (baz) (count_over_time({app="foo"}[3m])), 10)`,
+			`round(
+				sum by (baz) (
+					sum without () (
+						downstream<sum by (baz) (count_over_time({app="foo"} [1m] offset 2m0s)), shard=<nil>>
+						++ downstream<sum by (baz) (count_over_time({app="foo"} [1m] offset 1m0s)), shard=<nil>>
+						++ downstream<sum by (baz) (count_over_time({app="foo"} [1m])), shard=<nil>>
+					)
+				),
+				10
+			)`,
+			3,
+		},
+		{
+			`histogram_quantile(0.9, sum by (le) (count_over_time({app="foo"}[3m])))`,
+			`histogram_quantile(0.9,
+				sum by (le) (
+					sum without () (
+						downstream<sum by (le) (count_over_time({app="foo"} [1m] offset 2m0s)), shard=<nil>>
+						++ downstream<sum by (le) (count_over_time({app="foo"} [1m] offset 1m0s)), shard=<nil>>
+						++ downstream<sum by (le) (count_over_time({app="foo"} [1m])), shard=<nil>>
+					)
+				)
+			)`,
+			3,
+		},
 	} {
 		tc := tc
 		t.Run(tc.expr, func(t *testing.T) {
@@ -1987,6 +2016,11 @@ func Test_SplitRangeVectorMapping_Noop(t *testing.T) {
 			`(sum(last_over_time({app="foo"} | logfmt | unwrap total_count [1d]) by (foo)) or vector(0.000000))`,
 		},
 
+		// should be noop if subquery
+		{
+			`max_over_time(sum(count_over_time({app="foo"}[3m]))[1h:1m])`,
+			`max_over_time(sum(count_over_time({app="foo"}[3m]))[1h:1m])`,
+		},
 		// should be noop if literal expression
 		{
 			`5`,
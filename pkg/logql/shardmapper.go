@@ -13,26 +13,39 @@ import (
 )
 
 const (
-	ShardQuantileOverTime = "quantile_over_time"
+	ShardQuantileOverTime    = "quantile_over_time"
+	ShardApproxCountDistinct = "approx_count_distinct"
+	ShardApproxTopK          = "approx_topk"
 )
 
 type ShardMapper struct {
-	shards                   ShardingStrategy
-	metrics                  *MapperMetrics
-	quantileOverTimeSharding bool
+	shards                      ShardingStrategy
+	metrics                     *MapperMetrics
+	quantileOverTimeSharding    bool
+	approxCountDistinctSharding bool
+	approxTopKSharding          bool
 }
 
 func NewShardMapper(strategy ShardingStrategy, metrics *MapperMetrics, shardAggregation []string) ShardMapper {
 	quantileOverTimeSharding := false
+	approxCountDistinctSharding := false
+	approxTopKSharding := false
 	for _, a := range shardAggregation {
-		if a == ShardQuantileOverTime {
+		switch a {
+		case ShardQuantileOverTime:
 			quantileOverTimeSharding = true
+		case ShardApproxCountDistinct:
+			approxCountDistinctSharding = true
+		case ShardApproxTopK:
+			approxTopKSharding = true
 		}
 	}
 	return ShardMapper{
-		shards:                   strategy,
-		metrics:                  metrics,
-		quantileOverTimeSharding: quantileOverTimeSharding,
+		shards:                      strategy,
+		metrics:                     metrics,
+		quantileOverTimeSharding:    quantileOverTimeSharding,
+		approxCountDistinctSharding: approxCountDistinctSharding,
+		approxTopKSharding:          approxTopKSharding,
 	}
 }
 
@@ -81,6 +94,14 @@ func (m ShardMapper) Map(expr syntax.Expr, r *downstreamRecorder, topLevel bool)
 		return m.mapLabelReplaceExpr(e, r, topLevel)
 	case *syntax.RangeAggregationExpr:
 		return m.mapRangeAggregationExpr(e, r, topLevel)
+	case *syntax.SubqueryExpr:
+		return m.mapSubqueryExpr(e, r)
+	case *syntax.FunctionExpr:
+		return m.mapFunctionExpr(e, r, topLevel)
+	case *syntax.LabelJoinExpr:
+		return m.mapLabelJoinExpr(e, r, topLevel)
+	case *syntax.HistogramQuantileExpr:
+		return m.mapHistogramQuantileExpr(e, r)
 	case *syntax.BinOpExpr:
 		return m.mapBinOpExpr(e, r, topLevel)
 	default:
@@ -285,6 +306,48 @@ func (m ShardMapper) mapVectorAggregationExpr(expr *syntax.VectorAggregationExpr
 				Grouping:  expr.Grouping,
 				Operation: syntax.OpTypeSum,
 			}, bytesPerShard, nil
+
+		case syntax.OpTypeApproxTopK:
+			if !m.approxTopKSharding {
+				break
+			}
+
+			shards, bytesPerShard, err := m.shards.Resolver().Shards(expr)
+			if err != nil {
+				return nil, 0, err
+			}
+			if shards == 0 {
+				return noOp(expr, m.shards.Resolver())
+			}
+
+			// approx_topk(k, sum by (foo) (...)) ->
+			// topk_sketch_eval(topk_sketch_merge(
+			// __topk_sketch__(k, sum by (foo) (...))))
+			downstreams := make([]DownstreamSampleExpr, 0, shards)
+			sketchExpr := &syntax.VectorAggregationExpr{
+				Left:      expr.Left,
+				Grouping:  &syntax.Grouping{},
+				Params:    expr.Params,
+				Operation: syntax.OpTypeTopKSketch,
+			}
+			for shard := shards - 1; shard >= 0; shard-- {
+				s := NewPowerOfTwoShard(astmapper.ShardAnnotation{
+					Shard: shard,
+					Of:    shards,
+				})
+				downstreams = append(downstreams, DownstreamSampleExpr{
+					shard:      &s,
+					SampleExpr: sketchExpr,
+				})
+			}
+
+			return &TopKSketchEvalExpr{
+				topKMergeExpr: &TopKSketchMergeExpr{
+					downstreams: downstreams,
+				},
+				k: expr.Params,
+			}, bytesPerShard, nil
+
 		default:
 			// this should not be reachable. If an operation is shardable it should
 			// have an optimization listed. Nonetheless, we log this as a warning
@@ -332,6 +395,53 @@ func (m ShardMapper) mapLabelReplaceExpr(expr *syntax.LabelReplaceExpr, r *downs
 	return &cpy, bytesPerShard, nil
 }
 
+// mapFunctionExpr maps the inner expression of the function. Functions are
+// applied to each sample individually, so they can be applied to the merged
+// results of the downstream queries.
+func (m ShardMapper) mapFunctionExpr(expr *syntax.FunctionExpr, r *downstreamRecorder, topLevel bool) (syntax.SampleExpr, uint64, error) {
+	subMapped, bytesPerShard, err := m.Map(expr.Left, r, topLevel)
+	if err != nil {
+		return nil, 0, err
+	}
+	cpy := *expr
+	cpy.Left = subMapped.(syntax.SampleExpr)
+	return &cpy, bytesPerShard, nil
+}
+
+func (m ShardMapper) mapLabelJoinExpr(expr *syntax.LabelJoinExpr, r *downstreamRecorder, topLevel bool) (syntax.SampleExpr, uint64, error) {
+	subMapped, bytesPerShard, err := m.Map(expr.Left, r, topLevel)
+	if err != nil {
+		return nil, 0, err
+	}
+	cpy := *expr
+	cpy.Left = subMapped.(syntax.SampleExpr)
+	return &cpy, bytesPerShard, nil
+}
+
+// mapHistogramQuantileExpr maps the inner expression of the histogram
+// quantile. The quantile is calculated from the merged buckets.
+func (m ShardMapper) mapHistogramQuantileExpr(expr *syntax.HistogramQuantileExpr, r *downstreamRecorder) (syntax.SampleExpr, uint64, error) {
+	subMapped, bytesPerShard, err := m.Map(expr.Left, r, false)
+	if err != nil {
+		return nil, 0, err
+	}
+	cpy := *expr
+	cpy.Left = subMapped.(syntax.SampleExpr)
+	return &cpy, bytesPerShard, nil
+}
+
+// mapSubqueryExpr maps the inner expression of the subquery. The subquery
+// itself is evaluated on the merged results of its inner expression.
+func (m ShardMapper) mapSubqueryExpr(expr *syntax.SubqueryExpr, r *downstreamRecorder) (syntax.SampleExpr, uint64, error) {
+	subMapped, bytesPerShard, err := m.Map(expr.Left, r, false)
+	if err != nil {
+		return nil, 0, err
+	}
+	cpy := *expr
+	cpy.Left = subMapped.(syntax.SampleExpr)
+	return &cpy, bytesPerShard, nil
+}
+
 // These functions require a different merge strategy than the default
 // concatenation.
 // This is because the same label sets may exist on multiple shards when label-reducing parsing is applied or when
@@ -481,6 +591,47 @@ func (m ShardMapper) mapRangeAggregationExpr(expr *syntax.RangeAggregationExpr,
 			quantile: expr.Params,
 		}, bytesPerShard, nil
 
+	case syntax.OpRangeTypeApproxCountDistinct:
+		if !m.approxCountDistinctSharding {
+			return noOp(expr, m.shards.Resolver())
+		}
+
+		potentialConflict := syntax.ReducesLabels(expr)
+		if !potentialConflict && (expr.Grouping == nil || expr.Grouping.Noop()) {
+			return m.mapSampleExpr(expr, r)
+		}
+
+		shards, bytesPerShard, err := m.shards.Resolver().Shards(expr)
+		if err != nil {
+			return nil, 0, err
+		}
+		if shards == 0 {
+			return noOp(expr, m.shards.Resolver())
+		}
+
+		// approx_count_distinct() by (foo) ->
+		// count_distinct_sketch_eval(count_distinct_merge by (foo)
+		// (__count_distinct_sketch_over_time__() by (foo)))
+
+		downstreams := make([]DownstreamSampleExpr, 0, shards)
+		expr.Operation = syntax.OpRangeTypeCountDistinctSketch
+		for shard := shards - 1; shard >= 0; shard-- {
+			s := NewPowerOfTwoShard(astmapper.ShardAnnotation{
+				Shard: shard,
+				Of:    shards,
+			})
+			downstreams = append(downstreams, DownstreamSampleExpr{
+				shard:      &s,
+				SampleExpr: expr,
+			})
+		}
+
+		return &CountDistinctSketchEvalExpr{
+			countDistinctMergeExpr: &CountDistinctSketchMergeExpr{
+				downstreams: downstreams,
+			},
+		}, bytesPerShard, nil
+
 	default:
 		// don't shard if there's not an appropriate optimization
 		return noOp(expr, m.shards.Resolver())
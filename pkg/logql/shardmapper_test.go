@@ -116,7 +116,7 @@ func TestMapSampleExpr(t *testing.T) {
 
 func TestMappingStrings(t *testing.T) {
 	strategy := NewPowerOfTwoStrategy(ConstantShards(2))
-	m := NewShardMapper(strategy, nilShardMetrics, []string{ShardQuantileOverTime})
+	m := NewShardMapper(strategy, nilShardMetrics, []string{ShardQuantileOverTime, ShardApproxCountDistinct, ShardApproxTopK})
 	for _, tc := range []struct {
 		in  string
 		out string
@@ -299,6 +299,65 @@ func TestMappingStrings(t *testing.T) {
 					)
 				)`,
 		},
+		{
+			in: `max_over_time(sum by (cluster) (rate({foo="bar"}[5m]))[1h:1m])`,
+			out: `max_over_time(
+				sum by (cluster) (
+					downstream<sum by (cluster) (rate({foo="bar"}[5m])), shard=0_of_2>
+					++ downstream<sum by (cluster) (rate({foo="bar"}[5m])), shard=1_of_2>
+				)[1h:1m]
+			)`,
+		},
+		{
+			in: `approx_count_distinct({foo="bar"} | logfmt | unwrap user [5m]) by (cluster)`,
+			out: `countDistinctSketchEval<countDistinctSketchMerge<
+				downstream<__count_distinct_sketch_over_time__({foo="bar"} | logfmt | unwrap user [5m]) by (cluster), shard=1_of_2>
+				++ downstream<__count_distinct_sketch_over_time__({foo="bar"} | logfmt | unwrap user [5m]) by (cluster), shard=0_of_2>
+			>>`,
+		},
+		{
+			in:  `count_distinct_over_time({foo="bar"} | logfmt | unwrap user [5m]) by (cluster)`,
+			out: `count_distinct_over_time({foo="bar"} | logfmt | unwrap user [5m]) by (cluster)`,
+		},
+		{
+			in:  `max(approx_count_distinct({foo="bar"} | logfmt | unwrap user [5m]) by (cluster))`,
+			out: `max(approx_count_distinct({foo="bar"} | logfmt | unwrap user [5m]) by (cluster))`,
+		},
+		{
+			in: `approx_topk(10, sum by (path) (count_over_time({foo="bar"}[5m])))`,
+			out: `topkSketchEval<topkSketchMerge<
+				downstream<__topk_sketch__(10, sum by (path) (count_over_time({foo="bar"}[5m]))), shard=1_of_2>
+				++ downstream<__topk_sketch__(10, sum by (path) (count_over_time({foo="bar"}[5m]))), shard=0_of_2>
+			>>`,
+		},
+		{
+			// approx_topk can only merge the sketches of sums
+			in: `approx_topk(10, max by (path) (count_over_time({foo="bar"}[5m])))`,
+			out: `approx_topk(10,
+				max by (path) (
+					downstream<max by (path) (count_over_time({foo="bar"}[5m])), shard=0_of_2>
+					++ downstream<max by (path) (count_over_time({foo="bar"}[5m])), shard=1_of_2>
+				)
+			)`,
+		},
+		{
+			in: `abs(sum by (cluster) (rate({foo="bar"}[5m])))`,
+			out: `abs(
+				sum by (cluster) (
+					downstream<sum by (cluster) (rate({foo="bar"}[5m])), shard=0_of_2>
+					++ downstream<sum by (cluster) (rate({foo="bar"}[5m])), shard=1_of_2>
+				)
+			)`,
+		},
+		{
+			in: `histogram_quantile(0.99, sum by (le) (rate({foo="bar"} | logfmt [5m])))`,
+			out: `histogram_quantile(0.99,
+				sum by (le) (
+					downstream<sum by (le) (rate({foo="bar"} | logfmt [5m])), shard=0_of_2>
+					++ downstream<sum by (le) (rate({foo="bar"} | logfmt [5m])), shard=1_of_2>
+				)
+			)`,
+		},
 		{
 			in: `sum(count_over_time({foo="bar"} | logfmt | label_format bar=baz | bar="buz" [5m])) by (bar)`,
 			out: `sum by (bar) (
@@ -453,6 +512,10 @@ func TestMappingStrings_NoProbabilisticSharding(t *testing.T) {
 			in:  `max by (status)(quantile_over_time(0.70, {a=~".+"} | logfmt | unwrap value [1s]))`,
 			out: `maxby(status)(quantile_over_time(0.7,{a=~".+"}|logfmt|unwrapvalue[1s]))`,
 		},
+		{
+			in:  `approx_topk(10, sum by (path) (count_over_time({a=~".+"}[1s])))`,
+			out: `approx_topk(10,sumby(path)(downstream<sumby(path)(count_over_time({a=~".+"}[1s])),shard=0_of_2>++downstream<sumby(path)(count_over_time({a=~".+"}[1s])),shard=1_of_2>))`,
+		},
 	} {
 		t.Run(tc.in, func(t *testing.T) {
 
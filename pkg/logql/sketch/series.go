@@ -1,6 +1,8 @@
 package sketch
 
 import (
+	"fmt"
+
 	"github.com/prometheus/prometheus/promql/parser"
 
 	"github.com/grafana/loki/pkg/logproto"
@@ -13,6 +15,21 @@ type TopKVector struct {
 	ts   uint64
 }
 
+// NewTopKVector returns the topk sketch of the step at the timestamp ts.
+func NewTopKVector(topk *Topk, ts uint64) TopKVector {
+	return TopKVector{topk: topk, ts: ts}
+}
+
+// Topk returns the topk sketch of the vector.
+func (v TopKVector) Topk() *Topk {
+	return v.topk
+}
+
+// Timestamp returns the timestamp of the vector in milliseconds.
+func (v TopKVector) Timestamp() uint64 {
+	return v.ts
+}
+
 // TopkMatrix is `promql.Value` and `parser.Value`
 type TopKMatrix []TopKVector
 
@@ -24,6 +41,19 @@ func (TopKMatrix) String() string {
 	return ""
 }
 
+// Merge merges the topk sketches of right into s step by step.
+func (s TopKMatrix) Merge(right TopKMatrix) (TopKMatrix, error) {
+	if len(s) != len(right) {
+		return nil, fmt.Errorf("failed to merge topk matrix: lengths differ %d!=%d", len(s), len(right))
+	}
+	for i := range s {
+		if err := s[i].topk.Merge(right[i].topk); err != nil {
+			return nil, fmt.Errorf("failed to merge topk matrix: %w", err)
+		}
+	}
+	return s, nil
+}
+
 func (s TopKMatrix) ToProto() (*logproto.TopKMatrix, error) {
 	points := make([]*logproto.TopKMatrix_Vector, 0, len(s))
 	for _, point := range s {
@@ -237,7 +237,12 @@ func unsafeGetBytes(s string) []byte {
 // for each node in the heap and rebalance the heap, and then if the event we're observing has an estimate that is still
 // greater than the minimum heap element count, we should put this event into the heap and remove the other one.
 func (t *Topk) Observe(event string) {
-	estimate, h1, h2 := t.sketch.ConservativeIncrement(event)
+	t.ObserveN(event, 1)
+}
+
+// ObserveN is like Observe but counts n occurrences of the event at once.
+func (t *Topk) ObserveN(event string, n uint32) {
+	estimate, h1, h2 := t.sketch.ConservativeAdd(event, n)
 	t.hll.Insert(unsafeGetBytes(event))
 
 	if t.InTopk(h1, h2) {
@@ -308,6 +313,10 @@ func (t *Topk) Merge(from *Topk) error {
 	if err != nil {
 		return err
 	}
+	// sketches decoded from protos don't know their k
+	if t.max < from.max {
+		t.max = from.max
+	}
 
 	var all TopKResult
 	for _, e := range *t.heap {
@@ -323,7 +332,10 @@ func (t *Topk) Merge(from *Topk) error {
 	temp := &MinHeap{}
 	var h1, h2 uint32
 	// TODO: merging should also potentially replace it's bloomfilter? or 0 everything in the bloomfilter
-	for _, e := range all[:t.max] {
+	if len(all) > t.max {
+		all = all[:t.max]
+	}
+	for _, e := range all {
 		h1, h2 = hashn(e.Event)
 		t.heapPush(temp, e.Event, uint32(e.Count), h1, h2)
 	}
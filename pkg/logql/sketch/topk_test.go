@@ -42,6 +42,31 @@ func TestTopkCardinality(t *testing.T) {
 	assert.Truef(t, bigEnough, "Cardinality of %d was not big enough.", c)
 }
 
+func TestTopK_ObserveN(t *testing.T) {
+	topk, err := newCMSTopK(2, 64, 4)
+	require.NoError(t, err)
+	topk.ObserveN("a", 5)
+	topk.ObserveN("b", 20)
+	topk.Observe("c")
+	topk.ObserveN("c", 9)
+
+	require.Equal(t, TopKResult{{Event: "b", Count: 20}, {Event: "c", Count: 10}}, topk.Topk())
+}
+
+func TestTopK_MergeFewerThanK(t *testing.T) {
+	topk, err := newCMSTopK(10, 64, 4)
+	require.NoError(t, err)
+	topk.Observe("a")
+
+	other, err := newCMSTopK(10, 64, 4)
+	require.NoError(t, err)
+	other.ObserveN("a", 2)
+	other.Observe("b")
+
+	require.NoError(t, topk.Merge(other))
+	require.Equal(t, TopKResult{{Event: "a", Count: 3}, {Event: "b", Count: 1}}, topk.Topk())
+}
+
 // TODO: merging is not as accurate as it should be
 func TestTopK_Merge(t *testing.T) {
 	nStreams := 10
@@ -2,11 +2,15 @@ package logql
 
 import (
 	"github.com/prometheus/prometheus/promql"
+
+	"github.com/grafana/loki/pkg/logql/sketch"
 )
 
 type StepResult interface {
 	SampleVector() promql.Vector
 	QuantileSketchVec() ProbabilisticQuantileVector
+	CountDistinctSketchVec() ProbabilisticCountDistinctVector
+	TopKSketchVec() sketch.TopKVector
 }
 
 type SampleVector promql.Vector
@@ -21,6 +25,14 @@ func (p SampleVector) QuantileSketchVec() ProbabilisticQuantileVector {
 	return ProbabilisticQuantileVector{}
 }
 
+func (p SampleVector) CountDistinctSketchVec() ProbabilisticCountDistinctVector {
+	return ProbabilisticCountDistinctVector{}
+}
+
+func (p SampleVector) TopKSketchVec() sketch.TopKVector {
+	return sketch.TopKVector{}
+}
+
 // StepEvaluator evaluate a single step of a query.
 type StepEvaluator interface {
 	// while Next returns a promql.Value, the only acceptable types are Scalar and Vector.
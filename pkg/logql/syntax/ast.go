@@ -7,6 +7,7 @@ import (
 	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/grafana/loki/pkg/util"
 
@@ -627,6 +628,175 @@ func (e *LabelParserExpr) String() string {
 	return sb.String()
 }
 
+// parserOption is a `name="value"` option of a parser.
+type parserOption struct {
+	name, value string
+}
+
+type CSVParserExpr struct {
+	Columns   []string
+	Separator rune
+
+	implicit
+}
+
+func newCSVParserExpr(columns string, options []parserOption) *CSVParserExpr {
+	e := CSVParserExpr{
+		Columns:   strings.Split(columns, ","),
+		Separator: ',',
+	}
+	for i := range e.Columns {
+		e.Columns[i] = strings.TrimSpace(e.Columns[i])
+	}
+	for _, o := range options {
+		switch o.name {
+		case OpParserOptionSep:
+			r, size := utf8.DecodeRuneInString(o.value)
+			if size == 0 || size != len(o.value) {
+				panic(logqlmodel.NewParseError(fmt.Sprintf("invalid csv parser: separator must be a single character, got %q", o.value), 0, 0))
+			}
+			e.Separator = r
+		default:
+			panic(logqlmodel.NewParseError(fmt.Sprintf("invalid csv parser: unknown option %q", o.name), 0, 0))
+		}
+	}
+	if _, err := log.NewCSVParser(e.Columns, e.Separator); err != nil {
+		panic(logqlmodel.NewParseError(fmt.Sprintf("invalid csv parser: %s", err.Error()), 0, 0))
+	}
+	return &e
+}
+
+func (*CSVParserExpr) isStageExpr() {}
+
+func (e *CSVParserExpr) Shardable(_ bool) bool { return true }
+
+func (e *CSVParserExpr) Walk(f WalkFn) { f(e) }
+
+func (e *CSVParserExpr) Accept(v RootVisitor) { v.VisitCSVParser(e) }
+
+func (e *CSVParserExpr) Stage() (log.Stage, error) {
+	return log.NewCSVParser(e.Columns, e.Separator)
+}
+
+func (e *CSVParserExpr) String() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%s %s ", OpPipe, OpParserTypeCSV))
+	sb.WriteString(strconv.Quote(strings.Join(e.Columns, ",")))
+	if e.Separator != ',' {
+		sb.WriteString(fmt.Sprintf(" %s=%s", OpParserOptionSep, strconv.Quote(string(e.Separator))))
+	}
+	return sb.String()
+}
+
+const (
+	defaultKVSeparator   = ","
+	defaultKVKVSeparator = "="
+)
+
+type KVParserExpr struct {
+	Separator   string
+	KVSeparator string
+
+	implicit
+}
+
+func newKVParserExpr(options []parserOption) *KVParserExpr {
+	e := KVParserExpr{
+		Separator:   defaultKVSeparator,
+		KVSeparator: defaultKVKVSeparator,
+	}
+	for _, o := range options {
+		switch o.name {
+		case OpParserOptionSep:
+			e.Separator = o.value
+		case OpParserOptionKVSep:
+			e.KVSeparator = o.value
+		default:
+			panic(logqlmodel.NewParseError(fmt.Sprintf("invalid kv parser: unknown option %q", o.name), 0, 0))
+		}
+	}
+	if _, err := log.NewKVParser(e.Separator, e.KVSeparator); err != nil {
+		panic(logqlmodel.NewParseError(fmt.Sprintf("invalid kv parser: %s", err.Error()), 0, 0))
+	}
+	return &e
+}
+
+func (*KVParserExpr) isStageExpr() {}
+
+func (e *KVParserExpr) Shardable(_ bool) bool { return true }
+
+func (e *KVParserExpr) Walk(f WalkFn) { f(e) }
+
+func (e *KVParserExpr) Accept(v RootVisitor) { v.VisitKVParser(e) }
+
+func (e *KVParserExpr) Stage() (log.Stage, error) {
+	return log.NewKVParser(e.Separator, e.KVSeparator)
+}
+
+func (e *KVParserExpr) String() string {
+	var sb strings.Builder
+	sb.WriteString(OpPipe)
+	sb.WriteString(" ")
+	sb.WriteString(OpParserTypeKV)
+	if e.Separator != defaultKVSeparator {
+		sb.WriteString(fmt.Sprintf(" %s=%s", OpParserOptionSep, strconv.Quote(e.Separator)))
+	}
+	if e.KVSeparator != defaultKVKVSeparator {
+		sb.WriteString(fmt.Sprintf(" %s=%s", OpParserOptionKVSep, strconv.Quote(e.KVSeparator)))
+	}
+	return sb.String()
+}
+
+type XMLParserExpr struct {
+	Expressions []log.LabelExtractionExpr
+
+	implicit
+}
+
+func newXMLParserExpr(expressions []log.LabelExtractionExpr) *XMLParserExpr {
+	if len(expressions) > 0 {
+		if _, err := log.NewXMLExpressionParser(expressions); err != nil {
+			panic(logqlmodel.NewParseError(fmt.Sprintf("invalid xml parser: %s", err.Error()), 0, 0))
+		}
+	}
+	return &XMLParserExpr{
+		Expressions: expressions,
+	}
+}
+
+func (*XMLParserExpr) isStageExpr() {}
+
+func (e *XMLParserExpr) Shardable(_ bool) bool { return true }
+
+func (e *XMLParserExpr) Walk(f WalkFn) { f(e) }
+
+func (e *XMLParserExpr) Accept(v RootVisitor) { v.VisitXMLParser(e) }
+
+func (e *XMLParserExpr) Stage() (log.Stage, error) {
+	if len(e.Expressions) == 0 {
+		return log.NewXMLParser(), nil
+	}
+	return log.NewXMLExpressionParser(e.Expressions)
+}
+
+func (e *XMLParserExpr) String() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%s %s", OpPipe, OpParserTypeXML))
+	for i, exp := range e.Expressions {
+		if i == 0 {
+			sb.WriteString(" ")
+		}
+		sb.WriteString(exp.Identifier)
+		sb.WriteString("=")
+		sb.WriteString(strconv.Quote(exp.Expression))
+
+		if i+1 != len(e.Expressions) {
+			sb.WriteString(",")
+		}
+	}
+	return sb.String()
+}
+
 type LabelFilterExpr struct {
 	log.LabelFilterer
 	implicit
@@ -779,6 +949,160 @@ func (e *KeepLabelsExpr) Walk(f WalkFn) { f(e) }
 
 func (e *KeepLabelsExpr) Accept(v RootVisitor) { v.VisitKeepLabel(e) }
 
+// StructuredMetadataFilterExpr filters log lines by their structured metadata,
+// without looking at stream labels or labels extracted by parsers.
+type StructuredMetadataFilterExpr struct {
+	Matchers []*labels.Matcher
+
+	implicit
+}
+
+func newStructuredMetadataFilterExpr(matchers []*labels.Matcher) *StructuredMetadataFilterExpr {
+	return &StructuredMetadataFilterExpr{Matchers: matchers}
+}
+
+func (*StructuredMetadataFilterExpr) isStageExpr() {}
+
+func (e *StructuredMetadataFilterExpr) Shardable(_ bool) bool { return true }
+
+func (e *StructuredMetadataFilterExpr) Walk(f WalkFn) { f(e) }
+
+func (e *StructuredMetadataFilterExpr) Accept(v RootVisitor) { v.VisitStructuredMetadataFilter(e) }
+
+func (e *StructuredMetadataFilterExpr) Stage() (log.Stage, error) {
+	return log.NewStructuredMetadataFilter(e.Matchers), nil
+}
+
+func (e *StructuredMetadataFilterExpr) String() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%s %s ", OpPipe, OpStructuredMetadata))
+	for i, m := range e.Matchers {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(m.String())
+	}
+	return sb.String()
+}
+
+// LineLimitExpr keeps at most Limit lines per stream.
+type LineLimitExpr struct {
+	Limit uint64
+
+	implicit
+}
+
+func newLineLimitExpr(limit string) *LineLimitExpr {
+	n, err := strconv.ParseUint(limit, 10, 64)
+	if err != nil || n == 0 {
+		panic(logqlmodel.NewParseError(fmt.Sprintf("invalid limit: %s, it must be a positive integer", limit), 0, 0))
+	}
+	return &LineLimitExpr{Limit: n}
+}
+
+func (*LineLimitExpr) isStageExpr() {}
+
+func (e *LineLimitExpr) Shardable(_ bool) bool { return true }
+
+func (e *LineLimitExpr) Walk(f WalkFn) { f(e) }
+
+func (e *LineLimitExpr) Accept(v RootVisitor) { v.VisitLineLimit(e) }
+
+func (e *LineLimitExpr) Stage() (log.Stage, error) {
+	return log.NewLineLimiter(e.Limit), nil
+}
+
+func (e *LineLimitExpr) String() string {
+	return fmt.Sprintf("%s %s %d", OpPipe, OpLimit, e.Limit)
+}
+
+// DedupExpr drops the lines repeating a previous line within a time window,
+// lines are compared using the By labels or their content if no labels are given.
+type DedupExpr struct {
+	By     []string
+	Window time.Duration
+
+	implicit
+}
+
+func newDedupExpr(by []string, window time.Duration) *DedupExpr {
+	if window < 0 {
+		panic(logqlmodel.NewParseError(fmt.Sprintf("invalid dedup window: %s, it must be a positive duration", window), 0, 0))
+	}
+	return &DedupExpr{By: by, Window: window}
+}
+
+func (*DedupExpr) isStageExpr() {}
+
+func (e *DedupExpr) Shardable(_ bool) bool { return true }
+
+func (e *DedupExpr) Walk(f WalkFn) { f(e) }
+
+func (e *DedupExpr) Accept(v RootVisitor) { v.VisitDedup(e) }
+
+func (e *DedupExpr) Stage() (log.Stage, error) {
+	return log.NewLineDeduper(e.By, e.Window), nil
+}
+
+func (e *DedupExpr) String() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%s %s", OpPipe, OpDedup))
+	if len(e.By) > 0 {
+		sb.WriteString(" by (")
+		sb.WriteString(strings.Join(e.By, ","))
+		sb.WriteString(")")
+	}
+	if e.Window > 0 {
+		sb.WriteString(" ")
+		sb.WriteString(model.Duration(e.Window).String())
+	}
+	return sb.String()
+}
+
+// isLineLimitStage returns true if the stage limits or de-duplicates the lines of a query.
+func isLineLimitStage(e StageExpr) bool {
+	switch e.(type) {
+	case *LineLimitExpr, *DedupExpr:
+		return true
+	default:
+		return false
+	}
+}
+
+// SplitLineLimits splits a log selector into the expression selecting and processing the lines and its
+// limit and dedup stages, which are always the last stages of the pipeline.
+// The limit and dedup stages need the lines in the order of the query, they can only be applied once the
+// results of every source have been merged.
+func SplitLineLimits(expr LogSelectorExpr) (LogSelectorExpr, MultiStageExpr) {
+	p, ok := expr.(*PipelineExpr)
+	if !ok {
+		return expr, nil
+	}
+	i := len(p.MultiStages)
+	for i > 0 && isLineLimitStage(p.MultiStages[i-1]) {
+		i--
+	}
+	if i == len(p.MultiStages) {
+		return expr, nil
+	}
+	limits := p.MultiStages[i:]
+	if i == 0 {
+		return p.Left, limits
+	}
+	return newPipelineExpr(p.Left, p.MultiStages[:i]), limits
+}
+
+// LineLimits returns the limit and dedup stages of a log selector.
+func LineLimits(expr LogSelectorExpr) MultiStageExpr {
+	var limits MultiStageExpr
+	expr.Walk(func(e Expr) {
+		if s, ok := e.(StageExpr); ok && isLineLimitStage(s) {
+			limits = append(limits, s)
+		}
+	})
+	return limits
+}
+
 func (*LineFmtExpr) isStageExpr() {}
 
 func (e *LineFmtExpr) Shardable(_ bool) bool { return true }
@@ -1103,6 +1427,8 @@ const (
 	OpTypeSort     = "sort"
 	OpTypeSortDesc = "sort_desc"
 
+	OpTypeApproxTopK = "approx_topk"
+
 	// range vector ops
 	OpRangeTypeCount       = "count_over_time"
 	OpRangeTypeRate        = "rate"
@@ -1120,6 +1446,9 @@ const (
 	OpRangeTypeLast        = "last_over_time"
 	OpRangeTypeAbsent      = "absent_over_time"
 
+	OpRangeTypeCountDistinct       = "count_distinct_over_time"
+	OpRangeTypeApproxCountDistinct = "approx_count_distinct"
+
 	//vector
 	OpTypeVector = "vector"
 
@@ -1150,6 +1479,9 @@ const (
 	OpParserTypeRegexp  = "regexp"
 	OpParserTypeUnpack  = "unpack"
 	OpParserTypePattern = "pattern"
+	OpParserTypeCSV     = "csv"
+	OpParserTypeKV      = "kv"
+	OpParserTypeXML     = "xml"
 
 	OpFmtLine    = "line_format"
 	OpFmtLabel   = "label_format"
@@ -1172,6 +1504,20 @@ const (
 
 	OpLabelReplace = "label_replace"
 
+	// functions
+	OpFuncAbs           = "abs"
+	OpFuncCeil          = "ceil"
+	OpFuncFloor         = "floor"
+	OpFuncRound         = "round"
+	OpFuncClampMin      = "clamp_min"
+	OpFuncClampMax      = "clamp_max"
+	OpFuncSqrt          = "sqrt"
+	OpFuncExp           = "exp"
+	OpFuncLn            = "ln"
+	OpFuncTimestamp     = "timestamp"
+	OpLabelJoin         = "label_join"
+	OpHistogramQuantile = "histogram_quantile"
+
 	// function filters
 	OpFilterIP = "ip"
 
@@ -1181,14 +1527,27 @@ const (
 	// keep labels
 	OpKeep = "keep"
 
+	// line limits
+	OpLimit = "limit"
+	OpDedup = "dedup"
+
+	// structured metadata filter
+	OpStructuredMetadata = "structured_metadata"
+
 	// parser flags
 	OpStrict    = "--strict"
 	OpKeepEmpty = "--keep-empty"
 
+	// parser options
+	OpParserOptionSep   = "sep"
+	OpParserOptionKVSep = "kvsep"
+
 	// internal expressions not represented in LogQL. These are used to
 	// evaluate expressions differently resulting in intermediate formats
 	// that are not consumable by LogQL clients but are used for sharding.
-	OpRangeTypeQuantileSketch = "__quantile_sketch_over_time__"
+	OpRangeTypeQuantileSketch      = "__quantile_sketch_over_time__"
+	OpRangeTypeCountDistinctSketch = "__count_distinct_sketch_over_time__"
+	OpTypeTopKSketch               = "__topk_sketch__"
 )
 
 func IsComparisonOperator(op string) bool {
@@ -1292,7 +1651,8 @@ func (e *RangeAggregationExpr) MatcherGroups() ([]MatcherRange, error) {
 func (e RangeAggregationExpr) validate() error {
 	if e.Grouping != nil {
 		switch e.Operation {
-		case OpRangeTypeAvg, OpRangeTypeStddev, OpRangeTypeStdvar, OpRangeTypeQuantile, OpRangeTypeQuantileSketch, OpRangeTypeMax, OpRangeTypeMin, OpRangeTypeFirst, OpRangeTypeLast:
+		case OpRangeTypeAvg, OpRangeTypeStddev, OpRangeTypeStdvar, OpRangeTypeQuantile, OpRangeTypeQuantileSketch, OpRangeTypeMax, OpRangeTypeMin, OpRangeTypeFirst, OpRangeTypeLast,
+			OpRangeTypeCountDistinct, OpRangeTypeApproxCountDistinct, OpRangeTypeCountDistinctSketch:
 		default:
 			return fmt.Errorf("grouping not allowed for %s aggregation", e.Operation)
 		}
@@ -1303,6 +1663,12 @@ func (e RangeAggregationExpr) validate() error {
 			OpRangeTypeStdvar, OpRangeTypeQuantile, OpRangeTypeRate, OpRangeTypeRateCounter,
 			OpRangeTypeAbsent, OpRangeTypeFirst, OpRangeTypeLast, OpRangeTypeQuantileSketch:
 			return nil
+		case OpRangeTypeCountDistinct, OpRangeTypeApproxCountDistinct, OpRangeTypeCountDistinctSketch:
+			// distinct values are counted on the label value as is.
+			if e.Left.Unwrap.Operation != "" {
+				return fmt.Errorf("invalid conversion %s for aggregation %s", e.Left.Unwrap.Operation, e.Operation)
+			}
+			return nil
 		default:
 			return fmt.Errorf("invalid aggregation %s with unwrap", e.Operation)
 		}
@@ -1338,11 +1704,12 @@ func (e *RangeAggregationExpr) String() string {
 
 // impl SampleExpr
 func (e *RangeAggregationExpr) Shardable(topLevel bool) bool {
-	// Here we are blocking sharding of quantile operations if they are not
-	// the top level aggregation in a query, such as max(quantile_over_time(...)).
+	// Here we are blocking sharding of quantile and approximate distinct count
+	// operations if they are not the top level aggregation in a query, such as
+	// max(quantile_over_time(...)).
 	// The sharding here will be blocked even if the feature flag in the shardmapper
-	// to enable sharding of quantile queries is enabled.
-	if e.Operation == OpRangeTypeQuantile && !topLevel {
+	// to enable sharding of these queries is enabled.
+	if (e.Operation == OpRangeTypeQuantile || e.Operation == OpRangeTypeApproxCountDistinct) && !topLevel {
 		return false
 	}
 	return shardableOps[e.Operation] && e.Left.Shardable(topLevel)
@@ -1358,6 +1725,159 @@ func (e *RangeAggregationExpr) Walk(f WalkFn) {
 
 func (e *RangeAggregationExpr) Accept(v RootVisitor) { v.VisitRangeAggregation(e) }
 
+// subqueryRange is the range and resolution of a subquery, e.g. `[1h:1m]`.
+type subqueryRange struct {
+	Range time.Duration
+	Step  time.Duration
+}
+
+// SubqueryExpr applies a range aggregation to a metric expression that is
+// evaluated at a fixed resolution over the range of the subquery, e.g.
+// `max_over_time(rate({app="foo"}[1m])[1h:1m])`.
+// A zero Step means the step of the query is used as resolution.
+type SubqueryExpr struct {
+	Left      SampleExpr
+	Operation string
+	Range     time.Duration
+	Step      time.Duration
+	Offset    time.Duration
+
+	Params *float64
+	err    error
+	implicit
+}
+
+func newSubqueryExpr(left SampleExpr, operation string, rng subqueryRange, o *OffsetExpr, stringParams *string) SampleExpr {
+	var params *float64
+	if stringParams != nil {
+		if operation != OpRangeTypeQuantile {
+			return &SubqueryExpr{err: logqlmodel.NewParseError(fmt.Sprintf("parameter %s not supported for operation %s", *stringParams, operation), 0, 0)}
+		}
+		var err error
+		params = new(float64)
+		*params, err = strconv.ParseFloat(*stringParams, 64)
+		if err != nil {
+			return &SubqueryExpr{err: logqlmodel.NewParseError(fmt.Sprintf("invalid parameter for operation %s: %s", operation, err), 0, 0)}
+		}
+	} else if operation == OpRangeTypeQuantile {
+		return &SubqueryExpr{err: logqlmodel.NewParseError(fmt.Sprintf("parameter required for operation %s", operation), 0, 0)}
+	}
+	var offset time.Duration
+	if o != nil {
+		offset = o.Offset
+	}
+	e := &SubqueryExpr{
+		Left:      left,
+		Operation: operation,
+		Range:     rng.Range,
+		Step:      rng.Step,
+		Offset:    offset,
+		Params:    params,
+	}
+	if err := e.validate(); err != nil {
+		return &SubqueryExpr{err: logqlmodel.NewParseError(err.Error(), 0, 0)}
+	}
+	return e
+}
+
+func (e *SubqueryExpr) validate() error {
+	if e.Range <= 0 {
+		return fmt.Errorf("invalid subquery range %s", model.Duration(e.Range))
+	}
+	if e.Step < 0 {
+		return fmt.Errorf("invalid subquery resolution %s", model.Duration(e.Step))
+	}
+	switch e.Operation {
+	case OpRangeTypeCount, OpRangeTypeAvg, OpRangeTypeSum, OpRangeTypeMax, OpRangeTypeMin,
+		OpRangeTypeStddev, OpRangeTypeStdvar, OpRangeTypeQuantile, OpRangeTypeFirst,
+		OpRangeTypeLast, OpRangeTypeAbsent:
+		return nil
+	default:
+		return fmt.Errorf("invalid aggregation %s over subquery", e.Operation)
+	}
+}
+
+func (e *SubqueryExpr) isSampleExpr() {}
+
+func (e *SubqueryExpr) Selector() (LogSelectorExpr, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+	return e.Left.Selector()
+}
+
+// MatcherGroups returns the matcher groups of the inner expression, extended
+// by the range and offset of the subquery.
+func (e *SubqueryExpr) MatcherGroups() ([]MatcherRange, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+	groups, err := e.Left.MatcherGroups()
+	if err != nil {
+		return nil, err
+	}
+	for i := range groups {
+		groups[i].Interval += e.Range
+		groups[i].Offset += e.Offset
+	}
+	return groups, nil
+}
+
+func (e *SubqueryExpr) Extractor() (SampleExtractor, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+	return e.Left.Extractor()
+}
+
+// Shardable returns false since the subquery needs the merged result of
+// its inner expression. The inner expression can be sharded on its own.
+func (e *SubqueryExpr) Shardable(_ bool) bool {
+	return false
+}
+
+func (e *SubqueryExpr) Walk(f WalkFn) {
+	f(e)
+	if e.Left == nil {
+		return
+	}
+	e.Left.Walk(f)
+}
+
+func (e *SubqueryExpr) Accept(v RootVisitor) { v.VisitSubquery(e) }
+
+// impls Stringer
+func (e *SubqueryExpr) String() string {
+	var sb strings.Builder
+	sb.WriteString(e.Operation)
+	sb.WriteString("(")
+	if e.Params != nil {
+		sb.WriteString(strconv.FormatFloat(*e.Params, 'f', -1, 64))
+		sb.WriteString(",")
+	}
+	sb.WriteString(e.Left.String())
+	sb.WriteString(e.rangeString())
+	if e.Offset != 0 {
+		offsetExpr := OffsetExpr{Offset: e.Offset}
+		sb.WriteString(offsetExpr.String())
+	}
+	sb.WriteString(")")
+	return sb.String()
+}
+
+// rangeString returns the range and resolution of the subquery, e.g. `[1h:1m]`.
+func (e *SubqueryExpr) rangeString() string {
+	var sb strings.Builder
+	sb.WriteString("[")
+	sb.WriteString(model.Duration(e.Range).String())
+	sb.WriteString(":")
+	if e.Step != 0 {
+		sb.WriteString(model.Duration(e.Step).String())
+	}
+	sb.WriteString("]")
+	return sb.String()
+}
+
 // Grouping struct represents the grouping by/without label(s) for vector aggregators and range vector aggregators.
 // The representation is as follows:
 //   - No Grouping (labels dismissed): <operation> (<expr>) => Grouping{Without: false, Groups: nil}
@@ -1414,7 +1934,7 @@ func mustNewVectorAggregationExpr(left SampleExpr, operation string, gr *Groupin
 	var p int
 	var err error
 	switch operation {
-	case OpTypeBottomK, OpTypeTopK:
+	case OpTypeBottomK, OpTypeTopK, OpTypeApproxTopK, OpTypeTopKSketch:
 		if params == nil {
 			return &VectorAggregationExpr{err: logqlmodel.NewParseError(fmt.Sprintf("parameter required for operation %s", operation), 0, 0)}
 		}
@@ -1431,6 +1951,9 @@ func mustNewVectorAggregationExpr(left SampleExpr, operation string, gr *Groupin
 			return &VectorAggregationExpr{err: logqlmodel.NewParseError(fmt.Sprintf("unsupported parameter for operation %s(%s,", operation, *params), 0, 0)}
 		}
 	}
+	if operation == OpTypeApproxTopK && gr != nil {
+		return &VectorAggregationExpr{err: logqlmodel.NewParseError(fmt.Sprintf("grouping not allowed for %s aggregation", operation), 0, 0)}
+	}
 	if gr == nil {
 		gr = &Grouping{}
 	}
@@ -1490,7 +2013,7 @@ func (e *VectorAggregationExpr) String() string {
 	var params []string
 	switch e.Operation {
 	// bottomK and topk can have first parameter as 0
-	case OpTypeBottomK, OpTypeTopK:
+	case OpTypeBottomK, OpTypeTopK, OpTypeApproxTopK, OpTypeTopKSketch:
 		params = []string{fmt.Sprintf("%d", e.Params), e.Left.String()}
 	default:
 		if e.Params != 0 {
@@ -1536,6 +2059,13 @@ func (e *VectorAggregationExpr) Shardable(topLevel bool) bool {
 		}
 		return false
 
+	case OpTypeApproxTopK:
+		// approx_topk is sharded by merging the count-min sketches of
+		// the shards, which adds up the values of a series. This is only
+		// correct if the values of the series are a sum, too.
+		child, ok := e.Left.(*VectorAggregationExpr)
+		return ok && child.Operation == OpTypeSum
+
 	case OpTypeSum:
 		// sum can shard & merge vector & range aggregations, but only if
 		// the resulting computation is commutative and associative.
@@ -2131,6 +2661,260 @@ func (e *LabelReplaceExpr) String() string {
 	return sb.String()
 }
 
+// FunctionExpr applies a function to the value of each sample of its inner
+// expression, e.g. `abs(...)`, `round(..., 0.5)` or `clamp_min(..., 0)`.
+type FunctionExpr struct {
+	Left      SampleExpr
+	Operation string
+	Params    *float64
+	err       error
+
+	implicit
+}
+
+func newFunctionExpr(left SampleExpr, operation string, param *LiteralExpr) SampleExpr {
+	if _, ok := left.(*LiteralExpr); ok {
+		return &FunctionExpr{err: logqlmodel.NewParseError(fmt.Sprintf("expected vector expression as argument of %s", operation), 0, 0)}
+	}
+	var params *float64
+	if param != nil {
+		v, err := param.Value()
+		if err != nil {
+			return &FunctionExpr{err: err}
+		}
+		params = &v
+	}
+	switch operation {
+	case OpFuncRound:
+		// the parameter of round is optional.
+	case OpFuncClampMin, OpFuncClampMax:
+		if params == nil {
+			return &FunctionExpr{err: logqlmodel.NewParseError(fmt.Sprintf("parameter required for function %s", operation), 0, 0)}
+		}
+	default:
+		if params != nil {
+			return &FunctionExpr{err: logqlmodel.NewParseError(fmt.Sprintf("parameter %s not supported for function %s", param, operation), 0, 0)}
+		}
+	}
+	return &FunctionExpr{
+		Left:      left,
+		Operation: operation,
+		Params:    params,
+	}
+}
+
+func (e *FunctionExpr) isSampleExpr() {}
+
+func (e *FunctionExpr) Selector() (LogSelectorExpr, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+	return e.Left.Selector()
+}
+
+func (e *FunctionExpr) MatcherGroups() ([]MatcherRange, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+	return e.Left.MatcherGroups()
+}
+
+func (e *FunctionExpr) Extractor() (SampleExtractor, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+	return e.Left.Extractor()
+}
+
+func (e *FunctionExpr) Shardable(_ bool) bool {
+	return false
+}
+
+func (e *FunctionExpr) Walk(f WalkFn) {
+	f(e)
+	if e.Left == nil {
+		return
+	}
+	e.Left.Walk(f)
+}
+
+func (e *FunctionExpr) Accept(v RootVisitor) { v.VisitFunction(e) }
+
+func (e *FunctionExpr) String() string {
+	var sb strings.Builder
+	sb.WriteString(e.Operation)
+	sb.WriteString("(")
+	sb.WriteString(e.Left.String())
+	if e.Params != nil {
+		sb.WriteString(",")
+		sb.WriteString(strconv.FormatFloat(*e.Params, 'f', -1, 64))
+	}
+	sb.WriteString(")")
+	return sb.String()
+}
+
+// LabelJoinExpr joins the values of the source labels of each sample of its
+// inner expression using the separator and writes the result to the
+// destination label, e.g. `label_join(..., "dst", ",", "src1", "src2")`.
+type LabelJoinExpr struct {
+	Left      SampleExpr
+	Dst       string
+	Separator string
+	Src       []string
+	err       error
+
+	implicit
+}
+
+func newLabelJoinExpr(left SampleExpr, dst, separator string, src []string) *LabelJoinExpr {
+	if _, ok := left.(*LiteralExpr); ok {
+		return &LabelJoinExpr{err: logqlmodel.NewParseError(fmt.Sprintf("expected vector expression as argument of %s", OpLabelJoin), 0, 0)}
+	}
+	if !model.LabelName(dst).IsValid() {
+		return &LabelJoinExpr{err: logqlmodel.NewParseError(fmt.Sprintf("invalid destination label name in %s: %s", OpLabelJoin, dst), 0, 0)}
+	}
+	for _, s := range src {
+		if !model.LabelName(s).IsValid() {
+			return &LabelJoinExpr{err: logqlmodel.NewParseError(fmt.Sprintf("invalid source label name in %s: %s", OpLabelJoin, s), 0, 0)}
+		}
+	}
+	return &LabelJoinExpr{
+		Left:      left,
+		Dst:       dst,
+		Separator: separator,
+		Src:       src,
+	}
+}
+
+func (e *LabelJoinExpr) isSampleExpr() {}
+
+func (e *LabelJoinExpr) Selector() (LogSelectorExpr, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+	return e.Left.Selector()
+}
+
+func (e *LabelJoinExpr) MatcherGroups() ([]MatcherRange, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+	return e.Left.MatcherGroups()
+}
+
+func (e *LabelJoinExpr) Extractor() (SampleExtractor, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+	return e.Left.Extractor()
+}
+
+func (e *LabelJoinExpr) Shardable(_ bool) bool {
+	return false
+}
+
+func (e *LabelJoinExpr) Walk(f WalkFn) {
+	f(e)
+	if e.Left == nil {
+		return
+	}
+	e.Left.Walk(f)
+}
+
+func (e *LabelJoinExpr) Accept(v RootVisitor) { v.VisitLabelJoin(e) }
+
+func (e *LabelJoinExpr) String() string {
+	var sb strings.Builder
+	sb.WriteString(OpLabelJoin)
+	sb.WriteString("(")
+	sb.WriteString(e.Left.String())
+	sb.WriteString(",")
+	sb.WriteString(strconv.Quote(e.Dst))
+	sb.WriteString(",")
+	sb.WriteString(strconv.Quote(e.Separator))
+	for _, s := range e.Src {
+		sb.WriteString(",")
+		sb.WriteString(strconv.Quote(s))
+	}
+	sb.WriteString(")")
+	return sb.String()
+}
+
+// HistogramQuantileExpr calculates the φ-quantile from the buckets of a
+// histogram, e.g. `histogram_quantile(0.99, sum by (le) (...))`.
+// The buckets are the series of the inner expression that share the same
+// labels apart from the `le` label, which holds the upper bound of the bucket.
+type HistogramQuantileExpr struct {
+	Left     SampleExpr
+	Quantile float64
+	err      error
+
+	implicit
+}
+
+func newHistogramQuantileExpr(left SampleExpr, quantile string) *HistogramQuantileExpr {
+	if _, ok := left.(*LiteralExpr); ok {
+		return &HistogramQuantileExpr{err: logqlmodel.NewParseError(fmt.Sprintf("expected vector expression as argument of %s", OpHistogramQuantile), 0, 0)}
+	}
+	q, err := strconv.ParseFloat(quantile, 64)
+	if err != nil {
+		return &HistogramQuantileExpr{err: logqlmodel.NewParseError(fmt.Sprintf("invalid parameter for %s: %s", OpHistogramQuantile, err), 0, 0)}
+	}
+	return &HistogramQuantileExpr{
+		Left:     left,
+		Quantile: q,
+	}
+}
+
+func (e *HistogramQuantileExpr) isSampleExpr() {}
+
+func (e *HistogramQuantileExpr) Selector() (LogSelectorExpr, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+	return e.Left.Selector()
+}
+
+func (e *HistogramQuantileExpr) MatcherGroups() ([]MatcherRange, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+	return e.Left.MatcherGroups()
+}
+
+func (e *HistogramQuantileExpr) Extractor() (SampleExtractor, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+	return e.Left.Extractor()
+}
+
+// Shardable returns false since the quantile needs all buckets of a histogram.
+func (e *HistogramQuantileExpr) Shardable(_ bool) bool {
+	return false
+}
+
+func (e *HistogramQuantileExpr) Walk(f WalkFn) {
+	f(e)
+	if e.Left == nil {
+		return
+	}
+	e.Left.Walk(f)
+}
+
+func (e *HistogramQuantileExpr) Accept(v RootVisitor) { v.VisitHistogramQuantile(e) }
+
+func (e *HistogramQuantileExpr) String() string {
+	var sb strings.Builder
+	sb.WriteString(OpHistogramQuantile)
+	sb.WriteString("(")
+	sb.WriteString(strconv.FormatFloat(e.Quantile, 'f', -1, 64))
+	sb.WriteString(",")
+	sb.WriteString(e.Left.String())
+	sb.WriteString(")")
+	return sb.String()
+}
+
 // shardableOps lists the operations which may be sharded, but are not
 // guaranteed to be. See the `Shardable()` implementations
 // on the respective expr types for more details.
@@ -2159,6 +2943,8 @@ var shardableOps = map[string]bool{
 	OpTypeMax:   true,
 	OpTypeMin:   true,
 
+	OpTypeApproxTopK: true,
+
 	// range vector ops
 	OpRangeTypeAvg:       true,
 	OpRangeTypeCount:     true,
@@ -2170,6 +2956,8 @@ var shardableOps = map[string]bool{
 	OpRangeTypeMin:       true,
 	OpRangeTypeQuantile:  true,
 
+	OpRangeTypeApproxCountDistinct: true,
+
 	// binops - arith
 	OpTypeAdd: true,
 	OpTypeMul: true,
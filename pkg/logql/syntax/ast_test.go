@@ -45,6 +45,16 @@ func Test_logSelectorExpr_String(t *testing.T) {
 		{`{foo="bar"} |= "baz" |~ "blip" != "flip" !~ "flap" | logfmt --strict --keep-empty b="foo" | b=ip("127.0.0.1") | level="error"`, true},
 		{`{foo="bar"} |= "baz" |~ "blip" != "flip" !~ "flap" | logfmt | b=ip("127.0.0.1") | level="error" | c=ip("::1")`, true}, // chain inside label filters.
 		{`{foo="bar"} |= "baz" |~ "blip" != "flip" !~ "flap" | regexp "(?P<foo>foo|bar)"`, true},
+		{`{foo="bar"} |= "baz" | csv "ts,,level" | level="error"`, true},
+		{`{foo="bar"} |= "baz" | csv "ts,level" sep=";"`, true},
+		{`{foo="bar"} |= "baz" | kv`, true},
+		{`{foo="bar"} |= "baz" | kv sep=";" kvsep=":" | level="error"`, true},
+		{`{foo="bar"} |= "baz" | xml`, true},
+		{`{foo="bar"} |= "baz" | xml user="event/user",id="event/items/item[2]/@id" | user="bob"`, true},
+		{`{foo="bar"} |= "baz" | limit 10`, true},
+		{`{foo="bar"} |= "baz" | json | dedup by (request_id,pod) 5m | limit 1`, true},
+		{`{foo="bar"} |= "baz" | dedup`, true},
+		{`{foo="bar"} |= "baz" | structured_metadata trace_id="abc", env=~"prod.*"`, true},
 		{`{foo="bar"} |= "baz" |~ "blip" != "flip" !~ "flap" | regexp "(?P<foo>foo|bar)" | ( ( foo<5.01 , bar>20ms ) or foo="bar" ) | line_format "blip{{.boop}}bap" | label_format foo=bar,bar="blip{{.blop}}"`, true},
 	}
 
@@ -768,6 +778,28 @@ func TestFilterReodering(t *testing.T) {
 	})
 }
 
+func TestSplitLineLimits(t *testing.T) {
+	for _, tc := range []struct {
+		in       string
+		selector string
+		limits   string
+	}{
+		{`{app="foo"} |= "bar"`, `{app="foo"} |= "bar"`, ``},
+		{`{app="foo"} | limit 10`, `{app="foo"}`, `| limit 10`},
+		{`{app="foo"} |= "bar" | json | dedup by (pod) 1m | limit 10`, `{app="foo"} |= "bar" | json`, `| dedup by (pod) 1m | limit 10`},
+	} {
+		t.Run(tc.in, func(t *testing.T) {
+			expr, err := ParseLogSelector(tc.in, true)
+			require.NoError(t, err)
+
+			selector, limits := SplitLineLimits(expr)
+			require.Equal(t, tc.selector, selector.String())
+			require.Equal(t, tc.limits, limits.String())
+			require.Equal(t, limits, LineLimits(expr))
+		})
+	}
+}
+
 var result bool
 
 func BenchmarkReorderedPipeline(b *testing.B) {
@@ -82,6 +82,59 @@ func (v *cloneVisitor) VisitRangeAggregation(e *RangeAggregationExpr) {
 	v.cloned = copied
 }
 
+func (v *cloneVisitor) VisitSubquery(e *SubqueryExpr) {
+	copied := &SubqueryExpr{
+		Left:      MustClone[SampleExpr](e.Left),
+		Operation: e.Operation,
+		Range:     e.Range,
+		Step:      e.Step,
+		Offset:    e.Offset,
+	}
+
+	if e.Params != nil {
+		tmp := *e.Params
+		copied.Params = &tmp
+	}
+
+	v.cloned = copied
+}
+
+func (v *cloneVisitor) VisitFunction(e *FunctionExpr) {
+	copied := &FunctionExpr{
+		Left:      MustClone[SampleExpr](e.Left),
+		Operation: e.Operation,
+	}
+
+	if e.Params != nil {
+		tmp := *e.Params
+		copied.Params = &tmp
+	}
+
+	v.cloned = copied
+}
+
+func (v *cloneVisitor) VisitLabelJoin(e *LabelJoinExpr) {
+	copied := &LabelJoinExpr{
+		Left:      MustClone[SampleExpr](e.Left),
+		Dst:       e.Dst,
+		Separator: e.Separator,
+	}
+
+	if e.Src != nil {
+		copied.Src = make([]string, len(e.Src))
+		copy(copied.Src, e.Src)
+	}
+
+	v.cloned = copied
+}
+
+func (v *cloneVisitor) VisitHistogramQuantile(e *HistogramQuantileExpr) {
+	v.cloned = &HistogramQuantileExpr{
+		Left:     MustClone[SampleExpr](e.Left),
+		Quantile: e.Quantile,
+	}
+}
+
 func (v *cloneVisitor) VisitLabelReplace(e *LabelReplaceExpr) {
 	left := MustClone[SampleExpr](e.Left)
 	v.cloned = mustNewLabelReplaceExpr(left, e.Dst, e.Replacement, e.Src, e.Regex)
@@ -140,10 +193,29 @@ func (v *cloneVisitor) VisitPipeline(e *PipelineExpr) {
 	v.cloned = copied
 }
 
+func (v *cloneVisitor) VisitCSVParser(e *CSVParserExpr) {
+	copied := &CSVParserExpr{
+		Columns:   make([]string, len(e.Columns)),
+		Separator: e.Separator,
+	}
+	copy(copied.Columns, e.Columns)
+
+	v.cloned = copied
+}
+
 func (v *cloneVisitor) VisitDecolorize(*DecolorizeExpr) {
 	v.cloned = &DecolorizeExpr{}
 }
 
+func (v *cloneVisitor) VisitDedup(e *DedupExpr) {
+	copied := &DedupExpr{Window: e.Window}
+	if e.By != nil {
+		copied.By = make([]string, len(e.By))
+		copy(copied.By, e.By)
+	}
+	v.cloned = copied
+}
+
 func (v *cloneVisitor) VisitDropLabels(e *DropLabelsExpr) {
 	copied := &DropLabelsExpr{
 		dropLabels: make([]log.DropLabel, len(e.dropLabels)),
@@ -184,6 +256,13 @@ func (v *cloneVisitor) VisitKeepLabel(e *KeepLabelsExpr) {
 	v.cloned = copied
 }
 
+func (v *cloneVisitor) VisitKVParser(e *KVParserExpr) {
+	v.cloned = &KVParserExpr{
+		Separator:   e.Separator,
+		KVSeparator: e.KVSeparator,
+	}
+}
+
 func (v *cloneVisitor) VisitLabelFilter(e *LabelFilterExpr) {
 	v.cloned = &LabelFilterExpr{
 		LabelFilterer: cloneLabelFilterer(e.LabelFilterer),
@@ -283,6 +362,10 @@ func (v *cloneVisitor) VisitLineFmt(e *LineFmtExpr) {
 	v.cloned = &LineFmtExpr{Value: e.Value}
 }
 
+func (v *cloneVisitor) VisitLineLimit(e *LineLimitExpr) {
+	v.cloned = &LineLimitExpr{Limit: e.Limit}
+}
+
 func (v *cloneVisitor) VisitLogfmtExpressionParser(e *LogfmtExpressionParser) {
 	copied := &LogfmtExpressionParser{
 		Expressions: make([]log.LabelExtractionExpr, len(e.Expressions)),
@@ -300,3 +383,24 @@ func (v *cloneVisitor) VisitLogfmtParser(e *LogfmtParserExpr) {
 		KeepEmpty: e.KeepEmpty,
 	}
 }
+
+func (v *cloneVisitor) VisitStructuredMetadataFilter(e *StructuredMetadataFilterExpr) {
+	copied := &StructuredMetadataFilterExpr{
+		Matchers: make([]*labels.Matcher, len(e.Matchers)),
+	}
+	for i, m := range e.Matchers {
+		copied.Matchers[i] = labels.MustNewMatcher(m.Type, m.Name, m.Value)
+	}
+
+	v.cloned = copied
+}
+
+func (v *cloneVisitor) VisitXMLParser(e *XMLParserExpr) {
+	copied := &XMLParserExpr{}
+	if e.Expressions != nil {
+		copied.Expressions = make([]log.LabelExtractionExpr, len(e.Expressions))
+		copy(copied.Expressions, e.Expressions)
+	}
+
+	v.cloned = copied
+}
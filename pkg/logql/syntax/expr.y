@@ -34,12 +34,14 @@ import (
   bytes                   uint64
   str                     string
   duration                time.Duration
+  subqueryRange           subqueryRange
   LiteralExpr             *LiteralExpr
   BinOpModifier           *BinOpOptions
   BoolModifier            *BinOpOptions
   OnOrIgnoringModifier    *BinOpOptions
   LabelParser             *LabelParserExpr
   LogfmtParser            *LogfmtParserExpr
+  ParserOptions           []parserOption
   LineFilters             *LineFilterExpr
   LineFilter              *LineFilterExpr
   OrFilter                *LineFilterExpr
@@ -96,11 +98,18 @@ import (
 %type <BinOpExpr>             binOpExpr
 %type <LiteralExpr>           literalExpr
 %type <LabelReplaceExpr>      labelReplaceExpr
+%type <MetricExpr>            functionExpr
+%type <str>                   functionOp
+%type <MetricExpr>            labelJoinExpr
+%type <MetricExpr>            histogramQuantileExpr
+%type <Labels>                stringList
 %type <BinOpModifier>         binOpModifier
 %type <BoolModifier>          boolModifier
 %type <OnOrIgnoringModifier>  onOrIgnoringModifier
 %type <LabelParser>           labelParser
 %type <LogfmtParser>          logfmtParser
+%type <PipelineStage>         csvParser kvParser xmlParser lineLimitExpr dedupExpr structuredMetadataFilterExpr
+%type <ParserOptions>         parserOptions
 %type <PipelineExpr>          pipelineExpr
 %type <PipelineStage>         pipelineStage
 %type <BytesFilter>           bytesFilter
@@ -134,12 +143,14 @@ import (
 %token <bytes> BYTES
 %token <str>      IDENTIFIER STRING NUMBER PARSER_FLAG
 %token <duration> DURATION RANGE
+%token <subqueryRange> SUBQUERY_RANGE
 %token <val>      MATCHERS LABELS EQ RE NRE OPEN_BRACE CLOSE_BRACE OPEN_BRACKET CLOSE_BRACKET COMMA DOT PIPE_MATCH PIPE_EXACT
                   OPEN_PARENTHESIS CLOSE_PARENTHESIS BY WITHOUT COUNT_OVER_TIME RATE RATE_COUNTER SUM SORT SORT_DESC AVG MAX MIN COUNT STDDEV STDVAR BOTTOMK TOPK
                   BYTES_OVER_TIME BYTES_RATE BOOL JSON REGEXP LOGFMT PIPE LINE_FMT LABEL_FMT UNWRAP AVG_OVER_TIME SUM_OVER_TIME MIN_OVER_TIME
                   MAX_OVER_TIME STDVAR_OVER_TIME STDDEV_OVER_TIME QUANTILE_OVER_TIME BYTES_CONV DURATION_CONV DURATION_SECONDS_CONV
                   FIRST_OVER_TIME LAST_OVER_TIME ABSENT_OVER_TIME VECTOR LABEL_REPLACE UNPACK OFFSET PATTERN IP ON IGNORING GROUP_LEFT GROUP_RIGHT
-                  DECOLORIZE DROP KEEP
+                  DECOLORIZE DROP KEEP ABS CEIL FLOOR ROUND CLAMP_MIN CLAMP_MAX SQRT EXP LN TIMESTAMP LABEL_JOIN HISTOGRAM_QUANTILE
+                  COUNT_DISTINCT_OVER_TIME APPROX_COUNT_DISTINCT APPROX_TOPK CSV KV XML LIMIT DEDUP STRUCTURED_METADATA
 
 // Operators are listed with increasing precedence.
 %left <binOp> OR
@@ -164,6 +175,9 @@ metricExpr:
     | binOpExpr                                     { $$ = $1 }
     | literalExpr                                   { $$ = $1 }
     | labelReplaceExpr                              { $$ = $1 }
+    | functionExpr                                  { $$ = $1 }
+    | labelJoinExpr                                 { $$ = $1 }
+    | histogramQuantileExpr                         { $$ = $1 }
     | vectorExpr                                    { $$ = $1 }
     | OPEN_PARENTHESIS metricExpr CLOSE_PARENTHESIS { $$ = $2 }
     ;
@@ -220,6 +234,11 @@ rangeAggregationExpr:
     | rangeOp OPEN_PARENTHESIS NUMBER COMMA logRangeExpr CLOSE_PARENTHESIS           { $$ = newRangeAggregationExpr($5, $1, nil, &$3) }
     | rangeOp OPEN_PARENTHESIS logRangeExpr CLOSE_PARENTHESIS grouping               { $$ = newRangeAggregationExpr($3, $1, $5, nil) }
     | rangeOp OPEN_PARENTHESIS NUMBER COMMA logRangeExpr CLOSE_PARENTHESIS grouping  { $$ = newRangeAggregationExpr($5, $1, $7, &$3) }
+    // Range aggregations over subqueries.
+    | rangeOp OPEN_PARENTHESIS metricExpr SUBQUERY_RANGE CLOSE_PARENTHESIS                                { $$ = newSubqueryExpr($3, $1, $4, nil, nil) }
+    | rangeOp OPEN_PARENTHESIS metricExpr SUBQUERY_RANGE offsetExpr CLOSE_PARENTHESIS                     { $$ = newSubqueryExpr($3, $1, $4, $5, nil) }
+    | rangeOp OPEN_PARENTHESIS NUMBER COMMA metricExpr SUBQUERY_RANGE CLOSE_PARENTHESIS                   { $$ = newSubqueryExpr($5, $1, $6, nil, &$3) }
+    | rangeOp OPEN_PARENTHESIS NUMBER COMMA metricExpr SUBQUERY_RANGE offsetExpr CLOSE_PARENTHESIS        { $$ = newSubqueryExpr($5, $1, $6, $7, &$3) }
     ;
 
 vectorAggregationExpr:
@@ -238,6 +257,25 @@ labelReplaceExpr:
       { $$ = mustNewLabelReplaceExpr($3, $5, $7, $9, $11)}
     ;
 
+functionExpr:
+      functionOp OPEN_PARENTHESIS metricExpr CLOSE_PARENTHESIS                    { $$ = newFunctionExpr($3, $1, nil) }
+    | functionOp OPEN_PARENTHESIS metricExpr COMMA literalExpr CLOSE_PARENTHESIS  { $$ = newFunctionExpr($3, $1, $5) }
+    ;
+
+labelJoinExpr:
+      LABEL_JOIN OPEN_PARENTHESIS metricExpr COMMA STRING COMMA STRING CLOSE_PARENTHESIS                { $$ = newLabelJoinExpr($3, $5, $7, nil) }
+    | LABEL_JOIN OPEN_PARENTHESIS metricExpr COMMA STRING COMMA STRING COMMA stringList CLOSE_PARENTHESIS  { $$ = newLabelJoinExpr($3, $5, $7, $9) }
+    ;
+
+histogramQuantileExpr:
+      HISTOGRAM_QUANTILE OPEN_PARENTHESIS NUMBER COMMA metricExpr CLOSE_PARENTHESIS  { $$ = newHistogramQuantileExpr($5, $3) }
+    ;
+
+stringList:
+      STRING                  { $$ = []string{ $1 } }
+    | stringList COMMA STRING    { $$ = append($1, $3) }
+    ;
+
 filter:
       PIPE_MATCH                       { $$ = labels.MatchRegexp }
     | PIPE_EXACT                       { $$ = labels.MatchEqual }
@@ -274,12 +312,18 @@ pipelineStage:
   | PIPE labelParser             { $$ = $2 }
   | PIPE jsonExpressionParser    { $$ = $2 }
   | PIPE logfmtExpressionParser  { $$ = $2 }
+  | PIPE csvParser               { $$ = $2 }
+  | PIPE kvParser                { $$ = $2 }
+  | PIPE xmlParser               { $$ = $2 }
   | PIPE labelFilter             { $$ = &LabelFilterExpr{LabelFilterer: $2 }}
   | PIPE lineFormatExpr          { $$ = $2 }
   | PIPE decolorizeExpr          { $$ = $2 }
   | PIPE labelFormatExpr         { $$ = $2 }
   | PIPE dropLabelsExpr          { $$ = $2 }
   | PIPE keepLabelsExpr          { $$ = $2 }
+  | PIPE lineLimitExpr           { $$ = $2 }
+  | PIPE dedupExpr               { $$ = $2 }
+  | PIPE structuredMetadataFilterExpr { $$ = $2 }
   ;
 
 filterOp:
@@ -329,6 +373,26 @@ logfmtExpressionParser:
   | LOGFMT labelExtractionExpressionList              { $$ = newLogfmtExpressionParser($2, nil)}
   ;
 
+parserOptions:
+    IDENTIFIER EQ STRING                 { $$ = []parserOption{{name: $1, value: $3}} }
+  | parserOptions IDENTIFIER EQ STRING   { $$ = append($1, parserOption{name: $2, value: $4}) }
+  ;
+
+csvParser:
+    CSV STRING                   { $$ = newCSVParserExpr($2, nil) }
+  | CSV STRING parserOptions     { $$ = newCSVParserExpr($2, $3) }
+  ;
+
+kvParser:
+    KV                           { $$ = newKVParserExpr(nil) }
+  | KV parserOptions             { $$ = newKVParserExpr($2) }
+  ;
+
+xmlParser:
+    XML                                { $$ = newXMLParserExpr(nil) }
+  | XML labelExtractionExpressionList  { $$ = newXMLParserExpr($2) }
+  ;
+
 lineFormatExpr: LINE_FMT STRING { $$ = newLineFmtExpr($2) };
 
 decolorizeExpr: DECOLORIZE { $$ = newDecolorizeExpr() };
@@ -429,6 +493,17 @@ keepLabels:
 
 keepLabelsExpr: KEEP keepLabels { $$ = newKeepLabelsExpr($2) }
 
+lineLimitExpr: LIMIT NUMBER { $$ = newLineLimitExpr($2) }
+
+dedupExpr:
+      DEDUP                                                              { $$ = newDedupExpr(nil, 0) }
+    | DEDUP DURATION                                                     { $$ = newDedupExpr(nil, $2) }
+    | DEDUP BY OPEN_PARENTHESIS labels CLOSE_PARENTHESIS                 { $$ = newDedupExpr($4, 0) }
+    | DEDUP BY OPEN_PARENTHESIS labels CLOSE_PARENTHESIS DURATION        { $$ = newDedupExpr($4, $6) }
+    ;
+
+structuredMetadataFilterExpr: STRUCTURED_METADATA matchers { $$ = newStructuredMetadataFilterExpr($2) }
+
 // Operator precedence only works if each of these is listed separately.
 binOpExpr:
          expr OR binOpModifier expr          { $$ = mustNewBinOpExpr("or", $3, $1, $4) }
@@ -541,6 +616,7 @@ vectorOp:
       | STDVAR  { $$ = OpTypeStdvar }
       | BOTTOMK { $$ = OpTypeBottomK }
       | TOPK    { $$ = OpTypeTopK }
+      | APPROX_TOPK    { $$ = OpTypeApproxTopK }
       | SORT    { $$ = OpTypeSort }
       | SORT_DESC    { $$ = OpTypeSortDesc }
       ;
@@ -561,6 +637,21 @@ rangeOp:
     | FIRST_OVER_TIME    { $$ = OpRangeTypeFirst }
     | LAST_OVER_TIME     { $$ = OpRangeTypeLast }
     | ABSENT_OVER_TIME   { $$ = OpRangeTypeAbsent }
+    | COUNT_DISTINCT_OVER_TIME { $$ = OpRangeTypeCountDistinct }
+    | APPROX_COUNT_DISTINCT    { $$ = OpRangeTypeApproxCountDistinct }
+    ;
+
+functionOp:
+      ABS        { $$ = OpFuncAbs }
+    | CEIL       { $$ = OpFuncCeil }
+    | FLOOR      { $$ = OpFuncFloor }
+    | ROUND      { $$ = OpFuncRound }
+    | CLAMP_MIN  { $$ = OpFuncClampMin }
+    | CLAMP_MAX  { $$ = OpFuncClampMax }
+    | SQRT       { $$ = OpFuncSqrt }
+    | EXP        { $$ = OpFuncExp }
+    | LN         { $$ = OpFuncLn }
+    | TIMESTAMP  { $$ = OpFuncTimestamp }
     ;
 
 offsetExpr:
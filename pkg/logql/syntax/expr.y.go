@@ -37,12 +37,14 @@ type exprSymType struct {
 	bytes                 uint64
 	str                   string
 	duration              time.Duration
+	subqueryRange         subqueryRange
 	LiteralExpr           *LiteralExpr
 	BinOpModifier         *BinOpOptions
 	BoolModifier          *BinOpOptions
 	OnOrIgnoringModifier  *BinOpOptions
 	LabelParser           *LabelParserExpr
 	LogfmtParser          *LogfmtParserExpr
+	ParserOptions         []parserOption
 	LineFilters           *LineFilterExpr
 	LineFilter            *LineFilterExpr
 	OrFilter              *LineFilterExpr
@@ -83,88 +85,110 @@ const NUMBER = 57349
 const PARSER_FLAG = 57350
 const DURATION = 57351
 const RANGE = 57352
-const MATCHERS = 57353
-const LABELS = 57354
-const EQ = 57355
-const RE = 57356
-const NRE = 57357
-const OPEN_BRACE = 57358
-const CLOSE_BRACE = 57359
-const OPEN_BRACKET = 57360
-const CLOSE_BRACKET = 57361
-const COMMA = 57362
-const DOT = 57363
-const PIPE_MATCH = 57364
-const PIPE_EXACT = 57365
-const OPEN_PARENTHESIS = 57366
-const CLOSE_PARENTHESIS = 57367
-const BY = 57368
-const WITHOUT = 57369
-const COUNT_OVER_TIME = 57370
-const RATE = 57371
-const RATE_COUNTER = 57372
-const SUM = 57373
-const SORT = 57374
-const SORT_DESC = 57375
-const AVG = 57376
-const MAX = 57377
-const MIN = 57378
-const COUNT = 57379
-const STDDEV = 57380
-const STDVAR = 57381
-const BOTTOMK = 57382
-const TOPK = 57383
-const BYTES_OVER_TIME = 57384
-const BYTES_RATE = 57385
-const BOOL = 57386
-const JSON = 57387
-const REGEXP = 57388
-const LOGFMT = 57389
-const PIPE = 57390
-const LINE_FMT = 57391
-const LABEL_FMT = 57392
-const UNWRAP = 57393
-const AVG_OVER_TIME = 57394
-const SUM_OVER_TIME = 57395
-const MIN_OVER_TIME = 57396
-const MAX_OVER_TIME = 57397
-const STDVAR_OVER_TIME = 57398
-const STDDEV_OVER_TIME = 57399
-const QUANTILE_OVER_TIME = 57400
-const BYTES_CONV = 57401
-const DURATION_CONV = 57402
-const DURATION_SECONDS_CONV = 57403
-const FIRST_OVER_TIME = 57404
-const LAST_OVER_TIME = 57405
-const ABSENT_OVER_TIME = 57406
-const VECTOR = 57407
-const LABEL_REPLACE = 57408
-const UNPACK = 57409
-const OFFSET = 57410
-const PATTERN = 57411
-const IP = 57412
-const ON = 57413
-const IGNORING = 57414
-const GROUP_LEFT = 57415
-const GROUP_RIGHT = 57416
-const DECOLORIZE = 57417
-const DROP = 57418
-const KEEP = 57419
-const OR = 57420
-const AND = 57421
-const UNLESS = 57422
-const CMP_EQ = 57423
-const NEQ = 57424
-const LT = 57425
-const LTE = 57426
-const GT = 57427
-const GTE = 57428
-const ADD = 57429
-const SUB = 57430
-const MUL = 57431
-const DIV = 57432
-const MOD = 57433
-const POW = 57434
+const SUBQUERY_RANGE = 57353
+const MATCHERS = 57354
+const LABELS = 57355
+const EQ = 57356
+const RE = 57357
+const NRE = 57358
+const OPEN_BRACE = 57359
+const CLOSE_BRACE = 57360
+const OPEN_BRACKET = 57361
+const CLOSE_BRACKET = 57362
+const COMMA = 57363
+const DOT = 57364
+const PIPE_MATCH = 57365
+const PIPE_EXACT = 57366
+const OPEN_PARENTHESIS = 57367
+const CLOSE_PARENTHESIS = 57368
+const BY = 57369
+const WITHOUT = 57370
+const COUNT_OVER_TIME = 57371
+const RATE = 57372
+const RATE_COUNTER = 57373
+const SUM = 57374
+const SORT = 57375
+const SORT_DESC = 57376
+const AVG = 57377
+const MAX = 57378
+const MIN = 57379
+const COUNT = 57380
+const STDDEV = 57381
+const STDVAR = 57382
+const BOTTOMK = 57383
+const TOPK = 57384
+const BYTES_OVER_TIME = 57385
+const BYTES_RATE = 57386
+const BOOL = 57387
+const JSON = 57388
+const REGEXP = 57389
+const LOGFMT = 57390
+const PIPE = 57391
+const LINE_FMT = 57392
+const LABEL_FMT = 57393
+const UNWRAP = 57394
+const AVG_OVER_TIME = 57395
+const SUM_OVER_TIME = 57396
+const MIN_OVER_TIME = 57397
+const MAX_OVER_TIME = 57398
+const STDVAR_OVER_TIME = 57399
+const STDDEV_OVER_TIME = 57400
+const QUANTILE_OVER_TIME = 57401
+const BYTES_CONV = 57402
+const DURATION_CONV = 57403
+const DURATION_SECONDS_CONV = 57404
+const FIRST_OVER_TIME = 57405
+const LAST_OVER_TIME = 57406
+const ABSENT_OVER_TIME = 57407
+const VECTOR = 57408
+const LABEL_REPLACE = 57409
+const UNPACK = 57410
+const OFFSET = 57411
+const PATTERN = 57412
+const IP = 57413
+const ON = 57414
+const IGNORING = 57415
+const GROUP_LEFT = 57416
+const GROUP_RIGHT = 57417
+const DECOLORIZE = 57418
+const DROP = 57419
+const KEEP = 57420
+const ABS = 57421
+const CEIL = 57422
+const FLOOR = 57423
+const ROUND = 57424
+const CLAMP_MIN = 57425
+const CLAMP_MAX = 57426
+const SQRT = 57427
+const EXP = 57428
+const LN = 57429
+const TIMESTAMP = 57430
+const LABEL_JOIN = 57431
+const HISTOGRAM_QUANTILE = 57432
+const COUNT_DISTINCT_OVER_TIME = 57433
+const APPROX_COUNT_DISTINCT = 57434
+const APPROX_TOPK = 57435
+const CSV = 57436
+const KV = 57437
+const XML = 57438
+const LIMIT = 57439
+const DEDUP = 57440
+const STRUCTURED_METADATA = 57441
+const OR = 57442
+const AND = 57443
+const UNLESS = 57444
+const CMP_EQ = 57445
+const NEQ = 57446
+const LT = 57447
+const LTE = 57448
+const GT = 57449
+const GTE = 57450
+const ADD = 57451
+const SUB = 57452
+const MUL = 57453
+const DIV = 57454
+const MOD = 57455
+const POW = 57456
 
 var exprToknames = [...]string{
 	"$end",
@@ -177,6 +201,7 @@ var exprToknames = [...]string{
 	"PARSER_FLAG",
 	"DURATION",
 	"RANGE",
+	"SUBQUERY_RANGE",
 	"MATCHERS",
 	"LABELS",
 	"EQ",
@@ -244,6 +269,27 @@ var exprToknames = [...]string{
 	"DECOLORIZE",
 	"DROP",
 	"KEEP",
+	"ABS",
+	"CEIL",
+	"FLOOR",
+	"ROUND",
+	"CLAMP_MIN",
+	"CLAMP_MAX",
+	"SQRT",
+	"EXP",
+	"LN",
+	"TIMESTAMP",
+	"LABEL_JOIN",
+	"HISTOGRAM_QUANTILE",
+	"COUNT_DISTINCT_OVER_TIME",
+	"APPROX_COUNT_DISTINCT",
+	"APPROX_TOPK",
+	"CSV",
+	"KV",
+	"XML",
+	"LIMIT",
+	"DEDUP",
+	"STRUCTURED_METADATA",
 	"OR",
 	"AND",
 	"UNLESS",
@@ -275,257 +321,323 @@ var exprExca = [...]int{
 
 const exprPrivate = 57344
 
-const exprLast = 592
+const exprLast = 908
 
 var exprAct = [...]int{
 
-	287, 226, 82, 4, 212, 64, 180, 124, 202, 187,
-	73, 198, 195, 63, 235, 5, 150, 185, 75, 2,
-	56, 78, 48, 49, 50, 57, 58, 61, 62, 59,
-	60, 51, 52, 53, 54, 55, 56, 49, 50, 57,
-	58, 61, 62, 59, 60, 51, 52, 53, 54, 55,
-	56, 57, 58, 61, 62, 59, 60, 51, 52, 53,
-	54, 55, 56, 51, 52, 53, 54, 55, 56, 107,
-	146, 148, 149, 113, 53, 54, 55, 56, 205, 148,
-	149, 281, 215, 137, 290, 154, 164, 165, 213, 293,
-	138, 159, 67, 293, 71, 214, 152, 295, 71, 162,
-	163, 69, 70, 292, 345, 69, 70, 364, 161, 364,
-	92, 384, 166, 167, 168, 169, 170, 171, 172, 173,
-	174, 175, 176, 177, 178, 179, 71, 228, 379, 290,
-	337, 228, 304, 69, 70, 192, 134, 354, 189, 147,
-	200, 204, 83, 84, 372, 367, 211, 206, 209, 210,
-	207, 208, 140, 217, 140, 128, 134, 291, 108, 228,
-	233, 72, 371, 337, 139, 72, 227, 291, 292, 229,
-	230, 182, 344, 238, 222, 128, 120, 121, 119, 225,
-	129, 131, 295, 81, 71, 83, 84, 369, 246, 247,
-	248, 69, 70, 72, 296, 292, 222, 329, 122, 225,
-	123, 292, 250, 357, 71, 292, 130, 132, 133, 347,
-	304, 69, 70, 304, 71, 353, 71, 228, 352, 299,
-	283, 69, 70, 69, 70, 134, 285, 288, 328, 294,
-	181, 297, 237, 107, 300, 113, 301, 228, 71, 289,
-	152, 286, 237, 298, 128, 69, 70, 66, 304, 228,
-	222, 72, 314, 351, 302, 134, 237, 308, 310, 313,
-	315, 316, 312, 241, 200, 204, 323, 318, 322, 290,
-	182, 72, 231, 223, 128, 253, 311, 134, 134, 304,
-	338, 72, 237, 72, 306, 304, 326, 142, 141, 330,
-	305, 332, 334, 182, 336, 107, 128, 128, 361, 335,
-	346, 331, 309, 237, 107, 72, 264, 348, 219, 265,
-	237, 263, 134, 325, 324, 282, 13, 120, 121, 119,
-	245, 129, 131, 239, 153, 244, 382, 182, 183, 181,
-	236, 128, 358, 359, 340, 341, 342, 107, 360, 122,
-	243, 123, 242, 216, 362, 363, 158, 130, 132, 133,
-	368, 183, 181, 151, 157, 260, 156, 218, 261, 16,
-	259, 88, 13, 87, 374, 80, 375, 376, 13, 378,
-	153, 255, 262, 350, 251, 303, 6, 257, 380, 256,
-	21, 22, 23, 36, 45, 46, 37, 39, 40, 38,
-	41, 42, 43, 44, 24, 25, 254, 240, 232, 224,
-	252, 79, 234, 377, 26, 27, 28, 29, 30, 31,
-	32, 13, 366, 77, 33, 34, 35, 47, 19, 6,
-	365, 258, 343, 21, 22, 23, 36, 45, 46, 37,
-	39, 40, 38, 41, 42, 43, 44, 24, 25, 17,
-	18, 279, 333, 160, 280, 155, 278, 26, 27, 28,
-	29, 30, 31, 32, 13, 89, 86, 33, 34, 35,
-	47, 19, 6, 320, 321, 383, 21, 22, 23, 36,
-	45, 46, 37, 39, 40, 38, 41, 42, 43, 44,
-	24, 25, 17, 18, 276, 85, 373, 277, 381, 275,
-	26, 27, 28, 29, 30, 31, 32, 370, 356, 355,
-	33, 34, 35, 47, 19, 93, 94, 95, 96, 97,
-	98, 99, 100, 101, 102, 103, 104, 105, 106, 144,
-	188, 125, 327, 249, 273, 17, 18, 274, 270, 272,
-	349, 271, 317, 269, 143, 267, 3, 145, 268, 188,
-	266, 319, 186, 74, 196, 126, 307, 284, 221, 220,
-	219, 218, 193, 191, 190, 203, 199, 188, 79, 196,
-	111, 112, 194, 116, 201, 118, 197, 117, 115, 114,
-	184, 65, 135, 127, 136, 109, 110, 91, 90, 11,
-	10, 9, 20, 12, 15, 8, 339, 14, 7, 76,
-	68, 1,
+	344, 4, 274, 101, 83, 258, 155, 218, 92, 282,
+	240, 244, 82, 225, 231, 237, 5, 10, 3, 184,
+	223, 95, 75, 97, 19, 93, 94, 2, 67, 68,
+	69, 76, 77, 80, 81, 78, 79, 70, 71, 72,
+	73, 74, 75, 68, 69, 76, 77, 80, 81, 78,
+	79, 70, 71, 72, 73, 74, 75, 76, 77, 80,
+	81, 78, 79, 70, 71, 72, 73, 74, 75, 70,
+	71, 72, 73, 74, 75, 72, 73, 74, 75, 336,
+	261, 171, 202, 203, 200, 201, 430, 129, 251, 182,
+	183, 346, 439, 138, 180, 182, 183, 90, 86, 168,
+	345, 186, 189, 260, 88, 89, 90, 409, 194, 195,
+	196, 259, 345, 88, 89, 220, 187, 353, 273, 159,
+	352, 439, 346, 398, 90, 467, 20, 21, 90, 345,
+	275, 88, 89, 90, 355, 88, 89, 114, 199, 275,
+	88, 89, 204, 205, 206, 207, 208, 209, 210, 211,
+	212, 213, 214, 215, 216, 217, 90, 275, 343, 345,
+	172, 275, 352, 88, 89, 90, 275, 234, 168, 227,
+	242, 246, 88, 89, 97, 233, 174, 257, 252, 255,
+	256, 253, 254, 130, 181, 91, 263, 351, 159, 250,
+	92, 173, 457, 280, 91, 219, 102, 103, 85, 404,
+	272, 345, 168, 285, 168, 276, 268, 93, 277, 148,
+	149, 147, 91, 160, 162, 353, 91, 284, 220, 248,
+	220, 91, 159, 307, 159, 174, 352, 297, 298, 299,
+	393, 150, 100, 151, 102, 103, 434, 249, 375, 161,
+	163, 164, 464, 301, 91, 304, 319, 463, 265, 320,
+	284, 318, 448, 91, 406, 407, 408, 152, 153, 154,
+	165, 166, 167, 315, 456, 264, 316, 338, 314, 455,
+	284, 373, 342, 340, 348, 347, 349, 129, 447, 356,
+	398, 359, 358, 138, 446, 389, 350, 187, 273, 354,
+	341, 372, 351, 368, 90, 111, 436, 221, 219, 221,
+	219, 88, 89, 369, 371, 374, 376, 366, 412, 362,
+	362, 284, 442, 317, 425, 423, 377, 242, 246, 352,
+	362, 385, 387, 386, 381, 422, 268, 275, 362, 362,
+	313, 352, 370, 421, 420, 362, 362, 428, 168, 289,
+	364, 363, 390, 419, 288, 417, 413, 397, 395, 392,
+	357, 399, 402, 401, 220, 129, 268, 410, 159, 129,
+	403, 400, 284, 414, 115, 116, 117, 118, 119, 120,
+	121, 122, 123, 124, 125, 126, 127, 128, 360, 284,
+	269, 168, 91, 286, 292, 278, 176, 175, 388, 337,
+	312, 296, 295, 294, 293, 431, 262, 429, 19, 432,
+	283, 159, 193, 433, 192, 129, 191, 110, 16, 109,
+	108, 107, 437, 106, 438, 99, 6, 441, 462, 454,
+	27, 28, 29, 44, 54, 55, 45, 47, 48, 46,
+	49, 50, 51, 52, 30, 31, 450, 418, 416, 302,
+	452, 453, 361, 178, 32, 33, 34, 35, 36, 37,
+	38, 179, 458, 311, 39, 40, 41, 66, 22, 177,
+	310, 308, 179, 291, 290, 287, 279, 270, 379, 309,
+	56, 57, 58, 59, 60, 61, 62, 63, 64, 65,
+	24, 25, 42, 43, 53, 19, 98, 306, 303, 334,
+	394, 271, 335, 331, 333, 16, 332, 451, 330, 96,
+	20, 21, 440, 188, 435, 411, 445, 27, 28, 29,
+	44, 54, 55, 45, 47, 48, 46, 49, 50, 51,
+	52, 30, 31, 328, 325, 396, 329, 326, 327, 324,
+	247, 32, 33, 34, 35, 36, 37, 38, 383, 384,
+	466, 39, 40, 41, 66, 22, 322, 198, 197, 323,
+	226, 321, 226, 300, 465, 224, 105, 56, 57, 58,
+	59, 60, 61, 62, 63, 64, 65, 24, 25, 42,
+	43, 53, 281, 104, 461, 459, 444, 443, 427, 426,
+	424, 391, 16, 382, 380, 378, 238, 20, 21, 367,
+	6, 365, 339, 267, 27, 28, 29, 44, 54, 55,
+	45, 47, 48, 46, 49, 50, 51, 52, 30, 31,
+	266, 265, 264, 235, 230, 229, 228, 449, 32, 33,
+	34, 35, 36, 37, 38, 415, 284, 245, 39, 40,
+	41, 66, 22, 241, 305, 226, 232, 98, 238, 156,
+	157, 133, 134, 236, 56, 57, 58, 59, 60, 61,
+	62, 63, 64, 65, 24, 25, 42, 43, 53, 190,
+	141, 243, 143, 239, 142, 140, 139, 222, 84, 16,
+	169, 158, 170, 146, 20, 21, 145, 6, 144, 137,
+	136, 27, 28, 29, 44, 54, 55, 45, 47, 48,
+	46, 49, 50, 51, 52, 30, 31, 135, 131, 132,
+	113, 112, 460, 14, 13, 32, 33, 34, 35, 36,
+	37, 38, 23, 12, 11, 39, 40, 41, 66, 22,
+	9, 26, 15, 18, 8, 405, 17, 7, 87, 1,
+	0, 56, 57, 58, 59, 60, 61, 62, 63, 64,
+	65, 24, 25, 42, 43, 53, 185, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 16, 0, 0, 0,
+	0, 20, 21, 0, 188, 0, 0, 0, 27, 28,
+	29, 44, 54, 55, 45, 47, 48, 46, 49, 50,
+	51, 52, 30, 31, 0, 0, 0, 0, 0, 0,
+	0, 0, 32, 33, 34, 35, 36, 37, 38, 0,
+	0, 0, 39, 40, 41, 66, 22, 0, 0, 0,
+	0, 0, 0, 168, 0, 0, 0, 0, 56, 57,
+	58, 59, 60, 61, 62, 63, 64, 65, 24, 25,
+	42, 43, 53, 159, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 20, 21,
+	0, 0, 0, 0, 148, 149, 147, 0, 160, 162,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 150, 0, 151, 0,
+	0, 0, 0, 0, 161, 163, 164, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 152, 153, 154, 165, 166, 167,
 }
 var exprPact = [...]int{
 
-	352, -1000, -56, -1000, -1000, 199, 352, -1000, -1000, -1000,
-	-1000, -1000, -1000, 396, 341, 159, -1000, 478, 449, 339,
-	337, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
+	391, -1000, -72, -1000, -1000, 149, 391, -1000, -1000, -1000,
+	-1000, -1000, -1000, -1000, -1000, -1000, 481, 390, 207, -1000,
+	566, 549, 388, 386, 385, 384, 382, -1000, -1000, -1000,
 	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
-	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, 66, 66,
-	66, 66, 66, 66, 66, 66, 66, 66, 66, 66,
-	66, 66, 66, 199, -1000, 223, 272, 5, 84, -1000,
-	-1000, -1000, -1000, 263, 262, -56, 517, -1000, -1000, 57,
-	346, 438, 332, 330, 322, -1000, -1000, 352, 436, 352,
-	28, 13, -1000, 352, 352, 352, 352, 352, 352, 352,
-	352, 352, 352, 352, 352, 352, 352, -1000, -1000, -1000,
-	-1000, -1000, -1000, 273, -1000, -1000, -1000, -1000, -1000, 534,
-	552, 548, -1000, 547, -1000, -1000, -1000, -1000, 220, 546,
-	-1000, 554, 551, 550, 65, -1000, -1000, 82, 4, 319,
-	-1000, -1000, -1000, -1000, -1000, 553, 545, 544, 543, 542,
-	248, 379, 189, 300, 247, 378, 395, 305, 298, 377,
-	238, -42, 318, 316, 301, 296, -30, -30, -15, -15,
-	-72, -72, -72, -72, -24, -24, -24, -24, -24, -24,
-	273, 220, 220, 220, 515, 354, -1000, -1000, 387, 354,
-	-1000, -1000, 250, -1000, 376, -1000, 358, 359, -1000, 57,
-	-1000, 357, -1000, 57, -1000, 351, 302, 531, 524, 520,
-	480, 437, -1000, 3, 291, 82, 541, -1000, -1000, -1000,
-	-1000, -1000, -1000, 116, 300, 201, 157, 83, 131, 169,
-	194, 116, 352, 229, 355, 265, -1000, -1000, 259, -1000,
-	540, -1000, 277, 251, 237, 227, 307, 273, 151, -1000,
-	354, 552, 526, -1000, 539, 458, 551, 550, 290, -1000,
-	-1000, -1000, 289, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
 	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
-	-1000, 82, 516, -1000, 203, -1000, 172, 111, 55, 111,
-	433, 16, 220, 16, 153, 275, 412, 147, 79, -1000,
-	-1000, 184, -1000, 352, 525, -1000, -1000, 353, 228, -1000,
-	193, -1000, -1000, 190, -1000, 112, -1000, -1000, -1000, -1000,
-	-1000, -1000, -1000, -1000, 493, 492, -1000, 178, -1000, 116,
-	55, 111, 55, -1000, -1000, 273, -1000, 16, -1000, 274,
-	-1000, -1000, -1000, 61, 410, 402, 120, 116, 162, -1000,
-	491, -1000, -1000, -1000, -1000, 137, 119, -1000, -1000, 55,
-	-1000, 481, 59, 55, 46, 16, 16, 393, -1000, -1000,
-	349, -1000, -1000, 103, 55, -1000, -1000, 16, 482, -1000,
-	-1000, 306, 459, 86, -1000,
+	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
+	-1000, -1000, -1000, -1000, -1000, -1000, -1000, 92, 92, 92,
+	92, 92, 92, 92, 92, 92, 92, 92, 92, 92,
+	92, 92, 149, -1000, 140, 808, -19, 154, -1000, -1000,
+	-1000, -1000, 361, 360, -72, 441, -1000, -1000, 80, 739,
+	652, 381, 379, 377, -1000, -1000, 391, 391, 391, 541,
+	540, 391, 12, 8, -1000, 391, 391, 391, 391, 391,
+	391, 391, 391, 391, 391, 391, 391, 391, 391, -1000,
+	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, 199, -1000,
+	-1000, -1000, -1000, -1000, -1000, -1000, -1000, 547, 630, 610,
+	-1000, 609, 608, 631, 630, -1000, -1000, -1000, -1000, 376,
+	607, -1000, 633, 628, 622, 523, 210, 632, 74, -1000,
+	-1000, 105, -20, 371, -1000, -1000, -1000, -1000, -1000, 632,
+	606, 605, 604, 587, 354, 446, 480, 278, 478, 359,
+	445, 565, 374, 357, 444, 318, 443, 442, 358, -58,
+	369, 368, 367, 366, -46, -46, -36, -36, -92, -92,
+	-92, -92, -40, -40, -40, -40, -40, -40, 199, 376,
+	376, 376, 545, 418, -1000, -1000, 474, 418, -1000, -1000,
+	631, 629, 473, 418, 197, -1000, 440, -1000, 455, 439,
+	-1000, 80, -1000, 432, -1000, 80, -1000, -1000, -1000, 365,
+	430, 259, 242, 542, 520, 519, 489, 485, -1000, -21,
+	364, 105, 586, -1000, -1000, -1000, -1000, -1000, -1000, 169,
+	478, 132, 112, 90, 177, 163, 108, 324, 169, 391,
+	352, 421, 315, -1000, -1000, 314, -1000, 585, -1000, 17,
+	583, 391, -1000, 306, 265, 245, 212, 333, 199, 94,
+	-1000, 418, 630, 579, 629, 454, 578, -1000, 581, 533,
+	628, 622, 621, 363, -1000, -1000, -1000, 260, -1000, -1000,
+	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
+	-1000, -1000, -1000, -1000, -1000, -1000, 105, 575, -1000, 323,
+	-1000, 204, 479, -1000, 322, 516, 31, 113, 117, 71,
+	117, 31, 376, 194, 81, 495, 282, -1000, -1000, 320,
+	-1000, 391, 620, -1000, -1000, 417, 319, 416, 317, 308,
+	-1000, 307, -1000, -1000, 299, -1000, 289, -1000, -1000, 574,
+	-1000, -1000, -1000, -1000, -1000, -1000, -1000, 288, 573, 572,
+	-1000, 311, -1000, 169, 60, -1000, -1000, -1000, 31, 71,
+	117, 71, -1000, 199, -1000, 211, -1000, -1000, -1000, 494,
+	270, 43, 492, 169, 286, -1000, 571, -1000, 570, -1000,
+	-1000, -1000, -1000, -1000, -1000, 497, 258, 252, -1000, -1000,
+	-1000, 226, -1000, 71, 612, 31, 487, 72, 71, 65,
+	31, -1000, -1000, 398, 243, -1000, -1000, -1000, -1000, 166,
+	-1000, 31, 71, -1000, 569, -1000, 568, -1000, -1000, 397,
+	221, -1000, 548, -1000, 534, 99, -1000, -1000,
 }
 var exprPgo = [...]int{
 
-	0, 591, 18, 590, 2, 14, 536, 3, 16, 7,
-	589, 588, 587, 586, 15, 585, 584, 583, 582, 95,
-	581, 580, 579, 455, 578, 577, 576, 575, 13, 5,
-	574, 573, 572, 6, 571, 92, 4, 570, 569, 568,
-	567, 566, 11, 565, 564, 8, 563, 12, 562, 9,
-	17, 561, 560, 1, 545, 521, 0,
+	0, 729, 26, 728, 3, 9, 18, 1, 19, 6,
+	21, 727, 726, 725, 16, 724, 723, 722, 721, 103,
+	720, 17, 714, 713, 712, 704, 703, 702, 295, 701,
+	700, 699, 698, 697, 680, 679, 678, 676, 673, 14,
+	12, 4, 672, 671, 670, 7, 668, 98, 5, 667,
+	666, 665, 664, 663, 10, 662, 661, 11, 660, 15,
+	643, 13, 20, 642, 641, 2, 640, 639, 0,
 }
 var exprR1 = [...]int{
 
 	0, 1, 2, 2, 7, 7, 7, 7, 7, 7,
-	7, 6, 6, 6, 8, 8, 8, 8, 8, 8,
+	7, 7, 7, 7, 6, 6, 6, 8, 8, 8,
 	8, 8, 8, 8, 8, 8, 8, 8, 8, 8,
 	8, 8, 8, 8, 8, 8, 8, 8, 8, 8,
-	53, 53, 53, 13, 13, 13, 11, 11, 11, 11,
-	15, 15, 15, 15, 15, 15, 22, 3, 3, 3,
-	3, 14, 14, 14, 10, 10, 9, 9, 9, 9,
-	28, 28, 29, 29, 29, 29, 29, 29, 29, 29,
-	29, 29, 29, 19, 36, 36, 36, 35, 35, 35,
-	34, 34, 34, 37, 37, 27, 27, 26, 26, 26,
-	26, 52, 51, 51, 38, 39, 47, 47, 48, 48,
-	48, 46, 33, 33, 33, 33, 33, 33, 33, 33,
-	33, 49, 49, 50, 50, 55, 55, 54, 54, 32,
-	32, 32, 32, 32, 32, 32, 30, 30, 30, 30,
-	30, 30, 30, 31, 31, 31, 31, 31, 31, 31,
-	42, 42, 41, 41, 40, 45, 45, 44, 44, 43,
-	20, 20, 20, 20, 20, 20, 20, 20, 20, 20,
-	20, 20, 20, 20, 20, 24, 24, 25, 25, 25,
-	25, 23, 23, 23, 23, 23, 23, 23, 23, 21,
-	21, 21, 17, 18, 16, 16, 16, 16, 16, 16,
-	16, 16, 16, 16, 16, 12, 12, 12, 12, 12,
+	8, 8, 8, 65, 65, 65, 13, 13, 13, 11,
+	11, 11, 11, 11, 11, 11, 11, 15, 15, 15,
+	15, 15, 15, 22, 23, 23, 25, 25, 26, 27,
+	27, 3, 3, 3, 3, 14, 14, 14, 10, 10,
+	9, 9, 9, 9, 40, 40, 41, 41, 41, 41,
+	41, 41, 41, 41, 41, 41, 41, 41, 41, 41,
+	41, 41, 41, 19, 48, 48, 48, 47, 47, 47,
+	46, 46, 46, 49, 49, 32, 32, 31, 31, 31,
+	31, 64, 63, 63, 39, 39, 33, 33, 34, 34,
+	35, 35, 50, 51, 59, 59, 60, 60, 60, 58,
+	45, 45, 45, 45, 45, 45, 45, 45, 45, 61,
+	61, 62, 62, 67, 67, 66, 66, 44, 44, 44,
+	44, 44, 44, 44, 42, 42, 42, 42, 42, 42,
+	42, 43, 43, 43, 43, 43, 43, 43, 54, 54,
+	53, 53, 52, 57, 57, 56, 56, 55, 36, 37,
+	37, 37, 37, 38, 20, 20, 20, 20, 20, 20,
+	20, 20, 20, 20, 20, 20, 20, 20, 20, 29,
+	29, 30, 30, 30, 30, 28, 28, 28, 28, 28,
+	28, 28, 28, 21, 21, 21, 17, 18, 16, 16,
+	16, 16, 16, 16, 16, 16, 16, 16, 16, 16,
 	12, 12, 12, 12, 12, 12, 12, 12, 12, 12,
-	56, 5, 5, 4, 4, 4, 4,
+	12, 12, 12, 12, 12, 12, 12, 24, 24, 24,
+	24, 24, 24, 24, 24, 24, 24, 68, 5, 5,
+	4, 4, 4, 4,
 }
 var exprR2 = [...]int{
 
 	0, 1, 1, 1, 1, 1, 1, 1, 1, 1,
-	3, 1, 2, 3, 2, 3, 4, 5, 3, 4,
-	5, 6, 3, 4, 5, 6, 3, 4, 5, 6,
-	4, 5, 6, 7, 3, 4, 4, 5, 3, 2,
-	3, 6, 3, 1, 1, 1, 4, 6, 5, 7,
-	4, 5, 5, 6, 7, 7, 12, 1, 1, 1,
-	1, 3, 3, 2, 1, 3, 3, 3, 3, 3,
-	1, 2, 1, 2, 2, 2, 2, 2, 2, 2,
+	1, 1, 1, 3, 1, 2, 3, 2, 3, 4,
+	5, 3, 4, 5, 6, 3, 4, 5, 6, 3,
+	4, 5, 6, 4, 5, 6, 7, 3, 4, 4,
+	5, 3, 2, 3, 6, 3, 1, 1, 1, 4,
+	6, 5, 7, 5, 6, 7, 8, 4, 5, 5,
+	6, 7, 7, 12, 4, 6, 8, 10, 6, 1,
+	3, 1, 1, 1, 1, 3, 3, 2, 1, 3,
+	3, 3, 3, 3, 1, 2, 1, 2, 2, 2,
+	2, 2, 2, 2, 2, 2, 2, 2, 2, 2,
 	2, 2, 2, 1, 1, 4, 3, 2, 5, 4,
 	1, 3, 2, 1, 2, 1, 2, 1, 2, 1,
-	2, 2, 3, 2, 2, 1, 3, 3, 1, 3,
-	3, 2, 1, 1, 1, 1, 3, 2, 3, 3,
-	3, 3, 1, 1, 3, 6, 6, 1, 1, 3,
+	2, 2, 3, 2, 3, 4, 2, 3, 1, 2,
+	1, 2, 2, 1, 3, 3, 1, 3, 3, 2,
+	1, 1, 1, 1, 3, 2, 3, 3, 3, 3,
+	1, 1, 3, 6, 6, 1, 1, 3, 3, 3,
 	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
-	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
-	1, 1, 1, 3, 2, 1, 1, 1, 3, 2,
-	4, 4, 4, 4, 4, 4, 4, 4, 4, 4,
-	4, 4, 4, 4, 4, 0, 1, 5, 4, 5,
-	4, 1, 1, 2, 4, 5, 2, 4, 5, 1,
-	2, 2, 4, 1, 1, 1, 1, 1, 1, 1,
+	3, 3, 3, 3, 3, 3, 3, 3, 1, 1,
+	1, 3, 2, 1, 1, 1, 3, 2, 2, 1,
+	2, 5, 6, 2, 4, 4, 4, 4, 4, 4,
+	4, 4, 4, 4, 4, 4, 4, 4, 4, 0,
+	1, 5, 4, 5, 4, 1, 1, 2, 4, 5,
+	2, 4, 5, 1, 2, 2, 4, 1, 1, 1,
+	1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
 	1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
 	1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
-	2, 1, 3, 4, 4, 3, 3,
+	1, 1, 1, 1, 1, 1, 1, 2, 1, 3,
+	4, 4, 3, 3,
 }
 var exprChk = [...]int{
 
-	-1000, -1, -2, -6, -7, -14, 24, -11, -15, -20,
-	-21, -22, -17, 16, -12, -16, 7, 87, 88, 66,
-	-18, 28, 29, 30, 42, 43, 52, 53, 54, 55,
-	56, 57, 58, 62, 63, 64, 31, 34, 37, 35,
-	36, 38, 39, 40, 41, 32, 33, 65, 78, 79,
-	80, 87, 88, 89, 90, 91, 92, 81, 82, 85,
-	86, 83, 84, -28, -29, -34, 48, -35, -3, 22,
-	23, 15, 82, -7, -6, -2, -10, 17, -9, 5,
-	24, 24, -4, 26, 27, 7, 7, 24, 24, -23,
-	-24, -25, 44, -23, -23, -23, -23, -23, -23, -23,
-	-23, -23, -23, -23, -23, -23, -23, -29, -35, -27,
-	-26, -52, -51, -33, -38, -39, -46, -40, -43, 47,
-	45, 46, 67, 69, -9, -55, -54, -31, 24, 49,
-	75, 50, 76, 77, 5, -32, -30, 78, 6, -19,
-	70, 25, 25, 17, 2, 20, 13, 82, 14, 15,
-	-8, 7, -14, 24, -7, 7, 24, 24, 24, -7,
-	7, -2, 71, 72, 73, 74, -2, -2, -2, -2,
-	-2, -2, -2, -2, -2, -2, -2, -2, -2, -2,
-	-33, 79, 20, 78, -37, -50, 8, -49, 5, -50,
-	6, 6, -33, 6, -48, -47, 5, -41, -42, 5,
-	-9, -44, -45, 5, -9, 13, 82, 85, 86, 83,
-	84, 81, -36, 6, -19, 78, 24, -9, 6, 6,
-	6, 6, 2, 25, 20, 10, -53, -28, 48, -14,
-	-8, 25, 20, -7, 7, -5, 25, 5, -5, 25,
-	20, 25, 24, 24, 24, 24, -33, -33, -33, 8,
-	-50, 20, 13, 25, 20, 13, 20, 20, 70, 9,
-	4, 7, 70, 9, 4, 7, 9, 4, 7, 9,
-	4, 7, 9, 4, 7, 9, 4, 7, 9, 4,
-	7, 78, 24, -36, 6, -4, -8, -56, -53, -28,
-	68, 10, 48, 10, -53, 51, 25, -53, -28, 25,
-	-4, -7, 25, 20, 20, 25, 25, 6, -5, 25,
-	-5, 25, 25, -5, 25, -5, -49, 6, -47, 2,
-	5, 6, -42, -45, 24, 24, -36, 6, 25, 25,
-	-53, -28, -53, 9, -56, -33, -56, 10, 5, -13,
-	59, 60, 61, 10, 25, 25, -53, 25, -7, 5,
-	20, 25, 25, 25, 25, 6, 6, 25, -4, -53,
-	-56, 24, -56, -53, 48, 10, 10, 25, -4, 25,
-	6, 25, 25, 5, -53, -56, -56, 10, 20, 25,
-	-56, 6, 20, 6, 25,
+	-1000, -1, -2, -6, -7, -14, 25, -11, -15, -20,
+	-21, -22, -23, -25, -26, -17, 17, -12, -16, 7,
+	109, 110, 67, -24, 89, 90, -18, 29, 30, 31,
+	43, 44, 53, 54, 55, 56, 57, 58, 59, 63,
+	64, 65, 91, 92, 32, 35, 38, 36, 37, 39,
+	40, 41, 42, 93, 33, 34, 79, 80, 81, 82,
+	83, 84, 85, 86, 87, 88, 66, 100, 101, 102,
+	109, 110, 111, 112, 113, 114, 103, 104, 107, 108,
+	105, 106, -40, -41, -46, 49, -47, -3, 23, 24,
+	16, 104, -7, -6, -2, -10, 18, -9, 5, 25,
+	25, -4, 27, 28, 7, 7, 25, 25, 25, 25,
+	25, -28, -29, -30, 45, -28, -28, -28, -28, -28,
+	-28, -28, -28, -28, -28, -28, -28, -28, -28, -41,
+	-47, -32, -31, -64, -63, -33, -34, -35, -45, -50,
+	-51, -58, -52, -55, -36, -37, -38, 48, 46, 47,
+	68, 70, 94, 95, 96, -9, -67, -66, -43, 25,
+	50, 76, 51, 77, 78, 97, 98, 99, 5, -44,
+	-42, 100, 6, -19, 71, 26, 26, 18, 2, 21,
+	14, 104, 15, 16, -8, 7, -7, -14, 25, -7,
+	7, 25, 25, 25, -7, -7, -7, 7, 7, -2,
+	72, 73, 74, 75, -2, -2, -2, -2, -2, -2,
+	-2, -2, -2, -2, -2, -2, -2, -2, -45, 101,
+	21, 100, -49, -62, 8, -61, 5, -62, 6, 6,
+	6, -39, 5, -62, -45, 6, -60, -59, 5, -53,
+	-54, 5, -9, -56, -57, 5, -9, 7, 9, 27,
+	-10, 14, 104, 107, 108, 105, 106, 103, -48, 6,
+	-19, 100, 25, -9, 6, 6, 6, 6, 2, 26,
+	21, 11, -40, 10, -65, 49, -14, -8, 26, 21,
+	-7, 7, -5, 26, 5, -5, 26, 21, 26, 21,
+	21, 21, 26, 25, 25, 25, 25, -45, -45, -45,
+	8, -62, 21, 14, -39, 5, 14, 26, 21, 14,
+	21, 21, 25, 71, 9, 4, 7, 71, 9, 4,
+	7, 9, 4, 7, 9, 4, 7, 9, 4, 7,
+	9, 4, 7, 9, 4, 7, 100, 25, -48, 6,
+	-4, -8, -7, 26, -68, 69, 10, -65, -68, -65,
+	-40, 10, 49, 52, -40, 26, -65, 26, -4, -7,
+	26, 21, 21, 26, 26, 6, -21, 6, -7, -5,
+	26, -5, 26, 26, -5, 26, -5, -61, 6, 14,
+	6, -59, 2, 5, 6, -54, -57, -5, 25, 25,
+	-48, 6, 26, 26, 11, 26, 9, -68, 10, -65,
+	-40, -65, -68, -45, 5, -13, 60, 61, 62, 26,
+	-65, 10, 26, 26, -7, 5, 21, 26, 21, 26,
+	26, 26, 26, 26, 6, 26, 6, 6, 26, -4,
+	26, -68, -68, -65, 25, 10, 26, -68, -65, 49,
+	10, -4, 26, 6, 6, 9, 26, 26, 26, 5,
+	-68, 10, -65, -68, 21, 26, 21, 26, -68, 6,
+	-27, 6, 21, 26, 21, 6, 6, 26,
 }
 var exprDef = [...]int{
 
-	0, -2, 1, 2, 3, 11, 0, 4, 5, 6,
-	7, 8, 9, 0, 0, 0, 189, 0, 0, 0,
-	0, 205, 206, 207, 208, 209, 210, 211, 212, 213,
-	214, 215, 216, 217, 218, 219, 194, 195, 196, 197,
-	198, 199, 200, 201, 202, 203, 204, 193, 175, 175,
-	175, 175, 175, 175, 175, 175, 175, 175, 175, 175,
-	175, 175, 175, 12, 70, 72, 0, 90, 0, 57,
-	58, 59, 60, 3, 2, 0, 0, 63, 64, 0,
-	0, 0, 0, 0, 0, 190, 191, 0, 0, 0,
-	181, 182, 176, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 0, 0, 71, 92, 73,
-	74, 75, 76, 77, 78, 79, 80, 81, 82, 95,
-	97, 0, 99, 0, 112, 113, 114, 115, 0, 0,
-	105, 0, 0, 0, 0, 127, 128, 0, 87, 0,
-	83, 10, 13, 61, 62, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 3, 189, 0, 0, 0, 3,
-	0, 160, 0, 0, 183, 186, 161, 162, 163, 164,
-	165, 166, 167, 168, 169, 170, 171, 172, 173, 174,
-	117, 0, 0, 0, 96, 103, 93, 123, 122, 101,
-	98, 100, 0, 104, 111, 108, 0, 154, 152, 150,
-	151, 159, 157, 155, 156, 0, 0, 0, 0, 0,
-	0, 0, 91, 84, 0, 0, 0, 65, 66, 67,
-	68, 69, 39, 46, 0, 14, 0, 0, 0, 0,
-	0, 50, 0, 3, 189, 0, 225, 221, 0, 226,
-	0, 192, 0, 0, 0, 0, 118, 119, 120, 94,
-	102, 0, 0, 116, 0, 0, 0, 0, 0, 134,
-	141, 148, 0, 133, 140, 147, 129, 136, 143, 130,
-	137, 144, 131, 138, 145, 132, 139, 146, 135, 142,
-	149, 0, 0, 89, 0, 48, 0, 15, 18, 34,
-	0, 22, 0, 26, 0, 0, 0, 0, 0, 38,
-	52, 3, 51, 0, 0, 223, 224, 0, 0, 178,
-	0, 180, 184, 0, 187, 0, 124, 121, 109, 110,
-	106, 107, 153, 158, 0, 0, 86, 0, 88, 47,
-	19, 35, 36, 220, 23, 42, 27, 30, 40, 0,
-	43, 44, 45, 16, 0, 0, 0, 53, 3, 222,
-	0, 177, 179, 185, 188, 0, 0, 85, 49, 37,
-	31, 0, 17, 20, 0, 24, 28, 0, 54, 55,
-	0, 125, 126, 0, 21, 25, 29, 32, 0, 41,
-	33, 0, 0, 0, 56,
+	0, -2, 1, 2, 3, 14, 0, 4, 5, 6,
+	7, 8, 9, 10, 11, 12, 0, 0, 0, 223,
+	0, 0, 0, 0, 0, 0, 0, 240, 241, 242,
+	243, 244, 245, 246, 247, 248, 249, 250, 251, 252,
+	253, 254, 255, 256, 228, 229, 230, 231, 232, 233,
+	234, 235, 236, 237, 238, 239, 257, 258, 259, 260,
+	261, 262, 263, 264, 265, 266, 227, 209, 209, 209,
+	209, 209, 209, 209, 209, 209, 209, 209, 209, 209,
+	209, 209, 15, 84, 86, 0, 110, 0, 71, 72,
+	73, 74, 3, 2, 0, 0, 77, 78, 0, 0,
+	0, 0, 0, 0, 224, 225, 0, 0, 0, 0,
+	0, 0, 215, 216, 210, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 85,
+	112, 87, 88, 89, 90, 91, 92, 93, 94, 95,
+	96, 97, 98, 99, 100, 101, 102, 115, 117, 0,
+	119, 0, 0, 128, 130, 140, 141, 142, 143, 0,
+	0, 133, 0, 0, 0, 0, 189, 0, 0, 155,
+	156, 0, 107, 0, 103, 13, 16, 75, 76, 0,
+	0, 0, 0, 0, 0, 223, 3, 14, 0, 3,
+	223, 0, 0, 0, 3, 3, 3, 0, 0, 194,
+	0, 0, 217, 220, 195, 196, 197, 198, 199, 200,
+	201, 202, 203, 204, 205, 206, 207, 208, 145, 0,
+	0, 0, 116, 123, 113, 151, 150, 121, 118, 120,
+	126, 129, 0, 131, 0, 132, 139, 136, 0, 182,
+	180, 178, 179, 187, 185, 183, 184, 188, 190, 0,
+	193, 0, 0, 0, 0, 0, 0, 0, 111, 104,
+	0, 0, 0, 79, 80, 81, 82, 83, 42, 49,
+	0, 0, 15, 17, 0, 0, 14, 0, 57, 0,
+	3, 223, 0, 272, 268, 0, 273, 0, 64, 0,
+	0, 0, 226, 0, 0, 0, 0, 146, 147, 148,
+	114, 122, 0, 0, 127, 0, 0, 144, 0, 0,
+	0, 0, 0, 0, 162, 169, 176, 0, 161, 168,
+	175, 157, 164, 171, 158, 165, 172, 159, 166, 173,
+	160, 167, 174, 163, 170, 177, 0, 0, 109, 0,
+	51, 0, 3, 53, 0, 0, 29, 0, 18, 21,
+	37, 25, 0, 0, 15, 0, 0, 41, 59, 3,
+	58, 0, 0, 270, 271, 0, 0, 0, 3, 0,
+	212, 0, 214, 218, 0, 221, 0, 152, 149, 0,
+	124, 137, 138, 134, 135, 181, 186, 0, 0, 0,
+	106, 0, 108, 50, 0, 54, 267, 30, 33, 22,
+	38, 39, 26, 45, 43, 0, 46, 47, 48, 0,
+	0, 19, 0, 60, 3, 269, 0, 65, 0, 68,
+	211, 213, 219, 222, 125, 191, 0, 0, 105, 52,
+	55, 0, 34, 40, 0, 31, 0, 20, 23, 0,
+	27, 61, 62, 0, 0, 192, 153, 154, 56, 0,
+	32, 35, 24, 28, 0, 66, 0, 44, 36, 0,
+	0, 69, 0, 67, 0, 0, 70, 63,
 }
 var exprTok1 = [...]int{
 
@@ -542,7 +654,9 @@ var exprTok2 = [...]int{
 	62, 63, 64, 65, 66, 67, 68, 69, 70, 71,
 	72, 73, 74, 75, 76, 77, 78, 79, 80, 81,
 	82, 83, 84, 85, 86, 87, 88, 89, 90, 91,
-	92,
+	92, 93, 94, 95, 96, 97, 98, 99, 100, 101,
+	102, 103, 104, 105, 106, 107, 108, 109, 110, 111,
+	112, 113, 114,
 }
 var exprTok3 = [...]int{
 	0,
@@ -927,1090 +1041,1325 @@ exprdefault:
 	case 9:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
-			exprVAL.MetricExpr = exprDollar[1].VectorExpr
+			exprVAL.MetricExpr = exprDollar[1].MetricExpr
 		}
 	case 10:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+		{
+			exprVAL.MetricExpr = exprDollar[1].MetricExpr
+		}
+	case 11:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+		{
+			exprVAL.MetricExpr = exprDollar[1].MetricExpr
+		}
+	case 12:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+		{
+			exprVAL.MetricExpr = exprDollar[1].VectorExpr
+		}
+	case 13:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.MetricExpr = exprDollar[2].MetricExpr
 		}
-	case 11:
+	case 14:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.LogExpr = newMatcherExpr(exprDollar[1].Selector)
 		}
-	case 12:
+	case 15:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
 		{
 			exprVAL.LogExpr = newPipelineExpr(newMatcherExpr(exprDollar[1].Selector), exprDollar[2].PipelineExpr)
 		}
-	case 13:
+	case 16:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.LogExpr = exprDollar[2].LogExpr
 		}
-	case 14:
+	case 17:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
 		{
 			exprVAL.LogRangeExpr = newLogRange(newMatcherExpr(exprDollar[1].Selector), exprDollar[2].duration, nil, nil)
 		}
-	case 15:
+	case 18:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.LogRangeExpr = newLogRange(newMatcherExpr(exprDollar[1].Selector), exprDollar[2].duration, nil, exprDollar[3].OffsetExpr)
 		}
-	case 16:
+	case 19:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
 		{
 			exprVAL.LogRangeExpr = newLogRange(newMatcherExpr(exprDollar[2].Selector), exprDollar[4].duration, nil, nil)
 		}
-	case 17:
+	case 20:
 		exprDollar = exprS[exprpt-5 : exprpt+1]
 		{
 			exprVAL.LogRangeExpr = newLogRange(newMatcherExpr(exprDollar[2].Selector), exprDollar[4].duration, nil, exprDollar[5].OffsetExpr)
 		}
-	case 18:
+	case 21:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.LogRangeExpr = newLogRange(newMatcherExpr(exprDollar[1].Selector), exprDollar[2].duration, exprDollar[3].UnwrapExpr, nil)
 		}
-	case 19:
+	case 22:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
 		{
 			exprVAL.LogRangeExpr = newLogRange(newMatcherExpr(exprDollar[1].Selector), exprDollar[2].duration, exprDollar[4].UnwrapExpr, exprDollar[3].OffsetExpr)
 		}
-	case 20:
+	case 23:
 		exprDollar = exprS[exprpt-5 : exprpt+1]
 		{
 			exprVAL.LogRangeExpr = newLogRange(newMatcherExpr(exprDollar[2].Selector), exprDollar[4].duration, exprDollar[5].UnwrapExpr, nil)
 		}
-	case 21:
+	case 24:
 		exprDollar = exprS[exprpt-6 : exprpt+1]
 		{
 			exprVAL.LogRangeExpr = newLogRange(newMatcherExpr(exprDollar[2].Selector), exprDollar[4].duration, exprDollar[6].UnwrapExpr, exprDollar[5].OffsetExpr)
 		}
-	case 22:
+	case 25:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.LogRangeExpr = newLogRange(newMatcherExpr(exprDollar[1].Selector), exprDollar[3].duration, exprDollar[2].UnwrapExpr, nil)
 		}
-	case 23:
+	case 26:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
 		{
 			exprVAL.LogRangeExpr = newLogRange(newMatcherExpr(exprDollar[1].Selector), exprDollar[3].duration, exprDollar[2].UnwrapExpr, exprDollar[4].OffsetExpr)
 		}
-	case 24:
+	case 27:
 		exprDollar = exprS[exprpt-5 : exprpt+1]
 		{
 			exprVAL.LogRangeExpr = newLogRange(newMatcherExpr(exprDollar[2].Selector), exprDollar[5].duration, exprDollar[3].UnwrapExpr, nil)
 		}
-	case 25:
+	case 28:
 		exprDollar = exprS[exprpt-6 : exprpt+1]
 		{
 			exprVAL.LogRangeExpr = newLogRange(newMatcherExpr(exprDollar[2].Selector), exprDollar[5].duration, exprDollar[3].UnwrapExpr, exprDollar[6].OffsetExpr)
 		}
-	case 26:
+	case 29:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.LogRangeExpr = newLogRange(newPipelineExpr(newMatcherExpr(exprDollar[1].Selector), exprDollar[2].PipelineExpr), exprDollar[3].duration, nil, nil)
 		}
-	case 27:
+	case 30:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
 		{
 			exprVAL.LogRangeExpr = newLogRange(newPipelineExpr(newMatcherExpr(exprDollar[1].Selector), exprDollar[2].PipelineExpr), exprDollar[3].duration, nil, exprDollar[4].OffsetExpr)
 		}
-	case 28:
+	case 31:
 		exprDollar = exprS[exprpt-5 : exprpt+1]
 		{
 			exprVAL.LogRangeExpr = newLogRange(newPipelineExpr(newMatcherExpr(exprDollar[2].Selector), exprDollar[3].PipelineExpr), exprDollar[5].duration, nil, nil)
 		}
-	case 29:
+	case 32:
 		exprDollar = exprS[exprpt-6 : exprpt+1]
 		{
 			exprVAL.LogRangeExpr = newLogRange(newPipelineExpr(newMatcherExpr(exprDollar[2].Selector), exprDollar[3].PipelineExpr), exprDollar[5].duration, nil, exprDollar[6].OffsetExpr)
 		}
-	case 30:
+	case 33:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
 		{
 			exprVAL.LogRangeExpr = newLogRange(newPipelineExpr(newMatcherExpr(exprDollar[1].Selector), exprDollar[2].PipelineExpr), exprDollar[4].duration, exprDollar[3].UnwrapExpr, nil)
 		}
-	case 31:
+	case 34:
 		exprDollar = exprS[exprpt-5 : exprpt+1]
 		{
 			exprVAL.LogRangeExpr = newLogRange(newPipelineExpr(newMatcherExpr(exprDollar[1].Selector), exprDollar[2].PipelineExpr), exprDollar[4].duration, exprDollar[3].UnwrapExpr, exprDollar[5].OffsetExpr)
 		}
-	case 32:
+	case 35:
 		exprDollar = exprS[exprpt-6 : exprpt+1]
 		{
 			exprVAL.LogRangeExpr = newLogRange(newPipelineExpr(newMatcherExpr(exprDollar[2].Selector), exprDollar[3].PipelineExpr), exprDollar[6].duration, exprDollar[4].UnwrapExpr, nil)
 		}
-	case 33:
+	case 36:
 		exprDollar = exprS[exprpt-7 : exprpt+1]
 		{
 			exprVAL.LogRangeExpr = newLogRange(newPipelineExpr(newMatcherExpr(exprDollar[2].Selector), exprDollar[3].PipelineExpr), exprDollar[6].duration, exprDollar[4].UnwrapExpr, exprDollar[7].OffsetExpr)
 		}
-	case 34:
+	case 37:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.LogRangeExpr = newLogRange(newPipelineExpr(newMatcherExpr(exprDollar[1].Selector), exprDollar[3].PipelineExpr), exprDollar[2].duration, nil, nil)
 		}
-	case 35:
+	case 38:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
 		{
 			exprVAL.LogRangeExpr = newLogRange(newPipelineExpr(newMatcherExpr(exprDollar[1].Selector), exprDollar[4].PipelineExpr), exprDollar[2].duration, nil, exprDollar[3].OffsetExpr)
 		}
-	case 36:
+	case 39:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
 		{
 			exprVAL.LogRangeExpr = newLogRange(newPipelineExpr(newMatcherExpr(exprDollar[1].Selector), exprDollar[3].PipelineExpr), exprDollar[2].duration, exprDollar[4].UnwrapExpr, nil)
 		}
-	case 37:
+	case 40:
 		exprDollar = exprS[exprpt-5 : exprpt+1]
 		{
 			exprVAL.LogRangeExpr = newLogRange(newPipelineExpr(newMatcherExpr(exprDollar[1].Selector), exprDollar[4].PipelineExpr), exprDollar[2].duration, exprDollar[5].UnwrapExpr, exprDollar[3].OffsetExpr)
 		}
-	case 38:
+	case 41:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.LogRangeExpr = exprDollar[2].LogRangeExpr
 		}
-	case 40:
+	case 43:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.UnwrapExpr = newUnwrapExpr(exprDollar[3].str, "")
 		}
-	case 41:
+	case 44:
 		exprDollar = exprS[exprpt-6 : exprpt+1]
 		{
 			exprVAL.UnwrapExpr = newUnwrapExpr(exprDollar[5].str, exprDollar[3].ConvOp)
 		}
-	case 42:
+	case 45:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.UnwrapExpr = exprDollar[1].UnwrapExpr.addPostFilter(exprDollar[3].LabelFilter)
 		}
-	case 43:
+	case 46:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.ConvOp = OpConvBytes
 		}
-	case 44:
+	case 47:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.ConvOp = OpConvDuration
 		}
-	case 45:
+	case 48:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.ConvOp = OpConvDurationSeconds
 		}
-	case 46:
+	case 49:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
 		{
 			exprVAL.RangeAggregationExpr = newRangeAggregationExpr(exprDollar[3].LogRangeExpr, exprDollar[1].RangeOp, nil, nil)
 		}
-	case 47:
+	case 50:
 		exprDollar = exprS[exprpt-6 : exprpt+1]
 		{
 			exprVAL.RangeAggregationExpr = newRangeAggregationExpr(exprDollar[5].LogRangeExpr, exprDollar[1].RangeOp, nil, &exprDollar[3].str)
 		}
-	case 48:
+	case 51:
 		exprDollar = exprS[exprpt-5 : exprpt+1]
 		{
 			exprVAL.RangeAggregationExpr = newRangeAggregationExpr(exprDollar[3].LogRangeExpr, exprDollar[1].RangeOp, exprDollar[5].Grouping, nil)
 		}
-	case 49:
+	case 52:
 		exprDollar = exprS[exprpt-7 : exprpt+1]
 		{
 			exprVAL.RangeAggregationExpr = newRangeAggregationExpr(exprDollar[5].LogRangeExpr, exprDollar[1].RangeOp, exprDollar[7].Grouping, &exprDollar[3].str)
 		}
-	case 50:
+	case 53:
+		exprDollar = exprS[exprpt-5 : exprpt+1]
+		{
+			exprVAL.RangeAggregationExpr = newSubqueryExpr(exprDollar[3].MetricExpr, exprDollar[1].RangeOp, exprDollar[4].subqueryRange, nil, nil)
+		}
+	case 54:
+		exprDollar = exprS[exprpt-6 : exprpt+1]
+		{
+			exprVAL.RangeAggregationExpr = newSubqueryExpr(exprDollar[3].MetricExpr, exprDollar[1].RangeOp, exprDollar[4].subqueryRange, exprDollar[5].OffsetExpr, nil)
+		}
+	case 55:
+		exprDollar = exprS[exprpt-7 : exprpt+1]
+		{
+			exprVAL.RangeAggregationExpr = newSubqueryExpr(exprDollar[5].MetricExpr, exprDollar[1].RangeOp, exprDollar[6].subqueryRange, nil, &exprDollar[3].str)
+		}
+	case 56:
+		exprDollar = exprS[exprpt-8 : exprpt+1]
+		{
+			exprVAL.RangeAggregationExpr = newSubqueryExpr(exprDollar[5].MetricExpr, exprDollar[1].RangeOp, exprDollar[6].subqueryRange, exprDollar[7].OffsetExpr, &exprDollar[3].str)
+		}
+	case 57:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
 		{
 			exprVAL.VectorAggregationExpr = mustNewVectorAggregationExpr(exprDollar[3].MetricExpr, exprDollar[1].VectorOp, nil, nil)
 		}
-	case 51:
+	case 58:
 		exprDollar = exprS[exprpt-5 : exprpt+1]
 		{
 			exprVAL.VectorAggregationExpr = mustNewVectorAggregationExpr(exprDollar[4].MetricExpr, exprDollar[1].VectorOp, exprDollar[2].Grouping, nil)
 		}
-	case 52:
+	case 59:
 		exprDollar = exprS[exprpt-5 : exprpt+1]
 		{
 			exprVAL.VectorAggregationExpr = mustNewVectorAggregationExpr(exprDollar[3].MetricExpr, exprDollar[1].VectorOp, exprDollar[5].Grouping, nil)
 		}
-	case 53:
+	case 60:
 		exprDollar = exprS[exprpt-6 : exprpt+1]
 		{
 			exprVAL.VectorAggregationExpr = mustNewVectorAggregationExpr(exprDollar[5].MetricExpr, exprDollar[1].VectorOp, nil, &exprDollar[3].str)
 		}
-	case 54:
+	case 61:
 		exprDollar = exprS[exprpt-7 : exprpt+1]
 		{
 			exprVAL.VectorAggregationExpr = mustNewVectorAggregationExpr(exprDollar[5].MetricExpr, exprDollar[1].VectorOp, exprDollar[7].Grouping, &exprDollar[3].str)
 		}
-	case 55:
+	case 62:
 		exprDollar = exprS[exprpt-7 : exprpt+1]
 		{
 			exprVAL.VectorAggregationExpr = mustNewVectorAggregationExpr(exprDollar[6].MetricExpr, exprDollar[1].VectorOp, exprDollar[2].Grouping, &exprDollar[4].str)
 		}
-	case 56:
+	case 63:
 		exprDollar = exprS[exprpt-12 : exprpt+1]
 		{
 			exprVAL.LabelReplaceExpr = mustNewLabelReplaceExpr(exprDollar[3].MetricExpr, exprDollar[5].str, exprDollar[7].str, exprDollar[9].str, exprDollar[11].str)
 		}
-	case 57:
+	case 64:
+		exprDollar = exprS[exprpt-4 : exprpt+1]
+		{
+			exprVAL.MetricExpr = newFunctionExpr(exprDollar[3].MetricExpr, exprDollar[1].str, nil)
+		}
+	case 65:
+		exprDollar = exprS[exprpt-6 : exprpt+1]
+		{
+			exprVAL.MetricExpr = newFunctionExpr(exprDollar[3].MetricExpr, exprDollar[1].str, exprDollar[5].LiteralExpr)
+		}
+	case 66:
+		exprDollar = exprS[exprpt-8 : exprpt+1]
+		{
+			exprVAL.MetricExpr = newLabelJoinExpr(exprDollar[3].MetricExpr, exprDollar[5].str, exprDollar[7].str, nil)
+		}
+	case 67:
+		exprDollar = exprS[exprpt-10 : exprpt+1]
+		{
+			exprVAL.MetricExpr = newLabelJoinExpr(exprDollar[3].MetricExpr, exprDollar[5].str, exprDollar[7].str, exprDollar[9].Labels)
+		}
+	case 68:
+		exprDollar = exprS[exprpt-6 : exprpt+1]
+		{
+			exprVAL.MetricExpr = newHistogramQuantileExpr(exprDollar[5].MetricExpr, exprDollar[3].str)
+		}
+	case 69:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+		{
+			exprVAL.Labels = []string{exprDollar[1].str}
+		}
+	case 70:
+		exprDollar = exprS[exprpt-3 : exprpt+1]
+		{
+			exprVAL.Labels = append(exprDollar[1].Labels, exprDollar[3].str)
+		}
+	case 71:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.Filter = labels.MatchRegexp
 		}
-	case 58:
+	case 72:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.Filter = labels.MatchEqual
 		}
-	case 59:
+	case 73:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.Filter = labels.MatchNotRegexp
 		}
-	case 60:
+	case 74:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.Filter = labels.MatchNotEqual
 		}
-	case 61:
+	case 75:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.Selector = exprDollar[2].Matchers
 		}
-	case 62:
+	case 76:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.Selector = exprDollar[2].Matchers
 		}
-	case 63:
+	case 77:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
 		{
 		}
-	case 64:
+	case 78:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.Matchers = []*labels.Matcher{exprDollar[1].Matcher}
 		}
-	case 65:
+	case 79:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.Matchers = append(exprDollar[1].Matchers, exprDollar[3].Matcher)
 		}
-	case 66:
+	case 80:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.Matcher = mustNewMatcher(labels.MatchEqual, exprDollar[1].str, exprDollar[3].str)
 		}
-	case 67:
+	case 81:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.Matcher = mustNewMatcher(labels.MatchNotEqual, exprDollar[1].str, exprDollar[3].str)
 		}
-	case 68:
+	case 82:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.Matcher = mustNewMatcher(labels.MatchRegexp, exprDollar[1].str, exprDollar[3].str)
 		}
-	case 69:
+	case 83:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.Matcher = mustNewMatcher(labels.MatchNotRegexp, exprDollar[1].str, exprDollar[3].str)
 		}
-	case 70:
+	case 84:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.PipelineExpr = MultiStageExpr{exprDollar[1].PipelineStage}
 		}
-	case 71:
+	case 85:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
 		{
 			exprVAL.PipelineExpr = append(exprDollar[1].PipelineExpr, exprDollar[2].PipelineStage)
 		}
-	case 72:
+	case 86:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.PipelineStage = exprDollar[1].LineFilters
 		}
-	case 73:
+	case 87:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
 		{
 			exprVAL.PipelineStage = exprDollar[2].LogfmtParser
 		}
-	case 74:
+	case 88:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
 		{
 			exprVAL.PipelineStage = exprDollar[2].LabelParser
 		}
-	case 75:
+	case 89:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
 		{
 			exprVAL.PipelineStage = exprDollar[2].JSONExpressionParser
 		}
-	case 76:
+	case 90:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
 		{
 			exprVAL.PipelineStage = exprDollar[2].LogfmtExpressionParser
 		}
-	case 77:
+	case 91:
+		exprDollar = exprS[exprpt-2 : exprpt+1]
+		{
+			exprVAL.PipelineStage = exprDollar[2].PipelineStage
+		}
+	case 92:
+		exprDollar = exprS[exprpt-2 : exprpt+1]
+		{
+			exprVAL.PipelineStage = exprDollar[2].PipelineStage
+		}
+	case 93:
+		exprDollar = exprS[exprpt-2 : exprpt+1]
+		{
+			exprVAL.PipelineStage = exprDollar[2].PipelineStage
+		}
+	case 94:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
 		{
 			exprVAL.PipelineStage = &LabelFilterExpr{LabelFilterer: exprDollar[2].LabelFilter}
 		}
-	case 78:
+	case 95:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
 		{
 			exprVAL.PipelineStage = exprDollar[2].LineFormatExpr
 		}
-	case 79:
+	case 96:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
 		{
 			exprVAL.PipelineStage = exprDollar[2].DecolorizeExpr
 		}
-	case 80:
+	case 97:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
 		{
 			exprVAL.PipelineStage = exprDollar[2].LabelFormatExpr
 		}
-	case 81:
+	case 98:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
 		{
 			exprVAL.PipelineStage = exprDollar[2].DropLabelsExpr
 		}
-	case 82:
+	case 99:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
 		{
 			exprVAL.PipelineStage = exprDollar[2].KeepLabelsExpr
 		}
-	case 83:
+	case 100:
+		exprDollar = exprS[exprpt-2 : exprpt+1]
+		{
+			exprVAL.PipelineStage = exprDollar[2].PipelineStage
+		}
+	case 101:
+		exprDollar = exprS[exprpt-2 : exprpt+1]
+		{
+			exprVAL.PipelineStage = exprDollar[2].PipelineStage
+		}
+	case 102:
+		exprDollar = exprS[exprpt-2 : exprpt+1]
+		{
+			exprVAL.PipelineStage = exprDollar[2].PipelineStage
+		}
+	case 103:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.FilterOp = OpFilterIP
 		}
-	case 84:
+	case 104:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.OrFilter = newLineFilterExpr(labels.MatchEqual, "", exprDollar[1].str)
 		}
-	case 85:
+	case 105:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
 		{
 			exprVAL.OrFilter = newLineFilterExpr(labels.MatchEqual, exprDollar[1].FilterOp, exprDollar[3].str)
 		}
-	case 86:
+	case 106:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.OrFilter = newOrLineFilter(newLineFilterExpr(labels.MatchEqual, "", exprDollar[1].str), exprDollar[3].OrFilter)
 		}
-	case 87:
+	case 107:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
 		{
 			exprVAL.LineFilter = newLineFilterExpr(exprDollar[1].Filter, "", exprDollar[2].str)
 		}
-	case 88:
+	case 108:
 		exprDollar = exprS[exprpt-5 : exprpt+1]
 		{
 			exprVAL.LineFilter = newLineFilterExpr(exprDollar[1].Filter, exprDollar[2].FilterOp, exprDollar[4].str)
 		}
-	case 89:
+	case 109:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
 		{
 			exprVAL.LineFilter = newOrLineFilter(newLineFilterExpr(exprDollar[1].Filter, "", exprDollar[2].str), exprDollar[4].OrFilter)
 		}
-	case 90:
+	case 110:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.LineFilters = exprDollar[1].LineFilter
 		}
-	case 91:
+	case 111:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.LineFilters = newOrLineFilter(exprDollar[1].LineFilter, exprDollar[3].OrFilter)
 		}
-	case 92:
+	case 112:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
 		{
 			exprVAL.LineFilters = newNestedLineFilterExpr(exprDollar[1].LineFilters, exprDollar[2].LineFilter)
 		}
-	case 93:
+	case 113:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.ParserFlags = []string{exprDollar[1].str}
 		}
-	case 94:
+	case 114:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
 		{
 			exprVAL.ParserFlags = append(exprDollar[1].ParserFlags, exprDollar[2].str)
 		}
-	case 95:
+	case 115:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.LogfmtParser = newLogfmtParserExpr(nil)
 		}
-	case 96:
+	case 116:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
 		{
 			exprVAL.LogfmtParser = newLogfmtParserExpr(exprDollar[2].ParserFlags)
 		}
-	case 97:
+	case 117:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.LabelParser = newLabelParserExpr(OpParserTypeJSON, "")
 		}
-	case 98:
+	case 118:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
 		{
 			exprVAL.LabelParser = newLabelParserExpr(OpParserTypeRegexp, exprDollar[2].str)
 		}
-	case 99:
+	case 119:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.LabelParser = newLabelParserExpr(OpParserTypeUnpack, "")
 		}
-	case 100:
+	case 120:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
 		{
 			exprVAL.LabelParser = newLabelParserExpr(OpParserTypePattern, exprDollar[2].str)
 		}
-	case 101:
+	case 121:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
 		{
 			exprVAL.JSONExpressionParser = newJSONExpressionParser(exprDollar[2].LabelExtractionExpressionList)
 		}
-	case 102:
+	case 122:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.LogfmtExpressionParser = newLogfmtExpressionParser(exprDollar[3].LabelExtractionExpressionList, exprDollar[2].ParserFlags)
 		}
-	case 103:
+	case 123:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
 		{
 			exprVAL.LogfmtExpressionParser = newLogfmtExpressionParser(exprDollar[2].LabelExtractionExpressionList, nil)
 		}
-	case 104:
+	case 124:
+		exprDollar = exprS[exprpt-3 : exprpt+1]
+		{
+			exprVAL.ParserOptions = []parserOption{{name: exprDollar[1].str, value: exprDollar[3].str}}
+		}
+	case 125:
+		exprDollar = exprS[exprpt-4 : exprpt+1]
+		{
+			exprVAL.ParserOptions = append(exprDollar[1].ParserOptions, parserOption{name: exprDollar[2].str, value: exprDollar[4].str})
+		}
+	case 126:
+		exprDollar = exprS[exprpt-2 : exprpt+1]
+		{
+			exprVAL.PipelineStage = newCSVParserExpr(exprDollar[2].str, nil)
+		}
+	case 127:
+		exprDollar = exprS[exprpt-3 : exprpt+1]
+		{
+			exprVAL.PipelineStage = newCSVParserExpr(exprDollar[2].str, exprDollar[3].ParserOptions)
+		}
+	case 128:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+		{
+			exprVAL.PipelineStage = newKVParserExpr(nil)
+		}
+	case 129:
+		exprDollar = exprS[exprpt-2 : exprpt+1]
+		{
+			exprVAL.PipelineStage = newKVParserExpr(exprDollar[2].ParserOptions)
+		}
+	case 130:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+		{
+			exprVAL.PipelineStage = newXMLParserExpr(nil)
+		}
+	case 131:
+		exprDollar = exprS[exprpt-2 : exprpt+1]
+		{
+			exprVAL.PipelineStage = newXMLParserExpr(exprDollar[2].LabelExtractionExpressionList)
+		}
+	case 132:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
 		{
 			exprVAL.LineFormatExpr = newLineFmtExpr(exprDollar[2].str)
 		}
-	case 105:
+	case 133:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.DecolorizeExpr = newDecolorizeExpr()
 		}
-	case 106:
+	case 134:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.LabelFormat = log.NewRenameLabelFmt(exprDollar[1].str, exprDollar[3].str)
 		}
-	case 107:
+	case 135:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.LabelFormat = log.NewTemplateLabelFmt(exprDollar[1].str, exprDollar[3].str)
 		}
-	case 108:
+	case 136:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.LabelsFormat = []log.LabelFmt{exprDollar[1].LabelFormat}
 		}
-	case 109:
+	case 137:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.LabelsFormat = append(exprDollar[1].LabelsFormat, exprDollar[3].LabelFormat)
 		}
-	case 111:
+	case 139:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
 		{
 			exprVAL.LabelFormatExpr = newLabelFmtExpr(exprDollar[2].LabelsFormat)
 		}
-	case 112:
+	case 140:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.LabelFilter = log.NewStringLabelFilter(exprDollar[1].Matcher)
 		}
-	case 113:
+	case 141:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.LabelFilter = exprDollar[1].IPLabelFilter
 		}
-	case 114:
+	case 142:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.LabelFilter = exprDollar[1].UnitFilter
 		}
-	case 115:
+	case 143:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.LabelFilter = exprDollar[1].NumberFilter
 		}
-	case 116:
+	case 144:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.LabelFilter = exprDollar[2].LabelFilter
 		}
-	case 117:
+	case 145:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
 		{
 			exprVAL.LabelFilter = log.NewAndLabelFilter(exprDollar[1].LabelFilter, exprDollar[2].LabelFilter)
 		}
-	case 118:
+	case 146:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.LabelFilter = log.NewAndLabelFilter(exprDollar[1].LabelFilter, exprDollar[3].LabelFilter)
 		}
-	case 119:
+	case 147:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.LabelFilter = log.NewAndLabelFilter(exprDollar[1].LabelFilter, exprDollar[3].LabelFilter)
 		}
-	case 120:
+	case 148:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.LabelFilter = log.NewOrLabelFilter(exprDollar[1].LabelFilter, exprDollar[3].LabelFilter)
 		}
-	case 121:
+	case 149:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.LabelExtractionExpression = log.NewLabelExtractionExpr(exprDollar[1].str, exprDollar[3].str)
 		}
-	case 122:
+	case 150:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.LabelExtractionExpression = log.NewLabelExtractionExpr(exprDollar[1].str, exprDollar[1].str)
 		}
-	case 123:
+	case 151:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.LabelExtractionExpressionList = []log.LabelExtractionExpr{exprDollar[1].LabelExtractionExpression}
 		}
-	case 124:
+	case 152:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.LabelExtractionExpressionList = append(exprDollar[1].LabelExtractionExpressionList, exprDollar[3].LabelExtractionExpression)
 		}
-	case 125:
+	case 153:
 		exprDollar = exprS[exprpt-6 : exprpt+1]
 		{
 			exprVAL.IPLabelFilter = log.NewIPLabelFilter(exprDollar[5].str, exprDollar[1].str, log.LabelFilterEqual)
 		}
-	case 126:
+	case 154:
 		exprDollar = exprS[exprpt-6 : exprpt+1]
 		{
 			exprVAL.IPLabelFilter = log.NewIPLabelFilter(exprDollar[5].str, exprDollar[1].str, log.LabelFilterNotEqual)
 		}
-	case 127:
+	case 155:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.UnitFilter = exprDollar[1].DurationFilter
 		}
-	case 128:
+	case 156:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.UnitFilter = exprDollar[1].BytesFilter
 		}
-	case 129:
+	case 157:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.DurationFilter = log.NewDurationLabelFilter(log.LabelFilterGreaterThan, exprDollar[1].str, exprDollar[3].duration)
 		}
-	case 130:
+	case 158:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.DurationFilter = log.NewDurationLabelFilter(log.LabelFilterGreaterThanOrEqual, exprDollar[1].str, exprDollar[3].duration)
 		}
-	case 131:
+	case 159:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.DurationFilter = log.NewDurationLabelFilter(log.LabelFilterLesserThan, exprDollar[1].str, exprDollar[3].duration)
 		}
-	case 132:
+	case 160:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.DurationFilter = log.NewDurationLabelFilter(log.LabelFilterLesserThanOrEqual, exprDollar[1].str, exprDollar[3].duration)
 		}
-	case 133:
+	case 161:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.DurationFilter = log.NewDurationLabelFilter(log.LabelFilterNotEqual, exprDollar[1].str, exprDollar[3].duration)
 		}
-	case 134:
+	case 162:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.DurationFilter = log.NewDurationLabelFilter(log.LabelFilterEqual, exprDollar[1].str, exprDollar[3].duration)
 		}
-	case 135:
+	case 163:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.DurationFilter = log.NewDurationLabelFilter(log.LabelFilterEqual, exprDollar[1].str, exprDollar[3].duration)
 		}
-	case 136:
+	case 164:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.BytesFilter = log.NewBytesLabelFilter(log.LabelFilterGreaterThan, exprDollar[1].str, exprDollar[3].bytes)
 		}
-	case 137:
+	case 165:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.BytesFilter = log.NewBytesLabelFilter(log.LabelFilterGreaterThanOrEqual, exprDollar[1].str, exprDollar[3].bytes)
 		}
-	case 138:
+	case 166:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.BytesFilter = log.NewBytesLabelFilter(log.LabelFilterLesserThan, exprDollar[1].str, exprDollar[3].bytes)
 		}
-	case 139:
+	case 167:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.BytesFilter = log.NewBytesLabelFilter(log.LabelFilterLesserThanOrEqual, exprDollar[1].str, exprDollar[3].bytes)
 		}
-	case 140:
+	case 168:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.BytesFilter = log.NewBytesLabelFilter(log.LabelFilterNotEqual, exprDollar[1].str, exprDollar[3].bytes)
 		}
-	case 141:
+	case 169:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.BytesFilter = log.NewBytesLabelFilter(log.LabelFilterEqual, exprDollar[1].str, exprDollar[3].bytes)
 		}
-	case 142:
+	case 170:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.BytesFilter = log.NewBytesLabelFilter(log.LabelFilterEqual, exprDollar[1].str, exprDollar[3].bytes)
 		}
-	case 143:
+	case 171:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.NumberFilter = log.NewNumericLabelFilter(log.LabelFilterGreaterThan, exprDollar[1].str, mustNewFloat(exprDollar[3].str))
 		}
-	case 144:
+	case 172:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.NumberFilter = log.NewNumericLabelFilter(log.LabelFilterGreaterThanOrEqual, exprDollar[1].str, mustNewFloat(exprDollar[3].str))
 		}
-	case 145:
+	case 173:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.NumberFilter = log.NewNumericLabelFilter(log.LabelFilterLesserThan, exprDollar[1].str, mustNewFloat(exprDollar[3].str))
 		}
-	case 146:
+	case 174:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.NumberFilter = log.NewNumericLabelFilter(log.LabelFilterLesserThanOrEqual, exprDollar[1].str, mustNewFloat(exprDollar[3].str))
 		}
-	case 147:
+	case 175:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.NumberFilter = log.NewNumericLabelFilter(log.LabelFilterNotEqual, exprDollar[1].str, mustNewFloat(exprDollar[3].str))
 		}
-	case 148:
+	case 176:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.NumberFilter = log.NewNumericLabelFilter(log.LabelFilterEqual, exprDollar[1].str, mustNewFloat(exprDollar[3].str))
 		}
-	case 149:
+	case 177:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.NumberFilter = log.NewNumericLabelFilter(log.LabelFilterEqual, exprDollar[1].str, mustNewFloat(exprDollar[3].str))
 		}
-	case 150:
+	case 178:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.DropLabel = log.NewDropLabel(nil, exprDollar[1].str)
 		}
-	case 151:
+	case 179:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.DropLabel = log.NewDropLabel(exprDollar[1].Matcher, "")
 		}
-	case 152:
+	case 180:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.DropLabels = []log.DropLabel{exprDollar[1].DropLabel}
 		}
-	case 153:
+	case 181:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.DropLabels = append(exprDollar[1].DropLabels, exprDollar[3].DropLabel)
 		}
-	case 154:
+	case 182:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
 		{
 			exprVAL.DropLabelsExpr = newDropLabelsExpr(exprDollar[2].DropLabels)
 		}
-	case 155:
+	case 183:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.KeepLabel = log.NewKeepLabel(nil, exprDollar[1].str)
 		}
-	case 156:
+	case 184:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.KeepLabel = log.NewKeepLabel(exprDollar[1].Matcher, "")
 		}
-	case 157:
+	case 185:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.KeepLabels = []log.KeepLabel{exprDollar[1].KeepLabel}
 		}
-	case 158:
+	case 186:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.KeepLabels = append(exprDollar[1].KeepLabels, exprDollar[3].KeepLabel)
 		}
-	case 159:
+	case 187:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
 		{
 			exprVAL.KeepLabelsExpr = newKeepLabelsExpr(exprDollar[2].KeepLabels)
 		}
-	case 160:
+	case 188:
+		exprDollar = exprS[exprpt-2 : exprpt+1]
+		{
+			exprVAL.PipelineStage = newLineLimitExpr(exprDollar[2].str)
+		}
+	case 189:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+		{
+			exprVAL.PipelineStage = newDedupExpr(nil, 0)
+		}
+	case 190:
+		exprDollar = exprS[exprpt-2 : exprpt+1]
+		{
+			exprVAL.PipelineStage = newDedupExpr(nil, exprDollar[2].duration)
+		}
+	case 191:
+		exprDollar = exprS[exprpt-5 : exprpt+1]
+		{
+			exprVAL.PipelineStage = newDedupExpr(exprDollar[4].Labels, 0)
+		}
+	case 192:
+		exprDollar = exprS[exprpt-6 : exprpt+1]
+		{
+			exprVAL.PipelineStage = newDedupExpr(exprDollar[4].Labels, exprDollar[6].duration)
+		}
+	case 193:
+		exprDollar = exprS[exprpt-2 : exprpt+1]
+		{
+			exprVAL.PipelineStage = newStructuredMetadataFilterExpr(exprDollar[2].Matchers)
+		}
+	case 194:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
 		{
 			exprVAL.BinOpExpr = mustNewBinOpExpr("or", exprDollar[3].BinOpModifier, exprDollar[1].Expr, exprDollar[4].Expr)
 		}
-	case 161:
+	case 195:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
 		{
 			exprVAL.BinOpExpr = mustNewBinOpExpr("and", exprDollar[3].BinOpModifier, exprDollar[1].Expr, exprDollar[4].Expr)
 		}
-	case 162:
+	case 196:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
 		{
 			exprVAL.BinOpExpr = mustNewBinOpExpr("unless", exprDollar[3].BinOpModifier, exprDollar[1].Expr, exprDollar[4].Expr)
 		}
-	case 163:
+	case 197:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
 		{
 			exprVAL.BinOpExpr = mustNewBinOpExpr("+", exprDollar[3].BinOpModifier, exprDollar[1].Expr, exprDollar[4].Expr)
 		}
-	case 164:
+	case 198:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
 		{
 			exprVAL.BinOpExpr = mustNewBinOpExpr("-", exprDollar[3].BinOpModifier, exprDollar[1].Expr, exprDollar[4].Expr)
 		}
-	case 165:
+	case 199:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
 		{
 			exprVAL.BinOpExpr = mustNewBinOpExpr("*", exprDollar[3].BinOpModifier, exprDollar[1].Expr, exprDollar[4].Expr)
 		}
-	case 166:
+	case 200:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
 		{
 			exprVAL.BinOpExpr = mustNewBinOpExpr("/", exprDollar[3].BinOpModifier, exprDollar[1].Expr, exprDollar[4].Expr)
 		}
-	case 167:
+	case 201:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
 		{
 			exprVAL.BinOpExpr = mustNewBinOpExpr("%", exprDollar[3].BinOpModifier, exprDollar[1].Expr, exprDollar[4].Expr)
 		}
-	case 168:
+	case 202:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
 		{
 			exprVAL.BinOpExpr = mustNewBinOpExpr("^", exprDollar[3].BinOpModifier, exprDollar[1].Expr, exprDollar[4].Expr)
 		}
-	case 169:
+	case 203:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
 		{
 			exprVAL.BinOpExpr = mustNewBinOpExpr("==", exprDollar[3].BinOpModifier, exprDollar[1].Expr, exprDollar[4].Expr)
 		}
-	case 170:
+	case 204:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
 		{
 			exprVAL.BinOpExpr = mustNewBinOpExpr("!=", exprDollar[3].BinOpModifier, exprDollar[1].Expr, exprDollar[4].Expr)
 		}
-	case 171:
+	case 205:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
 		{
 			exprVAL.BinOpExpr = mustNewBinOpExpr(">", exprDollar[3].BinOpModifier, exprDollar[1].Expr, exprDollar[4].Expr)
 		}
-	case 172:
+	case 206:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
 		{
 			exprVAL.BinOpExpr = mustNewBinOpExpr(">=", exprDollar[3].BinOpModifier, exprDollar[1].Expr, exprDollar[4].Expr)
 		}
-	case 173:
+	case 207:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
 		{
 			exprVAL.BinOpExpr = mustNewBinOpExpr("<", exprDollar[3].BinOpModifier, exprDollar[1].Expr, exprDollar[4].Expr)
 		}
-	case 174:
+	case 208:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
 		{
 			exprVAL.BinOpExpr = mustNewBinOpExpr("<=", exprDollar[3].BinOpModifier, exprDollar[1].Expr, exprDollar[4].Expr)
 		}
-	case 175:
+	case 209:
 		exprDollar = exprS[exprpt-0 : exprpt+1]
 		{
 			exprVAL.BoolModifier = &BinOpOptions{VectorMatching: &VectorMatching{Card: CardOneToOne}}
 		}
-	case 176:
+	case 210:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.BoolModifier = &BinOpOptions{VectorMatching: &VectorMatching{Card: CardOneToOne}, ReturnBool: true}
 		}
-	case 177:
+	case 211:
 		exprDollar = exprS[exprpt-5 : exprpt+1]
 		{
 			exprVAL.OnOrIgnoringModifier = exprDollar[1].BoolModifier
 			exprVAL.OnOrIgnoringModifier.VectorMatching.On = true
 			exprVAL.OnOrIgnoringModifier.VectorMatching.MatchingLabels = exprDollar[4].Labels
 		}
-	case 178:
+	case 212:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
 		{
 			exprVAL.OnOrIgnoringModifier = exprDollar[1].BoolModifier
 			exprVAL.OnOrIgnoringModifier.VectorMatching.On = true
 		}
-	case 179:
+	case 213:
 		exprDollar = exprS[exprpt-5 : exprpt+1]
 		{
 			exprVAL.OnOrIgnoringModifier = exprDollar[1].BoolModifier
 			exprVAL.OnOrIgnoringModifier.VectorMatching.MatchingLabels = exprDollar[4].Labels
 		}
-	case 180:
+	case 214:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
 		{
 			exprVAL.OnOrIgnoringModifier = exprDollar[1].BoolModifier
 		}
-	case 181:
+	case 215:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.BinOpModifier = exprDollar[1].BoolModifier
 		}
-	case 182:
+	case 216:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.BinOpModifier = exprDollar[1].OnOrIgnoringModifier
 		}
-	case 183:
+	case 217:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
 		{
 			exprVAL.BinOpModifier = exprDollar[1].OnOrIgnoringModifier
 			exprVAL.BinOpModifier.VectorMatching.Card = CardManyToOne
 		}
-	case 184:
+	case 218:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
 		{
 			exprVAL.BinOpModifier = exprDollar[1].OnOrIgnoringModifier
 			exprVAL.BinOpModifier.VectorMatching.Card = CardManyToOne
 		}
-	case 185:
+	case 219:
 		exprDollar = exprS[exprpt-5 : exprpt+1]
 		{
 			exprVAL.BinOpModifier = exprDollar[1].OnOrIgnoringModifier
 			exprVAL.BinOpModifier.VectorMatching.Card = CardManyToOne
 			exprVAL.BinOpModifier.VectorMatching.Include = exprDollar[4].Labels
 		}
-	case 186:
+	case 220:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
 		{
 			exprVAL.BinOpModifier = exprDollar[1].OnOrIgnoringModifier
 			exprVAL.BinOpModifier.VectorMatching.Card = CardOneToMany
 		}
-	case 187:
+	case 221:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
 		{
 			exprVAL.BinOpModifier = exprDollar[1].OnOrIgnoringModifier
 			exprVAL.BinOpModifier.VectorMatching.Card = CardOneToMany
 		}
-	case 188:
+	case 222:
 		exprDollar = exprS[exprpt-5 : exprpt+1]
 		{
 			exprVAL.BinOpModifier = exprDollar[1].OnOrIgnoringModifier
 			exprVAL.BinOpModifier.VectorMatching.Card = CardOneToMany
 			exprVAL.BinOpModifier.VectorMatching.Include = exprDollar[4].Labels
 		}
-	case 189:
+	case 223:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.LiteralExpr = mustNewLiteralExpr(exprDollar[1].str, false)
 		}
-	case 190:
+	case 224:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
 		{
 			exprVAL.LiteralExpr = mustNewLiteralExpr(exprDollar[2].str, false)
 		}
-	case 191:
+	case 225:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
 		{
 			exprVAL.LiteralExpr = mustNewLiteralExpr(exprDollar[2].str, true)
 		}
-	case 192:
+	case 226:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
 		{
 			exprVAL.VectorExpr = NewVectorExpr(exprDollar[3].str)
 		}
-	case 193:
+	case 227:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.Vector = OpTypeVector
 		}
-	case 194:
+	case 228:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.VectorOp = OpTypeSum
 		}
-	case 195:
+	case 229:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.VectorOp = OpTypeAvg
 		}
-	case 196:
+	case 230:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.VectorOp = OpTypeCount
 		}
-	case 197:
+	case 231:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.VectorOp = OpTypeMax
 		}
-	case 198:
+	case 232:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.VectorOp = OpTypeMin
 		}
-	case 199:
+	case 233:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.VectorOp = OpTypeStddev
 		}
-	case 200:
+	case 234:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.VectorOp = OpTypeStdvar
 		}
-	case 201:
+	case 235:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.VectorOp = OpTypeBottomK
 		}
-	case 202:
+	case 236:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.VectorOp = OpTypeTopK
 		}
-	case 203:
+	case 237:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+		{
+			exprVAL.VectorOp = OpTypeApproxTopK
+		}
+	case 238:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.VectorOp = OpTypeSort
 		}
-	case 204:
+	case 239:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.VectorOp = OpTypeSortDesc
 		}
-	case 205:
+	case 240:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.RangeOp = OpRangeTypeCount
 		}
-	case 206:
+	case 241:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.RangeOp = OpRangeTypeRate
 		}
-	case 207:
+	case 242:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.RangeOp = OpRangeTypeRateCounter
 		}
-	case 208:
+	case 243:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.RangeOp = OpRangeTypeBytes
 		}
-	case 209:
+	case 244:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.RangeOp = OpRangeTypeBytesRate
 		}
-	case 210:
+	case 245:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.RangeOp = OpRangeTypeAvg
 		}
-	case 211:
+	case 246:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.RangeOp = OpRangeTypeSum
 		}
-	case 212:
+	case 247:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.RangeOp = OpRangeTypeMin
 		}
-	case 213:
+	case 248:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.RangeOp = OpRangeTypeMax
 		}
-	case 214:
+	case 249:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.RangeOp = OpRangeTypeStdvar
 		}
-	case 215:
+	case 250:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.RangeOp = OpRangeTypeStddev
 		}
-	case 216:
+	case 251:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.RangeOp = OpRangeTypeQuantile
 		}
-	case 217:
+	case 252:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.RangeOp = OpRangeTypeFirst
 		}
-	case 218:
+	case 253:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.RangeOp = OpRangeTypeLast
 		}
-	case 219:
+	case 254:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.RangeOp = OpRangeTypeAbsent
 		}
-	case 220:
+	case 255:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+		{
+			exprVAL.RangeOp = OpRangeTypeCountDistinct
+		}
+	case 256:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+		{
+			exprVAL.RangeOp = OpRangeTypeApproxCountDistinct
+		}
+	case 257:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+		{
+			exprVAL.str = OpFuncAbs
+		}
+	case 258:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+		{
+			exprVAL.str = OpFuncCeil
+		}
+	case 259:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+		{
+			exprVAL.str = OpFuncFloor
+		}
+	case 260:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+		{
+			exprVAL.str = OpFuncRound
+		}
+	case 261:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+		{
+			exprVAL.str = OpFuncClampMin
+		}
+	case 262:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+		{
+			exprVAL.str = OpFuncClampMax
+		}
+	case 263:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+		{
+			exprVAL.str = OpFuncSqrt
+		}
+	case 264:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+		{
+			exprVAL.str = OpFuncExp
+		}
+	case 265:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+		{
+			exprVAL.str = OpFuncLn
+		}
+	case 266:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+		{
+			exprVAL.str = OpFuncTimestamp
+		}
+	case 267:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
 		{
 			exprVAL.OffsetExpr = newOffsetExpr(exprDollar[2].duration)
 		}
-	case 221:
+	case 268:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.Labels = []string{exprDollar[1].str}
 		}
-	case 222:
+	case 269:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.Labels = append(exprDollar[1].Labels, exprDollar[3].str)
 		}
-	case 223:
+	case 270:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
 		{
 			exprVAL.Grouping = &Grouping{Without: false, Groups: exprDollar[3].Labels}
 		}
-	case 224:
+	case 271:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
 		{
 			exprVAL.Grouping = &Grouping{Without: true, Groups: exprDollar[3].Labels}
 		}
-	case 225:
+	case 272:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.Grouping = &Grouping{Without: false, Groups: nil}
 		}
-	case 226:
+	case 273:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.Grouping = &Grouping{Without: true, Groups: nil}
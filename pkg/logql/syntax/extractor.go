@@ -65,6 +65,11 @@ func (r RangeAggregationExpr) extractor(override *Grouping) (log.SampleExtractor
 		default:
 			convOp = log.ConvertFloat
 		}
+		// distinct counting operates on the hash of the label values.
+		switch r.Operation {
+		case OpRangeTypeCountDistinct, OpRangeTypeApproxCountDistinct, OpRangeTypeCountDistinctSketch:
+			convOp = log.ConvertHash
+		}
 
 		return log.LabelExtractorWithStages(
 			r.Left.Unwrap.Identifier,
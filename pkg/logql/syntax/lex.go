@@ -80,6 +80,22 @@ var tokens = map[string]int{
 	OpKeep: KEEP,
 }
 
+// pipeTokens are tokens that are only keywords at the start of a pipeline stage, so that they
+// can still be used as label names everywhere else.
+var pipeTokens = map[string]int{
+	// parsers
+	OpParserTypeCSV: CSV,
+	OpParserTypeKV:  KV,
+	OpParserTypeXML: XML,
+
+	// line limits
+	OpLimit: LIMIT,
+	OpDedup: DEDUP,
+
+	// structured metadata filter
+	OpStructuredMetadata: STRUCTURED_METADATA,
+}
+
 var parserFlags = map[string]struct{}{
 	OpStrict:    {},
 	OpKeepEmpty: {},
@@ -105,6 +121,10 @@ var functionTokens = map[string]int{
 	OpRangeTypeAbsent:      ABSENT_OVER_TIME,
 	OpTypeVector:           VECTOR,
 
+	OpRangeTypeCountDistinct:       COUNT_DISTINCT_OVER_TIME,
+	OpRangeTypeApproxCountDistinct: APPROX_COUNT_DISTINCT,
+	OpTypeApproxTopK:               APPROX_TOPK,
+
 	// vec ops
 	OpTypeSum:      SUM,
 	OpTypeAvg:      AVG,
@@ -119,6 +139,20 @@ var functionTokens = map[string]int{
 	OpTypeSortDesc: SORT_DESC,
 	OpLabelReplace: LABEL_REPLACE,
 
+	// functions
+	OpFuncAbs:           ABS,
+	OpFuncCeil:          CEIL,
+	OpFuncFloor:         FLOOR,
+	OpFuncRound:         ROUND,
+	OpFuncClampMin:      CLAMP_MIN,
+	OpFuncClampMax:      CLAMP_MAX,
+	OpFuncSqrt:          SQRT,
+	OpFuncExp:           EXP,
+	OpFuncLn:            LN,
+	OpFuncTimestamp:     TIMESTAMP,
+	OpLabelJoin:         LABEL_JOIN,
+	OpHistogramQuantile: HISTOGRAM_QUANTILE,
+
 	// conversion Op
 	OpConvBytes:           BYTES_CONV,
 	OpConvDuration:        DURATION_CONV,
@@ -132,9 +166,17 @@ type lexer struct {
 	Scanner
 	errs    []logqlmodel.ParseError
 	builder strings.Builder
+	// last is the previously returned token.
+	last int
 }
 
 func (l *lexer) Lex(lval *exprSymType) int {
+	tok := l.lex(lval)
+	l.last = tok
+	return tok
+}
+
+func (l *lexer) lex(lval *exprSymType) int {
 	r := l.Scan()
 
 	switch r {
@@ -200,6 +242,15 @@ func (l *lexer) Lex(lval *exprSymType) int {
 		l.builder.Reset()
 		for r := l.Next(); r != scanner.EOF; r = l.Next() {
 			if r == ']' {
+				if strings.Contains(l.builder.String(), ":") {
+					rng, err := parseSubqueryRange(l.builder.String())
+					if err != nil {
+						l.Error(err.Error())
+						return 0
+					}
+					lval.subqueryRange = rng
+					return SUBQUERY_RANGE
+				}
 				i, err := model.ParseDuration(l.builder.String())
 				if err != nil {
 					l.Error(err.Error())
@@ -245,6 +296,10 @@ func (l *lexer) Lex(lval *exprSymType) int {
 		return tok
 	}
 
+	if tok, ok := pipeTokens[tokenTextLower]; ok && l.last == PIPE && !isLabelFilter(l.Scanner) {
+		return tok
+	}
+
 	lval.str = tokenText
 	return IDENTIFIER
 }
@@ -315,6 +370,25 @@ func tryScanDuration(number string, l *Scanner) (time.Duration, bool) {
 	return duration, true
 }
 
+// parseSubqueryRange parses the content of a subquery range such as `1h:1m`.
+// The resolution after the colon is optional, e.g. `1h:`.
+func parseSubqueryRange(s string) (subqueryRange, error) {
+	rng, step, _ := strings.Cut(s, ":")
+	r, err := model.ParseDuration(strings.TrimSpace(rng))
+	if err != nil {
+		return subqueryRange{}, err
+	}
+	res := subqueryRange{Range: time.Duration(r)}
+	if step = strings.TrimSpace(step); step != "" {
+		s, err := model.ParseDuration(step)
+		if err != nil {
+			return subqueryRange{}, err
+		}
+		res.Step = time.Duration(s)
+	}
+	return res, nil
+}
+
 func parseDuration(d string) (time.Duration, error) {
 	var duration time.Duration
 	// Try to parse promql style durations first, to ensure that we support the same duration
@@ -403,6 +477,16 @@ func isFunction(sc Scanner) bool {
 	return false
 }
 
+// isLabelFilter returns true if the current token is the name of a label filter, e.g. `| limit > 5`.
+func isLabelFilter(sc Scanner) bool {
+	sc = trimSpace(sc)
+	switch sc.Peek() {
+	case '=', '!', '>', '<':
+		return true
+	}
+	return false
+}
+
 func trimSpace(l Scanner) Scanner {
 	for n := l.Peek(); n != scanner.EOF; n = l.Peek() {
 		if unicode.IsSpace(n) {
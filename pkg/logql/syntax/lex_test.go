@@ -83,6 +83,13 @@ func TestLex(t *testing.T) {
 		{`{foo="bar"}|logfmt|rate="b"`, []int{OPEN_BRACE, IDENTIFIER, EQ, STRING, CLOSE_BRACE, PIPE, LOGFMT, PIPE, IDENTIFIER, EQ, STRING}},
 		{`{foo="bar"}|logfmt|b=ip("b")`, []int{OPEN_BRACE, IDENTIFIER, EQ, STRING, CLOSE_BRACE, PIPE, LOGFMT, PIPE, IDENTIFIER, EQ, IP, OPEN_PARENTHESIS, STRING, CLOSE_PARENTHESIS}},
 		{`{foo="bar"}|logfmt|=ip("b")`, []int{OPEN_BRACE, IDENTIFIER, EQ, STRING, CLOSE_BRACE, PIPE, LOGFMT, PIPE_EXACT, IP, OPEN_PARENTHESIS, STRING, CLOSE_PARENTHESIS}},
+		{`{foo="bar"}|csv "a,b" sep=";"`, []int{OPEN_BRACE, IDENTIFIER, EQ, STRING, CLOSE_BRACE, PIPE, CSV, STRING, IDENTIFIER, EQ, STRING}},
+		{`{foo="bar"}|kv sep=";" kvsep=":"|xml a="b/@c"`, []int{OPEN_BRACE, IDENTIFIER, EQ, STRING, CLOSE_BRACE, PIPE, KV, IDENTIFIER, EQ, STRING, IDENTIFIER, EQ, STRING, PIPE, XML, IDENTIFIER, EQ, STRING}},
+		{`{foo="bar"}|dedup by (a,b) 5m|limit 10`, []int{OPEN_BRACE, IDENTIFIER, EQ, STRING, CLOSE_BRACE, PIPE, DEDUP, BY, OPEN_PARENTHESIS, IDENTIFIER, COMMA, IDENTIFIER, CLOSE_PARENTHESIS, DURATION, PIPE, LIMIT, NUMBER}},
+		{`{limit="a"} | json | limit="5" | dedup >= 1`, []int{OPEN_BRACE, IDENTIFIER, EQ, STRING, CLOSE_BRACE, PIPE, JSON, PIPE, IDENTIFIER, EQ, STRING, PIPE, IDENTIFIER, GTE, NUMBER}},
+		{`sum by (limit, dedup) (rate({foo="bar"}[5m]))`, []int{SUM, BY, OPEN_PARENTHESIS, IDENTIFIER, COMMA, IDENTIFIER, CLOSE_PARENTHESIS, OPEN_PARENTHESIS, RATE, OPEN_PARENTHESIS, OPEN_BRACE, IDENTIFIER, EQ, STRING, CLOSE_BRACE, RANGE, CLOSE_PARENTHESIS, CLOSE_PARENTHESIS}},
+		{`{kv="a"} | kv | xml="b" | csv "c"`, []int{OPEN_BRACE, IDENTIFIER, EQ, STRING, CLOSE_BRACE, PIPE, KV, PIPE, IDENTIFIER, EQ, STRING, PIPE, CSV, STRING}},
+		{`{foo="bar"}|structured_metadata a="b", c!~"d"`, []int{OPEN_BRACE, IDENTIFIER, EQ, STRING, CLOSE_BRACE, PIPE, STRUCTURED_METADATA, IDENTIFIER, EQ, STRING, COMMA, IDENTIFIER, NRE, STRING}},
 		{`{foo="bar"}|logfmt --strict --keep-empty|=ip("b")`, []int{OPEN_BRACE, IDENTIFIER, EQ, STRING, CLOSE_BRACE, PIPE, LOGFMT, PARSER_FLAG, PARSER_FLAG, PIPE_EXACT, IP, OPEN_PARENTHESIS, STRING, CLOSE_PARENTHESIS}},
 		{`ip`, []int{IDENTIFIER}},
 		{`rate`, []int{IDENTIFIER}},
@@ -95,6 +102,10 @@ func TestLex(t *testing.T) {
 		{`{foo="bar"} | logfmt --strict code"`, []int{OPEN_BRACE, IDENTIFIER, EQ, STRING, CLOSE_BRACE, PIPE, LOGFMT, PARSER_FLAG, IDENTIFIER}},
 		{`{foo="bar"} | logfmt --keep-empty --strict code="response.code", IPAddress="host"`, []int{OPEN_BRACE, IDENTIFIER, EQ, STRING, CLOSE_BRACE, PIPE, LOGFMT, PARSER_FLAG, PARSER_FLAG, IDENTIFIER, EQ, STRING, COMMA, IDENTIFIER, EQ, STRING}},
 		{`decolorize`, []int{DECOLORIZE}},
+		{`round(rate({foo="bar"}[5m]), 0.5)`, []int{ROUND, OPEN_PARENTHESIS, RATE, OPEN_PARENTHESIS, OPEN_BRACE, IDENTIFIER, EQ, STRING, CLOSE_BRACE, RANGE, CLOSE_PARENTHESIS, COMMA, NUMBER, CLOSE_PARENTHESIS}},
+		{`sum by (timestamp) (rate({foo="bar"}[5m]))`, []int{SUM, BY, OPEN_PARENTHESIS, IDENTIFIER, CLOSE_PARENTHESIS, OPEN_PARENTHESIS, RATE, OPEN_PARENTHESIS, OPEN_BRACE, IDENTIFIER, EQ, STRING, CLOSE_BRACE, RANGE, CLOSE_PARENTHESIS, CLOSE_PARENTHESIS}},
+		{`max_over_time(rate({foo="bar"}[5m])[1h:1m])`, []int{MAX_OVER_TIME, OPEN_PARENTHESIS, RATE, OPEN_PARENTHESIS, OPEN_BRACE, IDENTIFIER, EQ, STRING, CLOSE_BRACE, RANGE, CLOSE_PARENTHESIS, SUBQUERY_RANGE, CLOSE_PARENTHESIS}},
+		{`max_over_time(rate({foo="bar"}[5m])[1h:] offset 5m)`, []int{MAX_OVER_TIME, OPEN_PARENTHESIS, RATE, OPEN_PARENTHESIS, OPEN_BRACE, IDENTIFIER, EQ, STRING, CLOSE_BRACE, RANGE, CLOSE_PARENTHESIS, SUBQUERY_RANGE, OFFSET, DURATION, CLOSE_PARENTHESIS}},
 	} {
 		t.Run(tc.input, func(t *testing.T) {
 			actual := []int{}
@@ -18,6 +18,8 @@ const (
 	EmptyMatchers = "{}"
 
 	errAtleastOneEqualityMatcherRequired = "queries require at least one regexp or equality matcher that does not have an empty-compatible value. For instance, app=~\".*\" does not meet this requirement, but app=~\".+\" will"
+	errLineLimitsInMetricQuery           = "limit and dedup stages are not supported in metric queries"
+	errLineLimitsNotLast                 = "limit and dedup stages must be the last stages of the pipeline"
 )
 
 var parserPool = sync.Pool{
@@ -47,6 +49,9 @@ func init() {
 	for str, tok := range tokens {
 		exprToknames[tok-exprPrivate+1] = str
 	}
+	for str, tok := range pipeTokens {
+		exprToknames[tok-exprPrivate+1] = str
+	}
 }
 
 type parser struct {
@@ -58,6 +63,7 @@ type parser struct {
 
 func (p *parser) Parse() (Expr, error) {
 	p.lexer.errs = p.lexer.errs[:0]
+	p.lexer.last = 0
 	p.lexer.Scanner.Error = func(_ *Scanner, msg string) {
 		p.lexer.Error(msg)
 	}
@@ -205,11 +211,34 @@ func validateSampleExpr(expr SampleExpr) error {
 			}
 		}
 		return validateSampleExpr(e.Left)
+	case *SubqueryExpr:
+		if e.err != nil {
+			return e.err
+		}
+		return validateSampleExpr(e.Left)
+	case *FunctionExpr:
+		if e.err != nil {
+			return e.err
+		}
+		return validateSampleExpr(e.Left)
+	case *LabelJoinExpr:
+		if e.err != nil {
+			return e.err
+		}
+		return validateSampleExpr(e.Left)
+	case *HistogramQuantileExpr:
+		if e.err != nil {
+			return e.err
+		}
+		return validateSampleExpr(e.Left)
 	default:
 		selector, err := e.Selector()
 		if err != nil {
 			return err
 		}
+		if len(LineLimits(selector)) > 0 {
+			return logqlmodel.NewParseError(errLineLimitsInMetricQuery, 0, 0)
+		}
 		return validateLogSelectorExpression(selector)
 	}
 }
@@ -219,6 +248,9 @@ func validateLogSelectorExpression(expr LogSelectorExpr) error {
 	case *VectorExpr:
 		return nil
 	default:
+		if rest, _ := SplitLineLimits(e); len(LineLimits(rest)) > 0 {
+			return logqlmodel.NewParseError(errLineLimitsNotLast, 0, 0)
+		}
 		return validateMatchers(e.Matchers())
 	}
 }
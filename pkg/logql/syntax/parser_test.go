@@ -377,6 +377,96 @@ var ParseTestCases = []struct {
 		in:  `label_replace(rate({ foo = "bar" }[5m]),"foo","$1","bar","^^^^x43\\q")`,
 		err: logqlmodel.NewParseError("invalid regex in label_replace: error parsing regexp: invalid escape sequence: `\\q`", 0, 0),
 	},
+	{
+		in: `max_over_time(rate({ foo = "bar" }[5m])[1h:1m])`,
+		exp: newSubqueryExpr(
+			newRangeAggregationExpr(
+				&LogRange{
+					Left:     &MatchersExpr{Mts: []*labels.Matcher{mustNewMatcher(labels.MatchEqual, "foo", "bar")}},
+					Interval: 5 * time.Minute,
+				}, OpRangeTypeRate, nil, nil),
+			OpRangeTypeMax, subqueryRange{Range: time.Hour, Step: time.Minute}, nil, nil),
+	},
+	{
+		in: `quantile_over_time(0.99, sum by (foo) (count_over_time({ foo = "bar" }[5m]))[1h:] offset 10m)`,
+		exp: newSubqueryExpr(
+			mustNewVectorAggregationExpr(
+				newRangeAggregationExpr(
+					&LogRange{
+						Left:     &MatchersExpr{Mts: []*labels.Matcher{mustNewMatcher(labels.MatchEqual, "foo", "bar")}},
+						Interval: 5 * time.Minute,
+					}, OpRangeTypeCount, nil, nil),
+				OpTypeSum, &Grouping{Groups: []string{"foo"}}, nil),
+			OpRangeTypeQuantile, subqueryRange{Range: time.Hour}, newOffsetExpr(10*time.Minute), NewStringLabelFilter("0.99")),
+	},
+	{
+		in:  `rate(count_over_time({ foo = "bar" }[5m])[1h:1m])`,
+		err: logqlmodel.NewParseError("invalid aggregation rate over subquery", 0, 0),
+	},
+	{
+		in:  `max_over_time(rate({ foo = "bar" }[5m])[1h:1minutes])`,
+		err: logqlmodel.NewParseError(`unknown unit "minutes" in duration "1minutes"`, 0, 40),
+	},
+	{
+		in: `round(rate({ foo = "bar" }[5m]), 0.5)`,
+		exp: newFunctionExpr(
+			newRangeAggregationExpr(
+				&LogRange{
+					Left:     &MatchersExpr{Mts: []*labels.Matcher{mustNewMatcher(labels.MatchEqual, "foo", "bar")}},
+					Interval: 5 * time.Minute,
+				}, OpRangeTypeRate, nil, nil),
+			OpFuncRound, mustNewLiteralExpr("0.5", false)),
+	},
+	{
+		in: `clamp_min(abs(rate({ foo = "bar" }[5m])), -1)`,
+		exp: newFunctionExpr(
+			newFunctionExpr(
+				newRangeAggregationExpr(
+					&LogRange{
+						Left:     &MatchersExpr{Mts: []*labels.Matcher{mustNewMatcher(labels.MatchEqual, "foo", "bar")}},
+						Interval: 5 * time.Minute,
+					}, OpRangeTypeRate, nil, nil),
+				OpFuncAbs, nil),
+			OpFuncClampMin, mustNewLiteralExpr("1", true)),
+	},
+	{
+		in:  `clamp_max(rate({ foo = "bar" }[5m]))`,
+		err: logqlmodel.NewParseError("parameter required for function clamp_max", 0, 0),
+	},
+	{
+		in:  `abs(rate({ foo = "bar" }[5m]), 2)`,
+		err: logqlmodel.NewParseError("parameter 2 not supported for function abs", 0, 0),
+	},
+	{
+		in:  `ceil(5)`,
+		err: logqlmodel.NewParseError("expected vector expression as argument of ceil", 0, 0),
+	},
+	{
+		in: `label_join(rate({ foo = "bar" }[5m]), "dst", "-", "foo", "bar")`,
+		exp: newLabelJoinExpr(
+			newRangeAggregationExpr(
+				&LogRange{
+					Left:     &MatchersExpr{Mts: []*labels.Matcher{mustNewMatcher(labels.MatchEqual, "foo", "bar")}},
+					Interval: 5 * time.Minute,
+				}, OpRangeTypeRate, nil, nil),
+			"dst", "-", []string{"foo", "bar"}),
+	},
+	{
+		in:  `label_join(rate({ foo = "bar" }[5m]), "1dst", "-", "foo")`,
+		err: logqlmodel.NewParseError("invalid destination label name in label_join: 1dst", 0, 0),
+	},
+	{
+		in: `histogram_quantile(0.99, sum by (le) (count_over_time({ foo = "bar" }[5m])))`,
+		exp: newHistogramQuantileExpr(
+			mustNewVectorAggregationExpr(
+				newRangeAggregationExpr(
+					&LogRange{
+						Left:     &MatchersExpr{Mts: []*labels.Matcher{mustNewMatcher(labels.MatchEqual, "foo", "bar")}},
+						Interval: 5 * time.Minute,
+					}, OpRangeTypeCount, nil, nil),
+				OpTypeSum, &Grouping{Groups: []string{"le"}}, nil),
+			"0.99"),
+	},
 	{
 		in:  `rate({ foo = "bar" }[5)`,
 		err: logqlmodel.NewParseError("missing closing ']' in duration", 0, 21),
@@ -1633,6 +1723,66 @@ var ParseTestCases = []struct {
 		exp: nil,
 		err: logqlmodel.NewParseError("invalid aggregation count_over_time with unwrap", 0, 0),
 	},
+	{
+		in: `count_distinct_over_time({app="foo"} | logfmt | unwrap user_id [5m]) by (namespace)`,
+		exp: newRangeAggregationExpr(
+			newLogRange(
+				newPipelineExpr(
+					newMatcherExpr([]*labels.Matcher{mustNewMatcher(labels.MatchEqual, "app", "foo")}),
+					MultiStageExpr{newLogfmtParserExpr(nil)},
+				),
+				5*time.Minute,
+				newUnwrapExpr("user_id", ""),
+				nil),
+			OpRangeTypeCountDistinct,
+			&Grouping{Groups: []string{"namespace"}},
+			nil,
+		),
+	},
+	{
+		in: `approx_count_distinct({app="foo"} | logfmt | unwrap user_id [5m])`,
+		exp: newRangeAggregationExpr(
+			newLogRange(
+				newPipelineExpr(
+					newMatcherExpr([]*labels.Matcher{mustNewMatcher(labels.MatchEqual, "app", "foo")}),
+					MultiStageExpr{newLogfmtParserExpr(nil)},
+				),
+				5*time.Minute,
+				newUnwrapExpr("user_id", ""),
+				nil),
+			OpRangeTypeApproxCountDistinct,
+			nil,
+			nil,
+		),
+	},
+	{
+		in:  `count_distinct_over_time({app="foo"} | logfmt [5m])`,
+		exp: nil,
+		err: logqlmodel.NewParseError("invalid aggregation count_distinct_over_time without unwrap", 0, 0),
+	},
+	{
+		in:  `approx_count_distinct({app="foo"} | logfmt | unwrap bytes(size) [5m])`,
+		exp: nil,
+		err: logqlmodel.NewParseError("invalid conversion bytes for aggregation approx_count_distinct", 0, 0),
+	},
+	{
+		in: `approx_topk(10, sum by (path) (count_over_time({app="foo"}[5m])))`,
+		exp: mustNewVectorAggregationExpr(mustNewVectorAggregationExpr(&RangeAggregationExpr{
+			Left: &LogRange{
+				Left:     &MatchersExpr{Mts: []*labels.Matcher{mustNewMatcher(labels.MatchEqual, "app", "foo")}},
+				Interval: 5 * time.Minute,
+			},
+			Operation: OpRangeTypeCount,
+		}, OpTypeSum, &Grouping{Groups: []string{"path"}}, nil), OpTypeApproxTopK, nil, NewStringLabelFilter("10")),
+	},
+	{
+		in:  `approx_topk(sum by (path) (count_over_time({app="foo"}[5m])))`,
+		err: logqlmodel.NewParseError("parameter required for operation approx_topk", 0, 0),
+	},
+	{
+		in:  `approx_topk(10, sum by (path) (count_over_time({app="foo"}[5m]))) by (path)`,
+		err: logqlmodel.NewParseError("grouping not allowed for approx_topk aggregation", 0, 0),
+	},
 	{
 		in: `{app="foo"} |= "bar" | json |  status_code < 500 or status_code > 200 and size >= 2.5KiB `,
 		exp: &PipelineExpr{
@@ -2890,7 +3040,7 @@ var ParseTestCases = []struct {
 	},
 	{
 		in:  `quantile_over_time(foo,{namespace="tns"} |= "level=error" | json |foo>=5,bar<25ms| unwrap latency [5m])`,
-		err: logqlmodel.NewParseError("syntax error: unexpected IDENTIFIER, expecting NUMBER or { or (", 1, 20),
+		err: logqlmodel.NewParseError("syntax error: unexpected IDENTIFIER", 1, 20),
 	},
 	{
 		in:  `vector(abc)`,
@@ -3138,6 +3288,257 @@ var ParseTestCases = []struct {
 			},
 		},
 	},
+	{
+		in: `{app="foo"} | csv "ts,,level, msg"`,
+		exp: &PipelineExpr{
+			Left: newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
+			MultiStages: MultiStageExpr{
+				&CSVParserExpr{Columns: []string{"ts", "", "level", "msg"}, Separator: ','},
+			},
+		},
+	},
+	{
+		in: `{app="foo"} | csv "ts,level" sep="\t" | level="error"`,
+		exp: &PipelineExpr{
+			Left: newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
+			MultiStages: MultiStageExpr{
+				&CSVParserExpr{Columns: []string{"ts", "level"}, Separator: '\t'},
+				newLabelFilterExpr(log.NewStringLabelFilter(mustNewMatcher(labels.MatchEqual, "level", "error"))),
+			},
+		},
+	},
+	{
+		in:  `{app="foo"} | csv "ts,level" sep=";;"`,
+		err: logqlmodel.NewParseError(`invalid csv parser: separator must be a single character, got ";;"`, 0, 0),
+	},
+	{
+		in:  `{app="foo"} | csv "ts,level" kvsep=";"`,
+		err: logqlmodel.NewParseError(`invalid csv parser: unknown option "kvsep"`, 0, 0),
+	},
+	{
+		in:  `{app="foo"} | csv "ts,1level"`,
+		err: logqlmodel.NewParseError(`invalid csv parser: invalid extracted label name '1level'`, 0, 0),
+	},
+	{
+		in: `{app="foo"} | kv`,
+		exp: &PipelineExpr{
+			Left: newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
+			MultiStages: MultiStageExpr{
+				&KVParserExpr{Separator: ",", KVSeparator: "="},
+			},
+		},
+	},
+	{
+		in: `{app="foo"} | kv sep=";" kvsep=":" | line_format "{{.msg}}"`,
+		exp: &PipelineExpr{
+			Left: newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
+			MultiStages: MultiStageExpr{
+				&KVParserExpr{Separator: ";", KVSeparator: ":"},
+				newLineFmtExpr("{{.msg}}"),
+			},
+		},
+	},
+	{
+		in:  `{app="foo"} | kv sep=":" kvsep=":"`,
+		err: logqlmodel.NewParseError(`invalid kv parser: kv separators must be different, both are ":"`, 0, 0),
+	},
+	{
+		in:  `{app="foo"} | kv separator=";"`,
+		err: logqlmodel.NewParseError(`invalid kv parser: unknown option "separator"`, 0, 0),
+	},
+	{
+		in: `{app="foo"} | xml`,
+		exp: &PipelineExpr{
+			Left: newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
+			MultiStages: MultiStageExpr{
+				&XMLParserExpr{},
+			},
+		},
+	},
+	{
+		in: `{app="foo"} | xml user="event/user", id="event/@id", event`,
+		exp: &PipelineExpr{
+			Left: newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
+			MultiStages: MultiStageExpr{
+				&XMLParserExpr{Expressions: []log.LabelExtractionExpr{
+					log.NewLabelExtractionExpr("user", `event/user`),
+					log.NewLabelExtractionExpr("id", `event/@id`),
+					log.NewLabelExtractionExpr("event", `event`),
+				}},
+			},
+		},
+	},
+	{
+		in:  `{app="foo"} | xml user="event//user"`,
+		err: logqlmodel.NewParseError(`invalid xml parser: cannot parse expression [event//user]: empty element name`, 0, 0),
+	},
+	{
+		in: `{app="foo"} |= "error" | limit 10`,
+		exp: &PipelineExpr{
+			Left: newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
+			MultiStages: MultiStageExpr{
+				newLineFilterExpr(labels.MatchEqual, "", "error"),
+				&LineLimitExpr{Limit: 10},
+			},
+		},
+	},
+	{
+		in: `{app="foo"} | json | dedup by (request_id, pod) 5m | limit 1`,
+		exp: &PipelineExpr{
+			Left: newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
+			MultiStages: MultiStageExpr{
+				newLabelParserExpr(OpParserTypeJSON, ""),
+				&DedupExpr{By: []string{"request_id", "pod"}, Window: 5 * time.Minute},
+				&LineLimitExpr{Limit: 1},
+			},
+		},
+	},
+	{
+		in: `{app="foo"} | dedup 30s`,
+		exp: &PipelineExpr{
+			Left: newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
+			MultiStages: MultiStageExpr{
+				&DedupExpr{Window: 30 * time.Second},
+			},
+		},
+	},
+	{
+		in: `{app="foo"} | dedup`,
+		exp: &PipelineExpr{
+			Left: newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
+			MultiStages: MultiStageExpr{
+				&DedupExpr{},
+			},
+		},
+	},
+	{
+		in: `{app="foo"} | structured_metadata trace_id="abc", env=~"prod.*" | json`,
+		exp: &PipelineExpr{
+			Left: newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
+			MultiStages: MultiStageExpr{
+				&StructuredMetadataFilterExpr{Matchers: []*labels.Matcher{
+					mustNewMatcher(labels.MatchEqual, "trace_id", "abc"),
+					mustNewMatcher(labels.MatchRegexp, "env", "prod.*"),
+				}},
+				newLabelParserExpr(OpParserTypeJSON, ""),
+			},
+		},
+	},
+	{
+		in: `count_over_time({app="foo"} | structured_metadata trace_id!="" [5m])`,
+		exp: newRangeAggregationExpr(
+			newLogRange(&PipelineExpr{
+				Left: newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
+				MultiStages: MultiStageExpr{
+					&StructuredMetadataFilterExpr{Matchers: []*labels.Matcher{
+						mustNewMatcher(labels.MatchNotEqual, "trace_id", ""),
+					}},
+				},
+			}, 5*time.Minute, nil, nil),
+			OpRangeTypeCount, nil, nil,
+		),
+	},
+	{
+		in:  `{app="foo"} | limit 0`,
+		err: logqlmodel.NewParseError(`invalid limit: 0, it must be a positive integer`, 0, 0),
+	},
+	{
+		in:  `{app="foo"} | limit 1.5`,
+		err: logqlmodel.NewParseError(`invalid limit: 1.5, it must be a positive integer`, 0, 0),
+	},
+	{
+		in:  `{app="foo"} | limit 10 | json`,
+		err: logqlmodel.NewParseError(errLineLimitsNotLast, 0, 0),
+	},
+	{
+		in:  `count_over_time({app="foo"} | dedup [5m])`,
+		err: logqlmodel.NewParseError(errLineLimitsInMetricQuery, 0, 0),
+	},
+	{
+		in:  `{limit="a", dedup="b"}`,
+		exp: newMatcherExpr([]*labels.Matcher{mustNewMatcher(labels.MatchEqual, "limit", "a"), mustNewMatcher(labels.MatchEqual, "dedup", "b")}),
+	},
+	{
+		in: `{app="foo"} | json | limit="5" | dedup > 1 | limit 10`,
+		exp: &PipelineExpr{
+			Left: newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
+			MultiStages: MultiStageExpr{
+				newLabelParserExpr(OpParserTypeJSON, ""),
+				&LabelFilterExpr{LabelFilterer: log.NewStringLabelFilter(mustNewMatcher(labels.MatchEqual, "limit", "5"))},
+				&LabelFilterExpr{LabelFilterer: log.NewNumericLabelFilter(log.LabelFilterGreaterThan, "dedup", 1)},
+				&LineLimitExpr{Limit: 10},
+			},
+		},
+	},
+	{
+		in: `sum by (limit, dedup) (count_over_time({app="foo"}[5m]))`,
+		exp: mustNewVectorAggregationExpr(
+			newRangeAggregationExpr(
+				&LogRange{Left: newMatcherExpr([]*labels.Matcher{mustNewMatcher(labels.MatchEqual, "app", "foo")}), Interval: 5 * time.Minute},
+				OpRangeTypeCount, nil, nil,
+			),
+			OpTypeSum,
+			&Grouping{Groups: []string{"limit", "dedup"}},
+			nil,
+		),
+	},
+	{
+		in: `sum by (csv, kv, xml) (count_over_time({app="foo"}[5m]))`,
+		exp: mustNewVectorAggregationExpr(
+			newRangeAggregationExpr(
+				&LogRange{Left: newMatcherExpr([]*labels.Matcher{mustNewMatcher(labels.MatchEqual, "app", "foo")}), Interval: 5 * time.Minute},
+				OpRangeTypeCount, nil, nil,
+			),
+			OpTypeSum,
+			&Grouping{Groups: []string{"csv", "kv", "xml"}},
+			nil,
+		),
+	},
+	{
+		in: `sum without (kv) (count_over_time({app="foo"}[5m]))`,
+		exp: mustNewVectorAggregationExpr(
+			newRangeAggregationExpr(
+				&LogRange{Left: newMatcherExpr([]*labels.Matcher{mustNewMatcher(labels.MatchEqual, "app", "foo")}), Interval: 5 * time.Minute},
+				OpRangeTypeCount, nil, nil,
+			),
+			OpTypeSum,
+			&Grouping{Groups: []string{"kv"}, Without: true},
+			nil,
+		),
+	},
+	{
+		in: `sum by (structured_metadata) (count_over_time({app="foo"} | structured_metadata="a" | structured_metadata structured_metadata="b" [5m]))`,
+		exp: mustNewVectorAggregationExpr(
+			newRangeAggregationExpr(
+				&LogRange{
+					Left: &PipelineExpr{
+						Left: newMatcherExpr([]*labels.Matcher{mustNewMatcher(labels.MatchEqual, "app", "foo")}),
+						MultiStages: MultiStageExpr{
+							&LabelFilterExpr{LabelFilterer: log.NewStringLabelFilter(mustNewMatcher(labels.MatchEqual, "structured_metadata", "a"))},
+							newStructuredMetadataFilterExpr([]*labels.Matcher{mustNewMatcher(labels.MatchEqual, "structured_metadata", "b")}),
+						},
+					},
+					Interval: 5 * time.Minute,
+				},
+				OpRangeTypeCount, nil, nil,
+			),
+			OpTypeSum,
+			&Grouping{Groups: []string{"structured_metadata"}},
+			nil,
+		),
+	},
+	{
+		in: `{app="foo"} | kv | kv="1" | xml="2" | csv != "3"`,
+		exp: &PipelineExpr{
+			Left: newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
+			MultiStages: MultiStageExpr{
+				&KVParserExpr{Separator: ",", KVSeparator: "="},
+				&LabelFilterExpr{LabelFilterer: log.NewStringLabelFilter(mustNewMatcher(labels.MatchEqual, "kv", "1"))},
+				&LabelFilterExpr{LabelFilterer: log.NewStringLabelFilter(mustNewMatcher(labels.MatchEqual, "xml", "2"))},
+				&LabelFilterExpr{LabelFilterer: log.NewStringLabelFilter(mustNewMatcher(labels.MatchNotEqual, "csv", "3"))},
+			},
+		},
+	},
 	{
 		in: `{app="foo"} |= "foo" or "bar" |= "buzz" or "fizz"`,
 		exp: &PipelineExpr{
@@ -113,6 +113,23 @@ func (e *LabelParserExpr) Pretty(level int) string {
 	return commonPrefixIndent(level, e)
 }
 
+// e.g: | csv "col1,col2" sep=";"
+func (e *CSVParserExpr) Pretty(level int) string {
+	return commonPrefixIndent(level, e)
+}
+
+// e.g: | kv sep=";" kvsep=":"
+func (e *KVParserExpr) Pretty(level int) string {
+	return commonPrefixIndent(level, e)
+}
+
+// e.g:
+// `| xml`
+// `| xml label="expression", another="expression"`
+func (e *XMLParserExpr) Pretty(level int) string {
+	return commonPrefixIndent(level, e)
+}
+
 func (e *DropLabelsExpr) Pretty(level int) string {
 	return commonPrefixIndent(level, e)
 }
@@ -121,6 +138,21 @@ func (e *KeepLabelsExpr) Pretty(level int) string {
 	return commonPrefixIndent(level, e)
 }
 
+// e.g: | limit 10
+func (e *LineLimitExpr) Pretty(level int) string {
+	return commonPrefixIndent(level, e)
+}
+
+// e.g: | dedup by (request_id) 5m
+func (e *DedupExpr) Pretty(level int) string {
+	return commonPrefixIndent(level, e)
+}
+
+// e.g: | structured_metadata trace_id="abc", env=~"prod.*"
+func (e *StructuredMetadataFilterExpr) Pretty(level int) string {
+	return commonPrefixIndent(level, e)
+}
+
 // e.g: | level!="error"
 func (e *LabelFilterExpr) Pretty(level int) string {
 	return commonPrefixIndent(level, e)
@@ -226,6 +258,35 @@ func (e *RangeAggregationExpr) Pretty(level int) string {
 	return s
 }
 
+// e.g: max_over_time(rate({foo="bar"}[5m])[1h:1m])
+func (e *SubqueryExpr) Pretty(level int) string {
+	s := Indent(level)
+	if !NeedSplit(e) {
+		return s + e.String()
+	}
+
+	s += e.Operation // e.g: max_over_time
+
+	s += "(\n"
+
+	// print args to the function.
+	if e.Params != nil {
+		s = fmt.Sprintf("%s%s%s,", s, Indent(level+1), fmt.Sprint(*e.Params))
+		s += "\n"
+	}
+
+	s += e.Left.Pretty(level+1) + e.rangeString()
+
+	if e.Offset != 0 {
+		offsetExpr := OffsetExpr{Offset: e.Offset}
+		s += offsetExpr.Pretty(level)
+	}
+
+	s += "\n" + Indent(level) + ")"
+
+	return s
+}
+
 // e.g:
 // sum(count_over_time({foo="bar"}[5m])) by (container)
 // topk(10, count_over_time({foo="bar"}[5m])) by (container)
@@ -248,7 +309,7 @@ func (e *VectorAggregationExpr) Pretty(level int) string {
 	left := e.Left.Pretty(level + 1)
 	switch e.Operation {
 	// e.Params default value (0) can mean a legit param for topk and bottomk
-	case OpTypeBottomK, OpTypeTopK:
+	case OpTypeBottomK, OpTypeTopK, OpTypeApproxTopK, OpTypeTopKSketch:
 		params = []string{fmt.Sprintf("%s%d", Indent(level+1), e.Params), left}
 
 	default:
@@ -338,6 +399,85 @@ func (e *LabelReplaceExpr) Pretty(level int) string {
 	return s
 }
 
+// e.g: round(rate({job="api-server",service="a:c"}[5m]), 0.5)
+func (e *FunctionExpr) Pretty(level int) string {
+	s := Indent(level)
+
+	if !NeedSplit(e) {
+		return s + e.String()
+	}
+
+	s += e.Operation
+
+	s += "(\n"
+
+	s += e.Left.Pretty(level + 1)
+
+	if e.Params != nil {
+		s += ",\n" + Indent(level+1) + strconv.FormatFloat(*e.Params, 'f', -1, 64)
+	}
+
+	s += "\n" + Indent(level) + ")"
+
+	return s
+}
+
+// e.g: label_join(rate({job="api-server",service="a:c"}[5m]), "foo", ",", "job", "service")
+func (e *LabelJoinExpr) Pretty(level int) string {
+	s := Indent(level)
+
+	if !NeedSplit(e) {
+		return s + e.String()
+	}
+
+	s += OpLabelJoin
+
+	s += "(\n"
+
+	params := []string{
+		e.Left.Pretty(level + 1),
+		Indent(level+1) + strconv.Quote(e.Dst),
+		Indent(level+1) + strconv.Quote(e.Separator),
+	}
+	for _, src := range e.Src {
+		params = append(params, Indent(level+1)+strconv.Quote(src))
+	}
+
+	for i, v := range params {
+		s += v
+		// LogQL doesn't allow `,` at the end of last argument.
+		if i < len(params)-1 {
+			s += ","
+		}
+		s += "\n"
+	}
+
+	s += Indent(level) + ")"
+
+	return s
+}
+
+// e.g: histogram_quantile(0.99, sum by (le) (rate({job="api-server"} | unwrap latency [5m])))
+func (e *HistogramQuantileExpr) Pretty(level int) string {
+	s := Indent(level)
+
+	if !NeedSplit(e) {
+		return s + e.String()
+	}
+
+	s += OpHistogramQuantile
+
+	s += "(\n"
+
+	s += Indent(level+1) + strconv.FormatFloat(e.Quantile, 'f', -1, 64) + ",\n"
+
+	s += e.Left.Pretty(level + 1)
+
+	s += "\n" + Indent(level) + ")"
+
+	return s
+}
+
 // e.g: vector(5)
 func (e *VectorExpr) Pretty(level int) string {
 	return commonPrefixIndent(level, e)
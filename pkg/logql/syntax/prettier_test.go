@@ -200,6 +200,105 @@ func TestFormat_LabelReplace(t *testing.T) {
 	}
 }
 
+func TestFormat_Subquery(t *testing.T) {
+	MaxCharsPerLine = 20
+
+	cases := []struct {
+		name string
+		in   string
+		exp  string
+	}{
+		{
+			name: "subquery",
+			in:   `max_over_time(rate({job="api-server",service="a:c"}|= "err" [5m])[1h:1m] offset 5m)`,
+			exp: `max_over_time(
+  rate(
+    {job="api-server", service="a:c"}
+      |= "err" [5m]
+  )[1h:1m] offset 5m
+)`,
+		},
+		{
+			name: "subquery_quantile",
+			in:   `quantile_over_time(0.99, sum by (foo) (rate({job="api-server",service="a:c"}|= "err" [5m]))[1h:])`,
+			exp: `quantile_over_time(
+  0.99,
+  sum by (foo)(
+    rate(
+      {job="api-server", service="a:c"}
+        |= "err" [5m]
+    )
+  )[1h:]
+)`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			expr, err := ParseExpr(c.in)
+			require.NoError(t, err)
+			got := Prettify(expr)
+			assert.Equal(t, c.exp, got)
+		})
+	}
+}
+
+func TestFormat_Functions(t *testing.T) {
+	MaxCharsPerLine = 20
+
+	cases := []struct {
+		name string
+		in   string
+		exp  string
+	}{
+		{
+			name: "round",
+			in:   `round(rate({job="api-server",service="a:c"}|= "err" [5m]), 0.5)`,
+			exp: `round(
+  rate(
+    {job="api-server", service="a:c"}
+      |= "err" [5m]
+  ),
+  0.5
+)`,
+		},
+		{
+			name: "label_join",
+			in:   `label_join(rate({job="api-server",service="a:c"}|= "err" [5m]), "foo", ",", "job", "service")`,
+			exp: `label_join(
+  rate(
+    {job="api-server", service="a:c"}
+      |= "err" [5m]
+  ),
+  "foo",
+  ",",
+  "job",
+  "service"
+)`,
+		},
+		{
+			name: "histogram_quantile",
+			in:   `histogram_quantile(0.99, rate({job="api-server",service="a:c"}|= "err" [5m]))`,
+			exp: `histogram_quantile(
+  0.99,
+  rate(
+    {job="api-server", service="a:c"}
+      |= "err" [5m]
+  )
+)`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			expr, err := ParseExpr(c.in)
+			require.NoError(t, err)
+			got := Prettify(expr)
+			assert.Equal(t, c.exp, got)
+		})
+	}
+}
+
 func TestFormat_BinOp(t *testing.T) {
 	MaxCharsPerLine = 20
 
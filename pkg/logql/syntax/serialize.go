@@ -38,14 +38,17 @@ const (
 	Card                = "cardinality"
 	Dst                 = "dst"
 	Duration            = "duration"
+	Function            = "function"
 	Groups              = "groups"
 	GroupingField       = "grouping"
+	HistogramQuantile   = "histogram_quantile"
 	Include             = "include"
 	Identifier          = "identifier"
 	Inner               = "inner"
 	IntervalNanos       = "interval_nanos"
 	IPField             = "ip"
 	Label               = "label"
+	LabelJoin           = "label_join"
 	LabelReplace        = "label_replace"
 	LHS                 = "lhs"
 	Literal             = "literal"
@@ -60,6 +63,7 @@ const (
 	Params              = "params"
 	Pattern             = "pattern"
 	PostFilterers       = "post_filterers"
+	Quantile            = "quantile"
 	Range               = "range"
 	RangeAgg            = "range_agg"
 	Raw                 = "raw"
@@ -67,8 +71,11 @@ const (
 	Replacement         = "replacement"
 	ReturnBool          = "return_bool"
 	RHS                 = "rhs"
+	Separator           = "separator"
 	Src                 = "src"
+	StepNanos           = "step_nanos"
 	StringField         = "string"
+	Subquery            = "subquery"
 	NoopField           = "noop"
 	Type                = "type"
 	Unwrap              = "unwrap"
@@ -90,12 +97,20 @@ func DecodeJSON(raw string) (Expr, error) {
 		return decodeVectorAgg(iter)
 	case RangeAgg:
 		return decodeRangeAgg(iter)
+	case Subquery:
+		return decodeSubquery(iter)
 	case Literal:
 		return decodeLiteral(iter)
 	case Vector:
 		return decodeVector(iter)
 	case LabelReplace:
 		return decodeLabelReplace(iter)
+	case Function:
+		return decodeFunction(iter)
+	case LabelJoin:
+		return decodeLabelJoin(iter)
+	case HistogramQuantile:
+		return decodeHistogramQuantile(iter)
 	case LogSelector:
 		return decodeLogSelector(iter)
 	default:
@@ -204,6 +219,40 @@ func (v *JSONSerializer) VisitRangeAggregation(e *RangeAggregationExpr) {
 	v.Flush()
 }
 
+func (v *JSONSerializer) VisitSubquery(e *SubqueryExpr) {
+	v.WriteObjectStart()
+
+	v.WriteObjectField(Subquery)
+	v.WriteObjectStart()
+
+	v.WriteObjectField(Op)
+	v.WriteString(e.Operation)
+
+	if e.Params != nil {
+		v.WriteMore()
+		v.WriteObjectField(Params)
+		v.WriteFloat64(*e.Params)
+	}
+
+	v.WriteMore()
+	v.WriteObjectField(IntervalNanos)
+	v.WriteInt64(int64(e.Range))
+	v.WriteMore()
+	v.WriteObjectField(StepNanos)
+	v.WriteInt64(int64(e.Step))
+	v.WriteMore()
+	v.WriteObjectField(OffsetNanos)
+	v.WriteInt64(int64(e.Offset))
+
+	v.WriteMore()
+	v.WriteObjectField(Inner)
+	e.Left.Accept(v)
+
+	v.WriteObjectEnd()
+	v.WriteObjectEnd()
+	v.Flush()
+}
+
 func (v *JSONSerializer) VisitLogRange(e *LogRange) {
 	v.WriteObjectStart()
 
@@ -228,6 +277,81 @@ func (v *JSONSerializer) VisitLogRange(e *LogRange) {
 	v.Flush()
 }
 
+func (v *JSONSerializer) VisitFunction(e *FunctionExpr) {
+	v.WriteObjectStart()
+
+	v.WriteObjectField(Function)
+	v.WriteObjectStart()
+
+	v.WriteObjectField(Op)
+	v.WriteString(e.Operation)
+
+	if e.Params != nil {
+		v.WriteMore()
+		v.WriteObjectField(Params)
+		v.WriteFloat64(*e.Params)
+	}
+
+	v.WriteMore()
+	v.WriteObjectField(Inner)
+	e.Left.Accept(v)
+
+	v.WriteObjectEnd()
+	v.WriteObjectEnd()
+	v.Flush()
+}
+
+func (v *JSONSerializer) VisitLabelJoin(e *LabelJoinExpr) {
+	v.WriteObjectStart()
+
+	v.WriteObjectField(LabelJoin)
+	v.WriteObjectStart()
+
+	v.WriteObjectField(Inner)
+	e.Left.Accept(v)
+
+	v.WriteMore()
+	v.WriteObjectField(Dst)
+	v.WriteString(e.Dst)
+
+	v.WriteMore()
+	v.WriteObjectField(Separator)
+	v.WriteString(e.Separator)
+
+	v.WriteMore()
+	v.WriteObjectField(Src)
+	v.WriteArrayStart()
+	for i, s := range e.Src {
+		if i > 0 {
+			v.WriteMore()
+		}
+		v.WriteString(s)
+	}
+	v.WriteArrayEnd()
+
+	v.WriteObjectEnd()
+	v.WriteObjectEnd()
+	v.Flush()
+}
+
+func (v *JSONSerializer) VisitHistogramQuantile(e *HistogramQuantileExpr) {
+	v.WriteObjectStart()
+
+	v.WriteObjectField(HistogramQuantile)
+	v.WriteObjectStart()
+
+	v.WriteObjectField(Quantile)
+	v.WriteFloat64(e.Quantile)
+
+	v.WriteMore()
+	v.WriteObjectField(Inner)
+	e.Left.Accept(v)
+
+	v.WriteObjectEnd()
+	v.WriteObjectEnd()
+	v.Flush()
+}
+
 func (v *JSONSerializer) VisitLabelReplace(e *LabelReplaceExpr) {
 	v.WriteObjectStart()
 
@@ -306,17 +430,23 @@ func (v *JSONSerializer) VisitPipeline(e *PipelineExpr) {
 
 // Below are StageExpr visitors that we are skipping since a pipeline is
 // serialized as a string.
-func (*JSONSerializer) VisitDecolorize(*DecolorizeExpr)                     {}
-func (*JSONSerializer) VisitDropLabels(*DropLabelsExpr)                     {}
-func (*JSONSerializer) VisitJSONExpressionParser(*JSONExpressionParser)     {}
-func (*JSONSerializer) VisitKeepLabel(*KeepLabelsExpr)                      {}
-func (*JSONSerializer) VisitLabelFilter(*LabelFilterExpr)                   {}
-func (*JSONSerializer) VisitLabelFmt(*LabelFmtExpr)                         {}
-func (*JSONSerializer) VisitLabelParser(*LabelParserExpr)                   {}
-func (*JSONSerializer) VisitLineFilter(*LineFilterExpr)                     {}
-func (*JSONSerializer) VisitLineFmt(*LineFmtExpr)                           {}
-func (*JSONSerializer) VisitLogfmtExpressionParser(*LogfmtExpressionParser) {}
-func (*JSONSerializer) VisitLogfmtParser(*LogfmtParserExpr)                 {}
+func (*JSONSerializer) VisitCSVParser(*CSVParserExpr)                               {}
+func (*JSONSerializer) VisitDecolorize(*DecolorizeExpr)                             {}
+func (*JSONSerializer) VisitDedup(*DedupExpr)                                       {}
+func (*JSONSerializer) VisitDropLabels(*DropLabelsExpr)                             {}
+func (*JSONSerializer) VisitJSONExpressionParser(*JSONExpressionParser)             {}
+func (*JSONSerializer) VisitKVParser(*KVParserExpr)                                 {}
+func (*JSONSerializer) VisitKeepLabel(*KeepLabelsExpr)                              {}
+func (*JSONSerializer) VisitLabelFilter(*LabelFilterExpr)                           {}
+func (*JSONSerializer) VisitLabelFmt(*LabelFmtExpr)                                 {}
+func (*JSONSerializer) VisitLabelParser(*LabelParserExpr)                           {}
+func (*JSONSerializer) VisitLineFilter(*LineFilterExpr)                             {}
+func (*JSONSerializer) VisitLineFmt(*LineFmtExpr)                                   {}
+func (*JSONSerializer) VisitLineLimit(*LineLimitExpr)                               {}
+func (*JSONSerializer) VisitLogfmtExpressionParser(*LogfmtExpressionParser)         {}
+func (*JSONSerializer) VisitLogfmtParser(*LogfmtParserExpr)                         {}
+func (*JSONSerializer) VisitStructuredMetadataFilter(*StructuredMetadataFilterExpr) {}
+func (*JSONSerializer) VisitXMLParser(*XMLParserExpr)                               {}
 
 func encodeGrouping(s *jsoniter.Stream, g *Grouping) {
 	s.WriteObjectStart()
@@ -711,12 +841,20 @@ func decodeSample(iter *jsoniter.Iterator) (SampleExpr, error) {
 			expr, err = decodeVectorAgg(iter)
 		case RangeAgg:
 			expr, err = decodeRangeAgg(iter)
+		case Subquery:
+			expr, err = decodeSubquery(iter)
 		case Literal:
 			expr, err = decodeLiteral(iter)
 		case Vector:
 			expr, err = decodeVector(iter)
 		case LabelReplace:
 			expr, err = decodeLabelReplace(iter)
+		case Function:
+			expr, err = decodeFunction(iter)
+		case LabelJoin:
+			expr, err = decodeLabelJoin(iter)
+		case HistogramQuantile:
+			expr, err = decodeHistogramQuantile(iter)
 		default:
 			return nil, fmt.Errorf("unknown sample expression type: %s", key)
 		}
@@ -858,6 +996,31 @@ func decodeRangeAgg(iter *jsoniter.Iterator) (*RangeAggregationExpr, error) {
 	return expr, err
 }
 
+func decodeSubquery(iter *jsoniter.Iterator) (*SubqueryExpr, error) {
+	expr := &SubqueryExpr{}
+	var err error
+
+	for f := iter.ReadObject(); f != ""; f = iter.ReadObject() {
+		switch f {
+		case Op:
+			expr.Operation = iter.ReadString()
+		case Params:
+			tmp := iter.ReadFloat64()
+			expr.Params = &tmp
+		case IntervalNanos:
+			expr.Range = time.Duration(iter.ReadInt64())
+		case StepNanos:
+			expr.Step = time.Duration(iter.ReadInt64())
+		case OffsetNanos:
+			expr.Offset = time.Duration(iter.ReadInt64())
+		case Inner:
+			expr.Left, err = decodeSample(iter)
+		}
+	}
+
+	return expr, err
+}
+
 func decodeLogRange(iter *jsoniter.Iterator) (*LogRange, error) {
 	expr := &LogRange{}
 	var err error
@@ -904,6 +1067,64 @@ func decodeLabelReplace(iter *jsoniter.Iterator) (*LabelReplaceExpr, error) {
 	return mustNewLabelReplaceExpr(left, dst, replacement, src, regex), nil
 }
 
+func decodeFunction(iter *jsoniter.Iterator) (*FunctionExpr, error) {
+	expr := &FunctionExpr{}
+	var err error
+
+	for f := iter.ReadObject(); f != ""; f = iter.ReadObject() {
+		switch f {
+		case Op:
+			expr.Operation = iter.ReadString()
+		case Params:
+			tmp := iter.ReadFloat64()
+			expr.Params = &tmp
+		case Inner:
+			expr.Left, err = decodeSample(iter)
+		}
+	}
+
+	return expr, err
+}
+
+func decodeLabelJoin(iter *jsoniter.Iterator) (*LabelJoinExpr, error) {
+	expr := &LabelJoinExpr{}
+	var err error
+
+	for f := iter.ReadObject(); f != ""; f = iter.ReadObject() {
+		switch f {
+		case Inner:
+			expr.Left, err = decodeSample(iter)
+		case Dst:
+			expr.Dst = iter.ReadString()
+		case Separator:
+			expr.Separator = iter.ReadString()
+		case Src:
+			iter.ReadArrayCB(func(i *jsoniter.Iterator) bool {
+				expr.Src = append(expr.Src, i.ReadString())
+				return true
+			})
+		}
+	}
+
+	return expr, err
+}
+
+func decodeHistogramQuantile(iter *jsoniter.Iterator) (*HistogramQuantileExpr, error) {
+	expr := &HistogramQuantileExpr{}
+	var err error
+
+	for f := iter.ReadObject(); f != ""; f = iter.ReadObject() {
+		switch f {
+		case Quantile:
+			expr.Quantile = iter.ReadFloat64()
+		case Inner:
+			expr.Left, err = decodeSample(iter)
+		}
+	}
+
+	return expr, err
+}
+
 func decodeLiteral(iter *jsoniter.Iterator) (*LiteralExpr, error) {
 	expr := &LiteralExpr{}
 
@@ -16,7 +16,11 @@ type SampleExprVisitor interface {
 	VisitBinOp(*BinOpExpr)
 	VisitVectorAggregation(*VectorAggregationExpr)
 	VisitRangeAggregation(*RangeAggregationExpr)
+	VisitSubquery(*SubqueryExpr)
 	VisitLabelReplace(*LabelReplaceExpr)
+	VisitFunction(*FunctionExpr)
+	VisitLabelJoin(*LabelJoinExpr)
+	VisitHistogramQuantile(*HistogramQuantileExpr)
 	VisitLiteral(*LiteralExpr)
 	VisitVector(*VectorExpr)
 }
@@ -29,42 +33,58 @@ type LogSelectorExprVisitor interface {
 }
 
 type StageExprVisitor interface {
+	VisitCSVParser(*CSVParserExpr)
 	VisitDecolorize(*DecolorizeExpr)
+	VisitDedup(*DedupExpr)
 	VisitDropLabels(*DropLabelsExpr)
 	VisitJSONExpressionParser(*JSONExpressionParser)
 	VisitKeepLabel(*KeepLabelsExpr)
+	VisitKVParser(*KVParserExpr)
 	VisitLabelFilter(*LabelFilterExpr)
 	VisitLabelFmt(*LabelFmtExpr)
 	VisitLabelParser(*LabelParserExpr)
 	VisitLineFilter(*LineFilterExpr)
 	VisitLineFmt(*LineFmtExpr)
+	VisitLineLimit(*LineLimitExpr)
 	VisitLogfmtExpressionParser(*LogfmtExpressionParser)
 	VisitLogfmtParser(*LogfmtParserExpr)
+	VisitStructuredMetadataFilter(*StructuredMetadataFilterExpr)
+	VisitXMLParser(*XMLParserExpr)
 }
 
 var _ RootVisitor = &DepthFirstTraversal{}
 
 type DepthFirstTraversal struct {
-	VisitBinOpFn                  func(v RootVisitor, e *BinOpExpr)
-	VisitDecolorizeFn             func(v RootVisitor, e *DecolorizeExpr)
-	VisitDropLabelsFn             func(v RootVisitor, e *DropLabelsExpr)
-	VisitJSONExpressionParserFn   func(v RootVisitor, e *JSONExpressionParser)
-	VisitKeepLabelFn              func(v RootVisitor, e *KeepLabelsExpr)
-	VisitLabelFilterFn            func(v RootVisitor, e *LabelFilterExpr)
-	VisitLabelFmtFn               func(v RootVisitor, e *LabelFmtExpr)
-	VisitLabelParserFn            func(v RootVisitor, e *LabelParserExpr)
-	VisitLabelReplaceFn           func(v RootVisitor, e *LabelReplaceExpr)
-	VisitLineFilterFn             func(v RootVisitor, e *LineFilterExpr)
-	VisitLineFmtFn                func(v RootVisitor, e *LineFmtExpr)
-	VisitLiteralFn                func(v RootVisitor, e *LiteralExpr)
-	VisitLogRangeFn               func(v RootVisitor, e *LogRange)
-	VisitLogfmtExpressionParserFn func(v RootVisitor, e *LogfmtExpressionParser)
-	VisitLogfmtParserFn           func(v RootVisitor, e *LogfmtParserExpr)
-	VisitMatchersFn               func(v RootVisitor, e *MatchersExpr)
-	VisitPipelineFn               func(v RootVisitor, e *PipelineExpr)
-	VisitRangeAggregationFn       func(v RootVisitor, e *RangeAggregationExpr)
-	VisitVectorFn                 func(v RootVisitor, e *VectorExpr)
-	VisitVectorAggregationFn      func(v RootVisitor, e *VectorAggregationExpr)
+	VisitBinOpFn                    func(v RootVisitor, e *BinOpExpr)
+	VisitCSVParserFn                func(v RootVisitor, e *CSVParserExpr)
+	VisitDecolorizeFn               func(v RootVisitor, e *DecolorizeExpr)
+	VisitDedupFn                    func(v RootVisitor, e *DedupExpr)
+	VisitDropLabelsFn               func(v RootVisitor, e *DropLabelsExpr)
+	VisitJSONExpressionParserFn     func(v RootVisitor, e *JSONExpressionParser)
+	VisitKeepLabelFn                func(v RootVisitor, e *KeepLabelsExpr)
+	VisitKVParserFn                 func(v RootVisitor, e *KVParserExpr)
+	VisitLabelFilterFn              func(v RootVisitor, e *LabelFilterExpr)
+	VisitLabelFmtFn                 func(v RootVisitor, e *LabelFmtExpr)
+	VisitLabelParserFn              func(v RootVisitor, e *LabelParserExpr)
+	VisitLabelReplaceFn             func(v RootVisitor, e *LabelReplaceExpr)
+	VisitFunctionFn                 func(v RootVisitor, e *FunctionExpr)
+	VisitLabelJoinFn                func(v RootVisitor, e *LabelJoinExpr)
+	VisitHistogramQuantileFn        func(v RootVisitor, e *HistogramQuantileExpr)
+	VisitLineFilterFn               func(v RootVisitor, e *LineFilterExpr)
+	VisitLineFmtFn                  func(v RootVisitor, e *LineFmtExpr)
+	VisitLineLimitFn                func(v RootVisitor, e *LineLimitExpr)
+	VisitLiteralFn                  func(v RootVisitor, e *LiteralExpr)
+	VisitLogRangeFn                 func(v RootVisitor, e *LogRange)
+	VisitLogfmtExpressionParserFn   func(v RootVisitor, e *LogfmtExpressionParser)
+	VisitLogfmtParserFn             func(v RootVisitor, e *LogfmtParserExpr)
+	VisitMatchersFn                 func(v RootVisitor, e *MatchersExpr)
+	VisitPipelineFn                 func(v RootVisitor, e *PipelineExpr)
+	VisitRangeAggregationFn         func(v RootVisitor, e *RangeAggregationExpr)
+	VisitStructuredMetadataFilterFn func(v RootVisitor, e *StructuredMetadataFilterExpr)
+	VisitSubqueryFn                 func(v RootVisitor, e *SubqueryExpr)
+	VisitVectorFn                   func(v RootVisitor, e *VectorExpr)
+	VisitVectorAggregationFn        func(v RootVisitor, e *VectorAggregationExpr)
+	VisitXMLParserFn                func(v RootVisitor, e *XMLParserExpr)
 }
 
 // VisitBinOp implements RootVisitor.
@@ -80,6 +100,16 @@ func (v *DepthFirstTraversal) VisitBinOp(e *BinOpExpr) {
 	}
 }
 
+// VisitCSVParser implements RootVisitor.
+func (v *DepthFirstTraversal) VisitCSVParser(e *CSVParserExpr) {
+	if e == nil {
+		return
+	}
+	if v.VisitCSVParserFn != nil {
+		v.VisitCSVParserFn(v, e)
+	}
+}
+
 // VisitDecolorize implements RootVisitor.
 func (v *DepthFirstTraversal) VisitDecolorize(e *DecolorizeExpr) {
 	if e == nil {
@@ -90,6 +120,16 @@ func (v *DepthFirstTraversal) VisitDecolorize(e *DecolorizeExpr) {
 	}
 }
 
+// VisitDedup implements RootVisitor.
+func (v *DepthFirstTraversal) VisitDedup(e *DedupExpr) {
+	if e == nil {
+		return
+	}
+	if v.VisitDedupFn != nil {
+		v.VisitDedupFn(v, e)
+	}
+}
+
 // VisitDropLabels implements RootVisitor.
 func (v *DepthFirstTraversal) VisitDropLabels(e *DropLabelsExpr) {
 	if e == nil {
@@ -120,6 +160,16 @@ func (v *DepthFirstTraversal) VisitKeepLabel(e *KeepLabelsExpr) {
 	}
 }
 
+// VisitKVParser implements RootVisitor.
+func (v *DepthFirstTraversal) VisitKVParser(e *KVParserExpr) {
+	if e == nil {
+		return
+	}
+	if v.VisitKVParserFn != nil {
+		v.VisitKVParserFn(v, e)
+	}
+}
+
 // VisitLabelFilter implements RootVisitor.
 func (v *DepthFirstTraversal) VisitLabelFilter(e *LabelFilterExpr) {
 	if e == nil {
@@ -160,6 +210,42 @@ func (v *DepthFirstTraversal) VisitLabelReplace(e *LabelReplaceExpr) {
 	}
 }
 
+// VisitFunction implements RootVisitor.
+func (v *DepthFirstTraversal) VisitFunction(e *FunctionExpr) {
+	if e == nil {
+		return
+	}
+	if v.VisitFunctionFn != nil {
+		v.VisitFunctionFn(v, e)
+	} else {
+		e.Left.Accept(v)
+	}
+}
+
+// VisitLabelJoin implements RootVisitor.
+func (v *DepthFirstTraversal) VisitLabelJoin(e *LabelJoinExpr) {
+	if e == nil {
+		return
+	}
+	if v.VisitLabelJoinFn != nil {
+		v.VisitLabelJoinFn(v, e)
+	} else {
+		e.Left.Accept(v)
+	}
+}
+
+// VisitHistogramQuantile implements RootVisitor.
+func (v *DepthFirstTraversal) VisitHistogramQuantile(e *HistogramQuantileExpr) {
+	if e == nil {
+		return
+	}
+	if v.VisitHistogramQuantileFn != nil {
+		v.VisitHistogramQuantileFn(v, e)
+	} else {
+		e.Left.Accept(v)
+	}
+}
+
 // VisitLineFilter implements RootVisitor.
 func (v *DepthFirstTraversal) VisitLineFilter(e *LineFilterExpr) {
 	if e == nil {
@@ -187,6 +273,16 @@ func (v *DepthFirstTraversal) VisitLineFmt(e *LineFmtExpr) {
 	}
 }
 
+// VisitLineLimit implements RootVisitor.
+func (v *DepthFirstTraversal) VisitLineLimit(e *LineLimitExpr) {
+	if e == nil {
+		return
+	}
+	if v.VisitLineLimitFn != nil {
+		v.VisitLineLimitFn(v, e)
+	}
+}
+
 // VisitLiteral implements RootVisitor.
 func (v *DepthFirstTraversal) VisitLiteral(e *LiteralExpr) {
 	if e == nil {
@@ -266,6 +362,28 @@ func (v *DepthFirstTraversal) VisitRangeAggregation(e *RangeAggregationExpr) {
 	}
 }
 
+// VisitStructuredMetadataFilter implements RootVisitor.
+func (v *DepthFirstTraversal) VisitStructuredMetadataFilter(e *StructuredMetadataFilterExpr) {
+	if e == nil {
+		return
+	}
+	if v.VisitStructuredMetadataFilterFn != nil {
+		v.VisitStructuredMetadataFilterFn(v, e)
+	}
+}
+
+// VisitSubquery implements RootVisitor.
+func (v *DepthFirstTraversal) VisitSubquery(e *SubqueryExpr) {
+	if e == nil {
+		return
+	}
+	if v.VisitSubqueryFn != nil {
+		v.VisitSubqueryFn(v, e)
+	} else {
+		e.Left.Accept(v)
+	}
+}
+
 // VisitVector implements RootVisitor.
 func (v *DepthFirstTraversal) VisitVector(e *VectorExpr) {
 	if e == nil {
@@ -287,3 +405,13 @@ func (v *DepthFirstTraversal) VisitVectorAggregation(e *VectorAggregationExpr) {
 		e.Left.Accept(v)
 	}
 }
+
+// VisitXMLParser implements RootVisitor.
+func (v *DepthFirstTraversal) VisitXMLParser(e *XMLParserExpr) {
+	if e == nil {
+		return
+	}
+	if v.VisitXMLParserFn != nil {
+		v.VisitXMLParserFn(v, e)
+	}
+}
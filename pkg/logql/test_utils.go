@@ -17,6 +17,7 @@ import (
 	"github.com/grafana/loki/pkg/iter"
 	"github.com/grafana/loki/pkg/logproto"
 	"github.com/grafana/loki/pkg/logql/log"
+	"github.com/grafana/loki/pkg/logql/sketch"
 	"github.com/grafana/loki/pkg/logqlmodel"
 	"github.com/grafana/loki/pkg/querier/astmapper"
 )
@@ -247,6 +248,18 @@ func (m MockDownstreamer) Downstream(ctx context.Context, queries []DownstreamQu
 		}
 		return []logqlmodel.Result{{Data: matrix}}, nil
 	}
+	if matrix, ok := results[0].Data.(ProbabilisticCountDistinctMatrix); ok {
+		for _, m := range results[1:] {
+			matrix, _ = matrix.Merge(m.Data.(ProbabilisticCountDistinctMatrix))
+		}
+		return []logqlmodel.Result{{Data: matrix}}, nil
+	}
+	if matrix, ok := results[0].Data.(sketch.TopKMatrix); ok {
+		for _, m := range results[1:] {
+			matrix, _ = matrix.Merge(m.Data.(sketch.TopKMatrix))
+		}
+		return []logqlmodel.Result{{Data: matrix}}, nil
+	}
 	return results, nil
 }
 
@@ -659,6 +659,13 @@ func applyEmbeddedCacheConfig(r *ConfigWrapper) {
 		r.QueryRange.InstantMetricCacheConfig.CacheConfig = r.QueryRange.ResultsCacheConfig.CacheConfig
 		r.QueryRange.InstantMetricCacheConfig.CacheConfig.Prefix = prefix
 	}
+
+	detectedFieldsCacheConfig := r.QueryRange.DetectedFieldsCacheConfig.CacheConfig
+	if !cache.IsCacheConfigured(detectedFieldsCacheConfig) {
+		prefix := detectedFieldsCacheConfig.Prefix
+		r.QueryRange.DetectedFieldsCacheConfig.CacheConfig = r.QueryRange.ResultsCacheConfig.CacheConfig
+		r.QueryRange.DetectedFieldsCacheConfig.CacheConfig.Prefix = prefix
+	}
 }
 
 func applyIngesterFinalSleep(cfg *ConfigWrapper) {
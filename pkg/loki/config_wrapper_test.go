@@ -1143,6 +1143,35 @@ query_range:
 			assert.False(t, config.QueryRange.LabelsCacheConfig.CacheConfig.EmbeddedCache.Enabled)
 		})
 	})
+
+	t.Run("for the detected fields results cache config", func(t *testing.T) {
+		t.Run("no embedded cache enabled by default if Redis is set", func(t *testing.T) {
+			configFileString := `---
+query_range:
+  detected_fields_results_cache:
+    cache:
+      redis:
+        endpoint: endpoint.redis.org`
+
+			config, _, _ := configWrapperFromYAML(t, configFileString, nil)
+			assert.EqualValues(t, "endpoint.redis.org", config.QueryRange.DetectedFieldsCacheConfig.CacheConfig.Redis.Endpoint)
+			assert.EqualValues(t, "frontend.detected-fields-results-cache.", config.QueryRange.DetectedFieldsCacheConfig.CacheConfig.Prefix)
+			assert.False(t, config.QueryRange.DetectedFieldsCacheConfig.CacheConfig.EmbeddedCache.Enabled)
+		})
+
+		t.Run("embedded cache is enabled by default if no other cache is set", func(t *testing.T) {
+			config, _, _ := configWrapperFromYAML(t, minimalConfig, nil)
+			assert.True(t, config.QueryRange.DetectedFieldsCacheConfig.CacheConfig.EmbeddedCache.Enabled)
+			assert.EqualValues(t, "frontend.detected-fields-results-cache.", config.QueryRange.DetectedFieldsCacheConfig.CacheConfig.Prefix)
+		})
+
+		t.Run("gets results cache config if not configured directly", func(t *testing.T) {
+			config, _, _ := configWrapperFromYAML(t, defaultResulsCacheString, nil)
+			assert.EqualValues(t, "memcached.host.org", config.QueryRange.DetectedFieldsCacheConfig.CacheConfig.MemcacheClient.Host)
+			assert.EqualValues(t, "frontend.detected-fields-results-cache.", config.QueryRange.DetectedFieldsCacheConfig.CacheConfig.Prefix)
+			assert.False(t, config.QueryRange.DetectedFieldsCacheConfig.CacheConfig.EmbeddedCache.Enabled)
+		})
+	})
 }
 
 func TestDefaultUnmarshal(t *testing.T) {
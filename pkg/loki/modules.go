@@ -45,6 +45,7 @@ import (
 	"github.com/grafana/loki/pkg/compactor/generationnumber"
 	"github.com/grafana/loki/pkg/distributor"
 	"github.com/grafana/loki/pkg/ingester"
+	"github.com/grafana/loki/pkg/loghttp/push"
 	"github.com/grafana/loki/pkg/logproto"
 	"github.com/grafana/loki/pkg/logql"
 	"github.com/grafana/loki/pkg/lokifrontend/frontend"
@@ -348,6 +349,9 @@ func (t *Loki) initDistributor() (services.Service, error) {
 
 	lokiPushHandler := httpPushHandlerMiddleware.Wrap(http.HandlerFunc(t.distributor.PushHandler))
 	otlpPushHandler := httpPushHandlerMiddleware.Wrap(http.HandlerFunc(t.distributor.OTLPPushHandler))
+	elasticsearchBulkHandler := httpPushHandlerMiddleware.Wrap(http.HandlerFunc(t.distributor.ElasticsearchBulkHandler))
+	splunkHECEventHandler := httpPushHandlerMiddleware.Wrap(http.HandlerFunc(t.distributor.SplunkHECEventHandler))
+	splunkHECRawHandler := httpPushHandlerMiddleware.Wrap(http.HandlerFunc(t.distributor.SplunkHECRawHandler))
 
 	t.Server.HTTP.Path("/distributor/ring").Methods("GET", "POST").Handler(t.distributor)
 
@@ -358,6 +362,17 @@ func (t *Loki) initDistributor() (services.Service, error) {
 	t.Server.HTTP.Path("/api/prom/push").Methods("POST").Handler(lokiPushHandler)
 	t.Server.HTTP.Path("/loki/api/v1/push").Methods("POST").Handler(lokiPushHandler)
 	t.Server.HTTP.Path("/otlp/v1/logs").Methods("POST").Handler(otlpPushHandler)
+
+	// Endpoints compatible with the Elasticsearch bulk API and the Splunk HTTP Event Collector, for legacy shippers.
+	t.Server.HTTP.Path("/elasticsearch/").Methods("GET", "HEAD").Handler(httpPushHandlerMiddleware.Wrap(http.HandlerFunc(push.ElasticsearchInfoHandler)))
+	t.Server.HTTP.Path("/elasticsearch/_bulk").Methods("POST", "PUT").Handler(elasticsearchBulkHandler)
+	t.Server.HTTP.Path("/elasticsearch/{index}/_bulk").Methods("POST", "PUT").Handler(elasticsearchBulkHandler)
+	t.Server.HTTP.Path("/services/collector").Methods("POST").Handler(splunkHECEventHandler)
+	t.Server.HTTP.Path("/services/collector/event").Methods("POST").Handler(splunkHECEventHandler)
+	t.Server.HTTP.Path("/services/collector/event/1.0").Methods("POST").Handler(splunkHECEventHandler)
+	t.Server.HTTP.Path("/services/collector/raw").Methods("POST").Handler(splunkHECRawHandler)
+	t.Server.HTTP.Path("/services/collector/raw/1.0").Methods("POST").Handler(splunkHECRawHandler)
+	t.Server.HTTP.Path("/services/collector/health").Methods("GET").Handler(http.HandlerFunc(push.SplunkHECHealthHandler))
 	return t.distributor, nil
 }
 
@@ -500,6 +515,18 @@ func (t *Loki) initQuerier() (services.Service, error) {
 		router.Path("/loki/api/v1/index/shards").Methods("GET", "POST").Handler(indexShardsHTTPMiddleware.Wrap(httpHandler))
 		router.Path("/loki/api/v1/index/volume").Methods("GET", "POST").Handler(volumeHTTPMiddleware.Wrap(httpHandler))
 		router.Path("/loki/api/v1/index/volume_range").Methods("GET", "POST").Handler(volumeRangeHTTPMiddleware.Wrap(httpHandler))
+		router.Path("/loki/api/v1/patterns").Methods("GET", "POST").Handler(
+			middleware.Merge(
+				httpMiddleware,
+				querier.WrapQuerySpanAndTimeout("query.Patterns", t.Overrides),
+			).Wrap(httpHandler),
+		)
+		router.Path("/loki/api/v1/detected_fields").Methods("GET", "POST").Handler(
+			middleware.Merge(
+				httpMiddleware,
+				querier.WrapQuerySpanAndTimeout("query.DetectedFields", t.Overrides),
+			).Wrap(httpHandler),
+		)
 
 		router.Path("/api/prom/query").Methods("GET", "POST").Handler(
 			middleware.Merge(
@@ -977,7 +1004,7 @@ func (t *Loki) initQueryFrontend() (_ services.Service, err error) {
 		level.Debug(util_log.Logger).Log("msg", "no query frontend configured")
 	}
 
-	roundTripper := queryrange.NewSerializeRoundTripper(t.QueryFrontEndMiddleware.Wrap(frontendTripper), queryrange.DefaultCodec)
+	roundTripper := queryrange.NewSerializeRoundTripper(t.QueryFrontEndMiddleware.Wrap(frontendTripper), queryrange.DefaultCodec, t.Overrides)
 
 	frontendHandler := transport.NewHandler(t.Cfg.Frontend.Handler, roundTripper, util_log.Logger, prometheus.DefaultRegisterer, t.Cfg.MetricsNamespace)
 	if t.Cfg.Frontend.CompressResponses {
@@ -1044,6 +1071,8 @@ func (t *Loki) initQueryFrontend() (_ services.Service, err error) {
 	t.Server.HTTP.Path("/loki/api/v1/index/shards").Methods("GET", "POST").Handler(frontendHandler)
 	t.Server.HTTP.Path("/loki/api/v1/index/volume").Methods("GET", "POST").Handler(frontendHandler)
 	t.Server.HTTP.Path("/loki/api/v1/index/volume_range").Methods("GET", "POST").Handler(frontendHandler)
+	t.Server.HTTP.Path("/loki/api/v1/patterns").Methods("GET", "POST").Handler(frontendHandler)
+	t.Server.HTTP.Path("/loki/api/v1/detected_fields").Methods("GET", "POST").Handler(frontendHandler)
 	t.Server.HTTP.Path("/api/prom/query").Methods("GET", "POST").Handler(frontendHandler)
 	t.Server.HTTP.Path("/api/prom/label").Methods("GET", "POST").Handler(frontendHandler)
 	t.Server.HTTP.Path("/api/prom/label/{name}/values").Methods("GET", "POST").Handler(frontendHandler)
@@ -23,6 +23,7 @@ import (
 
 	"github.com/grafana/dskit/tenant"
 
+	"github.com/grafana/loki/pkg/querier/queryrange"
 	"github.com/grafana/loki/pkg/querier/queryrange/queryrangebase"
 	querier_stats "github.com/grafana/loki/pkg/querier/stats"
 	"github.com/grafana/loki/pkg/util"
@@ -141,6 +142,9 @@ func (f *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		server.WriteError(err, w)
 		return
 	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
 
 	hs := w.Header()
 	for h, vs := range resp.Header {
@@ -153,7 +157,11 @@ func (f *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	w.WriteHeader(resp.StatusCode)
 	// we don't check for copy error as there is no much we can do at this point
-	_, _ = io.Copy(w, resp.Body)
+	if resp.Header.Get("Content-Type") == queryrange.NDJSONType {
+		_, _ = copyAndFlush(w, resp.Body)
+	} else {
+		_, _ = io.Copy(w, resp.Body)
+	}
 
 	// Check whether we should parse the query string.
 	shouldReportSlowQuery := f.cfg.LogQueriesLongerThan > 0 && queryResponseTime > f.cfg.LogQueriesLongerThan
@@ -169,6 +177,34 @@ func (f *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// copyAndFlush copies a streamed response body, flushing every chunk to the client as soon as it is read.
+func copyAndFlush(w http.ResponseWriter, body io.Reader) (int64, error) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return io.Copy(w, body)
+	}
+
+	var written int64
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := body.Read(buf)
+		if n > 0 {
+			nw, werr := w.Write(buf[:n])
+			written += int64(nw)
+			if werr != nil {
+				return written, werr
+			}
+			flusher.Flush()
+		}
+		if err == io.EOF {
+			return written, nil
+		}
+		if err != nil {
+			return written, err
+		}
+	}
+}
+
 // reportSlowQuery reports slow queries.
 func (f *Handler) reportSlowQuery(r *http.Request, queryString url.Values, queryResponseTime time.Duration) {
 	logMessage := append([]interface{}{
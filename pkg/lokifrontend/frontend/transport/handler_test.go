@@ -1,10 +1,16 @@
 package transport
 
 import (
+	"io"
 	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
+	"github.com/go-kit/log"
 	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/pkg/querier/queryrange"
 )
 
 func TestFormatRequestHeaders(t *testing.T) {
@@ -21,3 +27,39 @@ func TestFormatRequestHeaders(t *testing.T) {
 
 	require.Equal(t, expected, fields)
 }
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
+func TestHandler_StreamsNDJSON(t *testing.T) {
+	body := "{\"stream\":{\"app\":\"foo\"},\"values\":[[\"1\",\"line\"]]}\n"
+	closed := false
+	rt := roundTripperFunc(func(_ *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": []string{queryrange.NDJSONType}},
+			Body:       &closeRecorder{Reader: strings.NewReader(body), closed: &closed},
+		}, nil
+	})
+	handler := NewHandler(HandlerConfig{MaxBodySize: 1024}, rt, log.NewNopLogger(), nil, "loki")
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/loki/api/v1/query_range", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, queryrange.NDJSONType, w.Header().Get("Content-Type"))
+	require.Equal(t, body, w.Body.String())
+	require.True(t, w.Flushed)
+	require.True(t, closed)
+}
+
+type closeRecorder struct {
+	io.Reader
+	closed *bool
+}
+
+func (c *closeRecorder) Close() error {
+	*c.closed = true
+	return nil
+}
@@ -0,0 +1,41 @@
+package drain
+
+import (
+	"errors"
+	"flag"
+	"time"
+)
+
+// Config configures the detection of log patterns.
+type Config struct {
+	Enabled             bool          `yaml:"enabled"`
+	SimilarityThreshold float64       `yaml:"similarity_threshold"`
+	MaxClusters         int           `yaml:"max_clusters"`
+	SampleInterval      time.Duration `yaml:"sample_interval"`
+	MaxAge              time.Duration `yaml:"max_age"`
+}
+
+// RegisterFlagsWithPrefix registers the flags with the given prefix.
+func (cfg *Config) RegisterFlagsWithPrefix(prefix string, f *flag.FlagSet) {
+	f.BoolVar(&cfg.Enabled, prefix+"enabled", false, "Detect the patterns of the log lines of each stream, which can be queried with the /loki/api/v1/patterns endpoint.")
+	f.Float64Var(&cfg.SimilarityThreshold, prefix+"similarity-threshold", 0.3, "Ratio of equal tokens a log line and a pattern of the same length must share for the line to be merged into the pattern. Lower values produce fewer and more generic patterns.")
+	f.IntVar(&cfg.MaxClusters, prefix+"max-clusters", 300, "Maximum number of patterns kept per stream. When exceeded, the least recently seen pattern is evicted.")
+	f.DurationVar(&cfg.SampleInterval, prefix+"sample-interval", 10*time.Second, "Resolution at which the number of occurrences of each pattern is recorded.")
+	f.DurationVar(&cfg.MaxAge, prefix+"max-age", 3*time.Hour, "How long the occurrences of each pattern are kept in memory.")
+}
+
+func (cfg *Config) Validate() error {
+	if !cfg.Enabled {
+		return nil
+	}
+	if cfg.SimilarityThreshold <= 0 || cfg.SimilarityThreshold > 1 {
+		return errors.New("pattern similarity threshold must be in the range (0, 1]")
+	}
+	if cfg.MaxClusters <= 0 {
+		return errors.New("pattern max clusters must be greater than 0")
+	}
+	if cfg.SampleInterval <= 0 {
+		return errors.New("pattern sample interval must be greater than 0")
+	}
+	return nil
+}
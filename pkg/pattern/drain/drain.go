@@ -0,0 +1,291 @@
+// Package drain groups log lines into patterns with the Drain algorithm
+// described in "Drain: An Online Log Parsing Approach with Fixed Depth Tree"
+// by Pinjia He, Jieming Zhu, Zibin Zheng and Michael R. Lyu.
+//
+// Lines are split into tokens on spaces. Lines with the same number of tokens
+// and enough tokens in common are merged into a single cluster, whose pattern
+// replaces the differing tokens with the `<_>` placeholder of the LogQL pattern
+// parser.
+package drain
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/hashicorp/golang-lru/simplelru"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/loki/pkg/logproto"
+)
+
+const (
+	// Placeholder is the token replacing variable parts of a pattern.
+	// It is an unnamed capture of the LogQL pattern parser.
+	Placeholder = "<_>"
+
+	// treeDepth is the depth of the parse tree, made of the root, the number
+	// of tokens, the leading tokens and the leaves.
+	treeDepth = 4
+	// maxChildren is the maximum number of children of an inner node of the
+	// parse tree. Once reached, further tokens are routed to the placeholder.
+	maxChildren = 100
+)
+
+// captureRegexp matches the named and unnamed captures of the pattern parser,
+// which can't appear as literals in a pattern.
+var captureRegexp = regexp.MustCompile(`<[a-zA-Z_][a-zA-Z0-9_]*>|<_>`)
+
+var placeholderRegexp = regexp.MustCompile(regexp.QuoteMeta(Placeholder))
+
+// Drain clusters the log lines of a stream. It is not safe for concurrent use.
+type Drain struct {
+	cfg      *Config
+	root     *node
+	clusters *simplelru.LRU
+	nextID   int
+}
+
+type node struct {
+	children   map[string]*node
+	clusterIDs []int
+}
+
+func newNode() *node {
+	return &node{children: map[string]*node{}}
+}
+
+// New creates a new Drain using the given config.
+func New(cfg *Config) *Drain {
+	// the only possible error is a non-positive size.
+	clusters, _ := simplelru.NewLRU(max(cfg.MaxClusters, 1), nil)
+	return &Drain{
+		cfg:      cfg,
+		root:     newNode(),
+		clusters: clusters,
+	}
+}
+
+// Train adds the line at the given timestamp to the best matching cluster,
+// creating a new cluster if there is none.
+func (d *Drain) Train(line string, ts time.Time) *LogCluster {
+	tokens := tokenize(line)
+	leaf := d.leaf(tokens)
+
+	cluster := d.match(leaf, tokens)
+	if cluster == nil {
+		cluster = &LogCluster{
+			id:     d.nextID,
+			Tokens: tokens,
+		}
+		d.nextID++
+		leaf.clusterIDs = append(leaf.clusterIDs, cluster.id)
+		d.clusters.Add(cluster.id, cluster)
+	} else {
+		cluster.merge(tokens)
+		// mark the cluster as recently used.
+		d.clusters.Get(cluster.id)
+	}
+	cluster.append(ts, d.cfg.SampleInterval, d.cfg.MaxAge)
+	return cluster
+}
+
+// Clusters returns the clusters of the Drain, from the least to the most
+// recently used.
+func (d *Drain) Clusters() []*LogCluster {
+	keys := d.clusters.Keys()
+	clusters := make([]*LogCluster, 0, len(keys))
+	for _, k := range keys {
+		if c, ok := d.clusters.Peek(k); ok {
+			clusters = append(clusters, c.(*LogCluster))
+		}
+	}
+	return clusters
+}
+
+// leaf returns the leaf node of the parse tree for the tokens, creating the
+// missing nodes on the way.
+func (d *Drain) leaf(tokens []string) *node {
+	current := d.root
+	key := strconv.Itoa(len(tokens))
+	for depth := 0; ; depth++ {
+		next, ok := current.children[key]
+		if !ok {
+			if len(current.children) >= maxChildren {
+				key = Placeholder
+				next, ok = current.children[key]
+			}
+			if !ok {
+				next = newNode()
+				current.children[key] = next
+			}
+		}
+		current = next
+
+		if depth >= treeDepth-3 || depth >= len(tokens) {
+			return current
+		}
+		key = tokens[depth]
+		if hasDigit(key) {
+			key = Placeholder
+		}
+	}
+}
+
+// match returns the most similar cluster of the leaf, if its similarity
+// reaches the threshold. Evicted clusters are removed from the leaf.
+func (d *Drain) match(leaf *node, tokens []string) *LogCluster {
+	var (
+		best       *LogCluster
+		bestSim    = -1.0
+		bestParams = -1
+		live       = leaf.clusterIDs[:0]
+	)
+	for _, id := range leaf.clusterIDs {
+		c, ok := d.clusters.Peek(id)
+		if !ok {
+			continue
+		}
+		live = append(live, id)
+
+		cluster := c.(*LogCluster)
+		sim, params := cluster.similarity(tokens)
+		if sim > bestSim || (sim == bestSim && params > bestParams) {
+			best, bestSim, bestParams = cluster, sim, params
+		}
+	}
+	leaf.clusterIDs = live
+
+	if best == nil || bestSim < d.cfg.SimilarityThreshold {
+		return nil
+	}
+	return best
+}
+
+func tokenize(line string) []string {
+	tokens := strings.Split(line, " ")
+	for i, t := range tokens {
+		// literal captures would be parsed as captures by the pattern parser.
+		if captureRegexp.MatchString(t) {
+			tokens[i] = Placeholder
+		}
+	}
+	return tokens
+}
+
+func hasDigit(s string) bool {
+	for _, r := range s {
+		if unicode.IsDigit(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// LogCluster is a group of similar log lines.
+type LogCluster struct {
+	id      int
+	Tokens  []string
+	Samples []logproto.PatternSample
+}
+
+// String returns the pattern of the cluster.
+func (c *LogCluster) String() string {
+	return strings.Join(c.Tokens, " ")
+}
+
+// Stage returns the LogQL stage selecting the lines of a pattern, ready to be
+// pasted into a query. The placeholders of the pattern are named after their
+// position, since the pattern parser only extracts named captures, e.g.
+// `| pattern "User <v1> logged in from <v2>"`. A pattern without placeholders
+// can't be used with the pattern parser and is returned as a line filter.
+func Stage(pattern string) string {
+	n := 0
+	named := placeholderRegexp.ReplaceAllStringFunc(pattern, func(string) string {
+		n++
+		return "<v" + strconv.Itoa(n) + ">"
+	})
+	if n == 0 {
+		return "|= " + strconv.Quote(pattern)
+	}
+	return "| pattern " + strconv.Quote(named)
+}
+
+// similarity returns the ratio of tokens equal to the ones of the cluster and
+// the number of placeholders of the cluster. The tokens must have the same
+// length as the ones of the cluster.
+func (c *LogCluster) similarity(tokens []string) (float64, int) {
+	var equal, params int
+	for i, t := range c.Tokens {
+		if t == Placeholder {
+			params++
+			continue
+		}
+		if t == tokens[i] {
+			equal++
+		}
+	}
+	return float64(equal) / float64(len(tokens)), params
+}
+
+// merge replaces the tokens of the cluster that differ from the given ones
+// with the placeholder.
+func (c *LogCluster) merge(tokens []string) {
+	for i, t := range c.Tokens {
+		if t != tokens[i] {
+			c.Tokens[i] = Placeholder
+		}
+	}
+}
+
+// append counts an occurrence at the given timestamp in its sample of the
+// interval, and drops the samples older than maxAge relative to it.
+func (c *LogCluster) append(ts time.Time, interval, maxAge time.Duration) {
+	t := model.TimeFromUnixNano(ts.Truncate(interval).UnixNano())
+
+	n := len(c.Samples)
+	switch {
+	case n == 0 || c.Samples[n-1].Timestamp.Before(t):
+		c.Samples = append(c.Samples, logproto.PatternSample{Timestamp: t, Value: 1})
+	default:
+		// out of order entries are rare, so search from the end.
+		i := n - 1
+		for i >= 0 && c.Samples[i].Timestamp.After(t) {
+			i--
+		}
+		if i >= 0 && c.Samples[i].Timestamp.Equal(t) {
+			c.Samples[i].Value++
+			break
+		}
+		c.Samples = append(c.Samples, logproto.PatternSample{})
+		copy(c.Samples[i+2:], c.Samples[i+1:])
+		c.Samples[i+1] = logproto.PatternSample{Timestamp: t, Value: 1}
+	}
+
+	if maxAge <= 0 {
+		return
+	}
+	oldest := c.Samples[len(c.Samples)-1].Timestamp.Add(-maxAge)
+	i := 0
+	for i < len(c.Samples) && c.Samples[i].Timestamp.Before(oldest) {
+		i++
+	}
+	if i > 0 {
+		c.Samples = append(c.Samples[:0], c.Samples[i:]...)
+	}
+}
+
+// Iterate calls f for each sample of the cluster within [from, through).
+func (c *LogCluster) Iterate(from, through model.Time, f func(logproto.PatternSample)) {
+	for _, s := range c.Samples {
+		if s.Timestamp.Before(from) {
+			continue
+		}
+		if !s.Timestamp.Before(through) {
+			return
+		}
+		f(s)
+	}
+}
@@ -0,0 +1,169 @@
+package drain
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/logql/syntax"
+)
+
+func testConfig() *Config {
+	return &Config{
+		Enabled:             true,
+		SimilarityThreshold: 0.3,
+		MaxClusters:         300,
+		SampleInterval:      10 * time.Second,
+		MaxAge:              time.Hour,
+	}
+}
+
+func patterns(d *Drain) []string {
+	var res []string
+	for _, c := range d.Clusters() {
+		res = append(res, c.String())
+	}
+	return res
+}
+
+func TestDrain_Train(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		lines    []string
+		expected []string
+	}{
+		{
+			name: "variable tokens",
+			lines: []string{
+				"User alice logged in from 10.0.0.1",
+				"User bob logged in from 10.0.0.2",
+				"User carol logged in from 192.168.1.1",
+			},
+			expected: []string{"User <_> logged in from <_>"},
+		},
+		{
+			name: "different lengths",
+			lines: []string{
+				"GET /api/users 200",
+				"GET /api/users 500",
+				"connection closed",
+			},
+			expected: []string{"GET /api/users <_>", "connection closed"},
+		},
+		{
+			name: "dissimilar lines",
+			lines: []string{
+				"starting server on port 3100",
+				"level=info msg=done took 10ms",
+			},
+			expected: []string{"starting server on port 3100", "level=info msg=done took 10ms"},
+		},
+		{
+			name: "tokens looking like captures",
+			lines: []string{
+				"received <nil> from upstream",
+			},
+			expected: []string{"received <_> from upstream"},
+		},
+		{
+			name: "repeated spaces are kept",
+			lines: []string{
+				"took  10ms to respond",
+				"took  20ms to respond",
+			},
+			expected: []string{"took  <_> to respond"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			d := New(testConfig())
+			for _, l := range tc.lines {
+				d.Train(l, time.Unix(0, 0))
+			}
+			require.ElementsMatch(t, tc.expected, patterns(d))
+		})
+	}
+}
+
+func TestDrain_PatternsAreValid(t *testing.T) {
+	d := New(testConfig())
+	lines := []string{
+		`level=info ts=2023-12-01T10:00:00Z caller=main.go:12 msg="request done" status=200 duration=12ms`,
+		`level=info ts=2023-12-01T10:00:01Z caller=main.go:12 msg="request done" status=500 duration=3ms`,
+		`GET /api/v1/push HTTP/1.1 <- 204`,
+		`GET /api/v1/query HTTP/1.1 <- 200`,
+	}
+	for _, l := range lines {
+		d.Train(l, time.Unix(0, 0))
+	}
+	require.Len(t, d.Clusters(), 2)
+	for i, c := range d.Clusters() {
+		stage := Stage(c.String())
+		expr, err := syntax.ParseLogSelector(`{app="foo"} `+stage, true)
+		require.NoError(t, err, stage)
+		p, err := expr.Pipeline()
+		require.NoError(t, err, stage)
+		sp := p.ForStream(labels.FromStrings("app", "foo"))
+		for _, line := range lines[2*i : 2*i+2] {
+			_, lbs, ok := sp.ProcessString(0, line)
+			require.True(t, ok, stage)
+			require.Len(t, lbs.Labels(), 1+strings.Count(c.String(), Placeholder), stage)
+		}
+	}
+}
+
+func TestStage(t *testing.T) {
+	require.Equal(t, `| pattern "User <v1> logged in from <v2>"`, Stage("User <_> logged in from <_>"))
+	require.Equal(t, `| pattern "msg=\"<v1>\""`, Stage(`msg="<_>"`))
+	require.Equal(t, `|= "connection closed"`, Stage("connection closed"))
+}
+
+func TestDrain_MaxClusters(t *testing.T) {
+	cfg := testConfig()
+	cfg.MaxClusters = 2
+	d := New(cfg)
+
+	d.Train("first line", time.Unix(0, 0))
+	d.Train("second message here", time.Unix(0, 0))
+	d.Train("first line", time.Unix(0, 0))
+	d.Train("a third kind of line", time.Unix(0, 0))
+
+	// the second cluster is the least recently used one.
+	require.Equal(t, []string{"first line", "a third kind of line"}, patterns(d))
+
+	d.Train("second message here", time.Unix(0, 0))
+	require.Equal(t, []string{"a third kind of line", "second message here"}, patterns(d))
+}
+
+func TestLogCluster_Samples(t *testing.T) {
+	d := New(testConfig())
+	for _, ts := range []int64{0, 5, 12, 19, 31, 15, 25} {
+		d.Train("hello world", time.Unix(ts, 0))
+	}
+	c := d.Clusters()[0]
+	require.Equal(t, []logproto.PatternSample{
+		{Timestamp: model.TimeFromUnix(0), Value: 2},
+		{Timestamp: model.TimeFromUnix(10), Value: 3},
+		{Timestamp: model.TimeFromUnix(20), Value: 1},
+		{Timestamp: model.TimeFromUnix(30), Value: 1},
+	}, c.Samples)
+
+	var got []logproto.PatternSample
+	c.Iterate(model.TimeFromUnix(10), model.TimeFromUnix(30), func(s logproto.PatternSample) {
+		got = append(got, s)
+	})
+	require.Equal(t, c.Samples[1:3], got)
+
+	// samples older than the max age are dropped.
+	d.Train("hello world", time.Unix(3600+10, 0))
+	require.Equal(t, []logproto.PatternSample{
+		{Timestamp: model.TimeFromUnix(10), Value: 3},
+		{Timestamp: model.TimeFromUnix(20), Value: 1},
+		{Timestamp: model.TimeFromUnix(30), Value: 1},
+		{Timestamp: model.TimeFromUnix(3610), Value: 1},
+	}, c.Samples)
+}
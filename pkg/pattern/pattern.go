@@ -0,0 +1,99 @@
+// Package pattern aggregates the log patterns detected in the ingesters into
+// responses of the patterns API.
+package pattern
+
+import (
+	"sort"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/loki/pkg/logproto"
+)
+
+// Accumulator sums the samples of the same pattern into steps of a query.
+type Accumulator struct {
+	start model.Time
+	step  int64
+
+	series map[string]map[model.Time]int64
+}
+
+// NewAccumulator creates an Accumulator for the steps of the request.
+func NewAccumulator(req *logproto.QueryPatternsRequest) *Accumulator {
+	return &Accumulator{
+		start:  req.Start,
+		step:   req.Step,
+		series: map[string]map[model.Time]int64{},
+	}
+}
+
+// Add adds the sample of the pattern to the step it belongs to.
+func (a *Accumulator) Add(pattern string, s logproto.PatternSample) {
+	ts := s.Timestamp
+	if a.step > 0 && ts >= a.start {
+		ts = a.start + model.Time((int64(ts-a.start)/a.step)*a.step)
+	}
+
+	samples, ok := a.series[pattern]
+	if !ok {
+		samples = map[model.Time]int64{}
+		a.series[pattern] = samples
+	}
+	samples[ts] += s.Value
+}
+
+// AddResponse adds all the samples of the response.
+func (a *Accumulator) AddResponse(res *logproto.QueryPatternsResponse) {
+	if res == nil {
+		return
+	}
+	for _, s := range res.Series {
+		for _, sample := range s.Samples {
+			a.Add(s.Pattern, sample)
+		}
+	}
+}
+
+// Response returns the accumulated patterns, the most frequent first. The
+// samples are divided by the given divisor, rounding up, to account for the
+// replication of the streams.
+func (a *Accumulator) Response(divisor int64) *logproto.QueryPatternsResponse {
+	if divisor < 1 {
+		divisor = 1
+	}
+
+	type series struct {
+		*logproto.PatternSeries
+		total int64
+	}
+	all := make([]series, 0, len(a.series))
+	for pattern, samples := range a.series {
+		s := series{PatternSeries: &logproto.PatternSeries{
+			Pattern: pattern,
+			Samples: make([]logproto.PatternSample, 0, len(samples)),
+		}}
+		for ts, v := range samples {
+			v = (v + divisor - 1) / divisor
+			s.total += v
+			s.Samples = append(s.Samples, logproto.PatternSample{Timestamp: ts, Value: v})
+		}
+		sort.Slice(s.Samples, func(i, j int) bool {
+			return s.Samples[i].Timestamp < s.Samples[j].Timestamp
+		})
+		all = append(all, s)
+	}
+	sort.Slice(all, func(i, j int) bool {
+		if all[i].total == all[j].total {
+			return all[i].Pattern < all[j].Pattern
+		}
+		return all[i].total > all[j].total
+	})
+
+	res := &logproto.QueryPatternsResponse{
+		Series: make([]*logproto.PatternSeries, 0, len(all)),
+	}
+	for _, s := range all {
+		res.Series = append(res.Series, s.PatternSeries)
+	}
+	return res
+}
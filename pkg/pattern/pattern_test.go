@@ -0,0 +1,55 @@
+package pattern
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/pkg/logproto"
+)
+
+func TestAccumulator(t *testing.T) {
+	acc := NewAccumulator(&logproto.QueryPatternsRequest{
+		Start: 1000,
+		End:   5000,
+		Step:  1000,
+	})
+
+	acc.Add("foo <_>", logproto.PatternSample{Timestamp: 1000, Value: 1})
+	acc.Add("foo <_>", logproto.PatternSample{Timestamp: 1500, Value: 2})
+	acc.Add("bar <_>", logproto.PatternSample{Timestamp: 2000, Value: 1})
+	acc.AddResponse(&logproto.QueryPatternsResponse{
+		Series: []*logproto.PatternSeries{
+			{
+				Pattern: "bar <_>",
+				Samples: []logproto.PatternSample{{Timestamp: 3000, Value: 10}, {Timestamp: 2999, Value: 1}},
+			},
+		},
+	})
+
+	require.Equal(t, &logproto.QueryPatternsResponse{
+		Series: []*logproto.PatternSeries{
+			{
+				Pattern: "bar <_>",
+				Samples: []logproto.PatternSample{{Timestamp: 2000, Value: 2}, {Timestamp: 3000, Value: 10}},
+			},
+			{
+				Pattern: "foo <_>",
+				Samples: []logproto.PatternSample{{Timestamp: 1000, Value: 3}},
+			},
+		},
+	}, acc.Response(1))
+
+	require.Equal(t, &logproto.QueryPatternsResponse{
+		Series: []*logproto.PatternSeries{
+			{
+				Pattern: "bar <_>",
+				Samples: []logproto.PatternSample{{Timestamp: 2000, Value: 1}, {Timestamp: 3000, Value: 4}},
+			},
+			{
+				Pattern: "foo <_>",
+				Samples: []logproto.PatternSample{{Timestamp: 1000, Value: 1}},
+			},
+		},
+	}, acc.Response(3))
+}
@@ -111,11 +111,23 @@ func (h *Handler) Do(ctx context.Context, req queryrangebase.Request) (queryrang
 			return nil, err
 		}
 		return &queryrange.VolumeResponse{Response: result}, nil
+	case *logproto.QueryPatternsRequest:
+		result, err := h.api.PatternsHandler(ctx, concrete)
+		if err != nil {
+			return nil, err
+		}
+		return &queryrange.QueryPatternsResponse{Response: result}, nil
+	case *logproto.DetectedFieldsRequest:
+		result, err := h.api.DetectedFieldsHandler(ctx, concrete)
+		if err != nil {
+			return nil, err
+		}
+		return &queryrange.DetectedFieldsResponse{Response: result}, nil
 	default:
 		return nil, fmt.Errorf("unsupported query type %T", req)
 	}
 }
 
 func NewQuerierHTTPHandler(h *Handler) http.Handler {
-	return queryrange.NewSerializeHTTPHandler(h, queryrange.DefaultCodec)
+	return queryrange.NewSerializeHTTPHandler(h, queryrange.DefaultCodec, h.api.limits)
 }
@@ -376,6 +376,23 @@ func (q *QuerierAPI) VolumeHandler(ctx context.Context, req *logproto.VolumeRequ
 	return resp, nil
 }
 
+// PatternsHandler queries the log patterns detected by the ingesters for the streams matching the passed selector.
+func (q *QuerierAPI) PatternsHandler(ctx context.Context, req *logproto.QueryPatternsRequest) (*logproto.QueryPatternsResponse, error) {
+	return q.querier.Patterns(ctx, req)
+}
+
+// DetectedFieldsHandler returns the fields detected in a sample of the log lines matching the passed query.
+func (q *QuerierAPI) DetectedFieldsHandler(ctx context.Context, req *logproto.DetectedFieldsRequest) (*logproto.DetectedFieldsResponse, error) {
+	expr, err := syntax.ParseLogSelector(req.Query, true)
+	if err != nil {
+		return nil, httpgrpc.Errorf(http.StatusBadRequest, err.Error())
+	}
+	if err := q.validateMaxEntriesLimits(ctx, expr, req.LineLimit); err != nil {
+		return nil, err
+	}
+	return q.querier.DetectedFields(ctx, req)
+}
+
 func (q *QuerierAPI) validateMaxEntriesLimits(ctx context.Context, expr syntax.Expr, limit uint32) error {
 	tenantIDs, err := tenant.TenantIDs(ctx)
 	if err != nil {
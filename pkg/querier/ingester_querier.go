@@ -25,6 +25,7 @@ import (
 	"github.com/grafana/loki/pkg/logql"
 	"github.com/grafana/loki/pkg/logql/syntax"
 	"github.com/grafana/loki/pkg/logqlmodel/stats"
+	"github.com/grafana/loki/pkg/pattern"
 	index_stats "github.com/grafana/loki/pkg/storage/stores/index/stats"
 	util_log "github.com/grafana/loki/pkg/util/log"
 )
@@ -356,6 +357,28 @@ func (q *IngesterQuerier) Volume(ctx context.Context, _ string, from, through mo
 	return merged, nil
 }
 
+func (q *IngesterQuerier) Patterns(ctx context.Context, req *logproto.QueryPatternsRequest) (*logproto.QueryPatternsResponse, error) {
+	resps, err := q.forAllIngesters(ctx, func(ctx context.Context, querierClient logproto.QuerierClient) (interface{}, error) {
+		return querierClient.GetPatterns(ctx, req)
+	})
+
+	if err != nil {
+		if isUnimplementedCallError(err) {
+			// Handle communication with older ingesters gracefully
+			return &logproto.QueryPatternsResponse{}, nil
+		}
+		return nil, err
+	}
+
+	acc := pattern.NewAccumulator(req)
+	for _, resp := range resps {
+		acc.AddResponse(resp.response.(*logproto.QueryPatternsResponse))
+	}
+
+	// Each stream is replicated to several ingesters, which all detect the same patterns.
+	return acc.Response(int64(q.ring.ReplicationFactor())), nil
+}
+
 func convertMatchersToString(matchers []*labels.Matcher) string {
 	out := strings.Builder{}
 	out.WriteRune('{')
@@ -401,3 +401,39 @@ func TestIngesterQuerier_Volume(t *testing.T) {
 		require.Equal(t, []logproto.Volume(nil), volumes.Volumes)
 	})
 }
+
+func TestIngesterQuerier_Patterns(t *testing.T) {
+	ret := &logproto.QueryPatternsResponse{
+		Series: []*logproto.PatternSeries{
+			{Pattern: "foo <_>", Samples: []logproto.PatternSample{{Timestamp: 0, Value: 1}, {Timestamp: 1000, Value: 2}}},
+			{Pattern: "bar <_>", Samples: []logproto.PatternSample{{Timestamp: 0, Value: 5}}},
+		},
+	}
+
+	ingesterClient := newQuerierClientMock()
+	ingesterClient.On("GetPatterns", mock.Anything, mock.Anything, mock.Anything).Return(ret, nil)
+
+	ingesterQuerier, err := newIngesterQuerier(
+		mockIngesterClientConfig(),
+		newReadRingMock([]ring.InstanceDesc{mockInstanceDesc("1.1.1.1", ring.ACTIVE), mockInstanceDesc("3.3.3.3", ring.ACTIVE)}, 0),
+		mockQuerierConfig().ExtraQueryDelay,
+		newIngesterClientMockFactory(ingesterClient),
+		constants.Loki,
+	)
+	require.NoError(t, err)
+
+	patterns, err := ingesterQuerier.Patterns(context.Background(), &logproto.QueryPatternsRequest{
+		Query: `{foo="bar"}`,
+		Start: 0,
+		End:   2000,
+		Step:  1000,
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, &logproto.QueryPatternsResponse{
+		Series: []*logproto.PatternSeries{
+			{Pattern: "bar <_>", Samples: []logproto.PatternSample{{Timestamp: 0, Value: 10}}},
+			{Pattern: "foo <_>", Samples: []logproto.PatternSample{{Timestamp: 0, Value: 2}, {Timestamp: 1000, Value: 4}}},
+		},
+	}, patterns)
+}
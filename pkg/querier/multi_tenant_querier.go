@@ -17,7 +17,9 @@ import (
 	"github.com/grafana/loki/pkg/loghttp"
 	"github.com/grafana/loki/pkg/logproto"
 	"github.com/grafana/loki/pkg/logql"
+	"github.com/grafana/loki/pkg/logql/detected"
 	"github.com/grafana/loki/pkg/logql/syntax"
+	"github.com/grafana/loki/pkg/pattern"
 	"github.com/grafana/loki/pkg/storage/stores/index/stats"
 )
 
@@ -258,6 +260,56 @@ func (q *MultiTenantQuerier) Volume(ctx context.Context, req *logproto.VolumeReq
 	return merged, nil
 }
 
+func (q *MultiTenantQuerier) Patterns(ctx context.Context, req *logproto.QueryPatternsRequest) (*logproto.QueryPatternsResponse, error) {
+	tenantIDs, err := tenant.TenantIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tenantIDs) == 1 {
+		return q.Querier.Patterns(ctx, req)
+	}
+
+	acc := pattern.NewAccumulator(req)
+	for _, id := range tenantIDs {
+		singleContext := user.InjectOrgID(ctx, id)
+		resp, err := q.Querier.Patterns(singleContext, req)
+		if err != nil {
+			return nil, err
+		}
+
+		acc.AddResponse(resp)
+	}
+
+	return acc.Response(1), nil
+}
+
+func (q *MultiTenantQuerier) DetectedFields(ctx context.Context, req *logproto.DetectedFieldsRequest) (*logproto.DetectedFieldsResponse, error) {
+	tenantIDs, err := tenant.TenantIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tenantIDs) == 1 {
+		return q.Querier.DetectedFields(ctx, req)
+	}
+
+	acc := detected.NewAccumulator()
+	for _, id := range tenantIDs {
+		singleContext := user.InjectOrgID(ctx, id)
+		resp, err := q.Querier.DetectedFields(singleContext, req)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := acc.AddResponse(resp); err != nil {
+			return nil, err
+		}
+	}
+
+	return acc.Response(req.FieldLimit)
+}
+
 // removeTenantSelector filters the given tenant IDs based on any tenant ID filter the in passed selector.
 func removeTenantSelector(params logql.SelectSampleParams, tenantIDs []string) (map[string]struct{}, syntax.Expr, error) {
 	expr, err := params.Expr()
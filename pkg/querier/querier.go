@@ -28,6 +28,7 @@ import (
 	"github.com/grafana/loki/pkg/loghttp"
 	"github.com/grafana/loki/pkg/logproto"
 	"github.com/grafana/loki/pkg/logql"
+	"github.com/grafana/loki/pkg/logql/detected"
 	"github.com/grafana/loki/pkg/logql/syntax"
 	querier_limits "github.com/grafana/loki/pkg/querier/limits"
 	"github.com/grafana/loki/pkg/querier/plan"
@@ -95,6 +96,8 @@ type Querier interface {
 	IndexStats(ctx context.Context, req *loghttp.RangeQuery) (*stats.Stats, error)
 	IndexShards(ctx context.Context, req *loghttp.RangeQuery, targetBytesPerShard uint64) (*logproto.ShardsResponse, error)
 	Volume(ctx context.Context, req *logproto.VolumeRequest) (*logproto.VolumeResponse, error)
+	Patterns(ctx context.Context, req *logproto.QueryPatternsRequest) (*logproto.QueryPatternsResponse, error)
+	DetectedFields(ctx context.Context, req *logproto.DetectedFieldsRequest) (*logproto.DetectedFieldsResponse, error)
 }
 
 type Limits querier_limits.Limits
@@ -897,3 +900,103 @@ func (q *SingleTenantQuerier) Volume(ctx context.Context, req *logproto.VolumeRe
 
 	return seriesvolume.Merge(responses, req.Limit), nil
 }
+
+// Patterns returns the log patterns detected by the ingesters. Patterns are
+// only kept in memory, so the store is never queried.
+func (q *SingleTenantQuerier) Patterns(ctx context.Context, req *logproto.QueryPatternsRequest) (*logproto.QueryPatternsResponse, error) {
+	sp, ctx := opentracing.StartSpanFromContext(ctx, "Querier.Patterns")
+	defer sp.Finish()
+
+	userID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if q.cfg.QueryStoreOnly {
+		return &logproto.QueryPatternsResponse{}, nil
+	}
+
+	// Enforce the query timeout while querying backends
+	queryTimeout := q.limits.QueryTimeout(ctx, userID)
+	ctx, cancel := context.WithDeadline(ctx, time.Now().Add(queryTimeout))
+	defer cancel()
+
+	sp.LogKV(
+		"user", userID,
+		"query", req.Query,
+		"start", req.Start.Time(),
+		"end", req.End.Time(),
+		"step", req.Step,
+	)
+
+	return q.ingesterQuerier.Patterns(ctx, req)
+}
+
+// DetectedFields parses the most recent log lines of the query with the logfmt
+// and json parsers and returns the fields they detect.
+func (q *SingleTenantQuerier) DetectedFields(ctx context.Context, req *logproto.DetectedFieldsRequest) (*logproto.DetectedFieldsResponse, error) {
+	sp, ctx := opentracing.StartSpanFromContext(ctx, "Querier.DetectedFields")
+	defer sp.Finish()
+
+	userID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	expr, err := syntax.ParseLogSelector(req.Query, true)
+	if err != nil {
+		return nil, err
+	}
+
+	// Enforce the query timeout while querying backends
+	queryTimeout := q.limits.QueryTimeout(ctx, userID)
+	ctx, cancel := context.WithDeadline(ctx, time.Now().Add(queryTimeout))
+	defer cancel()
+
+	sp.LogKV(
+		"user", userID,
+		"query", req.Query,
+		"start", req.Start.Time(),
+		"end", req.End.Time(),
+		"line_limit", req.LineLimit,
+		"field_limit", req.FieldLimit,
+	)
+
+	it, err := q.SelectLogs(ctx, logql.SelectLogParams{
+		QueryRequest: &logproto.QueryRequest{
+			Selector:  req.Query,
+			Start:     req.Start.Time(),
+			End:       req.End.Time(),
+			Limit:     req.LineLimit,
+			Direction: logproto.BACKWARD,
+			Plan: &plan.QueryPlan{
+				AST: expr,
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+
+	var (
+		acc     = detected.NewAccumulator()
+		streams = map[string]labels.Labels{}
+	)
+	for n := uint32(0); n < req.LineLimit && it.Next(); n++ {
+		lbs, ok := streams[it.Labels()]
+		if !ok {
+			lbs, err = syntax.ParseLabels(it.Labels())
+			if err != nil {
+				return nil, err
+			}
+			streams[it.Labels()] = lbs
+		}
+		acc.AddLine(lbs, []byte(it.Entry().Line))
+	}
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+
+	return acc.Response(req.FieldLimit)
+}
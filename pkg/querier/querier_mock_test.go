@@ -120,6 +120,15 @@ func (c *querierClientMock) GetVolume(ctx context.Context, in *logproto.VolumeRe
 	return res.(*logproto.VolumeResponse), args.Error(1)
 }
 
+func (c *querierClientMock) GetPatterns(ctx context.Context, in *logproto.QueryPatternsRequest, opts ...grpc.CallOption) (*logproto.QueryPatternsResponse, error) {
+	args := c.Called(ctx, in, opts)
+	res := args.Get(0)
+	if res == nil {
+		return (*logproto.QueryPatternsResponse)(nil), args.Error(1)
+	}
+	return res.(*logproto.QueryPatternsResponse), args.Error(1)
+}
+
 func (c *querierClientMock) Context() context.Context {
 	return context.Background()
 }
@@ -580,6 +589,30 @@ func (q *querierMock) Volume(ctx context.Context, req *logproto.VolumeRequest) (
 	return resp.(*logproto.VolumeResponse), err
 }
 
+func (q *querierMock) Patterns(ctx context.Context, req *logproto.QueryPatternsRequest) (*logproto.QueryPatternsResponse, error) {
+	args := q.MethodCalled("Patterns", ctx, req)
+
+	resp := args.Get(0)
+	err := args.Error(1)
+	if resp == nil {
+		return nil, err
+	}
+
+	return resp.(*logproto.QueryPatternsResponse), err
+}
+
+func (q *querierMock) DetectedFields(ctx context.Context, req *logproto.DetectedFieldsRequest) (*logproto.DetectedFieldsResponse, error) {
+	args := q.MethodCalled("DetectedFields", ctx, req)
+
+	resp := args.Get(0)
+	err := args.Error(1)
+	if resp == nil {
+		return nil, err
+	}
+
+	return resp.(*logproto.DetectedFieldsResponse), err
+}
+
 type engineMock struct {
 	util.ExtendedMock
 }
@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	io "io"
+	"math"
 	"net/http"
 	"net/url"
 	"regexp"
@@ -27,6 +28,7 @@ import (
 	"github.com/grafana/loki/pkg/loghttp"
 	"github.com/grafana/loki/pkg/logproto"
 	"github.com/grafana/loki/pkg/logql"
+	"github.com/grafana/loki/pkg/logql/detected"
 	"github.com/grafana/loki/pkg/logql/syntax"
 	"github.com/grafana/loki/pkg/logqlmodel"
 	"github.com/grafana/loki/pkg/logqlmodel/stats"
@@ -388,6 +390,18 @@ func (Codec) DecodeRequest(_ context.Context, r *http.Request, _ []string) (quer
 			TargetLabels: req.TargetLabels,
 			AggregateBy:  req.AggregateBy,
 		}, err
+	case PatternsOp:
+		req, err := loghttp.ParsePatternsQuery(r)
+		if err != nil {
+			return nil, httpgrpc.Errorf(http.StatusBadRequest, err.Error())
+		}
+		return req, nil
+	case DetectedFieldsOp:
+		req, err := loghttp.ParseDetectedFieldsQuery(r)
+		if err != nil {
+			return nil, httpgrpc.Errorf(http.StatusBadRequest, err.Error())
+		}
+		return req, nil
 	default:
 		return nil, httpgrpc.Errorf(http.StatusNotFound, fmt.Sprintf("unknown request path: %s", r.URL.Path))
 	}
@@ -581,6 +595,18 @@ func (Codec) DecodeHTTPGrpcRequest(ctx context.Context, r *httpgrpc.HTTPRequest)
 			TargetLabels: req.TargetLabels,
 			AggregateBy:  req.AggregateBy,
 		}, ctx, err
+	case PatternsOp:
+		req, err := loghttp.ParsePatternsQuery(httpReq)
+		if err != nil {
+			return nil, ctx, httpgrpc.Errorf(http.StatusBadRequest, err.Error())
+		}
+		return req, ctx, nil
+	case DetectedFieldsOp:
+		req, err := loghttp.ParseDetectedFieldsQuery(httpReq)
+		if err != nil {
+			return nil, ctx, httpgrpc.Errorf(http.StatusBadRequest, err.Error())
+		}
+		return req, ctx, nil
 	default:
 		return nil, ctx, httpgrpc.Errorf(http.StatusBadRequest, fmt.Sprintf("unknown request path in HTTP gRPC decode: %s", r.Url))
 	}
@@ -730,6 +756,9 @@ func (c Codec) EncodeRequest(ctx context.Context, r queryrangebase.Request) (*ht
 			"end":   []string{fmt.Sprintf("%d", request.End.UnixNano())},
 			"query": []string{request.GetQuery()},
 		}
+		if request.StructuredMetadata {
+			params["structured_metadata"] = []string{"true"}
+		}
 
 		u := &url.URL{
 			Path:     request.Path(), // NOTE: this could be either /label or /label/{name}/values endpoint. So forward the original path as it is.
@@ -838,6 +867,45 @@ func (c Codec) EncodeRequest(ctx context.Context, r queryrangebase.Request) (*ht
 			Header:     header,
 		}
 		return req.WithContext(ctx), nil
+	case *logproto.QueryPatternsRequest:
+		params := url.Values{
+			"query": []string{request.GetQuery()},
+			"start": []string{fmt.Sprintf("%d", request.Start.Time().UnixNano())},
+			"end":   []string{fmt.Sprintf("%d", request.End.Time().UnixNano())},
+			"step":  []string{fmt.Sprintf("%f", float64(request.Step)/float64(1e3))},
+		}
+		u := &url.URL{
+			Path:     "/loki/api/v1/patterns",
+			RawQuery: params.Encode(),
+		}
+		req := &http.Request{
+			Method:     "GET",
+			RequestURI: u.String(), // This is what the httpgrpc code looks at.
+			URL:        u,
+			Body:       http.NoBody,
+			Header:     header,
+		}
+		return req.WithContext(ctx), nil
+	case *logproto.DetectedFieldsRequest:
+		params := url.Values{
+			"query":       []string{request.GetQuery()},
+			"start":       []string{fmt.Sprintf("%d", request.Start.Time().UnixNano())},
+			"end":         []string{fmt.Sprintf("%d", request.End.Time().UnixNano())},
+			"line_limit":  []string{fmt.Sprintf("%d", request.LineLimit)},
+			"field_limit": []string{fmt.Sprintf("%d", request.FieldLimit)},
+		}
+		u := &url.URL{
+			Path:     "/loki/api/v1/detected_fields",
+			RawQuery: params.Encode(),
+		}
+		req := &http.Request{
+			Method:     "GET",
+			RequestURI: u.String(), // This is what the httpgrpc code looks at.
+			URL:        u,
+			Body:       http.NoBody,
+			Header:     header,
+		}
+		return req.WithContext(ctx), nil
 	default:
 		return nil, httpgrpc.Errorf(http.StatusInternalServerError, fmt.Sprintf("invalid request format, got (%T)", r))
 	}
@@ -863,6 +931,10 @@ func (c Codec) Path(r queryrangebase.Request) string {
 		return "/loki/api/v1/index/stats"
 	case *logproto.VolumeRequest:
 		return "/loki/api/v1/index/volume_range"
+	case *logproto.QueryPatternsRequest:
+		return "/loki/api/v1/patterns"
+	case *logproto.DetectedFieldsRequest:
+		return "/loki/api/v1/detected_fields"
 	}
 
 	return "other"
@@ -964,6 +1036,24 @@ func decodeResponseJSONFrom(buf []byte, req queryrangebase.Request, headers http
 			Response: &resp,
 			Headers:  httpResponseHeadersToPromResponseHeaders(headers),
 		}, nil
+	case *logproto.QueryPatternsRequest:
+		var resp loghttp.PatternsResponse
+		if err := json.Unmarshal(buf, &resp); err != nil {
+			return nil, httpgrpc.Errorf(http.StatusInternalServerError, "error decoding response: %v", err)
+		}
+		return &QueryPatternsResponse{
+			Response: resp.ToProto(),
+			Headers:  httpResponseHeadersToPromResponseHeaders(headers),
+		}, nil
+	case *logproto.DetectedFieldsRequest:
+		var resp loghttp.DetectedFieldsResponse
+		if err := json.Unmarshal(buf, &resp); err != nil {
+			return nil, httpgrpc.Errorf(http.StatusInternalServerError, "error decoding response: %v", err)
+		}
+		return &DetectedFieldsResponse{
+			Response: resp.ToProto(),
+			Headers:  httpResponseHeadersToPromResponseHeaders(headers),
+		}, nil
 	default:
 		var resp loghttp.QueryResponse
 		if err := resp.UnmarshalJSON(buf); err != nil {
@@ -1073,6 +1163,10 @@ func decodeResponseProtobuf(r *http.Response, req queryrangebase.Request) (query
 		return resp.GetStats().WithHeaders(headers), nil
 	case *logproto.ShardsRequest:
 		return resp.GetShardsResponse().WithHeaders(headers), nil
+	case *logproto.QueryPatternsRequest:
+		return resp.GetPatternsResponse().WithHeaders(headers), nil
+	case *logproto.DetectedFieldsRequest:
+		return resp.GetDetectedFields().WithHeaders(headers), nil
 	default:
 		switch concrete := resp.Response.(type) {
 		case *QueryResponse_Prom:
@@ -1083,6 +1177,8 @@ func decodeResponseProtobuf(r *http.Response, req queryrangebase.Request) (query
 			return concrete.TopkSketches.WithHeaders(headers), nil
 		case *QueryResponse_QuantileSketches:
 			return concrete.QuantileSketches.WithHeaders(headers), nil
+		case *QueryResponse_CountDistinctSketches:
+			return concrete.CountDistinctSketches.WithHeaders(headers), nil
 		default:
 			return nil, httpgrpc.Errorf(http.StatusInternalServerError, "unsupported response type, got (%T)", resp.Response)
 		}
@@ -1178,6 +1274,14 @@ func encodeResponseJSONTo(version loghttp.Version, res queryrangebase.Response,
 		if err := marshal.WriteVolumeResponseJSON(response.Response, w); err != nil {
 			return err
 		}
+	case *QueryPatternsResponse:
+		if err := marshal.WritePatternsResponseJSON(response.Response, w); err != nil {
+			return err
+		}
+	case *DetectedFieldsResponse:
+		if err := marshal.WriteDetectedFieldsResponseJSON(response.Response, w); err != nil {
+			return err
+		}
 	default:
 		return httpgrpc.Errorf(http.StatusInternalServerError, fmt.Sprintf("invalid response format, got (%T)", res))
 	}
@@ -1340,6 +1444,24 @@ func (Codec) MergeResponse(responses ...queryrangebase.Response) (queryrangebase
 			Response: seriesvolume.Merge(resps, resp0.Response.Limit),
 			Headers:  headers,
 		}, nil
+	case *DetectedFieldsResponse:
+		resp0 := responses[0].(*DetectedFieldsResponse)
+
+		acc := detected.NewAccumulator()
+		for _, r := range responses {
+			if err := acc.AddResponse(r.(*DetectedFieldsResponse).Response); err != nil {
+				return nil, err
+			}
+		}
+		merged, err := acc.Response(resp0.Response.GetFieldLimit())
+		if err != nil {
+			return nil, err
+		}
+
+		return &DetectedFieldsResponse{
+			Response: merged,
+			Headers:  resp0.Headers,
+		}, nil
 	default:
 		return nil, fmt.Errorf("unknown response type (%T) in merging responses", responses[0])
 	}
@@ -1765,14 +1887,26 @@ func NewEmptyResponse(r queryrangebase.Request) (queryrangebase.Response, error)
 		return &VolumeResponse{
 			Response: &logproto.VolumeResponse{},
 		}, nil
+	case *logproto.DetectedFieldsRequest:
+		return &DetectedFieldsResponse{
+			Response: &logproto.DetectedFieldsResponse{FieldLimit: req.FieldLimit},
+		}, nil
 	default:
 		return nil, fmt.Errorf("unsupported request type %T", req)
 	}
 }
 
 func mergeLokiResponse(responses ...queryrangebase.Response) *LokiResponse {
+	// without limit or dedup stages the merge can't fail.
+	res, _ := mergeLokiResponseWithLineLimits(nil, responses...)
+	return res
+}
+
+// mergeLokiResponseWithLineLimits merges responses and applies the limit and dedup stages of their query
+// again, since each response only applied them to its own lines.
+func mergeLokiResponseWithLineLimits(limits syntax.MultiStageExpr, responses ...queryrangebase.Response) (*LokiResponse, error) {
 	if len(responses) == 0 {
-		return nil
+		return nil, nil
 	}
 	var (
 		lokiRes       = responses[0].(*LokiResponse)
@@ -1786,6 +1920,15 @@ func mergeLokiResponse(responses ...queryrangebase.Response) *LokiResponse {
 		lokiResponses = append(lokiResponses, lokiResult)
 	}
 
+	if len(limits) > 0 {
+		// the limit of the response is applied once the lines of every response are limited and de-duplicated.
+		streams, err := logql.ApplyLineLimits(limits, mergeOrderedNonOverlappingStreams(lokiResponses, math.MaxUint32, lokiRes.Direction), lokiRes.Direction)
+		if err != nil {
+			return nil, err
+		}
+		lokiResponses = []*LokiResponse{{Data: LokiData{Result: streams}}}
+	}
+
 	return &LokiResponse{
 		Status:     loghttp.QueryStatusSuccess,
 		Direction:  lokiRes.Direction,
@@ -1798,5 +1941,18 @@ func mergeLokiResponse(responses ...queryrangebase.Response) *LokiResponse {
 			ResultType: loghttp.ResultTypeStream,
 			Result:     mergeOrderedNonOverlappingStreams(lokiResponses, lokiRes.Limit, lokiRes.Direction),
 		},
+	}, nil
+}
+
+// lineLimits returns the limit and dedup stages of a log query request.
+func lineLimits(req queryrangebase.Request) syntax.MultiStageExpr {
+	lokiReq, ok := req.(*LokiRequest)
+	if !ok || lokiReq.Plan == nil {
+		return nil
+	}
+	selector, ok := lokiReq.Plan.AST.(syntax.LogSelectorExpr)
+	if !ok {
+		return nil
 	}
+	return syntax.LineLimits(selector)
 }
@@ -25,6 +25,7 @@ import (
 	"github.com/grafana/loki/pkg/loghttp"
 	"github.com/grafana/loki/pkg/logproto"
 	"github.com/grafana/loki/pkg/logql"
+	"github.com/grafana/loki/pkg/logql/detected"
 	"github.com/grafana/loki/pkg/logql/syntax"
 	"github.com/grafana/loki/pkg/logqlmodel"
 	"github.com/grafana/loki/pkg/logqlmodel/stats"
@@ -199,6 +200,34 @@ func Test_codec_EncodeDecodeRequest(t *testing.T) {
 			Step:        30 * 1e3, // step is expected in ms; default is 0 or no step
 			AggregateBy: "series",
 		}, false},
+		{"patterns", func() (*http.Request, error) {
+			return DefaultCodec.EncodeRequest(ctx, &logproto.QueryPatternsRequest{
+				Query: `{job="foo"}`,
+				Start: model.TimeFromUnixNano(start.UnixNano()),
+				End:   model.TimeFromUnixNano(end.UnixNano()),
+				Step:  30 * 1e3,
+			})
+		}, &logproto.QueryPatternsRequest{
+			Query: `{job="foo"}`,
+			Start: model.TimeFromUnixNano(start.UnixNano()),
+			End:   model.TimeFromUnixNano(end.UnixNano()),
+			Step:  30 * 1e3, // step is expected in ms
+		}, false},
+		{"detected_fields", func() (*http.Request, error) {
+			return DefaultCodec.EncodeRequest(ctx, &logproto.DetectedFieldsRequest{
+				Query:      `{job="foo"} |= "error"`,
+				Start:      model.TimeFromUnixNano(start.UnixNano()),
+				End:        model.TimeFromUnixNano(end.UnixNano()),
+				LineLimit:  100,
+				FieldLimit: 10,
+			})
+		}, &logproto.DetectedFieldsRequest{
+			Query:      `{job="foo"} |= "error"`,
+			Start:      model.TimeFromUnixNano(start.UnixNano()),
+			End:        model.TimeFromUnixNano(end.UnixNano()),
+			LineLimit:  100,
+			FieldLimit: 10,
+		}, false},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -866,6 +895,109 @@ func Test_codec_seriesVolume_DecodeRequest(t *testing.T) {
 	})
 }
 
+func Test_codec_patterns_EncodeDecodeResponse(t *testing.T) {
+	ctx := user.InjectOrgID(context.Background(), "1")
+	req := &logproto.QueryPatternsRequest{
+		Query: `{job="foo"}`,
+		Start: model.TimeFromUnixNano(start.UnixNano()),
+		End:   model.TimeFromUnixNano(end.UnixNano()),
+		Step:  30 * 1e3,
+	}
+	res := &QueryPatternsResponse{
+		Response: &logproto.QueryPatternsResponse{
+			Series: []*logproto.PatternSeries{
+				{
+					Pattern: "user <_> logged in",
+					Samples: []logproto.PatternSample{{Timestamp: 1000, Value: 2}, {Timestamp: 31000, Value: 5}},
+				},
+			},
+		},
+	}
+
+	for _, accept := range []string{"", ProtobufType} {
+		httpReq := httptest.NewRequest(http.MethodGet, "/loki/api/v1/patterns", nil)
+		httpReq.Header.Set("Accept", accept)
+		httpRes, err := DefaultCodec.EncodeResponse(ctx, httpReq, res)
+		require.NoError(t, err)
+
+		if accept == "" {
+			body, err := io.ReadAll(httpRes.Body)
+			require.NoError(t, err)
+			require.JSONEq(t, `{"status":"success","data":[{"pattern":"user <_> logged in","stage":"| pattern \"user <v1> logged in\"","samples":[[1,2],[31,5]]}]}`, string(body))
+			httpRes.Body = io.NopCloser(bytes.NewReader(body))
+		}
+
+		decoded, err := DefaultCodec.DecodeResponse(ctx, httpRes, req)
+		require.NoError(t, err)
+		require.Equal(t, res.Response, decoded.(*QueryPatternsResponse).Response)
+	}
+}
+
+func Test_codec_detectedFields_EncodeDecodeResponse(t *testing.T) {
+	ctx := user.InjectOrgID(context.Background(), "1")
+	req := &logproto.DetectedFieldsRequest{
+		Query:      `{job="foo"}`,
+		Start:      model.TimeFromUnixNano(start.UnixNano()),
+		End:        model.TimeFromUnixNano(end.UnixNano()),
+		LineLimit:  100,
+		FieldLimit: 10,
+	}
+	res := &DetectedFieldsResponse{
+		Response: &logproto.DetectedFieldsResponse{
+			Fields: []*logproto.DetectedField{
+				{Label: "status", Type: "int", Cardinality: 4, Parsers: []string{"json", "logfmt"}},
+			},
+			FieldLimit: 10,
+		},
+	}
+
+	for _, accept := range []string{"", ProtobufType} {
+		httpReq := httptest.NewRequest(http.MethodGet, "/loki/api/v1/detected_fields", nil)
+		httpReq.Header.Set("Accept", accept)
+		httpRes, err := DefaultCodec.EncodeResponse(ctx, httpReq, res)
+		require.NoError(t, err)
+
+		if accept == "" {
+			body, err := io.ReadAll(httpRes.Body)
+			require.NoError(t, err)
+			require.JSONEq(t, `{"fields":[{"label":"status","type":"int","cardinality":4,"parsers":["json","logfmt"]}],"fieldLimit":10}`, string(body))
+			httpRes.Body = io.NopCloser(bytes.NewReader(body))
+		}
+
+		decoded, err := DefaultCodec.DecodeResponse(ctx, httpRes, req)
+		require.NoError(t, err)
+		require.Equal(t, res.Response, decoded.(*DetectedFieldsResponse).Response)
+	}
+}
+
+func Test_codec_detectedFields_MergeResponse(t *testing.T) {
+	acc := detected.NewAccumulator()
+	stream := labels.FromStrings("job", "foo")
+	acc.AddLine(stream, []byte(`status=200 path=/a`))
+	first, err := acc.Response(10)
+	require.NoError(t, err)
+
+	acc = detected.NewAccumulator()
+	acc.AddLine(stream, []byte(`{"status":"OK"}`))
+	second, err := acc.Response(10)
+	require.NoError(t, err)
+
+	merged, err := DefaultCodec.MergeResponse(
+		&DetectedFieldsResponse{Response: first},
+		&DetectedFieldsResponse{Response: second},
+	)
+	require.NoError(t, err)
+
+	fields := merged.(*DetectedFieldsResponse).Response.Fields
+	require.Len(t, fields, 2)
+	require.Equal(t, "path", fields[0].Label)
+	require.Equal(t, "status", fields[1].Label)
+	require.Equal(t, detected.TypeString, fields[1].Type)
+	require.Equal(t, uint64(2), fields[1].Cardinality)
+	require.Equal(t, []string{detected.ParserJSON, detected.ParserLogfmt}, fields[1].Parsers)
+	require.Equal(t, uint32(10), merged.(*DetectedFieldsResponse).Response.FieldLimit)
+}
+
 func Test_codec_EncodeResponse(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -0,0 +1,91 @@
+package queryrange
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/go-kit/log"
+
+	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/querier/queryrange/queryrangebase"
+	"github.com/grafana/loki/pkg/storage/chunk/cache"
+	"github.com/grafana/loki/pkg/storage/chunk/cache/resultscache"
+	"github.com/grafana/loki/pkg/util"
+)
+
+type cacheKeyDetectedFields struct {
+	cacheKeyLimits
+}
+
+// GenerateCacheKey generates a cache key based on the userID, query, interval and the limits of the request.
+func (i cacheKeyDetectedFields) GenerateCacheKey(ctx context.Context, userID string, r resultscache.Request) string {
+	cacheKey := i.cacheKeyLimits.GenerateCacheKey(ctx, userID, r)
+
+	req := r.(*logproto.DetectedFieldsRequest)
+	return fmt.Sprintf("detected_fields:%s:%d:%d", cacheKey, req.GetLineLimit(), req.GetFieldLimit())
+}
+
+type detectedFieldsExtractor struct{}
+
+// Extract extracts the detected fields response for the specific time range.
+// The fields are detected from a sample of the lines, which can't be partitioned by time range, so only a response
+// entirely within the time range is returned as is. The fields of a response overlapping the time range can't be
+// told apart and an empty response is returned instead, though the cache middleware only extracts entire extents.
+func (p detectedFieldsExtractor) Extract(start, end int64, res resultscache.Response, resStart, resEnd int64) resultscache.Response {
+	if start > resStart || end < resEnd {
+		return &DetectedFieldsResponse{Response: &logproto.DetectedFieldsResponse{}}
+	}
+	return res
+}
+
+func (p detectedFieldsExtractor) ResponseWithoutHeaders(resp queryrangebase.Response) queryrangebase.Response {
+	fieldsResp := resp.(*DetectedFieldsResponse)
+	return &DetectedFieldsResponse{
+		Response: fieldsResp.Response,
+	}
+}
+
+type DetectedFieldsCacheConfig struct {
+	queryrangebase.ResultsCacheConfig `yaml:",inline"`
+}
+
+// RegisterFlags registers flags.
+func (cfg *DetectedFieldsCacheConfig) RegisterFlags(f *flag.FlagSet) {
+	cfg.RegisterFlagsWithPrefix(f, "frontend.detected-fields-results-cache.")
+}
+
+func (cfg *DetectedFieldsCacheConfig) Validate() error {
+	return cfg.ResultsCacheConfig.Validate()
+}
+
+func NewDetectedFieldsCacheMiddleware(
+	logger log.Logger,
+	limits Limits,
+	merger queryrangebase.Merger,
+	c cache.Cache,
+	cacheGenNumberLoader queryrangebase.CacheGenNumberLoader,
+	iqo util.IngesterQueryOptions,
+	shouldCache queryrangebase.ShouldCacheFn,
+	parallelismForReq queryrangebase.ParallelismForReqFn,
+	retentionEnabled bool,
+	transformer UserIDTransformer,
+	metrics *queryrangebase.ResultsCacheMetrics,
+) (queryrangebase.Middleware, error) {
+	return queryrangebase.NewResultsCacheMiddleware(
+		logger,
+		c,
+		cacheKeyDetectedFields{cacheKeyLimits{limits, transformer, iqo}},
+		limits,
+		merger,
+		detectedFieldsExtractor{},
+		cacheGenNumberLoader,
+		func(ctx context.Context, r queryrangebase.Request) bool {
+			return shouldCacheMetadataReq(ctx, logger, shouldCache, r, limits)
+		},
+		parallelismForReq,
+		retentionEnabled,
+		true,
+		metrics,
+	)
+}
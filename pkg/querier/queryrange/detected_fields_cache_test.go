@@ -0,0 +1,124 @@
+package queryrange
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/pkg/loghttp"
+	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/querier/queryrange/queryrangebase"
+	"github.com/grafana/loki/pkg/storage/chunk/cache"
+	"github.com/grafana/loki/pkg/util"
+)
+
+func TestCacheKeyDetectedFields_GenerateCacheKey(t *testing.T) {
+	k := cacheKeyDetectedFields{cacheKeyLimits{
+		Limits: fakeLimits{
+			splitDuration: map[string]time.Duration{
+				"fake": time.Hour,
+			},
+		},
+	}}
+
+	from, through := util.RoundToMilliseconds(testTime, testTime.Add(1*time.Hour))
+	req := &logproto.DetectedFieldsRequest{
+		Query:      `{app="foo"}`,
+		Start:      from,
+		End:        through,
+		LineLimit:  100,
+		FieldLimit: 10,
+	}
+
+	expectedInterval := testTime.UnixMilli() / time.Hour.Milliseconds()
+	require.Equal(t,
+		fmt.Sprintf(`detected_fields:fake:{app="foo"}:0:%d:%d:100:10`, expectedInterval, time.Hour.Nanoseconds()),
+		k.GenerateCacheKey(context.Background(), "fake", req),
+	)
+}
+
+func TestDetectedFieldsCache(t *testing.T) {
+	cacheMiddleware, err := NewDetectedFieldsCacheMiddleware(
+		log.NewNopLogger(),
+		fakeLimits{
+			splitDuration: map[string]time.Duration{
+				"fake": 24 * time.Hour,
+			},
+		},
+		DefaultCodec,
+		cache.NewMockCache(),
+		nil,
+		nil,
+		nil,
+		func(_ context.Context, _ []string, _ queryrangebase.Request) int {
+			return 1
+		},
+		false,
+		nil,
+		nil,
+	)
+	require.NoError(t, err)
+
+	start := testTime.Truncate(time.Millisecond)
+	req := &logproto.DetectedFieldsRequest{
+		Query:      `{app="foo"}`,
+		Start:      model.TimeFromUnixNano(start.UnixNano()),
+		End:        model.TimeFromUnixNano(start.Add(time.Hour).UnixNano()),
+		LineLimit:  100,
+		FieldLimit: 10,
+	}
+	resp := &DetectedFieldsResponse{
+		Response: &logproto.DetectedFieldsResponse{
+			Fields: []*logproto.DetectedField{
+				{Label: "status", Type: "int", Cardinality: 3, Parsers: []string{"logfmt"}},
+			},
+			FieldLimit: 10,
+		},
+	}
+
+	var lastReq queryrangebase.Request
+	downstreamHandler := &mockDownstreamHandler{fn: func(_ context.Context, r queryrangebase.Request) (queryrangebase.Response, error) {
+		lastReq = r
+		return resp, nil
+	}}
+	handler := cacheMiddleware.Wrap(downstreamHandler)
+
+	ctx := user.InjectOrgID(context.Background(), "fake")
+	got, err := handler.Do(ctx, req)
+	require.NoError(t, err)
+	require.Equal(t, 1, downstreamHandler.Called())
+	require.Equal(t, req, lastReq)
+	require.Equal(t, resp, got)
+
+	// the second request is served from the cache, merging the cached
+	// extents adds the sketches of the cardinalities.
+	got, err = handler.Do(ctx, req)
+	require.NoError(t, err)
+	require.Equal(t, 1, downstreamHandler.Called())
+	require.Equal(t, loghttp.NewDetectedFieldsResponse(resp.Response), loghttp.NewDetectedFieldsResponse(got.(*DetectedFieldsResponse).Response))
+
+	// a narrower request can't use the fields detected over the whole cached extent.
+	narrower := *req
+	narrower.Start = model.TimeFromUnixNano(start.Add(30 * time.Minute).UnixNano())
+	_, err = handler.Do(ctx, &narrower)
+	require.NoError(t, err)
+	require.Equal(t, 2, downstreamHandler.Called())
+	require.Equal(t, &narrower, lastReq)
+}
+
+func TestDetectedFieldsExtractor_Extract(t *testing.T) {
+	resp := &DetectedFieldsResponse{
+		Response: &logproto.DetectedFieldsResponse{
+			Fields: []*logproto.DetectedField{{Label: "status", Type: "int", Cardinality: 3}},
+		},
+	}
+	require.Equal(t, resp, detectedFieldsExtractor{}.Extract(0, 100, resp, 10, 90))
+	require.Empty(t, detectedFieldsExtractor{}.Extract(20, 100, resp, 10, 90).(*DetectedFieldsResponse).Response.Fields)
+	require.Empty(t, detectedFieldsExtractor{}.Extract(0, 80, resp, 10, 90).(*DetectedFieldsResponse).Response.Fields)
+}
@@ -57,11 +57,17 @@ func (i cacheKeyLabels) GenerateCacheKey(ctx context.Context, userID string, r r
 		userID = i.transformer(ctx, userID)
 	}
 
+	// structured metadata names and values are cached apart from the labels ones.
+	var structuredMetadata string
+	if lr.GetStructuredMetadata() {
+		structuredMetadata = ":structured_metadata"
+	}
+
 	if lr.GetValues() {
-		return fmt.Sprintf("labelvalues:%s:%s:%s:%d:%d", userID, lr.GetName(), lr.GetQuery(), currentInterval, split)
+		return fmt.Sprintf("labelvalues%s:%s:%s:%s:%d:%d", structuredMetadata, userID, lr.GetName(), lr.GetQuery(), currentInterval, split)
 	}
 
-	return fmt.Sprintf("labels:%s:%d:%d", userID, currentInterval, split)
+	return fmt.Sprintf("labels%s:%s:%d:%d", structuredMetadata, userID, currentInterval, split)
 }
 
 type labelsExtractor struct{}
@@ -55,6 +55,16 @@ func TestCacheKeyLabels_GenerateCacheKey(t *testing.T) {
 		req.Query = `{cluster="eu-west1"}`
 		require.Equal(t, fmt.Sprintf(`labelvalues:fake:foo:{cluster="eu-west1"}:%d:%d`, expectedInterval, time.Hour.Nanoseconds()), k.GenerateCacheKey(context.Background(), "fake", &req))
 	})
+
+	t.Run("structured metadata", func(t *testing.T) {
+		req := req
+		req.StructuredMetadata = true
+		require.Equal(t, fmt.Sprintf(`labels:structured_metadata:fake:%d:%d`, expectedInterval, time.Hour.Nanoseconds()), k.GenerateCacheKey(context.Background(), "fake", &req))
+
+		req.Name = "trace_id"
+		req.Values = true
+		require.Equal(t, fmt.Sprintf(`labelvalues:structured_metadata:fake:trace_id::%d:%d`, expectedInterval, time.Hour.Nanoseconds()), k.GenerateCacheKey(context.Background(), "fake", &req))
+	})
 }
 
 func TestLabelsCache(t *testing.T) {
@@ -39,6 +39,7 @@ import (
 const (
 	limitErrTmpl                             = "maximum of series (%d) reached for a single query"
 	maxSeriesErrTmpl                         = "max entries limit per query exceeded, limit > max_entries_limit (%d > %d)"
+	maxStreamedEntriesErrTmpl                = "max streamed entries limit per query reached (%d), the results might be incomplete; consider reducing the time range of the query"
 	requiredLabelsErrTmpl                    = "stream selector is missing required matchers [%s], labels present in the query were [%s]"
 	requiredNumberLabelsErrTmpl              = "stream selector has less label matchers than required: (present: [%s], number_present: %d, required_number_label_matchers: %d)"
 	limErrQueryTooManyBytesTmpl              = "the query would read too many bytes (query: %s, limit: %s); consider adding more specific stream selectors or reduce the time range of the query"
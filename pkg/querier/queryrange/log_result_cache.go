@@ -261,7 +261,11 @@ func (l *logResultCache) handleHit(ctx context.Context, cacheKey string, cachedR
 				if startResp.Status != loghttp.QueryStatusSuccess {
 					return startResp, nil
 				}
-				result = mergeLokiResponse(startResp, result)
+				merged, err := mergeLokiResponseWithLineLimits(lineLimits(lokiReq), startResp, result)
+				if err != nil {
+					return nil, err
+				}
+				result = merged
 			}
 		}
 
@@ -275,7 +279,11 @@ func (l *logResultCache) handleHit(ctx context.Context, cacheKey string, cachedR
 				if endResp.Status != loghttp.QueryStatusSuccess {
 					return endResp, nil
 				}
-				result = mergeLokiResponse(endResp, result)
+				merged, err := mergeLokiResponseWithLineLimits(lineLimits(lokiReq), endResp, result)
+				if err != nil {
+					return nil, err
+				}
+				result = merged
 			}
 		}
 	}
@@ -124,6 +124,9 @@ func ResultToResponse(result logqlmodel.Result, params logql.Params) (queryrange
 		r := data.ToProto()
 		data.Release()
 		return &QuantileSketchResponse{Response: r}, nil
+	case logql.ProbabilisticCountDistinctMatrix:
+		r, err := data.ToProto()
+		return &CountDistinctSketchResponse{Response: r}, err
 	}
 
 	return nil, fmt.Errorf("unsupported data type: %T", result.Data)
@@ -183,6 +186,15 @@ func ResponseToResult(resp queryrangebase.Response) (logqlmodel.Result, error) {
 			Data:    matrix,
 			Headers: resp.GetHeaders(),
 		}, nil
+	case *CountDistinctSketchResponse:
+		matrix, err := logql.ProbabilisticCountDistinctMatrixFromProto(r.Response)
+		if err != nil {
+			return logqlmodel.Result{}, fmt.Errorf("cannot decode count distinct sketch: %w", err)
+		}
+		return logqlmodel.Result{
+			Data:    matrix,
+			Headers: resp.GetHeaders(),
+		}, nil
 	default:
 		return logqlmodel.Result{}, fmt.Errorf("cannot decode (%T)", resp)
 	}
@@ -212,6 +224,12 @@ func QueryResponseUnwrap(res *QueryResponse) (queryrangebase.Response, error) {
 		return concrete.TopkSketches, nil
 	case *QueryResponse_QuantileSketches:
 		return concrete.QuantileSketches, nil
+	case *QueryResponse_CountDistinctSketches:
+		return concrete.CountDistinctSketches, nil
+	case *QueryResponse_PatternsResponse:
+		return concrete.PatternsResponse, nil
+	case *QueryResponse_DetectedFields:
+		return concrete.DetectedFields, nil
 	default:
 		return nil, fmt.Errorf("unsupported QueryResponse response type, got (%T)", res.Response)
 	}
@@ -245,8 +263,14 @@ func QueryResponseWrap(res queryrangebase.Response) (*QueryResponse, error) {
 		p.Response = &QueryResponse_TopkSketches{response}
 	case *QuantileSketchResponse:
 		p.Response = &QueryResponse_QuantileSketches{response}
+	case *CountDistinctSketchResponse:
+		p.Response = &QueryResponse_CountDistinctSketches{response}
 	case *ShardsResponse:
 		p.Response = &QueryResponse_ShardsResponse{response}
+	case *QueryPatternsResponse:
+		p.Response = &QueryResponse_PatternsResponse{response}
+	case *DetectedFieldsResponse:
+		p.Response = &QueryResponse_DetectedFields{response}
 	default:
 		return nil, fmt.Errorf("invalid response format, got (%T)", res)
 	}
@@ -319,6 +343,10 @@ func (Codec) QueryRequestUnwrap(ctx context.Context, req *QueryRequest) (queryra
 		return concrete.ShardsRequest, ctx, nil
 	case *QueryRequest_Volume:
 		return concrete.Volume, ctx, nil
+	case *QueryRequest_PatternsRequest:
+		return concrete.PatternsRequest, ctx, nil
+	case *QueryRequest_DetectedFields:
+		return concrete.DetectedFields, ctx, nil
 	case *QueryRequest_Streams:
 		if concrete.Streams.Plan == nil {
 			parsed, err := syntax.ParseExpr(concrete.Streams.GetQuery())
@@ -361,6 +389,10 @@ func (Codec) QueryRequestWrap(ctx context.Context, r queryrangebase.Request) (*Q
 		result.Request = &QueryRequest_Streams{Streams: req}
 	case *logproto.ShardsRequest:
 		result.Request = &QueryRequest_ShardsRequest{ShardsRequest: req}
+	case *logproto.QueryPatternsRequest:
+		result.Request = &QueryRequest_PatternsRequest{PatternsRequest: req}
+	case *logproto.DetectedFieldsRequest:
+		result.Request = &QueryRequest_DetectedFields{DetectedFields: req}
 	default:
 		return nil, fmt.Errorf("unsupported request type while wrapping, got (%T)", r)
 	}
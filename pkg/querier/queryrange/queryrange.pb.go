@@ -831,6 +831,43 @@ func (m *QuantileSketchResponse) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_QuantileSketchResponse proto.InternalMessageInfo
 
+type CountDistinctSketchResponse struct {
+	Response *github_com_grafana_loki_pkg_logproto.CountDistinctSketchMatrix                                      `protobuf:"bytes,1,opt,name=response,proto3,customtype=github.com/grafana/loki/pkg/logproto.CountDistinctSketchMatrix" json:"response,omitempty"`
+	Headers  []github_com_grafana_loki_pkg_querier_queryrange_queryrangebase_definitions.PrometheusResponseHeader `protobuf:"bytes,2,rep,name=Headers,proto3,customtype=github.com/grafana/loki/pkg/querier/queryrange/queryrangebase/definitions.PrometheusResponseHeader" json:"-"`
+}
+
+func (m *CountDistinctSketchResponse) Reset()      { *m = CountDistinctSketchResponse{} }
+func (*CountDistinctSketchResponse) ProtoMessage() {}
+func (*CountDistinctSketchResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_51b9d53b40d11902, []int{13}
+}
+func (m *CountDistinctSketchResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *CountDistinctSketchResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_CountDistinctSketchResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *CountDistinctSketchResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CountDistinctSketchResponse.Merge(m, src)
+}
+func (m *CountDistinctSketchResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *CountDistinctSketchResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CountDistinctSketchResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CountDistinctSketchResponse proto.InternalMessageInfo
+
 type ShardsResponse struct {
 	Response *github_com_grafana_loki_pkg_logproto.ShardsResponse                                                 `protobuf:"bytes,1,opt,name=response,proto3,customtype=github.com/grafana/loki/pkg/logproto.ShardsResponse" json:"response,omitempty"`
 	Headers  []github_com_grafana_loki_pkg_querier_queryrange_queryrangebase_definitions.PrometheusResponseHeader `protobuf:"bytes,2,rep,name=Headers,proto3,customtype=github.com/grafana/loki/pkg/querier/queryrange/queryrangebase/definitions.PrometheusResponseHeader" json:"-"`
@@ -839,7 +876,7 @@ type ShardsResponse struct {
 func (m *ShardsResponse) Reset()      { *m = ShardsResponse{} }
 func (*ShardsResponse) ProtoMessage() {}
 func (*ShardsResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_51b9d53b40d11902, []int{13}
+	return fileDescriptor_51b9d53b40d11902, []int{14}
 }
 func (m *ShardsResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -868,6 +905,80 @@ func (m *ShardsResponse) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_ShardsResponse proto.InternalMessageInfo
 
+type QueryPatternsResponse struct {
+	Response *github_com_grafana_loki_pkg_logproto.QueryPatternsResponse                                          `protobuf:"bytes,1,opt,name=response,proto3,customtype=github.com/grafana/loki/pkg/logproto.QueryPatternsResponse" json:"response,omitempty"`
+	Headers  []github_com_grafana_loki_pkg_querier_queryrange_queryrangebase_definitions.PrometheusResponseHeader `protobuf:"bytes,2,rep,name=Headers,proto3,customtype=github.com/grafana/loki/pkg/querier/queryrange/queryrangebase/definitions.PrometheusResponseHeader" json:"-"`
+}
+
+func (m *QueryPatternsResponse) Reset()      { *m = QueryPatternsResponse{} }
+func (*QueryPatternsResponse) ProtoMessage() {}
+func (*QueryPatternsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_51b9d53b40d11902, []int{15}
+}
+func (m *QueryPatternsResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *QueryPatternsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_QueryPatternsResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *QueryPatternsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryPatternsResponse.Merge(m, src)
+}
+func (m *QueryPatternsResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *QueryPatternsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryPatternsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueryPatternsResponse proto.InternalMessageInfo
+
+type DetectedFieldsResponse struct {
+	Response *github_com_grafana_loki_pkg_logproto.DetectedFieldsResponse                                         `protobuf:"bytes,1,opt,name=response,proto3,customtype=github.com/grafana/loki/pkg/logproto.DetectedFieldsResponse" json:"response,omitempty"`
+	Headers  []github_com_grafana_loki_pkg_querier_queryrange_queryrangebase_definitions.PrometheusResponseHeader `protobuf:"bytes,2,rep,name=Headers,proto3,customtype=github.com/grafana/loki/pkg/querier/queryrange/queryrangebase/definitions.PrometheusResponseHeader" json:"-"`
+}
+
+func (m *DetectedFieldsResponse) Reset()      { *m = DetectedFieldsResponse{} }
+func (*DetectedFieldsResponse) ProtoMessage() {}
+func (*DetectedFieldsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_51b9d53b40d11902, []int{16}
+}
+func (m *DetectedFieldsResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *DetectedFieldsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_DetectedFieldsResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *DetectedFieldsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DetectedFieldsResponse.Merge(m, src)
+}
+func (m *DetectedFieldsResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *DetectedFieldsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DetectedFieldsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DetectedFieldsResponse proto.InternalMessageInfo
+
 type QueryResponse struct {
 	Status *rpc.Status `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
 	// Types that are valid to be assigned to Response:
@@ -880,13 +991,16 @@ type QueryResponse struct {
 	//	*QueryResponse_TopkSketches
 	//	*QueryResponse_QuantileSketches
 	//	*QueryResponse_ShardsResponse
+	//	*QueryResponse_CountDistinctSketches
+	//	*QueryResponse_PatternsResponse
+	//	*QueryResponse_DetectedFields
 	Response isQueryResponse_Response `protobuf_oneof:"response"`
 }
 
 func (m *QueryResponse) Reset()      { *m = QueryResponse{} }
 func (*QueryResponse) ProtoMessage() {}
 func (*QueryResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_51b9d53b40d11902, []int{14}
+	return fileDescriptor_51b9d53b40d11902, []int{17}
 }
 func (m *QueryResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -949,16 +1063,28 @@ type QueryResponse_QuantileSketches struct {
 type QueryResponse_ShardsResponse struct {
 	ShardsResponse *ShardsResponse `protobuf:"bytes,10,opt,name=shardsResponse,proto3,oneof"`
 }
+type QueryResponse_CountDistinctSketches struct {
+	CountDistinctSketches *CountDistinctSketchResponse `protobuf:"bytes,11,opt,name=countDistinctSketches,proto3,oneof"`
+}
+type QueryResponse_PatternsResponse struct {
+	PatternsResponse *QueryPatternsResponse `protobuf:"bytes,12,opt,name=patternsResponse,proto3,oneof"`
+}
+type QueryResponse_DetectedFields struct {
+	DetectedFields *DetectedFieldsResponse `protobuf:"bytes,13,opt,name=detectedFields,proto3,oneof"`
+}
 
-func (*QueryResponse_Series) isQueryResponse_Response()           {}
-func (*QueryResponse_Labels) isQueryResponse_Response()           {}
-func (*QueryResponse_Stats) isQueryResponse_Response()            {}
-func (*QueryResponse_Prom) isQueryResponse_Response()             {}
-func (*QueryResponse_Streams) isQueryResponse_Response()          {}
-func (*QueryResponse_Volume) isQueryResponse_Response()           {}
-func (*QueryResponse_TopkSketches) isQueryResponse_Response()     {}
-func (*QueryResponse_QuantileSketches) isQueryResponse_Response() {}
-func (*QueryResponse_ShardsResponse) isQueryResponse_Response()   {}
+func (*QueryResponse_Series) isQueryResponse_Response()                {}
+func (*QueryResponse_Labels) isQueryResponse_Response()                {}
+func (*QueryResponse_Stats) isQueryResponse_Response()                 {}
+func (*QueryResponse_Prom) isQueryResponse_Response()                  {}
+func (*QueryResponse_Streams) isQueryResponse_Response()               {}
+func (*QueryResponse_Volume) isQueryResponse_Response()                {}
+func (*QueryResponse_TopkSketches) isQueryResponse_Response()          {}
+func (*QueryResponse_QuantileSketches) isQueryResponse_Response()      {}
+func (*QueryResponse_ShardsResponse) isQueryResponse_Response()        {}
+func (*QueryResponse_CountDistinctSketches) isQueryResponse_Response() {}
+func (*QueryResponse_PatternsResponse) isQueryResponse_Response()      {}
+func (*QueryResponse_DetectedFields) isQueryResponse_Response()        {}
 
 func (m *QueryResponse) GetResponse() isQueryResponse_Response {
 	if m != nil {
@@ -1037,6 +1163,27 @@ func (m *QueryResponse) GetShardsResponse() *ShardsResponse {
 	return nil
 }
 
+func (m *QueryResponse) GetCountDistinctSketches() *CountDistinctSketchResponse {
+	if x, ok := m.GetResponse().(*QueryResponse_CountDistinctSketches); ok {
+		return x.CountDistinctSketches
+	}
+	return nil
+}
+
+func (m *QueryResponse) GetPatternsResponse() *QueryPatternsResponse {
+	if x, ok := m.GetResponse().(*QueryResponse_PatternsResponse); ok {
+		return x.PatternsResponse
+	}
+	return nil
+}
+
+func (m *QueryResponse) GetDetectedFields() *DetectedFieldsResponse {
+	if x, ok := m.GetResponse().(*QueryResponse_DetectedFields); ok {
+		return x.DetectedFields
+	}
+	return nil
+}
+
 // XXX_OneofWrappers is for the internal use of the proto package.
 func (*QueryResponse) XXX_OneofWrappers() []interface{} {
 	return []interface{}{
@@ -1049,6 +1196,9 @@ func (*QueryResponse) XXX_OneofWrappers() []interface{} {
 		(*QueryResponse_TopkSketches)(nil),
 		(*QueryResponse_QuantileSketches)(nil),
 		(*QueryResponse_ShardsResponse)(nil),
+		(*QueryResponse_CountDistinctSketches)(nil),
+		(*QueryResponse_PatternsResponse)(nil),
+		(*QueryResponse_DetectedFields)(nil),
 	}
 }
 
@@ -1061,6 +1211,8 @@ type QueryRequest struct {
 	//	*QueryRequest_Streams
 	//	*QueryRequest_Volume
 	//	*QueryRequest_ShardsRequest
+	//	*QueryRequest_PatternsRequest
+	//	*QueryRequest_DetectedFields
 	Request  isQueryRequest_Request `protobuf_oneof:"request"`
 	Metadata map[string]string      `protobuf:"bytes,7,rep,name=metadata,proto3" json:"metadata" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
 }
@@ -1068,7 +1220,7 @@ type QueryRequest struct {
 func (m *QueryRequest) Reset()      { *m = QueryRequest{} }
 func (*QueryRequest) ProtoMessage() {}
 func (*QueryRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_51b9d53b40d11902, []int{15}
+	return fileDescriptor_51b9d53b40d11902, []int{18}
 }
 func (m *QueryRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1125,14 +1277,22 @@ type QueryRequest_Volume struct {
 type QueryRequest_ShardsRequest struct {
 	ShardsRequest *logproto.ShardsRequest `protobuf:"bytes,8,opt,name=shardsRequest,proto3,oneof"`
 }
+type QueryRequest_PatternsRequest struct {
+	PatternsRequest *logproto.QueryPatternsRequest `protobuf:"bytes,9,opt,name=patternsRequest,proto3,oneof"`
+}
+type QueryRequest_DetectedFields struct {
+	DetectedFields *logproto.DetectedFieldsRequest `protobuf:"bytes,10,opt,name=detectedFields,proto3,oneof"`
+}
 
-func (*QueryRequest_Series) isQueryRequest_Request()        {}
-func (*QueryRequest_Labels) isQueryRequest_Request()        {}
-func (*QueryRequest_Stats) isQueryRequest_Request()         {}
-func (*QueryRequest_Instant) isQueryRequest_Request()       {}
-func (*QueryRequest_Streams) isQueryRequest_Request()       {}
-func (*QueryRequest_Volume) isQueryRequest_Request()        {}
-func (*QueryRequest_ShardsRequest) isQueryRequest_Request() {}
+func (*QueryRequest_Series) isQueryRequest_Request()          {}
+func (*QueryRequest_Labels) isQueryRequest_Request()          {}
+func (*QueryRequest_Stats) isQueryRequest_Request()           {}
+func (*QueryRequest_Instant) isQueryRequest_Request()         {}
+func (*QueryRequest_Streams) isQueryRequest_Request()         {}
+func (*QueryRequest_Volume) isQueryRequest_Request()          {}
+func (*QueryRequest_ShardsRequest) isQueryRequest_Request()   {}
+func (*QueryRequest_PatternsRequest) isQueryRequest_Request() {}
+func (*QueryRequest_DetectedFields) isQueryRequest_Request()  {}
 
 func (m *QueryRequest) GetRequest() isQueryRequest_Request {
 	if m != nil {
@@ -1190,6 +1350,20 @@ func (m *QueryRequest) GetShardsRequest() *logproto.ShardsRequest {
 	return nil
 }
 
+func (m *QueryRequest) GetPatternsRequest() *logproto.QueryPatternsRequest {
+	if x, ok := m.GetRequest().(*QueryRequest_PatternsRequest); ok {
+		return x.PatternsRequest
+	}
+	return nil
+}
+
+func (m *QueryRequest) GetDetectedFields() *logproto.DetectedFieldsRequest {
+	if x, ok := m.GetRequest().(*QueryRequest_DetectedFields); ok {
+		return x.DetectedFields
+	}
+	return nil
+}
+
 func (m *QueryRequest) GetMetadata() map[string]string {
 	if m != nil {
 		return m.Metadata
@@ -1207,6 +1381,8 @@ func (*QueryRequest) XXX_OneofWrappers() []interface{} {
 		(*QueryRequest_Streams)(nil),
 		(*QueryRequest_Volume)(nil),
 		(*QueryRequest_ShardsRequest)(nil),
+		(*QueryRequest_PatternsRequest)(nil),
+		(*QueryRequest_DetectedFields)(nil),
 	}
 }
 
@@ -1224,7 +1400,10 @@ func init() {
 	proto.RegisterType((*VolumeResponse)(nil), "queryrange.VolumeResponse")
 	proto.RegisterType((*TopKSketchesResponse)(nil), "queryrange.TopKSketchesResponse")
 	proto.RegisterType((*QuantileSketchResponse)(nil), "queryrange.QuantileSketchResponse")
+	proto.RegisterType((*CountDistinctSketchResponse)(nil), "queryrange.CountDistinctSketchResponse")
 	proto.RegisterType((*ShardsResponse)(nil), "queryrange.ShardsResponse")
+	proto.RegisterType((*QueryPatternsResponse)(nil), "queryrange.QueryPatternsResponse")
+	proto.RegisterType((*DetectedFieldsResponse)(nil), "queryrange.DetectedFieldsResponse")
 	proto.RegisterType((*QueryResponse)(nil), "queryrange.QueryResponse")
 	proto.RegisterType((*QueryRequest)(nil), "queryrange.QueryRequest")
 	proto.RegisterMapType((map[string]string)(nil), "queryrange.QueryRequest.MetadataEntry")
@@ -1235,107 +1414,121 @@ func init() {
 }
 
 var fileDescriptor_51b9d53b40d11902 = []byte{
-	// 1586 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xec, 0x58, 0xcb, 0x6f, 0x1b, 0x45,
-	0x18, 0xf7, 0xfa, 0x19, 0x4f, 0x9a, 0x10, 0x26, 0x51, 0xba, 0xa4, 0xed, 0xae, 0x65, 0x89, 0x36,
-	0x20, 0x58, 0xd3, 0xa4, 0xf4, 0x09, 0x88, 0x2e, 0x69, 0xe5, 0x8a, 0x16, 0xb5, 0x9b, 0x88, 0x03,
-	0xe2, 0x32, 0x89, 0x27, 0xce, 0x92, 0x7d, 0x65, 0x67, 0x9c, 0x36, 0x37, 0xfe, 0x00, 0x90, 0xfa,
-	0x57, 0x20, 0x24, 0xaa, 0x9e, 0x38, 0x71, 0xe4, 0x42, 0x8f, 0x3d, 0x56, 0x91, 0x30, 0x34, 0xbd,
-	0xa0, 0x9c, 0x7a, 0xe3, 0x8a, 0xe6, 0xb1, 0xeb, 0x59, 0xdb, 0x69, 0x9d, 0x22, 0xa4, 0x46, 0xe2,
-	0x62, 0xcf, 0xe3, 0xfb, 0xcd, 0xce, 0xfe, 0xbe, 0xdf, 0xf7, 0xed, 0x37, 0x03, 0xce, 0x44, 0x9b,
-	0xed, 0xc6, 0x56, 0x07, 0xc7, 0x2e, 0x8e, 0xf9, 0xff, 0x4e, 0x8c, 0x82, 0x36, 0x56, 0x9a, 0x56,
-	0x14, 0x87, 0x34, 0x84, 0xa0, 0x37, 0x32, 0xb7, 0xd0, 0x76, 0xe9, 0x46, 0x67, 0xd5, 0x5a, 0x0b,
-	0xfd, 0x46, 0x3b, 0x6c, 0x87, 0x8d, 0x76, 0x18, 0xb6, 0x3d, 0x8c, 0x22, 0x97, 0xc8, 0x66, 0x23,
-	0x8e, 0xd6, 0x1a, 0x84, 0x22, 0xda, 0x21, 0x02, 0x3f, 0x37, 0xc3, 0x0c, 0x79, 0x93, 0x43, 0xe4,
-	0xa8, 0x29, 0xcd, 0x79, 0x6f, 0xb5, 0xb3, 0xde, 0xa0, 0xae, 0x8f, 0x09, 0x45, 0x7e, 0x94, 0x18,
-	0xb0, 0xfd, 0x79, 0x61, 0x5b, 0x20, 0xdd, 0xa0, 0x85, 0xef, 0xb5, 0x11, 0xc5, 0x77, 0xd1, 0x8e,
-	0x34, 0x38, 0x91, 0x31, 0x48, 0x1a, 0x72, 0xf2, 0xad, 0xcc, 0x24, 0xd9, 0xc4, 0x74, 0x6d, 0x43,
-	0x4e, 0xd5, 0xe4, 0xd4, 0x96, 0xe7, 0x87, 0x2d, 0xec, 0xf1, 0xcd, 0x12, 0xf1, 0x2b, 0x2d, 0xa6,
-	0x99, 0x45, 0xd4, 0x21, 0x1b, 0xfc, 0x47, 0x0e, 0x7e, 0xf6, 0x52, 0xbe, 0x56, 0x11, 0xc1, 0x8d,
-	0x16, 0x5e, 0x77, 0x03, 0x97, 0xba, 0x61, 0x40, 0xd4, 0xb6, 0x5c, 0xe4, 0xfc, 0x68, 0x8b, 0xf4,
-	0xfb, 0xa0, 0xfe, 0xb0, 0x00, 0xc6, 0x6f, 0x86, 0x9b, 0xae, 0x83, 0xb7, 0x3a, 0x98, 0x50, 0x38,
-	0x03, 0x4a, 0xdc, 0x46, 0xd7, 0x6a, 0xda, 0x7c, 0xd5, 0x11, 0x1d, 0x36, 0xea, 0xb9, 0xbe, 0x4b,
-	0xf5, 0x7c, 0x4d, 0x9b, 0x9f, 0x70, 0x44, 0x07, 0x42, 0x50, 0x24, 0x14, 0x47, 0x7a, 0xa1, 0xa6,
-	0xcd, 0x17, 0x1c, 0xde, 0x86, 0x73, 0x60, 0xcc, 0x0d, 0x28, 0x8e, 0xb7, 0x91, 0xa7, 0x57, 0xf9,
-	0x78, 0xda, 0x87, 0x9f, 0x80, 0x0a, 0xa1, 0x28, 0xa6, 0x2b, 0x44, 0x2f, 0xd6, 0xb4, 0xf9, 0xf1,
-	0x85, 0x39, 0x4b, 0xf8, 0xca, 0x4a, 0x7c, 0x65, 0xad, 0x24, 0xbe, 0xb2, 0xc7, 0x1e, 0x75, 0xcd,
-	0xdc, 0xfd, 0x3f, 0x4c, 0xcd, 0x49, 0x40, 0xf0, 0x32, 0x28, 0xe1, 0xa0, 0xb5, 0x42, 0xf4, 0xd2,
-	0x21, 0xd0, 0x02, 0x02, 0xcf, 0x82, 0x6a, 0xcb, 0x8d, 0xf1, 0x1a, 0xe3, 0x4c, 0x2f, 0xd7, 0xb4,
-	0xf9, 0xc9, 0x85, 0x69, 0x2b, 0x75, 0xed, 0x52, 0x32, 0xe5, 0xf4, 0xac, 0xd8, 0xeb, 0x45, 0x88,
-	0x6e, 0xe8, 0x15, 0xce, 0x04, 0x6f, 0xc3, 0x3a, 0x28, 0x93, 0x0d, 0x14, 0xb7, 0x88, 0x3e, 0x56,
-	0x2b, 0xcc, 0x57, 0x6d, 0xb0, 0xdf, 0x35, 0xe5, 0x88, 0x23, 0xff, 0xe1, 0xd7, 0xa0, 0x18, 0x79,
-	0x28, 0xd0, 0x01, 0xdf, 0xe5, 0x94, 0xa5, 0x70, 0x7e, 0xdb, 0x43, 0x81, 0x7d, 0x7e, 0xb7, 0x6b,
-	0x66, 0xe4, 0x1e, 0xa3, 0x75, 0x14, 0xa0, 0x86, 0x17, 0x6e, 0xba, 0x0d, 0xd5, 0x8d, 0x6c, 0x15,
-	0xeb, 0x0e, 0x43, 0x33, 0x9c, 0xc3, 0x57, 0xad, 0xff, 0x96, 0x07, 0x90, 0x39, 0xec, 0x46, 0x40,
-	0x28, 0x0a, 0xe8, 0xab, 0xf8, 0xed, 0x23, 0x50, 0x66, 0x31, 0xb1, 0x42, 0xb8, 0xe7, 0x46, 0x25,
-	0x52, 0x62, 0xb2, 0x4c, 0x16, 0x0f, 0xc5, 0x64, 0x69, 0x28, 0x93, 0xe5, 0x97, 0x32, 0x59, 0xf9,
-	0x4f, 0x98, 0xd4, 0x41, 0x91, 0xf5, 0xe0, 0x14, 0x28, 0xc4, 0xe8, 0x2e, 0x27, 0xee, 0x98, 0xc3,
-	0x9a, 0xf5, 0x9f, 0x8a, 0xe0, 0x98, 0x08, 0x0a, 0x12, 0x85, 0x01, 0xc1, 0x6c, 0xb3, 0xcb, 0x3c,
-	0xf3, 0x08, 0x7a, 0xe5, 0x66, 0xf9, 0x88, 0x23, 0x67, 0xe0, 0xa7, 0xa0, 0xb8, 0x84, 0x28, 0xe2,
-	0x54, 0x8f, 0x2f, 0xcc, 0xa8, 0x9b, 0x65, 0x6b, 0xb1, 0x39, 0x7b, 0x96, 0xb1, 0xb9, 0xdf, 0x35,
-	0x27, 0x5b, 0x88, 0xa2, 0xf7, 0x42, 0xdf, 0xa5, 0xd8, 0x8f, 0xe8, 0x8e, 0xc3, 0x91, 0xf0, 0x43,
-	0x50, 0xbd, 0x16, 0xc7, 0x61, 0xbc, 0xb2, 0x13, 0x61, 0xee, 0x9a, 0xaa, 0x7d, 0x7c, 0xbf, 0x6b,
-	0x4e, 0xe3, 0x64, 0x50, 0x41, 0xf4, 0x2c, 0xe1, 0x3b, 0xa0, 0xc4, 0x3b, 0xdc, 0x19, 0x55, 0x7b,
-	0x7a, 0xbf, 0x6b, 0xbe, 0xc1, 0x21, 0x8a, 0xb9, 0xb0, 0xc8, 0xfa, 0xae, 0x34, 0x92, 0xef, 0x52,
-	0x09, 0x95, 0x55, 0x09, 0xe9, 0xa0, 0xb2, 0x8d, 0x63, 0xc2, 0x96, 0xa9, 0xf0, 0xf1, 0xa4, 0x0b,
-	0xaf, 0x02, 0xc0, 0x88, 0x71, 0x09, 0x75, 0xd7, 0x58, 0x94, 0x30, 0x32, 0x26, 0x2c, 0x91, 0x04,
-	0x1d, 0x4c, 0x3a, 0x1e, 0xb5, 0xa1, 0x64, 0x41, 0x31, 0x74, 0x94, 0x36, 0x7c, 0xa0, 0x81, 0x4a,
-	0x13, 0xa3, 0x16, 0x8e, 0x89, 0x5e, 0xad, 0x15, 0xe6, 0xc7, 0x17, 0xde, 0xb6, 0xd4, 0x8c, 0x77,
-	0x3b, 0x0e, 0x7d, 0x4c, 0x37, 0x70, 0x87, 0x24, 0x0e, 0x12, 0xd6, 0xf6, 0xe6, 0x6e, 0xd7, 0x5c,
-	0x1d, 0x45, 0x0f, 0x23, 0x65, 0xd9, 0x03, 0x9f, 0xb3, 0xdf, 0x35, 0xb5, 0xf7, 0x9d, 0x64, 0x8b,
-	0xf5, 0xdf, 0x35, 0xf0, 0x26, 0xf3, 0xf0, 0x32, 0x5b, 0x9b, 0x28, 0x01, 0xe9, 0x23, 0xba, 0xb6,
-	0xa1, 0x6b, 0x4c, 0xde, 0x8e, 0xe8, 0xa8, 0x29, 0x30, 0xff, 0xaf, 0x52, 0x60, 0xe1, 0xf0, 0x29,
-	0x30, 0x89, 0xc2, 0xe2, 0xd0, 0x28, 0x2c, 0x1d, 0x14, 0x85, 0xf5, 0xef, 0x0a, 0x22, 0xe3, 0x24,
-	0xef, 0x77, 0x88, 0x98, 0xb8, 0x9e, 0xc6, 0x44, 0x81, 0xef, 0x36, 0x95, 0x9a, 0x58, 0xeb, 0x46,
-	0x0b, 0x07, 0xd4, 0x5d, 0x77, 0x71, 0xfc, 0x92, 0xc8, 0x50, 0xe4, 0x56, 0xc8, 0xca, 0x4d, 0xd5,
-	0x4a, 0xf1, 0xb5, 0xd7, 0x4a, 0x5f, 0x74, 0x94, 0x5e, 0x21, 0x3a, 0xea, 0xcf, 0xf3, 0x60, 0x96,
-	0xb9, 0xe3, 0x26, 0x5a, 0xc5, 0xde, 0x17, 0xc8, 0x3f, 0xa4, 0x4b, 0x4e, 0x2b, 0x2e, 0xa9, 0xda,
-	0xf0, 0x7f, 0xca, 0x47, 0xa0, 0xfc, 0x07, 0x0d, 0x8c, 0x25, 0x39, 0x1c, 0x5a, 0x00, 0x08, 0x18,
-	0x4f, 0xd3, 0x82, 0xe8, 0x49, 0x06, 0x8e, 0xd3, 0x51, 0x47, 0xb1, 0x80, 0xdf, 0x80, 0xb2, 0xe8,
-	0xc9, 0x28, 0x38, 0xae, 0x44, 0x01, 0x8d, 0x31, 0xf2, 0xaf, 0xb6, 0x50, 0x44, 0x71, 0x6c, 0x5f,
-	0x62, 0xbb, 0xd8, 0xed, 0x9a, 0x67, 0x5e, 0x44, 0x11, 0xaf, 0x1b, 0x05, 0x8e, 0x39, 0x57, 0x3c,
-	0xd3, 0x91, 0x4f, 0xa8, 0x7f, 0xaf, 0x81, 0x29, 0xb6, 0x51, 0x46, 0x4d, 0xaa, 0x8a, 0x25, 0x30,
-	0x16, 0xcb, 0x36, 0xdf, 0xee, 0xf8, 0x42, 0xdd, 0xca, 0xd2, 0x3a, 0x84, 0x4a, 0xbb, 0xf8, 0xa8,
-	0x6b, 0x6a, 0x4e, 0x8a, 0x84, 0x8b, 0x19, 0x1a, 0xf3, 0xc3, 0x68, 0x64, 0x90, 0x5c, 0x86, 0xb8,
-	0x5f, 0xf2, 0x00, 0xde, 0x60, 0x05, 0x36, 0x13, 0x5f, 0x4f, 0xa7, 0x9d, 0x81, 0x1d, 0x9d, 0xec,
-	0x91, 0x32, 0x68, 0x6f, 0x5f, 0xd9, 0xed, 0x9a, 0x17, 0x5e, 0xc4, 0xca, 0x0b, 0xc0, 0xca, 0x2b,
-	0xa8, 0xc2, 0xcd, 0xbf, 0xfe, 0xdf, 0x95, 0x87, 0x79, 0x30, 0xf9, 0x65, 0xe8, 0x75, 0x7c, 0x9c,
-	0x12, 0xe7, 0x0f, 0x10, 0xa7, 0xf7, 0x88, 0xcb, 0xda, 0xda, 0x17, 0x76, 0xbb, 0xe6, 0xe2, 0x48,
-	0xa4, 0x65, 0x81, 0x47, 0x97, 0xb0, 0x07, 0x79, 0x30, 0xb3, 0x12, 0x46, 0x9f, 0x2f, 0xf3, 0x43,
-	0x99, 0x92, 0x17, 0xf1, 0x00, 0x6d, 0x33, 0x3d, 0xda, 0x18, 0xe2, 0x16, 0xa2, 0xb1, 0x7b, 0xcf,
-	0x5e, 0xdc, 0xed, 0x9a, 0x8d, 0x91, 0x28, 0xeb, 0x81, 0x8e, 0x2e, 0x5d, 0xbf, 0xe6, 0xc1, 0xec,
-	0x9d, 0x0e, 0x0a, 0xa8, 0xeb, 0x61, 0x41, 0x59, 0x4a, 0xd8, 0xce, 0x00, 0x61, 0x46, 0x8f, 0xb0,
-	0x2c, 0x46, 0x52, 0xf7, 0xf1, 0x6e, 0xd7, 0xbc, 0x34, 0x12, 0x75, 0xc3, 0xe0, 0x47, 0x97, 0xc4,
-	0x9f, 0xf3, 0x60, 0x72, 0x59, 0xd4, 0x4b, 0xc9, 0x1b, 0x90, 0x21, 0xe4, 0xa9, 0xb7, 0x0c, 0xd1,
-	0xaa, 0x95, 0x45, 0x1c, 0x22, 0x54, 0xb3, 0xc0, 0xa3, 0x4b, 0xdb, 0xdf, 0x45, 0x30, 0xc1, 0xcf,
-	0x63, 0x29, 0x6b, 0xef, 0x02, 0x59, 0xa9, 0x48, 0xce, 0x60, 0x52, 0xda, 0xc6, 0xd1, 0x9a, 0xb5,
-	0x2c, 0x6b, 0x18, 0x61, 0x01, 0x2f, 0x82, 0x32, 0xe1, 0x05, 0xa4, 0xfc, 0x0e, 0x19, 0xfd, 0x87,
-	0xad, 0x6c, 0xa9, 0xda, 0xcc, 0x39, 0xd2, 0x9e, 0x1d, 0x7d, 0x3d, 0x56, 0x37, 0x25, 0x05, 0x74,
-	0xbd, 0x1f, 0x39, 0x58, 0x55, 0x31, 0xb4, 0xc0, 0xc0, 0xf3, 0xa0, 0xc4, 0x3f, 0x78, 0xf2, 0xfa,
-	0x22, 0xf3, 0xd8, 0xc1, 0x2f, 0x4f, 0x33, 0xe7, 0x08, 0x73, 0xb8, 0x00, 0x8a, 0x51, 0x1c, 0xfa,
-	0xb2, 0xf8, 0x38, 0xd9, 0xff, 0x4c, 0xf5, 0x6b, 0xdd, 0xcc, 0x39, 0xdc, 0x16, 0x9e, 0x63, 0x27,
-	0x05, 0xf6, 0x99, 0x27, 0xfc, 0xe4, 0xc5, 0x32, 0x7d, 0x1f, 0x4c, 0x81, 0x24, 0xa6, 0xf0, 0x1c,
-	0x28, 0x6f, 0xf3, 0x6c, 0x2e, 0xcf, 0xcc, 0x73, 0x2a, 0x28, 0x9b, 0xe7, 0xd9, 0x7b, 0x09, 0x5b,
-	0x78, 0x1d, 0x1c, 0xa3, 0x61, 0xb4, 0x99, 0xe4, 0x4d, 0x79, 0x6a, 0xab, 0xa9, 0xd8, 0x61, 0x79,
-	0xb5, 0x99, 0x73, 0x32, 0x38, 0x78, 0x1b, 0x4c, 0x6d, 0x65, 0xa2, 0x1b, 0x13, 0x7e, 0x09, 0xd4,
-	0xc7, 0xf3, 0xf0, 0xa4, 0xd3, 0xcc, 0x39, 0x03, 0x68, 0xb8, 0x04, 0x26, 0x49, 0x46, 0xf2, 0xf2,
-	0x56, 0x25, 0xf3, 0x5e, 0xd9, 0xa0, 0x68, 0xe6, 0x9c, 0x3e, 0x8c, 0x0d, 0x7a, 0x11, 0xc9, 0xcf,
-	0xf6, 0x52, 0x79, 0xe2, 0xa0, 0x76, 0x21, 0x15, 0x93, 0x10, 0xde, 0xa9, 0x83, 0xc4, 0xc4, 0xcd,
-	0x15, 0x2d, 0x7d, 0x90, 0x6a, 0x49, 0xa8, 0x70, 0xb6, 0x97, 0x22, 0xb9, 0x8a, 0x14, 0x84, 0xd4,
-	0xcf, 0x62, 0xa2, 0x1f, 0x21, 0xbe, 0x13, 0xc3, 0x8b, 0x9e, 0x04, 0x25, 0xc5, 0x73, 0x19, 0x54,
-	0x5c, 0x71, 0xd7, 0x33, 0x4c, 0x76, 0x83, 0x57, 0x41, 0x4c, 0x0e, 0x12, 0x00, 0x17, 0x7b, 0x22,
-	0x12, 0xda, 0x3b, 0x3e, 0x28, 0xa2, 0x14, 0x94, 0x68, 0xe8, 0x6c, 0xaa, 0xa1, 0xb2, 0xc4, 0x0c,
-	0x54, 0x0a, 0xe9, 0x8b, 0x49, 0x01, 0x5d, 0x03, 0x13, 0x09, 0xe5, 0x7c, 0x4a, 0x2a, 0xe8, 0xd4,
-	0x41, 0x79, 0x2f, 0xc1, 0x67, 0x51, 0xb0, 0x09, 0xc6, 0x7c, 0x4c, 0x11, 0x3b, 0x8f, 0xe8, 0x15,
-	0x9e, 0xc4, 0x4e, 0x67, 0x75, 0xd3, 0x73, 0x9b, 0x75, 0x4b, 0x1a, 0x5e, 0x0b, 0x68, 0xbc, 0x23,
-	0x4b, 0xcf, 0x14, 0x3d, 0x77, 0x05, 0x4c, 0x64, 0x0c, 0xe0, 0x14, 0x28, 0x6c, 0xe2, 0xe4, 0x76,
-	0x8c, 0x35, 0xd9, 0x01, 0x7d, 0x1b, 0x79, 0x1d, 0xcc, 0xbd, 0x57, 0x75, 0x44, 0xe7, 0x72, 0xfe,
-	0xa2, 0x66, 0x57, 0x41, 0x25, 0x16, 0x4f, 0xb1, 0x5b, 0x8f, 0x9f, 0x1a, 0xb9, 0x27, 0x4f, 0x8d,
-	0xdc, 0xf3, 0xa7, 0x86, 0xf6, 0xed, 0x9e, 0xa1, 0xfd, 0xb8, 0x67, 0x68, 0x8f, 0xf6, 0x0c, 0xed,
-	0xf1, 0x9e, 0xa1, 0xfd, 0xb9, 0x67, 0x68, 0x7f, 0xed, 0x19, 0xb9, 0xe7, 0x7b, 0x86, 0x76, 0xff,
-	0x99, 0x91, 0x7b, 0xfc, 0xcc, 0xc8, 0x3d, 0x79, 0x66, 0xe4, 0xbe, 0xb2, 0x0e, 0x97, 0x4f, 0x57,
-	0xcb, 0x9c, 0xdd, 0xc5, 0x7f, 0x02, 0x00, 0x00, 0xff, 0xff, 0x3b, 0x20, 0xe2, 0xc2, 0x3a, 0x17,
-	0x00, 0x00,
+	// 1811 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xec, 0x59, 0xcd, 0x8f, 0x1c, 0x47,
+	0x15, 0x9f, 0x9e, 0xcf, 0x9d, 0xb7, 0x1f, 0x59, 0xca, 0x9b, 0x75, 0xb3, 0x4e, 0xa6, 0x87, 0x41,
+	0xc4, 0x0b, 0x82, 0x19, 0xb2, 0x1b, 0xec, 0xc4, 0x0e, 0x86, 0x74, 0xd6, 0xd6, 0x18, 0x1c, 0x70,
+	0x7a, 0x57, 0x1c, 0x10, 0x12, 0xaa, 0x9d, 0x29, 0xcf, 0x36, 0x3b, 0xd3, 0xdd, 0xee, 0xaa, 0x71,
+	0xb2, 0x42, 0x48, 0x48, 0x5c, 0x41, 0xca, 0x5f, 0x81, 0x90, 0x88, 0x72, 0xe2, 0xc4, 0x31, 0x48,
+	0xe0, 0xa3, 0xc5, 0x29, 0x1a, 0x89, 0x06, 0xaf, 0x2f, 0x68, 0x4f, 0xbe, 0x71, 0x45, 0xf5, 0xd1,
+	0x3d, 0xd5, 0xd3, 0xbd, 0xf6, 0x6c, 0x10, 0x92, 0x57, 0xca, 0x65, 0xa7, 0xaa, 0xfa, 0xfd, 0x5e,
+	0x57, 0xfd, 0xde, 0xfb, 0xbd, 0xae, 0xaa, 0x85, 0xcb, 0xc1, 0xe1, 0xa0, 0x73, 0x7f, 0x4c, 0x42,
+	0x97, 0x84, 0xe2, 0xf7, 0x28, 0xc4, 0xde, 0x80, 0x68, 0xcd, 0x76, 0x10, 0xfa, 0xcc, 0x47, 0x30,
+	0x1d, 0xd9, 0xd8, 0x1a, 0xb8, 0xec, 0x60, 0xbc, 0xdf, 0xee, 0xf9, 0xa3, 0xce, 0xc0, 0x1f, 0xf8,
+	0x9d, 0x81, 0xef, 0x0f, 0x86, 0x04, 0x07, 0x2e, 0x55, 0xcd, 0x4e, 0x18, 0xf4, 0x3a, 0x94, 0x61,
+	0x36, 0xa6, 0x12, 0xbf, 0xb1, 0xc6, 0x0d, 0x45, 0x53, 0x40, 0xd4, 0xa8, 0xa5, 0xcc, 0x45, 0x6f,
+	0x7f, 0x7c, 0xaf, 0xc3, 0xdc, 0x11, 0xa1, 0x0c, 0x8f, 0x82, 0xd8, 0x80, 0xcf, 0x6f, 0xe8, 0x0f,
+	0x24, 0xd2, 0xf5, 0xfa, 0xe4, 0xc3, 0x01, 0x66, 0xe4, 0x03, 0x7c, 0xa4, 0x0c, 0x2e, 0xa5, 0x0c,
+	0xe2, 0x86, 0x7a, 0xf8, 0xe5, 0xd4, 0x43, 0x7a, 0x48, 0x58, 0xef, 0x40, 0x3d, 0x6a, 0xaa, 0x47,
+	0xf7, 0x87, 0x23, 0xbf, 0x4f, 0x86, 0x62, 0xb2, 0x54, 0xfe, 0x55, 0x16, 0x17, 0xb8, 0x45, 0x30,
+	0xa6, 0x07, 0xe2, 0x8f, 0x1a, 0x7c, 0xf7, 0xb9, 0x7c, 0xed, 0x63, 0x4a, 0x3a, 0x7d, 0x72, 0xcf,
+	0xf5, 0x5c, 0xe6, 0xfa, 0x1e, 0xd5, 0xdb, 0xca, 0xc9, 0x95, 0xf9, 0x9c, 0xcc, 0xc6, 0xa0, 0xf5,
+	0x49, 0x09, 0x16, 0xef, 0xf8, 0x87, 0xae, 0x43, 0xee, 0x8f, 0x09, 0x65, 0x68, 0x0d, 0x2a, 0xc2,
+	0xc6, 0x34, 0x9a, 0xc6, 0x66, 0xdd, 0x91, 0x1d, 0x3e, 0x3a, 0x74, 0x47, 0x2e, 0x33, 0x8b, 0x4d,
+	0x63, 0x73, 0xd9, 0x91, 0x1d, 0x84, 0xa0, 0x4c, 0x19, 0x09, 0xcc, 0x52, 0xd3, 0xd8, 0x2c, 0x39,
+	0xa2, 0x8d, 0x36, 0x60, 0xc1, 0xf5, 0x18, 0x09, 0x1f, 0xe0, 0xa1, 0x59, 0x17, 0xe3, 0x49, 0x1f,
+	0xdd, 0x80, 0x1a, 0x65, 0x38, 0x64, 0x7b, 0xd4, 0x2c, 0x37, 0x8d, 0xcd, 0xc5, 0xad, 0x8d, 0xb6,
+	0x8c, 0x55, 0x3b, 0x8e, 0x55, 0x7b, 0x2f, 0x8e, 0x95, 0xbd, 0xf0, 0x30, 0xb2, 0x0a, 0x1f, 0xfd,
+	0xd3, 0x32, 0x9c, 0x18, 0x84, 0xae, 0x41, 0x85, 0x78, 0xfd, 0x3d, 0x6a, 0x56, 0xce, 0x80, 0x96,
+	0x10, 0xf4, 0x3a, 0xd4, 0xfb, 0x6e, 0x48, 0x7a, 0x9c, 0x33, 0xb3, 0xda, 0x34, 0x36, 0x57, 0xb6,
+	0x2e, 0xb4, 0x93, 0xd0, 0xee, 0xc4, 0x8f, 0x9c, 0xa9, 0x15, 0x5f, 0x5e, 0x80, 0xd9, 0x81, 0x59,
+	0x13, 0x4c, 0x88, 0x36, 0x6a, 0x41, 0x95, 0x1e, 0xe0, 0xb0, 0x4f, 0xcd, 0x85, 0x66, 0x69, 0xb3,
+	0x6e, 0xc3, 0x49, 0x64, 0xa9, 0x11, 0x47, 0xfd, 0xa2, 0x9f, 0x41, 0x39, 0x18, 0x62, 0xcf, 0x04,
+	0x31, 0xcb, 0xd5, 0xb6, 0xc6, 0xf9, 0xdd, 0x21, 0xf6, 0xec, 0x2b, 0x93, 0xc8, 0x4a, 0xa5, 0x7b,
+	0x88, 0xef, 0x61, 0x0f, 0x77, 0x86, 0xfe, 0xa1, 0xdb, 0xd1, 0xc3, 0xc8, 0xbd, 0xb4, 0xdf, 0xe7,
+	0x68, 0x8e, 0x73, 0x84, 0xd7, 0xd6, 0xdf, 0x8a, 0x80, 0x78, 0xc0, 0x6e, 0x7b, 0x94, 0x61, 0x8f,
+	0x7d, 0x9e, 0xb8, 0xbd, 0x0d, 0x55, 0xae, 0x89, 0x3d, 0x2a, 0x22, 0x37, 0x2f, 0x91, 0x0a, 0x93,
+	0x66, 0xb2, 0x7c, 0x26, 0x26, 0x2b, 0xb9, 0x4c, 0x56, 0x9f, 0xcb, 0x64, 0xed, 0xff, 0xc2, 0xa4,
+	0x09, 0x65, 0xde, 0x43, 0xab, 0x50, 0x0a, 0xf1, 0x07, 0x82, 0xb8, 0x25, 0x87, 0x37, 0x5b, 0x7f,
+	0x2c, 0xc3, 0x92, 0x14, 0x05, 0x0d, 0x7c, 0x8f, 0x12, 0x3e, 0xd9, 0x5d, 0x51, 0x79, 0x24, 0xbd,
+	0x6a, 0xb2, 0x62, 0xc4, 0x51, 0x4f, 0xd0, 0xf7, 0xa1, 0xbc, 0x83, 0x19, 0x16, 0x54, 0x2f, 0x6e,
+	0xad, 0xe9, 0x93, 0xe5, 0xbe, 0xf8, 0x33, 0x7b, 0x9d, 0xb3, 0x79, 0x12, 0x59, 0x2b, 0x7d, 0xcc,
+	0xf0, 0x37, 0xfd, 0x91, 0xcb, 0xc8, 0x28, 0x60, 0x47, 0x8e, 0x40, 0xa2, 0xef, 0x40, 0xfd, 0x66,
+	0x18, 0xfa, 0xe1, 0xde, 0x51, 0x40, 0x44, 0x68, 0xea, 0xf6, 0xc5, 0x93, 0xc8, 0xba, 0x40, 0xe2,
+	0x41, 0x0d, 0x31, 0xb5, 0x44, 0x5f, 0x87, 0x8a, 0xe8, 0x88, 0x60, 0xd4, 0xed, 0x0b, 0x27, 0x91,
+	0xf5, 0x92, 0x80, 0x68, 0xe6, 0xd2, 0x22, 0x1d, 0xbb, 0xca, 0x5c, 0xb1, 0x4b, 0x52, 0xa8, 0xaa,
+	0xa7, 0x90, 0x09, 0xb5, 0x07, 0x24, 0xa4, 0xdc, 0x4d, 0x4d, 0x8c, 0xc7, 0x5d, 0xf4, 0x0e, 0x00,
+	0x27, 0xc6, 0xa5, 0xcc, 0xed, 0x71, 0x95, 0x70, 0x32, 0x96, 0xdb, 0xb2, 0x08, 0x3a, 0x84, 0x8e,
+	0x87, 0xcc, 0x46, 0x8a, 0x05, 0xcd, 0xd0, 0xd1, 0xda, 0xe8, 0x63, 0x03, 0x6a, 0x5d, 0x82, 0xfb,
+	0x24, 0xa4, 0x66, 0xbd, 0x59, 0xda, 0x5c, 0xdc, 0xfa, 0x5a, 0x5b, 0xaf, 0x78, 0x77, 0x43, 0x7f,
+	0x44, 0xd8, 0x01, 0x19, 0xd3, 0x38, 0x40, 0xd2, 0xda, 0x3e, 0x9c, 0x44, 0xd6, 0xfe, 0x3c, 0xf9,
+	0x30, 0x57, 0x95, 0x3d, 0xf5, 0x3d, 0x27, 0x91, 0x65, 0x7c, 0xcb, 0x89, 0xa7, 0xd8, 0xfa, 0x87,
+	0x01, 0x5f, 0xe2, 0x11, 0xde, 0xe5, 0xbe, 0xa9, 0x26, 0xc8, 0x11, 0x66, 0xbd, 0x03, 0xd3, 0xe0,
+	0xe9, 0xed, 0xc8, 0x8e, 0x5e, 0x02, 0x8b, 0xff, 0x53, 0x09, 0x2c, 0x9d, 0xbd, 0x04, 0xc6, 0x2a,
+	0x2c, 0xe7, 0xaa, 0xb0, 0x72, 0x9a, 0x0a, 0x5b, 0xbf, 0x2d, 0xc9, 0x8a, 0x13, 0xaf, 0xef, 0x0c,
+	0x9a, 0xb8, 0x95, 0x68, 0xa2, 0x24, 0x66, 0x9b, 0xa4, 0x9a, 0xf4, 0x75, 0xbb, 0x4f, 0x3c, 0xe6,
+	0xde, 0x73, 0x49, 0xf8, 0x1c, 0x65, 0x68, 0xe9, 0x56, 0x4a, 0xa7, 0x9b, 0x9e, 0x2b, 0xe5, 0x17,
+	0x3e, 0x57, 0x66, 0xd4, 0x51, 0xf9, 0x1c, 0xea, 0x68, 0x3d, 0x2d, 0xc2, 0x3a, 0x0f, 0xc7, 0x1d,
+	0xbc, 0x4f, 0x86, 0x3f, 0xc2, 0xa3, 0x33, 0x86, 0xe4, 0x35, 0x2d, 0x24, 0x75, 0x1b, 0x7d, 0x41,
+	0xf9, 0x1c, 0x94, 0xff, 0xde, 0x80, 0x85, 0xb8, 0x86, 0xa3, 0x36, 0x80, 0x84, 0x89, 0x32, 0x2d,
+	0x89, 0x5e, 0xe1, 0xe0, 0x30, 0x19, 0x75, 0x34, 0x0b, 0xf4, 0x0b, 0xa8, 0xca, 0x9e, 0x52, 0xc1,
+	0x45, 0x4d, 0x05, 0x2c, 0x24, 0x78, 0xf4, 0x4e, 0x1f, 0x07, 0x8c, 0x84, 0xf6, 0x5b, 0x7c, 0x16,
+	0x93, 0xc8, 0xba, 0xfc, 0x2c, 0x8a, 0xc4, 0xbe, 0x51, 0xe2, 0x78, 0x70, 0xe5, 0x3b, 0x1d, 0xf5,
+	0x86, 0xd6, 0xef, 0x0c, 0x58, 0xe5, 0x13, 0xe5, 0xd4, 0x24, 0x59, 0xb1, 0x03, 0x0b, 0xa1, 0x6a,
+	0x8b, 0xe9, 0x2e, 0x6e, 0xb5, 0xda, 0x69, 0x5a, 0x73, 0xa8, 0xb4, 0xcb, 0x0f, 0x23, 0xcb, 0x70,
+	0x12, 0x24, 0xda, 0x4e, 0xd1, 0x58, 0xcc, 0xa3, 0x91, 0x43, 0x0a, 0x29, 0xe2, 0xfe, 0x5c, 0x04,
+	0x74, 0x9b, 0x6f, 0xb0, 0x79, 0xf2, 0x4d, 0xf3, 0x74, 0x9c, 0x99, 0xd1, 0x2b, 0x53, 0x52, 0xb2,
+	0xf6, 0xf6, 0xf5, 0x49, 0x64, 0x5d, 0x7d, 0x16, 0x2b, 0xcf, 0x00, 0x6b, 0x4b, 0xd0, 0x13, 0xb7,
+	0xf8, 0xe2, 0x7f, 0x57, 0x3e, 0x29, 0xc2, 0xca, 0x4f, 0xfc, 0xe1, 0x78, 0x44, 0x12, 0xe2, 0x46,
+	0x19, 0xe2, 0xcc, 0x29, 0x71, 0x69, 0x5b, 0xfb, 0xea, 0x24, 0xb2, 0xb6, 0xe7, 0x22, 0x2d, 0x0d,
+	0x3c, 0xbf, 0x84, 0x7d, 0x5c, 0x84, 0xb5, 0x3d, 0x3f, 0xf8, 0xe1, 0xae, 0x38, 0x94, 0x69, 0x75,
+	0x91, 0x64, 0x68, 0x5b, 0x9b, 0xd2, 0xc6, 0x11, 0xef, 0x61, 0x16, 0xba, 0x1f, 0xda, 0xdb, 0x93,
+	0xc8, 0xea, 0xcc, 0x45, 0xd9, 0x14, 0x74, 0x7e, 0xe9, 0xfa, 0xb4, 0x08, 0xeb, 0xef, 0x8f, 0xb1,
+	0xc7, 0xdc, 0x21, 0x91, 0x94, 0x25, 0x84, 0x1d, 0x65, 0x08, 0x6b, 0x4c, 0x09, 0x4b, 0x63, 0x14,
+	0x75, 0xdf, 0x9d, 0x44, 0xd6, 0x5b, 0x73, 0x51, 0x97, 0x07, 0x3f, 0xbf, 0x24, 0xfe, 0xbd, 0x08,
+	0x97, 0xde, 0xf5, 0xc7, 0x1e, 0xdb, 0xe1, 0x25, 0xcf, 0xeb, 0xb1, 0x19, 0x26, 0x7f, 0x63, 0x64,
+	0xa8, 0xfc, 0xea, 0x94, 0xca, 0x1c, 0xa4, 0xe2, 0xd3, 0x9e, 0x44, 0xd6, 0x8d, 0xb9, 0xf8, 0x3c,
+	0xd5, 0xc7, 0xf9, 0x25, 0xf5, 0x4f, 0x45, 0x58, 0xd9, 0x95, 0x9b, 0xd0, 0x78, 0x05, 0x34, 0x27,
+	0x23, 0xf5, 0xab, 0x9b, 0x60, 0xbf, 0x9d, 0x46, 0x9c, 0xa1, 0xfe, 0xa5, 0x81, 0xe7, 0x97, 0xb6,
+	0xbf, 0x14, 0xe1, 0x65, 0x79, 0xc8, 0xc5, 0x8c, 0x91, 0xd0, 0x9b, 0xb2, 0xf7, 0xcb, 0x0c, 0x7b,
+	0x96, 0xae, 0xe7, 0x1c, 0x88, 0x7d, 0x63, 0x12, 0x59, 0xd7, 0xe6, 0x14, 0x74, 0x0e, 0xfe, 0xfc,
+	0xb2, 0xf8, 0xd7, 0x22, 0xac, 0xef, 0x10, 0x46, 0x7a, 0x8c, 0xf4, 0x6f, 0xb9, 0x64, 0xa8, 0x25,
+	0xe1, 0xaf, 0x32, 0x34, 0x36, 0xb5, 0xd3, 0x73, 0x2e, 0xc6, 0xfe, 0xde, 0x24, 0xb2, 0xae, 0xcf,
+	0xc5, 0x63, 0xbe, 0x83, 0xf3, 0x4b, 0xe4, 0x7f, 0xaa, 0xb0, 0x2c, 0x72, 0x23, 0xe1, 0xef, 0x1b,
+	0xa0, 0x4e, 0x23, 0x8a, 0x3d, 0x14, 0x1f, 0x5f, 0xc3, 0xa0, 0xd7, 0xde, 0x55, 0xe7, 0x14, 0x69,
+	0x81, 0xde, 0x84, 0x2a, 0x15, 0x87, 0x44, 0xb5, 0xd7, 0x6c, 0xcc, 0x5e, 0xa8, 0xa4, 0x8f, 0xa3,
+	0xdd, 0x82, 0xa3, 0xec, 0xd1, 0xdb, 0x50, 0x1d, 0xf2, 0xb3, 0x51, 0x7c, 0x48, 0x6e, 0xcd, 0x22,
+	0xb3, 0x27, 0x27, 0x8e, 0x96, 0x18, 0x74, 0x05, 0x2a, 0x62, 0x53, 0xab, 0xae, 0x28, 0x53, 0xaf,
+	0xcd, 0xee, 0x2e, 0xbb, 0x05, 0x47, 0x9a, 0xa3, 0x2d, 0x28, 0x07, 0xa1, 0x3f, 0x52, 0x07, 0x8c,
+	0x57, 0x66, 0xdf, 0xa9, 0xef, 0xc8, 0xbb, 0x05, 0x47, 0xd8, 0xa2, 0x37, 0xa0, 0x46, 0xc5, 0x56,
+	0x9e, 0x8a, 0xdb, 0x15, 0xbe, 0x9b, 0x9b, 0x81, 0x69, 0x90, 0xd8, 0x14, 0xbd, 0x01, 0xd5, 0x07,
+	0x62, 0xc7, 0xa6, 0xee, 0xc5, 0x36, 0x74, 0x50, 0x7a, 0x2f, 0xc7, 0xd7, 0x25, 0x6d, 0xd1, 0x2d,
+	0x58, 0x62, 0x7e, 0x70, 0x18, 0xef, 0x8d, 0xd4, 0xcd, 0x4c, 0x53, 0xc7, 0xe6, 0xed, 0x9d, 0xba,
+	0x05, 0x27, 0x85, 0x43, 0x77, 0x61, 0xf5, 0x7e, 0xea, 0x0b, 0x4e, 0xa8, 0xb8, 0xe8, 0x9d, 0xe1,
+	0x39, 0x7f, 0x63, 0xd1, 0x2d, 0x38, 0x19, 0x34, 0xda, 0x81, 0x15, 0x9a, 0xaa, 0xc0, 0xea, 0xe6,
+	0x34, 0xb5, 0xae, 0x74, 0x8d, 0xee, 0x16, 0x9c, 0x19, 0x0c, 0xfa, 0x39, 0xbc, 0xdc, 0xcb, 0x7e,
+	0x09, 0x09, 0x35, 0x17, 0x85, 0xb3, 0xcb, 0xba, 0xb3, 0x67, 0x7c, 0xb0, 0xbb, 0x05, 0x27, 0xdf,
+	0x0f, 0xfa, 0x31, 0xac, 0x06, 0x33, 0x45, 0xce, 0x5c, 0x12, 0xbe, 0xbf, 0x92, 0x5e, 0x78, 0x4e,
+	0x35, 0xe4, 0xeb, 0x9e, 0x05, 0xa3, 0x3b, 0xb0, 0xd2, 0x4f, 0x49, 0xde, 0x5c, 0xce, 0xf2, 0x98,
+	0x5f, 0x14, 0xf8, 0xfa, 0xd3, 0x58, 0x1b, 0xa6, 0xb5, 0xa9, 0xf5, 0x69, 0x05, 0x96, 0x94, 0xf2,
+	0xe4, 0x65, 0xd4, 0xd5, 0x44, 0x4c, 0x52, 0x78, 0xaf, 0x9e, 0x26, 0x26, 0x61, 0xae, 0x69, 0xe9,
+	0xdb, 0x89, 0x96, 0xa4, 0x0a, 0xd7, 0xa7, 0xf5, 0x4e, 0xa8, 0x48, 0x43, 0x28, 0xfd, 0x6c, 0xc7,
+	0xfa, 0x91, 0xe2, 0xbb, 0x94, 0x7f, 0xb0, 0x8b, 0x51, 0x4a, 0x3c, 0xd7, 0xa0, 0xe6, 0xca, 0xfb,
+	0xec, 0x3c, 0xd9, 0x65, 0xaf, 0xbb, 0xb9, 0x1c, 0x14, 0x00, 0x6d, 0x4f, 0x45, 0x24, 0xb5, 0x77,
+	0x31, 0x2b, 0xa2, 0x04, 0x14, 0x6b, 0xe8, 0xf5, 0x44, 0x43, 0x55, 0x85, 0xc9, 0x9c, 0x86, 0x92,
+	0x85, 0x29, 0x01, 0xdd, 0x84, 0xe5, 0x38, 0xe5, 0xc4, 0x23, 0xa5, 0xa0, 0x57, 0x4f, 0xdb, 0x86,
+	0xc4, 0xf8, 0x34, 0x0a, 0xfd, 0x00, 0x5e, 0x9a, 0x66, 0x82, 0x74, 0x54, 0xcf, 0xee, 0xb0, 0x53,
+	0x39, 0x14, 0x7b, 0x9a, 0x05, 0xa2, 0xdb, 0x99, 0x0c, 0x82, 0xd9, 0x8f, 0xfb, 0x6c, 0xfe, 0xc4,
+	0xbe, 0x66, 0x80, 0xa8, 0x0b, 0x0b, 0x23, 0xc2, 0x70, 0x1f, 0x33, 0x6c, 0xd6, 0xc4, 0xb7, 0xe5,
+	0xb5, 0x4c, 0x56, 0x2b, 0x74, 0xfb, 0x3d, 0x65, 0x78, 0xd3, 0x63, 0xe1, 0x91, 0x3a, 0xf5, 0x27,
+	0xe8, 0x8d, 0xeb, 0xb0, 0x9c, 0x32, 0x40, 0xab, 0x50, 0x3a, 0x24, 0xf1, 0x3f, 0x26, 0x78, 0x13,
+	0xad, 0x41, 0xe5, 0x01, 0x1e, 0x8e, 0x89, 0x48, 0xaa, 0xba, 0x23, 0x3b, 0xd7, 0x8a, 0x6f, 0x1a,
+	0x76, 0x1d, 0x6a, 0xa1, 0x7c, 0x8b, 0xdd, 0x7f, 0xf4, 0xb8, 0x51, 0xf8, 0xec, 0x71, 0xa3, 0xf0,
+	0xf4, 0x71, 0xc3, 0xf8, 0xf5, 0x71, 0xc3, 0xf8, 0xc3, 0x71, 0xc3, 0x78, 0x78, 0xdc, 0x30, 0x1e,
+	0x1d, 0x37, 0x8c, 0x7f, 0x1d, 0x37, 0x8c, 0x7f, 0x1f, 0x37, 0x0a, 0x4f, 0x8f, 0x1b, 0xc6, 0x47,
+	0x4f, 0x1a, 0x85, 0x47, 0x4f, 0x1a, 0x85, 0xcf, 0x9e, 0x34, 0x0a, 0x3f, 0x6d, 0x9f, 0xed, 0x33,
+	0xb7, 0x5f, 0x15, 0x34, 0x6d, 0xff, 0x37, 0x00, 0x00, 0xff, 0xff, 0xa8, 0x4d, 0x20, 0xe6, 0xb5,
+	0x1c, 0x00, 0x00,
 }
 
 func (this *LokiRequest) Equal(that interface{}) bool {
@@ -1867,6 +2060,42 @@ func (this *QuantileSketchResponse) Equal(that interface{}) bool {
 	}
 	return true
 }
+func (this *CountDistinctSketchResponse) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*CountDistinctSketchResponse)
+	if !ok {
+		that2, ok := that.(CountDistinctSketchResponse)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if that1.Response == nil {
+		if this.Response != nil {
+			return false
+		}
+	} else if !this.Response.Equal(*that1.Response) {
+		return false
+	}
+	if len(this.Headers) != len(that1.Headers) {
+		return false
+	}
+	for i := range this.Headers {
+		if !this.Headers[i].Equal(that1.Headers[i]) {
+			return false
+		}
+	}
+	return true
+}
 func (this *ShardsResponse) Equal(that interface{}) bool {
 	if that == nil {
 		return this == nil
@@ -1903,6 +2132,78 @@ func (this *ShardsResponse) Equal(that interface{}) bool {
 	}
 	return true
 }
+func (this *QueryPatternsResponse) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*QueryPatternsResponse)
+	if !ok {
+		that2, ok := that.(QueryPatternsResponse)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if that1.Response == nil {
+		if this.Response != nil {
+			return false
+		}
+	} else if !this.Response.Equal(*that1.Response) {
+		return false
+	}
+	if len(this.Headers) != len(that1.Headers) {
+		return false
+	}
+	for i := range this.Headers {
+		if !this.Headers[i].Equal(that1.Headers[i]) {
+			return false
+		}
+	}
+	return true
+}
+func (this *DetectedFieldsResponse) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*DetectedFieldsResponse)
+	if !ok {
+		that2, ok := that.(DetectedFieldsResponse)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if that1.Response == nil {
+		if this.Response != nil {
+			return false
+		}
+	} else if !this.Response.Equal(*that1.Response) {
+		return false
+	}
+	if len(this.Headers) != len(that1.Headers) {
+		return false
+	}
+	for i := range this.Headers {
+		if !this.Headers[i].Equal(that1.Headers[i]) {
+			return false
+		}
+	}
+	return true
+}
 func (this *QueryResponse) Equal(that interface{}) bool {
 	if that == nil {
 		return this == nil
@@ -2152,6 +2453,78 @@ func (this *QueryResponse_ShardsResponse) Equal(that interface{}) bool {
 	}
 	return true
 }
+func (this *QueryResponse_CountDistinctSketches) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*QueryResponse_CountDistinctSketches)
+	if !ok {
+		that2, ok := that.(QueryResponse_CountDistinctSketches)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if !this.CountDistinctSketches.Equal(that1.CountDistinctSketches) {
+		return false
+	}
+	return true
+}
+func (this *QueryResponse_PatternsResponse) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*QueryResponse_PatternsResponse)
+	if !ok {
+		that2, ok := that.(QueryResponse_PatternsResponse)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if !this.PatternsResponse.Equal(that1.PatternsResponse) {
+		return false
+	}
+	return true
+}
+func (this *QueryResponse_DetectedFields) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*QueryResponse_DetectedFields)
+	if !ok {
+		that2, ok := that.(QueryResponse_DetectedFields)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if !this.DetectedFields.Equal(that1.DetectedFields) {
+		return false
+	}
+	return true
+}
 func (this *QueryRequest) Equal(that interface{}) bool {
 	if that == nil {
 		return this == nil
@@ -2358,6 +2731,54 @@ func (this *QueryRequest_ShardsRequest) Equal(that interface{}) bool {
 	}
 	return true
 }
+func (this *QueryRequest_PatternsRequest) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*QueryRequest_PatternsRequest)
+	if !ok {
+		that2, ok := that.(QueryRequest_PatternsRequest)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if !this.PatternsRequest.Equal(that1.PatternsRequest) {
+		return false
+	}
+	return true
+}
+func (this *QueryRequest_DetectedFields) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*QueryRequest_DetectedFields)
+	if !ok {
+		that2, ok := that.(QueryRequest_DetectedFields)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if !this.DetectedFields.Equal(that1.DetectedFields) {
+		return false
+	}
+	return true
+}
 func (this *LokiRequest) GoString() string {
 	if this == nil {
 		return "nil"
@@ -2537,6 +2958,17 @@ func (this *QuantileSketchResponse) GoString() string {
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
+func (this *CountDistinctSketchResponse) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 6)
+	s = append(s, "&queryrange.CountDistinctSketchResponse{")
+	s = append(s, "Response: "+fmt.Sprintf("%#v", this.Response)+",\n")
+	s = append(s, "Headers: "+fmt.Sprintf("%#v", this.Headers)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
 func (this *ShardsResponse) GoString() string {
 	if this == nil {
 		return "nil"
@@ -2548,11 +2980,33 @@ func (this *ShardsResponse) GoString() string {
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
+func (this *QueryPatternsResponse) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 6)
+	s = append(s, "&queryrange.QueryPatternsResponse{")
+	s = append(s, "Response: "+fmt.Sprintf("%#v", this.Response)+",\n")
+	s = append(s, "Headers: "+fmt.Sprintf("%#v", this.Headers)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *DetectedFieldsResponse) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 6)
+	s = append(s, "&queryrange.DetectedFieldsResponse{")
+	s = append(s, "Response: "+fmt.Sprintf("%#v", this.Response)+",\n")
+	s = append(s, "Headers: "+fmt.Sprintf("%#v", this.Headers)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
 func (this *QueryResponse) GoString() string {
 	if this == nil {
 		return "nil"
 	}
-	s := make([]string, 0, 14)
+	s := make([]string, 0, 17)
 	s = append(s, "&queryrange.QueryResponse{")
 	if this.Status != nil {
 		s = append(s, "Status: "+fmt.Sprintf("%#v", this.Status)+",\n")
@@ -2635,11 +3089,35 @@ func (this *QueryResponse_ShardsResponse) GoString() string {
 		`ShardsResponse:` + fmt.Sprintf("%#v", this.ShardsResponse) + `}`}, ", ")
 	return s
 }
+func (this *QueryResponse_CountDistinctSketches) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&queryrange.QueryResponse_CountDistinctSketches{` +
+		`CountDistinctSketches:` + fmt.Sprintf("%#v", this.CountDistinctSketches) + `}`}, ", ")
+	return s
+}
+func (this *QueryResponse_PatternsResponse) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&queryrange.QueryResponse_PatternsResponse{` +
+		`PatternsResponse:` + fmt.Sprintf("%#v", this.PatternsResponse) + `}`}, ", ")
+	return s
+}
+func (this *QueryResponse_DetectedFields) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&queryrange.QueryResponse_DetectedFields{` +
+		`DetectedFields:` + fmt.Sprintf("%#v", this.DetectedFields) + `}`}, ", ")
+	return s
+}
 func (this *QueryRequest) GoString() string {
 	if this == nil {
 		return "nil"
 	}
-	s := make([]string, 0, 12)
+	s := make([]string, 0, 14)
 	s = append(s, "&queryrange.QueryRequest{")
 	if this.Request != nil {
 		s = append(s, "Request: "+fmt.Sprintf("%#v", this.Request)+",\n")
@@ -2716,6 +3194,22 @@ func (this *QueryRequest_ShardsRequest) GoString() string {
 		`ShardsRequest:` + fmt.Sprintf("%#v", this.ShardsRequest) + `}`}, ", ")
 	return s
 }
+func (this *QueryRequest_PatternsRequest) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&queryrange.QueryRequest_PatternsRequest{` +
+		`PatternsRequest:` + fmt.Sprintf("%#v", this.PatternsRequest) + `}`}, ", ")
+	return s
+}
+func (this *QueryRequest_DetectedFields) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&queryrange.QueryRequest_DetectedFields{` +
+		`DetectedFields:` + fmt.Sprintf("%#v", this.DetectedFields) + `}`}, ", ")
+	return s
+}
 func valueToGoStringQueryrange(v interface{}, typ string) string {
 	rv := reflect.ValueOf(v)
 	if rv.IsNil() {
@@ -3507,6 +4001,55 @@ func (m *QuantileSketchResponse) MarshalToSizedBuffer(dAtA []byte) (int, error)
 	return len(dAtA) - i, nil
 }
 
+func (m *CountDistinctSketchResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *CountDistinctSketchResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *CountDistinctSketchResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Headers) > 0 {
+		for iNdEx := len(m.Headers) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size := m.Headers[iNdEx].Size()
+				i -= size
+				if _, err := m.Headers[iNdEx].MarshalTo(dAtA[i:]); err != nil {
+					return 0, err
+				}
+				i = encodeVarintQueryrange(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if m.Response != nil {
+		{
+			size := m.Response.Size()
+			i -= size
+			if _, err := m.Response.MarshalTo(dAtA[i:]); err != nil {
+				return 0, err
+			}
+			i = encodeVarintQueryrange(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
 func (m *ShardsResponse) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
@@ -3556,6 +4099,104 @@ func (m *ShardsResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *QueryPatternsResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *QueryPatternsResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *QueryPatternsResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Headers) > 0 {
+		for iNdEx := len(m.Headers) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size := m.Headers[iNdEx].Size()
+				i -= size
+				if _, err := m.Headers[iNdEx].MarshalTo(dAtA[i:]); err != nil {
+					return 0, err
+				}
+				i = encodeVarintQueryrange(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if m.Response != nil {
+		{
+			size := m.Response.Size()
+			i -= size
+			if _, err := m.Response.MarshalTo(dAtA[i:]); err != nil {
+				return 0, err
+			}
+			i = encodeVarintQueryrange(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *DetectedFieldsResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *DetectedFieldsResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *DetectedFieldsResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Headers) > 0 {
+		for iNdEx := len(m.Headers) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size := m.Headers[iNdEx].Size()
+				i -= size
+				if _, err := m.Headers[iNdEx].MarshalTo(dAtA[i:]); err != nil {
+					return 0, err
+				}
+				i = encodeVarintQueryrange(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if m.Response != nil {
+		{
+			size := m.Response.Size()
+			i -= size
+			if _, err := m.Response.MarshalTo(dAtA[i:]); err != nil {
+				return 0, err
+			}
+			i = encodeVarintQueryrange(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
 func (m *QueryResponse) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
@@ -3780,34 +4421,94 @@ func (m *QueryResponse_ShardsResponse) MarshalToSizedBuffer(dAtA []byte) (int, e
 	}
 	return len(dAtA) - i, nil
 }
-func (m *QueryRequest) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
-	}
-	return dAtA[:n], nil
-}
-
-func (m *QueryRequest) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+func (m *QueryResponse_CountDistinctSketches) MarshalTo(dAtA []byte) (int, error) {
+	return m.MarshalToSizedBuffer(dAtA[:m.Size()])
 }
 
-func (m *QueryRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *QueryResponse_CountDistinctSketches) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if m.Request != nil {
+	if m.CountDistinctSketches != nil {
 		{
-			size := m.Request.Size()
-			i -= size
-			if _, err := m.Request.MarshalTo(dAtA[i:]); err != nil {
+			size, err := m.CountDistinctSketches.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
 				return 0, err
 			}
-		}
+			i -= size
+			i = encodeVarintQueryrange(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x5a
+	}
+	return len(dAtA) - i, nil
+}
+func (m *QueryResponse_PatternsResponse) MarshalTo(dAtA []byte) (int, error) {
+	return m.MarshalToSizedBuffer(dAtA[:m.Size()])
+}
+
+func (m *QueryResponse_PatternsResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	if m.PatternsResponse != nil {
+		{
+			size, err := m.PatternsResponse.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintQueryrange(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x62
+	}
+	return len(dAtA) - i, nil
+}
+func (m *QueryResponse_DetectedFields) MarshalTo(dAtA []byte) (int, error) {
+	return m.MarshalToSizedBuffer(dAtA[:m.Size()])
+}
+
+func (m *QueryResponse_DetectedFields) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	if m.DetectedFields != nil {
+		{
+			size, err := m.DetectedFields.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintQueryrange(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x6a
+	}
+	return len(dAtA) - i, nil
+}
+func (m *QueryRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *QueryRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *QueryRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Request != nil {
+		{
+			size := m.Request.Size()
+			i -= size
+			if _, err := m.Request.MarshalTo(dAtA[i:]); err != nil {
+				return 0, err
+			}
+		}
 	}
 	if len(m.Metadata) > 0 {
 		for k := range m.Metadata {
@@ -3971,6 +4672,46 @@ func (m *QueryRequest_ShardsRequest) MarshalToSizedBuffer(dAtA []byte) (int, err
 	}
 	return len(dAtA) - i, nil
 }
+func (m *QueryRequest_PatternsRequest) MarshalTo(dAtA []byte) (int, error) {
+	return m.MarshalToSizedBuffer(dAtA[:m.Size()])
+}
+
+func (m *QueryRequest_PatternsRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	if m.PatternsRequest != nil {
+		{
+			size, err := m.PatternsRequest.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintQueryrange(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x4a
+	}
+	return len(dAtA) - i, nil
+}
+func (m *QueryRequest_DetectedFields) MarshalTo(dAtA []byte) (int, error) {
+	return m.MarshalToSizedBuffer(dAtA[:m.Size()])
+}
+
+func (m *QueryRequest_DetectedFields) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	if m.DetectedFields != nil {
+		{
+			size, err := m.DetectedFields.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintQueryrange(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x52
+	}
+	return len(dAtA) - i, nil
+}
 func encodeVarintQueryrange(dAtA []byte, offset int, v uint64) int {
 	offset -= sovQueryrange(v)
 	base := offset
@@ -4312,6 +5053,25 @@ func (m *QuantileSketchResponse) Size() (n int) {
 	return n
 }
 
+func (m *CountDistinctSketchResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Response != nil {
+		l = m.Response.Size()
+		n += 1 + l + sovQueryrange(uint64(l))
+	}
+	if len(m.Headers) > 0 {
+		for _, e := range m.Headers {
+			l = e.Size()
+			n += 1 + l + sovQueryrange(uint64(l))
+		}
+	}
+	return n
+}
+
 func (m *ShardsResponse) Size() (n int) {
 	if m == nil {
 		return 0
@@ -4331,6 +5091,44 @@ func (m *ShardsResponse) Size() (n int) {
 	return n
 }
 
+func (m *QueryPatternsResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Response != nil {
+		l = m.Response.Size()
+		n += 1 + l + sovQueryrange(uint64(l))
+	}
+	if len(m.Headers) > 0 {
+		for _, e := range m.Headers {
+			l = e.Size()
+			n += 1 + l + sovQueryrange(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *DetectedFieldsResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Response != nil {
+		l = m.Response.Size()
+		n += 1 + l + sovQueryrange(uint64(l))
+	}
+	if len(m.Headers) > 0 {
+		for _, e := range m.Headers {
+			l = e.Size()
+			n += 1 + l + sovQueryrange(uint64(l))
+		}
+	}
+	return n
+}
+
 func (m *QueryResponse) Size() (n int) {
 	if m == nil {
 		return 0
@@ -4455,6 +5253,42 @@ func (m *QueryResponse_ShardsResponse) Size() (n int) {
 	}
 	return n
 }
+func (m *QueryResponse_CountDistinctSketches) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.CountDistinctSketches != nil {
+		l = m.CountDistinctSketches.Size()
+		n += 1 + l + sovQueryrange(uint64(l))
+	}
+	return n
+}
+func (m *QueryResponse_PatternsResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.PatternsResponse != nil {
+		l = m.PatternsResponse.Size()
+		n += 1 + l + sovQueryrange(uint64(l))
+	}
+	return n
+}
+func (m *QueryResponse_DetectedFields) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.DetectedFields != nil {
+		l = m.DetectedFields.Size()
+		n += 1 + l + sovQueryrange(uint64(l))
+	}
+	return n
+}
 func (m *QueryRequest) Size() (n int) {
 	if m == nil {
 		return 0
@@ -4559,6 +5393,30 @@ func (m *QueryRequest_ShardsRequest) Size() (n int) {
 	}
 	return n
 }
+func (m *QueryRequest_PatternsRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.PatternsRequest != nil {
+		l = m.PatternsRequest.Size()
+		n += 1 + l + sovQueryrange(uint64(l))
+	}
+	return n
+}
+func (m *QueryRequest_DetectedFields) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.DetectedFields != nil {
+		l = m.DetectedFields.Size()
+		n += 1 + l + sovQueryrange(uint64(l))
+	}
+	return n
+}
 
 func sovQueryrange(x uint64) (n int) {
 	return (math_bits.Len64(x|1) + 6) / 7
@@ -4742,6 +5600,17 @@ func (this *QuantileSketchResponse) String() string {
 	}, "")
 	return s
 }
+func (this *CountDistinctSketchResponse) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&CountDistinctSketchResponse{`,
+		`Response:` + fmt.Sprintf("%v", this.Response) + `,`,
+		`Headers:` + fmt.Sprintf("%v", this.Headers) + `,`,
+		`}`,
+	}, "")
+	return s
+}
 func (this *ShardsResponse) String() string {
 	if this == nil {
 		return "nil"
@@ -4753,6 +5622,28 @@ func (this *ShardsResponse) String() string {
 	}, "")
 	return s
 }
+func (this *QueryPatternsResponse) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&QueryPatternsResponse{`,
+		`Response:` + fmt.Sprintf("%v", this.Response) + `,`,
+		`Headers:` + fmt.Sprintf("%v", this.Headers) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *DetectedFieldsResponse) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&DetectedFieldsResponse{`,
+		`Response:` + fmt.Sprintf("%v", this.Response) + `,`,
+		`Headers:` + fmt.Sprintf("%v", this.Headers) + `,`,
+		`}`,
+	}, "")
+	return s
+}
 func (this *QueryResponse) String() string {
 	if this == nil {
 		return "nil"
@@ -4854,6 +5745,36 @@ func (this *QueryResponse_ShardsResponse) String() string {
 	}, "")
 	return s
 }
+func (this *QueryResponse_CountDistinctSketches) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&QueryResponse_CountDistinctSketches{`,
+		`CountDistinctSketches:` + strings.Replace(fmt.Sprintf("%v", this.CountDistinctSketches), "CountDistinctSketchResponse", "CountDistinctSketchResponse", 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *QueryResponse_PatternsResponse) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&QueryResponse_PatternsResponse{`,
+		`PatternsResponse:` + strings.Replace(fmt.Sprintf("%v", this.PatternsResponse), "QueryPatternsResponse", "QueryPatternsResponse", 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *QueryResponse_DetectedFields) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&QueryResponse_DetectedFields{`,
+		`DetectedFields:` + strings.Replace(fmt.Sprintf("%v", this.DetectedFields), "DetectedFieldsResponse", "DetectedFieldsResponse", 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
 func (this *QueryRequest) String() string {
 	if this == nil {
 		return "nil"
@@ -4945,17 +5866,37 @@ func (this *QueryRequest_ShardsRequest) String() string {
 	}, "")
 	return s
 }
-func valueToStringQueryrange(v interface{}) string {
-	rv := reflect.ValueOf(v)
-	if rv.IsNil() {
+func (this *QueryRequest_PatternsRequest) String() string {
+	if this == nil {
 		return "nil"
 	}
-	pv := reflect.Indirect(rv).Interface()
-	return fmt.Sprintf("*%v", pv)
+	s := strings.Join([]string{`&QueryRequest_PatternsRequest{`,
+		`PatternsRequest:` + strings.Replace(fmt.Sprintf("%v", this.PatternsRequest), "QueryPatternsRequest", "logproto.QueryPatternsRequest", 1) + `,`,
+		`}`,
+	}, "")
+	return s
 }
-func (m *LokiRequest) Unmarshal(dAtA []byte) error {
-	l := len(dAtA)
-	iNdEx := 0
+func (this *QueryRequest_DetectedFields) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&QueryRequest_DetectedFields{`,
+		`DetectedFields:` + strings.Replace(fmt.Sprintf("%v", this.DetectedFields), "DetectedFieldsRequest", "logproto.DetectedFieldsRequest", 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func valueToStringQueryrange(v interface{}) string {
+	rv := reflect.ValueOf(v)
+	if rv.IsNil() {
+		return "nil"
+	}
+	pv := reflect.Indirect(rv).Interface()
+	return fmt.Sprintf("*%v", pv)
+}
+func (m *LokiRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
 	for iNdEx < l {
 		preIndex := iNdEx
 		var wire uint64
@@ -6461,9 +7402,370 @@ func (m *LokiLabelNamesResponse) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
-		case 4:
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Headers", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQueryrange
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Headers = append(m.Headers, github_com_grafana_loki_pkg_querier_queryrange_queryrangebase_definitions.PrometheusResponseHeader{})
+			if err := m.Headers[len(m.Headers)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Statistics", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQueryrange
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.Statistics.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipQueryrange(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *LokiData) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowQueryrange
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: LokiData: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: LokiData: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ResultType", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQueryrange
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ResultType = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Result", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQueryrange
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Result = append(m.Result, github_com_grafana_loki_pkg_push.Stream{})
+			if err := m.Result[len(m.Result)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipQueryrange(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *LokiPromResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowQueryrange
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: LokiPromResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: LokiPromResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Response", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQueryrange
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Response == nil {
+				m.Response = &queryrangebase.PrometheusResponse{}
+			}
+			if err := m.Response.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Statistics", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQueryrange
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.Statistics.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipQueryrange(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *IndexStatsResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowQueryrange
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: IndexStatsResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: IndexStatsResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Headers", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Response", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -6490,14 +7792,16 @@ func (m *LokiLabelNamesResponse) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Headers = append(m.Headers, github_com_grafana_loki_pkg_querier_queryrange_queryrangebase_definitions.PrometheusResponseHeader{})
-			if err := m.Headers[len(m.Headers)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if m.Response == nil {
+				m.Response = &github_com_grafana_loki_pkg_logproto.IndexStatsResponse{}
+			}
+			if err := m.Response.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
-		case 5:
+		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Statistics", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Headers", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -6524,7 +7828,8 @@ func (m *LokiLabelNamesResponse) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if err := m.Statistics.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			m.Headers = append(m.Headers, github_com_grafana_loki_pkg_querier_queryrange_queryrangebase_definitions.PrometheusResponseHeader{})
+			if err := m.Headers[len(m.Headers)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
@@ -6552,7 +7857,7 @@ func (m *LokiLabelNamesResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *LokiData) Unmarshal(dAtA []byte) error {
+func (m *VolumeResponse) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -6575,17 +7880,17 @@ func (m *LokiData) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: LokiData: wiretype end group for non-group")
+			return fmt.Errorf("proto: VolumeResponse: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: LokiData: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: VolumeResponse: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ResultType", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Response", wireType)
 			}
-			var stringLen uint64
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowQueryrange
@@ -6595,27 +7900,31 @@ func (m *LokiData) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthQueryrange
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthQueryrange
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.ResultType = string(dAtA[iNdEx:postIndex])
+			if m.Response == nil {
+				m.Response = &github_com_grafana_loki_pkg_logproto.VolumeResponse{}
+			}
+			if err := m.Response.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
 			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Result", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Headers", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -6642,8 +7951,8 @@ func (m *LokiData) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Result = append(m.Result, github_com_grafana_loki_pkg_push.Stream{})
-			if err := m.Result[len(m.Result)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			m.Headers = append(m.Headers, github_com_grafana_loki_pkg_querier_queryrange_queryrangebase_definitions.PrometheusResponseHeader{})
+			if err := m.Headers[len(m.Headers)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
@@ -6671,7 +7980,7 @@ func (m *LokiData) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *LokiPromResponse) Unmarshal(dAtA []byte) error {
+func (m *TopKSketchesResponse) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -6694,10 +8003,10 @@ func (m *LokiPromResponse) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: LokiPromResponse: wiretype end group for non-group")
+			return fmt.Errorf("proto: TopKSketchesResponse: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: LokiPromResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: TopKSketchesResponse: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
@@ -6730,7 +8039,7 @@ func (m *LokiPromResponse) Unmarshal(dAtA []byte) error {
 				return io.ErrUnexpectedEOF
 			}
 			if m.Response == nil {
-				m.Response = &queryrangebase.PrometheusResponse{}
+				m.Response = &github_com_grafana_loki_pkg_logproto.TopKMatrix{}
 			}
 			if err := m.Response.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
@@ -6738,7 +8047,7 @@ func (m *LokiPromResponse) Unmarshal(dAtA []byte) error {
 			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Statistics", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Headers", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -6765,7 +8074,8 @@ func (m *LokiPromResponse) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if err := m.Statistics.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			m.Headers = append(m.Headers, github_com_grafana_loki_pkg_querier_queryrange_queryrangebase_definitions.PrometheusResponseHeader{})
+			if err := m.Headers[len(m.Headers)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
@@ -6793,7 +8103,7 @@ func (m *LokiPromResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *IndexStatsResponse) Unmarshal(dAtA []byte) error {
+func (m *QuantileSketchResponse) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -6816,10 +8126,10 @@ func (m *IndexStatsResponse) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: IndexStatsResponse: wiretype end group for non-group")
+			return fmt.Errorf("proto: QuantileSketchResponse: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: IndexStatsResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: QuantileSketchResponse: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
@@ -6852,7 +8162,7 @@ func (m *IndexStatsResponse) Unmarshal(dAtA []byte) error {
 				return io.ErrUnexpectedEOF
 			}
 			if m.Response == nil {
-				m.Response = &github_com_grafana_loki_pkg_logproto.IndexStatsResponse{}
+				m.Response = &github_com_grafana_loki_pkg_logproto.QuantileSketchMatrix{}
 			}
 			if err := m.Response.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
@@ -6916,7 +8226,7 @@ func (m *IndexStatsResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *VolumeResponse) Unmarshal(dAtA []byte) error {
+func (m *CountDistinctSketchResponse) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -6939,10 +8249,10 @@ func (m *VolumeResponse) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: VolumeResponse: wiretype end group for non-group")
+			return fmt.Errorf("proto: CountDistinctSketchResponse: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: VolumeResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: CountDistinctSketchResponse: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
@@ -6975,7 +8285,7 @@ func (m *VolumeResponse) Unmarshal(dAtA []byte) error {
 				return io.ErrUnexpectedEOF
 			}
 			if m.Response == nil {
-				m.Response = &github_com_grafana_loki_pkg_logproto.VolumeResponse{}
+				m.Response = &github_com_grafana_loki_pkg_logproto.CountDistinctSketchMatrix{}
 			}
 			if err := m.Response.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
@@ -7039,7 +8349,7 @@ func (m *VolumeResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *TopKSketchesResponse) Unmarshal(dAtA []byte) error {
+func (m *ShardsResponse) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -7062,10 +8372,10 @@ func (m *TopKSketchesResponse) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: TopKSketchesResponse: wiretype end group for non-group")
+			return fmt.Errorf("proto: ShardsResponse: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: TopKSketchesResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: ShardsResponse: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
@@ -7098,7 +8408,7 @@ func (m *TopKSketchesResponse) Unmarshal(dAtA []byte) error {
 				return io.ErrUnexpectedEOF
 			}
 			if m.Response == nil {
-				m.Response = &github_com_grafana_loki_pkg_logproto.TopKMatrix{}
+				m.Response = &github_com_grafana_loki_pkg_logproto.ShardsResponse{}
 			}
 			if err := m.Response.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
@@ -7162,7 +8472,7 @@ func (m *TopKSketchesResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *QuantileSketchResponse) Unmarshal(dAtA []byte) error {
+func (m *QueryPatternsResponse) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -7185,10 +8495,10 @@ func (m *QuantileSketchResponse) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: QuantileSketchResponse: wiretype end group for non-group")
+			return fmt.Errorf("proto: QueryPatternsResponse: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: QuantileSketchResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: QueryPatternsResponse: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
@@ -7221,7 +8531,7 @@ func (m *QuantileSketchResponse) Unmarshal(dAtA []byte) error {
 				return io.ErrUnexpectedEOF
 			}
 			if m.Response == nil {
-				m.Response = &github_com_grafana_loki_pkg_logproto.QuantileSketchMatrix{}
+				m.Response = &github_com_grafana_loki_pkg_logproto.QueryPatternsResponse{}
 			}
 			if err := m.Response.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
@@ -7285,7 +8595,7 @@ func (m *QuantileSketchResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *ShardsResponse) Unmarshal(dAtA []byte) error {
+func (m *DetectedFieldsResponse) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -7308,10 +8618,10 @@ func (m *ShardsResponse) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: ShardsResponse: wiretype end group for non-group")
+			return fmt.Errorf("proto: DetectedFieldsResponse: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: ShardsResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: DetectedFieldsResponse: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
@@ -7344,7 +8654,7 @@ func (m *ShardsResponse) Unmarshal(dAtA []byte) error {
 				return io.ErrUnexpectedEOF
 			}
 			if m.Response == nil {
-				m.Response = &github_com_grafana_loki_pkg_logproto.ShardsResponse{}
+				m.Response = &github_com_grafana_loki_pkg_logproto.DetectedFieldsResponse{}
 			}
 			if err := m.Response.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
@@ -7788,6 +9098,111 @@ func (m *QueryResponse) Unmarshal(dAtA []byte) error {
 			}
 			m.Response = &QueryResponse_ShardsResponse{v}
 			iNdEx = postIndex
+		case 11:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CountDistinctSketches", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQueryrange
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &CountDistinctSketchResponse{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Response = &QueryResponse_CountDistinctSketches{v}
+			iNdEx = postIndex
+		case 12:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PatternsResponse", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQueryrange
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &QueryPatternsResponse{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Response = &QueryResponse_PatternsResponse{v}
+			iNdEx = postIndex
+		case 13:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DetectedFields", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQueryrange
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &DetectedFieldsResponse{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Response = &QueryResponse_DetectedFields{v}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipQueryrange(dAtA[iNdEx:])
@@ -8213,6 +9628,76 @@ func (m *QueryRequest) Unmarshal(dAtA []byte) error {
 			}
 			m.Request = &QueryRequest_ShardsRequest{v}
 			iNdEx = postIndex
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PatternsRequest", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQueryrange
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &logproto.QueryPatternsRequest{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Request = &QueryRequest_PatternsRequest{v}
+			iNdEx = postIndex
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DetectedFields", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQueryrange
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &logproto.DetectedFieldsRequest{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Request = &QueryRequest_DetectedFields{v}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipQueryrange(dAtA[iNdEx:])
@@ -865,6 +865,7 @@ func TestPostQueries(t *testing.T) {
 		handler,
 		handler,
 		handler,
+		handler,
 		fakeLimits{},
 	).Do(ctx, lreq)
 	require.NoError(t, err)
@@ -1242,6 +1243,7 @@ type fakeLimits struct {
 	tsdbMaxQueryParallelism     int
 	maxQueryLookback            time.Duration
 	maxEntriesLimitPerQuery     int
+	maxStreamedEntriesPerQuery  int
 	maxSeries                   int
 	splitDuration               map[string]time.Duration
 	metadataSplitDuration       map[string]time.Duration
@@ -1325,6 +1327,10 @@ func (f fakeLimits) MaxEntriesLimitPerQuery(context.Context, string) int {
 	return f.maxEntriesLimitPerQuery
 }
 
+func (f fakeLimits) MaxStreamedEntriesPerQuery(context.Context, string) int {
+	return f.maxStreamedEntriesPerQuery
+}
+
 func (f fakeLimits) MaxQuerySeries(context.Context, string) int {
 	return f.maxSeries
 }
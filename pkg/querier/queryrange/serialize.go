@@ -1,6 +1,7 @@
 package queryrange
 
 import (
+	"io"
 	"net/http"
 
 	"github.com/opentracing/opentracing-go"
@@ -12,14 +13,18 @@ import (
 )
 
 type serializeRoundTripper struct {
-	codec queryrangebase.Codec
-	next  queryrangebase.Handler
+	codec  queryrangebase.Codec
+	next   queryrangebase.Handler
+	limits StreamLimits
 }
 
-func NewSerializeRoundTripper(next queryrangebase.Handler, codec queryrangebase.Codec) http.RoundTripper {
+// NewSerializeRoundTripper returns a round tripper decoding requests and encoding responses with the codec.
+// Range log queries accepting NDJSON are streamed in batches sized by the limits, if the limits allow it.
+func NewSerializeRoundTripper(next queryrangebase.Handler, codec queryrangebase.Codec, limits StreamLimits) http.RoundTripper {
 	return &serializeRoundTripper{
-		next:  next,
-		codec: codec,
+		next:   next,
+		codec:  codec,
+		limits: limits,
 	}
 }
 
@@ -33,6 +38,29 @@ func (rt *serializeRoundTripper) RoundTrip(r *http.Request) (*http.Response, err
 		return nil, err
 	}
 
+	if req, ok := streamableRequest(ctx, r, request, rt.limits); ok {
+		streamer, err := newLogStreamer(ctx, rt.next, rt.limits, req, httpreq.ExtractEncodingFlags(r))
+		if err != nil {
+			return nil, err
+		}
+		// The first batch is fetched before responding, so that query errors keep their status code.
+		first, err := streamer.nextBatch(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		pr, pw := io.Pipe()
+		go func() {
+			streamer.run(ctx, first, pw, func() {})
+			_ = pw.Close()
+		}()
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": []string{NDJSONType}},
+			Body:       pr,
+		}, nil
+	}
+
 	response, err := rt.next.Do(ctx, request)
 	if err != nil {
 		return nil, err
@@ -42,14 +70,18 @@ func (rt *serializeRoundTripper) RoundTrip(r *http.Request) (*http.Response, err
 }
 
 type serializeHTTPHandler struct {
-	codec queryrangebase.Codec
-	next  queryrangebase.Handler
+	codec  queryrangebase.Codec
+	next   queryrangebase.Handler
+	limits StreamLimits
 }
 
-func NewSerializeHTTPHandler(next queryrangebase.Handler, codec queryrangebase.Codec) http.Handler {
+// NewSerializeHTTPHandler returns a handler decoding requests and encoding responses with the codec.
+// Range log queries accepting NDJSON are streamed in batches sized by the limits, if the limits allow it.
+func NewSerializeHTTPHandler(next queryrangebase.Handler, codec queryrangebase.Codec, limits StreamLimits) http.Handler {
 	return &serializeHTTPHandler{
-		next:  next,
-		codec: codec,
+		next:   next,
+		codec:  codec,
+		limits: limits,
 	}
 }
 
@@ -64,6 +96,30 @@ func (rt *serializeHTTPHandler) ServeHTTP(w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	encodingFlags := httpreq.ExtractEncodingFlags(r)
+
+	if req, ok := streamableRequest(ctx, r, request, rt.limits); ok {
+		streamer, err := newLogStreamer(ctx, rt.next, rt.limits, req, encodingFlags)
+		if err != nil {
+			serverutil.WriteError(err, w)
+			return
+		}
+		first, err := streamer.nextBatch(ctx)
+		if err != nil {
+			serverutil.WriteError(err, w)
+			return
+		}
+
+		w.Header().Set("Content-Type", NDJSONType)
+		w.WriteHeader(http.StatusOK)
+		flush := func() {}
+		if f, ok := w.(http.Flusher); ok {
+			flush = f.Flush
+		}
+		streamer.run(ctx, first, w, flush)
+		return
+	}
+
 	response, err := rt.next.Do(ctx, request)
 	if err != nil {
 		serverutil.WriteError(err, w)
@@ -71,7 +127,6 @@ func (rt *serializeHTTPHandler) ServeHTTP(w http.ResponseWriter, r *http.Request
 	}
 
 	version := loghttp.GetVersion(r.RequestURI)
-	encodingFlags := httpreq.ExtractEncodingFlags(r)
 	if err := encodeResponseJSONTo(version, response, w, encodingFlags); err != nil {
 		serverutil.WriteError(err, w)
 	}
@@ -111,7 +111,7 @@ func TestResponseFormat(t *testing.T) {
 			handler := queryrangebase.HandlerFunc(func(ctx context.Context, r queryrangebase.Request) (queryrangebase.Response, error) {
 				return tc.response, nil
 			})
-			httpHandler := NewSerializeHTTPHandler(handler, DefaultCodec)
+			httpHandler := NewSerializeHTTPHandler(handler, DefaultCodec, nil)
 
 			w := httptest.NewRecorder()
 			req := httptest.NewRequest(http.MethodGet, tc.url+
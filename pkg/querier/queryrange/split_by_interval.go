@@ -218,13 +218,18 @@ func (h *splitByInterval) Do(ctx context.Context, r queryrangebase.Request) (que
 	switch req := r.(type) {
 	case *LokiRequest:
 		limit = int64(req.Limit)
+		// limit and dedup stages drop lines across splits, the lines of a split can't be counted until every previous split is merged.
+		if len(lineLimits(req)) > 0 {
+			limit = 0
+		}
 		if req.Direction == logproto.BACKWARD {
 			for i, j := 0, len(intervals)-1; i < j; i, j = i+1, j-1 {
 				intervals[i], intervals[j] = intervals[j], intervals[i]
 			}
 		}
-	case *LokiSeriesRequest, *LabelRequest, *logproto.IndexStatsRequest, *logproto.VolumeRequest, *logproto.ShardsRequest:
+	case *LokiSeriesRequest, *LabelRequest, *logproto.IndexStatsRequest, *logproto.VolumeRequest, *logproto.ShardsRequest, *logproto.DetectedFieldsRequest:
 		// Set this to 0 since this is not used in Series/Labels/Index Request.
+		// Each split of a detected fields request samples up to its own line limit.
 		limit = 0
 	default:
 		return nil, httpgrpc.Errorf(http.StatusBadRequest, "unknown request type")
@@ -245,6 +250,9 @@ func (h *splitByInterval) Do(ctx context.Context, r queryrangebase.Request) (que
 	if err != nil {
 		return nil, err
 	}
+	if limits := lineLimits(r); len(limits) > 0 && len(resps) > 0 {
+		return mergeLokiResponseWithLineLimits(limits, resps...)
+	}
 	return h.merger.MergeResponse(resps...)
 }
 
@@ -265,13 +273,24 @@ func maxRangeVectorAndOffsetDuration(expr syntax.Expr) (time.Duration, time.Dura
 
 	var maxRVDuration, maxOffset time.Duration
 	expr.Walk(func(e syntax.Expr) {
-		if r, ok := e.(*syntax.LogRange); ok {
+		switch r := e.(type) {
+		case *syntax.LogRange:
 			if r.Interval > maxRVDuration {
 				maxRVDuration = r.Interval
 			}
 			if r.Offset > maxOffset {
 				maxOffset = r.Offset
 			}
+		case *syntax.SubqueryExpr:
+			// The inner expression of a subquery is evaluated over the range
+			// of the subquery, therefore their durations add up.
+			innerRV, innerOffset, _ := maxRangeVectorAndOffsetDuration(r.Left)
+			if r.Range+innerRV > maxRVDuration {
+				maxRVDuration = r.Range + innerRV
+			}
+			if r.Offset+innerOffset > maxOffset {
+				maxOffset = r.Offset + innerOffset
+			}
 		}
 	})
 	return maxRVDuration, maxOffset, nil
@@ -1537,6 +1537,40 @@ func Test_splitByInterval_Do(t *testing.T) {
 				},
 			},
 		},
+		{
+			"forward dedup",
+			&LokiRequest{
+				StartTs:   time.Unix(0, 0),
+				EndTs:     time.Unix(0, (4 * time.Hour).Nanoseconds()),
+				Query:     `{foo="bar"} | dedup by (level) 2h`,
+				Limit:     2,
+				Step:      1,
+				Direction: logproto.FORWARD,
+				Path:      "/api/prom/query_range",
+				Plan: &plan.QueryPlan{
+					AST: syntax.MustParseExpr(`{foo="bar"} | dedup by (level) 2h`),
+				},
+			},
+			&LokiResponse{
+				Status:     loghttp.QueryStatusSuccess,
+				Direction:  logproto.FORWARD,
+				Limit:      2,
+				Version:    1,
+				Statistics: stats.Result{Summary: stats.Summary{Splits: 4}},
+				Data: LokiData{
+					ResultType: loghttp.ResultTypeStream,
+					Result: []logproto.Stream{
+						{
+							Labels: `{foo="bar", level="debug"}`,
+							Entries: []logproto.Entry{
+								{Timestamp: time.Unix(0, 0), Line: fmt.Sprintf("%d", 0)},
+								{Timestamp: time.Unix(0, 2*time.Hour.Nanoseconds()), Line: fmt.Sprintf("%d", 2*time.Hour.Nanoseconds())},
+							},
+						},
+					},
+				},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -95,6 +95,16 @@ func (s *defaultSplitter) split(execTime time.Time, tenantIDs []string, req quer
 				AggregateBy:  r.AggregateBy,
 			})
 		}
+	case *logproto.DetectedFieldsRequest:
+		factory = func(start, end time.Time) {
+			reqs = append(reqs, &logproto.DetectedFieldsRequest{
+				Query:      r.Query,
+				Start:      model.TimeFromUnixNano(start.UnixNano()),
+				End:        model.TimeFromUnixNano(end.UnixNano()),
+				LineLimit:  r.LineLimit,
+				FieldLimit: r.FieldLimit,
+			})
+		}
 	default:
 		return nil, nil
 	}
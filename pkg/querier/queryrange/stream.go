@@ -0,0 +1,248 @@
+package queryrange
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/grafana/dskit/httpgrpc"
+	"github.com/grafana/dskit/tenant"
+
+	"github.com/grafana/loki/pkg/iter"
+	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/logql/syntax"
+	"github.com/grafana/loki/pkg/querier/queryrange/queryrangebase"
+	"github.com/grafana/loki/pkg/util/httpreq"
+	"github.com/grafana/loki/pkg/util/marshal"
+	"github.com/grafana/loki/pkg/util/validation"
+)
+
+// NDJSONType is the content type of streamed log query responses.
+// Each line of the body is a stream holding a single entry, in the order of the query direction.
+const NDJSONType = `application/x-ndjson`
+
+// defaultStreamBatchSize is the number of entries fetched per batch when the tenant has no max entries limit.
+const defaultStreamBatchSize = 5000
+
+// StreamLimits is the subset of limits needed to stream log query results.
+type StreamLimits interface {
+	MaxEntriesLimitPerQuery(context.Context, string) int
+	MaxStreamedEntriesPerQuery(context.Context, string) int
+}
+
+// AcceptsNDJSON returns true if the client asked for a streamed response.
+func AcceptsNDJSON(r *http.Request) bool {
+	for _, accept := range r.Header.Values("Accept") {
+		if strings.Contains(accept, NDJSONType) {
+			return true
+		}
+	}
+	return false
+}
+
+// streamableRequest returns the request as a range log query if it can be streamed.
+// Queries are only streamed if all their tenants are allowed to stream results.
+func streamableRequest(ctx context.Context, r *http.Request, req queryrangebase.Request, limits StreamLimits) (*LokiRequest, bool) {
+	if !AcceptsNDJSON(r) || maxStreamedEntries(ctx, limits) <= 0 {
+		return nil, false
+	}
+	lokiReq, ok := req.(*LokiRequest)
+	if !ok || lokiReq.Plan == nil {
+		return nil, false
+	}
+	_, ok = lokiReq.Plan.AST.(syntax.LogSelectorExpr)
+	return lokiReq, ok
+}
+
+// maxStreamedEntries returns the maximum number of entries a streamed query can return, 0 if it can't be streamed.
+func maxStreamedEntries(ctx context.Context, limits StreamLimits) int {
+	if limits == nil {
+		return 0
+	}
+	tenantIDs, err := tenant.TenantIDs(ctx)
+	if err != nil {
+		return 0
+	}
+	maxStreamedCapture := func(id string) int { return limits.MaxStreamedEntriesPerQuery(ctx, id) }
+	return validation.SmallestPositiveIntPerTenant(tenantIDs, maxStreamedCapture)
+}
+
+// entryKey identifies an entry already written at the boundary timestamp of a batch.
+type entryKey struct {
+	labels string
+	line   string
+}
+
+// logStreamer pages through a range log query in batches no larger than the tenant max entries limit,
+// so that exports of any size can be written incrementally without buffering the whole result.
+//
+// Batching works like logcli: the next batch starts (forward) or ends (backward) at the timestamp of the
+// last entry written, and entries at that timestamp that were already written are skipped.
+type logStreamer struct {
+	next        queryrangebase.Handler
+	req         *LokiRequest
+	batchSize   int
+	encodeFlags httpreq.EncodingFlags
+
+	remaining   int
+	maxStreamed int
+	start, end  time.Time
+	done        bool
+
+	lastTs   time.Time
+	lastSent map[entryKey]struct{}
+}
+
+func newLogStreamer(ctx context.Context, next queryrangebase.Handler, limits StreamLimits, req *LokiRequest, encodeFlags httpreq.EncodingFlags) (*logStreamer, error) {
+	tenantIDs, err := tenant.TenantIDs(ctx)
+	if err != nil {
+		return nil, httpgrpc.Errorf(http.StatusBadRequest, err.Error())
+	}
+	remaining, maxStreamed := int(req.Limit), maxStreamedEntries(ctx, limits)
+	if remaining > maxStreamed {
+		remaining = maxStreamed
+	}
+
+	batchSize := defaultStreamBatchSize
+	maxEntriesCapture := func(id string) int { return limits.MaxEntriesLimitPerQuery(ctx, id) }
+	if l := validation.SmallestPositiveNonZeroIntPerTenant(tenantIDs, maxEntriesCapture); l > 0 {
+		batchSize = l
+	}
+
+	return &logStreamer{
+		next:        next,
+		req:         req,
+		batchSize:   batchSize,
+		encodeFlags: encodeFlags,
+		remaining:   remaining,
+		maxStreamed: maxStreamed,
+		start:       req.StartTs,
+		end:         req.EndTs,
+	}, nil
+}
+
+// nextBatch fetches the next batch of entries. It returns nil once the query is exhausted.
+func (s *logStreamer) nextBatch(ctx context.Context) (*LokiResponse, error) {
+	if s.done || s.remaining <= 0 {
+		return nil, nil
+	}
+
+	// Ask for the entries already written at the boundary timestamp again, since they will be skipped.
+	size := s.remaining + len(s.lastSent)
+	if size > s.batchSize {
+		size = s.batchSize
+	}
+	if size <= len(s.lastSent) {
+		return nil, fmt.Errorf("cannot stream query results: %d entries or more share the timestamp %s, increase the max entries limit", len(s.lastSent), s.lastTs)
+	}
+
+	req := *s.req
+	req.StartTs = s.start
+	req.EndTs = s.end
+	req.Limit = uint32(size)
+
+	resp, err := s.next.Do(ctx, &req)
+	if err != nil {
+		return nil, err
+	}
+	lokiResp, ok := resp.(*LokiResponse)
+	if !ok {
+		return nil, fmt.Errorf("unexpected response type %T while streaming query results", resp)
+	}
+
+	var received int
+	for _, stream := range lokiResp.Data.Result {
+		received += len(stream.Entries)
+	}
+	// A short batch means there is nothing left in the query range.
+	s.done = received < size
+	return lokiResp, nil
+}
+
+// write writes the entries of a batch one per line and moves the query range past them.
+func (s *logStreamer) write(resp *LokiResponse, w io.Writer) error {
+	it := iter.NewStreamsIterator(resp.Data.Result, s.req.Direction)
+	defer it.Close()
+
+	ts, sent := s.lastTs, s.lastSent
+	var written int
+	for s.remaining > 0 && it.Next() {
+		entry := it.Entry()
+		key := entryKey{labels: it.Labels(), line: entry.Line}
+		if entry.Timestamp.Equal(s.lastTs) {
+			if _, ok := s.lastSent[key]; ok {
+				continue
+			}
+		}
+
+		if err := marshal.WriteStreamLineJSON(logproto.Stream{
+			Labels:  key.labels,
+			Entries: []logproto.Entry{entry},
+		}, w, s.encodeFlags); err != nil {
+			return err
+		}
+		s.remaining--
+		written++
+
+		if !entry.Timestamp.Equal(ts) {
+			ts, sent = entry.Timestamp, map[entryKey]struct{}{}
+		}
+		sent[key] = struct{}{}
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+
+	if written == 0 {
+		s.done = true
+		return nil
+	}
+
+	s.lastTs, s.lastSent = ts, sent
+	if s.req.Direction == logproto.FORWARD {
+		s.start = ts
+	} else {
+		// The end is exclusive, so move it past the last timestamp to fetch the remaining entries sharing it.
+		s.end = ts.Add(time.Nanosecond)
+	}
+	return nil
+}
+
+// run writes the first batch and keeps fetching and writing batches until the query is exhausted.
+// Once the response has started, errors can only be reported as a last line of the body.
+func (s *logStreamer) run(ctx context.Context, first *LokiResponse, w io.Writer, flush func()) {
+	buf := bufio.NewWriter(w)
+	err := s.stream(ctx, first, buf, flush)
+	if err != nil {
+		_ = marshal.WriteStreamErrorJSON(err, buf)
+	}
+	_ = buf.Flush()
+	flush()
+}
+
+func (s *logStreamer) stream(ctx context.Context, resp *LokiResponse, buf *bufio.Writer, flush func()) error {
+	for resp != nil {
+		if err := s.write(resp, buf); err != nil {
+			return err
+		}
+		if err := buf.Flush(); err != nil {
+			return err
+		}
+		flush()
+
+		var err error
+		if resp, err = s.nextBatch(ctx); err != nil {
+			return err
+		}
+	}
+	// Queries asking for more entries than the limit end with an error, so that clients know the
+	// results might be incomplete.
+	if !s.done && s.remaining <= 0 && int(s.req.Limit) > s.maxStreamed {
+		return fmt.Errorf(maxStreamedEntriesErrTmpl, s.maxStreamed)
+	}
+	return nil
+}
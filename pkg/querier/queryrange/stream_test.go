@@ -0,0 +1,283 @@
+package queryrange
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/grafana/dskit/user"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/pkg/loghttp"
+	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/querier/queryrange/queryrangebase"
+)
+
+type streamTestEntry struct {
+	labels string
+	entry  logproto.Entry
+}
+
+// streamTestHandler answers log queries from a fixed set of entries, honouring the range, limit and direction.
+func streamTestHandler(t *testing.T, entries []streamTestEntry, maxLimit uint32, requests *int) queryrangebase.Handler {
+	return queryrangebase.HandlerFunc(func(_ context.Context, r queryrangebase.Request) (queryrangebase.Response, error) {
+		req := r.(*LokiRequest)
+		require.LessOrEqual(t, req.Limit, maxLimit)
+		*requests++
+
+		var selected []streamTestEntry
+		for _, e := range entries {
+			if !e.entry.Timestamp.Before(req.StartTs) && e.entry.Timestamp.Before(req.EndTs) {
+				selected = append(selected, e)
+			}
+		}
+		sort.SliceStable(selected, func(i, j int) bool {
+			if req.Direction == logproto.FORWARD {
+				return selected[i].entry.Timestamp.Before(selected[j].entry.Timestamp)
+			}
+			return selected[i].entry.Timestamp.After(selected[j].entry.Timestamp)
+		})
+		if len(selected) > int(req.Limit) {
+			selected = selected[:req.Limit]
+		}
+
+		streams := map[string]*logproto.Stream{}
+		var result []logproto.Stream
+		for _, e := range selected {
+			s, ok := streams[e.labels]
+			if !ok {
+				s = &logproto.Stream{Labels: e.labels}
+				streams[e.labels] = s
+			}
+			s.Entries = append(s.Entries, e.entry)
+		}
+		for _, s := range streams {
+			result = append(result, *s)
+		}
+
+		return &LokiResponse{
+			Status:    loghttp.QueryStatusSuccess,
+			Direction: req.Direction,
+			Limit:     req.Limit,
+			Version:   uint32(loghttp.VersionV1),
+			Data: LokiData{
+				ResultType: loghttp.ResultTypeStream,
+				Result:     result,
+			},
+		}, nil
+	})
+}
+
+func streamTestEntries() []streamTestEntry {
+	var entries []streamTestEntry
+	for i := 1; i <= 10; i++ {
+		entries = append(entries, streamTestEntry{`{app="a"}`, logproto.Entry{Timestamp: time.Unix(0, int64(i)), Line: fmt.Sprintf("a%d", i)}})
+	}
+	// Entries sharing a timestamp across a batch boundary.
+	entries = append(entries,
+		streamTestEntry{`{app="b"}`, logproto.Entry{Timestamp: time.Unix(0, 3), Line: "b3"}},
+		streamTestEntry{`{app="b"}`, logproto.Entry{Timestamp: time.Unix(0, 3), Line: "b3'"}},
+		streamTestEntry{`{app="b"}`, logproto.Entry{Timestamp: time.Unix(0, 7), Line: "b7"}},
+	)
+	return entries
+}
+
+type streamedLine struct {
+	Stream map[string]string `json:"stream"`
+	Values [][]string        `json:"values"`
+	Error  string            `json:"error"`
+}
+
+func readStreamedLines(t *testing.T, r io.Reader) []streamedLine {
+	var lines []streamedLine
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		var l streamedLine
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &l))
+		lines = append(lines, l)
+	}
+	require.NoError(t, scanner.Err())
+	return lines
+}
+
+func TestSerializeHTTPHandler_Stream(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		direction string
+		limit     int
+		expected  []string
+		requests  int
+	}{
+		{
+			name:      "forward",
+			direction: "forward",
+			limit:     100,
+			expected:  []string{"a1", "a2", "a3", "b3", "b3'", "a4", "a5", "a6", "a7", "b7", "a8", "a9", "a10"},
+			requests:  5,
+		},
+		{
+			name:      "backward",
+			direction: "backward",
+			limit:     100,
+			expected:  []string{"a10", "a9", "a8", "a7", "b7", "a6", "a5", "a4", "a3", "b3", "b3'", "a2", "a1"},
+			requests:  5,
+		},
+		{
+			name:      "limit",
+			direction: "forward",
+			limit:     5,
+			expected:  []string{"a1", "a2", "a3", "b3", "b3'"},
+			requests:  2,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var requests int
+			handler := streamTestHandler(t, streamTestEntries(), 4, &requests)
+			httpHandler := NewSerializeHTTPHandler(handler, DefaultCodec, fakeLimits{maxEntriesLimitPerQuery: 4, maxStreamedEntriesPerQuery: 100})
+
+			req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/loki/api/v1/query_range?start=0&end=100&query=%%7Bapp%%3D~%%22.%%2B%%22%%7D&direction=%s&limit=%d", tc.direction, tc.limit), nil)
+			req.Header.Set("Accept", NDJSONType)
+			req = req.WithContext(user.InjectOrgID(context.Background(), "1"))
+			w := httptest.NewRecorder()
+			httpHandler.ServeHTTP(w, req)
+
+			require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+			require.Equal(t, NDJSONType, w.Header().Get("Content-Type"))
+
+			var got []string
+			for _, l := range readStreamedLines(t, w.Body) {
+				require.Empty(t, l.Error)
+				require.Len(t, l.Values, 1)
+				got = append(got, l.Values[0][1])
+			}
+			require.Equal(t, tc.expected, got)
+			require.Equal(t, tc.requests, requests)
+		})
+	}
+}
+
+func TestSerializeHTTPHandler_StreamLimits(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		limit       int
+		maxStreamed int
+		streamed    bool
+		expected    []string
+		err         string
+	}{
+		{
+			name:        "disabled",
+			limit:       100,
+			maxStreamed: 0,
+		},
+		{
+			name:        "capped",
+			limit:       100,
+			maxStreamed: 6,
+			streamed:    true,
+			expected:    []string{"a1", "a2", "a3", "b3", "b3'", "a4"},
+			err:         fmt.Sprintf(maxStreamedEntriesErrTmpl, 6),
+		},
+		{
+			name:        "exhausted before the limit",
+			limit:       100,
+			maxStreamed: 14,
+			streamed:    true,
+			expected:    []string{"a1", "a2", "a3", "b3", "b3'", "a4", "a5", "a6", "a7", "b7", "a8", "a9", "a10"},
+		},
+		{
+			name:        "below the limit",
+			limit:       6,
+			maxStreamed: 13,
+			streamed:    true,
+			expected:    []string{"a1", "a2", "a3", "b3", "b3'", "a4"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var requests int
+			handler := streamTestHandler(t, streamTestEntries(), 100, &requests)
+			httpHandler := NewSerializeHTTPHandler(handler, DefaultCodec, fakeLimits{maxEntriesLimitPerQuery: 4, maxStreamedEntriesPerQuery: tc.maxStreamed})
+
+			req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/loki/api/v1/query_range?start=0&end=100&query=%%7Bapp%%3D~%%22.%%2B%%22%%7D&direction=forward&limit=%d", tc.limit), nil)
+			req.Header.Set("Accept", NDJSONType)
+			req = req.WithContext(user.InjectOrgID(context.Background(), "1"))
+			w := httptest.NewRecorder()
+			httpHandler.ServeHTTP(w, req)
+
+			require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+			if !tc.streamed {
+				require.NotEqual(t, NDJSONType, w.Header().Get("Content-Type"))
+				require.Equal(t, 1, requests)
+				return
+			}
+			require.Equal(t, NDJSONType, w.Header().Get("Content-Type"))
+
+			var (
+				got []string
+				err string
+			)
+			for _, l := range readStreamedLines(t, w.Body) {
+				if l.Error != "" {
+					err = l.Error
+					continue
+				}
+				got = append(got, l.Values[0][1])
+			}
+			require.Equal(t, tc.expected, got)
+			require.Equal(t, tc.err, err)
+		})
+	}
+}
+
+func TestSerializeHTTPHandler_StreamMetricQuery(t *testing.T) {
+	handler := queryrangebase.HandlerFunc(func(_ context.Context, r queryrangebase.Request) (queryrangebase.Response, error) {
+		return &LokiPromResponse{Response: &queryrangebase.PrometheusResponse{
+			Status: loghttp.QueryStatusSuccess,
+			Data:   queryrangebase.PrometheusData{ResultType: loghttp.ResultTypeMatrix},
+		}}, nil
+	})
+	httpHandler := NewSerializeHTTPHandler(handler, DefaultCodec, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/loki/api/v1/query_range?start=0&end=100&query=count_over_time(%7Bapp%3D%22a%22%7D%5B1m%5D)", nil)
+	req.Header.Set("Accept", NDJSONType)
+	req = req.WithContext(user.InjectOrgID(context.Background(), "1"))
+	w := httptest.NewRecorder()
+	httpHandler.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.NotEqual(t, NDJSONType, w.Header().Get("Content-Type"))
+}
+
+func TestSerializeRoundTripper_Stream(t *testing.T) {
+	var requests int
+	next := streamTestHandler(t, streamTestEntries(), 4, &requests)
+	failing := queryrangebase.HandlerFunc(func(ctx context.Context, r queryrangebase.Request) (queryrangebase.Response, error) {
+		if requests == 2 {
+			return nil, errors.New("querier unavailable")
+		}
+		return next.Do(ctx, r)
+	})
+	rt := NewSerializeRoundTripper(failing, DefaultCodec, fakeLimits{maxEntriesLimitPerQuery: 4, maxStreamedEntriesPerQuery: 100})
+
+	req := httptest.NewRequest(http.MethodGet, "/loki/api/v1/query_range?start=0&end=100&query=%7Bapp%3D~%22.%2B%22%7D&direction=forward&limit=100", nil)
+	req.Header.Set("Accept", NDJSONType)
+	req = req.WithContext(user.InjectOrgID(context.Background(), "1"))
+	resp, err := rt.RoundTrip(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, NDJSONType, resp.Header.Get("Content-Type"))
+
+	lines := readStreamedLines(t, resp.Body)
+	require.Len(t, lines, 7)
+	require.Equal(t, "querier unavailable", lines[6].Error)
+}
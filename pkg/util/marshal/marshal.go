@@ -61,6 +61,31 @@ func WriteQueryResponseJSON(data parser.Value, statistics stats.Result, w io.Wri
 	return s.Flush()
 }
 
+// WriteStreamLineJSON marshals a logproto.Stream to v1 loghttp JSON followed by a
+// newline, as a single line of a streamed (NDJSON) query response.
+func WriteStreamLineJSON(stream logproto.Stream, w io.Writer, encodeFlags httpreq.EncodingFlags) error {
+	s := jsoniter.ConfigFastest.BorrowStream(w)
+	defer jsoniter.ConfigFastest.ReturnStream(s)
+	if err := encodeStream(stream, s, encodeFlags); err != nil {
+		return fmt.Errorf("could not write JSON stream: %w", err)
+	}
+	s.WriteRaw("\n")
+	return s.Flush()
+}
+
+// WriteStreamErrorJSON writes an error as the last line of a streamed (NDJSON)
+// query response, once it is too late to change the status code.
+func WriteStreamErrorJSON(err error, w io.Writer) error {
+	s := jsoniter.ConfigFastest.BorrowStream(w)
+	defer jsoniter.ConfigFastest.ReturnStream(s)
+	s.WriteObjectStart()
+	s.WriteObjectField("error")
+	s.WriteString(err.Error())
+	s.WriteObjectEnd()
+	s.WriteRaw("\n")
+	return s.Flush()
+}
+
 // WriteLabelResponseJSON marshals a logproto.LabelResponse to v1 loghttp JSON
 // and then writes it to the provided io.Writer.
 func WriteLabelResponseJSON(data []string, w io.Writer) error {
@@ -174,3 +199,23 @@ func WriteVolumeResponseJSON(r *logproto.VolumeResponse, w io.Writer) error {
 	s.WriteRaw("\n")
 	return s.Flush()
 }
+
+// WritePatternsResponseJSON marshals a logproto.QueryPatternsResponse to JSON and then
+// writes it to the provided io.Writer.
+func WritePatternsResponseJSON(r *logproto.QueryPatternsResponse, w io.Writer) error {
+	s := jsoniter.ConfigFastest.BorrowStream(w)
+	defer jsoniter.ConfigFastest.ReturnStream(s)
+	s.WriteVal(loghttp.NewPatternsResponse(r))
+	s.WriteRaw("\n")
+	return s.Flush()
+}
+
+// WriteDetectedFieldsResponseJSON marshals a logproto.DetectedFieldsResponse to JSON and then
+// writes it to the provided io.Writer.
+func WriteDetectedFieldsResponseJSON(r *logproto.DetectedFieldsResponse, w io.Writer) error {
+	s := jsoniter.ConfigFastest.BorrowStream(w)
+	defer jsoniter.ConfigFastest.ReturnStream(s)
+	s.WriteVal(loghttp.NewDetectedFieldsResponse(r))
+	s.WriteRaw("\n")
+	return s.Flush()
+}
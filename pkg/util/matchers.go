@@ -25,3 +25,13 @@ func SplitFiltersAndMatchers(allMatchers []*labels.Matcher) (filters, matchers [
 	}
 	return
 }
+
+// MatchesLabels returns whether the labels match all the matchers.
+func MatchesLabels(matchers []*labels.Matcher, lbs labels.Labels) bool {
+	for _, m := range matchers {
+		if !m.Matches(lbs.Get(m.Name)) {
+			return false
+		}
+	}
+	return true
+}
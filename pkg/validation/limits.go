@@ -21,7 +21,9 @@ import (
 
 	"github.com/grafana/loki/pkg/chunkenc"
 	"github.com/grafana/loki/pkg/compactor/deletionmode"
+	"github.com/grafana/loki/pkg/distributor/sampling"
 	"github.com/grafana/loki/pkg/distributor/shardstreams"
+	"github.com/grafana/loki/pkg/distributor/transform"
 	"github.com/grafana/loki/pkg/loghttp/push"
 	"github.com/grafana/loki/pkg/logql"
 	"github.com/grafana/loki/pkg/logql/syntax"
@@ -102,6 +104,7 @@ type Limits struct {
 	MaxStreamsMatchersPerQuery int              `yaml:"max_streams_matchers_per_query" json:"max_streams_matchers_per_query"`
 	MaxConcurrentTailRequests  int              `yaml:"max_concurrent_tail_requests" json:"max_concurrent_tail_requests"`
 	MaxEntriesLimitPerQuery    int              `yaml:"max_entries_limit_per_query" json:"max_entries_limit_per_query"`
+	MaxStreamedEntriesPerQuery int              `yaml:"max_streamed_entries_per_query" json:"max_streamed_entries_per_query"`
 	MaxCacheFreshness          model.Duration   `yaml:"max_cache_freshness_per_query" json:"max_cache_freshness_per_query"`
 	MaxMetadataCacheFreshness  model.Duration   `yaml:"max_metadata_cache_freshness" json:"max_metadata_cache_freshness"`
 	MaxStatsCacheFreshness     model.Duration   `yaml:"max_stats_cache_freshness" json:"max_stats_cache_freshness"`
@@ -210,6 +213,9 @@ type Limits struct {
 	MaxStructuredMetadataEntriesCount int                   `yaml:"max_structured_metadata_entries_count" json:"max_structured_metadata_entries_count" doc:"description=Maximum number of structured metadata entries per log line."`
 	OTLPConfig                        push.OTLPConfig       `yaml:"otlp_config" json:"otlp_config" doc:"description=OTLP log ingestion configurations"`
 	GlobalOTLPConfig                  push.GlobalOTLPConfig `yaml:"-" json:"-"`
+
+	StreamTransformations []transform.Rule  `yaml:"stream_transformations,omitempty" json:"stream_transformations,omitempty" doc:"description=Experimental. Transformations applied in order by the distributor to the labels of the pushed streams, before their validation.\nExample:\n stream_transformations:\n - action: structured_metadata\n labels: [trace_id]\n - selector: '{namespace=\"dev\"}'\n action: drop\n labels: [pod]\n - action: rename\n source_label: app_kubernetes_io_name\n target_label: app\n - action: hash\n labels: [user_id]\n modulus: 16\n - action: add\n target_label: cluster\n value: eu-west-1\nThe drop, hash and structured_metadata actions apply to the 'labels', the hash action replacing their values with their hash, modulo the 'modulus' if set. The rename action renames the 'source_label' to the 'target_label', and the add action sets the 'target_label' to the 'value'. The 'selector' of a rule is matched against the labels transformed by the previous rules. The structured_metadata action doesn't overwrite the structured metadata with the same name the entries already have."`
+	SamplingPolicies      []sampling.Policy `yaml:"sampling_policies,omitempty" json:"sampling_policies,omitempty" doc:"description=Experimental. Policies dropping or sampling the pushed entries in the distributor. The first policy whose selector matches an entry decides whether it's dropped.\nExample:\n sampling_policies:\n - name: debug\n selector: '{namespace=\"dev\", level=\"debug\"}'\n action: sample\n keep_ratio: 0.1\n - selector: '{app=\"healthcheck\"}'\n action: drop\nThe 'selector' is a LogQL stream selector, optionally followed by line filters. The drop action drops the matching entries, and the sample action keeps the 'keep_ratio' of them, chosen by the hash of their stream and line. Dropped entries are reported as discarded samples."`
 }
 
 type StreamRetention struct {
@@ -246,6 +252,7 @@ func (l *Limits) RegisterFlags(f *flag.FlagSet) {
 	_ = l.CreationGracePeriod.Set("10m")
 	f.Var(&l.CreationGracePeriod, "validation.create-grace-period", "Duration which table will be created/deleted before/after it's needed; we won't accept sample from before this time.")
 	f.IntVar(&l.MaxEntriesLimitPerQuery, "validation.max-entries-limit", 5000, "Maximum number of log entries that will be returned for a query.")
+	f.IntVar(&l.MaxStreamedEntriesPerQuery, "validation.max-streamed-entries-limit", 0, "Maximum number of log entries that will be returned for a query streaming its results as newline delimited JSON. 0 disables streaming: such queries get the usual response, limited by the max entries limit.")
 
 	f.IntVar(&l.MaxLocalStreamsPerUser, "ingester.max-streams-per-user", 0, "Maximum number of active streams per user, per ingester. 0 to disable.")
 	f.IntVar(&l.MaxGlobalStreamsPerUser, "ingester.max-global-streams-per-user", 5000, "Maximum number of active streams per user, across the cluster. 0 to disable. When the global limit is enabled, each ingester is configured with a dynamic local limit based on the replication factor and the current number of healthy ingesters, and is kept updated whenever the number of ingesters change.")
@@ -444,6 +451,18 @@ func (l *Limits) Validate() error {
 		return err
 	}
 
+	for i := range l.StreamTransformations {
+		if err := l.StreamTransformations[i].Validate(); err != nil {
+			return fmt.Errorf("invalid stream transformation %d: %w", i, err)
+		}
+	}
+
+	for i := range l.SamplingPolicies {
+		if err := l.SamplingPolicies[i].Validate(); err != nil {
+			return fmt.Errorf("invalid sampling policy %d: %w", i, err)
+		}
+	}
+
 	if _, err := logql.ParseShardVersion(l.TSDBShardingStrategy); err != nil {
 		return errors.Wrap(err, "invalid tsdb sharding strategy")
 	}
@@ -698,6 +717,11 @@ func (o *Overrides) MaxEntriesLimitPerQuery(_ context.Context, userID string) in
 	return o.getOverridesForUser(userID).MaxEntriesLimitPerQuery
 }
 
+// MaxStreamedEntriesPerQuery returns the limit to number of entries a streamed query should return, 0 if queries can't be streamed.
+func (o *Overrides) MaxStreamedEntriesPerQuery(_ context.Context, userID string) int {
+	return o.getOverridesForUser(userID).MaxStreamedEntriesPerQuery
+}
+
 func (o *Overrides) QueryTimeout(_ context.Context, userID string) time.Duration {
 	return time.Duration(o.getOverridesForUser(userID).QueryTimeout)
 }
@@ -970,6 +994,14 @@ func (o *Overrides) OTLPConfig(userID string) push.OTLPConfig {
 	return o.getOverridesForUser(userID).OTLPConfig
 }
 
+func (o *Overrides) StreamTransformations(userID string) []transform.Rule {
+	return o.getOverridesForUser(userID).StreamTransformations
+}
+
+func (o *Overrides) SamplingPolicies(userID string) []sampling.Policy {
+	return o.getOverridesForUser(userID).SamplingPolicies
+}
+
 func (o *Overrides) getOverridesForUser(userID string) *Limits {
 	if o.tenantLimits != nil {
 		l := o.tenantLimits.TenantLimits(userID)
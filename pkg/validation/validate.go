@@ -25,6 +25,9 @@ const (
 	// LineTooLong is a reason for discarding too long log lines.
 	LineTooLong         = "line_too_long"
 	LineTooLongErrorMsg = "Max entry size '%d' bytes exceeded for stream '%s' while adding an entry with length '%d' bytes"
+	// SamplingPolicy is a reason for discarding lines dropped by the sampling policies of the tenant.
+	SamplingPolicy         = "sampling_policy"
+	SamplingPolicyErrorMsg = "%d entries of stream '%s' were dropped by the sampling policy '%s', totaling '%d' bytes"
 	// StreamLimit is a reason for discarding lines when we can't create a new stream
 	// because the limit of active streams has been reached.
 	StreamLimit         = "stream_limit"
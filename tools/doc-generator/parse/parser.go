@@ -22,6 +22,7 @@ import (
 	prometheus_config "github.com/prometheus/prometheus/config"
 	"github.com/prometheus/prometheus/model/relabel"
 
+	"github.com/grafana/loki/pkg/distributor/sampling"
 	"github.com/grafana/loki/pkg/ruler/util"
 	storage_config "github.com/grafana/loki/pkg/storage/config"
 	util_validation "github.com/grafana/loki/pkg/util/validation"
@@ -371,6 +372,8 @@ func getFieldCustomType(t reflect.Type) (string, bool) {
 		return "remote_write_config...", true
 	case reflect.TypeOf(storage_config.PeriodConfig{}).String():
 		return "period_config", true
+	case reflect.TypeOf([]sampling.Policy{}).String():
+		return "list of Policies", true
 	case reflect.TypeOf(validation.OverwriteMarshalingStringMap{}).String():
 		return "headers", true
 	default: